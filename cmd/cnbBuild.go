@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"archive/zip"
+	"context"
 	"fmt"
 	"os"
 	"path"
 	"path/filepath"
 	"slices"
+	"time"
 
 	"dario.cat/mergo"
 	"github.com/SAP/jenkins-library/pkg/buildpacks"
@@ -119,10 +121,33 @@ func cnbBuild(config cnbBuildOptions, telemetryData *telemetry.CustomData, commo
 
 	client := &piperhttp.Client{}
 
-	err := callCnbBuild(&config, telemetryData, utils, commonPipelineEnvironment, client)
+	startedOn := time.Now()
+	images, err := callCnbBuild(&config, telemetryData, utils, commonPipelineEnvironment, client)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
+
+	if config.SignImages {
+		// the docker config has been prepared by callCnbBuild
+		if err := signContainerImages(images, config.SigningKey, config.SigningKeyPassword, config.DockerConfigJSON, utils); err != nil {
+			log.Entry().WithError(err).Fatal("Signing of images failed")
+		}
+	}
+
+	if config.CreateProvenance {
+		if err := createCnbProvenance(&config, images, startedOn, utils); err != nil {
+			log.Entry().WithError(err).Fatal("Creation of provenance failed")
+		}
+	}
+}
+
+// createCnbProvenance creates the provenance of the pushed images, it is attached to the images if they are signed
+func createCnbProvenance(config *cnbBuildOptions, images []string, startedOn time.Time, utils piperutils.FileUtils) error {
+	statement, err := writeImageProvenance(cnbBuildMetadata(), config, images, startedOn, utils)
+	if err != nil || statement == nil || !config.SignImages {
+		return err
+	}
+	return attachProvenance(images, statement, config.SigningKey, config.SigningKeyPassword, config.DockerConfigJSON, utils)
 }
 
 func isBuilder(utils cnbutils.BuildUtils) error {
@@ -277,13 +302,14 @@ func (config *cnbBuildOptions) resolvePath(utils cnbutils.BuildUtils) (buildpack
 	}
 }
 
-func callCnbBuild(config *cnbBuildOptions, telemetryData *telemetry.CustomData, utils cnbutils.BuildUtils, commonPipelineEnvironment *cnbBuildCommonPipelineEnvironment, httpClient piperhttp.Sender) error {
+// callCnbBuild builds and pushes the images, the pushed images are returned referenced by digest
+func callCnbBuild(config *cnbBuildOptions, telemetryData *telemetry.CustomData, utils cnbutils.BuildUtils, commonPipelineEnvironment *cnbBuildCommonPipelineEnvironment, httpClient piperhttp.Sender) ([]string, error) {
 	stepName := "cnbBuild"
 
 	err := isBuilder(utils)
 	if err != nil {
 		log.SetErrorCategory(log.ErrorConfiguration)
-		return fmt.Errorf("the provided dockerImage is not a valid builder: %w", err)
+		return nil, fmt.Errorf("the provided dockerImage is not a valid builder: %w", err)
 	}
 
 	telemetry := buildpacks.NewTelemetry(telemetryData)
@@ -311,7 +337,7 @@ func callCnbBuild(config *cnbBuildOptions, telemetryData *telemetry.CustomData,
 	err = ensureDockerConfig(config, utils)
 	if err != nil {
 		log.SetErrorCategory(log.ErrorConfiguration)
-		return fmt.Errorf("failed to create/rename DockerConfigJSON file: %w", err)
+		return nil, fmt.Errorf("failed to create/rename DockerConfigJSON file: %w", err)
 	}
 
 	if config.DockerConfigJSONCPE != "" {
@@ -319,23 +345,25 @@ func callCnbBuild(config *cnbBuildOptions, telemetryData *telemetry.CustomData,
 		err = docker.MergeDockerConfigJSON(config.DockerConfigJSONCPE, config.DockerConfigJSON, utils)
 		if err != nil {
 			log.SetErrorCategory(log.ErrorConfiguration)
-			return fmt.Errorf("failed to merge DockerConfigJSON files: %w", err)
+			return nil, fmt.Errorf("failed to merge DockerConfigJSON files: %w", err)
 		}
 	}
 
 	mergedConfigs, err := processConfigs(*config, config.MultipleImages)
 	if err != nil {
-		return fmt.Errorf("failed to process config: %w", err)
+		return nil, fmt.Errorf("failed to process config: %w", err)
 	}
 
 	buildSummary := cnbutils.NewBuildSummary(dockerImage, utils)
+	images := []string{}
 	for _, c := range mergedConfigs {
 		imageSummary := &cnbutils.ImageSummary{}
 		err = runCnbBuild(&c, telemetry, imageSummary, utils, commonPipelineEnvironment, httpClient)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		buildSummary.Images = append(buildSummary.Images, imageSummary)
+		images = append(images, imageSummary.ImageRef)
 	}
 
 	buildSummary.Print()
@@ -345,7 +373,7 @@ func callCnbBuild(config *cnbBuildOptions, telemetryData *telemetry.CustomData,
 		syftScanner, err := syft.CreateSyftScanner(config.SyftDownloadURL, utils, httpClient)
 		if err != nil {
 			log.SetErrorCategory(log.ErrorCompliance)
-			return fmt.Errorf("failed to create syft scanner file: %w", err)
+			return nil, fmt.Errorf("failed to create syft scanner file: %w", err)
 		}
 		// images produces with cnb have sboms
 		syftScanner.AddArgument("--override-default-catalogers=sbom-cataloger,go-module-binary-cataloger,apk-db-cataloger,dpkg-db-cataloger,rpm-db-cataloger")
@@ -353,11 +381,11 @@ func callCnbBuild(config *cnbBuildOptions, telemetryData *telemetry.CustomData,
 		err = syftScanner.ScanImages(filepath.Dir(config.DockerConfigJSON), utils, commonPipelineEnvironment.container.registryURL, commonPipelineEnvironment.container.imageNameTags)
 		if err != nil {
 			log.SetErrorCategory(log.ErrorCompliance)
-			return fmt.Errorf("failed to create BOM file: %w", err)
+			return nil, fmt.Errorf("failed to create BOM file: %w", err)
 		}
 	}
 
-	return nil
+	return images, nil
 }
 
 func runCnbBuild(config *cnbBuildOptions, telemetry *buildpacks.Telemetry, imageSummary *cnbutils.ImageSummary, utils cnbutils.BuildUtils, commonPipelineEnvironment *cnbBuildCommonPipelineEnvironment, httpClient piperhttp.Sender) error {
@@ -566,7 +594,23 @@ func runCnbBuild(config *cnbBuildOptions, telemetry *buildpacks.Telemetry, image
 		"-buildpacks", buildpacksPath,
 		"-order", orderPath,
 		"-platform", platformPath,
-		"-skip-restore",
+	}
+
+	var cacheKey string
+	if config.CacheImage != "" || config.CacheDirectory != "" {
+		cacheKey, err = buildCacheKey(targetImage.ContainerImageName, projDescPath, orderPath, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorBuild)
+			return fmt.Errorf("failed to determine the cache key: %w", err)
+		}
+		cacheArgs, err := prepareBuildCache(config, cacheKey, uid, gid, dockerKeychain, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("failed to prepare the build cache: %w", err)
+		}
+		creatorArgs = append(creatorArgs, cacheArgs...)
+	} else {
+		creatorArgs = append(creatorArgs, "-skip-restore")
 	}
 
 	if GeneralConfig.Verbose {
@@ -614,6 +658,10 @@ func runCnbBuild(config *cnbBuildOptions, telemetry *buildpacks.Telemetry, image
 	commonPipelineEnvironment.container.imageDigests = append(commonPipelineEnvironment.container.imageDigests, digest)
 	imageSummary.ImageRef = fmt.Sprintf("%s@%s", containerImage, digest)
 
+	if cacheKey != "" && config.PruneCache {
+		pruneBuildCache(config, cacheKey, utils)
+	}
+
 	if len(config.PreserveFiles) > 0 {
 		if pathType != buildpacks.PathEnumArchive {
 			err = cnbutils.CopyProject(target, source, ignore.CompileIgnoreLines(config.PreserveFiles...), nil, utils, true)
@@ -629,6 +677,72 @@ func runCnbBuild(config *cnbBuildOptions, telemetry *buildpacks.Telemetry, image
 	return nil
 }
 
+// buildCacheKey derives the cache key from the project descriptor and the buildpack order used for the build
+func buildCacheKey(imageName, projDescPath, orderPath string, utils cnbutils.BuildUtils) (string, error) {
+	var descriptor []byte
+	if projDescPath != "" {
+		var err error
+		descriptor, err = utils.FileRead(projDescPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", projDescPath, err)
+		}
+	}
+	order, err := cnbutils.LoadOrder(orderPath, utils)
+	if err != nil {
+		return "", fmt.Errorf("failed to read buildpack order %s: %w", orderPath, err)
+	}
+	return cnbutils.CacheKey(imageName, descriptor, order)
+}
+
+// prepareBuildCache returns the lifecycle arguments for the cache image and the cache directory of the image
+func prepareBuildCache(config *cnbBuildOptions, cacheKey string, uid, gid int, dockerKeychain *cnbutils.DockerKeychain, utils cnbutils.BuildUtils) ([]string, error) {
+	if config.CacheImage != "" && config.CacheDirectory != "" {
+		return nil, fmt.Errorf("cacheImage and cacheDirectory must not be configured both, the lifecycle uses only one of them")
+	}
+	args := []string{}
+	if config.CacheImage != "" {
+		cacheImage, err := cnbutils.CacheImageReference(config.CacheImage, cacheKey)
+		if err != nil {
+			return nil, err
+		}
+		if !dockerKeychain.AuthExistsForImage(cacheImage) {
+			log.Entry().Warnf("provided dockerConfigJSON does not contain credentials for the cache image %q, anonymous auth will be used", cacheImage)
+		}
+		log.Entry().Infof("Using cache image '%s'", cacheImage)
+		args = append(args, "-cache-image", cacheImage)
+	}
+	if config.CacheDirectory != "" {
+		cacheDir := filepath.Join(config.CacheDirectory, cacheKey)
+		if err := utils.MkdirAll(cacheDir, os.ModePerm); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory %s: %w", cacheDir, err)
+		}
+		if err := utils.Chown(cacheDir, uid, gid); err != nil {
+			return nil, fmt.Errorf("failed to change ownership of cache directory %s: %w", cacheDir, err)
+		}
+		log.Entry().Infof("Using cache directory '%s'", cacheDir)
+		args = append(args, "-cache-dir", cacheDir)
+	}
+	return args, nil
+}
+
+// pruneBuildCache deletes the stale caches of the image, failures do not fail the build
+func pruneBuildCache(config *cnbBuildOptions, cacheKey string, utils cnbutils.BuildUtils) {
+	if config.CacheImage != "" {
+		opts, err := docker.RegistryOptions(context.Background(), config.DockerConfigJSON, utils)
+		if err == nil {
+			_, err = cnbutils.PruneCacheImages(config.CacheImage, cacheKey, opts...)
+		}
+		if err != nil {
+			log.Entry().WithError(err).Warnf("failed to prune the cache images of '%s'", config.CacheImage)
+		}
+	}
+	if config.CacheDirectory != "" {
+		if _, err := cnbutils.PruneCacheDirectories(config.CacheDirectory, cacheKey, utils); err != nil {
+			log.Entry().WithError(err).Warnf("failed to prune the cache directories in '%s'", config.CacheDirectory)
+		}
+	}
+}
+
 func expandEnvVars(envVars map[string]any) map[string]any {
 	expandedEnvVars := map[string]any{}
 	for key, value := range envVars {
@@ -43,9 +43,16 @@ type cnbBuildOptions struct {
 	PreserveFiles             []string                 `json:"preserveFiles,omitempty"`
 	BuildSettingsInfo         string                   `json:"buildSettingsInfo,omitempty"`
 	CreateBOM                 bool                     `json:"createBOM,omitempty"`
+	CreateProvenance          bool                     `json:"createProvenance,omitempty"`
 	SyftDownloadURL           string                   `json:"syftDownloadUrl,omitempty"`
 	RunImage                  string                   `json:"runImage,omitempty"`
 	DefaultProcess            string                   `json:"defaultProcess,omitempty"`
+	CacheImage                string                   `json:"cacheImage,omitempty"`
+	CacheDirectory            string                   `json:"cacheDirectory,omitempty"`
+	PruneCache                bool                     `json:"pruneCache,omitempty"`
+	SignImages                bool                     `json:"signImages,omitempty"`
+	SigningKey                string                   `json:"signingKey,omitempty"`
+	SigningKeyPassword        string                   `json:"signingKeyPassword,omitempty"`
 }
 
 type cnbBuildCommonPipelineEnvironment struct {
@@ -173,6 +180,8 @@ func CnbBuildCommand() *cobra.Command {
 			log.SetStepErrors(stepErrors)
 			log.RegisterSecret(stepConfig.DockerConfigJSON)
 			log.RegisterSecret(stepConfig.DockerConfigJSONCPE)
+			log.RegisterSecret(stepConfig.SigningKey)
+			log.RegisterSecret(stepConfig.SigningKeyPassword)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -283,9 +292,16 @@ func addCnbBuildFlags(cmd *cobra.Command, stepConfig *cnbBuildOptions) {
 	cmd.Flags().StringSliceVar(&stepConfig.PreserveFiles, "preserveFiles", []string{}, "List of globs, for keeping build results in the Jenkins workspace.\n\n*Note*: globs will be calculated relative to the [path](#path) property.\n")
 	cmd.Flags().StringVar(&stepConfig.BuildSettingsInfo, "buildSettingsInfo", os.Getenv("PIPER_buildSettingsInfo"), "Build settings info is typically filled by the step automatically to create information about the build settings that were used during the mta build. This information is typically used for compliance related processes.")
 	cmd.Flags().BoolVar(&stepConfig.CreateBOM, "createBOM", false, "Creates the bill of materials (BOM) using Syft and stores it in a file in CycloneDX 1.4 format.")
+	cmd.Flags().BoolVar(&stepConfig.CreateProvenance, "createProvenance", false, "Creates an [in-toto](https://github.com/in-toto/attestation) statement with the [SLSA provenance](https://slsa.dev/spec/v1.0/provenance) of the build artifacts in `provenance/cnbBuild.intoto.json`. It contains the digests of the artifacts, the invoking configuration, the source, the build run of the orchestrator and the dependencies listed in the BOM (see `createBOM`). The pushed images are the build artifacts. If `signImages` is active, the provenance is attached to the images as attestation signed with the `signingKey`.")
 	cmd.Flags().StringVar(&stepConfig.SyftDownloadURL, "syftDownloadUrl", `https://github.com/anchore/syft/releases/download/v1.38.0/syft_1.38.0_linux_amd64.tar.gz`, "Specifies the download url of the Syft Linux amd64 tar binary file. This can be found at https://github.com/anchore/syft/releases/.")
 	cmd.Flags().StringVar(&stepConfig.RunImage, "runImage", os.Getenv("PIPER_runImage"), "Base image from which application images are built. Will be defaulted to the image provided by the builder. See also https://buildpacks.io/docs/for-app-developers/concepts/base-images/.")
 	cmd.Flags().StringVar(&stepConfig.DefaultProcess, "defaultProcess", os.Getenv("PIPER_defaultProcess"), "Process that should be started by default. See https://buildpacks.io/docs/app-developer-guide/run-an-app/")
+	cmd.Flags().StringVar(&stepConfig.CacheImage, "cacheImage", os.Getenv("PIPER_cacheImage"), "Repository of the image the lifecycle stores the build cache in, e.g. `my.registry.com/my-app-cache`.\nThe cache image is tagged with a cache key derived from the image name, the project descriptor and the buildpack order, hence the cache is reused as long as the build configuration does not change.\nCan be configured for each entry of `multipleImages`, the credentials for the cache image have to be part of the `dockerConfigJSON`.\n")
+	cmd.Flags().StringVar(&stepConfig.CacheDirectory, "cacheDirectory", os.Getenv("PIPER_cacheDirectory"), "Directory the lifecycle stores the build cache in, e.g. a volume mounted into the build container which is kept across pipeline runs.\nThe cache of each image is stored in a sub directory named after the cache key, see `cacheImage`.\nCan be configured for each entry of `multipleImages`, but not in combination with `cacheImage`.\n")
+	cmd.Flags().BoolVar(&stepConfig.PruneCache, "pruneCache", false, "Deletes the stale caches of an image after a successful build, i.e. the cache images and cache directories created with another cache key.")
+	cmd.Flags().BoolVar(&stepConfig.SignImages, "signImages", false, "Signs the pushed images with the `signingKey`. The signatures are stored next to the images in the format of [cosign](https://github.com/sigstore/cosign) and can be verified with the step `imageVerifySignature`.")
+	cmd.Flags().StringVar(&stepConfig.SigningKey, "signingKey", os.Getenv("PIPER_signingKey"), "Path to the PEM encoded private key used for signing images, e.g. a key pair created with `cosign generate-key-pair`.")
+	cmd.Flags().StringVar(&stepConfig.SigningKeyPassword, "signingKeyPassword", os.Getenv("PIPER_signingKeyPassword"), "Password of the encrypted `signingKey`.")
 
 	cmd.MarkFlagRequired("containerImageTag")
 	cmd.MarkFlagRequired("containerRegistryUrl")
@@ -303,6 +319,8 @@ func cnbBuildMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
 					{Name: "dockerConfigJsonCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing Docker config.json (with registry credential(s)) in the following format:\n\n```json\n{\n  \"auths\": {\n    \"$server\": {\n      \"auth\": \"base64($username + ':' + $password)\"\n    }\n  }\n}\n```\n\nExample:\n\n```json\n{\n  \"auths\": {\n    \"example.com\": {\n      \"auth\": \"dXNlcm5hbWU6cGFzc3dvcmQ=\"\n    }\n  }\n}\n```\n", Type: "jenkins"},
+					{Name: "signingKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the private key used for signing images.", Type: "jenkins"},
+					{Name: "signingKeyPasswordCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the password of the private key used for signing images.", Type: "jenkins"},
 				},
 				Parameters: []config.StepParameters{
 					{
@@ -533,6 +551,15 @@ func cnbBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "createProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 					{
 						Name:        "syftDownloadUrl",
 						ResourceRef: []config.ResourceReference{},
@@ -560,6 +587,82 @@ func cnbBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_defaultProcess"),
 					},
+					{
+						Name:        "cacheImage",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_cacheImage"),
+					},
+					{
+						Name:        "cacheDirectory",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_cacheDirectory"),
+					},
+					{
+						Name:        "pruneCache",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "signImages",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "signingKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "signingKeyFileVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "image-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_signingKey"),
+					},
+					{
+						Name: "signingKeyPassword",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyPasswordCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "signingKeyPasswordVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "image-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_signingKeyPassword"),
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/SAP/jenkins-library/pkg/buildcache"
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// restoreDependencyCache restores the dependency cache of a build step if a cache location is configured.
+// The key is only calculated if the cache is enabled. The returned cache is nil if the cache is not used.
+func restoreDependencyCache(location, credentials string, save bool, key func() (string, error), dirs []string) *buildcache.Cache {
+	if location == "" {
+		return nil
+	}
+	cacheKey, err := key()
+	if err != nil {
+		log.Entry().WithError(err).Warn("Dependency cache is not used")
+		return nil
+	}
+	return buildcache.Restore(context.Background(), buildcache.Config{Location: location, Credentials: credentials, Save: save}, cacheKey, dirs)
+}
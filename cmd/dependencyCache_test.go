@@ -0,0 +1,53 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreDependencyCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		cache := restoreDependencyCache("", "", true, func() (string, error) {
+			t.Error("the key must not be calculated if the cache is disabled")
+			return "", nil
+		}, []string{t.TempDir()})
+		assert.Nil(t, cache)
+	})
+
+	t.Run("key calculation fails", func(t *testing.T) {
+		t.Parallel()
+		cache := restoreDependencyCache(t.TempDir(), "", true, func() (string, error) {
+			return "", errors.New("failed to read pom.xml")
+		}, []string{t.TempDir()})
+		assert.Nil(t, cache)
+	})
+
+	t.Run("save and restore", func(t *testing.T) {
+		t.Parallel()
+		location := t.TempDir()
+		key := func() (string, error) { return "go-0123456789abcdef", nil }
+		modCache := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(modCache, "module.zip"), []byte("zip"), 0644))
+
+		cache := restoreDependencyCache(location, "", true, key, []string{modCache})
+		require.NotNil(t, cache)
+		cache.Save(context.Background())
+		assert.FileExists(t, filepath.Join(location, "go-0123456789abcdef.tar.gz"))
+
+		restoredModCache := t.TempDir()
+		restoreDependencyCache(location, "", true, key, []string{restoredModCache})
+		assert.FileExists(t, filepath.Join(restoredModCache, "module.zip"))
+	})
+}
@@ -218,7 +218,7 @@ func runDetect(ctx context.Context, config detectExecuteScanOptions, utils detec
 	if config.BuildMaven {
 		log.Entry().Infof("#### BuildMaven - start")
 		mavenConfig := setMavenConfig(config)
-		mavenUtils := maven.NewUtilsBundle()
+		mavenUtils := newMavenBuildUtils()
 
 		err := runMavenBuild(&mavenConfig, nil, mavenUtils, &mavenBuildCommonPipelineEnvironment{})
 		if err != nil {
@@ -2,20 +2,28 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/build"
+	"github.com/SAP/jenkins-library/pkg/buildcache"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
 	"github.com/SAP/jenkins-library/pkg/certutils"
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/goget"
+	"github.com/SAP/jenkins-library/pkg/golang"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
@@ -26,6 +34,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/versioning"
 
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 )
 
 const (
@@ -39,6 +48,7 @@ const (
 	GolangCycloneDXPackage       = "github.com/CycloneDX/cyclonedx-gomod/cmd/cyclonedx-gomod@v1.10.0"
 	sbomFilename                 = "bom-golang.xml"
 	GolangCycloneDXSchemaVersion = "1.4"
+	golangReleaseDirectory       = "dist"
 )
 
 type golangBuildUtils interface {
@@ -127,6 +137,7 @@ func golangBuild(config golangBuildOptions, telemetryData *telemetry.CustomData,
 }
 
 func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomData, utils golangBuildUtils, commonPipelineEnvironment *golangBuildCommonPipelineEnvironment) error {
+	startedOn := time.Now()
 	goModFile, err := readGoModFile(utils) // returns nil if go.mod doesnt exist
 	if err != nil {
 		return err
@@ -136,6 +147,9 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 		return err
 	}
 
+	dependencyCache := restoreDependencyCache(config.DependencyCacheLocation, config.DependencyCacheCredentials, config.DependencyCacheSave,
+		func() (string, error) { return buildcache.FilesKey("go", buildcache.GoLockFiles, utils) }, buildcache.GoDirs())
+
 	// install test pre-requisites only in case testing should be performed
 	if config.RunTests || config.RunIntegrationTests {
 		if err := utils.RunExecutable("go", "install", golangTestsumPackage); err != nil {
@@ -203,6 +217,12 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 		}
 	}
 
+	if config.RunVulnerabilityCheck {
+		if err := runGolangVulnerabilityCheck(config, goModFile, utils); err != nil {
+			return err
+		}
+	}
+
 	if config.CreateBOM {
 		if err := runBOMCreation(utils, sbomFilename); err != nil {
 			return err
@@ -219,6 +239,10 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 		ldflags = (*ldf).String()
 		log.Entry().Infof("ldflags from template: '%v'", ldflags)
 	}
+	if config.StripBinaries {
+		// omit the symbol table and the debug information
+		ldflags = strings.TrimSpace("-s -w " + ldflags)
+	}
 
 	var binaries []string
 	platforms, err := multiarch.ParsePlatformStrings(config.TargetArchitectures)
@@ -228,6 +252,15 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 
 	multipleArchitectures := len(platforms) > 1
 
+	var releaseArchiveOptions golang.ReleaseArchiveOptions
+	if config.CreateReleaseArchives {
+		releaseArchiveOptions, err = prepareGolangReleaseArchives(config, goModFile, utils, GeneralConfig.EnvRootPath)
+		if err != nil {
+			return err
+		}
+	}
+	var releaseAssets []string
+
 	for _, platform := range platforms {
 		binaryNames, err := runGolangBuildPerArchitecture(config, goModFile, utils, ldflags, platform, multipleArchitectures)
 		if err != nil {
@@ -237,8 +270,27 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 		if len(binaryNames) > 0 {
 			binaries = append(binaries, binaryNames...)
 		}
+
+		// binaries without platform specific names are overwritten by the build for the next platform
+		if config.CreateReleaseArchives {
+			archive, err := golang.CreateReleaseArchive(platform, binaryNames, releaseArchiveOptions, utils)
+			if err != nil {
+				return err
+			}
+			releaseAssets = append(releaseAssets, archive)
+		}
 	}
 
+	if config.CreateReleaseArchives {
+		checksumsFile := filepath.Join(golangReleaseDirectory, golang.ChecksumsFile)
+		if err := golang.WriteChecksums(releaseAssets, checksumsFile, utils); err != nil {
+			return err
+		}
+		releaseAssets = append(releaseAssets, checksumsFile)
+		commonPipelineEnvironment.custom.releaseAssets = releaseAssets
+	}
+	dependencyCache.Save(context.Background())
+
 	log.Entry().Debugf("creating build settings information...")
 	stepName := "golangBuild"
 	dockerImage, err := utils.getDockerImageValue(stepName)
@@ -258,27 +310,24 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 	}
 	commonPipelineEnvironment.custom.buildSettingsInfo = buildSettingsInfo
 
+	if config.CreateProvenance {
+		subjects, err := build.FileSubjects(append(slices.Clone(binaries), releaseAssets...), utils)
+		if err != nil {
+			return err
+		}
+		if _, err := writeProvenance(golangBuildMetadata(), config, subjects, []string{sbomFilename}, startedOn, utils); err != nil {
+			return fmt.Errorf("failed to create provenance: %w", err)
+		}
+	}
+
 	if config.Publish {
 		if len(config.TargetRepositoryURL) == 0 {
 			return fmt.Errorf("there's no target repository for binary publishing configured")
 		}
 
-		artifactVersion := config.ArtifactVersion
-
-		if len(artifactVersion) == 0 {
-			artifactOpts := versioning.Options{
-				VersioningScheme: "library",
-			}
-
-			artifact, err := versioning.GetArtifact("golang", "", &artifactOpts, utils)
-			if err != nil {
-				return err
-			}
-
-			artifactVersion, err = artifact.GetVersion()
-			if err != nil {
-				return err
-			}
+		artifactVersion, err := golangArtifactVersion(config, utils)
+		if err != nil {
+			return err
 		}
 
 		if goModFile == nil {
@@ -298,7 +347,13 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 		var binaryArtifacts piperenv.Artifacts
 		buildCoordinates := []versioning.Coordinates{}
 
-		for _, binary := range binaries {
+		// the release archives are published instead of the bare binaries
+		artifacts := binaries
+		if len(releaseAssets) > 0 {
+			artifacts = releaseAssets
+		}
+
+		for _, binary := range artifacts {
 
 			targetPath := fmt.Sprintf("go/%s/%s/%s", goModFile.Module.Mod.Path, artifactVersion, binary)
 
@@ -350,6 +405,75 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 	return nil
 }
 
+// golangArtifactVersion returns the configured artifact version or the version of the go module
+func golangArtifactVersion(config *golangBuildOptions, utils golangBuildUtils) (string, error) {
+	if len(config.ArtifactVersion) > 0 {
+		return config.ArtifactVersion, nil
+	}
+	artifactOpts := versioning.Options{
+		VersioningScheme: "library",
+	}
+	artifact, err := versioning.GetArtifact("golang", "", &artifactOpts, utils)
+	if err != nil {
+		return "", err
+	}
+	return artifact.GetVersion()
+}
+
+// prepareGolangReleaseArchives determines the name, the version and the modification time of the entries of the release archives
+func prepareGolangReleaseArchives(config *golangBuildOptions, goModFile *modfile.File, utils golangBuildUtils, envRootPath string) (golang.ReleaseArchiveOptions, error) {
+	cpe := piperenv.CPEMap{}
+	if err := cpe.LoadFromDisk(path.Join(envRootPath, "commonPipelineEnvironment")); err != nil {
+		log.Entry().Warning("failed to load values from commonPipelineEnvironment")
+	}
+
+	name := path.Base(filepath.ToSlash(strings.TrimRight(config.Output, string(os.PathSeparator))))
+	if goModFile != nil && goModFile.Module != nil {
+		// the major version suffix of modules like example.com/tool/v2 is not part of the name
+		modulePath := goModFile.Module.Mod.Path
+		if prefix, _, ok := module.SplitPathVersion(modulePath); ok {
+			modulePath = prefix
+		}
+		name = path.Base(modulePath)
+	}
+	version, err := golangArtifactVersion(config, utils)
+	if err != nil {
+		return golang.ReleaseArchiveOptions{}, fmt.Errorf("failed to determine the version of the release archives: %w", err)
+	}
+
+	return golang.ReleaseArchiveOptions{
+		NameTemplate: config.ReleaseArchiveNameTemplate,
+		Name:         name,
+		Version:      version,
+		CPE:          cpe,
+		Files:        config.ReleaseArchiveFiles,
+		Directory:    golangReleaseDirectory,
+		ModTime:      golangReleaseTimestamp(utils),
+	}, nil
+}
+
+// golangReleaseTimestamp returns the time of SOURCE_DATE_EPOCH or of the last commit, so that rebuilding a commit results in identical archives
+func golangReleaseTimestamp(utils golangBuildUtils) time.Time {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if len(epoch) == 0 {
+		var stdout bytes.Buffer
+		utils.Stdout(&stdout)
+		err := utils.RunExecutable("git", "log", "-1", "--format=%ct")
+		utils.Stdout(log.Writer())
+		if err != nil {
+			log.Entry().Warnf("failed to read the time of the last commit: %v", err)
+		}
+		epoch = strings.TrimSpace(stdout.String())
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		// the earliest time which can be represented in zip archives
+		log.Entry().Warn("the time of the last commit is unknown, using 1980-01-01 as modification time of the release archive entries")
+		return time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Unix(seconds, 0).UTC()
+}
+
 func createGoBuildArtifactsMetadata(binary string, repositoryURL string, artifactVersion string, utils golangBuildUtils) (error, versioning.Coordinates) {
 	options := versioning.Options{}
 	builtArtifact, err := versioning.GetArtifact("golang", "", &options, utils)
@@ -619,6 +743,113 @@ func runGolangBuildPerArchitecture(config *golangBuildOptions, goModFile *modfil
 	return binaryNames, nil
 }
 
+// runGolangVulnerabilityCheck analyzes the packages with govulncheck and fails if vulnerabilities without assessment are found
+func runGolangVulnerabilityCheck(config *golangBuildOptions, goModFile *modfile.File, utils golangBuildUtils) error {
+	if err := utils.RunExecutable("go", "install", config.GovulncheckPackage); err != nil {
+		return fmt.Errorf("failed to install govulncheck: %w", err)
+	}
+
+	args := []string{"-json"}
+	if len(config.VulnerabilityDatabase) > 0 {
+		database := config.VulnerabilityDatabase
+		if !strings.Contains(database, "://") {
+			// a local mirror of the database has to be referenced by a file URL
+			absolutePath, err := utils.Abs(database)
+			if err != nil {
+				return fmt.Errorf("failed to determine the path of the vulnerability database: %w", err)
+			}
+			database = "file://" + filepath.ToSlash(absolutePath)
+		}
+		args = append(args, "-db", database)
+	}
+	if tags := golangBuildTags(config.BuildFlags); len(tags) > 0 {
+		args = append(args, "-tags", tags)
+	}
+	packages := config.Packages
+	if len(packages) == 0 {
+		packages = []string{"./..."}
+	}
+	args = append(args, packages...)
+
+	var stdout bytes.Buffer
+	utils.Stdout(&stdout)
+	err := utils.RunExecutable("govulncheck", args...)
+	utils.Stdout(log.Writer())
+	if err != nil {
+		return fmt.Errorf("failed to run govulncheck: %w", err)
+	}
+
+	findings, err := golang.ParseVulncheckOutput(stdout.Bytes())
+	if err != nil {
+		return err
+	}
+	assessments, err := readGolangAssessments(config.AssessmentFile, utils)
+	if err != nil {
+		return err
+	}
+	golang.ApplyAssessments(findings, assessments)
+
+	projectName := ""
+	if goModFile != nil && goModFile.Module != nil {
+		projectName = goModFile.Module.Mod.Path
+	}
+	reports, err := golang.WriteVulncheckReports(findings, projectName, config.ArtifactVersion, utils)
+	if err != nil {
+		return err
+	}
+	if err := piperutils.PersistReportsAndLinks("golangBuild", "", utils, reports, nil); err != nil {
+		log.Entry().Warnf("failed to persist reports: %v", err)
+	}
+
+	for _, reachability := range []string{golang.ReachabilityCalled, golang.ReachabilityImported, golang.ReachabilityRequired} {
+		log.Entry().Infof("%v vulnerabilit(ies) without assessment %v", golang.CountVulnerabilities(findings, reachability), reachability)
+	}
+	if config.FailOnVulnerabilities != "none" {
+		if count := golang.CountVulnerabilities(findings, config.FailOnVulnerabilities); count > 0 {
+			log.SetErrorCategory(log.ErrorCompliance)
+			return fmt.Errorf("%v vulnerabilit(ies) without assessment which are at least %v found, see %v", count, config.FailOnVulnerabilities, golang.VulncheckReportsDirectory)
+		}
+	}
+	return nil
+}
+
+// golangBuildTags returns the build tags of the build flags
+func golangBuildTags(buildFlags []string) string {
+	for i, flag := range buildFlags {
+		if tags, found := strings.CutPrefix(flag, "-tags="); found {
+			return tags
+		}
+		if flag == "-tags" && i+1 < len(buildFlags) {
+			return buildFlags[i+1]
+		}
+	}
+	return ""
+}
+
+// readGolangAssessments reads the assessments of vulnerabilities, no assessments are returned if the file doesn't exist
+func readGolangAssessments(assessmentFile string, utils golangBuildUtils) ([]format.Assessment, error) {
+	if len(assessmentFile) == 0 {
+		return nil, nil
+	}
+	exists, err := utils.FileExists(assessmentFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existence of assessment file '%v': %w", assessmentFile, err)
+	}
+	if !exists {
+		return nil, nil
+	}
+	content, err := utils.FileRead(assessmentFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read assessment file '%v': %w", assessmentFile, err)
+	}
+	assessments, err := format.ReadAssessments(io.NopCloser(bytes.NewReader(content)))
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("failed to parse assessment file '%v': %w", assessmentFile, err)
+	}
+	return *assessments, nil
+}
+
 func runBOMCreation(utils golangBuildUtils, outputFilename string) error {
 	if err := utils.RunExecutable("cyclonedx-gomod", "mod", "-licenses", fmt.Sprintf("-verbose=%t", GeneralConfig.Verbose), "-test", "-output", outputFilename, "-output-version", GolangCycloneDXSchemaVersion); err != nil {
 		return fmt.Errorf("BOM creation failed: %w", err)
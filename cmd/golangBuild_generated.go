@@ -28,6 +28,7 @@ type golangBuildOptions struct {
 	CgoEnabled                   bool     `json:"cgoEnabled,omitempty"`
 	CoverageFormat               string   `json:"coverageFormat,omitempty" validate:"possible-values=cobertura html"`
 	CreateBOM                    bool     `json:"createBOM,omitempty"`
+	CreateProvenance             bool     `json:"createProvenance,omitempty"`
 	CustomTLSCertificateLinks    []string `json:"customTlsCertificateLinks,omitempty"`
 	GoProxy                      string   `json:"goProxy,omitempty"`
 	ExcludeGeneratedFromCoverage bool     `json:"excludeGeneratedFromCoverage,omitempty"`
@@ -44,6 +45,15 @@ type golangBuildOptions struct {
 	RunTests                     bool     `json:"runTests,omitempty"`
 	RunIntegrationTests          bool     `json:"runIntegrationTests,omitempty"`
 	TargetArchitectures          []string `json:"targetArchitectures,omitempty"`
+	RunVulnerabilityCheck        bool     `json:"runVulnerabilityCheck,omitempty"`
+	GovulncheckPackage           string   `json:"govulncheckPackage,omitempty"`
+	VulnerabilityDatabase        string   `json:"vulnerabilityDatabase,omitempty"`
+	FailOnVulnerabilities        string   `json:"failOnVulnerabilities,omitempty" validate:"possible-values=called imported required none"`
+	AssessmentFile               string   `json:"assessmentFile,omitempty"`
+	CreateReleaseArchives        bool     `json:"createReleaseArchives,omitempty"`
+	ReleaseArchiveNameTemplate   string   `json:"releaseArchiveNameTemplate,omitempty"`
+	ReleaseArchiveFiles          []string `json:"releaseArchiveFiles,omitempty"`
+	StripBinaries                bool     `json:"stripBinaries,omitempty"`
 	TestOptions                  []string `json:"testOptions,omitempty"`
 	TestResultFormat             string   `json:"testResultFormat,omitempty" validate:"possible-values=junit standard"`
 	PrivateModules               string   `json:"privateModules,omitempty"`
@@ -51,6 +61,9 @@ type golangBuildOptions struct {
 	ArtifactVersion              string   `json:"artifactVersion,omitempty"`
 	GolangciLintURL              string   `json:"golangciLintUrl,omitempty"`
 	CreateBuildArtifactsMetadata bool     `json:"createBuildArtifactsMetadata,omitempty"`
+	DependencyCacheLocation      string   `json:"dependencyCacheLocation,omitempty"`
+	DependencyCacheCredentials   string   `json:"dependencyCacheCredentials,omitempty"`
+	DependencyCacheSave          bool     `json:"dependencyCacheSave,omitempty"`
 }
 
 type golangBuildCommonPipelineEnvironment struct {
@@ -58,6 +71,7 @@ type golangBuildCommonPipelineEnvironment struct {
 		buildSettingsInfo string
 		goBuildArtifacts  string
 		artifacts         piperenv.Artifacts
+		releaseAssets     []string
 	}
 }
 
@@ -70,6 +84,7 @@ func (p *golangBuildCommonPipelineEnvironment) persist(path, resourceName string
 		{category: "custom", name: "buildSettingsInfo", value: p.custom.buildSettingsInfo},
 		{category: "custom", name: "goBuildArtifacts", value: p.custom.goBuildArtifacts},
 		{category: "custom", name: "artifacts", value: p.custom.artifacts},
+		{category: "custom", name: "releaseAssets", value: p.custom.releaseAssets},
 	}
 
 	errCount := 0
@@ -98,6 +113,8 @@ func (p *golangBuildReports) persist(stepConfig golangBuildOptions, gcpJsonKeyFi
 		{FilePattern: "**/bom-golang.xml", ParamRef: "", StepResultType: "sbom"},
 		{FilePattern: "**/TEST-*.xml", ParamRef: "", StepResultType: "junit"},
 		{FilePattern: "**/cobertura-coverage.xml", ParamRef: "", StepResultType: "cobertura-coverage"},
+		{FilePattern: "**/govulncheck/piper_govulncheck_report.json", ParamRef: "", StepResultType: "govulncheck"},
+		{FilePattern: "**/govulncheck/piper_govulncheck.sarif", ParamRef: "", StepResultType: "govulncheck"},
 	}
 
 	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
@@ -142,7 +159,52 @@ It will also execute golang-based tests using [gotestsum](https://github.com/got
 
 Besides execution of the default tests the step allows for running an additional integration test run using ` + "`" + `-tags=integration` + "`" + ` using pattern ` + "`" + `./...` + "`" + `
 
-If the build is successful the resulting artifact can be uploaded to e.g. a binary repository automatically.`,
+If the build is successful the resulting artifact can be uploaded to e.g. a binary repository automatically.
+
+### vulnerability check
+
+With ` + "`" + `runVulnerabilityCheck` + "`" + ` the ` + "`" + `packages` + "`" + ` are analyzed with [govulncheck](https://go.dev/doc/security/vuln/) for known vulnerabilities of the [Go vulnerability database](https://vuln.go.dev).
+govulncheck analyzes the call graph and distinguishes vulnerable functions which are called by the code from vulnerable packages which are only imported and vulnerable modules which are only required.
+By default the build fails if vulnerable functions are called, see ` + "`" + `failOnVulnerabilities` + "`" + `.
+In an environment without internet access the ` + "`" + `vulnerabilityDatabase` + "`" + ` can point to a local mirror of the database, the ` + "`" + `govulncheckPackage` + "`" + ` has to be available via the ` + "`" + `goProxy` + "`" + ` then.
+
+Vulnerabilities which have been assessed, e.g. since they are mitigated, are listed in the ` + "`" + `assessmentFile` + "`" + ` and don't fail the build:
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+ignore:
+  - vulnerability: GO-2024-2687
+    status: notRelevant
+    analysis: mitigated
+    purls:
+      - purl: pkg:golang/golang.org/x/net
+` + "`" + `` + "`" + `` + "`" + `
+
+The findings are reported as SARIF and JSON report and as markdown report per vulnerability without assessment in the ` + "`" + `govulncheck` + "`" + ` folder.
+
+### release archives
+
+With ` + "`" + `createReleaseArchives` + "`" + ` the binaries built for each of the ` + "`" + `targetArchitectures` + "`" + ` are packaged together with the ` + "`" + `releaseArchiveFiles` + "`" + ` into a release archive in the ` + "`" + `dist` + "`" + ` folder,
+a zip archive for windows and a gzipped tar archive for other platforms. The SHA-256 digests of the archives are listed in ` + "`" + `dist/checksums.txt` + "`" + `.
+The binaries are always built with ` + "`" + `-trimpath` + "`" + ` and the archive entries get the time of the last commit (or ` + "`" + `SOURCE_DATE_EPOCH` + "`" + `) as well as fixed permissions, so that rebuilding a commit results in identical archives.
+
+The version can be embedded into the binaries with the ` + "`" + `ldflagsTemplate` + "`" + `, e.g. ` + "`" + `-X main.version={{index .CPE "artifactVersion"}}` + "`" + `.
+With ` + "`" + `publish` + "`" + ` the release archives and the checksums file are uploaded to the ` + "`" + `targetRepositoryURL` + "`" + ` instead of the bare binaries.
+They are also provided in the commonPipelineEnvironment as ` + "`" + `custom/releaseAssets` + "`" + `, which [githubPublishRelease](githubPublishRelease.md) uploads as release assets.
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+steps:
+  golangBuild:
+    output: dist/mytool
+    targetArchitectures:
+      - linux,amd64
+      - darwin,arm64
+      - windows,amd64
+    createReleaseArchives: true
+    releaseArchiveFiles:
+      - LICENSE
+      - README.md
+    stripBinaries: true
+` + "`" + `` + "`" + `` + "`" + ``,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -176,6 +238,7 @@ If the build is successful the resulting artifact can be uploaded to e.g. a bina
 			log.RegisterSecret(stepConfig.TargetRepositoryPassword)
 			log.RegisterSecret(stepConfig.TargetRepositoryUser)
 			log.RegisterSecret(stepConfig.PrivateModulesGitToken)
+			log.RegisterSecret(stepConfig.DependencyCacheCredentials)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -272,6 +335,7 @@ func addGolangBuildFlags(cmd *cobra.Command, stepConfig *golangBuildOptions) {
 	cmd.Flags().BoolVar(&stepConfig.CgoEnabled, "cgoEnabled", false, "If active: enables the creation of Go packages that call C code.")
 	cmd.Flags().StringVar(&stepConfig.CoverageFormat, "coverageFormat", `html`, "Defines the format of the coverage repository.")
 	cmd.Flags().BoolVar(&stepConfig.CreateBOM, "createBOM", false, "Creates the bill of materials (BOM) using CycloneDX plugin. It requires Go 1.17 or newer.")
+	cmd.Flags().BoolVar(&stepConfig.CreateProvenance, "createProvenance", false, "Creates an [in-toto](https://github.com/in-toto/attestation) statement with the [SLSA provenance](https://slsa.dev/spec/v1.0/provenance) of the build artifacts in `provenance/golangBuild.intoto.json`. It contains the digests of the artifacts, the invoking configuration, the source, the build run of the orchestrator and the dependencies listed in the BOM (see `createBOM`). The built binaries are the build artifacts.")
 	cmd.Flags().StringSliceVar(&stepConfig.CustomTLSCertificateLinks, "customTlsCertificateLinks", []string{}, "List of download links to custom TLS certificates. This is required to ensure trusted connections to instances with repositories (like nexus) when publish flag is set to true.")
 	cmd.Flags().StringVar(&stepConfig.GoProxy, "goProxy", os.Getenv("PIPER_goProxy"), "Configures the value of the GOPROXY environment variable, specifying the Go module proxy to use for dependency resolution. Supports a list of proxies separated by commas (`,`) or pipes (`|`). A comma causes Go to fall through to the next proxy only on `404`/`410` responses; a pipe causes fallthrough on any error. Use `|direct` if your proxy may return non-404/410 errors for modules it cannot serve (e.g. `\"https://proxy.example.com|direct\"`).")
 	cmd.Flags().BoolVar(&stepConfig.ExcludeGeneratedFromCoverage, "excludeGeneratedFromCoverage", true, "Defines if generated files should be excluded, according to [https://golang.org/s/generatedcode](https://golang.org/s/generatedcode).")
@@ -288,6 +352,15 @@ func addGolangBuildFlags(cmd *cobra.Command, stepConfig *golangBuildOptions) {
 	cmd.Flags().BoolVar(&stepConfig.RunTests, "runTests", true, "Activates execution of tests using [gotestsum](https://github.com/gotestyourself/gotestsum). Tag Go unit tests with 'unit' build tag to exclude them using `--runTests=false`")
 	cmd.Flags().BoolVar(&stepConfig.RunIntegrationTests, "runIntegrationTests", false, "Activates execution of a second test run using tag `integration`.")
 	cmd.Flags().StringSliceVar(&stepConfig.TargetArchitectures, "targetArchitectures", []string{`linux,amd64`}, "Defines the target architectures for which the build should run using OS and architecture separated by a comma. If you specify multiple architectures, make sure to set [output](#output) parameter as well.")
+	cmd.Flags().BoolVar(&stepConfig.RunVulnerabilityCheck, "runVulnerabilityCheck", false, "Analyzes the `packages` with [govulncheck](https://go.dev/doc/security/vuln/) for known vulnerabilities.")
+	cmd.Flags().StringVar(&stepConfig.GovulncheckPackage, "govulncheckPackage", `golang.org/x/vuln/cmd/govulncheck@v1.1.4`, "Specifies the govulncheck package and version installed with `go install`.")
+	cmd.Flags().StringVar(&stepConfig.VulnerabilityDatabase, "vulnerabilityDatabase", os.Getenv("PIPER_vulnerabilityDatabase"), "URL of the Go vulnerability database or path of a local mirror of it, which allows running the vulnerability check offline. Defaults to `https://vuln.go.dev`.")
+	cmd.Flags().StringVar(&stepConfig.FailOnVulnerabilities, "failOnVulnerabilities", `called`, "Defines which vulnerabilities without assessment fail the build: vulnerable functions which are `called`, vulnerable packages which are at least `imported`, vulnerable modules which are at least `required` or `none`.")
+	cmd.Flags().StringVar(&stepConfig.AssessmentFile, "assessmentFile", `hs-assessments.yaml`, "Path to the YAML file with the assessments of vulnerabilities found by the vulnerability check.")
+	cmd.Flags().BoolVar(&stepConfig.CreateReleaseArchives, "createReleaseArchives", false, "Packages the binaries of each target architecture into a release archive in the `dist` folder and lists the SHA-256 digests of the archives in `dist/checksums.txt`.")
+	cmd.Flags().StringVar(&stepConfig.ReleaseArchiveNameTemplate, "releaseArchiveNameTemplate", `{{.Name}}_{{.Version}}_{{.OS}}_{{.Arch}}`, "Defines the name of the release archives without extension in a golang template format. It can use the fields `.Name` (go module name), `.Version` (see `artifactVersion`), `.OS` and `.Arch` as well as commonPipelineEnvironment parameters in the form `.CPE[\"<paramName>\"]`.")
+	cmd.Flags().StringSliceVar(&stepConfig.ReleaseArchiveFiles, "releaseArchiveFiles", []string{}, "Additional files added to the release archives of all target architectures, e.g. `LICENSE` or `README.md`.")
+	cmd.Flags().BoolVar(&stepConfig.StripBinaries, "stripBinaries", false, "Omits the symbol table and the debug information from the binaries using `-ldflags \"-s -w\"`, which reduces their size.")
 	cmd.Flags().StringSliceVar(&stepConfig.TestOptions, "testOptions", []string{}, "Options to pass to test as per `go test` documentation (comprises e.g. flags, packages).")
 	cmd.Flags().StringVar(&stepConfig.TestResultFormat, "testResultFormat", `junit`, "Defines the output format of the test results.")
 	cmd.Flags().StringVar(&stepConfig.PrivateModules, "privateModules", os.Getenv("PIPER_privateModules"), "Tells go which modules shall be considered to be private (by setting [GOPRIVATE](https://pkg.go.dev/cmd/go#hdr-Configuration_for_downloading_non_public_code)).")
@@ -295,6 +368,9 @@ func addGolangBuildFlags(cmd *cobra.Command, stepConfig *golangBuildOptions) {
 	cmd.Flags().StringVar(&stepConfig.ArtifactVersion, "artifactVersion", os.Getenv("PIPER_artifactVersion"), "Version of the artifact to be built.")
 	cmd.Flags().StringVar(&stepConfig.GolangciLintURL, "golangciLintUrl", `https://github.com/golangci/golangci-lint/releases/download/v1.64.8/golangci-lint-1.64.8-linux-amd64.tar.gz`, "Specifies the download url of the Golangci-Lint Linux amd64 tar binary file. This can be found at https://github.com/golangci/golangci-lint/releases. **Notice:** We will soon upgrade to V2 which requires configuration migration if present. Migration guide: https://golangci-lint.run/usage/migration-guide/")
 	cmd.Flags().BoolVar(&stepConfig.CreateBuildArtifactsMetadata, "createBuildArtifactsMetadata", false, "metadata about the artifacts that are build and published , this metadata is generally used by steps downstream in the pipeline")
+	cmd.Flags().StringVar(&stepConfig.DependencyCacheLocation, "dependencyCacheLocation", os.Getenv("PIPER_dependencyCacheLocation"), "Location of the dependency cache, either a local directory (e.g. a volume shared between builds) or a bucket URL with an optional prefix: `s3://<bucket>/<prefix>`, `gs://<bucket>/<prefix>` or `azblob://<container>/<prefix>`. The cache is keyed by a hash of the `go.sum` files and restored before the build. If empty, no dependency cache is used.")
+	cmd.Flags().StringVar(&stepConfig.DependencyCacheCredentials, "dependencyCacheCredentials", os.Getenv("PIPER_dependencyCacheCredentials"), "JSON credentials of the bucket used as dependency cache. For S3 `{\"access_key_id\": \"\", \"secret_access_key\": \"\", \"region\": \"\"}` with an optional `endpoint` for S3 compatible storages, the default AWS credential chain is used if empty. For Google Cloud Storage the JSON key of a service account. For Azure `{\"account_name\": \"\", \"sas_token\": \"\"}`.")
+	cmd.Flags().BoolVar(&stepConfig.DependencyCacheSave, "dependencyCacheSave", true, "Saves the dependency cache after a successful build if no cache was restored for the current key. Set to false for read-only access, e.g. in pull request builds.")
 
 	cmd.MarkFlagRequired("targetArchitectures")
 }
@@ -311,6 +387,7 @@ func golangBuildMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
 					{Name: "golangPrivateModulesGitTokenCredentialsId", Description: "Jenkins 'Username with password' credentials ID containing username/password for http access to your git repos where your go private modules are stored.", Type: "jenkins"},
+					{Name: "dependencyCacheCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the JSON credentials of the bucket used as dependency cache.", Type: "jenkins"},
 				},
 				Parameters: []config.StepParameters{
 					{
@@ -363,6 +440,15 @@ func golangBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "createProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 					{
 						Name:        "customTlsCertificateLinks",
 						ResourceRef: []config.ResourceReference{},
@@ -537,6 +623,87 @@ func golangBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     []string{`linux,amd64`},
 					},
+					{
+						Name:        "runVulnerabilityCheck",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "govulncheckPackage",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `golang.org/x/vuln/cmd/govulncheck@v1.1.4`,
+					},
+					{
+						Name:        "vulnerabilityDatabase",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_vulnerabilityDatabase"),
+					},
+					{
+						Name:        "failOnVulnerabilities",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `called`,
+					},
+					{
+						Name:        "assessmentFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `hs-assessments.yaml`,
+					},
+					{
+						Name:        "createReleaseArchives",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "releaseArchiveNameTemplate",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `{{.Name}}_{{.Version}}_{{.OS}}_{{.Arch}}`,
+					},
+					{
+						Name:        "releaseArchiveFiles",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "stripBinaries",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 					{
 						Name:        "testOptions",
 						ResourceRef: []config.ResourceReference{},
@@ -617,6 +784,44 @@ func golangBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "dependencyCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_dependencyCacheLocation"),
+					},
+					{
+						Name: "dependencyCacheCredentials",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "dependencyCacheCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "dependencyCacheVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "dependency-cache",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_dependencyCacheCredentials"),
+					},
+					{
+						Name:        "dependencyCacheSave",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -631,6 +836,7 @@ func golangBuildMetadata() config.StepData {
 							{"name": "custom/buildSettingsInfo"},
 							{"name": "custom/goBuildArtifacts"},
 							{"name": "custom/artifacts", "type": "piperenv.Artifacts"},
+							{"name": "custom/releaseAssets", "type": "[]string"},
 						},
 					},
 					{
@@ -640,6 +846,8 @@ func golangBuildMetadata() config.StepData {
 							{"filePattern": "**/bom-golang.xml", "type": "sbom"},
 							{"filePattern": "**/TEST-*.xml", "type": "junit"},
 							{"filePattern": "**/cobertura-coverage.xml", "type": "cobertura-coverage"},
+							{"filePattern": "**/govulncheck/piper_govulncheck_report.json", "type": "govulncheck"},
+							{"filePattern": "**/govulncheck/piper_govulncheck.sarif", "type": "govulncheck"},
 						},
 					},
 				},
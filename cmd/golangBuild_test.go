@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
@@ -18,6 +19,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"golang.org/x/mod/modfile"
 	"golang.org/x/mod/module"
@@ -249,6 +251,43 @@ go 1.17`
 		}
 	})
 
+	t.Run("success - publishes release archives", func(t *testing.T) {
+		t.Setenv("SOURCE_DATE_EPOCH", "")
+		config := golangBuildOptions{
+			TargetArchitectures:   []string{"linux,amd64", "windows,amd64"},
+			Output:                "testBin",
+			Publish:               true,
+			TargetRepositoryURL:   "https://my.target.repository.local",
+			ArtifactVersion:       "1.0.0",
+			CreateReleaseArchives: true,
+			ReleaseArchiveFiles:   []string{"LICENSE"},
+			StripBinaries:         true,
+			LdflagsTemplate:       "-X main.version=1.0.0",
+		}
+		utils := newGolangBuildTestsUtils()
+		utils.returnFileUploadStatus = 201
+		utils.StdoutReturn = map[string]string{"git log -1 --format=%ct": "1760860800\n"}
+		utils.FilesMock.AddFile("go.mod", []byte("module example.com/my/module"))
+		utils.FilesMock.AddFile("LICENSE", []byte("Apache-2.0"))
+		utils.FilesMock.AddFile("testBin-linux.amd64", []byte("linux binary"))
+		utils.FilesMock.AddFile("testBin-windows.amd64.exe", []byte("windows binary"))
+		telemetryData := telemetry.CustomData{}
+		cpe := golangBuildCommonPipelineEnvironment{}
+
+		err := runGolangBuild(&config, &telemetryData, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Contains(t, utils.ExecMockRunner.Calls, mock.ExecCall{Exec: "go", Params: []string{"build", "-trimpath", "-o", "testBin-linux.amd64", "-ldflags", "-s -w -X main.version=1.0.0"}})
+			releaseAssets := []string{filepath.Join("dist", "module_1.0.0_linux_amd64.tar.gz"), filepath.Join("dist", "module_1.0.0_windows_amd64.zip"), filepath.Join("dist", "checksums.txt")}
+			assert.Equal(t, releaseAssets, cpe.custom.releaseAssets)
+			for _, asset := range releaseAssets {
+				assert.True(t, utils.HasWrittenFile(asset), asset)
+				assert.Equal(t, "https://my.target.repository.local/go/example.com/my/module/1.0.0/"+filepath.ToSlash(asset), utils.fileUploads[asset])
+			}
+			assert.Len(t, utils.fileUploads, 3)
+		}
+	})
+
 	t.Run("success - publishes binaries (when TargetRepositoryURL ends with slash)", func(t *testing.T) {
 		config := golangBuildOptions{
 			TargetArchitectures:          []string{"linux,amd64"},
@@ -1812,3 +1851,111 @@ func TestRunBOMCreation(t *testing.T) {
 		assert.EqualError(t, err, "BOM creation failed: BOM creation failure")
 	})
 }
+
+func TestPrepareGolangReleaseArchives(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1760860800")
+	tests := map[string]string{
+		"example.com/my/module":    "module",
+		"example.com/my/module/v2": "module",
+		"gopkg.in/yaml.v3":         "yaml",
+	}
+	for modulePath, expected := range tests {
+		goModFile, err := modfile.Parse("go.mod", []byte("module "+modulePath), nil)
+		require.NoError(t, err)
+
+		options, err := prepareGolangReleaseArchives(&golangBuildOptions{Output: "testBin", ArtifactVersion: "2.0.0"}, goModFile, newGolangBuildTestsUtils(), t.TempDir())
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, options.Name, modulePath)
+		assert.Equal(t, "2.0.0", options.Version)
+	}
+}
+
+func TestGolangReleaseTimestamp(t *testing.T) {
+	t.Run("SOURCE_DATE_EPOCH", func(t *testing.T) {
+		t.Setenv("SOURCE_DATE_EPOCH", "1760860800")
+		utils := newGolangBuildTestsUtils()
+
+		assert.Equal(t, time.Date(2025, time.October, 19, 8, 0, 0, 0, time.UTC), golangReleaseTimestamp(utils))
+		assert.Empty(t, utils.Calls)
+	})
+
+	t.Run("last commit", func(t *testing.T) {
+		t.Setenv("SOURCE_DATE_EPOCH", "")
+		utils := newGolangBuildTestsUtils()
+		utils.StdoutReturn = map[string]string{"git log -1 --format=%ct": "1760860800\n"}
+
+		assert.Equal(t, time.Date(2025, time.October, 19, 8, 0, 0, 0, time.UTC), golangReleaseTimestamp(utils))
+	})
+
+	t.Run("no commit", func(t *testing.T) {
+		t.Setenv("SOURCE_DATE_EPOCH", "")
+		utils := newGolangBuildTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"git log -1 --format=%ct": errors.New("not a git repository")}
+
+		assert.Equal(t, time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC), golangReleaseTimestamp(utils))
+	})
+}
+
+func TestRunGolangVulnerabilityCheck(t *testing.T) {
+	vulncheckOutput := `{"osv":{"id":"GO-2024-2687","aliases":["CVE-2023-45288"],"summary":"HTTP/2 CONTINUATION flood in net/http"}}
+{"osv":{"id":"GO-2023-1988","summary":"Improper rendering of text nodes in golang.org/x/net/html"}}
+{"finding":{"osv":"GO-2024-2687","fixed_version":"v0.23.0","trace":[{"module":"golang.org/x/net","version":"v0.10.0","package":"golang.org/x/net/http2","function":"ReadFrame","receiver":"*Framer"},{"module":"private.example.com/test","package":"private.example.com/test","function":"main","position":{"filename":"main.go","line":12}}]}}
+{"finding":{"osv":"GO-2023-1988","fixed_version":"v0.13.0","trace":[{"module":"golang.org/x/net","version":"v0.10.0","package":"golang.org/x/net/html"}]}}
+`
+	goModFile, err := modfile.Parse("go.mod", []byte("module private.example.com/test\n\ngo 1.17"), nil)
+	require.NoError(t, err)
+
+	t.Run("success - offline database and assessment", func(t *testing.T) {
+		config := golangBuildOptions{
+			GovulncheckPackage:    "golang.org/x/vuln/cmd/govulncheck@v1.1.4",
+			VulnerabilityDatabase: "vulndb",
+			FailOnVulnerabilities: "called",
+			AssessmentFile:        "hs-assessments.yaml",
+			BuildFlags:            []string{"-tags=release"},
+			Packages:              []string{"./cmd/..."},
+		}
+		utils := newGolangBuildTestsUtils()
+		utils.AddFile("hs-assessments.yaml", []byte("ignore:\n  - vulnerability: CVE-2023-45288\n    status: notRelevant\n    analysis: mitigated\n    purls:\n      - purl: pkg:golang/golang.org/x/net\n"))
+		utils.StdoutReturn = map[string]string{"govulncheck -json -db file:///vulndb -tags release ./cmd/...": vulncheckOutput}
+
+		err := runGolangVulnerabilityCheck(&config, goModFile, utils)
+
+		require.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "go", Params: []string{"install", "golang.org/x/vuln/cmd/govulncheck@v1.1.4"}},
+			{Exec: "govulncheck", Params: []string{"-json", "-db", "file:///vulndb", "-tags", "release", "./cmd/..."}},
+		}, utils.Calls)
+		assert.True(t, utils.HasWrittenFile(filepath.Join("govulncheck", "piper_govulncheck.sarif")))
+		assert.True(t, utils.HasWrittenFile(filepath.Join("govulncheck", "GO-2023-1988-golang.org_x_net.md")))
+		assert.False(t, utils.HasWrittenFile(filepath.Join("govulncheck", "GO-2024-2687-golang.org_x_net.md")))
+	})
+
+	t.Run("failure - vulnerable function called", func(t *testing.T) {
+		config := golangBuildOptions{FailOnVulnerabilities: "called"}
+		utils := newGolangBuildTestsUtils()
+		utils.StdoutReturn = map[string]string{"govulncheck -json ./...": vulncheckOutput}
+
+		err := runGolangVulnerabilityCheck(&config, goModFile, utils)
+
+		assert.EqualError(t, err, "1 vulnerabilit(ies) without assessment which are at least called found, see govulncheck")
+	})
+
+	t.Run("success - failOnVulnerabilities none", func(t *testing.T) {
+		config := golangBuildOptions{FailOnVulnerabilities: "none"}
+		utils := newGolangBuildTestsUtils()
+		utils.StdoutReturn = map[string]string{"govulncheck -json ./...": vulncheckOutput}
+
+		assert.NoError(t, runGolangVulnerabilityCheck(&config, goModFile, utils))
+	})
+
+	t.Run("failure - govulncheck", func(t *testing.T) {
+		config := golangBuildOptions{FailOnVulnerabilities: "called"}
+		utils := newGolangBuildTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"govulncheck -json ./...": errors.New("no go.mod file")}
+
+		err := runGolangVulnerabilityCheck(&config, goModFile, utils)
+
+		assert.EqualError(t, err, "failed to run govulncheck: no go.mod file")
+	})
+}
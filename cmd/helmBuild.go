@@ -5,6 +5,8 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
 	"github.com/SAP/jenkins-library/pkg/command"
@@ -54,6 +56,12 @@ func helmBuild(config helmBuildOptions, telemetryData *telemetry.CustomData, com
 		Version:                   config.Version,
 		PublishVersion:            config.Version,
 		RenderSubchartNotes:       config.RenderSubchartNotes,
+		SignChart:                 config.SignChart,
+		SigningKey:                config.SigningKey,
+		SigningKeyring:            config.SigningKeyring,
+		SigningPassphrase:         config.SigningPassphrase,
+		VerifyDependencies:        config.VerifyDependencies,
+		DependencyKeyring:         config.DependencyKeyring,
 	}
 
 	utils := kubernetes.NewDeployUtilsBundle(helmConfig.CustomTLSCertificateLinks)
@@ -105,6 +113,20 @@ func runHelmBuild(config helmBuildOptions, helmExecutor kubernetes.HelmExecutor,
 			log.Entry().WithError(err).Fatalf("failed to parse/render template: %v", err)
 		}
 	}
+	restoreDependencies := func() error { return nil }
+	if len(config.DependencyMirrors) > 0 {
+		_, restore, err := helm.MirrorDependencies(config.ChartPath, config.DependencyMirrors, fileUtils)
+		if err != nil {
+			return fmt.Errorf("failed to mirror chart dependencies: %w", err)
+		}
+		restoreDependencies = restore
+		// the mirrors must neither remain in the workspace nor be published with the chart
+		defer func() {
+			if err := restore(); err != nil {
+				log.Entry().Warnf("failed to restore the chart dependencies: %v", err)
+			}
+		}()
+	}
 	switch config.HelmCommand {
 	case "upgrade":
 		if err := helmExecutor.RunHelmUpgrade(); err != nil {
@@ -136,11 +158,18 @@ func runHelmBuild(config helmBuildOptions, helmExecutor kubernetes.HelmExecutor,
 			return fmt.Errorf("failed to execute helm publish: %v", err)
 		}
 		commonPipelineEnvironment.custom.helmChartURL = targetURL
+		if err := signHelmChart(config, targetURL, fileUtils); err != nil {
+			return fmt.Errorf("signing of chart failed: %w", err)
+		}
 		if config.CreateBOM {
 			generateSBOMs(config, helmExecutor, execRunner, fileUtils, httpClient)
 		}
+	case "verify":
+		if err := runHelmVerify(config, helmExecutor, fileUtils); err != nil {
+			return err
+		}
 	default:
-		if err := runHelmBuildDefault(config, helmExecutor, commonPipelineEnvironment, execRunner, fileUtils, httpClient); err != nil {
+		if err := runHelmBuildDefault(config, helmExecutor, commonPipelineEnvironment, execRunner, fileUtils, httpClient, restoreDependencies); err != nil {
 			return err
 		}
 	}
@@ -167,23 +196,38 @@ func runHelmBuild(config helmBuildOptions, helmExecutor kubernetes.HelmExecutor,
 	return nil
 }
 
-func runHelmBuildDefault(config helmBuildOptions, helmExecutor kubernetes.HelmExecutor, commonPipelineEnvironment *helmBuildCommonPipelineEnvironment, execRunner command.ExecRunner, fileUtils piperutils.FileUtils, httpClient piperhttp.Sender) error {
+func runHelmBuildDefault(config helmBuildOptions, helmExecutor kubernetes.HelmExecutor, commonPipelineEnvironment *helmBuildCommonPipelineEnvironment, execRunner command.ExecRunner, fileUtils piperutils.FileUtils, httpClient piperhttp.Sender, restoreDependencies func() error) error {
 	if len(config.Dependency) > 0 {
 		if err := helmExecutor.RunHelmDependency(); err != nil {
 			return fmt.Errorf("failed to execute helm dependency: %v", err)
 		}
+		// packaging with dependency update resolves the dependencies again and still requires the mirrors
+		if !config.PackageDependencyUpdate {
+			if err := restoreDependencies(); err != nil {
+				return fmt.Errorf("failed to restore chart dependencies: %w", err)
+			}
+		}
 	}
 
 	if err := helmExecutor.RunHelmLint(); err != nil {
 		return fmt.Errorf("failed to execute helm lint: %v", err)
 	}
 
+	if config.VerifyChart {
+		if err := runHelmVerify(config, helmExecutor, fileUtils); err != nil {
+			return err
+		}
+	}
+
 	if config.Publish {
 		targetURL, err := helmExecutor.RunHelmPublish()
 		if err != nil {
 			return fmt.Errorf("failed to execute helm publish: %v", err)
 		}
 		commonPipelineEnvironment.custom.helmChartURL = targetURL
+		if err := signHelmChart(config, targetURL, fileUtils); err != nil {
+			return fmt.Errorf("signing of chart failed: %w", err)
+		}
 		if config.CreateBOM {
 			generateSBOMs(config, helmExecutor, execRunner, fileUtils, httpClient)
 		}
@@ -192,6 +236,93 @@ func runHelmBuildDefault(config helmBuildOptions, helmExecutor kubernetes.HelmEx
 	return nil
 }
 
+// signHelmChart signs the chart pushed to an OCI registry in the format of cosign
+func signHelmChart(config helmBuildOptions, targetURL string, fileUtils piperutils.FileUtils) error {
+	if !config.SignOciChart {
+		return nil
+	}
+	chart, found := strings.CutPrefix(targetURL, "oci://")
+	if !found {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("only charts pushed to an OCI registry can be signed, please configure a targetRepositoryURL starting with 'oci://'")
+	}
+	return signContainerImages([]string{chart}, config.OciSigningKey, config.OciSigningKeyPassword, config.DockerConfigJSON, fileUtils)
+}
+
+// runHelmVerify renders the chart for each combination of verification values file and
+// Kubernetes version, checks the rendered manifests and runs the chart unit tests.
+// All checks are executed before the step fails, so that the reports contain every issue.
+func runHelmVerify(config helmBuildOptions, helmExecutor kubernetes.HelmExecutor, fileUtils piperutils.FileUtils) error {
+	results := []helm.CheckResult{}
+
+	if config.RequireValuesSchema {
+		result := helm.CheckResult{Scenario: helm.ScenarioName("", ""), Check: helm.CheckValuesSchema, Findings: []helm.Finding{}}
+		if exists, _ := fileUtils.FileExists(filepath.Join(config.ChartPath, "values.schema.json")); !exists {
+			result.Findings = append(result.Findings, helm.Finding{Message: "the chart does not provide a values.schema.json", Severity: helm.SeverityError})
+		}
+		results = append(results, result)
+	}
+
+	valuesFiles := config.VerificationValuesFiles
+	if len(valuesFiles) == 0 {
+		valuesFiles = []string{""}
+	}
+	kubeVersions := config.KubeVersions
+	if len(kubeVersions) == 0 {
+		kubeVersions = []string{""}
+	}
+	for _, valuesFile := range valuesFiles {
+		values := []string{}
+		if len(valuesFile) > 0 {
+			values = append(values, valuesFile)
+		}
+		for _, kubeVersion := range kubeVersions {
+			scenario := helm.ScenarioName(valuesFile, kubeVersion)
+			log.Entry().Infof("Verifying chart with %v", scenario)
+			manifests, err := helmExecutor.RunHelmTemplateFor(values, kubeVersion)
+			if err != nil {
+				results = append(results, helm.RenderFailure(scenario, err))
+				continue
+			}
+			checks, err := helm.CheckManifests(scenario, manifests, kubeVersion, config.PolicyChecks)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return fmt.Errorf("failed to check rendered manifests: %w", err)
+			}
+			results = append(results, checks...)
+		}
+	}
+
+	if config.RunChartUnitTests {
+		if err := fileUtils.MkdirAll(helm.VerificationReportsDirectory, 0777); err != nil {
+			return fmt.Errorf("failed to create report directory: %w", err)
+		}
+		result := helm.CheckResult{Scenario: helm.ScenarioName("", ""), Check: helm.CheckUnitTests, Findings: []helm.Finding{}}
+		if err := helmExecutor.RunHelmUnitTest(filepath.Join(helm.VerificationReportsDirectory, helm.UnitTestReportFile)); err != nil {
+			result.Findings = append(result.Findings, helm.Finding{Message: err.Error(), Severity: helm.SeverityError})
+		}
+		results = append(results, result)
+	}
+
+	chartName := filepath.Base(config.ChartPath)
+	reports, err := helm.WriteReports(chartName, results, time.Now(), fileUtils)
+	if err != nil {
+		return fmt.Errorf("failed to write chart verification reports: %w", err)
+	}
+	piperutils.PersistReportsAndLinks("helmBuild", "", fileUtils, reports, nil)
+
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			log.Entry().Infof("[%v] %v: %v (%v)", result.Scenario, result.Check, finding, finding.Severity)
+		}
+	}
+	if errorCount := helm.CountFindings(results, helm.SeverityError); errorCount > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("chart verification failed with %v error(s), see %v for details", errorCount, helm.VerificationReportsDirectory)
+	}
+	return nil
+}
+
 // generateSBOMs produces both SBOMs for the published chart, sharing a single
 // discovered image set so the chart BOM and the container BOMs describe the
 // same images. Both are best-effort: a failure is logged but never fails the
@@ -23,41 +23,57 @@ import (
 )
 
 type helmBuildOptions struct {
-	AdditionalParameters      []string `json:"additionalParameters,omitempty"`
-	ChartPath                 string   `json:"chartPath,omitempty"`
-	TargetRepositoryURL       string   `json:"targetRepositoryURL,omitempty"`
-	TargetRepositoryName      string   `json:"targetRepositoryName,omitempty"`
-	TargetRepositoryUser      string   `json:"targetRepositoryUser,omitempty"`
-	TargetRepositoryPassword  string   `json:"targetRepositoryPassword,omitempty"`
-	SourceRepositoryURL       string   `json:"sourceRepositoryURL,omitempty"`
-	SourceRepositoryName      string   `json:"sourceRepositoryName,omitempty"`
-	SourceRepositoryUser      string   `json:"sourceRepositoryUser,omitempty"`
-	SourceRepositoryPassword  string   `json:"sourceRepositoryPassword,omitempty"`
-	HelmDeployWaitSeconds     int      `json:"helmDeployWaitSeconds,omitempty"`
-	HelmValues                []string `json:"helmValues,omitempty"`
-	Image                     string   `json:"image,omitempty"`
-	KeepFailedDeployments     bool     `json:"keepFailedDeployments,omitempty"`
-	KubeConfig                string   `json:"kubeConfig,omitempty"`
-	KubeContext               string   `json:"kubeContext,omitempty"`
-	Namespace                 string   `json:"namespace,omitempty"`
-	DockerConfigJSON          string   `json:"dockerConfigJSON,omitempty"`
-	HelmCommand               string   `json:"helmCommand,omitempty" validate:"possible-values=upgrade lint install test uninstall dependency publish"`
-	AppVersion                string   `json:"appVersion,omitempty"`
-	Dependency                string   `json:"dependency,omitempty" validate:"possible-values=build list update"`
-	PackageDependencyUpdate   bool     `json:"packageDependencyUpdate,omitempty"`
-	DumpLogs                  bool     `json:"dumpLogs,omitempty"`
-	FilterTest                string   `json:"filterTest,omitempty"`
-	CustomTLSCertificateLinks []string `json:"customTlsCertificateLinks,omitempty"`
-	Publish                   bool     `json:"publish,omitempty"`
-	Version                   string   `json:"version,omitempty"`
-	RenderSubchartNotes       bool     `json:"renderSubchartNotes,omitempty"`
-	TemplateStartDelimiter    string   `json:"templateStartDelimiter,omitempty"`
-	TemplateEndDelimiter      string   `json:"templateEndDelimiter,omitempty"`
-	RenderValuesTemplate      bool     `json:"renderValuesTemplate,omitempty"`
-	CreateBOM                 bool     `json:"createBOM,omitempty"`
-	SyftDownloadURL           string   `json:"syftDownloadUrl,omitempty"`
-	ContainerImageNameTags    []string `json:"containerImageNameTags,omitempty"`
-	BuildSettingsInfo         string   `json:"buildSettingsInfo,omitempty"`
+	AdditionalParameters      []string          `json:"additionalParameters,omitempty"`
+	ChartPath                 string            `json:"chartPath,omitempty"`
+	TargetRepositoryURL       string            `json:"targetRepositoryURL,omitempty"`
+	TargetRepositoryName      string            `json:"targetRepositoryName,omitempty"`
+	TargetRepositoryUser      string            `json:"targetRepositoryUser,omitempty"`
+	TargetRepositoryPassword  string            `json:"targetRepositoryPassword,omitempty"`
+	SourceRepositoryURL       string            `json:"sourceRepositoryURL,omitempty"`
+	SourceRepositoryName      string            `json:"sourceRepositoryName,omitempty"`
+	SourceRepositoryUser      string            `json:"sourceRepositoryUser,omitempty"`
+	SourceRepositoryPassword  string            `json:"sourceRepositoryPassword,omitempty"`
+	HelmDeployWaitSeconds     int               `json:"helmDeployWaitSeconds,omitempty"`
+	HelmValues                []string          `json:"helmValues,omitempty"`
+	Image                     string            `json:"image,omitempty"`
+	KeepFailedDeployments     bool              `json:"keepFailedDeployments,omitempty"`
+	KubeConfig                string            `json:"kubeConfig,omitempty"`
+	KubeContext               string            `json:"kubeContext,omitempty"`
+	Namespace                 string            `json:"namespace,omitempty"`
+	DockerConfigJSON          string            `json:"dockerConfigJSON,omitempty"`
+	HelmCommand               string            `json:"helmCommand,omitempty" validate:"possible-values=upgrade lint install test uninstall dependency publish verify"`
+	AppVersion                string            `json:"appVersion,omitempty"`
+	Dependency                string            `json:"dependency,omitempty" validate:"possible-values=build list update"`
+	PackageDependencyUpdate   bool              `json:"packageDependencyUpdate,omitempty"`
+	VerifyDependencies        bool              `json:"verifyDependencies,omitempty"`
+	DependencyKeyring         string            `json:"dependencyKeyring,omitempty"`
+	DependencyMirrors         map[string]string `json:"dependencyMirrors,omitempty"`
+	DumpLogs                  bool              `json:"dumpLogs,omitempty"`
+	FilterTest                string            `json:"filterTest,omitempty"`
+	CustomTLSCertificateLinks []string          `json:"customTlsCertificateLinks,omitempty"`
+	Publish                   bool              `json:"publish,omitempty"`
+	SignChart                 bool              `json:"signChart,omitempty"`
+	SigningKey                string            `json:"signingKey,omitempty"`
+	SigningKeyring            string            `json:"signingKeyring,omitempty"`
+	SigningPassphrase         string            `json:"signingPassphrase,omitempty"`
+	SignOciChart              bool              `json:"signOciChart,omitempty"`
+	OciSigningKey             string            `json:"ociSigningKey,omitempty"`
+	OciSigningKeyPassword     string            `json:"ociSigningKeyPassword,omitempty"`
+	Version                   string            `json:"version,omitempty"`
+	RenderSubchartNotes       bool              `json:"renderSubchartNotes,omitempty"`
+	TemplateStartDelimiter    string            `json:"templateStartDelimiter,omitempty"`
+	TemplateEndDelimiter      string            `json:"templateEndDelimiter,omitempty"`
+	RenderValuesTemplate      bool              `json:"renderValuesTemplate,omitempty"`
+	CreateBOM                 bool              `json:"createBOM,omitempty"`
+	SyftDownloadURL           string            `json:"syftDownloadUrl,omitempty"`
+	ContainerImageNameTags    []string          `json:"containerImageNameTags,omitempty"`
+	VerifyChart               bool              `json:"verifyChart,omitempty"`
+	VerificationValuesFiles   []string          `json:"verificationValuesFiles,omitempty"`
+	KubeVersions              []string          `json:"kubeVersions,omitempty"`
+	RequireValuesSchema       bool              `json:"requireValuesSchema,omitempty"`
+	PolicyChecks              []string          `json:"policyChecks,omitempty" validate:"possible-values=no-privileged-containers resource-limits"`
+	RunChartUnitTests         bool              `json:"runChartUnitTests,omitempty"`
+	BuildSettingsInfo         string            `json:"buildSettingsInfo,omitempty"`
 }
 
 type helmBuildCommonPipelineEnvironment struct {
@@ -101,6 +117,7 @@ func (p *helmBuildReports) persist(stepConfig helmBuildOptions, gcpJsonKeyFilePa
 	log.Entry().Info("Uploading reports to Google Cloud Storage...")
 	content := []gcs.ReportOutputParam{
 		{FilePattern: "**/bom-*.xml", ParamRef: "", StepResultType: "sbom"},
+		{FilePattern: "helm-verification/TEST-*.xml", ParamRef: "", StepResultType: "junit"},
 	}
 
 	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
@@ -150,7 +167,7 @@ Executes helm functionality as the package manager for Kubernetes.
 * [Helm Charts](https://artifacthub.io/)
 ` + "`" + `` + "`" + `` + "`" + `
 Available Commands:
-` + "`" + `upgrade` + "`" + `, ` + "`" + `lint` + "`" + `, ` + "`" + `install` + "`" + `, ` + "`" + `test` + "`" + `, ` + "`" + `uninstall` + "`" + `, ` + "`" + `dependency` + "`" + `, ` + "`" + `publish` + "`" + `
+` + "`" + `upgrade` + "`" + `, ` + "`" + `lint` + "`" + `, ` + "`" + `install` + "`" + `, ` + "`" + `test` + "`" + `, ` + "`" + `uninstall` + "`" + `, ` + "`" + `dependency` + "`" + `, ` + "`" + `publish` + "`" + `, ` + "`" + `verify` + "`" + `
 
   upgrade       upgrade a release
   lint          examine a chart for possible issues
@@ -159,9 +176,42 @@ Available Commands:
   uninstall     uninstall a release
   dependency    package a chart directory into a chart archive
   publish       package and publish a release
+  verify        run the chart verification
 
 ` + "`" + `` + "`" + `` + "`" + `
 
+### Chart verification
+
+With ` + "`" + `verifyChart` + "`" + ` (or ` + "`" + `helmCommand: verify` + "`" + `) the chart is verified beyond ` + "`" + `helm lint` + "`" + `:
+
+* The chart is rendered with ` + "`" + `helm template` + "`" + ` for each of the ` + "`" + `verificationValuesFiles` + "`" + ` and each of the ` + "`" + `kubeVersions` + "`" + `.
+  Helm validates the values against the ` + "`" + `values.schema.json` + "`" + ` of the chart while rendering, with ` + "`" + `requireValuesSchema` + "`" + ` the chart has to provide such a schema.
+* The rendered manifests are checked for APIs which are deprecated or removed in the respective Kubernetes version. Removed APIs fail the verification.
+* The ` + "`" + `policyChecks` + "`" + ` are applied to the rendered workloads, e.g. no privileged containers and CPU and memory limits set for all containers.
+* With ` + "`" + `runChartUnitTests` + "`" + ` the unit tests of the chart are executed with the [helm-unittest](https://github.com/helm-unittest/helm-unittest) plugin, which needs to be available in the container.
+
+The results are written as JUnit report and as report for the pipeline summary into the folder ` + "`" + `helm-verification` + "`" + `. Any error fails the step.
+
+### Provenance and dependency mirrors
+
+With ` + "`" + `signChart` + "`" + ` the packaged chart is signed with the PGP key ` + "`" + `signingKey` + "`" + ` from the secret keyring ` + "`" + `signingKeyring` + "`" + ` and a provenance file (` + "`" + `.prov` + "`" + `) is created, which is published together with the chart.
+Helm requires a keyring in the legacy GnuPG format, which can be exported with ` + "`" + `gpg --export-secret-keys >secring.gpg` + "`" + `.
+
+If the ` + "`" + `targetRepositoryURL` + "`" + ` starts with ` + "`" + `oci://` + "`" + `, the chart is pushed to the OCI registry with ` + "`" + `helm push` + "`" + ` instead, e.g. ` + "`" + `oci://my.registry.com/charts` + "`" + `. The registry credentials are taken from ` + "`" + `dockerConfigJSON` + "`" + ` or ` + "`" + `targetRepositoryUser` + "`" + ` and ` + "`" + `targetRepositoryPassword` + "`" + `.
+With ` + "`" + `signOciChart` + "`" + ` the pushed chart is additionally signed with the private key ` + "`" + `ociSigningKey` + "`" + ` in the format of [cosign](https://github.com/sigstore/cosign), the signature can be verified with the step ` + "`" + `imageVerifySignature` + "`" + `.
+
+With ` + "`" + `verifyDependencies` + "`" + ` the provenance files of the dependencies are verified against the public keys in ` + "`" + `dependencyKeyring` + "`" + ` when building or updating the dependencies.
+
+With ` + "`" + `dependencyMirrors` + "`" + ` the repositories of the dependencies are replaced by internal mirrors in ` + "`" + `Chart.yaml` + "`" + ` and ` + "`" + `Chart.lock` + "`" + ` before the dependencies are resolved, e.g.
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+dependencyMirrors:
+  https://charts.bitnami.com/bitnami: https://nexus.example.com/repository/bitnami
+  oci://registry-1.docker.io/bitnamicharts: oci://mirror.example.com/bitnamicharts
+` + "`" + `` + "`" + `` + "`" + `
+
+The digest of the ` + "`" + `Chart.lock` + "`" + ` is updated accordingly. Both files are restored once the dependencies are resolved, so that the mirrors are neither published with the chart nor remain in the workspace. Mirrors of HTTP repositories need to be known to helm, e.g. by configuring them as ` + "`" + `sourceRepositoryURL` + "`" + `.
+
 Note: piper supports only helm3 version, since helm2 is deprecated.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
@@ -199,6 +249,10 @@ Note: piper supports only helm3 version, since helm2 is deprecated.`,
 			log.RegisterSecret(stepConfig.SourceRepositoryPassword)
 			log.RegisterSecret(stepConfig.KubeConfig)
 			log.RegisterSecret(stepConfig.DockerConfigJSON)
+			log.RegisterSecret(stepConfig.SigningKeyring)
+			log.RegisterSecret(stepConfig.SigningPassphrase)
+			log.RegisterSecret(stepConfig.OciSigningKey)
+			log.RegisterSecret(stepConfig.OciSigningKeyPassword)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -292,7 +346,7 @@ Note: piper supports only helm3 version, since helm2 is deprecated.`,
 func addHelmBuildFlags(cmd *cobra.Command, stepConfig *helmBuildOptions) {
 	cmd.Flags().StringSliceVar(&stepConfig.AdditionalParameters, "additionalParameters", []string{}, "Defines additional parameters for Helm like  \"helm install [NAME] [CHART] [flags]\".")
 	cmd.Flags().StringVar(&stepConfig.ChartPath, "chartPath", os.Getenv("PIPER_chartPath"), "Defines the chart path for helm. chartPath is mandatory for install/upgrade/publish commands.")
-	cmd.Flags().StringVar(&stepConfig.TargetRepositoryURL, "targetRepositoryURL", os.Getenv("PIPER_targetRepositoryURL"), "URL of the target repository where the compiled helm .tgz archive shall be uploaded - typically provided by the CI/CD environment.")
+	cmd.Flags().StringVar(&stepConfig.TargetRepositoryURL, "targetRepositoryURL", os.Getenv("PIPER_targetRepositoryURL"), "URL of the target repository where the compiled helm .tgz archive shall be uploaded - typically provided by the CI/CD environment. Charts are pushed to OCI registries with URLs starting with `oci://`.")
 	cmd.Flags().StringVar(&stepConfig.TargetRepositoryName, "targetRepositoryName", os.Getenv("PIPER_targetRepositoryName"), "set the chart repository. The value is required for install/upgrade/uninstall commands.")
 	cmd.Flags().StringVar(&stepConfig.TargetRepositoryUser, "targetRepositoryUser", os.Getenv("PIPER_targetRepositoryUser"), "Username for the chart repository where the compiled helm .tgz archive shall be uploaded - typically provided by the CI/CD environment.")
 	cmd.Flags().StringVar(&stepConfig.TargetRepositoryPassword, "targetRepositoryPassword", os.Getenv("PIPER_targetRepositoryPassword"), "Password for the target repository where the compiled helm .tgz archive shall be uploaded - typically provided by the CI/CD environment.")
@@ -308,14 +362,24 @@ func addHelmBuildFlags(cmd *cobra.Command, stepConfig *helmBuildOptions) {
 	cmd.Flags().StringVar(&stepConfig.KubeContext, "kubeContext", os.Getenv("PIPER_kubeContext"), "Defines the context to use from the \"kubeconfig\" file.")
 	cmd.Flags().StringVar(&stepConfig.Namespace, "namespace", `default`, "Defines the target Kubernetes namespace for the deployment.")
 	cmd.Flags().StringVar(&stepConfig.DockerConfigJSON, "dockerConfigJSON", os.Getenv("PIPER_dockerConfigJSON"), "Path to the file `.docker/config.json` - this is typically provided by your CI/CD system. You can find more details about the Docker credentials in the [Docker documentation](https://docs.docker.com/engine/reference/commandline/login/).")
-	cmd.Flags().StringVar(&stepConfig.HelmCommand, "helmCommand", os.Getenv("PIPER_helmCommand"), "Helm: defines the command `upgrade`, `lint`, `install`, `test`, `uninstall`, `dependency`, `publish`.")
+	cmd.Flags().StringVar(&stepConfig.HelmCommand, "helmCommand", os.Getenv("PIPER_helmCommand"), "Helm: defines the command `upgrade`, `lint`, `install`, `test`, `uninstall`, `dependency`, `publish`, `verify`.")
 	cmd.Flags().StringVar(&stepConfig.AppVersion, "appVersion", os.Getenv("PIPER_appVersion"), "set the appVersion on the chart to this version")
 	cmd.Flags().StringVar(&stepConfig.Dependency, "dependency", os.Getenv("PIPER_dependency"), "manage a chart's dependencies")
 	cmd.Flags().BoolVar(&stepConfig.PackageDependencyUpdate, "packageDependencyUpdate", false, "update dependencies from \"Chart.yaml\" to dir \"charts/\" before packaging")
+	cmd.Flags().BoolVar(&stepConfig.VerifyDependencies, "verifyDependencies", false, "Verifies the provenance files of the dependencies when building or updating the dependencies.")
+	cmd.Flags().StringVar(&stepConfig.DependencyKeyring, "dependencyKeyring", os.Getenv("PIPER_dependencyKeyring"), "Path to the keyring containing the public keys used to verify the provenance files of the dependencies. Defaults to the keyring of helm.")
+	cmd.Flags().StringToStringVar(&stepConfig.DependencyMirrors, "dependencyMirrors", map[string]string{}, "Mirrors replacing the repositories of the dependencies in `Chart.yaml` and `Chart.lock`, given as repository URL prefix and URL prefix of the mirror.")
 	cmd.Flags().BoolVar(&stepConfig.DumpLogs, "dumpLogs", false, "dump the logs from test pods (this runs after all tests are complete, but before any cleanup)")
 	cmd.Flags().StringVar(&stepConfig.FilterTest, "filterTest", os.Getenv("PIPER_filterTest"), "specify tests by attribute (currently `name`) using attribute=value syntax or `!attribute=value` to exclude a test (can specify multiple or separate values with commas `name=test1,name=test2`)")
 	cmd.Flags().StringSliceVar(&stepConfig.CustomTLSCertificateLinks, "customTlsCertificateLinks", []string{}, "List of download links to custom TLS certificates. This is required to ensure trusted connections to instances with repositories (like nexus) when publish flag is set to true.")
 	cmd.Flags().BoolVar(&stepConfig.Publish, "publish", false, "Configures helm to run the deploy command to publish artifacts to a repository.")
+	cmd.Flags().BoolVar(&stepConfig.SignChart, "signChart", false, "Signs the packaged chart with a PGP key and publishes the provenance file (`.prov`) along with the chart.")
+	cmd.Flags().StringVar(&stepConfig.SigningKey, "signingKey", os.Getenv("PIPER_signingKey"), "Name of the PGP key used to sign the chart, e.g. the email address of the key.")
+	cmd.Flags().StringVar(&stepConfig.SigningKeyring, "signingKeyring", os.Getenv("PIPER_signingKeyring"), "Path to the secret keyring containing the PGP key used to sign the chart.")
+	cmd.Flags().StringVar(&stepConfig.SigningPassphrase, "signingPassphrase", os.Getenv("PIPER_signingPassphrase"), "Passphrase of the PGP key used to sign the chart.")
+	cmd.Flags().BoolVar(&stepConfig.SignOciChart, "signOciChart", false, "Signs the chart pushed to an OCI registry with the `ociSigningKey`. The signature is stored next to the chart in the format of [cosign](https://github.com/sigstore/cosign) and can be verified with the step `imageVerifySignature`.")
+	cmd.Flags().StringVar(&stepConfig.OciSigningKey, "ociSigningKey", os.Getenv("PIPER_ociSigningKey"), "Path to the PEM encoded private key used for signing charts pushed to an OCI registry, e.g. a key pair created with `cosign generate-key-pair`.")
+	cmd.Flags().StringVar(&stepConfig.OciSigningKeyPassword, "ociSigningKeyPassword", os.Getenv("PIPER_ociSigningKeyPassword"), "Password of the encrypted `ociSigningKey`.")
 	cmd.Flags().StringVar(&stepConfig.Version, "version", os.Getenv("PIPER_version"), "Defines the artifact version to use from helm package/publish commands.")
 	cmd.Flags().BoolVar(&stepConfig.RenderSubchartNotes, "renderSubchartNotes", true, "If set, render subchart notes along with the parent.")
 	cmd.Flags().StringVar(&stepConfig.TemplateStartDelimiter, "templateStartDelimiter", `{{`, "When templating value files, use this start delimiter.")
@@ -324,6 +388,12 @@ func addHelmBuildFlags(cmd *cobra.Command, stepConfig *helmBuildOptions) {
 	cmd.Flags().BoolVar(&stepConfig.CreateBOM, "createBOM", false, "Creates the bill of materials (BOM) using Syft for referenced container images and a chart-level BOM (bom-helm.xml) in CycloneDX 1.4 format.")
 	cmd.Flags().StringVar(&stepConfig.SyftDownloadURL, "syftDownloadUrl", `https://github.com/anchore/syft/releases/download/v1.44.0/syft_1.44.0_linux_amd64.tar.gz`, "Specifies the download url of the Syft Linux amd64 tar binary file. This can be found at https://github.com/anchore/syft/releases/.")
 	cmd.Flags().StringSliceVar(&stepConfig.ContainerImageNameTags, "containerImageNameTags", []string{}, "List of full names (registry and tag) of the container images referenced by the chart. Used as a fallback source when image discovery via `helm template` yields no results. Typically populated by an upstream kanikoExecute step.")
+	cmd.Flags().BoolVar(&stepConfig.VerifyChart, "verifyChart", false, "Verifies the chart after `helm lint` in the default flow, see [Chart verification](#chart-verification).")
+	cmd.Flags().StringSliceVar(&stepConfig.VerificationValuesFiles, "verificationValuesFiles", []string{}, "Values files the chart is rendered with during the verification in addition to `helmValues`. Each file is rendered separately, without files the chart is rendered with `helmValues` only.")
+	cmd.Flags().StringSliceVar(&stepConfig.KubeVersions, "kubeVersions", []string{}, "Kubernetes versions (e.g. `1.33.0`) the chart is rendered and checked for deprecated APIs against during the verification. Without versions deprecated APIs are only reported as warnings.")
+	cmd.Flags().BoolVar(&stepConfig.RequireValuesSchema, "requireValuesSchema", false, "Fails the verification if the chart does not provide a `values.schema.json`.")
+	cmd.Flags().StringSliceVar(&stepConfig.PolicyChecks, "policyChecks", []string{`no-privileged-containers`, `resource-limits`}, "Policy checks applied to the rendered workloads during the verification.")
+	cmd.Flags().BoolVar(&stepConfig.RunChartUnitTests, "runChartUnitTests", false, "Runs the unit tests of the chart with the helm-unittest plugin during the verification.")
 	cmd.Flags().StringVar(&stepConfig.BuildSettingsInfo, "buildSettingsInfo", os.Getenv("PIPER_buildSettingsInfo"), "Build settings info is typically filled by the step automatically to create information about the build settings that were used during the helm build. This information is typically used for compliance related processes.")
 
 	cmd.MarkFlagRequired("image")
@@ -344,6 +414,10 @@ func helmBuildMetadata() config.StepData {
 					{Name: "dockerConfigJsonCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing Docker config.json (with registry credential(s)).", Type: "jenkins"},
 					{Name: "sourceRepositoryCredentialsId", Description: "Jenkins 'Username Password' credentials ID containing username and password for the Helm Repository authentication (source repo)", Type: "jenkins"},
 					{Name: "targetRepositoryCredentialsId", Description: "Jenkins 'Username Password' credentials ID containing username and password for the Helm Repository authentication (target repo)", Type: "jenkins"},
+					{Name: "signingKeyringCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the PGP secret keyring used to sign the chart.", Type: "jenkins"},
+					{Name: "signingPassphraseCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the passphrase of the PGP signing key.", Type: "jenkins"},
+					{Name: "ociSigningKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the private key used for signing charts pushed to an OCI registry.", Type: "jenkins"},
+					{Name: "ociSigningKeyPasswordCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the password of the private key used for signing charts pushed to an OCI registry.", Type: "jenkins"},
 				},
 				Resources: []config.StepResources{
 					{Name: "deployDescriptor", Type: "stash"},
@@ -652,6 +726,33 @@ func helmBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "verifyDependencies",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "dependencyKeyring",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_dependencyKeyring"),
+					},
+					{
+						Name:        "dependencyMirrors",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "map[string]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     map[string]string{},
+					},
 					{
 						Name:        "dumpLogs",
 						ResourceRef: []config.ResourceReference{},
@@ -688,6 +789,113 @@ func helmBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "signChart",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "signingKey",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_signingKey"),
+					},
+					{
+						Name: "signingKeyring",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyringCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "signingKeyringVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "helm-signing-keyring",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_signingKeyring"),
+					},
+					{
+						Name: "signingPassphrase",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingPassphraseCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "signingPassphraseVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "helm-signing",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_signingPassphrase"),
+					},
+					{
+						Name:        "signOciChart",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "ociSigningKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "ociSigningKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "ociSigningKeyFileVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "image-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_ociSigningKey"),
+					},
+					{
+						Name: "ociSigningKeyPassword",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "ociSigningKeyPasswordCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "ociSigningKeyPasswordVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "image-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_ociSigningKeyPassword"),
+					},
 					{
 						Name:        "version",
 						ResourceRef: []config.ResourceReference{},
@@ -765,6 +973,60 @@ func helmBuildMetadata() config.StepData {
 						Aliases:   []config.Alias{},
 						Default:   []string{},
 					},
+					{
+						Name:        "verifyChart",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "verificationValuesFiles",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "kubeVersions",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "requireValuesSchema",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "policyChecks",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`no-privileged-containers`, `resource-limits`},
+					},
+					{
+						Name:        "runChartUnitTests",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 					{
 						Name: "buildSettingsInfo",
 						ResourceRef: []config.ResourceReference{
@@ -799,6 +1061,7 @@ func helmBuildMetadata() config.StepData {
 						Type: "reports",
 						Parameters: []map[string]interface{}{
 							{"filePattern": "**/bom-*.xml", "type": "sbom"},
+							{"filePattern": "helm-verification/TEST-*.xml", "type": "junit"},
 						},
 					},
 				},
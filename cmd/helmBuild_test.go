@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"crypto"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,12 +15,14 @@ import (
 
 	"errors"
 
+	"github.com/SAP/jenkins-library/pkg/docker"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/kubernetes/mocks"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -324,6 +327,40 @@ func TestRunHelmPush(t *testing.T) {
 	}
 }
 
+func TestRunHelmPushSigning(t *testing.T) {
+	setupConfigOpenFileMock(t)
+	host := newSigningTestRegistry(t)
+	digest := pushSigningTestImage(t, host+"/charts/foo:1.0.0")
+	files := &mock.FilesMock{}
+	addSigningTestKeys(t, files, "cosign.key", "cosign.pub")
+
+	t.Run("chart in OCI registry is signed", func(t *testing.T) {
+		config := helmBuildOptions{HelmCommand: "publish", SignOciChart: true, OciSigningKey: "cosign.key"}
+		helmExecutor := &mocks.HelmExecutor{}
+		helmExecutor.On("RunHelmPublish").Return("oci://"+host+"/charts/foo:1.0.0", nil)
+
+		err := runHelmBuild(config, helmExecutor, &fileHandlerMock{}, &helmBuildCommonPipelineEnvironment{}, &mock.ExecMockRunner{}, files, newHelmMockUtilsBundle())
+
+		require.NoError(t, err)
+		publicKey, _ := files.FileRead("cosign.pub")
+		key, err := docker.LoadPublicKey(publicKey)
+		require.NoError(t, err)
+		verified, err := docker.VerifyImageSignature(host+"/charts/foo:1.0.0", []crypto.PublicKey{key})
+		assert.NoError(t, err)
+		assert.Equal(t, host+"/charts/foo@"+digest, verified)
+	})
+
+	t.Run("error case - chart not in OCI registry", func(t *testing.T) {
+		config := helmBuildOptions{HelmCommand: "publish", SignOciChart: true, OciSigningKey: "cosign.key"}
+		helmExecutor := &mocks.HelmExecutor{}
+		helmExecutor.On("RunHelmPublish").Return("https://my.target.repository/foo-1.0.0.tgz", nil)
+
+		err := runHelmBuild(config, helmExecutor, &fileHandlerMock{}, &helmBuildCommonPipelineEnvironment{}, &mock.ExecMockRunner{}, files, newHelmMockUtilsBundle())
+
+		assert.EqualError(t, err, "signing of chart failed: only charts pushed to an OCI registry can be signed, please configure a targetRepositoryURL starting with 'oci://'")
+	})
+}
+
 func TestRunHelmPushSBOM(t *testing.T) {
 	setupConfigOpenFileMock(t)
 	// SBOM behaviour of the explicit "publish" command path in runHelmBuild.
@@ -1032,6 +1069,141 @@ func TestRunHelmPopulatesBuildSettingsInfo(t *testing.T) {
 	})
 }
 
+func TestRunHelmVerify(t *testing.T) {
+	setupConfigOpenFileMock(t)
+	compliantManifests := []byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\nspec:\n  template:\n    spec:\n      containers:\n        - name: app\n          resources:\n            limits:\n              cpu: 100m\n              memory: 64Mi\n")
+	removedAPIManifests := []byte("apiVersion: batch/v1beta1\nkind: CronJob\nmetadata:\n  name: cleanup\n")
+	policies := []string{"no-privileged-containers", "resource-limits"}
+
+	t.Run("success case (values files and Kubernetes versions)", func(t *testing.T) {
+		utils := newHelmMockUtilsBundle()
+		utils.AddFile("chart/values.schema.json", []byte("{}"))
+		cpe := helmBuildCommonPipelineEnvironment{}
+		helmExecutor := &mocks.HelmExecutor{}
+		for _, values := range [][]string{{"values-dev.yaml"}, {"values-prod.yaml"}} {
+			for _, kubeVersion := range []string{"1.24.0", "1.33.0"} {
+				helmExecutor.On("RunHelmTemplateFor", values, kubeVersion).Return(compliantManifests, nil).Once()
+			}
+		}
+		helmExecutor.On("RunHelmUnitTest", "helm-verification/TEST-helm-unittest.xml").Return(nil)
+		config := helmBuildOptions{
+			HelmCommand:             "verify",
+			ChartPath:               "chart",
+			VerificationValuesFiles: []string{"values-dev.yaml", "values-prod.yaml"},
+			KubeVersions:            []string{"1.24.0", "1.33.0"},
+			RequireValuesSchema:     true,
+			PolicyChecks:            policies,
+			RunChartUnitTests:       true,
+		}
+
+		err := runHelmBuild(config, helmExecutor, utils, &cpe, &mock.ExecMockRunner{}, utils.FilesMock, utils.HttpClientMock)
+
+		require.NoError(t, err)
+		helmExecutor.AssertExpectations(t)
+		assert.True(t, utils.HasFile("helm-verification/TEST-helm-verification.xml"))
+		assert.True(t, utils.HasFile("helm-verification/piper_helm_verification_report.html"))
+		content, err := utils.FileRead("helm-verification/piper_helm_verification_report.json")
+		require.NoError(t, err)
+		var results []map[string]interface{}
+		require.NoError(t, json.Unmarshal(content, &results))
+		// schema requirement, 4 scenarios with 3 checks each and the unit tests
+		assert.Len(t, results, 14)
+	})
+
+	t.Run("default flow with verifyChart", func(t *testing.T) {
+		utils := newHelmMockUtilsBundle()
+		cpe := helmBuildCommonPipelineEnvironment{}
+		helmExecutor := &mocks.HelmExecutor{}
+		helmExecutor.On("RunHelmLint").Return(nil)
+		helmExecutor.On("RunHelmTemplateFor", []string{}, "").Return(compliantManifests, nil)
+		config := helmBuildOptions{ChartPath: "chart", VerifyChart: true, PolicyChecks: policies}
+
+		err := runHelmBuild(config, helmExecutor, utils, &cpe, &mock.ExecMockRunner{}, utils.FilesMock, utils.HttpClientMock)
+
+		require.NoError(t, err)
+		helmExecutor.AssertExpectations(t)
+		assert.True(t, utils.HasFile("helm-verification/TEST-helm-verification.xml"))
+	})
+
+	t.Run("error case: verification issues", func(t *testing.T) {
+		utils := newHelmMockUtilsBundle()
+		cpe := helmBuildCommonPipelineEnvironment{}
+		helmExecutor := &mocks.HelmExecutor{}
+		helmExecutor.On("RunHelmTemplateFor", []string{}, "1.33.0").Return(removedAPIManifests, nil)
+		helmExecutor.On("RunHelmTemplateFor", []string{}, "1.20.0").Return(nil, errors.New("failed to execute helm template: exit status 1: Error: values don't meet the specifications of the schema(s) in the following chart(s)"))
+		helmExecutor.On("RunHelmUnitTest", "helm-verification/TEST-helm-unittest.xml").Return(errors.New("failed to execute helm unittest: exit status 1"))
+		config := helmBuildOptions{
+			HelmCommand:         "verify",
+			ChartPath:           "chart",
+			KubeVersions:        []string{"1.33.0", "1.20.0"},
+			RequireValuesSchema: true,
+			PolicyChecks:        policies,
+			RunChartUnitTests:   true,
+		}
+
+		err := runHelmBuild(config, helmExecutor, utils, &cpe, &mock.ExecMockRunner{}, utils.FilesMock, utils.HttpClientMock)
+
+		assert.EqualError(t, err, "chart verification failed with 4 error(s), see helm-verification for details")
+		content, err := utils.FileRead("helm-verification/TEST-helm-verification.xml")
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "batch/v1beta1 CronJob was removed in Kubernetes 1.25")
+		assert.Contains(t, string(content), "the chart does not provide a values.schema.json")
+		assert.Contains(t, string(content), `<testcase name="values-schema" classname="chart: default values (Kubernetes 1.20.0)">`)
+		assert.Contains(t, string(content), `<testcase name="unit-tests" classname="chart: default values">`)
+	})
+
+	t.Run("error case: unknown policy check", func(t *testing.T) {
+		utils := newHelmMockUtilsBundle()
+		cpe := helmBuildCommonPipelineEnvironment{}
+		helmExecutor := &mocks.HelmExecutor{}
+		helmExecutor.On("RunHelmTemplateFor", []string{}, "").Return(compliantManifests, nil)
+		config := helmBuildOptions{HelmCommand: "verify", ChartPath: "chart", PolicyChecks: []string{"no-host-network"}}
+
+		err := runHelmBuild(config, helmExecutor, utils, &cpe, &mock.ExecMockRunner{}, utils.FilesMock, utils.HttpClientMock)
+
+		assert.EqualError(t, err, "failed to check rendered manifests: unknown policy check 'no-host-network'")
+	})
+}
+
+func TestRunHelmDependencyMirrors(t *testing.T) {
+	setupConfigOpenFileMock(t)
+	config := helmBuildOptions{
+		HelmCommand:       "dependency",
+		Dependency:        "build",
+		ChartPath:         "chart",
+		DependencyMirrors: map[string]string{"https://charts.bitnami.com/bitnami": "https://nexus.example.com/repository/bitnami"},
+	}
+
+	t.Run("success case", func(t *testing.T) {
+		utils := newHelmMockUtilsBundle()
+		utils.AddFile("chart/Chart.yaml", []byte("apiVersion: v2\nname: app\nversion: 1.0.0\ndependencies:\n  - name: postgresql\n    version: 16.x.x\n    repository: https://charts.bitnami.com/bitnami\n"))
+		cpe := helmBuildCommonPipelineEnvironment{}
+		helmExecutor := &mocks.HelmExecutor{}
+		helmExecutor.On("RunHelmDependency").Return(nil).Run(func(testifymock.Arguments) {
+			content, _ := utils.FileRead("chart/Chart.yaml")
+			assert.Contains(t, string(content), "repository: https://nexus.example.com/repository/bitnami\n")
+		})
+
+		err := runHelmBuild(config, helmExecutor, utils, &cpe, &mock.ExecMockRunner{}, utils.FilesMock, utils.HttpClientMock)
+
+		require.NoError(t, err)
+		helmExecutor.AssertExpectations(t)
+		content, _ := utils.FileRead("chart/Chart.yaml")
+		assert.Contains(t, string(content), "repository: https://charts.bitnami.com/bitnami\n", "the original Chart.yaml must be restored")
+	})
+
+	t.Run("error case: missing Chart.yaml", func(t *testing.T) {
+		utils := newHelmMockUtilsBundle()
+		cpe := helmBuildCommonPipelineEnvironment{}
+		helmExecutor := &mocks.HelmExecutor{}
+
+		err := runHelmBuild(config, helmExecutor, utils, &cpe, &mock.ExecMockRunner{}, utils.FilesMock, utils.HttpClientMock)
+
+		assert.ErrorContains(t, err, "failed to mirror chart dependencies: failed to read chart/Chart.yaml")
+		helmExecutor.AssertNotCalled(t, "RunHelmDependency")
+	})
+}
+
 func TestParseAndRenderCPETemplate(t *testing.T) {
 	commonPipelineEnvironment := "commonPipelineEnvironment"
 	valuesYaml := []byte(`
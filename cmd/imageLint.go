@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	"github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/imagelint"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type imageLintUtils interface {
+	piperutils.FileUtils
+}
+
+type imageLintUtilsBundle struct {
+	*piperutils.Files
+}
+
+func newImageLintUtils() imageLintUtils {
+	return &imageLintUtilsBundle{Files: &piperutils.Files{}}
+}
+
+func imageLint(config imageLintOptions, telemetryData *telemetry.CustomData) {
+	utils := newImageLintUtils()
+
+	if err := runImageLint(&config, utils); err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runImageLint(config *imageLintOptions, utils imageLintUtils) error {
+	linter, err := imagelint.NewLinter(imagelint.Options{
+		MaxLayerSize:         int64(config.MaxLayerSize),
+		DisallowedBaseImages: config.DisallowedBaseImages,
+		ExcludeRules:         config.ExcludeRules,
+		ScanFiles:            config.ScanFiles,
+		MaxFileSize:          int64(config.MaxFileSize),
+	})
+	if err != nil {
+		return err
+	}
+
+	findings := []imagelint.Finding{}
+	for _, imageTarball := range config.ImageTarballs {
+		log.Entry().Infof("Inspecting image tarball '%v'", imageTarball)
+		image, err := tarball.ImageFromPath(imageTarball, nil)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("failed to read image tarball '%v': %w", imageTarball, err)
+		}
+		imageFindings, err := linter.Lint(imageTarball, image)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, imageFindings...)
+	}
+
+	images, err := imagesToLint(config)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	if len(images) > 0 {
+		opts, err := docker.RegistryOptions(context.Background(), config.DockerConfigJSON, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+		for _, image := range images {
+			log.Entry().Infof("Inspecting image '%v'", image)
+			img, err := remoteImage(image, opts...)
+			if err != nil {
+				return err
+			}
+			imageFindings, err := linter.Lint(image, img)
+			if err != nil {
+				return err
+			}
+			findings = append(findings, imageFindings...)
+		}
+	}
+
+	reports, err := imagelint.WriteReports(findings, config.DockerfilePath, utils)
+	if err != nil {
+		return err
+	}
+	piperutils.PersistReportsAndLinks("imageLint", "", utils, reports, nil)
+
+	if len(findings) == 0 {
+		log.Entry().Info("No findings detected")
+		return nil
+	}
+	failing := 0
+	for _, finding := range findings {
+		log.Entry().Warnf("[%v] %v: %v (%v)", finding.Severity, finding.Image, finding.Message, finding.RuleID)
+		if config.FailOnSeverity != "none" && imagelint.SeverityRank(finding.Severity) >= imagelint.SeverityRank(config.FailOnSeverity) {
+			failing++
+		}
+	}
+	if failing > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v finding(s) with severity %v or higher detected", failing, config.FailOnSeverity)
+	}
+	return nil
+}
+
+// imagesToLint returns the configured images or the images built in the pipeline, preferably referenced by digest
+func imagesToLint(config *imageLintOptions) ([]string, error) {
+	if len(config.Images) > 0 || len(config.ImageTarballs) > 0 {
+		return config.Images, nil
+	}
+	if len(config.ContainerImageNameTags) == 0 {
+		return nil, errors.New("no images to inspect, please configure images or imageTarballs")
+	}
+	return builtImageReferences(config.ContainerRegistryURL, config.ContainerImageNameTags, config.ContainerImageDigests)
+}
+
+func remoteImage(image string, opts ...remote.Option) (v1.Image, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image reference '%v': %w", image, err)
+	}
+	img, err := remote.Image(ref, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image '%v': %w", image, err)
+	}
+	return img, nil
+}
@@ -0,0 +1,427 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/eventing"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/spf13/cobra"
+)
+
+type imageLintOptions struct {
+	Images                 []string `json:"images,omitempty"`
+	ImageTarballs          []string `json:"imageTarballs,omitempty"`
+	ContainerRegistryURL   string   `json:"containerRegistryUrl,omitempty"`
+	ContainerImageNameTags []string `json:"containerImageNameTags,omitempty"`
+	ContainerImageDigests  []string `json:"containerImageDigests,omitempty"`
+	DockerConfigJSON       string   `json:"dockerConfigJSON,omitempty"`
+	DockerfilePath         string   `json:"dockerfilePath,omitempty"`
+	DisallowedBaseImages   []string `json:"disallowedBaseImages,omitempty"`
+	MaxLayerSize           int      `json:"maxLayerSize,omitempty"`
+	ScanFiles              bool     `json:"scanFiles,omitempty"`
+	MaxFileSize            int      `json:"maxFileSize,omitempty"`
+	ExcludeRules           []string `json:"excludeRules,omitempty"`
+	FailOnSeverity         string   `json:"failOnSeverity,omitempty" validate:"possible-values=high medium low none"`
+}
+
+type imageLintReports struct {
+}
+
+func (p *imageLintReports) persist(stepConfig imageLintOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/piper_imagelint_report.json", ParamRef: "", StepResultType: "imagelint"},
+		{FilePattern: "**/piper_imagelint.sarif", ParamRef: "", StepResultType: "imagelint"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, piperutils.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// ImageLintCommand Inspects container images for insecure configuration and secrets without running them
+func ImageLintCommand() *cobra.Command {
+	const STEP_NAME = "imageLint"
+
+	metadata := imageLintMetadata()
+	var stepConfig imageLintOptions
+	var startTime time.Time
+	var reports imageLintReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createImageLintCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Inspects container images for insecure configuration and secrets without running them",
+		Long: `This step analyzes the configuration and the layers of built container images offline, i.e. without running the image and without an external service.
+In contrast to ` + "`" + `hadolintExecute` + "`" + `, which lints the Dockerfile, and ` + "`" + `containerExecuteStructureTests` + "`" + `, which runs tests within the container, the resulting image itself is inspected.
+
+The following rules are checked:
+
+| Rule | Severity | Description |
+| ---- | -------- | ----------- |
+| ` + "`" + `root-user` + "`" + ` | medium | The image runs as root user. |
+| ` + "`" + `missing-healthcheck` + "`" + ` | low | The image does not define a health check. |
+| ` + "`" + `secret-in-env` + "`" + ` | high | An environment variable contains a secret. |
+| ` + "`" + `secret-in-label` + "`" + ` | high | A label contains a secret. |
+| ` + "`" + `secret-in-file` + "`" + ` | high | A file of a layer contains a secret, detected with the rules of ` + "`" + `secretScan` + "`" + `, or is a credentials file like ` + "`" + `id_rsa` + "`" + ` or ` + "`" + `.netrc` + "`" + `. |
+| ` + "`" + `large-layer` + "`" + ` | low | A layer exceeds the ` + "`" + `maxLayerSize` + "`" + `. |
+| ` + "`" + `disallowed-base-image` + "`" + ` | high | The base image matches one of the ` + "`" + `disallowedBaseImages` + "`" + `. |
+| ` + "`" + `unknown-base-image` + "`" + ` | medium | The base image cannot be checked against the ` + "`" + `disallowedBaseImages` + "`" + ` since it is unknown. |
+
+The base image is taken from the annotation or label ` + "`" + `org.opencontainers.image.base.name` + "`" + `, which is e.g. set by ` + "`" + `docker buildx build` + "`" + ` or can be added via ` + "`" + `LABEL` + "`" + ` in the Dockerfile.
+If ` + "`" + `disallowedBaseImages` + "`" + ` are configured, images without the annotation and label are reported as ` + "`" + `unknown-base-image` + "`" + `.
+
+By default the images built in the pipeline are inspected based on the ` + "`" + `commonPipelineEnvironment` + "`" + `. Alternatively images can be configured via ` + "`" + `images` + "`" + ` or provided as tarball via ` + "`" + `imageTarballs` + "`" + `.
+
+The findings are reported as JSON and SARIF file.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err = PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			// Set step error patterns for improved error detection
+			stepErrors := make([]log.StepError, len(metadata.Metadata.Errors))
+			for i, err := range metadata.Metadata.Errors {
+				stepErrors[i] = log.StepError{
+					Pattern:  err.Pattern,
+					Message:  err.Message,
+					Category: err.Category,
+				}
+			}
+			log.SetStepErrors(stepErrors)
+			log.RegisterSecret(stepConfig.DockerConfigJSON)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			vaultClient := config.GlobalVaultClient()
+			var oidcTokenProvider func(string) (string, error)
+			if vaultClient != nil {
+				defer vaultClient.MustRevokeToken()
+				oidcTokenProvider = vaultClient.GetOIDCTokenByValidation
+			}
+
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.LogStepTelemetryData()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.GCPPubSubConfig.ProjectNumber) > 0 {
+					if err := eventing.PublishTaskRunFinishedEvent(
+						oidcTokenProvider,
+						&GeneralConfig,
+						eventing.EventContext{
+							StepName:   STEP_NAME,
+							StageName:  telemetryClient.GetData().StageName,
+							ErrorCode:  stepTelemetryData.ErrorCode,
+							PipelineID: telemetryClient.GetBuildURL(),
+						},
+					); err != nil {
+						log.Entry().WithError(err).Warn("failed to publish GCP Pub/Sub event")
+					}
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(STEP_NAME)
+			imageLint(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addImageLintFlags(createImageLintCmd, &stepConfig)
+	return createImageLintCmd
+}
+
+func addImageLintFlags(cmd *cobra.Command, stepConfig *imageLintOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.Images, "images", []string{}, "Full references of the images which are inspected, e.g. `my.registry.com/my-image:1.0.0`. If neither `images` nor `imageTarballs` are set, the images of the `commonPipelineEnvironment` are inspected.")
+	cmd.Flags().StringSliceVar(&stepConfig.ImageTarballs, "imageTarballs", []string{}, "Paths to images saved as tarball, e.g. via `docker save` or `kanikoExecute` with `--tar-path`.")
+	cmd.Flags().StringVar(&stepConfig.ContainerRegistryURL, "containerRegistryUrl", os.Getenv("PIPER_containerRegistryUrl"), "http(s) url of the Container registry containing the images built in the pipeline.")
+	cmd.Flags().StringSliceVar(&stepConfig.ContainerImageNameTags, "containerImageNameTags", []string{}, "Names and tags of the images built in the pipeline.")
+	cmd.Flags().StringSliceVar(&stepConfig.ContainerImageDigests, "containerImageDigests", []string{}, "Digests of the images built in the pipeline.")
+	cmd.Flags().StringVar(&stepConfig.DockerConfigJSON, "dockerConfigJSON", os.Getenv("PIPER_dockerConfigJSON"), "Path to the file `.docker/config.json` - this is typically provided by your CI/CD system. You can find more details about the Docker credentials in the [Docker documentation](https://docs.docker.com/engine/reference/commandline/login/).")
+	cmd.Flags().StringVar(&stepConfig.DockerfilePath, "dockerfilePath", `Dockerfile`, "Path to the Dockerfile the images are built from. Findings in the image configuration are reported for this file in the SARIF report.")
+	cmd.Flags().StringSliceVar(&stepConfig.DisallowedBaseImages, "disallowedBaseImages", []string{}, "Glob patterns of base images which must not be used, e.g. `docker.io/library/centos:*`.")
+	cmd.Flags().IntVar(&stepConfig.MaxLayerSize, "maxLayerSize", 524288000, "Maximal compressed size of a layer in bytes. `0` disables the check.")
+	cmd.Flags().BoolVar(&stepConfig.ScanFiles, "scanFiles", true, "Whether the files of all layers are scanned for secrets. Files deleted in a later layer are scanned as well since they are still part of the image.")
+	cmd.Flags().IntVar(&stepConfig.MaxFileSize, "maxFileSize", 1048576, "Maximal size of a file in bytes to be scanned for secrets, larger files are skipped. With `0` all files are scanned, line by line.")
+	cmd.Flags().StringSliceVar(&stepConfig.ExcludeRules, "excludeRules", []string{}, "IDs of rules which are not checked, e.g. `missing-healthcheck`.")
+	cmd.Flags().StringVar(&stepConfig.FailOnSeverity, "failOnSeverity", `high`, "The step fails if a finding of this or a higher severity is detected. With `none` the step never fails because of findings.")
+
+}
+
+// retrieve step metadata
+func imageLintMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "imageLint",
+			Aliases:     []config.Alias{},
+			Description: "Inspects container images for insecure configuration and secrets without running them",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "dockerConfigJsonCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing Docker config.json (with registry credential(s)).", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:        "images",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "imageTarballs",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name: "containerRegistryUrl",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/registryUrl",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "dockerRegistryUrl"}},
+						Default:   os.Getenv("PIPER_containerRegistryUrl"),
+					},
+					{
+						Name: "containerImageNameTags",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/imageNameTags",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   []string{},
+					},
+					{
+						Name: "containerImageDigests",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/imageDigests",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   []string{},
+					},
+					{
+						Name: "dockerConfigJSON",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/dockerConfigJSON",
+							},
+
+							{
+								Name: "dockerConfigJsonCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "dockerConfigFileVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "docker-config",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_dockerConfigJSON"),
+					},
+					{
+						Name:        "dockerfilePath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `Dockerfile`,
+					},
+					{
+						Name:        "disallowedBaseImages",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "maxLayerSize",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     524288000,
+					},
+					{
+						Name:        "scanFiles",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "maxFileSize",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     1048576,
+					},
+					{
+						Name:        "excludeRules",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "failOnSeverity",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `high`,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/piper_imagelint_report.json", "type": "imagelint"},
+							{"filePattern": "**/piper_imagelint.sarif", "type": "imagelint"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
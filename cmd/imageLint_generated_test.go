@@ -0,0 +1,19 @@
+//go:build unit
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageLintCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := ImageLintCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "imageLint", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,95 @@
+//go:build unit
+
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+type imageLintMockUtils struct {
+	*mock.FilesMock
+}
+
+func newImageLintTestImage(t *testing.T, config v1.Config) v1.Image {
+	img, err := random.Image(256, 1)
+	require.NoError(t, err)
+	img, err = mutate.Config(img, config)
+	require.NoError(t, err)
+	return img
+}
+
+func TestRunImageLint(t *testing.T) {
+	t.Parallel()
+	host := newSigningTestRegistry(t)
+	compliant := newImageLintTestImage(t, v1.Config{User: "app", Healthcheck: &v1.HealthConfig{Test: []string{"CMD", "true"}}})
+	ref, _ := name.ParseReference(host + "/app:1.0.0")
+	require.NoError(t, remote.Write(ref, compliant))
+	digest, _ := compliant.Digest()
+
+	insecureTarball := filepath.Join(t.TempDir(), "insecure.tar")
+	insecure := newImageLintTestImage(t, v1.Config{Env: []string{"DB_PASSWORD=s3cr3t-value"}})
+	require.NoError(t, tarball.WriteToFile(insecureTarball, name.MustParseReference("insecure:1.0.0"), insecure))
+
+	t.Run("images of commonPipelineEnvironment", func(t *testing.T) {
+		t.Parallel()
+		utils := imageLintMockUtils{FilesMock: &mock.FilesMock{}}
+		config := imageLintOptions{
+			ContainerRegistryURL:   "http://" + host,
+			ContainerImageNameTags: []string{"app:1.0.0"},
+			ContainerImageDigests:  []string{digest.String()},
+			DockerfilePath:         "Dockerfile",
+			FailOnSeverity:         "high",
+		}
+
+		err := runImageLint(&config, utils)
+
+		assert.NoError(t, err)
+		assert.True(t, utils.HasFile("imagelint/piper_imagelint.sarif"))
+	})
+
+	t.Run("image tarball with findings", func(t *testing.T) {
+		t.Parallel()
+		utils := imageLintMockUtils{FilesMock: &mock.FilesMock{}}
+		config := imageLintOptions{ImageTarballs: []string{insecureTarball}, DockerfilePath: "Dockerfile", FailOnSeverity: "high"}
+
+		err := runImageLint(&config, utils)
+
+		assert.EqualError(t, err, "1 finding(s) with severity high or higher detected")
+		report, err := utils.FileRead("imagelint/piper_imagelint_report.json")
+		require.NoError(t, err)
+		assert.Contains(t, string(report), `"ruleId": "secret-in-env"`)
+		assert.Contains(t, string(report), `"ruleId": "root-user"`)
+		assert.NotContains(t, string(report), "s3cr3t-value")
+	})
+
+	t.Run("findings below severity", func(t *testing.T) {
+		t.Parallel()
+		utils := imageLintMockUtils{FilesMock: &mock.FilesMock{}}
+		config := imageLintOptions{ImageTarballs: []string{insecureTarball}, ExcludeRules: []string{"secret-in-env"}, FailOnSeverity: "high"}
+		assert.NoError(t, runImageLint(&config, utils))
+
+		config.FailOnSeverity = "low"
+		assert.EqualError(t, runImageLint(&config, utils), "2 finding(s) with severity low or higher detected")
+
+		config.FailOnSeverity = "none"
+		assert.NoError(t, runImageLint(&config, utils))
+	})
+
+	t.Run("no images", func(t *testing.T) {
+		t.Parallel()
+		err := runImageLint(&imageLintOptions{}, imageLintMockUtils{FilesMock: &mock.FilesMock{}})
+		assert.EqualError(t, err, "no images to inspect, please configure images or imageTarballs")
+	})
+}
@@ -4,16 +4,20 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
 	"errors"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/docker"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/multiarch"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 )
@@ -24,8 +28,14 @@ const (
 
 type dockerImageUtils interface {
 	LoadImage(ctx context.Context, src string) (v1.Image, error)
+	PullImage(ctx context.Context, src, platform string) (v1.Image, error)
 	PushImage(ctx context.Context, im v1.Image, dest, platform string) error
+	PushImageIndex(ctx context.Context, index v1.ImageIndex, dest string) error
+	GetImageIndex(ctx context.Context, src string) (v1.ImageIndex, error)
 	CopyImage(ctx context.Context, src, dest, platform string) error
+	GetImageDigest(ctx context.Context, src string) (string, error)
+	ListTags(ctx context.Context, repository string) ([]string, error)
+	DeleteImage(ctx context.Context, src string) error
 }
 
 type imagePushToRegistryUtils interface {
@@ -66,7 +76,7 @@ func newImagePushToRegistryUtils(disableHTTP2 bool) imagePushToRegistryUtils {
 	return &utils
 }
 
-func imagePushToRegistry(config imagePushToRegistryOptions, telemetryData *telemetry.CustomData) {
+func imagePushToRegistry(config imagePushToRegistryOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *imagePushToRegistryCommonPipelineEnvironment) {
 	// Utils can be used wherever the command.ExecRunner interface is expected.
 	// It can also be used for example as a mavenExecRunner.
 	utils := newImagePushToRegistryUtils(config.DisableHTTP2)
@@ -77,13 +87,13 @@ func imagePushToRegistry(config imagePushToRegistryOptions, telemetryData *telem
 
 	// Error situations should be bubbled up until they reach the line below which will then stop execution
 	// through the log.Entry().Fatal() call leading to an os.Exit(1) in the end.
-	err := runImagePushToRegistry(&config, telemetryData, utils)
+	err := runImagePushToRegistry(&config, telemetryData, commonPipelineEnvironment, utils)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
 }
 
-func runImagePushToRegistry(config *imagePushToRegistryOptions, telemetryData *telemetry.CustomData, utils imagePushToRegistryUtils) error {
+func runImagePushToRegistry(config *imagePushToRegistryOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *imagePushToRegistryCommonPipelineEnvironment, utils imagePushToRegistryUtils) error {
 	if !config.PushLocalDockerImage && !config.UseImageNameTags {
 		if len(config.TargetImages) == 0 {
 			config.TargetImages = mapSourceTargetImages(config.SourceImages)
@@ -94,6 +104,28 @@ func runImagePushToRegistry(config *imagePushToRegistryOptions, telemetryData *t
 		}
 	}
 
+	if len(config.TargetArchitectures) > 0 {
+		if config.PushLocalDockerImage || config.UseImageNameTags {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.New("configuration error: targetArchitectures cannot be combined with pushLocalDockerImage or useImageNameTags")
+		}
+		if len(config.TargetArchitectures) != len(config.PlatformImages) {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.New("configuration error: please configure one of platformImages for each of targetArchitectures")
+		}
+	}
+
+	if config.PromoteByDigest {
+		if config.PushLocalDockerImage || config.UseImageNameTags || len(config.TargetArchitectures) > 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.New("configuration error: promoteByDigest cannot be combined with pushLocalDockerImage, useImageNameTags or targetArchitectures")
+		}
+		if len(config.SourceImageDigests) != len(config.SourceImageNameTags) {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.New("configuration error: please configure one of sourceImageDigests for each of sourceImageNameTags")
+		}
+	}
+
 	if config.UseImageNameTags {
 		if len(config.TargetImageNameTags) > 0 && len(config.TargetImageNameTags) != len(config.SourceImageNameTags) {
 			log.SetErrorCategory(log.ErrorConfiguration)
@@ -117,7 +149,7 @@ func runImagePushToRegistry(config *imagePushToRegistryOptions, telemetryData *t
 		if err := pushLocalImageToTargetRegistry(config, utils); err != nil {
 			return fmt.Errorf("failed to push local image to %q: %w", config.TargetRegistryURL, err)
 		}
-		return nil
+		return completePush(config, utils)
 	}
 
 	log.Entry().Debug("Handling source registry credentials")
@@ -125,20 +157,97 @@ func runImagePushToRegistry(config *imagePushToRegistryOptions, telemetryData *t
 		return fmt.Errorf("failed to handle credentials for source registry: %w", err)
 	}
 
+	if config.PromoteByDigest {
+		if err := promoteImages(config, commonPipelineEnvironment, utils); err != nil {
+			return fmt.Errorf("failed to promote images: %w", err)
+		}
+		return completePush(config, utils)
+	}
+
+	if len(config.TargetArchitectures) > 0 {
+		if err := pushMultiPlatformImage(config, utils); err != nil {
+			return fmt.Errorf("failed to push multi-platform image: %w", err)
+		}
+		return completePush(config, utils)
+	}
+
 	if config.UseImageNameTags {
 		if err := pushImageNameTagsToTargetRegistry(config, utils); err != nil {
 			return fmt.Errorf("failed to push imageNameTags to target registry: %w", err)
 		}
-		return nil
+		return completePush(config, utils)
 	}
 
 	if err := copyImages(config, utils); err != nil {
 		return fmt.Errorf("failed to copy images: %w", err)
 	}
 
+	return completePush(config, utils)
+}
+
+// completePush signs the pushed images and applies the retention policy to the target repositories
+func completePush(config *imagePushToRegistryOptions, utils imagePushToRegistryUtils) error {
+	if err := signPushedImages(config, utils); err != nil {
+		return err
+	}
+	if err := applyRetentionPolicy(config, utils); err != nil {
+		return fmt.Errorf("failed to apply retention policy: %w", err)
+	}
+	return nil
+}
+
+// signPushedImages signs the images in the target registry if configured
+func signPushedImages(config *imagePushToRegistryOptions, utils imagePushToRegistryUtils) error {
+	if !config.SignImages {
+		return nil
+	}
+	if err := signContainerImages(targetImageReferences(config), config.SigningKey, config.SigningKeyPassword, targetDockerConfigPath, utils); err != nil {
+		return fmt.Errorf("failed to sign images: %w", err)
+	}
 	return nil
 }
 
+// targetImageReferences returns the images pushed to the target registry
+func targetImageReferences(config *imagePushToRegistryOptions) []string {
+	images := []string{}
+	switch {
+	case config.PromoteByDigest:
+		for _, sourceImage := range config.SourceImages {
+			for _, tag := range promotionTags(config) {
+				images = append(images, fmt.Sprintf("%s/%s:%s", config.TargetRegistryURL, config.TargetImages[sourceImage], tag))
+			}
+		}
+	case config.UseImageNameTags && !config.PushLocalDockerImage:
+		for i, sourceImageNameTag := range config.SourceImageNameTags {
+			if len(config.TargetImageNameTags) == 0 {
+				images = append(images, fmt.Sprintf("%s/%s", config.TargetRegistryURL, sourceImageNameTag))
+			} else {
+				images = append(images, fmt.Sprintf("%s/%s", config.TargetRegistryURL, config.TargetImageNameTags[i]))
+			}
+		}
+	default:
+		targetImages := []string{}
+		if config.PushLocalDockerImage {
+			for _, targetImage := range config.TargetImages {
+				targetImages = append(targetImages, targetImage)
+			}
+			sort.Strings(targetImages)
+		} else {
+			for _, sourceImage := range config.SourceImages {
+				targetImages = append(targetImages, config.TargetImages[sourceImage])
+			}
+		}
+		for _, targetImage := range targetImages {
+			if config.TargetImageTag != "" {
+				images = append(images, fmt.Sprintf("%s/%s:%s", config.TargetRegistryURL, targetImage, config.TargetImageTag))
+			} else if config.TagLatest {
+				images = append(images, fmt.Sprintf("%s/%s", config.TargetRegistryURL, targetImage))
+			}
+		}
+	}
+	return images
+}
+
 func handleCredentialsForPrivateRegistry(dockerConfigJsonPath, registry, username, password string, utils imagePushToRegistryUtils) error {
 	if len(dockerConfigJsonPath) == 0 {
 		if len(registry) == 0 || len(username) == 0 || len(password) == 0 {
@@ -286,6 +395,218 @@ func pushImageNameTagsToTargetRegistry(config *imagePushToRegistryOptions, utils
 	return nil
 }
 
+// pushMultiPlatformImage assembles the images of the platforms into an image index and pushes it to the target images.
+// Each push is verified to contain all platforms.
+func pushMultiPlatformImage(config *imagePushToRegistryOptions, utils imagePushToRegistryUtils) error {
+	ctx := context.Background()
+	platforms, err := multiarch.ParsePlatformStrings(config.TargetArchitectures)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	images := []docker.PlatformImage{}
+	for i, platform := range platforms {
+		img, err := loadPlatformImage(ctx, config.PlatformImages[i], platform.ToString(), config.SourceRegistryURL, utils)
+		if err != nil {
+			return fmt.Errorf("failed to load image for platform %v: %w", platform.ToString(), err)
+		}
+		images = append(images, docker.PlatformImage{
+			Platform: v1.Platform{OS: platform.OS, Architecture: platform.Arch, Variant: platform.Variant},
+			Image:    img,
+		})
+	}
+	index, err := docker.NewImageIndex(images)
+	if err != nil {
+		return err
+	}
+
+	targets := targetImageReferences(config)
+	if len(targets) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.New("no target images configured, please configure targetImageTag or tagLatest")
+	}
+	for _, dst := range targets {
+		log.Entry().Infof("Pushing image index with %v platform(s) to %s...", len(images), dst)
+		if err := utils.PushImageIndex(ctx, index, dst); err != nil {
+			return err
+		}
+		pushed, err := utils.GetImageIndex(ctx, dst)
+		if err != nil {
+			return fmt.Errorf("failed to read pushed image index %s: %w", dst, err)
+		}
+		if err := docker.VerifyImageIndex(index, pushed); err != nil {
+			return fmt.Errorf("verification of %s failed: %w", dst, err)
+		}
+		log.Entry().Infof("Pushing image index with %v platform(s) to %s... Done", len(images), dst)
+	}
+	return nil
+}
+
+// loadPlatformImage reads the image of a platform from a tarball or from the source registry
+func loadPlatformImage(ctx context.Context, src, platform, sourceRegistryURL string, utils imagePushToRegistryUtils) (v1.Image, error) {
+	if exists, _ := utils.FileExists(src); exists {
+		log.Entry().Infof("Loading image %s for platform %s...", src, platform)
+		return utils.LoadImage(ctx, src)
+	}
+	if len(sourceRegistryURL) > 0 {
+		src = fmt.Sprintf("%s/%s", sourceRegistryURL, src)
+	}
+	log.Entry().Infof("Pulling image %s for platform %s...", src, platform)
+	return utils.PullImage(ctx, src, platform)
+}
+
+// promoteImages copies the source images by digest to the target images and verifies that all tags point to the promoted digest
+func promoteImages(config *imagePushToRegistryOptions, commonPipelineEnvironment *imagePushToRegistryCommonPipelineEnvironment, utils imagePushToRegistryUtils) error {
+	ctx := context.Background()
+	tags := promotionTags(config)
+	if len(tags) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.New("no target tags configured, please configure targetImageTag, environmentTags or tagLatest")
+	}
+
+	for _, sourceImage := range config.SourceImages {
+		digest, err := sourceImageDigest(config, sourceImage)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+		src := fmt.Sprintf("%s/%s@%s", config.SourceRegistryURL, sourceImage, digest)
+		targetImage := config.TargetImages[sourceImage]
+		for _, tag := range tags {
+			dst := fmt.Sprintf("%s/%s:%s", config.TargetRegistryURL, targetImage, tag)
+			log.Entry().Infof("Promoting %s to %s...", src, dst)
+			if err := utils.CopyImage(ctx, src, dst, ""); err != nil {
+				return err
+			}
+			pushedDigest, err := utils.GetImageDigest(ctx, dst)
+			if err != nil {
+				return fmt.Errorf("failed to resolve digest of %s: %w", dst, err)
+			}
+			if pushedDigest != digest {
+				return fmt.Errorf("%s points to %s instead of the promoted digest %s", dst, pushedDigest, digest)
+			}
+			log.Entry().Infof("Promoting %s to %s... Done", src, dst)
+		}
+		commonPipelineEnvironment.container.promotedImages = append(commonPipelineEnvironment.container.promotedImages, fmt.Sprintf("%s/%s@%s", config.TargetRegistryURL, targetImage, digest))
+	}
+	return nil
+}
+
+// promotionTags returns the tags of the promoted images in the target registry
+func promotionTags(config *imagePushToRegistryOptions) []string {
+	tags := []string{}
+	if len(config.TargetImageTag) > 0 {
+		tags = append(tags, config.TargetImageTag)
+	}
+	tags = append(tags, config.EnvironmentTags...)
+	if config.TagLatest {
+		tags = append(tags, "latest")
+	}
+	unique := []string{}
+	for _, tag := range tags {
+		if !slices.Contains(unique, tag) {
+			unique = append(unique, tag)
+		}
+	}
+	return unique
+}
+
+// sourceImageDigest looks up the digest of the source image in the images recorded by the build
+func sourceImageDigest(config *imagePushToRegistryOptions, sourceImage string) (string, error) {
+	for i, imageNameTag := range config.SourceImageNameTags {
+		imageNameTag = strings.TrimPrefix(imageNameTag, config.SourceRegistryURL+"/")
+		imageName, tag := imageNameTag, ""
+		if j := strings.LastIndex(imageNameTag, ":"); j > strings.LastIndex(imageNameTag, "/") {
+			imageName, tag = imageNameTag[:j], imageNameTag[j+1:]
+		}
+		if imageName == sourceImage && (len(config.SourceImageTag) == 0 || tag == config.SourceImageTag) {
+			return config.SourceImageDigests[i], nil
+		}
+	}
+	return "", fmt.Errorf("no digest found for image %v, please provide sourceImageNameTags and sourceImageDigests e.g. via the commonPipelineEnvironment of the build step", sourceImage)
+}
+
+// applyRetentionPolicy deletes the version tags exceeding retentionKeepVersions from the repositories of the target images.
+// Manifests are deleted by digest, hence versions sharing the digest of a kept tag are skipped.
+// The cosign signatures, attestations and SBOMs stored for a deleted digest are deleted as well.
+func applyRetentionPolicy(config *imagePushToRegistryOptions, utils imagePushToRegistryUtils) error {
+	if config.RetentionKeepVersions <= 0 {
+		return nil
+	}
+	ctx := context.Background()
+
+	repositories := []string{}
+	protectedTags := map[string][]string{}
+	for _, image := range targetImageReferences(config) {
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			return fmt.Errorf("invalid image reference %q: %w", image, err)
+		}
+		repository := ref.Context().Name()
+		if _, ok := protectedTags[repository]; !ok {
+			repositories = append(repositories, repository)
+		}
+		protectedTags[repository] = append(protectedTags[repository], ref.Identifier())
+	}
+
+	for _, repository := range repositories {
+		tags, err := utils.ListTags(ctx, repository)
+		if err != nil {
+			return fmt.Errorf("failed to list tags of %s: %w", repository, err)
+		}
+		prune := docker.VersionTagsToPrune(tags, config.RetentionKeepVersions, append(protectedTags[repository], config.EnvironmentTags...))
+		if len(prune) == 0 {
+			continue
+		}
+
+		keptDigests := map[string]bool{}
+		for _, tag := range tags {
+			if slices.Contains(prune, tag) {
+				continue
+			}
+			digest, err := utils.GetImageDigest(ctx, fmt.Sprintf("%s:%s", repository, tag))
+			if err != nil {
+				return fmt.Errorf("failed to resolve digest of %s:%s: %w", repository, tag, err)
+			}
+			keptDigests[digest] = true
+		}
+
+		for _, tag := range prune {
+			digest, err := utils.GetImageDigest(ctx, fmt.Sprintf("%s:%s", repository, tag))
+			if err != nil {
+				return fmt.Errorf("failed to resolve digest of %s:%s: %w", repository, tag, err)
+			}
+			if keptDigests[digest] {
+				log.Entry().Infof("Keeping %s:%s since its digest %s is still referenced by another tag", repository, tag, digest)
+				continue
+			}
+			log.Entry().Infof("Deleting %s:%s (%s)...", repository, tag, digest)
+			if err := utils.DeleteImage(ctx, fmt.Sprintf("%s@%s", repository, digest)); err != nil {
+				return fmt.Errorf("failed to delete %s:%s: %w", repository, tag, err)
+			}
+			keptDigests[digest] = true
+			log.Entry().Infof("Deleting %s:%s (%s)... Done", repository, tag, digest)
+
+			// signatures and attestations of the deleted image would be orphaned otherwise
+			for _, signatureTag := range docker.SignatureTags(digest) {
+				if !slices.Contains(tags, signatureTag) {
+					continue
+				}
+				signatureDigest, err := utils.GetImageDigest(ctx, fmt.Sprintf("%s:%s", repository, signatureTag))
+				if err != nil {
+					return fmt.Errorf("failed to resolve digest of %s:%s: %w", repository, signatureTag, err)
+				}
+				log.Entry().Infof("Deleting %s:%s (%s) of the deleted image", repository, signatureTag, signatureDigest)
+				if err := utils.DeleteImage(ctx, fmt.Sprintf("%s@%s", repository, signatureDigest)); err != nil {
+					return fmt.Errorf("failed to delete %s:%s: %w", repository, signatureTag, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func mapSourceTargetImages(sourceImages []string) map[string]string {
 	targetImages := make(map[string]string, len(sourceImages))
 
@@ -5,11 +5,13 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/eventing"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/validation"
@@ -35,7 +37,44 @@ type imagePushToRegistryOptions struct {
 	PushLocalDockerImage   bool              `json:"pushLocalDockerImage,omitempty"`
 	LocalDockerImagePath   string            `json:"localDockerImagePath,omitempty" validate:"required_if=PushLocalDockerImage true"`
 	TargetArchitecture     string            `json:"targetArchitecture,omitempty"`
+	TargetArchitectures    []string          `json:"targetArchitectures,omitempty"`
+	PlatformImages         []string          `json:"platformImages,omitempty"`
+	PromoteByDigest        bool              `json:"promoteByDigest,omitempty"`
+	SourceImageDigests     []string          `json:"sourceImageDigests,omitempty"`
+	EnvironmentTags        []string          `json:"environmentTags,omitempty"`
+	RetentionKeepVersions  int               `json:"retentionKeepVersions,omitempty"`
 	DisableHTTP2           bool              `json:"disableHTTP2,omitempty"`
+	SignImages             bool              `json:"signImages,omitempty"`
+	SigningKey             string            `json:"signingKey,omitempty"`
+	SigningKeyPassword     string            `json:"signingKeyPassword,omitempty"`
+}
+
+type imagePushToRegistryCommonPipelineEnvironment struct {
+	container struct {
+		promotedImages []string
+	}
+}
+
+func (p *imagePushToRegistryCommonPipelineEnvironment) persist(path, resourceName string) {
+	content := []struct {
+		category string
+		name     string
+		value    interface{}
+	}{
+		{category: "container", name: "promotedImages", value: p.container.promotedImages},
+	}
+
+	errCount := 0
+	for _, param := range content {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(param.category, param.name), param.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting piper environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Error("failed to persist Piper environment")
+	}
 }
 
 // ImagePushToRegistryCommand Allows you to copy a Docker image from a source container registry  to a destination container registry.
@@ -45,6 +84,7 @@ func ImagePushToRegistryCommand() *cobra.Command {
 	metadata := imagePushToRegistryMetadata()
 	var stepConfig imagePushToRegistryOptions
 	var startTime time.Time
+	var commonPipelineEnvironment imagePushToRegistryCommonPipelineEnvironment
 	var logCollector *log.CollectorHook
 	var splunkClient *splunk.Splunk
 	telemetryClient := &telemetry.Telemetry{}
@@ -92,6 +132,8 @@ Currently the imagePushToRegistry only supports copying a local image or image f
 			log.RegisterSecret(stepConfig.TargetRegistryUser)
 			log.RegisterSecret(stepConfig.TargetRegistryPassword)
 			log.RegisterSecret(stepConfig.DockerConfigJSON)
+			log.RegisterSecret(stepConfig.SigningKey)
+			log.RegisterSecret(stepConfig.SigningKeyPassword)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -130,6 +172,7 @@ Currently the imagePushToRegistry only supports copying a local image or image f
 			stepTelemetryData := telemetry.CustomData{}
 			stepTelemetryData.ErrorCode = "1"
 			handler := func() {
+				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 				config.RemoveVaultSecretFiles()
 				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
 				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
@@ -170,7 +213,7 @@ Currently the imagePushToRegistry only supports copying a local image or image f
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(STEP_NAME)
-			imagePushToRegistry(stepConfig, &stepTelemetryData)
+			imagePushToRegistry(stepConfig, &stepTelemetryData, &commonPipelineEnvironment)
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
 		},
@@ -199,7 +242,16 @@ func addImagePushToRegistryFlags(cmd *cobra.Command, stepConfig *imagePushToRegi
 	cmd.Flags().BoolVar(&stepConfig.PushLocalDockerImage, "pushLocalDockerImage", false, "Defines if the local image should be pushed to registry")
 	cmd.Flags().StringVar(&stepConfig.LocalDockerImagePath, "localDockerImagePath", os.Getenv("PIPER_localDockerImagePath"), "If the `localDockerImagePath` is a directory, it will be read as an OCI image layout. Otherwise, `localDockerImagePath` is assumed to be a docker-style tarball.")
 	cmd.Flags().StringVar(&stepConfig.TargetArchitecture, "targetArchitecture", os.Getenv("PIPER_targetArchitecture"), "Specifies the targetArchitecture in the form os/arch[/variant][:osversion] (e.g. linux/amd64). All OS and architectures of the specified image will be copied if it is a multi-platform image. To only push a single platform to the target registry use this parameter")
+	cmd.Flags().StringSliceVar(&stepConfig.TargetArchitectures, "targetArchitectures", []string{}, "Platforms of a multi-platform image in the form os/arch[/variant] (e.g. linux/amd64). The images of the individual platforms are configured in `platformImages` in the same order.\nThe images are assembled into an OCI image index (a Docker manifest list in case of Docker images) which is pushed to the `targetImages` in the target registry. After pushing it is verified that the image index contains all platforms.\n\n```yaml\nsourceImages:\n  - my-app\ntargetArchitectures:\n  - linux/amd64\n  - linux/arm64\nplatformImages:\n  - my-app:1.0.0-amd64\n  - images/my-app-arm64.tar\n```\n")
+	cmd.Flags().StringSliceVar(&stepConfig.PlatformImages, "platformImages", []string{}, "Images of the individual platforms of a multi-platform image, see `targetArchitectures`. An image is either a path to a docker-style tarball or the name and tag of an image in the `sourceRegistryUrl`. If a source image is a multi-platform image itself, the image of the corresponding platform is used.")
+	cmd.Flags().BoolVar(&stepConfig.PromoteByDigest, "promoteByDigest", false, "Promotes the already tested images from the source registry to the target registry by digest instead of by tag.\nThe digests of the `sourceImages` are taken from `sourceImageDigests` which are written to the commonPipelineEnvironment by the build step, e.g. kanikoExecute or cnbBuild.\nThe images are tagged in the target registry with `targetImageTag`, the `environmentTags` and `latest` if `tagLatest` is set. After copying it is verified that all tags point to the promoted digest.\nThe promoted images are recorded in the commonPipelineEnvironment as `container/promotedImages`.\n")
+	cmd.Flags().StringSliceVar(&stepConfig.SourceImageDigests, "sourceImageDigests", []string{}, "Digests of the `sourceImageNameTags`, in the same order. Used in combination with `promoteByDigest`.")
+	cmd.Flags().StringSliceVar(&stepConfig.EnvironmentTags, "environmentTags", []string{}, "Additional tags of the promoted images in the target registry which denote the environment, e.g. `qa` or `prod`. Used in combination with `promoteByDigest`.")
+	cmd.Flags().IntVar(&stepConfig.RetentionKeepVersions, "retentionKeepVersions", 0, "Number of versions to keep in the repositories of the target images. Older version tags are deleted from the target registry after the images have been pushed.\nOnly tags which are versions are considered, tags like `latest` or the `environmentTags` are never deleted, neither are versions pointing to the same digest as a kept tag.\nThe cosign signatures, attestations and SBOMs of a deleted image are deleted together with it.\nThe target registry has to support the deletion of manifests. `0` disables the retention policy.\n")
 	cmd.Flags().BoolVar(&stepConfig.DisableHTTP2, "disableHTTP2", false, "Disables HTTP/2 for registry communication. Set to true if you encounter HTTP/2 stream errors during image push/pull operations.")
+	cmd.Flags().BoolVar(&stepConfig.SignImages, "signImages", false, "Signs the pushed images with the `signingKey`. The signatures are stored next to the images in the format of [cosign](https://github.com/sigstore/cosign) and can be verified with the step `imageVerifySignature`.")
+	cmd.Flags().StringVar(&stepConfig.SigningKey, "signingKey", os.Getenv("PIPER_signingKey"), "Path to the PEM encoded private key used for signing images, e.g. a key pair created with `cosign generate-key-pair`.")
+	cmd.Flags().StringVar(&stepConfig.SigningKeyPassword, "signingKeyPassword", os.Getenv("PIPER_signingKeyPassword"), "Password of the encrypted `signingKey`.")
 
 	cmd.MarkFlagRequired("targetRegistryUrl")
 	cmd.MarkFlagRequired("targetRegistryUser")
@@ -216,6 +268,10 @@ func imagePushToRegistryMetadata() config.StepData {
 		},
 		Spec: config.StepSpec{
 			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "signingKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the private key used for signing images.", Type: "jenkins"},
+					{Name: "signingKeyPasswordCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the password of the private key used for signing images.", Type: "jenkins"},
+				},
 				Resources: []config.StepResources{
 					{Name: "source", Type: "stash"},
 				},
@@ -447,6 +503,65 @@ func imagePushToRegistryMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_targetArchitecture"),
 					},
+					{
+						Name:        "targetArchitectures",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "platformImages",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "promoteByDigest",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "sourceImageDigests",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "container/imageDigests",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   []string{},
+					},
+					{
+						Name:        "environmentTags",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "retentionKeepVersions",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     0,
+					},
 					{
 						Name:        "disableHTTP2",
 						ResourceRef: []config.ResourceReference{},
@@ -456,11 +571,71 @@ func imagePushToRegistryMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "signImages",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "signingKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "signingKeyFileVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "image-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_signingKey"),
+					},
+					{
+						Name: "signingKeyPassword",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyPasswordCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "signingKeyPasswordVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "image-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_signingKeyPassword"),
+					},
 				},
 			},
 			Containers: []config.Container{
 				{Image: "gcr.io/go-containerregistry/crane:debug", EnvVars: []config.EnvVar{{Name: "container", Value: "docker"}}, Options: []config.Option{{Name: "-u", Value: "0"}, {Name: "--entrypoint", Value: ""}}},
 			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "commonPipelineEnvironment",
+						Type: "piperEnvironment",
+						Parameters: []map[string]interface{}{
+							{"name": "container/promotedImages", "type": "[]string"},
+						},
+					},
+				},
+			},
 		},
 	}
 	return theMetaData
@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	dockermock "github.com/SAP/jenkins-library/pkg/docker/mock"
 	"github.com/SAP/jenkins-library/pkg/mock"
@@ -62,7 +68,7 @@ func TestRunImagePushToRegistry(t *testing.T) {
 		}
 		craneMockUtils := &dockermock.CraneMockUtils{}
 		utils := newImagePushToRegistryMockUtils(craneMockUtils)
-		err := runImagePushToRegistry(&config, nil, utils)
+		err := runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
 		assert.NoError(t, err)
 		createdConfig, err := utils.FileRead(targetDockerConfigPath)
 		assert.NoError(t, err)
@@ -91,7 +97,7 @@ func TestRunImagePushToRegistry(t *testing.T) {
 		}
 		craneMockUtils := &dockermock.CraneMockUtils{}
 		utils := newImagePushToRegistryMockUtils(craneMockUtils)
-		err := runImagePushToRegistry(&config, nil, utils)
+		err := runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
 		assert.NoError(t, err)
 		createdConfig, err := utils.FileRead(targetDockerConfigPath)
 		assert.Equal(t, customDockerConfig, string(createdConfig))
@@ -114,7 +120,7 @@ func TestRunImagePushToRegistry(t *testing.T) {
 			ErrCopyImage: dockermock.ErrCopyImage,
 		}
 		utils := newImagePushToRegistryMockUtils(craneMockUtils)
-		err := runImagePushToRegistry(&config, nil, utils)
+		err := runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
 		assert.EqualError(t, err, "failed to copy images: copy image err")
 	})
 
@@ -134,7 +140,7 @@ func TestRunImagePushToRegistry(t *testing.T) {
 			ErrLoadImage: dockermock.ErrLoadImage,
 		}
 		utils := newImagePushToRegistryMockUtils(craneMockUtils)
-		err := runImagePushToRegistry(&config, nil, utils)
+		err := runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
 		assert.EqualError(t, err, "failed to push local image to \"target.registry\": load image err")
 	})
 }
@@ -238,3 +244,308 @@ func TestMapSourceTargetImages(t *testing.T) {
 	got := mapSourceTargetImages(sourceImages)
 	assert.Equal(t, got, expected)
 }
+
+func TestTargetImageReferences(t *testing.T) {
+	t.Parallel()
+
+	t.Run("copied images", func(t *testing.T) {
+		t.Parallel()
+		config := imagePushToRegistryOptions{
+			TargetRegistryURL: "target.registry",
+			SourceImages:      []string{"image-1", "image-2"},
+			TargetImages:      map[string]string{"image-1": "target-1", "image-2": "target-2"},
+			TargetImageTag:    "1.0.0",
+			TagLatest:         true,
+		}
+		assert.Equal(t, []string{"target.registry/target-1:1.0.0", "target.registry/target-2:1.0.0"}, targetImageReferences(&config))
+	})
+
+	t.Run("local image tagged latest", func(t *testing.T) {
+		t.Parallel()
+		config := imagePushToRegistryOptions{
+			TargetRegistryURL:    "target.registry",
+			PushLocalDockerImage: true,
+			TargetImages:         map[string]string{"b": "target-b", "a": "target-a"},
+			TagLatest:            true,
+		}
+		assert.Equal(t, []string{"target.registry/target-a", "target.registry/target-b"}, targetImageReferences(&config))
+	})
+
+	t.Run("imageNameTags", func(t *testing.T) {
+		t.Parallel()
+		config := imagePushToRegistryOptions{
+			TargetRegistryURL:   "target.registry",
+			UseImageNameTags:    true,
+			SourceImageNameTags: []string{"app:1.0.0"},
+		}
+		assert.Equal(t, []string{"target.registry/app:1.0.0"}, targetImageReferences(&config))
+		config.TargetImageNameTags = []string{"renamed:1.0.0"}
+		assert.Equal(t, []string{"target.registry/renamed:1.0.0"}, targetImageReferences(&config))
+	})
+}
+
+func newPlatformTestImage(t *testing.T, os, arch string) v1.Image {
+	img, err := random.Image(256, 1)
+	require.NoError(t, err)
+	configFile, err := img.ConfigFile()
+	require.NoError(t, err)
+	configFile.OS, configFile.Architecture = os, arch
+	img, err = mutate.ConfigFile(img, configFile)
+	require.NoError(t, err)
+	return img
+}
+
+func TestPushMultiPlatformImage(t *testing.T) {
+	t.Parallel()
+	amd64Image := newPlatformTestImage(t, "linux", "amd64")
+	arm64Image := newPlatformTestImage(t, "linux", "arm64")
+
+	newConfig := func() imagePushToRegistryOptions {
+		return imagePushToRegistryOptions{
+			SourceRegistryURL:      "https://source.registry",
+			SourceRegistryUser:     "sourceuser",
+			SourceRegistryPassword: "sourcepassword",
+			SourceImages:           []string{"app"},
+			TargetRegistryURL:      "https://target.registry",
+			TargetRegistryUser:     "targetuser",
+			TargetRegistryPassword: "targetpassword",
+			TargetImageTag:         "1.0.0",
+			TargetArchitectures:    []string{"linux/amd64", "linux/arm64"},
+			PlatformImages:         []string{"app:1.0.0-amd64", "images/app-arm64.tar"},
+		}
+	}
+
+	t.Run("registry image and tarball", func(t *testing.T) {
+		t.Parallel()
+		craneMockUtils := &dockermock.CraneMockUtils{Images: map[string]v1.Image{
+			"source.registry/app:1.0.0-amd64": amd64Image,
+			"images/app-arm64.tar":            arm64Image,
+		}}
+		utils := newImagePushToRegistryMockUtils(craneMockUtils)
+		utils.AddFile("images/app-arm64.tar", []byte("tarball"))
+		config := newConfig()
+
+		err := runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
+
+		require.NoError(t, err)
+		require.Len(t, craneMockUtils.PushedIndexes, 1)
+		index := craneMockUtils.PushedIndexes["target.registry/app:1.0.0"]
+		require.NotNil(t, index)
+		manifest, err := index.IndexManifest()
+		require.NoError(t, err)
+		require.Len(t, manifest.Manifests, 2)
+		amd64Digest, _ := amd64Image.Digest()
+		assert.Equal(t, amd64Digest, manifest.Manifests[0].Digest)
+		assert.Equal(t, "linux/amd64", manifest.Manifests[0].Platform.String())
+		assert.Equal(t, "linux/arm64", manifest.Manifests[1].Platform.String())
+	})
+
+	t.Run("image of wrong platform", func(t *testing.T) {
+		t.Parallel()
+		craneMockUtils := &dockermock.CraneMockUtils{Images: map[string]v1.Image{
+			"source.registry/app:1.0.0-amd64": amd64Image,
+			"images/app-arm64.tar":            amd64Image,
+		}}
+		utils := newImagePushToRegistryMockUtils(craneMockUtils)
+		utils.AddFile("images/app-arm64.tar", []byte("tarball"))
+		config := newConfig()
+
+		err := runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
+
+		assert.EqualError(t, err, "failed to push multi-platform image: image for platform linux/arm64 has been built for platform linux/amd64")
+		assert.Empty(t, craneMockUtils.PushedIndexes)
+	})
+
+	t.Run("failed to push image index", func(t *testing.T) {
+		t.Parallel()
+		craneMockUtils := &dockermock.CraneMockUtils{
+			Images:            map[string]v1.Image{"source.registry/app:1.0.0-amd64": amd64Image, "source.registry/images/app-arm64.tar": arm64Image},
+			ErrPushImageIndex: errors.New("push index err"),
+		}
+		utils := newImagePushToRegistryMockUtils(craneMockUtils)
+		config := newConfig()
+
+		err := runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
+
+		assert.EqualError(t, err, "failed to push multi-platform image: push index err")
+	})
+
+	t.Run("configuration errors", func(t *testing.T) {
+		t.Parallel()
+		utils := newImagePushToRegistryMockUtils(&dockermock.CraneMockUtils{})
+
+		config := newConfig()
+		config.PlatformImages = config.PlatformImages[:1]
+		err := runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
+		assert.EqualError(t, err, "configuration error: please configure one of platformImages for each of targetArchitectures")
+
+		config = newConfig()
+		config.UseImageNameTags = true
+		err = runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
+		assert.EqualError(t, err, "configuration error: targetArchitectures cannot be combined with pushLocalDockerImage or useImageNameTags")
+	})
+}
+
+func TestPromoteImagesByDigest(t *testing.T) {
+	t.Parallel()
+	newConfig := func() imagePushToRegistryOptions {
+		return imagePushToRegistryOptions{
+			SourceRegistryURL:      "https://source.registry",
+			SourceRegistryUser:     "sourceuser",
+			SourceRegistryPassword: "sourcepassword",
+			SourceImages:           []string{"app", "worker"},
+			SourceImageTag:         "1.0.0",
+			SourceImageNameTags:    []string{"app:0.9.0", "app:1.0.0", "worker:1.0.0"},
+			SourceImageDigests:     []string{"sha256:0900", "sha256:1000", "sha256:2000"},
+			TargetRegistryURL:      "https://target.registry",
+			TargetRegistryUser:     "targetuser",
+			TargetRegistryPassword: "targetpassword",
+			TargetImageTag:         "1.0.0",
+			EnvironmentTags:        []string{"prod"},
+			PromoteByDigest:        true,
+		}
+	}
+
+	t.Run("promote to environment", func(t *testing.T) {
+		t.Parallel()
+		craneMockUtils := &dockermock.CraneMockUtils{}
+		utils := newImagePushToRegistryMockUtils(craneMockUtils)
+		config := newConfig()
+		cpe := imagePushToRegistryCommonPipelineEnvironment{}
+
+		err := runImagePushToRegistry(&config, nil, &cpe, utils)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"target.registry/app:1.0.0":    "source.registry/app@sha256:1000",
+			"target.registry/app:prod":     "source.registry/app@sha256:1000",
+			"target.registry/worker:1.0.0": "source.registry/worker@sha256:2000",
+			"target.registry/worker:prod":  "source.registry/worker@sha256:2000",
+		}, craneMockUtils.CopiedImages)
+		assert.Equal(t, []string{"target.registry/app@sha256:1000", "target.registry/worker@sha256:2000"}, cpe.container.promotedImages)
+	})
+
+	t.Run("tag points to other digest", func(t *testing.T) {
+		t.Parallel()
+		craneMockUtils := &dockermock.CraneMockUtils{}
+		utils := &promotionDigestMockUtils{imagePushToRegistryMockUtils: newImagePushToRegistryMockUtils(craneMockUtils)}
+		config := newConfig()
+
+		err := runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
+
+		assert.EqualError(t, err, "failed to promote images: target.registry/app:1.0.0 points to sha256:ffff instead of the promoted digest sha256:1000")
+	})
+
+	t.Run("digest not recorded", func(t *testing.T) {
+		t.Parallel()
+		utils := newImagePushToRegistryMockUtils(&dockermock.CraneMockUtils{})
+		config := newConfig()
+		config.SourceImageTag = "2.0.0"
+
+		err := runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
+
+		assert.EqualError(t, err, "failed to promote images: no digest found for image app, please provide sourceImageNameTags and sourceImageDigests e.g. via the commonPipelineEnvironment of the build step")
+	})
+
+	t.Run("configuration errors", func(t *testing.T) {
+		t.Parallel()
+		utils := newImagePushToRegistryMockUtils(&dockermock.CraneMockUtils{})
+
+		config := newConfig()
+		config.SourceImageDigests = config.SourceImageDigests[:1]
+		err := runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
+		assert.EqualError(t, err, "configuration error: please configure one of sourceImageDigests for each of sourceImageNameTags")
+
+		config = newConfig()
+		config.PushLocalDockerImage = true
+		err = runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
+		assert.EqualError(t, err, "configuration error: promoteByDigest cannot be combined with pushLocalDockerImage, useImageNameTags or targetArchitectures")
+
+		config = newConfig()
+		config.TargetImageTag, config.EnvironmentTags = "", nil
+		err = runImagePushToRegistry(&config, nil, &imagePushToRegistryCommonPipelineEnvironment{}, utils)
+		assert.EqualError(t, err, "failed to promote images: no target tags configured, please configure targetImageTag, environmentTags or tagLatest")
+	})
+}
+
+// promotionDigestMockUtils simulates a tag which has been overwritten concurrently
+type promotionDigestMockUtils struct {
+	*imagePushToRegistryMockUtils
+}
+
+func (m *promotionDigestMockUtils) GetImageDigest(_ context.Context, src string) (string, error) {
+	return "sha256:ffff", nil
+}
+
+func TestApplyRetentionPolicy(t *testing.T) {
+	t.Parallel()
+	newCraneMockUtils := func() *dockermock.CraneMockUtils {
+		return &dockermock.CraneMockUtils{
+			Tags: map[string][]string{
+				"target.registry/app": {"0.8.0", "0.9.0", "0.9.1", "1.0.0", "latest", "prod", "sha256-0800.sig", "sha256-0900.sig", "sha256-0900.att"},
+			},
+			Digests: map[string]string{
+				"target.registry/app:0.8.0":           "sha256:0800",
+				"target.registry/app:0.9.0":           "sha256:0900",
+				"target.registry/app:0.9.1":           "sha256:0900",
+				"target.registry/app:1.0.0":           "sha256:1000",
+				"target.registry/app:latest":          "sha256:1000",
+				"target.registry/app:prod":            "sha256:0800",
+				"target.registry/app:sha256-0800.sig": "sha256:0801",
+				"target.registry/app:sha256-0900.sig": "sha256:0901",
+				"target.registry/app:sha256-0900.att": "sha256:0902",
+			},
+		}
+	}
+	config := imagePushToRegistryOptions{
+		SourceImages:          []string{"app"},
+		TargetImages:          map[string]string{"app": "app"},
+		TargetRegistryURL:     "target.registry",
+		TargetImageTag:        "1.0.0",
+		RetentionKeepVersions: 2,
+	}
+
+	t.Run("delete old versions", func(t *testing.T) {
+		t.Parallel()
+		craneMockUtils := newCraneMockUtils()
+		craneMockUtils.Digests["target.registry/app:prod"] = "sha256:1000"
+
+		err := applyRetentionPolicy(&config, newImagePushToRegistryMockUtils(craneMockUtils))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"target.registry/app@sha256:0800", "target.registry/app@sha256:0801"}, craneMockUtils.DeletedImages, "the signature of the deleted image is deleted as well")
+	})
+
+	t.Run("keep versions referenced by other tags", func(t *testing.T) {
+		t.Parallel()
+		craneMockUtils := newCraneMockUtils()
+		keepOne := config
+		keepOne.RetentionKeepVersions = 1
+
+		err := applyRetentionPolicy(&keepOne, newImagePushToRegistryMockUtils(craneMockUtils))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"target.registry/app@sha256:0900", "target.registry/app@sha256:0901", "target.registry/app@sha256:0902"}, craneMockUtils.DeletedImages, "the signature of the kept image sha256:0800 is kept")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		craneMockUtils := newCraneMockUtils()
+		disabled := config
+		disabled.RetentionKeepVersions = 0
+
+		assert.NoError(t, applyRetentionPolicy(&disabled, newImagePushToRegistryMockUtils(craneMockUtils)))
+		assert.Empty(t, craneMockUtils.DeletedImages)
+	})
+
+	t.Run("deletion not supported", func(t *testing.T) {
+		t.Parallel()
+		craneMockUtils := newCraneMockUtils()
+		craneMockUtils.Digests["target.registry/app:prod"] = "sha256:1000"
+		craneMockUtils.ErrDeleteImage = errors.New("UNSUPPORTED")
+
+		err := applyRetentionPolicy(&config, newImagePushToRegistryMockUtils(craneMockUtils))
+
+		assert.EqualError(t, err, "failed to delete target.registry/app:0.8.0: UNSUPPORTED")
+	})
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/SAP/jenkins-library/pkg/build"
+	"github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// signContainerImages signs the pushed images with the signing key, the signatures are stored in the registry in the format of cosign.
+// Images should be referenced by digest to make sure the image which has been built is signed.
+func signContainerImages(images []string, signingKey, signingKeyPassword, dockerConfigJSON string, utils piperutils.FileUtils) error {
+	if len(signingKey) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.New("signingKey is required for signing images")
+	}
+	if len(images) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.New("no pushed images found which can be signed")
+	}
+	key, opts, err := imageSigningOptions(signingKey, signingKeyPassword, dockerConfigJSON, utils)
+	if err != nil {
+		return err
+	}
+
+	for _, image := range images {
+		log.Entry().Infof("Signing image '%v'", image)
+		if _, err := docker.SignImage(image, key, opts...); err != nil {
+			return fmt.Errorf("failed to sign image '%v': %w", image, err)
+		}
+	}
+	return nil
+}
+
+// attachProvenance pushes the provenance as attestation signed with the signing key next to the images
+func attachProvenance(images []string, statement *build.Statement, signingKey, signingKeyPassword, dockerConfigJSON string, utils piperutils.FileUtils) error {
+	if len(signingKey) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.New("signingKey is required for attaching the provenance to images")
+	}
+	content, err := json.Marshal(statement)
+	if err != nil {
+		return fmt.Errorf("failed to marshal provenance: %w", err)
+	}
+	key, opts, err := imageSigningOptions(signingKey, signingKeyPassword, dockerConfigJSON, utils)
+	if err != nil {
+		return err
+	}
+
+	for _, image := range images {
+		log.Entry().Infof("Attaching provenance to image '%v'", image)
+		if _, err := docker.AttachAttestation(image, content, build.ProvenancePredicateType, key, opts...); err != nil {
+			return fmt.Errorf("failed to attach provenance to image '%v': %w", image, err)
+		}
+	}
+	return nil
+}
+
+func imageSigningOptions(signingKey, signingKeyPassword, dockerConfigJSON string, utils piperutils.FileUtils) (crypto.Signer, []remote.Option, error) {
+	content, err := utils.FileRead(signingKey)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, nil, fmt.Errorf("failed to read signing key '%v': %w", signingKey, err)
+	}
+	key, err := docker.LoadSigningKey(content, []byte(signingKeyPassword))
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, nil, err
+	}
+	opts, err := docker.RegistryOptions(context.Background(), dockerConfigJSON, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, nil, err
+	}
+	return key, opts, nil
+}
+
+// imageReferencesByDigest combines the pushed images and their digests to references by digest.
+// The registry is taken from each image, the registryURL is only used for images without registry, e.g. the imageNameTags of the commonPipelineEnvironment.
+// Without registryURL such images refer to Docker Hub.
+// A single digest is used for all images, e.g. if the same image is pushed with several tags.
+func imageReferencesByDigest(registryURL string, images, digests []string) ([]string, error) {
+	if len(digests) == 0 {
+		return nil, errors.New("no image digests available")
+	}
+	if len(digests) != 1 && len(digests) != len(images) {
+		return nil, fmt.Errorf("number of image digests (%v) does not match number of images (%v)", len(digests), len(images))
+	}
+
+	references := []string{}
+	for i, image := range images {
+		digest := digests[0]
+		if len(digests) > 1 {
+			digest = digests[i]
+		}
+		destination, err := imageDestination(registryURL, image)
+		if err != nil {
+			return nil, err
+		}
+		ref, err := name.ParseReference(destination)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image '%v': %w", image, err)
+		}
+		reference := ref.Context().Digest(strings.TrimSpace(digest)).String()
+		if !slices.Contains(references, reference) {
+			references = append(references, reference)
+		}
+	}
+	return references, nil
+}
+
+// imageDestination returns the full reference of the image, the registry of the registryURL is added if the image does not contain a registry
+func imageDestination(registryURL, image string) (string, error) {
+	if len(registryURL) == 0 || hasRegistry(image) {
+		return image, nil
+	}
+	registry, err := docker.ContainerRegistryFromURL(registryURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to read registry url %v: %w", registryURL, err)
+	}
+	return fmt.Sprintf("%v/%v", registry, image), nil
+}
+
+// hasRegistry follows the rule of Docker, the first part of the image name is a registry if it contains a dot or a port or is localhost
+func hasRegistry(image string) bool {
+	first, _, found := strings.Cut(image, "/")
+	return found && (strings.ContainsAny(first, ".:") || first == "localhost")
+}
@@ -0,0 +1,134 @@
+//go:build unit
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	stdlog "log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+// newSigningTestRegistry starts an in-process registry and returns its host
+func newSigningTestRegistry(t *testing.T) string {
+	server := httptest.NewServer(registry.New(registry.Logger(stdlog.New(io.Discard, "", 0))))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func pushSigningTestImage(t *testing.T, image string) string {
+	img, err := random.Image(256, 1)
+	require.NoError(t, err)
+	ref, err := name.ParseReference(image)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+	digest, _ := img.Digest()
+	return digest.String()
+}
+
+// addSigningTestKeys adds an unencrypted private key and the corresponding public key to the files mock
+func addSigningTestKeys(t *testing.T, files *mock.FilesMock, privateKeyPath, publicKeyPath string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	files.AddFile(privateKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	der, _ = x509.MarshalPKIXPublicKey(key.Public())
+	files.AddFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestSignContainerImages(t *testing.T) {
+	t.Parallel()
+	host := newSigningTestRegistry(t)
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		digest := pushSigningTestImage(t, host+"/signed:1.0.0")
+		files := &mock.FilesMock{}
+		addSigningTestKeys(t, files, "cosign.key", "cosign.pub")
+
+		err := signContainerImages([]string{host + "/signed@" + digest}, "cosign.key", "", "", files)
+
+		assert.NoError(t, err)
+		signatureTag, err := name.NewTag(host + "/signed:" + strings.ReplaceAll(digest, ":", "-") + ".sig")
+		require.NoError(t, err)
+		signatures, err := remote.Image(signatureTag)
+		require.NoError(t, err)
+		layers, _ := signatures.Layers()
+		assert.Len(t, layers, 1)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		t.Parallel()
+		err := signContainerImages([]string{host + "/signed:1.0.0"}, "", "", "", &mock.FilesMock{})
+		assert.EqualError(t, err, "signingKey is required for signing images")
+
+		err = signContainerImages([]string{host + "/signed:1.0.0"}, "cosign.key", "", "", &mock.FilesMock{})
+		assert.ErrorContains(t, err, "failed to read signing key 'cosign.key'")
+	})
+
+	t.Run("unknown image", func(t *testing.T) {
+		t.Parallel()
+		files := &mock.FilesMock{}
+		addSigningTestKeys(t, files, "cosign.key", "cosign.pub")
+
+		err := signContainerImages([]string{host + "/unknown:1.0.0"}, "cosign.key", "", "", files)
+
+		assert.ErrorContains(t, err, "failed to sign image '"+host+"/unknown:1.0.0'")
+	})
+}
+
+func TestImageReferencesByDigest(t *testing.T) {
+	t.Parallel()
+	digest1 := "sha256:" + strings.Repeat("1", 64)
+	digest2 := "sha256:" + strings.Repeat("2", 64)
+
+	t.Run("digest per image", func(t *testing.T) {
+		t.Parallel()
+		images, err := imageReferencesByDigest("https://my.registry.com", []string{"app:1.0.0", "sidecar:1.0.0"}, []string{digest1, digest2 + "\n"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"my.registry.com/app@" + digest1, "my.registry.com/sidecar@" + digest2}, images)
+	})
+
+	t.Run("one digest for several tags", func(t *testing.T) {
+		t.Parallel()
+		images, err := imageReferencesByDigest("https://my.registry.com", []string{"app:1.0.0", "app:latest"}, []string{digest1})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"my.registry.com/app@" + digest1}, images)
+	})
+
+	t.Run("registry per destination", func(t *testing.T) {
+		t.Parallel()
+		images, err := imageReferencesByDigest("", []string{"my.registry.com/app:1.0.0", "other.registry.com:5000/team/app:1.0.0", "localhost/app:1.0.0", "team/app:1.0.0"}, []string{digest1})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"my.registry.com/app@" + digest1, "other.registry.com:5000/team/app@" + digest1, "localhost/app@" + digest1, "index.docker.io/team/app@" + digest1}, images)
+
+		images, err = imageReferencesByDigest("https://my.registry.com", []string{"team/app:1.0.0", "other.registry.com/app:1.0.0"}, []string{digest1, digest2})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"my.registry.com/team/app@" + digest1, "other.registry.com/app@" + digest2}, images)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := imageReferencesByDigest("https://my.registry.com", []string{"app:1.0.0"}, nil)
+		assert.EqualError(t, err, "no image digests available")
+		_, err = imageReferencesByDigest("https://my.registry.com", []string{"a:1", "b:1", "c:1"}, []string{digest1, digest2})
+		assert.EqualError(t, err, "number of image digests (2) does not match number of images (3)")
+		_, err = imageReferencesByDigest("my registry", []string{"app:1.0.0"}, []string{digest1})
+		assert.ErrorContains(t, err, "failed to read registry url my registry")
+	})
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type imageVerifySignatureUtils interface {
+	piperutils.FileUtils
+}
+
+type imageVerifySignatureUtilsBundle struct {
+	*piperutils.Files
+}
+
+func newImageVerifySignatureUtils() imageVerifySignatureUtils {
+	return &imageVerifySignatureUtilsBundle{Files: &piperutils.Files{}}
+}
+
+func imageVerifySignature(config imageVerifySignatureOptions, telemetryData *telemetry.CustomData) {
+	utils := newImageVerifySignatureUtils()
+
+	if err := runImageVerifySignature(&config, utils); err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runImageVerifySignature(config *imageVerifySignatureOptions, utils imageVerifySignatureUtils) error {
+	publicKeys, err := loadPublicKeys(config.PublicKeys, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	images, err := imagesToVerify(config)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	opts, err := docker.RegistryOptions(context.Background(), config.DockerConfigJSON, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	failed := 0
+	for _, image := range images {
+		verified, err := docker.VerifyImageSignature(image, publicKeys, opts...)
+		if err != nil {
+			log.Entry().WithError(err).Errorf("Signature verification of image '%v' failed", image)
+			failed++
+			continue
+		}
+		log.Entry().Infof("Signature of image '%v' verified", verified)
+	}
+	if failed > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("signature verification failed for %v of %v image(s)", failed, len(images))
+	}
+	return nil
+}
+
+func loadPublicKeys(paths []string, utils imageVerifySignatureUtils) ([]crypto.PublicKey, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no public keys configured")
+	}
+	publicKeys := []crypto.PublicKey{}
+	for _, path := range paths {
+		content, err := utils.FileRead(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key '%v': %w", path, err)
+		}
+		publicKey, err := docker.LoadPublicKey(content)
+		if err != nil {
+			return nil, fmt.Errorf("public key '%v': %w", path, err)
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+	return publicKeys, nil
+}
+
+// imagesToVerify returns the configured images or the images built in the pipeline, preferably referenced by digest
+func imagesToVerify(config *imageVerifySignatureOptions) ([]string, error) {
+	if len(config.Images) > 0 {
+		return config.Images, nil
+	}
+	if len(config.ContainerImageNameTags) == 0 {
+		return nil, errors.New("no images to verify, please configure images")
+	}
+	return builtImageReferences(config.ContainerRegistryURL, config.ContainerImageNameTags, config.ContainerImageDigests)
+}
+
+// builtImageReferences returns the full references of the images built in the pipeline, by digest if available
+func builtImageReferences(registryURL string, imageNameTags, imageDigests []string) ([]string, error) {
+	if len(imageDigests) > 0 {
+		return imageReferencesByDigest(registryURL, imageNameTags, imageDigests)
+	}
+	images := []string{}
+	for _, imageNameTag := range imageNameTags {
+		image, err := imageDestination(registryURL, imageNameTag)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
@@ -0,0 +1,81 @@
+//go:build unit
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+type imageVerifySignatureMockUtils struct {
+	*mock.FilesMock
+}
+
+func TestRunImageVerifySignature(t *testing.T) {
+	t.Parallel()
+	host := newSigningTestRegistry(t)
+
+	files := &mock.FilesMock{}
+	addSigningTestKeys(t, files, "cosign.key", "cosign.pub")
+	addSigningTestKeys(t, files, "other.key", "other.pub")
+	signedDigest := pushSigningTestImage(t, host+"/app:1.0.0")
+	require.NoError(t, signContainerImages([]string{host + "/app:1.0.0"}, "cosign.key", "", "", files))
+	pushSigningTestImage(t, host+"/unsigned:1.0.0")
+
+	t.Run("images of commonPipelineEnvironment", func(t *testing.T) {
+		t.Parallel()
+		config := imageVerifySignatureOptions{
+			ContainerRegistryURL:   "http://" + host,
+			ContainerImageNameTags: []string{"app:1.0.0"},
+			ContainerImageDigests:  []string{signedDigest},
+			PublicKeys:             []string{"other.pub", "cosign.pub"},
+		}
+
+		err := runImageVerifySignature(&config, imageVerifySignatureMockUtils{FilesMock: files})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("configured images", func(t *testing.T) {
+		t.Parallel()
+		config := imageVerifySignatureOptions{
+			Images:     []string{host + "/app:1.0.0", host + "/unsigned:1.0.0"},
+			PublicKeys: []string{"cosign.pub"},
+		}
+
+		err := runImageVerifySignature(&config, imageVerifySignatureMockUtils{FilesMock: files})
+
+		assert.EqualError(t, err, "signature verification failed for 1 of 2 image(s)")
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		t.Parallel()
+		config := imageVerifySignatureOptions{
+			ContainerRegistryURL:   "http://" + host,
+			ContainerImageNameTags: []string{"app:1.0.0"},
+			PublicKeys:             []string{"other.pub"},
+		}
+
+		err := runImageVerifySignature(&config, imageVerifySignatureMockUtils{FilesMock: files})
+
+		assert.EqualError(t, err, "signature verification failed for 1 of 1 image(s)")
+	})
+
+	t.Run("configuration errors", func(t *testing.T) {
+		t.Parallel()
+		utils := imageVerifySignatureMockUtils{FilesMock: files}
+
+		err := runImageVerifySignature(&imageVerifySignatureOptions{PublicKeys: []string{"cosign.pub"}}, utils)
+		assert.EqualError(t, err, "no images to verify, please configure images")
+
+		err = runImageVerifySignature(&imageVerifySignatureOptions{Images: []string{host + "/app:1.0.0"}, PublicKeys: []string{"missing.pub"}}, utils)
+		assert.ErrorContains(t, err, "failed to read public key 'missing.pub'")
+
+		err = runImageVerifySignature(&imageVerifySignatureOptions{Images: []string{host + "/app:1.0.0"}, PublicKeys: []string{"cosign.key"}}, utils)
+		assert.EqualError(t, err, "public key 'cosign.key': unsupported public key type 'PRIVATE KEY'")
+	})
+}
@@ -1,15 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 
 	"github.com/SAP/jenkins-library/pkg/build"
+	"github.com/SAP/jenkins-library/pkg/buildkit"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
 	"github.com/SAP/jenkins-library/pkg/certutils"
 	"github.com/SAP/jenkins-library/pkg/command"
@@ -22,6 +25,14 @@ import (
 	"github.com/SAP/jenkins-library/pkg/versioning"
 )
 
+const (
+	builderBuildKit       = "buildkit"
+	kanikoDockerConfigDir = "/kaniko/.docker"
+)
+
+// buildKitBuild sends the build to the BuildKit daemon, it is replaced in tests
+var buildKitBuild = buildkit.Build
+
 func kanikoExecute(config kanikoExecuteOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *kanikoExecuteCommonPipelineEnvironment) {
 	// for command execution use Command
 	c := command.Command{
@@ -41,13 +52,73 @@ func kanikoExecute(config kanikoExecuteOptions, telemetryData *telemetry.CustomD
 
 	fileUtils := &piperutils.Files{}
 
-	err := runKanikoExecute(&config, telemetryData, commonPipelineEnvironment, &c, client, fileUtils)
+	if config.SignImages || config.CreateProvenance {
+		// signing and provenance require the digests of the pushed images
+		config.ReadImageDigest = true
+	}
+
+	dockerConfigDir := config.DockerConfigDirectory
+	if len(dockerConfigDir) == 0 {
+		dockerConfigDir = kanikoDockerConfigDir
+	}
+	if config.Builder == builderBuildKit {
+		// the registry credentials are kept in a private directory which is removed once the images are signed
+		var err error
+		dockerConfigDir, err = fileUtils.TempDir("", "buildkit-docker-config-")
+		if err != nil {
+			log.Entry().WithError(err).Fatal("Failed to create directory for the Docker config")
+		}
+	}
+
+	err := runKanikoExecuteAndSign(&config, telemetryData, commonPipelineEnvironment, &c, client, fileUtils, dockerConfigDir)
+	if config.Builder == builderBuildKit {
+		// log.Fatal does not run deferred functions, hence the directory is removed before
+		if err := fileUtils.RemoveAll(dockerConfigDir); err != nil {
+			log.Entry().WithError(err).Warnf("failed to remove directory '%v'", dockerConfigDir)
+		}
+	}
 	if err != nil {
 		log.Entry().WithError(err).Fatal("Kaniko execution failed")
 	}
 }
 
-func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *kanikoExecuteCommonPipelineEnvironment, execRunner command.ExecRunner, httpClient piperhttp.Sender, fileUtils piperutils.FileUtils) error {
+// runKanikoExecuteAndSign builds the images and signs them and creates their provenance if configured
+func runKanikoExecuteAndSign(config *kanikoExecuteOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *kanikoExecuteCommonPipelineEnvironment, execRunner command.ExecRunner, httpClient piperhttp.Sender, fileUtils piperutils.FileUtils, dockerConfigDir string) error {
+	startedOn := time.Now()
+	images, err := runKanikoExecute(config, telemetryData, commonPipelineEnvironment, execRunner, httpClient, fileUtils, dockerConfigDir)
+	if err != nil {
+		return err
+	}
+
+	dockerConfigFile := filepath.Join(dockerConfigDir, "config.json")
+	if config.SignImages {
+		if err := signContainerImages(images, config.SigningKey, config.SigningKeyPassword, dockerConfigFile, fileUtils); err != nil {
+			return fmt.Errorf("signing of images failed: %w", err)
+		}
+	}
+
+	if config.CreateProvenance {
+		if err := createKanikoProvenance(config, images, startedOn, dockerConfigFile, fileUtils); err != nil {
+			return fmt.Errorf("creation of provenance failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// createKanikoProvenance creates the provenance of the pushed images, it is attached to the images if they are signed
+func createKanikoProvenance(config *kanikoExecuteOptions, images []string, startedOn time.Time, dockerConfigFile string, fileUtils piperutils.FileUtils) error {
+	statement, err := writeImageProvenance(kanikoExecuteMetadata(), config, images, startedOn, fileUtils)
+	if err != nil || statement == nil || !config.SignImages {
+		return err
+	}
+	return attachProvenance(images, statement, config.SigningKey, config.SigningKeyPassword, dockerConfigFile, fileUtils)
+}
+
+// runKanikoExecute builds and pushes the images, the pushed images are returned referenced by digest for signing and provenance
+func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *kanikoExecuteCommonPipelineEnvironment, execRunner command.ExecRunner, httpClient piperhttp.Sender, fileUtils piperutils.FileUtils, dockerConfigDir string) ([]string, error) {
+	images := []string{}
+	dockerConfigFile := filepath.Join(dockerConfigDir, "config.json")
+
 	// backward compatibility for parameter ContainerBuildOptions
 	if len(config.ContainerBuildOptions) > 0 {
 		config.BuildOptions = strings.Split(config.ContainerBuildOptions, " ")
@@ -60,14 +131,16 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 	if len(config.ContainerPreparationCommand) > 0 {
 		prepCommand := strings.Split(config.ContainerPreparationCommand, " ")
 		if err := execRunner.RunExecutable(prepCommand[0], prepCommand[1:]...); err != nil {
-			return fmt.Errorf("failed to initialize Kaniko container: %w", err)
+			return nil, fmt.Errorf("failed to initialize Kaniko container: %w", err)
 		}
 	}
 
-	if len(config.CustomTLSCertificateLinks) > 0 {
+	if len(config.CustomTLSCertificateLinks) > 0 && config.Builder == builderBuildKit {
+		log.Entry().Warning("customTlsCertificateLinks are not applied to the BuildKit daemon, the certificates have to be trusted by the daemon")
+	} else if len(config.CustomTLSCertificateLinks) > 0 {
 		err := certutils.CertificateUpdate(config.CustomTLSCertificateLinks, httpClient, fileUtils, "/kaniko/ssl/certs/ca-certificates.crt")
 		if err != nil {
-			return fmt.Errorf("failed to update certificates: %w", err)
+			return nil, fmt.Errorf("failed to update certificates: %w", err)
 		}
 	} else {
 		log.Entry().Info("skipping updation of certificates")
@@ -80,7 +153,7 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 		var err error
 		dockerConfig, err = fileUtils.FileRead(config.DockerConfigJSON)
 		if err != nil {
-			return fmt.Errorf("failed to read existing docker config json at '%v': %w", config.DockerConfigJSON, err)
+			return nil, fmt.Errorf("failed to read existing docker config json at '%v': %w", config.DockerConfigJSON, err)
 		}
 	}
 
@@ -89,27 +162,27 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 	if len(config.DockerConfigJSON) > 0 && len(config.ContainerRegistryURL) > 0 && len(config.ContainerRegistryPassword) > 0 && len(config.ContainerRegistryUser) > 0 {
 		targetConfigJson, err := docker.CreateDockerConfigJSON(config.ContainerRegistryURL, config.ContainerRegistryUser, config.ContainerRegistryPassword, "", config.DockerConfigJSON, fileUtils)
 		if err != nil {
-			return fmt.Errorf("failed to update existing docker config json file '%v': %w", config.DockerConfigJSON, err)
+			return nil, fmt.Errorf("failed to update existing docker config json file '%v': %w", config.DockerConfigJSON, err)
 		}
 
 		dockerConfig, err = fileUtils.FileRead(targetConfigJson)
 		if err != nil {
-			return fmt.Errorf("failed to read enhanced file '%v': %w", config.DockerConfigJSON, err)
+			return nil, fmt.Errorf("failed to read enhanced file '%v': %w", config.DockerConfigJSON, err)
 		}
 	} else if len(config.DockerConfigJSON) == 0 && len(config.ContainerRegistryURL) > 0 && len(config.ContainerRegistryPassword) > 0 && len(config.ContainerRegistryUser) > 0 {
-		targetConfigJson, err := docker.CreateDockerConfigJSON(config.ContainerRegistryURL, config.ContainerRegistryUser, config.ContainerRegistryPassword, "", "/kaniko/.docker/config.json", fileUtils)
+		targetConfigJson, err := docker.CreateDockerConfigJSON(config.ContainerRegistryURL, config.ContainerRegistryUser, config.ContainerRegistryPassword, "", dockerConfigFile, fileUtils)
 		if err != nil {
-			return fmt.Errorf("failed to create new docker config json at /kaniko/.docker/config.json: %w", err)
+			return nil, fmt.Errorf("failed to create new docker config json at %v: %w", dockerConfigFile, err)
 		}
 
 		dockerConfig, err = fileUtils.FileRead(targetConfigJson)
 		if err != nil {
-			return fmt.Errorf("failed to read new docker config file at /kaniko/.docker/config.json: %w", err)
+			return nil, fmt.Errorf("failed to read new docker config file at %v: %w", dockerConfigFile, err)
 		}
 	}
 
-	if err := fileUtils.FileWrite("/kaniko/.docker/config.json", dockerConfig, 0644); err != nil {
-		return fmt.Errorf("failed to write file '/kaniko/.docker/config.json': %w", err)
+	if err := fileUtils.FileWrite(dockerConfigFile, dockerConfig, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write file '%v': %w", dockerConfigFile, err)
 	}
 
 	log.Entry().Debugf("preparing build settings information...")
@@ -117,7 +190,7 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 	// ToDo: better testability required. So far retrieval of config is rather non deterministic
 	dockerImage, err := GetDockerImageValue(stepName)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve dockerImage configuration: %w", err)
+		return nil, fmt.Errorf("failed to retrieve dockerImage configuration: %w", err)
 	}
 
 	kanikoConfig := buildsettings.BuildOptions{
@@ -137,19 +210,19 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 		log.Entry().Debugf("Multi-image build activated for image name '%v'", config.ContainerImageName)
 
 		if config.ContainerRegistryURL == "" {
-			return fmt.Errorf("empty ContainerRegistryURL")
+			return nil, fmt.Errorf("empty ContainerRegistryURL")
 		}
 		if config.ContainerImageName == "" {
-			return fmt.Errorf("empty ContainerImageName")
+			return nil, fmt.Errorf("empty ContainerImageName")
 		}
 		if config.ContainerImageTag == "" {
-			return fmt.Errorf("empty ContainerImageTag")
+			return nil, fmt.Errorf("empty ContainerImageTag")
 		}
 
 		containerRegistry, err := docker.ContainerRegistryFromURL(config.ContainerRegistryURL)
 		if err != nil {
 			log.SetErrorCategory(log.ErrorConfiguration)
-			return fmt.Errorf("failed to read registry url %v: %w", config.ContainerRegistryURL, err)
+			return nil, fmt.Errorf("failed to read registry url %v: %w", config.ContainerRegistryURL, err)
 		}
 
 		commonPipelineEnvironment.container.registryURL = config.ContainerRegistryURL
@@ -159,18 +232,20 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 
 		imageListWithFilePath, err := docker.ImageListWithFilePath(config.ContainerImageName, config.ContainerMultiImageBuildExcludes, config.ContainerMultiImageBuildTrimDir, fileUtils)
 		if err != nil {
-			return fmt.Errorf("failed to identify image list for multi image build: %w", err)
+			return nil, fmt.Errorf("failed to identify image list for multi image build: %w", err)
 		}
 		if len(imageListWithFilePath) == 0 {
-			return fmt.Errorf("no docker files to process, please check exclude list")
+			return nil, fmt.Errorf("no docker files to process, please check exclude list")
 		}
 		for image, file := range imageListWithFilePath {
 			log.Entry().Debugf("Building image '%v' using file '%v'", image, file)
 			containerImageNameAndTag := fmt.Sprintf("%v:%v", image, containerImageTag)
 			buildOpts := append(config.BuildOptions, "--destination", fmt.Sprintf("%v/%v", containerRegistry, containerImageNameAndTag))
-			if err = runKaniko(config, file, buildOpts, execRunner, fileUtils, commonPipelineEnvironment); err != nil {
-				return fmt.Errorf("failed to build image '%v' using '%v': %w", image, file, err)
+			pushed, err := runKaniko(config, file, buildOpts, execRunner, fileUtils, commonPipelineEnvironment, dockerConfigDir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build image '%v' using '%v': %w", image, file, err)
 			}
+			images = append(images, pushed...)
 			commonPipelineEnvironment.container.imageNames = append(commonPipelineEnvironment.container.imageNames, image)
 			commonPipelineEnvironment.container.imageNameTags = append(commonPipelineEnvironment.container.imageNameTags, containerImageNameAndTag)
 		}
@@ -184,42 +259,42 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 		}
 		if config.CreateBOM {
 			// Syft for multi image, generates bom-docker-(1/2/3).xml
-			err := syft.GenerateSBOM(config.SyftDownloadURL, "/kaniko/.docker", execRunner, fileUtils, httpClient, commonPipelineEnvironment.container.registryURL, commonPipelineEnvironment.container.imageNameTags)
+			err := syft.GenerateSBOM(config.SyftDownloadURL, dockerConfigDir, execRunner, fileUtils, httpClient, commonPipelineEnvironment.container.registryURL, commonPipelineEnvironment.container.imageNameTags)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 
 		if config.CreateBuildArtifactsMetadata {
 			err := createDockerBuildArtifactMetadata(commonPipelineEnvironment.container.imageNameTags, commonPipelineEnvironment)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
-		return nil
+		return images, nil
 
 	case config.MultipleImages != nil:
 		log.Entry().Debugf("multipleImages build activated")
 		parsedMultipleImages, err := parseMultipleImages(config.MultipleImages)
 		if err != nil {
 			log.SetErrorCategory(log.ErrorConfiguration)
-			return fmt.Errorf("failed to parse multipleImages param: %w", err)
+			return nil, fmt.Errorf("failed to parse multipleImages param: %w", err)
 		}
 
 		for _, entry := range parsedMultipleImages {
 			switch {
 			case entry.ContextSubPath == "":
-				return fmt.Errorf("multipleImages: empty contextSubPath")
+				return nil, fmt.Errorf("multipleImages: empty contextSubPath")
 			case entry.ContainerImageName != "":
 				containerRegistry, err := docker.ContainerRegistryFromURL(config.ContainerRegistryURL)
 				if err != nil {
 					log.SetErrorCategory(log.ErrorConfiguration)
-					return fmt.Errorf("multipleImages: failed to read registry url %v: %w", config.ContainerRegistryURL, err)
+					return nil, fmt.Errorf("multipleImages: failed to read registry url %v: %w", config.ContainerRegistryURL, err)
 				}
 
 				if entry.ContainerImageTag == "" {
 					if config.ContainerImageTag == "" {
-						return fmt.Errorf("both multipleImages containerImageTag and config.containerImageTag are empty")
+						return nil, fmt.Errorf("both multipleImages containerImageTag and config.containerImageTag are empty")
 					}
 					entry.ContainerImageTag = config.ContainerImageTag
 				}
@@ -239,9 +314,11 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 					dockerfilePath = entry.DockerfilePath
 				}
 
-				if err = runKaniko(config, dockerfilePath, buildOptions, execRunner, fileUtils, commonPipelineEnvironment); err != nil {
-					return fmt.Errorf("multipleImages: failed to build image '%v' using '%v': %w", entry.ContainerImageName, config.DockerfilePath, err)
+				pushed, err := runKaniko(config, dockerfilePath, buildOptions, execRunner, fileUtils, commonPipelineEnvironment, dockerConfigDir)
+				if err != nil {
+					return nil, fmt.Errorf("multipleImages: failed to build image '%v' using '%v': %w", entry.ContainerImageName, config.DockerfilePath, err)
 				}
+				images = append(images, pushed...)
 
 				commonPipelineEnvironment.container.imageNameTags = append(commonPipelineEnvironment.container.imageNameTags, containerImageNameAndTag)
 				commonPipelineEnvironment.container.imageNames = append(commonPipelineEnvironment.container.imageNames, entry.ContainerImageName)
@@ -250,12 +327,12 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 				containerImageName, err := docker.ContainerImageNameFromImage(entry.ContainerImage)
 				if err != nil {
 					log.SetErrorCategory(log.ErrorConfiguration)
-					return fmt.Errorf("invalid name part in image %v: %w", entry.ContainerImage, err)
+					return nil, fmt.Errorf("invalid name part in image %v: %w", entry.ContainerImage, err)
 				}
 				containerImageNameTag, err := docker.ContainerImageNameTagFromImage(entry.ContainerImage)
 				if err != nil {
 					log.SetErrorCategory(log.ErrorConfiguration)
-					return fmt.Errorf("invalid tag part in image %v: %w", entry.ContainerImage, err)
+					return nil, fmt.Errorf("invalid tag part in image %v: %w", entry.ContainerImage, err)
 				}
 
 				log.Entry().Debugf("multipleImages: image build '%v'", containerImageName)
@@ -270,14 +347,16 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 					dockerfilePath = entry.DockerfilePath
 				}
 
-				if err = runKaniko(config, dockerfilePath, buildOptions, execRunner, fileUtils, commonPipelineEnvironment); err != nil {
-					return fmt.Errorf("multipleImages: failed to build image '%v' using '%v': %w", containerImageName, config.DockerfilePath, err)
+				pushed, err := runKaniko(config, dockerfilePath, buildOptions, execRunner, fileUtils, commonPipelineEnvironment, dockerConfigDir)
+				if err != nil {
+					return nil, fmt.Errorf("multipleImages: failed to build image '%v' using '%v': %w", containerImageName, config.DockerfilePath, err)
 				}
+				images = append(images, pushed...)
 
 				commonPipelineEnvironment.container.imageNameTags = append(commonPipelineEnvironment.container.imageNameTags, containerImageNameTag)
 				commonPipelineEnvironment.container.imageNames = append(commonPipelineEnvironment.container.imageNames, containerImageName)
 			default:
-				return fmt.Errorf("multipleImages: either containerImageName or containerImage must be filled")
+				return nil, fmt.Errorf("multipleImages: either containerImageName or containerImage must be filled")
 			}
 		}
 
@@ -291,19 +370,19 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 
 		if config.CreateBOM {
 			// Syft for multi image, generates bom-docker-(1/2/3).xml
-			err := syft.GenerateSBOM(config.SyftDownloadURL, "/kaniko/.docker", execRunner, fileUtils, httpClient, commonPipelineEnvironment.container.registryURL, commonPipelineEnvironment.container.imageNameTags)
+			err := syft.GenerateSBOM(config.SyftDownloadURL, dockerConfigDir, execRunner, fileUtils, httpClient, commonPipelineEnvironment.container.registryURL, commonPipelineEnvironment.container.imageNameTags)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 
 		if config.CreateBuildArtifactsMetadata {
 			err := createDockerBuildArtifactMetadata(commonPipelineEnvironment.container.imageNameTags, commonPipelineEnvironment)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
-		return nil
+		return images, nil
 
 	case slices.Contains(config.BuildOptions, "--destination"):
 		log.Entry().Infof("Running Kaniko build with destination defined via buildOptions: %v", config.BuildOptions)
@@ -315,7 +394,7 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 				containerRegistry, err := docker.ContainerRegistryFromImage(destination)
 				if err != nil {
 					log.SetErrorCategory(log.ErrorConfiguration)
-					return fmt.Errorf("invalid registry part in image %v: %w", destination, err)
+					return nil, fmt.Errorf("invalid registry part in image %v: %w", destination, err)
 				}
 				if commonPipelineEnvironment.container.registryURL == "" {
 					commonPipelineEnvironment.container.registryURL = fmt.Sprintf("https://%v", containerRegistry)
@@ -339,7 +418,7 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 		containerRegistry, err := docker.ContainerRegistryFromURL(config.ContainerRegistryURL)
 		if err != nil {
 			log.SetErrorCategory(log.ErrorConfiguration)
-			return fmt.Errorf("failed to read registry url %v: %w", config.ContainerRegistryURL, err)
+			return nil, fmt.Errorf("failed to read registry url %v: %w", config.ContainerRegistryURL, err)
 		}
 
 		// Docker image tags don't allow plus signs in tags, thus replacing with dash
@@ -358,7 +437,7 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 		containerRegistry, err := docker.ContainerRegistryFromImage(config.ContainerImage)
 		if err != nil {
 			log.SetErrorCategory(log.ErrorConfiguration)
-			return fmt.Errorf("invalid registry part in image %v: %w", config.ContainerImage, err)
+			return nil, fmt.Errorf("invalid registry part in image %v: %w", config.ContainerImage, err)
 		}
 
 		// errors are already caught with previous call to docker.ContainerRegistryFromImage
@@ -374,31 +453,37 @@ func runKanikoExecute(config *kanikoExecuteOptions, telemetryData *telemetry.Cus
 		config.BuildOptions = append(config.BuildOptions, "--no-push")
 	}
 
-	if err = runKaniko(config, config.DockerfilePath, config.BuildOptions, execRunner, fileUtils, commonPipelineEnvironment); err != nil {
-		return err
+	pushed, err := runKaniko(config, config.DockerfilePath, config.BuildOptions, execRunner, fileUtils, commonPipelineEnvironment, dockerConfigDir)
+	if err != nil {
+		return nil, err
 	}
+	images = append(images, pushed...)
 
 	if config.CreateBOM {
 		// Syft for single image, generates bom-docker-0.xml
-		err := syft.GenerateSBOM(config.SyftDownloadURL, "/kaniko/.docker", execRunner, fileUtils, httpClient, commonPipelineEnvironment.container.registryURL, commonPipelineEnvironment.container.imageNameTags)
+		err := syft.GenerateSBOM(config.SyftDownloadURL, dockerConfigDir, execRunner, fileUtils, httpClient, commonPipelineEnvironment.container.registryURL, commonPipelineEnvironment.container.imageNameTags)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 	if config.CreateBuildArtifactsMetadata {
 		err := createDockerBuildArtifactMetadata(commonPipelineEnvironment.container.imageNameTags, commonPipelineEnvironment)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return nil
+	return images, nil
 }
 
-func runKaniko(config *kanikoExecuteOptions, dockerFilepath string, buildOptions []string, execRunner command.ExecRunner, fileUtils piperutils.FileUtils, commonPipelineEnvironment *kanikoExecuteCommonPipelineEnvironment) error {
+func runKaniko(config *kanikoExecuteOptions, dockerFilepath string, buildOptions []string, execRunner command.ExecRunner, fileUtils piperutils.FileUtils, commonPipelineEnvironment *kanikoExecuteCommonPipelineEnvironment, dockerConfigDir string) ([]string, error) {
+	if config.Builder == builderBuildKit {
+		return runBuildKit(config, dockerFilepath, buildOptions, fileUtils, commonPipelineEnvironment, dockerConfigDir)
+	}
+
 	cwd, err := fileUtils.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to get current working directory: %w", err)
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
 	}
 
 	// kaniko build context needs a proper prefix, for local directory it is 'dir://'
@@ -410,11 +495,17 @@ func runKaniko(config *kanikoExecuteOptions, dockerFilepath string, buildOptions
 		kanikoOpts = append(kanikoOpts, "--registry-mirror", mirror)
 	}
 
+	builderOpts, err := kanikoBuilderOptions(config)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, err
+	}
+	kanikoOpts = append(kanikoOpts, builderOpts...)
 	kanikoOpts = append(kanikoOpts, buildOptions...)
 
 	tmpDir, err := fileUtils.TempDir("", "*-kanikoExecute")
 	if err != nil {
-		return fmt.Errorf("failed to create tmp dir for kanikoExecute: %w", err)
+		return nil, fmt.Errorf("failed to create tmp dir for kanikoExecute: %w", err)
 	}
 
 	digestFilePath := fmt.Sprintf("%s/digest.txt", tmpDir)
@@ -427,16 +518,17 @@ func runKaniko(config *kanikoExecuteOptions, dockerFilepath string, buildOptions
 		kanikoOpts = append(kanikoOpts, "--verbosity=debug")
 	}
 
+	execRunner.AppendEnv([]string{"DOCKER_CONFIG=" + dockerConfigDir})
 	err = execRunner.RunExecutable("/kaniko/executor", kanikoOpts...)
 	if err != nil {
 		log.SetErrorCategory(log.ErrorBuild)
-		return fmt.Errorf("execution of '/kaniko/executor' failed: %w", err)
+		return nil, fmt.Errorf("execution of '/kaniko/executor' failed: %w", err)
 	}
 
 	if b, err := fileUtils.FileExists(digestFilePath); err == nil && b {
 		digest, err := fileUtils.FileRead(digestFilePath)
 		if err != nil {
-			return fmt.Errorf("error while reading image digest: %w", err)
+			return nil, fmt.Errorf("error while reading image digest: %w", err)
 		}
 
 		digestStr := string(digest)
@@ -445,9 +537,133 @@ func runKaniko(config *kanikoExecuteOptions, dockerFilepath string, buildOptions
 
 		commonPipelineEnvironment.container.imageDigest = digestStr
 		commonPipelineEnvironment.container.imageDigests = append(commonPipelineEnvironment.container.imageDigests, digestStr)
+
+		if (config.SignImages || config.CreateProvenance) && !slices.Contains(buildOptions, "--no-push") {
+			return imageReferencesByDigest("", kanikoDestinations(buildOptions), []string{digestStr})
+		}
 	}
 
-	return nil
+	return nil, nil
+}
+
+// kanikoDestinations returns the images the build is pushed to
+func kanikoDestinations(buildOptions []string) []string {
+	destinations := []string{}
+	for i, option := range buildOptions {
+		if option == "--destination" && i+1 < len(buildOptions) {
+			destinations = append(destinations, buildOptions[i+1])
+		}
+	}
+	return destinations
+}
+
+// kanikoBuilderOptions maps the build arguments, the target stage and the target architecture to the flags of the kaniko executor.
+// The options which are only supported by BuildKit are rejected.
+func kanikoBuilderOptions(config *kanikoExecuteOptions) ([]string, error) {
+	opts := []string{}
+	for _, buildArg := range config.BuildArgs {
+		if !strings.Contains(buildArg, "=") {
+			return nil, fmt.Errorf("invalid build argument '%v', please use the format KEY=VALUE", buildArg)
+		}
+		opts = append(opts, "--build-arg", buildArg)
+	}
+	if len(config.TargetStage) > 0 {
+		opts = append(opts, "--target", config.TargetStage)
+	}
+	switch len(config.TargetArchitectures) {
+	case 0:
+	case 1:
+		opts = append(opts, "--custom-platform", config.TargetArchitectures[0])
+	default:
+		return nil, fmt.Errorf("multi-platform images are only supported with builder '%v', kaniko builds a single target architecture", builderBuildKit)
+	}
+	if len(config.BuildSecrets) > 0 || len(config.CacheImports) > 0 || len(config.CacheExports) > 0 {
+		return nil, fmt.Errorf("buildSecrets, cacheImports and cacheExports are only supported with builder '%v'", builderBuildKit)
+	}
+	return opts, nil
+}
+
+// runBuildKit builds the image with the Dockerfile frontend of a BuildKit daemon.
+// Destinations, the context sub path and --no-push are taken from the kaniko build options, other kaniko options are ignored.
+// The pushed images are returned referenced by digest for signing and provenance.
+func runBuildKit(config *kanikoExecuteOptions, dockerFilepath string, buildOptions []string, fileUtils piperutils.FileUtils, commonPipelineEnvironment *kanikoExecuteCommonPipelineEnvironment, dockerConfigDir string) ([]string, error) {
+	cwd, err := fileUtils.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	contextDir := cwd
+	destinations := []string{}
+	push := true
+	for i := 0; i < len(buildOptions); i++ {
+		switch option := buildOptions[i]; option {
+		case "--destination", "--context-sub-path":
+			if i+1 >= len(buildOptions) {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return nil, fmt.Errorf("missing value of build option '%v'", option)
+			}
+			i++
+			if option == "--destination" {
+				destinations = append(destinations, buildOptions[i])
+			} else {
+				contextDir = filepath.Join(cwd, buildOptions[i])
+			}
+		case "--no-push":
+			push = false
+		default:
+			log.Entry().Debugf("build option '%v' is not supported by BuildKit and is ignored", option)
+		}
+	}
+	if len(config.RegistryMirrors) > 0 {
+		log.Entry().Warning("registryMirrors are not applied to the BuildKit daemon, mirrors have to be configured in the daemon configuration")
+	}
+
+	if !filepath.IsAbs(dockerFilepath) {
+		dockerFilepath = filepath.Join(cwd, dockerFilepath)
+	}
+	dockerConfig, err := fileUtils.FileRead(filepath.Join(dockerConfigDir, "config.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Docker config: %w", err)
+	}
+
+	options := &buildkit.BuildOptions{
+		Address:      config.BuildkitAddress,
+		ContextDir:   contextDir,
+		Dockerfile:   dockerFilepath,
+		Target:       config.TargetStage,
+		BuildArgs:    config.BuildArgs,
+		Platforms:    config.TargetArchitectures,
+		Secrets:      config.BuildSecrets,
+		CacheImports: config.CacheImports,
+		CacheExports: config.CacheExports,
+		Destinations: destinations,
+		Push:         push,
+		DockerConfig: dockerConfig,
+	}
+	for _, buildArg := range options.BuildArgs {
+		if !strings.Contains(buildArg, "=") {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, fmt.Errorf("invalid build argument '%v', please use the format KEY=VALUE", buildArg)
+		}
+	}
+
+	digest, err := buildKitBuild(context.Background(), options, log.Writer())
+	if err != nil {
+		log.SetErrorCategory(log.ErrorBuild)
+		return nil, err
+	}
+
+	if config.ReadImageDigest && push && len(destinations) > 0 {
+		log.Entry().Debugf("image digest: %s", digest)
+
+		commonPipelineEnvironment.container.imageDigest = digest
+		commonPipelineEnvironment.container.imageDigests = append(commonPipelineEnvironment.container.imageDigests, digest)
+		if config.SignImages || config.CreateProvenance {
+			return imageReferencesByDigest("", destinations, []string{digest})
+		}
+	}
+
+	return nil, nil
 }
 
 func createDockerBuildArtifactMetadata(containerImageNameTags []string, commonPipelineEnvironment *kanikoExecuteCommonPipelineEnvironment) error {
@@ -39,12 +39,25 @@ type kanikoExecuteOptions struct {
 	ContainerRegistryPassword        string                   `json:"containerRegistryPassword,omitempty"`
 	CustomTLSCertificateLinks        []string                 `json:"customTlsCertificateLinks,omitempty"`
 	DockerConfigJSON                 string                   `json:"dockerConfigJSON,omitempty"`
+	DockerConfigDirectory            string                   `json:"dockerConfigDirectory,omitempty"`
 	DockerfilePath                   string                   `json:"dockerfilePath,omitempty"`
 	ReadImageDigest                  bool                     `json:"readImageDigest,omitempty"`
 	CreateBOM                        bool                     `json:"createBOM,omitempty"`
+	CreateProvenance                 bool                     `json:"createProvenance,omitempty"`
 	SyftDownloadURL                  string                   `json:"syftDownloadUrl,omitempty"`
 	CreateBuildArtifactsMetadata     bool                     `json:"createBuildArtifactsMetadata,omitempty"`
 	RegistryMirrors                  []string                 `json:"registryMirrors,omitempty"`
+	Builder                          string                   `json:"builder,omitempty" validate:"possible-values=kaniko buildkit"`
+	BuildkitAddress                  string                   `json:"buildkitAddress,omitempty"`
+	BuildArgs                        []string                 `json:"buildArgs,omitempty"`
+	TargetStage                      string                   `json:"targetStage,omitempty"`
+	BuildSecrets                     []string                 `json:"buildSecrets,omitempty"`
+	CacheImports                     []string                 `json:"cacheImports,omitempty"`
+	CacheExports                     []string                 `json:"cacheExports,omitempty"`
+	TargetArchitectures              []string                 `json:"targetArchitectures,omitempty"`
+	SignImages                       bool                     `json:"signImages,omitempty"`
+	SigningKey                       string                   `json:"signingKey,omitempty"`
+	SigningKeyPassword               string                   `json:"signingKeyPassword,omitempty"`
 }
 
 type kanikoExecuteCommonPipelineEnvironment struct {
@@ -147,6 +160,24 @@ func KanikoExecuteCommand() *cobra.Command {
 
 For building one container image the step expects that one of the containerImage, containerImageName or --destination (via buildOptions) is set.
 
+### Building with BuildKit
+
+Instead of the kaniko executor the images can be built by a [BuildKit](https://github.com/moby/buildkit) daemon using [` + "`" + `builder` + "`" + `](#builder) ` + "`" + `buildkit` + "`" + `.
+The step then sends the build to the daemon at [` + "`" + `buildkitAddress` + "`" + `](#buildkitaddress), e.g. a ` + "`" + `buildkitd` + "`" + ` sidecar or service.
+The registry credentials are only kept in a private temporary directory during the build and are removed afterwards.
+BuildKit additionally supports secret mounts via [` + "`" + `buildSecrets` + "`" + `](#buildsecrets), the import and export of build caches via [` + "`" + `cacheImports` + "`" + `](#cacheimports) and [` + "`" + `cacheExports` + "`" + `](#cacheexports)
+as well as multi-platform images via [` + "`" + `targetArchitectures` + "`" + `](#targetarchitectures).
+
+` + "`" + `` + "`" + `` + "`" + `
+steps:
+  kanikoExecute:
+    builder: buildkit
+    buildkitAddress: tcp://buildkitd:1234
+    targetArchitectures:
+      - linux/amd64
+      - linux/arm64
+` + "`" + `` + "`" + `` + "`" + `
+
 ### Building multiple container images
 
 The step allows you to build multiple container images with one run.
@@ -240,6 +271,8 @@ Following final image names will be built:
 			}
 			log.SetStepErrors(stepErrors)
 			log.RegisterSecret(stepConfig.DockerConfigJSON)
+			log.RegisterSecret(stepConfig.SigningKey)
+			log.RegisterSecret(stepConfig.SigningKeyPassword)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -347,12 +380,25 @@ func addKanikoExecuteFlags(cmd *cobra.Command, stepConfig *kanikoExecuteOptions)
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryPassword, "containerRegistryPassword", os.Getenv("PIPER_containerRegistryPassword"), "Password of the Container registry where the image should be pushed to -  which will updated in a docker config json file. If a docker config json file is provided via parameter `dockerConfigJSON` , then the existing file will be enhanced")
 	cmd.Flags().StringSliceVar(&stepConfig.CustomTLSCertificateLinks, "customTlsCertificateLinks", []string{}, "List containing download links of custom TLS certificates. This is required to ensure trusted connections to registries with custom certificates.")
 	cmd.Flags().StringVar(&stepConfig.DockerConfigJSON, "dockerConfigJSON", os.Getenv("PIPER_dockerConfigJSON"), "Path to the file `.docker/config.json` - this is typically provided by your CI/CD system. You can find more details about the Docker credentials in the [Docker documentation](https://docs.docker.com/engine/reference/commandline/login/).")
+	cmd.Flags().StringVar(&stepConfig.DockerConfigDirectory, "dockerConfigDirectory", `/kaniko/.docker`, "Directory the Docker config with the registry credentials is written to. Kaniko reads it via the environment variable `DOCKER_CONFIG`, the images are signed with the same credentials.")
 	cmd.Flags().StringVar(&stepConfig.DockerfilePath, "dockerfilePath", `Dockerfile`, "Defines the location of the Dockerfile relative to the pipeline working directory.")
 	cmd.Flags().BoolVar(&stepConfig.ReadImageDigest, "readImageDigest", false, "")
 	cmd.Flags().BoolVar(&stepConfig.CreateBOM, "createBOM", false, "Creates the bill of materials (BOM) using Syft and stores it in a file in CycloneDX 1.4 format.")
+	cmd.Flags().BoolVar(&stepConfig.CreateProvenance, "createProvenance", false, "Creates an [in-toto](https://github.com/in-toto/attestation) statement with the [SLSA provenance](https://slsa.dev/spec/v1.0/provenance) of the build artifacts in `provenance/kanikoExecute.intoto.json`. It contains the digests of the artifacts, the invoking configuration, the source, the build run of the orchestrator and the dependencies listed in the BOM (see `createBOM`). The pushed images are the build artifacts. If `signImages` is active, the provenance is attached to the images as attestation signed with the `signingKey`.")
 	cmd.Flags().StringVar(&stepConfig.SyftDownloadURL, "syftDownloadUrl", `https://github.com/anchore/syft/releases/download/v1.44.0/syft_1.44.0_linux_amd64.tar.gz`, "Specifies the download url of the Syft Linux amd64 tar binary file. This can be found at https://github.com/anchore/syft/releases/.")
 	cmd.Flags().BoolVar(&stepConfig.CreateBuildArtifactsMetadata, "createBuildArtifactsMetadata", false, "metadata about the artifacts that are build and published , this metadata is generally used by steps downstream in the pipeline")
 	cmd.Flags().StringSliceVar(&stepConfig.RegistryMirrors, "registryMirrors", []string{}, "List of registry mirrors to use instead of default index.docker.io. Format examples, mirror.gcr.io, 127.0.0.1, 192.168.0.1:5000, mycompany-docker-virtual.jfrog.io")
+	cmd.Flags().StringVar(&stepConfig.Builder, "builder", `kaniko`, "Defines the builder of the images. With `buildkit` the images are built by a [BuildKit](https://github.com/moby/buildkit) daemon instead of the kaniko executor, see [`buildkitAddress`](#buildkitaddress).\nThe destinations, `--context-sub-path` and `--no-push` of the [`buildOptions`](#buildoptions) are applied to BuildKit builds as well, other kaniko options are ignored.\n")
+	cmd.Flags().StringVar(&stepConfig.BuildkitAddress, "buildkitAddress", `unix:///run/buildkit/buildkitd.sock`, "Address of the BuildKit daemon used with `builder` `buildkit`, e.g. `tcp://buildkitd:1234` or `unix:///run/buildkit/buildkitd.sock`.")
+	cmd.Flags().StringSliceVar(&stepConfig.BuildArgs, "buildArgs", []string{}, "Build arguments in the form `KEY=VALUE` passed to the Dockerfile.")
+	cmd.Flags().StringVar(&stepConfig.TargetStage, "targetStage", os.Getenv("PIPER_targetStage"), "Stage of a multi-stage Dockerfile which is built.")
+	cmd.Flags().StringSliceVar(&stepConfig.BuildSecrets, "buildSecrets", []string{}, "Secrets which are mounted into `RUN --mount=type=secret` instructions, in the format of `buildctl --secret`. Only supported with `builder` `buildkit`.\n\n```yaml\nbuildSecrets:\n  - id=npmrc,src=.npmrc\n  - id=token,env=ARTIFACTORY_TOKEN\n```\n")
+	cmd.Flags().StringSliceVar(&stepConfig.CacheImports, "cacheImports", []string{}, "Build caches which are imported, in the format of `buildctl --import-cache`, e.g. `type=registry,ref=my.registry.com/my-image:buildcache`. Only supported with `builder` `buildkit`.")
+	cmd.Flags().StringSliceVar(&stepConfig.CacheExports, "cacheExports", []string{}, "Build caches which are exported, in the format of `buildctl --export-cache`, e.g. `type=registry,ref=my.registry.com/my-image:buildcache,mode=max`. Only supported with `builder` `buildkit`.")
+	cmd.Flags().StringSliceVar(&stepConfig.TargetArchitectures, "targetArchitectures", []string{}, "Platforms of a multi-platform image in the form os/arch[/variant], e.g. `linux/amd64`. With `builder` `buildkit` the image is pushed as image index, kaniko only builds a single target architecture.")
+	cmd.Flags().BoolVar(&stepConfig.SignImages, "signImages", false, "Signs the pushed images with the `signingKey`. The signatures are stored next to the images in the format of [cosign](https://github.com/sigstore/cosign) and can be verified with the step `imageVerifySignature`.")
+	cmd.Flags().StringVar(&stepConfig.SigningKey, "signingKey", os.Getenv("PIPER_signingKey"), "Path to the PEM encoded private key used for signing images, e.g. a key pair created with `cosign generate-key-pair`.")
+	cmd.Flags().StringVar(&stepConfig.SigningKeyPassword, "signingKeyPassword", os.Getenv("PIPER_signingKeyPassword"), "Password of the encrypted `signingKey`.")
 
 }
 
@@ -368,6 +414,8 @@ func kanikoExecuteMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
 					{Name: "dockerConfigJsonCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing Docker config.json (with registry credential(s)). You can create it like explained in the [protocodeExecuteScan Prerequisites section](https://www.project-piper.io/steps/protecodeExecuteScan/#prerequisites).", Type: "jenkins"},
+					{Name: "signingKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the private key used for signing images.", Type: "jenkins"},
+					{Name: "signingKeyPasswordCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the password of the private key used for signing images.", Type: "jenkins"},
 				},
 				Parameters: []config.StepParameters{
 					{
@@ -549,6 +597,15 @@ func kanikoExecuteMetadata() config.StepData {
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_dockerConfigJSON"),
 					},
+					{
+						Name:        "dockerConfigDirectory",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `/kaniko/.docker`,
+					},
 					{
 						Name:        "dockerfilePath",
 						ResourceRef: []config.ResourceReference{},
@@ -576,6 +633,15 @@ func kanikoExecuteMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "createProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 					{
 						Name:        "syftDownloadUrl",
 						ResourceRef: []config.ResourceReference{},
@@ -603,10 +669,132 @@ func kanikoExecuteMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     []string{},
 					},
+					{
+						Name:        "builder",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `kaniko`,
+					},
+					{
+						Name:        "buildkitAddress",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `unix:///run/buildkit/buildkitd.sock`,
+					},
+					{
+						Name:        "buildArgs",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "targetStage",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_targetStage"),
+					},
+					{
+						Name:        "buildSecrets",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "cacheImports",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "cacheExports",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "targetArchitectures",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "signImages",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "signingKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "signingKeyFileVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "image-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_signingKey"),
+					},
+					{
+						Name: "signingKeyPassword",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "signingKeyPasswordCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "signingKeyPasswordVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "image-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_signingKeyPassword"),
+					},
 				},
 			},
 			Containers: []config.Container{
-				{Image: "gcr.io/kaniko-project/executor:debug", EnvVars: []config.EnvVar{{Name: "container", Value: "docker"}}, Options: []config.Option{{Name: "-u", Value: "0"}, {Name: "--entrypoint", Value: ""}}},
+				{Image: "gcr.io/kaniko-project/executor:debug", EnvVars: []config.EnvVar{{Name: "container", Value: "docker"}}, Options: []config.Option{{Name: "-u", Value: "0"}, {Name: "--entrypoint", Value: ""}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "builder", Value: "kaniko"}}}}},
+				{Image: "moby/buildkit:v0.32.2", Options: []config.Option{{Name: "--entrypoint", Value: ""}}, Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "builder", Value: "buildkit"}}}}},
 			},
 			Outputs: config.StepOutputs{
 				Resources: []config.StepResources{
@@ -5,6 +5,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/buildkit"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
@@ -79,7 +81,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils.AddFile("path/to/docker/config.json", []byte(`{"auths":{"custom":"test"}}`))
 		fileUtils.AddFile("/kaniko/ssl/certs/ca-certificates.crt", []byte(``))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.NoError(t, err)
 
@@ -130,7 +132,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils.AddFile("/kaniko/ssl/certs/ca-certificates.crt", []byte(``))
 		fileUtils.AddFile("/tmp/*-kanikoExecutetest/digest.txt", []byte(`sha256:468dd1253cc9f498fc600454bb8af96d880fec3f9f737e7057692adfe9f7d5b0`))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.NoError(t, err)
 
@@ -158,6 +160,32 @@ func TestRunKanikoExecute(t *testing.T) {
 		assert.Equal(t, []string{"sha256:468dd1253cc9f498fc600454bb8af96d880fec3f9f737e7057692adfe9f7d5b0"}, commonPipelineEnvironment.container.imageDigests)
 	})
 
+	t.Run("success case - pushed images of all destinations for signing", func(t *testing.T) {
+		config := &kanikoExecuteOptions{
+			BuildOptions:          []string{"--destination", "my.registry.com/app:1.0.0", "--destination", "other.registry.com:5000/team/app:latest"},
+			DockerfilePath:        "Dockerfile",
+			DockerConfigDirectory: "/home/piper/.docker",
+			ReadImageDigest:       true,
+			SignImages:            true,
+		}
+
+		execRunner := &mock.ExecMockRunner{}
+		commonPipelineEnvironment := kanikoExecuteCommonPipelineEnvironment{}
+		fileUtils := &mock.FilesMock{}
+		fileUtils.AddFile("/tmp/*-kanikoExecutetest/digest.txt", []byte(`sha256:468dd1253cc9f498fc600454bb8af96d880fec3f9f737e7057692adfe9f7d5b0`))
+
+		images, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, nil, fileUtils, config.DockerConfigDirectory)
+
+		assert.NoError(t, err)
+		assert.True(t, fileUtils.HasFile("/home/piper/.docker/config.json"))
+		assert.Contains(t, execRunner.Env, "DOCKER_CONFIG=/home/piper/.docker")
+		assert.Equal(t, "https://my.registry.com", commonPipelineEnvironment.container.registryURL)
+		assert.Equal(t, []string{
+			"my.registry.com/app@sha256:468dd1253cc9f498fc600454bb8af96d880fec3f9f737e7057692adfe9f7d5b0",
+			"other.registry.com:5000/team/app@sha256:468dd1253cc9f498fc600454bb8af96d880fec3f9f737e7057692adfe9f7d5b0",
+		}, images)
+	})
+
 	t.Run("success case - image params", func(t *testing.T) {
 		config := &kanikoExecuteOptions{
 			BuildOptions:                []string{"--skip-tls-verify-pull"},
@@ -180,7 +208,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils.AddFile("path/to/docker/config.json", []byte(`{"auths":{"custom":"test"}}`))
 		fileUtils.AddFile("/kaniko/ssl/certs/ca-certificates.crt", []byte(``))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.NoError(t, err)
 
@@ -224,7 +252,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils.AddFile("path/to/docker/config.json", []byte(`{"auths":{"custom":"test"}}`))
 		fileUtils.AddFile("/kaniko/ssl/certs/ca-certificates.crt", []byte(``))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.NoError(t, err)
 
@@ -269,7 +297,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils.AddFile("path/to/docker/config.json", []byte(``))
 		fileUtils.FileReadErrors = map[string]error{"/kaniko/ssl/certs/ca-certificates.crt": fmt.Errorf("read error")}
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.NoErrorf(t, err, "failed to update certificates: failed to load file '/kaniko/ssl/certs/ca-certificates.crt': read error")
 	})
@@ -291,7 +319,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils := &mock.FilesMock{}
 		fileUtils.AddFile("/kaniko/ssl/certs/ca-certificates.crt", []byte(``))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.NoError(t, err)
 
@@ -323,7 +351,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils.AddFile("path/to/docker/config.json", []byte(`{"auths":{"custom":"test"}}`))
 		fileUtils.AddFile("/kaniko/ssl/certs/ca-certificates.crt", []byte(``))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.NoError(t, err)
 		cwd, _ := fileUtils.Getwd()
@@ -354,7 +382,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		client := &piperhttp.Client{}
 		client.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
 
-		err = runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, client, fileUtils)
+		_, err = runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, client, fileUtils, kanikoDockerConfigDir)
 		assert.NoError(t, err)
 		assert.Equal(t, "/kaniko/executor", execRunner.Calls[1].Exec)
 		assert.Equal(t, "myImage:tag", commonPipelineEnvironment.container.imageNameTag)
@@ -380,7 +408,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils.AddFile("sub1/Dockerfile", []byte("some content"))
 		fileUtils.AddFile("sub2/Dockerfile", []byte("some content"))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, nil, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, nil, fileUtils, kanikoDockerConfigDir)
 
 		assert.NoError(t, err)
 
@@ -437,7 +465,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils.AddFile("sub1/Dockerfile", []byte("some content"))
 		fileUtils.AddFile("sub2/Dockerfile", []byte("some content"))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, nil, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, nil, fileUtils, kanikoDockerConfigDir)
 
 		assert.NoError(t, err)
 
@@ -497,7 +525,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		client := &piperhttp.Client{}
 		client.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
 
-		err = runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, client, fileUtils)
+		_, err = runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, client, fileUtils, kanikoDockerConfigDir)
 		assert.NoError(t, err)
 
 		assert.Equal(t, 6, len(execRunner.Calls))
@@ -566,7 +594,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils.AddFile("path/to/docker/config.json", []byte(`{"auths": {"dummyUrl": {"auth": "XXXXXXX"}}}`))
 		fileUtils.AddFile("/kaniko/ssl/certs/ca-certificates.crt", []byte(``))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.NoError(t, err)
 
@@ -601,7 +629,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils := &mock.FilesMock{}
 		fileUtils.AddFile("/kaniko/ssl/certs/ca-certificates.crt", []byte(``))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.NoError(t, err)
 
@@ -652,7 +680,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		client := &piperhttp.Client{}
 		client.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
 
-		err = runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, client, fileUtils)
+		_, err = runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, client, fileUtils, kanikoDockerConfigDir)
 		assert.NoError(t, err)
 
 		assert.Equal(t, 4, len(execRunner.Calls))
@@ -704,7 +732,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		execRunner := &mock.ExecMockRunner{}
 		fileUtils := &mock.FilesMock{}
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &cpe, execRunner, nil, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &cpe, execRunner, nil, fileUtils, kanikoDockerConfigDir)
 
 		assert.Error(t, err)
 		assert.Contains(t, fmt.Sprint(err), "failed to identify image list for multi image build")
@@ -725,7 +753,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils := &mock.FilesMock{}
 		fileUtils.AddFile("Dockerfile", []byte("some content"))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &cpe, execRunner, nil, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &cpe, execRunner, nil, fileUtils, kanikoDockerConfigDir)
 
 		assert.Error(t, err)
 		assert.Contains(t, fmt.Sprint(err), "no docker files to process, please check exclude list")
@@ -747,7 +775,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils := &mock.FilesMock{}
 		fileUtils.AddFile("Dockerfile", []byte("some content"))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &cpe, execRunner, nil, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &cpe, execRunner, nil, fileUtils, kanikoDockerConfigDir)
 
 		assert.Error(t, err)
 		assert.Contains(t, fmt.Sprint(err), "failed to build image")
@@ -765,7 +793,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		certClient := &kanikoMockClient{}
 		fileUtils := &mock.FilesMock{}
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.EqualError(t, err, "failed to initialize Kaniko container: rm failed")
 	})
@@ -780,7 +808,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		certClient := &kanikoMockClient{}
 		fileUtils := &mock.FilesMock{}
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.EqualError(t, err, "execution of '/kaniko/executor' failed: kaniko run failed")
 	})
@@ -803,7 +831,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils := &mock.FilesMock{}
 		fileUtils.FileReadErrors = map[string]error{"/kaniko/ssl/certs/ca-certificates.crt": fmt.Errorf("read error")}
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.EqualError(t, err, "failed to update certificates: failed to load file '/kaniko/ssl/certs/ca-certificates.crt': read error")
 	})
@@ -819,7 +847,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils := &mock.FilesMock{}
 		fileUtils.FileReadErrors = map[string]error{"path/to/docker/config.json": fmt.Errorf("read error")}
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.EqualError(t, err, "failed to read existing docker config json at 'path/to/docker/config.json': read error")
 	})
@@ -836,7 +864,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils.AddFile("path/to/docker/config.json", []byte(`{"auths":{"custom":"test"}}`))
 		fileUtils.FileWriteErrors = map[string]error{"/kaniko/.docker/config.json": fmt.Errorf("write error")}
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, certClient, fileUtils, kanikoDockerConfigDir)
 
 		assert.EqualError(t, err, "failed to write file '/kaniko/.docker/config.json': write error")
 	})
@@ -858,7 +886,7 @@ func TestRunKanikoExecute(t *testing.T) {
 		fileUtils := &mock.FilesMock{}
 		fileUtils.AddFile("Dockerfile", []byte("some content"))
 
-		err := runKanikoExecute(config, &telemetry.CustomData{}, &cpe, execRunner, nil, fileUtils)
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &cpe, execRunner, nil, fileUtils, kanikoDockerConfigDir)
 
 		assert.Error(t, err)
 		assert.Contains(t, fmt.Sprint(err), "multipleImages: empty contextSubPath")
@@ -974,3 +1002,226 @@ func TestFindImageNameTagInPurl(t *testing.T) {
 		assert.Equal(t, "registry.example.com/namespace/subnamespace/image:tag", result)
 	})
 }
+
+func TestRunKanikoExecuteBuildKit(t *testing.T) {
+	openFileBak := configOptions.OpenFile
+	buildKitBuildBak := buildKitBuild
+	defer func() {
+		configOptions.OpenFile = openFileBak
+		buildKitBuild = buildKitBuildBak
+	}()
+
+	configOptions.OpenFile = configOpenFileMock
+
+	var builds []*buildkit.BuildOptions
+	buildKitBuild = func(ctx context.Context, options *buildkit.BuildOptions, progress io.Writer) (string, error) {
+		builds = append(builds, options)
+		return "sha256:468dd1253cc9f498fc600454bb8af96d880fec3f9f737e7057692adfe9f7d5b0", nil
+	}
+
+	t.Run("success case - single image", func(t *testing.T) {
+		builds = nil
+		config := &kanikoExecuteOptions{
+			Builder:             "buildkit",
+			BuildkitAddress:     "tcp://buildkitd:1234",
+			BuildOptions:        []string{"--skip-tls-verify-pull", "--ignore-path=/workspace"},
+			ContainerImage:      "my.registry.com/myImage:tag",
+			DockerfilePath:      "docker/Dockerfile",
+			DockerConfigJSON:    "path/to/docker/config.json",
+			BuildArgs:           []string{"VERSION=1.0.0"},
+			TargetStage:         "runtime",
+			BuildSecrets:        []string{"id=npmrc,src=.npmrc"},
+			CacheImports:        []string{"type=registry,ref=my.registry.com/myImage:buildcache"},
+			CacheExports:        []string{"type=registry,ref=my.registry.com/myImage:buildcache,mode=max"},
+			TargetArchitectures: []string{"linux/amd64", "linux/arm64"},
+			ReadImageDigest:     true,
+		}
+
+		execRunner := &mock.ExecMockRunner{}
+		commonPipelineEnvironment := kanikoExecuteCommonPipelineEnvironment{}
+
+		fileUtils := &mock.FilesMock{}
+		fileUtils.AddFile("path/to/docker/config.json", []byte(`{"auths":{"custom":"test"}}`))
+
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, execRunner, nil, fileUtils, "/tmp/buildkit-docker-config-1")
+
+		assert.NoError(t, err)
+
+		c, err := fileUtils.FileRead("/tmp/buildkit-docker-config-1/config.json")
+		assert.NoError(t, err)
+		assert.Equal(t, `{"auths":{"custom":"test"}}`, string(c))
+
+		assert.Empty(t, execRunner.Calls)
+		cwd, _ := fileUtils.Getwd()
+		assert.Equal(t, []*buildkit.BuildOptions{{
+			Address:      "tcp://buildkitd:1234",
+			ContextDir:   cwd,
+			Dockerfile:   filepath.Join(cwd, "docker", "Dockerfile"),
+			Target:       "runtime",
+			BuildArgs:    []string{"VERSION=1.0.0"},
+			Platforms:    []string{"linux/amd64", "linux/arm64"},
+			Secrets:      []string{"id=npmrc,src=.npmrc"},
+			CacheImports: []string{"type=registry,ref=my.registry.com/myImage:buildcache"},
+			CacheExports: []string{"type=registry,ref=my.registry.com/myImage:buildcache,mode=max"},
+			Destinations: []string{"my.registry.com/myImage:tag"},
+			Push:         true,
+			DockerConfig: []byte(`{"auths":{"custom":"test"}}`),
+		}}, builds)
+
+		assert.Equal(t, "https://my.registry.com", commonPipelineEnvironment.container.registryURL)
+		assert.Equal(t, []string{"myImage:tag"}, commonPipelineEnvironment.container.imageNameTags)
+		assert.Equal(t, "sha256:468dd1253cc9f498fc600454bb8af96d880fec3f9f737e7057692adfe9f7d5b0", commonPipelineEnvironment.container.imageDigest)
+		assert.Equal(t, []string{"sha256:468dd1253cc9f498fc600454bb8af96d880fec3f9f737e7057692adfe9f7d5b0"}, commonPipelineEnvironment.container.imageDigests)
+	})
+
+	t.Run("success case - pushed images for provenance", func(t *testing.T) {
+		builds = nil
+		config := &kanikoExecuteOptions{
+			Builder:          "buildkit",
+			BuildkitAddress:  "tcp://buildkitd:1234",
+			BuildOptions:     []string{"--destination", "my.registry.com/app:1.0.0", "--destination", "other.registry.com/app:1.0.0"},
+			DockerfilePath:   "Dockerfile",
+			ReadImageDigest:  true,
+			CreateProvenance: true,
+		}
+
+		images, err := runKanikoExecute(config, &telemetry.CustomData{}, &kanikoExecuteCommonPipelineEnvironment{}, &mock.ExecMockRunner{}, nil, &mock.FilesMock{}, "/tmp/buildkit-docker-config-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"my.registry.com/app@sha256:468dd1253cc9f498fc600454bb8af96d880fec3f9f737e7057692adfe9f7d5b0",
+			"other.registry.com/app@sha256:468dd1253cc9f498fc600454bb8af96d880fec3f9f737e7057692adfe9f7d5b0",
+		}, images)
+	})
+
+	t.Run("success case - multi image build", func(t *testing.T) {
+		builds = nil
+		config := &kanikoExecuteOptions{
+			Builder:                  "buildkit",
+			BuildkitAddress:          "unix:///run/buildkit/buildkitd.sock",
+			ContainerImageName:       "myImage",
+			ContainerImageTag:        "myTag",
+			ContainerRegistryURL:     "https://my.registry.com:50000",
+			ContainerMultiImageBuild: true,
+		}
+
+		commonPipelineEnvironment := kanikoExecuteCommonPipelineEnvironment{}
+
+		fileUtils := &mock.FilesMock{}
+		fileUtils.AddFile("Dockerfile", []byte("some content"))
+		fileUtils.AddFile("sub1/Dockerfile", []byte("some content"))
+
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, &mock.ExecMockRunner{}, nil, fileUtils, "/tmp/buildkit-docker-config-1")
+
+		assert.NoError(t, err)
+
+		cwd, _ := fileUtils.Getwd()
+		destinations := map[string][]string{}
+		for _, build := range builds {
+			destinations[build.Dockerfile] = build.Destinations
+		}
+		assert.Equal(t, map[string][]string{
+			filepath.Join(cwd, "Dockerfile"):         {"my.registry.com:50000/myImage:myTag"},
+			filepath.Join(cwd, "sub1", "Dockerfile"): {"my.registry.com:50000/myImage-sub1:myTag"},
+		}, destinations)
+		assert.ElementsMatch(t, []string{"myImage:myTag", "myImage-sub1:myTag"}, commonPipelineEnvironment.container.imageNameTags)
+		assert.Empty(t, commonPipelineEnvironment.container.imageDigests)
+	})
+
+	t.Run("success case - multiple images with context sub path", func(t *testing.T) {
+		builds = nil
+		config := &kanikoExecuteOptions{
+			Builder:              "buildkit",
+			BuildkitAddress:      "unix:///run/buildkit/buildkitd.sock",
+			ContainerRegistryURL: "https://my.registry.com",
+			ContainerImageName:   "myImage",
+			ContainerImageTag:    "myTag",
+			DockerfilePath:       "Dockerfile",
+			MultipleImages:       []map[string]interface{}{{"contextSubPath": "app", "containerImageName": "myApp"}},
+		}
+
+		fileUtils := &mock.FilesMock{}
+
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &kanikoExecuteCommonPipelineEnvironment{}, &mock.ExecMockRunner{}, nil, fileUtils, "/tmp/buildkit-docker-config-1")
+
+		assert.NoError(t, err)
+		cwd, _ := fileUtils.Getwd()
+		assert.Equal(t, filepath.Join(cwd, "app"), builds[0].ContextDir)
+		assert.Equal(t, []string{"my.registry.com/myApp:myTag"}, builds[0].Destinations)
+	})
+
+	t.Run("success case - no push", func(t *testing.T) {
+		builds = nil
+		config := &kanikoExecuteOptions{
+			Builder:         "buildkit",
+			BuildkitAddress: "unix:///run/buildkit/buildkitd.sock",
+			DockerfilePath:  "Dockerfile",
+			ReadImageDigest: true,
+		}
+
+		commonPipelineEnvironment := kanikoExecuteCommonPipelineEnvironment{}
+
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &commonPipelineEnvironment, &mock.ExecMockRunner{}, nil, &mock.FilesMock{}, "/tmp/buildkit-docker-config-1")
+
+		assert.NoError(t, err)
+		assert.Empty(t, builds[0].Destinations)
+		assert.Empty(t, commonPipelineEnvironment.container.imageDigests)
+	})
+
+	t.Run("error case - invalid build argument", func(t *testing.T) {
+		config := &kanikoExecuteOptions{
+			Builder:         "buildkit",
+			BuildkitAddress: "unix:///run/buildkit/buildkitd.sock",
+			DockerfilePath:  "Dockerfile",
+			BuildArgs:       []string{"VERSION"},
+		}
+
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &kanikoExecuteCommonPipelineEnvironment{}, &mock.ExecMockRunner{}, nil, &mock.FilesMock{}, "/tmp/buildkit-docker-config-1")
+
+		assert.EqualError(t, err, "invalid build argument 'VERSION', please use the format KEY=VALUE")
+	})
+
+	t.Run("error case - build failed", func(t *testing.T) {
+		buildKitBuild = func(ctx context.Context, options *buildkit.BuildOptions, progress io.Writer) (string, error) {
+			return "", fmt.Errorf("BuildKit build failed: connection refused")
+		}
+		config := &kanikoExecuteOptions{
+			Builder:         "buildkit",
+			BuildkitAddress: "unix:///run/buildkit/buildkitd.sock",
+			DockerfilePath:  "Dockerfile",
+		}
+
+		_, err := runKanikoExecute(config, &telemetry.CustomData{}, &kanikoExecuteCommonPipelineEnvironment{}, &mock.ExecMockRunner{}, nil, &mock.FilesMock{}, "/tmp/buildkit-docker-config-1")
+
+		assert.EqualError(t, err, "BuildKit build failed: connection refused")
+	})
+}
+
+func TestKanikoBuilderOptions(t *testing.T) {
+	t.Run("build arguments, target stage and target architecture", func(t *testing.T) {
+		config := &kanikoExecuteOptions{BuildArgs: []string{"VERSION=1.0.0"}, TargetStage: "runtime", TargetArchitectures: []string{"linux/arm64"}}
+
+		opts, err := kanikoBuilderOptions(config)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"--build-arg", "VERSION=1.0.0", "--target", "runtime", "--custom-platform", "linux/arm64"}, opts)
+	})
+
+	t.Run("error case - multiple target architectures", func(t *testing.T) {
+		_, err := kanikoBuilderOptions(&kanikoExecuteOptions{TargetArchitectures: []string{"linux/amd64", "linux/arm64"}})
+
+		assert.EqualError(t, err, "multi-platform images are only supported with builder 'buildkit', kaniko builds a single target architecture")
+	})
+
+	t.Run("error case - build secrets", func(t *testing.T) {
+		_, err := kanikoBuilderOptions(&kanikoExecuteOptions{BuildSecrets: []string{"id=npmrc,src=.npmrc"}})
+
+		assert.EqualError(t, err, "buildSecrets, cacheImports and cacheExports are only supported with builder 'buildkit'")
+	})
+
+	t.Run("error case - invalid build argument", func(t *testing.T) {
+		_, err := kanikoBuilderOptions(&kanikoExecuteOptions{BuildArgs: []string{"VERSION"}})
+
+		assert.EqualError(t, err, "invalid build argument 'VERSION', please use the format KEY=VALUE")
+	})
+}
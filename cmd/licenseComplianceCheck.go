@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/licensing"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type licenseComplianceCheckUtils interface {
+	piperutils.FileUtils
+}
+
+type licenseComplianceCheckUtilsBundle struct {
+	*piperutils.Files
+}
+
+func newLicenseComplianceCheckUtils() licenseComplianceCheckUtils {
+	return &licenseComplianceCheckUtilsBundle{Files: &piperutils.Files{}}
+}
+
+func licenseComplianceCheck(config licenseComplianceCheckOptions, telemetryData *telemetry.CustomData) {
+	utils := newLicenseComplianceCheckUtils()
+
+	if err := runLicenseComplianceCheck(&config, utils); err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runLicenseComplianceCheck(config *licenseComplianceCheckOptions, utils licenseComplianceCheckUtils) error {
+	policy, err := loadLicensePolicy(config, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	components, err := readSBOMComponents(config, utils)
+	if err != nil {
+		return err
+	}
+	results := policy.EvaluateComponents(components)
+
+	productName := config.ProductName
+	if len(productName) > 0 {
+		productName = path.Base(productName)
+	}
+	reports, err := licensing.WriteReports(productName, results, time.Now(), utils)
+	if err != nil {
+		return err
+	}
+	piperutils.PersistReportsAndLinks("licenseComplianceCheck", "", utils, reports, nil)
+
+	for _, result := range results {
+		if result.Decision == licensing.Allow {
+			continue
+		}
+		log.Entry().Warnf("License '%v' of component %v %v (%v): %v", result.Component.Expression, result.Component.DisplayName(), result.Component.Version, result.Component.Scope, result.Decision)
+	}
+	counts := licensing.CountDecisions(results)
+	log.Entry().Infof("%v component(s) evaluated: %v allowed, %v to be reviewed, %v denied", len(results), counts[licensing.Allow], counts[licensing.Review], counts[licensing.Deny])
+
+	if config.FailOnDenied && counts[licensing.Deny] > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v component(s) with denied licenses found", counts[licensing.Deny])
+	}
+	if config.FailOnReview && counts[licensing.Review] > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v component(s) with licenses requiring a review found", counts[licensing.Review])
+	}
+	return nil
+}
+
+func loadLicensePolicy(config *licenseComplianceCheckOptions, utils licenseComplianceCheckUtils) (*licensing.Policy, error) {
+	policy := &licensing.Policy{}
+	if len(config.PolicyFile) > 0 {
+		content, err := utils.FileRead(config.PolicyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy file '%v': %w", config.PolicyFile, err)
+		}
+		if policy, err = licensing.ParsePolicy(content); err != nil {
+			return nil, err
+		}
+	}
+	policy.Allow = append(policy.Allow, config.AllowedLicenses...)
+	policy.Review = append(policy.Review, config.ReviewLicenses...)
+	policy.Deny = append(policy.Deny, config.DeniedLicenses...)
+	if len(config.DefaultDecision) > 0 {
+		policy.Default = licensing.Decision(config.DefaultDecision)
+	}
+	return policy, nil
+}
+
+func readSBOMComponents(config *licenseComplianceCheckOptions, utils licenseComplianceCheckUtils) ([]licensing.Component, error) {
+	files := []string{}
+	for _, pattern := range config.BomFilePattern {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, fmt.Errorf("invalid SBOM file pattern '%v': %w", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+	files, err := piperutils.ExcludeFiles(piperutils.UniqueStrings(files), config.ExcludePaths)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+	if len(files) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("no SBOM files found matching %v, make sure the build step creates SBOMs", config.BomFilePattern)
+	}
+	sort.Strings(files)
+
+	componentLists := [][]licensing.Component{}
+	for _, file := range files {
+		log.Entry().Infof("Reading SBOM '%v'", file)
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SBOM '%v': %w", file, err)
+		}
+		components, err := licensing.ReadComponents(content)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, fmt.Errorf("SBOM '%v': %w", file, err)
+		}
+		componentLists = append(componentLists, components)
+	}
+	return licensing.MergeComponents(componentLists...), nil
+}
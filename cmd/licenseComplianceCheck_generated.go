@@ -0,0 +1,368 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/eventing"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/spf13/cobra"
+)
+
+type licenseComplianceCheckOptions struct {
+	BomFilePattern  []string `json:"bomFilePattern,omitempty"`
+	ExcludePaths    []string `json:"excludePaths,omitempty"`
+	PolicyFile      string   `json:"policyFile,omitempty"`
+	AllowedLicenses []string `json:"allowedLicenses,omitempty"`
+	ReviewLicenses  []string `json:"reviewLicenses,omitempty"`
+	DeniedLicenses  []string `json:"deniedLicenses,omitempty"`
+	DefaultDecision string   `json:"defaultDecision,omitempty" validate:"possible-values=allow review deny"`
+	FailOnDenied    bool     `json:"failOnDenied,omitempty"`
+	FailOnReview    bool     `json:"failOnReview,omitempty"`
+	ProductName     string   `json:"productName,omitempty"`
+}
+
+type licenseComplianceCheckReports struct {
+}
+
+func (p *licenseComplianceCheckReports) persist(stepConfig licenseComplianceCheckOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "license-compliance/piper_license_compliance_report.*", ParamRef: "", StepResultType: "license-compliance"},
+		{FilePattern: "license-compliance/NOTICE.txt", ParamRef: "", StepResultType: "license-compliance"},
+		{FilePattern: "license-compliance/attribution.html", ParamRef: "", StepResultType: "license-compliance"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, piperutils.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// LicenseComplianceCheckCommand Evaluate the licenses of the dependencies declared in SBOMs against a license policy
+func LicenseComplianceCheckCommand() *cobra.Command {
+	const STEP_NAME = "licenseComplianceCheck"
+
+	metadata := licenseComplianceCheckMetadata()
+	var stepConfig licenseComplianceCheckOptions
+	var startTime time.Time
+	var reports licenseComplianceCheckReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createLicenseComplianceCheckCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Evaluate the licenses of the dependencies declared in SBOMs against a license policy",
+		Long: `This step aggregates the licenses of all components contained in the CycloneDX SBOMs generated during the build, e.g. by ` + "`" + `mavenBuild` + "`" + `, ` + "`" + `npmExecuteScripts` + "`" + ` or ` + "`" + `golangBuild` + "`" + `.
+Declared license names and URLs are normalized to SPDX license expressions.
+
+Every license is evaluated against a policy which either allows a license, requires a review or denies it.
+For expressions combining several licenses with ` + "`" + `OR` + "`" + ` the least restrictive decision applies, for ` + "`" + `AND` + "`" + ` the most restrictive one.
+The policy can be defined with the parameters ` + "`" + `allowedLicenses` + "`" + `, ` + "`" + `reviewLicenses` + "`" + ` and ` + "`" + `deniedLicenses` + "`" + ` or via a ` + "`" + `policyFile` + "`" + ` with the following format:
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+allow: [MIT, Apache-2.0, "BSD-*"]
+review: ["LGPL-*", NOASSERTION]
+deny: ["GPL-*", "AGPL-*"]
+default: review
+scopes:
+  optional:
+    allow: ["GPL-*"]
+  excluded:
+    default: allow
+` + "`" + `` + "`" + `` + "`" + `
+
+Entries may contain wildcards, exact entries take precedence over wildcards.
+Policies for a dependency scope (` + "`" + `required` + "`" + `, ` + "`" + `optional` + "`" + `, ` + "`" + `excluded` + "`" + `) take precedence over the general policy.
+
+In addition to a JSON and HTML report, the step creates the third-party notice files ` + "`" + `NOTICE.txt` + "`" + ` and ` + "`" + `attribution.html` + "`" + ` for all components which are part of the delivery, i.e. not of scope ` + "`" + `excluded` + "`" + `.
+The results are also contained in the pipeline scan summary.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err = PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			// Set step error patterns for improved error detection
+			stepErrors := make([]log.StepError, len(metadata.Metadata.Errors))
+			for i, err := range metadata.Metadata.Errors {
+				stepErrors[i] = log.StepError{
+					Pattern:  err.Pattern,
+					Message:  err.Message,
+					Category: err.Category,
+				}
+			}
+			log.SetStepErrors(stepErrors)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			vaultClient := config.GlobalVaultClient()
+			var oidcTokenProvider func(string) (string, error)
+			if vaultClient != nil {
+				defer vaultClient.MustRevokeToken()
+				oidcTokenProvider = vaultClient.GetOIDCTokenByValidation
+			}
+
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.LogStepTelemetryData()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.GCPPubSubConfig.ProjectNumber) > 0 {
+					if err := eventing.PublishTaskRunFinishedEvent(
+						oidcTokenProvider,
+						&GeneralConfig,
+						eventing.EventContext{
+							StepName:   STEP_NAME,
+							StageName:  telemetryClient.GetData().StageName,
+							ErrorCode:  stepTelemetryData.ErrorCode,
+							PipelineID: telemetryClient.GetBuildURL(),
+						},
+					); err != nil {
+						log.Entry().WithError(err).Warn("failed to publish GCP Pub/Sub event")
+					}
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(STEP_NAME)
+			licenseComplianceCheck(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addLicenseComplianceCheckFlags(createLicenseComplianceCheckCmd, &stepConfig)
+	return createLicenseComplianceCheckCmd
+}
+
+func addLicenseComplianceCheckFlags(cmd *cobra.Command, stepConfig *licenseComplianceCheckOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.BomFilePattern, "bomFilePattern", []string{`**/bom-*.xml`, `**/bom-*.json`, `**/bom.xml`, `**/bom.json`}, "Glob patterns of the CycloneDX SBOM files in XML or JSON format which are evaluated.")
+	cmd.Flags().StringSliceVar(&stepConfig.ExcludePaths, "excludePaths", []string{`**/node_modules/**`}, "Glob patterns of SBOM files which are not evaluated.")
+	cmd.Flags().StringVar(&stepConfig.PolicyFile, "policyFile", os.Getenv("PIPER_policyFile"), "Path to a YAML file containing the license policy. The parameters `allowedLicenses`, `reviewLicenses` and `deniedLicenses` are added to it.")
+	cmd.Flags().StringSliceVar(&stepConfig.AllowedLicenses, "allowedLicenses", []string{}, "SPDX license identifiers which are allowed, wildcards like `BSD-*` are supported.")
+	cmd.Flags().StringSliceVar(&stepConfig.ReviewLicenses, "reviewLicenses", []string{}, "SPDX license identifiers which require a review, wildcards like `LGPL-*` are supported.")
+	cmd.Flags().StringSliceVar(&stepConfig.DeniedLicenses, "deniedLicenses", []string{}, "SPDX license identifiers which are denied, wildcards like `GPL-*` are supported.")
+	cmd.Flags().StringVar(&stepConfig.DefaultDecision, "defaultDecision", os.Getenv("PIPER_defaultDecision"), "Decision for licenses which are not part of the policy. Overrides the default of the `policyFile`.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnDenied, "failOnDenied", true, "Whether the step fails in case components with denied licenses are found.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnReview, "failOnReview", false, "Whether the step fails in case components with licenses requiring a review are found.")
+	cmd.Flags().StringVar(&stepConfig.ProductName, "productName", os.Getenv("PIPER_productName"), "Name of the product used in the notice files and reports. Defaults to the name of the git repository.")
+
+}
+
+// retrieve step metadata
+func licenseComplianceCheckMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "licenseComplianceCheck",
+			Aliases:     []config.Alias{},
+			Description: "Evaluate the licenses of the dependencies declared in SBOMs against a license policy",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "bomFilePattern",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/bom-*.xml`, `**/bom-*.json`, `**/bom.xml`, `**/bom.json`},
+					},
+					{
+						Name:        "excludePaths",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/node_modules/**`},
+					},
+					{
+						Name:        "policyFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_policyFile"),
+					},
+					{
+						Name:        "allowedLicenses",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "reviewLicenses",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "deniedLicenses",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "defaultDecision",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_defaultDecision"),
+					},
+					{
+						Name:        "failOnDenied",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "failOnReview",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "productName",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/repository",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_productName"),
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "license-compliance/piper_license_compliance_report.*", "type": "license-compliance"},
+							{"filePattern": "license-compliance/NOTICE.txt", "type": "license-compliance"},
+							{"filePattern": "license-compliance/attribution.html", "type": "license-compliance"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,133 @@
+//go:build unit
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/licensing"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type licenseComplianceCheckMockUtils struct {
+	*mock.FilesMock
+}
+
+func newLicenseComplianceCheckTestsUtils() licenseComplianceCheckMockUtils {
+	utils := licenseComplianceCheckMockUtils{FilesMock: &mock.FilesMock{}}
+	utils.AddFile("target/bom-maven.xml", []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.4" version="1">
+  <components>
+    <component type="library">
+      <group>org.apache.commons</group><name>commons-text</name><version>1.10.0</version>
+      <licenses><license><name>Apache License, Version 2.0</name></license></licenses>
+      <purl>pkg:maven/org.apache.commons/commons-text@1.10.0</purl>
+    </component>
+    <component type="library">
+      <group>junit</group><name>junit</name><version>4.13.2</version><scope>excluded</scope>
+      <licenses><license><id>EPL-1.0</id></license></licenses>
+      <purl>pkg:maven/junit/junit@4.13.2</purl>
+    </component>
+  </components>
+</bom>`))
+	utils.AddFile("ui/bom-npm.json", []byte(`{"bomFormat": "CycloneDX", "specVersion": "1.4", "components": [
+  {"type": "library", "name": "readline-sync", "version": "1.4.10", "purl": "pkg:npm/readline-sync@1.4.10", "licenses": [{"license": {"id": "GPL-3.0-only"}}]}
+]}`))
+	utils.AddFile("ui/node_modules/dep/bom-npm.json", []byte(`invalid`))
+	return utils
+}
+
+func defaultLicenseComplianceCheckOptions() licenseComplianceCheckOptions {
+	return licenseComplianceCheckOptions{
+		BomFilePattern:  []string{"**/bom-*.xml", "**/bom-*.json"},
+		ExcludePaths:    []string{"**/node_modules/**"},
+		AllowedLicenses: []string{"Apache-2.0", "MIT"},
+		DeniedLicenses:  []string{"GPL-*"},
+		FailOnDenied:    true,
+		ProductName:     "my-app",
+	}
+}
+
+func TestRunLicenseComplianceCheck(t *testing.T) {
+	t.Parallel()
+
+	t.Run("denied license", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		utils := newLicenseComplianceCheckTestsUtils()
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.EqualError(t, err, "1 component(s) with denied licenses found")
+		assert.True(t, utils.HasWrittenFile("license-compliance/NOTICE.txt"))
+		notice, _ := utils.FileRead("license-compliance/NOTICE.txt")
+		assert.Contains(t, string(notice), "my-app includes the following third-party components")
+		assert.Contains(t, string(notice), "org.apache.commons/commons-text 1.10.0")
+		assert.NotContains(t, string(notice), "junit")
+		assert.True(t, utils.HasWrittenFile("license-compliance/attribution.html"))
+		assert.True(t, utils.HasWrittenFile("license-compliance/piper_license_compliance_report.json"))
+	})
+
+	t.Run("review required", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		config.DeniedLicenses = nil
+		config.FailOnReview = true
+		utils := newLicenseComplianceCheckTestsUtils()
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.EqualError(t, err, "2 component(s) with licenses requiring a review found")
+	})
+
+	t.Run("success with policy file", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		config.PolicyFile = "license-policy.yml"
+		utils := newLicenseComplianceCheckTestsUtils()
+		utils.AddFile("license-policy.yml", []byte("scopes:\n  required:\n    allow: [GPL-3.0-only]\n  excluded:\n    default: allow\n"))
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("no SBOM", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		config.BomFilePattern = []string{"**/sbom.xml"}
+		utils := newLicenseComplianceCheckTestsUtils()
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.EqualError(t, err, "no SBOM files found matching [**/sbom.xml], make sure the build step creates SBOMs")
+	})
+}
+
+func TestLoadLicensePolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parameters extend policy file", func(t *testing.T) {
+		t.Parallel()
+		config := licenseComplianceCheckOptions{PolicyFile: "policy.yml", DeniedLicenses: []string{"AGPL-*"}, DefaultDecision: "deny"}
+		utils := licenseComplianceCheckMockUtils{FilesMock: &mock.FilesMock{}}
+		utils.AddFile("policy.yml", []byte("deny: [SSPL-1.0]\ndefault: allow\n"))
+
+		policy, err := loadLicensePolicy(&config, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"SSPL-1.0", "AGPL-*"}, policy.Deny)
+		assert.Equal(t, licensing.Deny, policy.Default)
+	})
+
+	t.Run("missing policy file", func(t *testing.T) {
+		t.Parallel()
+		config := licenseComplianceCheckOptions{PolicyFile: "policy.yml"}
+		utils := licenseComplianceCheckMockUtils{FilesMock: &mock.FilesMock{}}
+
+		_, err := loadLicensePolicy(&config, utils)
+
+		assert.ErrorContains(t, err, "failed to read policy file 'policy.yml'")
+	})
+}
@@ -1,15 +1,21 @@
 package cmd
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
-	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/build"
+	"github.com/SAP/jenkins-library/pkg/buildcache"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -27,13 +33,31 @@ const (
 	mvnCycloneDXPackage  = "org.cyclonedx:cyclonedx-maven-plugin:2.9.1"
 )
 
-func mavenBuild(config mavenBuildOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *mavenBuildCommonPipelineEnvironment) {
-	utils := maven.NewUtilsBundle()
-	// enables url-log.json creation
-	cmd := reflect.ValueOf(utils).Elem().FieldByName("Command")
-	if cmd.IsValid() {
-		reflect.Indirect(cmd).FieldByName("StepName").SetString("mavenBuild")
+type mavenBuildUtils interface {
+	maven.Utils
+	piperutils.FileUtils
+}
+
+type mavenBuildUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+	*piperhttp.Client
+}
+
+func newMavenBuildUtils() mavenBuildUtils {
+	utils := mavenBuildUtilsBundle{
+		// the step name enables url-log.json creation
+		Command: &command.Command{StepName: "mavenBuild"},
+		Files:   &piperutils.Files{},
+		Client:  &piperhttp.Client{},
 	}
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func mavenBuild(config mavenBuildOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *mavenBuildCommonPipelineEnvironment) {
+	utils := newMavenBuildUtils()
 
 	err := runMavenBuild(&config, telemetryData, utils, commonPipelineEnvironment)
 	if err != nil {
@@ -89,7 +113,10 @@ func runMakeBOMGoal(config *mavenBuildOptions, utils maven.Utils) error {
 	return err
 }
 
-func runMavenBuild(config *mavenBuildOptions, _ *telemetry.CustomData, utils maven.Utils, commonPipelineEnvironment *mavenBuildCommonPipelineEnvironment) error {
+func runMavenBuild(config *mavenBuildOptions, _ *telemetry.CustomData, utils mavenBuildUtils, commonPipelineEnvironment *mavenBuildCommonPipelineEnvironment) error {
+	startedOn := time.Now()
+	dependencyCache := restoreDependencyCache(config.DependencyCacheLocation, config.DependencyCacheCredentials, config.DependencyCacheSave,
+		func() (string, error) { return buildcache.MavenKey(config.PomPath, utils) }, buildcache.MavenDirs(config.M2Path))
 	flags := []string{"--update-snapshots", "--batch-mode"}
 
 	if len(config.Profiles) > 0 {
@@ -135,6 +162,29 @@ func runMavenBuild(config *mavenBuildOptions, _ *telemetry.CustomData, utils mav
 		goals = append(goals, "install")
 	}
 
+	referenceDir := ""
+	if config.VerifyReproducibility {
+		// the reference artifacts are kept outside of the workspace, so that neither the provenance nor later steps pick them up
+		dir, err := utils.TempDir("", "reproducibility-reference")
+		if err != nil {
+			return fmt.Errorf("failed to create directory for reference artifacts: %w", err)
+		}
+		referenceDir = dir
+		defer func() {
+			if err := utils.RemoveAll(referenceDir); err != nil {
+				log.Entry().Warnf("failed to remove reference artifacts: %v", err)
+			}
+		}()
+	}
+
+	if config.VerifyReproducibility && config.ReproducibilityReference == "rebuild" {
+		if err := runReproducibilityReferenceBuild(config, flags, defines, referenceDir, utils); err != nil {
+			return err
+		}
+		// the archivers do not recreate up to date archives, hence the build has to start from scratch to be comparable
+		goals = append([]string{"clean"}, goals...)
+	}
+
 	mavenOptions := maven.ExecuteOptions{
 		Flags:                       flags,
 		Goals:                       goals,
@@ -157,6 +207,19 @@ func runMavenBuild(config *mavenBuildOptions, _ *telemetry.CustomData, utils mav
 			return fmt.Errorf("failed to execute makeBOM goal: %w", err)
 		}
 	}
+	dependencyCache.Save(context.Background())
+
+	if config.DependencyAnalysis {
+		if err := runMavenDependencyAnalysis(config, flags, utils); err != nil {
+			return err
+		}
+	}
+
+	if config.VerifyReproducibility {
+		if err := verifyMavenReproducibility(config, referenceDir, utils); err != nil {
+			return err
+		}
+	}
 
 	log.Entry().Debugf("creating build settings information...")
 	stepName := "mavenBuild"
@@ -180,6 +243,12 @@ func runMavenBuild(config *mavenBuildOptions, _ *telemetry.CustomData, utils mav
 	}
 	commonPipelineEnvironment.custom.buildSettingsInfo = buildSettingsInfo
 
+	if config.CreateProvenance {
+		if err := createMavenProvenance(config, startedOn, utils); err != nil {
+			return fmt.Errorf("failed to create provenance: %w", err)
+		}
+	}
+
 	if err == nil {
 		if config.Publish && !config.Verify {
 			log.Entry().Infof("publish detected, running mvn deploy")
@@ -276,6 +345,16 @@ func createBuildArtifactsMetadata(config *mavenBuildOptions, commonPipelineEnvir
 	return false
 }
 
+// createMavenProvenance creates the provenance of the packaged artifacts of all modules
+func createMavenProvenance(config *mavenBuildOptions, startedOn time.Time, utils piperutils.FileUtils) error {
+	subjects, err := provenanceFileSubjects([]string{"**/target/*.jar", "**/target/*.war", "**/target/*.ear"}, utils)
+	if err != nil {
+		return err
+	}
+	_, err = writeProvenance(mavenBuildMetadata(), config, subjects, []string{"**/target/" + mvnBomFilename + ".xml"}, startedOn, utils)
+	return err
+}
+
 func createOrUpdateProjectSettingsXML(projectSettingsFile string, altDeploymentRepositoryID string, altDeploymentRepositoryUser string, altDeploymentRepositoryPassword string, utils maven.Utils) (string, error) {
 	if len(projectSettingsFile) > 0 {
 		projectSettingsFilePath, err := maven.UpdateProjectSettingsXML(projectSettingsFile, altDeploymentRepositoryID, altDeploymentRepositoryUser, altDeploymentRepositoryPassword, utils)
@@ -388,3 +467,365 @@ func getTempDirForCertFile() string {
 	}
 	return tmpFolder
 }
+
+// mavenArtifact is a jar or war file built for a module
+type mavenArtifact struct {
+	path    string
+	project *maven.Project
+}
+
+// runReproducibilityReferenceBuild builds the project without tests and keeps the artifacts as reference for verifying the actual build is reproducible
+func runReproducibilityReferenceBuild(config *mavenBuildOptions, flags, defines []string, referenceDir string, utils maven.Utils) error {
+	log.Entry().Info("Building the project as reference for verifying the build is reproducible")
+	goals := []string{"clean"}
+	if config.Flatten {
+		goals = append(goals, "flatten:flatten")
+	}
+	goals = append(goals, "package")
+	mavenOptions := maven.ExecuteOptions{
+		Flags:                       flags,
+		Goals:                       goals,
+		Defines:                     append(slices.Clone(defines), "-DskipTests"),
+		PomPath:                     config.PomPath,
+		ProjectSettingsFile:         config.ProjectSettingsFile,
+		GlobalSettingsFile:          config.GlobalSettingsFile,
+		M2Path:                      config.M2Path,
+		LogSuccessfulMavenTransfers: config.LogSuccessfulMavenTransfers,
+	}
+	if _, err := maven.Execute(&mavenOptions, utils); err != nil {
+		return fmt.Errorf("failed to execute reference build: %w", err)
+	}
+
+	artifacts, err := findMavenBuildArtifacts(config.PomPath, utils)
+	if err != nil {
+		return err
+	}
+	for _, artifact := range artifacts {
+		reference := reproducibilityReferencePath(referenceDir, artifact.path)
+		if err := utils.MkdirAll(filepath.Dir(reference), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for reference artifact: %w", err)
+		}
+		if _, err := utils.Copy(artifact.path, reference); err != nil {
+			return fmt.Errorf("failed to keep reference artifact '%v': %w", artifact.path, err)
+		}
+	}
+	return nil
+}
+
+// verifyMavenReproducibility compares the built artifacts with their reference and checks all modules set the output timestamp
+func verifyMavenReproducibility(config *mavenBuildOptions, referenceDir string, utils mavenBuildUtils) error {
+	projectDir := filepath.Dir(config.PomPath)
+	missing, err := maven.ModulesWithoutOutputTimestamp(projectDir, utils)
+	if err != nil {
+		return fmt.Errorf("failed to check %v: %w", maven.OutputTimestampProperty, err)
+	}
+	report := maven.ReproducibilityReport{ModulesWithoutOutputTimestamp: missing, Artifacts: []maven.ArtifactComparison{}}
+	for _, pomFile := range missing {
+		log.Entry().Errorf("Module '%v' does not set %v", pomFile, maven.OutputTimestampProperty)
+	}
+
+	artifacts, err := findMavenBuildArtifacts(config.PomPath, utils)
+	if err != nil {
+		return err
+	}
+	if len(artifacts) == 0 {
+		log.Entry().Warn("No jar or war files found, only the output timestamp is verified")
+	}
+	for _, artifact := range artifacts {
+		reference := reproducibilityReferencePath(referenceDir, artifact.path)
+		comparison := maven.ArtifactComparison{Artifact: artifact.path, Reference: reference}
+		if config.ReproducibilityReference == "published" {
+			if err := downloadPublishedArtifact(config, artifact, reference, utils); err != nil {
+				return err
+			}
+		}
+		if exists, _ := utils.FileExists(reference); !exists {
+			comparison.Differences = []maven.ArchiveDifference{{Reason: "not built by the reference build"}}
+		} else {
+			if comparison.Differences, err = maven.CompareArchives(reference, artifact.path, utils); err != nil {
+				return err
+			}
+			// the reference is not needed anymore and must not be picked up as build artifact, e.g. by the provenance
+			if err := utils.FileRemove(reference); err != nil {
+				log.Entry().Warnf("failed to remove reference artifact '%v': %v", reference, err)
+			}
+		}
+		for _, difference := range comparison.Differences {
+			log.Entry().Errorf("%v: %v %v", artifact.path, difference.Entry, difference.Reason)
+		}
+		report.Artifacts = append(report.Artifacts, comparison)
+	}
+
+	reportFile := filepath.Join(reproducibilityDirectory, "reproducibility-report.json")
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to create reproducibility report: %w", err)
+	}
+	if err := utils.MkdirAll(reproducibilityDirectory, 0755); err != nil {
+		return fmt.Errorf("failed to create directory for reproducibility report: %w", err)
+	}
+	if err := utils.FileWrite(reportFile, content, 0666); err != nil {
+		return fmt.Errorf("failed to write reproducibility report: %w", err)
+	}
+
+	if !report.Reproducible() {
+		log.SetErrorCategory(log.ErrorBuild)
+		return fmt.Errorf("the build is not reproducible: %v artifact(s) differ from the reference and %v module(s) do not set %v, see %v",
+			len(report.DifferingArtifacts()), len(report.ModulesWithoutOutputTimestamp), maven.OutputTimestampProperty, reportFile)
+	}
+	log.Entry().Infof("The build is reproducible, %v artifact(s) match the reference", len(report.Artifacts))
+	return nil
+}
+
+const reproducibilityDirectory = "reproducibility"
+
+func reproducibilityReferencePath(referenceDir, artifact string) string {
+	return filepath.Join(referenceDir, artifact)
+}
+
+// findMavenBuildArtifacts returns the jar and war files in the target directories of all modules which are not of packaging pom
+func findMavenBuildArtifacts(pomPath string, utils maven.Utils) ([]mavenArtifact, error) {
+	artifacts := []mavenArtifact{}
+	err := maven.VisitAllMavenModules(filepath.Dir(pomPath), utils, nil, func(info maven.ModuleInfo) error {
+		if info.Project.Packaging == "pom" {
+			return nil
+		}
+		for _, extension := range []string{"jar", "war"} {
+			matches, err := utils.Glob(filepath.Join(filepath.Dir(info.PomXMLPath), "target", "*."+extension))
+			if err != nil {
+				return err
+			}
+			for _, match := range matches {
+				artifacts = append(artifacts, mavenArtifact{path: match, project: info.Project})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find build artifacts: %w", err)
+	}
+	return artifacts, nil
+}
+
+// downloadPublishedArtifact downloads the artifact with the same coordinates from the maven repository
+func downloadPublishedArtifact(config *mavenBuildOptions, artifact mavenArtifact, target string, utils maven.Utils) error {
+	repositoryURL := config.ReproducibilityReferenceRepositoryURL
+	if repositoryURL == "" {
+		repositoryURL = config.AltDeploymentRepositoryURL
+	}
+	if repositoryURL == "" {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("verifying the build against published artifacts requires reproducibilityReferenceRepositoryUrl")
+	}
+	url, err := publishedArtifactURL(repositoryURL, artifact)
+	if err != nil {
+		return err
+	}
+	var header http.Header
+	// the credentials of the deployment repository must not be sent to a different host
+	if config.AltDeploymentRepositoryUser != "" && sameRepositoryHost(repositoryURL, config.AltDeploymentRepositoryURL) {
+		header = http.Header{}
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(config.AltDeploymentRepositoryUser+":"+config.AltDeploymentRepositoryPassword)))
+	}
+	if err := utils.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for reference artifact: %w", err)
+	}
+	log.Entry().Infof("Downloading reference artifact %v", url)
+	if err := utils.DownloadFile(url, target, header, nil); err != nil {
+		return fmt.Errorf("failed to download reference artifact '%v': %w", url, err)
+	}
+	return nil
+}
+
+// sameRepositoryHost returns true if both repository urls point to the same host
+func sameRepositoryHost(repositoryURL, otherURL string) bool {
+	repository, err := url.Parse(repositoryURL)
+	if err != nil {
+		return false
+	}
+	other, err := url.Parse(otherURL)
+	if err != nil {
+		return false
+	}
+	return repository.Host != "" && strings.EqualFold(repository.Host, other.Host)
+}
+
+// publishedArtifactURL returns the url of the artifact in the maven repository layout.
+// Artifacts named after the artifactId and version keep their classifier, other names are considered the main artifact.
+func publishedArtifactURL(repositoryURL string, artifact mavenArtifact) (string, error) {
+	project := artifact.project
+	groupID := project.GroupID
+	if groupID == "" {
+		groupID = project.Parent.GroupID
+	}
+	version := project.Version
+	if version == "" {
+		version = project.Parent.Version
+	}
+	if groupID == "" || version == "" || strings.Contains(version, "${") {
+		return "", fmt.Errorf("failed to determine the coordinates of '%v', CI friendly versions are not supported for published references", artifact.path)
+	}
+	if strings.HasSuffix(version, "-SNAPSHOT") {
+		return "", fmt.Errorf("snapshot version '%v' of '%v' cannot be compared to published artifacts", version, artifact.path)
+	}
+
+	name := filepath.Base(artifact.path)
+	prefix := project.ArtifactID + "-" + version
+	if !strings.HasPrefix(name, prefix) {
+		name = prefix + filepath.Ext(name)
+	}
+	return strings.Join([]string{strings.TrimSuffix(repositoryURL, "/"), strings.ReplaceAll(groupID, ".", "/"), project.ArtifactID, version, name}, "/"), nil
+}
+
+const mvnDependencyTreeFile = "dependency-tree.json"
+
+// runMavenDependencyAnalysis checks the resolved dependencies of all modules against the dependency policy
+func runMavenDependencyAnalysis(config *mavenBuildOptions, flags []string, utils mavenBuildUtils) error {
+	modules, projectModules, rootProject, err := resolveMavenDependencies(config, flags, utils)
+	if err != nil {
+		return err
+	}
+
+	options := maven.DependencyAnalysisOptions{
+		Checks:             config.DependencyAnalysisChecks,
+		FailOn:             config.DependencyAnalysisFailOn,
+		BannedDependencies: config.BannedDependencies,
+		ReleaseBuild:       mavenReleaseBuild(modules, rootProject),
+		ProjectModules:     projectModules,
+		LocalRepository:    buildcache.MavenDirs(config.M2Path)[0],
+	}
+	issues, err := maven.AnalyzeDependencies(modules, options, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("failed to analyze dependencies: %w", err)
+	}
+	for _, issue := range issues {
+		message := fmt.Sprintf("%v: %v %v", issue.Check, issue.Dependency, issue.Message)
+		if issue.Module != "" {
+			message = fmt.Sprintf("%v: %v in %v %v", issue.Check, issue.Dependency, issue.Module, issue.Message)
+		}
+		if issue.Severity == maven.SeverityError {
+			log.Entry().Error(message)
+		} else {
+			log.Entry().Warn(message)
+		}
+	}
+
+	scanReport := maven.CreateDependencyScanReport(mavenProjectKey(rootProject), modules, config.DependencyAnalysisChecks, issues, time.Now())
+	reports, err := maven.WriteDependencyAnalysisReports(scanReport, issues, "mavenBuild", utils)
+	if err != nil {
+		return fmt.Errorf("failed to write dependency analysis reports: %w", err)
+	}
+	if err := piperutils.PersistReportsAndLinks("mavenBuild", "", utils, reports, nil); err != nil {
+		log.Entry().Warnf("failed to persist dependency analysis reports: %v", err)
+	}
+
+	if errorCount := maven.CountIssues(issues, maven.SeverityError); errorCount > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("dependency analysis found %v issue(s) violating the dependency policy, see %v", errorCount, maven.DependencyAnalysisDirectory)
+	}
+	log.Entry().Infof("Dependency analysis of %v module(s) found %v warning(s)", len(modules), len(issues))
+	return nil
+}
+
+// resolveMavenDependencies reads the resolved dependencies of all modules from the SBOMs created by the build
+// or from the verbose dependency tree written by the maven-dependency-plugin.
+// The tree is required without SBOMs and for finding version conflicts within a module, which the SBOMs do not contain.
+func resolveMavenDependencies(config *mavenBuildOptions, flags []string, utils maven.Utils) ([]maven.ModuleDependencies, []string, *maven.Project, error) {
+	useDependencyTree := !config.CreateBOM || slices.Contains(config.DependencyAnalysisChecks, maven.CheckVersionConflicts)
+	if useDependencyTree {
+		mavenOptions := maven.ExecuteOptions{
+			Flags:                       flags,
+			Goals:                       []string{"org.apache.maven.plugins:maven-dependency-plugin:3.8.1:tree"},
+			Defines:                     []string{"-Dverbose", "-DoutputType=json", "-DoutputFile=target/" + mvnDependencyTreeFile},
+			PomPath:                     config.PomPath,
+			ProjectSettingsFile:         config.ProjectSettingsFile,
+			GlobalSettingsFile:          config.GlobalSettingsFile,
+			M2Path:                      config.M2Path,
+			LogSuccessfulMavenTransfers: config.LogSuccessfulMavenTransfers,
+		}
+		if _, err := maven.Execute(&mavenOptions, utils); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to resolve the dependency tree: %w", err)
+		}
+	}
+
+	modules := []maven.ModuleDependencies{}
+	projectModules := []string{}
+	var rootProject *maven.Project
+	err := maven.VisitAllMavenModules(filepath.Dir(config.PomPath), utils, nil, func(info maven.ModuleInfo) error {
+		if rootProject == nil {
+			rootProject = info.Project
+		}
+		projectModules = append(projectModules, mavenProjectKey(info.Project))
+
+		dependencyFile := filepath.Join(filepath.Dir(info.PomXMLPath), "target", mvnDependencyTreeFile)
+		parse := maven.ParseDependencyTree
+		if !useDependencyTree {
+			dependencyFile = filepath.Join(filepath.Dir(info.PomXMLPath), "target", mvnSimpleBomFilename+".xml")
+			parse = maven.ParseBOMDependencies
+		}
+		if exists, _ := utils.FileExists(dependencyFile); !exists {
+			log.Entry().Debugf("No resolved dependencies found for module '%v'", info.PomXMLPath)
+			return nil
+		}
+		content, err := utils.FileRead(dependencyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read '%v': %w", dependencyFile, err)
+		}
+		module, err := parse(content)
+		if err != nil {
+			return fmt.Errorf("failed to read the dependencies of '%v': %w", info.PomXMLPath, err)
+		}
+		if module.Module == ":" || module.Module == "" {
+			module.Module = mavenProjectKey(info.Project)
+		}
+		modules = append(modules, module)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to resolve dependencies: %w", err)
+	}
+	if rootProject == nil {
+		return nil, nil, nil, fmt.Errorf("no maven project found in '%v'", config.PomPath)
+	}
+	return modules, projectModules, rootProject, nil
+}
+
+// mavenProjectKey returns groupId:artifactId of the project, the groupId may be inherited from the parent
+func mavenProjectKey(project *maven.Project) string {
+	groupID := project.GroupID
+	if groupID == "" {
+		groupID = project.Parent.GroupID
+	}
+	return groupID + ":" + project.ArtifactID
+}
+
+// mavenReleaseBuild checks whether the version of the root project as resolved by maven is a release version
+func mavenReleaseBuild(modules []maven.ModuleDependencies, rootProject *maven.Project) bool {
+	version := mavenProjectVersion(rootProject)
+	for _, module := range modules {
+		if module.Module == mavenProjectKey(rootProject) && len(module.Version) > 0 {
+			version = module.Version
+			break
+		}
+	}
+	if strings.Contains(version, "${") {
+		log.Entry().Warnf("The version '%v' of the project could not be resolved, it is not considered a release", version)
+		return false
+	}
+	return !strings.HasSuffix(version, "-SNAPSHOT")
+}
+
+// mavenProjectVersion returns the version of the project, simple property references like CI friendly versions are resolved
+func mavenProjectVersion(project *maven.Project) string {
+	version := project.Version
+	if version == "" {
+		version = project.Parent.Version
+	}
+	if strings.HasPrefix(version, "${") && strings.HasSuffix(version, "}") {
+		if value, ok := project.Properties[strings.TrimSuffix(strings.TrimPrefix(version, "${"), "}")]; ok {
+			return value
+		}
+	}
+	return version
+}
@@ -23,25 +23,36 @@ import (
 )
 
 type mavenBuildOptions struct {
-	PomPath                         string   `json:"pomPath,omitempty"`
-	Profiles                        []string `json:"profiles,omitempty"`
-	Flatten                         bool     `json:"flatten,omitempty"`
-	Verify                          bool     `json:"verify,omitempty"`
-	ProjectSettingsFile             string   `json:"projectSettingsFile,omitempty"`
-	GlobalSettingsFile              string   `json:"globalSettingsFile,omitempty"`
-	M2Path                          string   `json:"m2Path,omitempty"`
-	LogSuccessfulMavenTransfers     bool     `json:"logSuccessfulMavenTransfers,omitempty"`
-	CreateBOM                       bool     `json:"createBOM,omitempty"`
-	AltDeploymentRepositoryPassword string   `json:"altDeploymentRepositoryPassword,omitempty"`
-	AltDeploymentRepositoryUser     string   `json:"altDeploymentRepositoryUser,omitempty"`
-	AltDeploymentRepositoryURL      string   `json:"altDeploymentRepositoryUrl,omitempty"`
-	AltDeploymentRepositoryID       string   `json:"altDeploymentRepositoryID,omitempty"`
-	CustomTLSCertificateLinks       []string `json:"customTlsCertificateLinks,omitempty"`
-	Publish                         bool     `json:"publish,omitempty"`
-	JavaCaCertFilePath              string   `json:"javaCaCertFilePath,omitempty"`
-	BuildSettingsInfo               string   `json:"buildSettingsInfo,omitempty"`
-	DeployFlags                     []string `json:"deployFlags,omitempty"`
-	CreateBuildArtifactsMetadata    bool     `json:"createBuildArtifactsMetadata,omitempty"`
+	PomPath                               string   `json:"pomPath,omitempty"`
+	Profiles                              []string `json:"profiles,omitempty"`
+	Flatten                               bool     `json:"flatten,omitempty"`
+	Verify                                bool     `json:"verify,omitempty"`
+	ProjectSettingsFile                   string   `json:"projectSettingsFile,omitempty"`
+	GlobalSettingsFile                    string   `json:"globalSettingsFile,omitempty"`
+	M2Path                                string   `json:"m2Path,omitempty"`
+	LogSuccessfulMavenTransfers           bool     `json:"logSuccessfulMavenTransfers,omitempty"`
+	CreateBOM                             bool     `json:"createBOM,omitempty"`
+	CreateProvenance                      bool     `json:"createProvenance,omitempty"`
+	AltDeploymentRepositoryPassword       string   `json:"altDeploymentRepositoryPassword,omitempty"`
+	AltDeploymentRepositoryUser           string   `json:"altDeploymentRepositoryUser,omitempty"`
+	AltDeploymentRepositoryURL            string   `json:"altDeploymentRepositoryUrl,omitempty"`
+	AltDeploymentRepositoryID             string   `json:"altDeploymentRepositoryID,omitempty"`
+	CustomTLSCertificateLinks             []string `json:"customTlsCertificateLinks,omitempty"`
+	Publish                               bool     `json:"publish,omitempty"`
+	JavaCaCertFilePath                    string   `json:"javaCaCertFilePath,omitempty"`
+	BuildSettingsInfo                     string   `json:"buildSettingsInfo,omitempty"`
+	DeployFlags                           []string `json:"deployFlags,omitempty"`
+	CreateBuildArtifactsMetadata          bool     `json:"createBuildArtifactsMetadata,omitempty"`
+	DependencyAnalysis                    bool     `json:"dependencyAnalysis,omitempty"`
+	DependencyAnalysisChecks              []string `json:"dependencyAnalysisChecks,omitempty" validate:"possible-values=versionConflicts snapshots bannedDependencies duplicateClasses"`
+	DependencyAnalysisFailOn              []string `json:"dependencyAnalysisFailOn,omitempty" validate:"possible-values=versionConflicts snapshots bannedDependencies duplicateClasses"`
+	BannedDependencies                    []string `json:"bannedDependencies,omitempty"`
+	VerifyReproducibility                 bool     `json:"verifyReproducibility,omitempty"`
+	ReproducibilityReference              string   `json:"reproducibilityReference,omitempty" validate:"possible-values=rebuild published"`
+	ReproducibilityReferenceRepositoryURL string   `json:"reproducibilityReferenceRepositoryUrl,omitempty"`
+	DependencyCacheLocation               string   `json:"dependencyCacheLocation,omitempty"`
+	DependencyCacheCredentials            string   `json:"dependencyCacheCredentials,omitempty"`
+	DependencyCacheSave                   bool     `json:"dependencyCacheSave,omitempty"`
 }
 
 type mavenBuildCommonPipelineEnvironment struct {
@@ -87,6 +98,7 @@ func (p *mavenBuildReports) persist(stepConfig mavenBuildOptions, gcpJsonKeyFile
 		{FilePattern: "**/bom-maven.xml", ParamRef: "", StepResultType: "sbom"},
 		{FilePattern: "**/TEST-*.xml", ParamRef: "", StepResultType: "junit"},
 		{FilePattern: "**/jacoco.xml", ParamRef: "", StepResultType: "jacoco-coverage"},
+		{FilePattern: "dependency-analysis/piper_maven_dependency_analysis_report.*", ParamRef: "", StepResultType: "dependency-analysis"},
 	}
 
 	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
@@ -166,7 +178,43 @@ Ensure the following configuration in the Piper ` + "`" + `config.yaml` + "`" +
 ` + "`" + `` + "`" + `` + "`" + `yaml
 general:
   projectSettingsFile: <path to the above settings.xml>
-` + "`" + `` + "`" + `` + "`" + ``,
+` + "`" + `` + "`" + `` + "`" + `
+
+### analyze dependencies
+
+With ` + "`" + `dependencyAnalysis` + "`" + ` the step analyzes the resolved dependencies of all modules without requiring any plugin configuration in the project.
+It reports dependencies resolved in different versions by the modules, SNAPSHOT dependencies in release builds, banned dependencies and classes contained in more than one jar:
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+steps:
+  mavenBuild:
+    dependencyAnalysis: true
+    dependencyAnalysisFailOn:
+      - snapshots
+      - bannedDependencies
+      - versionConflicts
+    bannedDependencies:
+      - log4j:log4j
+      - commons-logging
+` + "`" + `` + "`" + `` + "`" + `
+
+The result is available as report in the ` + "`" + `dependency-analysis` + "`" + ` folder and in the pipeline summary.
+
+### verify the build is reproducible
+
+With ` + "`" + `verifyReproducibility` + "`" + ` the step verifies the jar and war files are reproducible. By default the project is built an additional time without tests before the actual build,
+alternatively the artifacts can be compared with the artifacts of the same version published before, e.g. by ` + "`" + `nexusUpload` + "`" + `:
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+steps:
+  mavenBuild:
+    verifyReproducibility: true
+    reproducibilityReference: published
+    reproducibilityReferenceRepositoryUrl: https://nexus.example.com/repository/maven-releases
+` + "`" + `` + "`" + `` + "`" + `
+
+Archives are compared entry by entry, timestamps and manifest attributes like ` + "`" + `Build-Jdk-Spec` + "`" + ` or ` + "`" + `Built-By` + "`" + ` are ignored. Since the timestamps are only reproducible if
+[` + "`" + `project.build.outputTimestamp` + "`" + `](https://maven.apache.org/guides/mini/guide-reproducible-builds.html) is set, every module has to set or inherit this property from a parent within the project.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -198,6 +246,7 @@ general:
 			}
 			log.SetStepErrors(stepErrors)
 			log.RegisterSecret(stepConfig.AltDeploymentRepositoryPassword)
+			log.RegisterSecret(stepConfig.DependencyCacheCredentials)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -298,6 +347,7 @@ func addMavenBuildFlags(cmd *cobra.Command, stepConfig *mavenBuildOptions) {
 	cmd.Flags().StringVar(&stepConfig.M2Path, "m2Path", os.Getenv("PIPER_m2Path"), "Path to the location of the local repository that should be used.")
 	cmd.Flags().BoolVar(&stepConfig.LogSuccessfulMavenTransfers, "logSuccessfulMavenTransfers", false, "Configures maven to log successful downloads. This is set to `false` by default to reduce the noise in build logs.")
 	cmd.Flags().BoolVar(&stepConfig.CreateBOM, "createBOM", false, "Creates the bill of materials (BOM) using CycloneDX Maven plugin.")
+	cmd.Flags().BoolVar(&stepConfig.CreateProvenance, "createProvenance", false, "Creates an [in-toto](https://github.com/in-toto/attestation) statement with the [SLSA provenance](https://slsa.dev/spec/v1.0/provenance) of the build artifacts in `provenance/mavenBuild.intoto.json`. It contains the digests of the artifacts, the invoking configuration, the source, the build run of the orchestrator and the dependencies listed in the BOM (see `createBOM`). The jar, war and ear files in the `target` folders of the modules are the build artifacts.")
 	cmd.Flags().StringVar(&stepConfig.AltDeploymentRepositoryPassword, "altDeploymentRepositoryPassword", os.Getenv("PIPER_altDeploymentRepositoryPassword"), "Password for the alternative deployment repository to which the project artifacts should be deployed ( other than those specified in <distributionManagement> ). This password will be updated in settings.xml . When no settings.xml is provided a new one is created corresponding with <servers> tag")
 	cmd.Flags().StringVar(&stepConfig.AltDeploymentRepositoryUser, "altDeploymentRepositoryUser", os.Getenv("PIPER_altDeploymentRepositoryUser"), "User for the alternative deployment repository to which the project artifacts should be deployed ( other than those specified in <distributionManagement> ). This user will be updated in settings.xml . When no settings.xml is provided a new one is created corresponding with <servers> tag")
 	cmd.Flags().StringVar(&stepConfig.AltDeploymentRepositoryURL, "altDeploymentRepositoryUrl", os.Getenv("PIPER_altDeploymentRepositoryUrl"), "Url for the alternative deployment repository to which the project artifacts should be deployed ( other than those specified in <distributionManagement> ). This Url will be updated in settings.xml . When no settings.xml is provided a new one is created corresponding with <servers> tag")
@@ -308,6 +358,16 @@ func addMavenBuildFlags(cmd *cobra.Command, stepConfig *mavenBuildOptions) {
 	cmd.Flags().StringVar(&stepConfig.BuildSettingsInfo, "buildSettingsInfo", os.Getenv("PIPER_buildSettingsInfo"), "build settings info is typically filled by the step automatically to create information about the build settings that were used during the maven build . This information is typically used for compliance related processes.")
 	cmd.Flags().StringSliceVar(&stepConfig.DeployFlags, "deployFlags", []string{`-Dmaven.main.skip=true`, `-Dmaven.test.skip=true`, `-Dmaven.install.skip=true`}, "maven deploy flags that will be used when publish is detected.")
 	cmd.Flags().BoolVar(&stepConfig.CreateBuildArtifactsMetadata, "createBuildArtifactsMetadata", false, "metadata about the artifacts that are build and published , this metadata is generally used by steps downstream in the pipeline")
+	cmd.Flags().BoolVar(&stepConfig.DependencyAnalysis, "dependencyAnalysis", false, "Analyzes the resolved dependencies of all modules after the build. The dependencies are read from the verbose JSON output of the tree goal of the maven-dependency-plugin. If `createBOM` is active and the `versionConflicts` check is not, they are read from the SBOM instead. The result is written to the `dependency-analysis` folder.")
+	cmd.Flags().StringSliceVar(&stepConfig.DependencyAnalysisChecks, "dependencyAnalysisChecks", []string{`versionConflicts`, `snapshots`, `bannedDependencies`, `duplicateClasses`}, "Checks of the dependency analysis: `versionConflicts` finds dependencies required in different versions within a module and dependencies resolved in different versions by the modules, `snapshots` finds SNAPSHOT dependencies in release builds, `bannedDependencies` finds dependencies matching `bannedDependencies` and `duplicateClasses` finds classes contained in more than one jar of a module.")
+	cmd.Flags().StringSliceVar(&stepConfig.DependencyAnalysisFailOn, "dependencyAnalysisFailOn", []string{`snapshots`, `bannedDependencies`}, "Checks of the dependency analysis failing the build. Issues of the other checks are reported as warnings.")
+	cmd.Flags().StringSliceVar(&stepConfig.BannedDependencies, "bannedDependencies", []string{}, "Dependencies which must not be used, as `groupId[:artifactId[:version]]`. Each part may contain wildcards, e.g. `log4j:log4j` or `org.apache.logging.log4j:log4j-core:2.1[0-4].*`.")
+	cmd.Flags().BoolVar(&stepConfig.VerifyReproducibility, "verifyReproducibility", false, "Verifies the build is reproducible. The built jar and war files are compared entry by entry with a reference, ignoring timestamps and manifest attributes describing the build environment, and every module has to set `project.build.outputTimestamp`. The step fails if the build is not reproducible, the result is written to `reproducibility/reproducibility-report.json`.")
+	cmd.Flags().StringVar(&stepConfig.ReproducibilityReference, "reproducibilityReference", `rebuild`, "Reference used to verify the build is reproducible: `rebuild` builds the project an additional time before the actual build, `published` downloads the previously published artifacts of the same version from `reproducibilityReferenceRepositoryUrl`.")
+	cmd.Flags().StringVar(&stepConfig.ReproducibilityReferenceRepositoryURL, "reproducibilityReferenceRepositoryUrl", os.Getenv("PIPER_reproducibilityReferenceRepositoryUrl"), "Url of the maven repository the published reference artifacts are downloaded from, e.g. the repository `nexusUpload` published to. Defaults to `altDeploymentRepositoryUrl`. The credentials `altDeploymentRepositoryUser` and `altDeploymentRepositoryPassword` are only sent if the repository is located on the same host as `altDeploymentRepositoryUrl`.")
+	cmd.Flags().StringVar(&stepConfig.DependencyCacheLocation, "dependencyCacheLocation", os.Getenv("PIPER_dependencyCacheLocation"), "Location of the dependency cache, either a local directory (e.g. a volume shared between builds) or a bucket URL with an optional prefix: `s3://<bucket>/<prefix>`, `gs://<bucket>/<prefix>` or `azblob://<container>/<prefix>`. The cache is keyed by a hash of the `pom.xml` files of all modules and restored before the build. If empty, no dependency cache is used.")
+	cmd.Flags().StringVar(&stepConfig.DependencyCacheCredentials, "dependencyCacheCredentials", os.Getenv("PIPER_dependencyCacheCredentials"), "JSON credentials of the bucket used as dependency cache. For S3 `{\"access_key_id\": \"\", \"secret_access_key\": \"\", \"region\": \"\"}` with an optional `endpoint` for S3 compatible storages, the default AWS credential chain is used if empty. For Google Cloud Storage the JSON key of a service account. For Azure `{\"account_name\": \"\", \"sas_token\": \"\"}`.")
+	cmd.Flags().BoolVar(&stepConfig.DependencyCacheSave, "dependencyCacheSave", true, "Saves the dependency cache after a successful build if no cache was restored for the current key. Set to false for read-only access, e.g. in pull request builds.")
 
 }
 
@@ -335,6 +395,7 @@ func mavenBuildMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
 					{Name: "altDeploymentRepositoryPasswordId", Description: "Jenkins credentials ID containing the artifact deployment repository password.", Type: "jenkins"},
+					{Name: "dependencyCacheCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the JSON credentials of the bucket used as dependency cache.", Type: "jenkins"},
 				},
 				Resources: []config.StepResources{
 					{Type: "stash"},
@@ -426,6 +487,15 @@ func mavenBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "maven/createBOM"}},
 						Default:     false,
 					},
+					{
+						Name:        "createProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 					{
 						Name: "altDeploymentRepositoryPassword",
 						ResourceRef: []config.ResourceReference{
@@ -567,6 +637,107 @@ func mavenBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "dependencyAnalysis",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "dependencyAnalysisChecks",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`versionConflicts`, `snapshots`, `bannedDependencies`, `duplicateClasses`},
+					},
+					{
+						Name:        "dependencyAnalysisFailOn",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`snapshots`, `bannedDependencies`},
+					},
+					{
+						Name:        "bannedDependencies",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "verifyReproducibility",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "reproducibilityReference",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `rebuild`,
+					},
+					{
+						Name:        "reproducibilityReferenceRepositoryUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_reproducibilityReferenceRepositoryUrl"),
+					},
+					{
+						Name:        "dependencyCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_dependencyCacheLocation"),
+					},
+					{
+						Name: "dependencyCacheCredentials",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "dependencyCacheCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "dependencyCacheVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "dependency-cache",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_dependencyCacheCredentials"),
+					},
+					{
+						Name:        "dependencyCacheSave",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -589,6 +760,7 @@ func mavenBuildMetadata() config.StepData {
 							{"filePattern": "**/bom-maven.xml", "type": "sbom"},
 							{"filePattern": "**/TEST-*.xml", "type": "junit"},
 							{"filePattern": "**/jacoco.xml", "type": "jacoco-coverage"},
+							{"filePattern": "dependency-analysis/piper_maven_dependency_analysis_report.*", "type": "dependency-analysis"},
 						},
 					},
 				},
@@ -4,12 +4,21 @@
 package cmd
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/build"
+	"github.com/SAP/jenkins-library/pkg/maven"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/SAP/jenkins-library/pkg/config"
 )
@@ -237,3 +246,256 @@ func TestLoadRemoteRepoCertificates(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+type mavenReproducibilityMockUtils struct {
+	mavenMockUtils
+	downloads map[string][]byte
+}
+
+func (m *mavenReproducibilityMockUtils) DownloadFile(url, filename string, header http.Header, _ []*http.Cookie) error {
+	content, ok := m.downloads[url]
+	if !ok {
+		return fmt.Errorf("not found: %v", url)
+	}
+	if header.Get("Authorization") == "" {
+		return fmt.Errorf("unauthorized")
+	}
+	m.AddFile(filename, content)
+	return nil
+}
+
+func testJar(t *testing.T, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	file, err := writer.Create("com/example/App.class")
+	require.NoError(t, err)
+	_, err = file.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestMavenBuildVerifyReproducibility(t *testing.T) {
+	SetConfigOptions(ConfigCommandOptions{
+		OpenFile: config.OpenPiperFile,
+	})
+	const reproduciblePom = `<project><groupId>com.example</groupId><artifactId>app</artifactId><version>1.2.0</version>
+<properties><project.build.outputTimestamp>2026-10-19T08:00:00Z</project.build.outputTimestamp></properties></project>`
+
+	t.Run("rebuild", func(t *testing.T) {
+		mockedUtils := newMavenMockUtils()
+		mockedUtils.AddFile("pom.xml", []byte(reproduciblePom))
+		mockedUtils.AddFile("target/app-1.2.0.jar", testJar(t, "app"))
+		options := mavenBuildOptions{PomPath: "pom.xml", VerifyReproducibility: true, ReproducibilityReference: "rebuild", CreateProvenance: true}
+
+		err := runMavenBuild(&options, nil, &mockedUtils, &cpe)
+
+		require.NoError(t, err)
+		require.Len(t, mockedUtils.Calls, 2, "Expected the reference build and the main build")
+		assert.Contains(t, mockedUtils.Calls[0].Params, "package")
+		assert.Contains(t, mockedUtils.Calls[0].Params, "-DskipTests")
+		assert.Contains(t, mockedUtils.Calls[1].Params, "clean")
+		assert.Contains(t, mockedUtils.Calls[1].Params, "install")
+		assert.True(t, mockedUtils.HasRemovedFile(filepath.Join("/tmp", "reproducibility-referencetest", "target", "app-1.2.0.jar")), "the reference is kept outside of the workspace and removed after the verification")
+		content, err := mockedUtils.FileRead(filepath.Join("reproducibility", "reproducibility-report.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"artifact": "target/app-1.2.0.jar"`)
+
+		content, err = mockedUtils.FileRead(path.Join(build.ProvenanceDirectory, "mavenBuild.intoto.json"))
+		require.NoError(t, err)
+		var statement build.Statement
+		require.NoError(t, json.Unmarshal(content, &statement))
+		subjects := []string{}
+		for _, subject := range statement.Subject {
+			subjects = append(subjects, subject.Name)
+		}
+		assert.Equal(t, []string{"app-1.2.0.jar"}, subjects, "the reference artifacts must not be subjects of the provenance")
+	})
+
+	t.Run("module without output timestamp", func(t *testing.T) {
+		mockedUtils := newMavenMockUtils()
+		mockedUtils.AddFile("pom.xml", []byte(`<project><groupId>com.example</groupId><artifactId>app</artifactId><version>1.2.0</version></project>`))
+		options := mavenBuildOptions{PomPath: "pom.xml", VerifyReproducibility: true, ReproducibilityReference: "rebuild"}
+
+		err := runMavenBuild(&options, nil, &mockedUtils, &cpe)
+
+		assert.EqualError(t, err, "the build is not reproducible: 0 artifact(s) differ from the reference and 1 module(s) do not set project.build.outputTimestamp, see reproducibility/reproducibility-report.json")
+	})
+
+	t.Run("published", func(t *testing.T) {
+		mockedUtils := mavenReproducibilityMockUtils{mavenMockUtils: newMavenMockUtils(), downloads: map[string][]byte{
+			"https://nexus.example.com/repository/releases/com/example/app/1.2.0/app-1.2.0.jar":         testJar(t, "app"),
+			"https://nexus.example.com/repository/releases/com/example/app/1.2.0/app-1.2.0-sources.jar": testJar(t, "app sources"),
+		}}
+		mockedUtils.AddFile("pom.xml", []byte(reproduciblePom))
+		mockedUtils.AddFile("target/app-1.2.0.jar", testJar(t, "app"))
+		mockedUtils.AddFile("target/app-1.2.0-sources.jar", testJar(t, "changed sources"))
+		options := mavenBuildOptions{
+			PomPath:                               "pom.xml",
+			VerifyReproducibility:                 true,
+			ReproducibilityReference:              "published",
+			ReproducibilityReferenceRepositoryURL: "https://nexus.example.com/repository/releases/",
+			AltDeploymentRepositoryURL:            "https://nexus.example.com/repository/snapshots/",
+			AltDeploymentRepositoryUser:           "user",
+			AltDeploymentRepositoryPassword:       "password",
+		}
+
+		err := runMavenBuild(&options, nil, &mockedUtils, &cpe)
+
+		assert.EqualError(t, err, "the build is not reproducible: 1 artifact(s) differ from the reference and 0 module(s) do not set project.build.outputTimestamp, see reproducibility/reproducibility-report.json")
+		assert.Len(t, mockedUtils.Calls, 1, "Expected no reference build")
+	})
+
+	t.Run("published on a different host", func(t *testing.T) {
+		mockedUtils := mavenReproducibilityMockUtils{mavenMockUtils: newMavenMockUtils(), downloads: map[string][]byte{
+			"https://repo.example.org/releases/com/example/app/1.2.0/app-1.2.0.jar": testJar(t, "app"),
+		}}
+		mockedUtils.AddFile("pom.xml", []byte(reproduciblePom))
+		mockedUtils.AddFile("target/app-1.2.0.jar", testJar(t, "app"))
+		options := mavenBuildOptions{
+			PomPath:                               "pom.xml",
+			VerifyReproducibility:                 true,
+			ReproducibilityReference:              "published",
+			ReproducibilityReferenceRepositoryURL: "https://repo.example.org/releases",
+			AltDeploymentRepositoryURL:            "https://nexus.example.com/repository/releases/",
+			AltDeploymentRepositoryUser:           "user",
+			AltDeploymentRepositoryPassword:       "password",
+		}
+
+		err := runMavenBuild(&options, nil, &mockedUtils, &cpe)
+
+		assert.EqualError(t, err, "failed to download reference artifact 'https://repo.example.org/releases/com/example/app/1.2.0/app-1.2.0.jar': unauthorized")
+	})
+
+	t.Run("published without repository", func(t *testing.T) {
+		mockedUtils := newMavenMockUtils()
+		mockedUtils.AddFile("pom.xml", []byte(reproduciblePom))
+		mockedUtils.AddFile("target/app-1.2.0.jar", testJar(t, "app"))
+		options := mavenBuildOptions{PomPath: "pom.xml", VerifyReproducibility: true, ReproducibilityReference: "published"}
+
+		err := runMavenBuild(&options, nil, &mockedUtils, &cpe)
+
+		assert.EqualError(t, err, "verifying the build against published artifacts requires reproducibilityReferenceRepositoryUrl")
+	})
+}
+
+func TestMavenBuildDependencyAnalysis(t *testing.T) {
+	SetConfigOptions(ConfigCommandOptions{
+		OpenFile: config.OpenPiperFile,
+	})
+	// the reports are persisted in the working directory
+	t.Chdir(t.TempDir())
+	addProject := func(mockedUtils *mavenMockUtils) {
+		mockedUtils.AddFile("pom.xml", []byte(`<project><groupId>com.example</groupId><artifactId>parent</artifactId><version>1.2.0</version><packaging>pom</packaging>
+<modules><module>app</module></modules></project>`))
+		mockedUtils.AddFile("app/pom.xml", []byte(`<project><parent><groupId>com.example</groupId><artifactId>parent</artifactId><version>1.2.0</version></parent><artifactId>app</artifactId></project>`))
+	}
+
+	t.Run("dependency tree", func(t *testing.T) {
+		mockedUtils := newMavenMockUtils()
+		addProject(&mockedUtils)
+		mockedUtils.AddFile("target/dependency-tree.json", []byte(`{"groupId": "com.example", "artifactId": "parent", "version": "1.2.0", "children": []}`))
+		mockedUtils.AddFile("app/target/dependency-tree.json", []byte(`{"groupId": "com.example", "artifactId": "app", "version": "1.2.0", "children": [
+  {"groupId": "com.acme", "artifactId": "lib", "version": "0.1-SNAPSHOT", "type": "jar", "scope": "compile"}
+]}`))
+		options := mavenBuildOptions{
+			PomPath:                  "pom.xml",
+			DependencyAnalysis:       true,
+			DependencyAnalysisChecks: []string{"versionConflicts", "snapshots", "bannedDependencies"},
+			DependencyAnalysisFailOn: []string{"snapshots", "bannedDependencies"},
+		}
+
+		err := runMavenBuild(&options, nil, &mockedUtils, &cpe)
+
+		assert.EqualError(t, err, "dependency analysis found 1 issue(s) violating the dependency policy, see dependency-analysis")
+		require.Len(t, mockedUtils.Calls, 2, "Expected the build and the dependency tree")
+		assert.Contains(t, mockedUtils.Calls[1].Params, "org.apache.maven.plugins:maven-dependency-plugin:3.8.1:tree")
+		assert.Contains(t, mockedUtils.Calls[1].Params, "-DoutputType=json")
+		assert.Contains(t, mockedUtils.Calls[1].Params, "-Dverbose")
+		content, err := mockedUtils.FileRead(filepath.Join("dependency-analysis", "piper_maven_dependency_analysis_report.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"dependency": "com.acme:lib:0.1-SNAPSHOT"`)
+	})
+
+	t.Run("SBOM", func(t *testing.T) {
+		mockedUtils := newMavenMockUtils()
+		addProject(&mockedUtils)
+		mockedUtils.AddFile("app/target/simple-bom-maven.xml", []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.4" version="1">
+  <components>
+    <component type="library">
+      <group>log4j</group>
+      <name>log4j</name>
+      <version>1.2.17</version>
+      <purl>pkg:maven/log4j/log4j@1.2.17?type=jar</purl>
+    </component>
+  </components>
+</bom>`))
+		options := mavenBuildOptions{
+			PomPath:                  "pom.xml",
+			CreateBOM:                true,
+			DependencyAnalysis:       true,
+			DependencyAnalysisChecks: []string{"bannedDependencies"},
+			DependencyAnalysisFailOn: []string{"versionConflicts"},
+			BannedDependencies:       []string{"log4j:log4j"},
+		}
+
+		err := runMavenBuild(&options, nil, &mockedUtils, &cpe)
+
+		require.NoError(t, err)
+		assert.Len(t, mockedUtils.Calls, 2, "Expected the build and the BOM creation only")
+		content, err := mockedUtils.FileRead(filepath.Join("dependency-analysis", "piper_maven_dependency_analysis_report.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"module": "com.example:app"`)
+		assert.Contains(t, string(content), `"severity": "warning"`)
+	})
+
+	t.Run("invalid banned dependency", func(t *testing.T) {
+		mockedUtils := newMavenMockUtils()
+		addProject(&mockedUtils)
+		options := mavenBuildOptions{PomPath: "pom.xml", DependencyAnalysis: true, DependencyAnalysisChecks: []string{"bannedDependencies"}, BannedDependencies: []string{"a:b:c:d"}}
+
+		err := runMavenBuild(&options, nil, &mockedUtils, &cpe)
+
+		assert.EqualError(t, err, "failed to analyze dependencies: invalid banned dependency 'a:b:c:d', expected groupId[:artifactId[:version]]")
+	})
+}
+
+func TestMavenReleaseBuild(t *testing.T) {
+	t.Parallel()
+	project := &maven.Project{GroupID: "com.example", ArtifactID: "parent", Version: "${revision}"}
+
+	// the version resolved by maven takes precedence, e.g. with the revision passed as define
+	assert.False(t, mavenReleaseBuild([]maven.ModuleDependencies{{Module: "com.example:parent", Version: "1.2.0-SNAPSHOT"}}, project))
+	assert.True(t, mavenReleaseBuild([]maven.ModuleDependencies{{Module: "com.example:app", Version: "1.2.0-SNAPSHOT"}, {Module: "com.example:parent", Version: "1.2.0"}}, project))
+	// unresolved versions are no releases
+	assert.False(t, mavenReleaseBuild([]maven.ModuleDependencies{}, project))
+	assert.True(t, mavenReleaseBuild([]maven.ModuleDependencies{}, &maven.Project{Version: "${revision}", Properties: maven.Properties{"revision": "1.2.0"}}))
+}
+
+func TestMavenProjectVersion(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "1.0.0", mavenProjectVersion(&maven.Project{Version: "1.0.0"}))
+	assert.Equal(t, "2.0.0-SNAPSHOT", mavenProjectVersion(&maven.Project{Parent: maven.Parent{Version: "2.0.0-SNAPSHOT"}}))
+	assert.Equal(t, "3.1.0", mavenProjectVersion(&maven.Project{Version: "${revision}", Properties: maven.Properties{"revision": "3.1.0"}}))
+}
+
+func TestPublishedArtifactURL(t *testing.T) {
+	t.Parallel()
+	project := &maven.Project{Parent: maven.Parent{GroupID: "com.example", Version: "2.0.0"}, ArtifactID: "app"}
+
+	url, err := publishedArtifactURL("https://repo.example.com/releases", mavenArtifact{path: "app/target/app-2.0.0-tests.jar", project: project})
+	require.NoError(t, err)
+	assert.Equal(t, "https://repo.example.com/releases/com/example/app/2.0.0/app-2.0.0-tests.jar", url)
+
+	url, err = publishedArtifactURL("https://repo.example.com/releases", mavenArtifact{path: "app/target/app.war", project: project})
+	require.NoError(t, err)
+	assert.Equal(t, "https://repo.example.com/releases/com/example/app/2.0.0/app-2.0.0.war", url)
+
+	_, err = publishedArtifactURL("https://repo.example.com/releases", mavenArtifact{path: "target/app.jar", project: &maven.Project{GroupID: "com.example", ArtifactID: "app", Version: "${revision}"}})
+	assert.ErrorContains(t, err, "CI friendly versions are not supported")
+
+	_, err = publishedArtifactURL("https://repo.example.com/releases", mavenArtifact{path: "target/app.jar", project: &maven.Project{GroupID: "com.example", ArtifactID: "app", Version: "1.0.0-SNAPSHOT"}})
+	assert.ErrorContains(t, err, "snapshot version '1.0.0-SNAPSHOT'")
+}
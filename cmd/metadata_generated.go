@@ -80,7 +80,9 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"gradleExecuteBuild":                        gradleExecuteBuildMetadata(),
 		"hadolintExecute":                           hadolintExecuteMetadata(),
 		"helmBuild":                                 helmBuildMetadata(),
+		"imageLint":                                 imageLintMetadata(),
 		"imagePushToRegistry":                       imagePushToRegistryMetadata(),
+		"imageVerifySignature":                      imageVerifySignatureMetadata(),
 		"influxWriteData":                           influxWriteDataMetadata(),
 		"integrationArtifactDeploy":                 integrationArtifactDeployMetadata(),
 		"integrationArtifactDownload":               integrationArtifactDownloadMetadata(),
@@ -97,6 +99,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"kanikoExecute":                             kanikoExecuteMetadata(),
 		"karmaExecuteTests":                         karmaExecuteTestsMetadata(),
 		"kubernetesDeploy":                          kubernetesDeployMetadata(),
+		"licenseComplianceCheck":                    licenseComplianceCheckMetadata(),
 		"malwareExecuteScan":                        malwareExecuteScanMetadata(),
 		"mavenBuild":                                mavenBuildMetadata(),
 		"mavenExecute":                              mavenExecuteMetadata(),
@@ -108,9 +111,11 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"npmExecuteLint":                            npmExecuteLintMetadata(),
 		"npmExecuteScripts":                         npmExecuteScriptsMetadata(),
 		"npmExecuteTests":                           npmExecuteTestsMetadata(),
+		"ociArtifactExecute":                        ociArtifactExecuteMetadata(),
 		"pipelineCreateScanSummary":                 pipelineCreateScanSummaryMetadata(),
 		"protecodeExecuteScan":                      protecodeExecuteScanMetadata(),
 		"pythonBuild":                               pythonBuildMetadata(),
+		"secretScan":                                secretScanMetadata(),
 		"shellExecute":                              shellExecuteMetadata(),
 		"sonarExecuteScan":                          sonarExecuteScanMetadata(),
 		"terraformExecute":                          terraformExecuteMetadata(),
@@ -86,6 +86,7 @@ func (m MTABuildTarget) String() string {
 
 type mtaBuildUtils interface {
 	maven.Utils
+	piperutils.FileUtils
 
 	SetEnv(env []string)
 	AppendEnv(env []string)
@@ -151,6 +152,7 @@ func mtaBuild(config mtaBuildOptions, _ *telemetry.CustomData, commonPipelineEnv
 }
 
 func runMtaBuild(config mtaBuildOptions, commonPipelineEnvironment *mtaBuildCommonPipelineEnvironment, utils mtaBuildUtils) error {
+	startedOn := time.Now()
 	if err := handleSettingsFiles(config, utils); err != nil {
 		return err
 	}
@@ -254,6 +256,18 @@ func runMtaBuild(config mtaBuildOptions, commonPipelineEnvironment *mtaBuildComm
 	commonPipelineEnvironment.mtarFilePath = filepath.ToSlash(getMtarFilePath(config, mtarName))
 	commonPipelineEnvironment.custom.mtaBuildToolDesc = filepath.ToSlash(mtaYamlFile)
 
+	if config.CreateProvenance {
+		mtarPath := getMtarFilePath(config, mtarName)
+		subjects, err := build.FileSubjects([]string{mtarPath}, utils)
+		if err != nil {
+			return fmt.Errorf("failed to create provenance: %w", err)
+		}
+		sbomPath := filepath.Join(filepath.Dir(mtarPath), "sbom-gen/bom-mta.xml")
+		if _, err := writeProvenance(mtaBuildMetadata(), config, subjects, []string{sbomPath}, startedOn, utils); err != nil {
+			return fmt.Errorf("failed to create provenance: %w", err)
+		}
+	}
+
 	if config.InstallArtifacts {
 		if err = installMavenArtifacts(utils, config); err != nil {
 			return err
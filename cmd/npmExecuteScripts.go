@@ -1,13 +1,24 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/build"
+	"github.com/SAP/jenkins-library/pkg/buildcache"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
+	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/npm"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/versioning"
 )
@@ -16,22 +27,69 @@ func npmExecuteScripts(config npmExecuteScriptsOptions, telemetryData *telemetry
 	npmExecutorOptions := npm.ExecutorOptions{
 		DefaultNpmRegistry: config.DefaultNpmRegistry,
 		PnpmVersion:        config.PnpmVersion,
+		BunVersion:         config.BunVersion,
+		Workspace:          npm.WorkspaceOptions{Parallelism: config.ScriptParallelism},
+		Publish:            npm.PublishOptions{VerifyIntegrity: config.VerifyPublishIntegrity, SkipPublishedVersions: config.SkipPublishedVersions},
+	}
+	if config.Publish && config.PublishProvenance {
+		// npm signs provenance statements only with the identity tokens of supported CI systems
+		if !npmProvenanceSupported() {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			log.Entry().Fatal("npm provenance is only supported on GitHub Actions and GitLab CI, use createProvenance to attest the packed packages on other orchestrators")
+		}
+		npmExecutorOptions.Publish.Provenance = true
+	}
+	if config.AffectedPackagesOnly {
+		runner := &command.Command{StepName: "npmExecuteScripts"}
+		changedFiles, err := npmChangedFiles(config.AffectedPackagesBaseRef, orchestrator.GetOrchestratorConfigProvider(nil), runner)
+		if err != nil {
+			log.Entry().WithError(err).Warn("failed to determine the changed files, running the scripts in all packages")
+		}
+		npmExecutorOptions.Workspace.ChangedFiles = changedFiles
 	}
 	npmExecutor := npm.NewExecutor(npmExecutorOptions)
 
-	err := runNpmExecuteScripts(npmExecutor, &config, commonPipelineEnvironment)
+	err := runNpmExecuteScripts(npmExecutor, &config, commonPipelineEnvironment, &piperutils.Files{})
 	if err != nil {
 		log.SetErrorCategory(log.ErrorBuild)
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
 }
 
-func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOptions, commonPipelineEnvironment *npmExecuteScriptsCommonPipelineEnvironment) error {
+// npmPackedTarballPatterns returns the patterns of the tarballs created by packing the packages, e.g. before publishing.
+// The package managers write the tarball into the directory of the package.
+func npmPackedTarballPatterns(npmExecutor npm.Executor, config *npmExecuteScriptsOptions) ([]string, error) {
+	packageJSONFiles := config.BuildDescriptorList
+	if len(packageJSONFiles) == 0 {
+		var err error
+		if packageJSONFiles, err = npmExecutor.FindPackageJSONFilesWithExcludes(config.BuildDescriptorExcludeList); err != nil {
+			return nil, err
+		}
+	}
+	patterns := []string{}
+	for _, packageJSON := range packageJSONFiles {
+		patterns = append(patterns, filepath.Join(filepath.Dir(packageJSON), "*.tgz"))
+	}
+	return patterns, nil
+}
+
+// npmProvenanceSupported checks whether npm can publish with provenance on the current CI system
+func npmProvenanceSupported() bool {
+	return orchestrator.DetectOrchestrator() == orchestrator.GitHubActions || os.Getenv("GITLAB_CI") == "true"
+}
+
+func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOptions, commonPipelineEnvironment *npmExecuteScriptsCommonPipelineEnvironment, utils piperutils.FileUtils) error {
+	startedOn := time.Now()
 	// setting env. variable to omit installation of dev. dependencies
 	if config.Production {
 		os.Setenv("NODE_ENV", "production")
 	}
 
+	dependencyCache := restoreDependencyCache(config.DependencyCacheLocation, config.DependencyCacheCredentials, config.DependencyCacheSave,
+		func() (string, error) {
+			return buildcache.FilesKey("npm", buildcache.NpmLockFiles, utils)
+		}, buildcache.NpmDirs())
+
 	if config.Install {
 		if len(config.BuildDescriptorList) > 0 {
 			if err := npmExecutor.InstallAllDependencies(config.BuildDescriptorList); err != nil {
@@ -70,6 +128,7 @@ func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOpt
 	if err != nil {
 		return err
 	}
+	dependencyCache.Save(context.Background())
 
 	log.Entry().Debugf("creating build settings information...")
 	stepName := "npmExecuteScripts"
@@ -112,6 +171,20 @@ func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOpt
 		}
 	}
 
+	if config.CreateProvenance {
+		patterns, err := npmPackedTarballPatterns(npmExecutor, config)
+		if err != nil {
+			return fmt.Errorf("failed to create provenance: %w", err)
+		}
+		subjects, err := provenanceFileSubjects(patterns, utils)
+		if err != nil {
+			return fmt.Errorf("failed to create provenance: %w", err)
+		}
+		if _, err := writeProvenance(npmExecuteScriptsMetadata(), config, subjects, []string{"**/bom-npm.xml"}, startedOn, utils); err != nil {
+			return fmt.Errorf("failed to create provenance: %w", err)
+		}
+	}
+
 	if config.CreateBuildArtifactsMetadata {
 		if len(buildCoordinates) == 0 {
 			log.Entry().Warnf("unable to identify artifact coordinates for the npm packages published")
@@ -127,3 +200,40 @@ func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOpt
 
 	return nil
 }
+
+// npmChangedFiles returns the files changed compared to the base reference, which defaults to the target branch of a pull request.
+// Without base reference the files changed by the commits of the build are returned, nil is returned if they are unknown.
+func npmChangedFiles(baseRef string, provider orchestrator.ConfigProvider, runner command.ExecRunner) ([]string, error) {
+	if baseRef == "" && provider.IsPullRequest() && provider.PullRequestConfig().Base != "" {
+		baseRef = "origin/" + provider.PullRequestConfig().Base
+	}
+	diffs := [][]string{}
+	if baseRef != "" {
+		diffs = append(diffs, []string{"diff", "--name-only", baseRef + "...HEAD"})
+	} else {
+		for _, changeSet := range provider.ChangeSets() {
+			diffs = append(diffs, []string{"diff-tree", "--no-commit-id", "--name-only", "-r", changeSet.CommitId})
+		}
+	}
+	if len(diffs) == 0 {
+		return nil, fmt.Errorf("neither a base reference nor the commits of the build are known")
+	}
+
+	changedFiles := []string{}
+	for _, diff := range diffs {
+		var stdout bytes.Buffer
+		runner.Stdout(&stdout)
+		err := runner.RunExecutable("git", diff...)
+		runner.Stdout(log.Writer())
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine changed files with git %s: %w", strings.Join(diff, " "), err)
+		}
+		for _, file := range strings.Split(stdout.String(), "\n") {
+			if file = strings.TrimSpace(file); file != "" && !slices.Contains(changedFiles, file) {
+				changedFiles = append(changedFiles, file)
+			}
+		}
+	}
+	log.Entry().Infof("Found %v changed file(s)", len(changedFiles))
+	return changedFiles, nil
+}
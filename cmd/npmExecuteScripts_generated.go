@@ -30,7 +30,11 @@ type npmExecuteScriptsOptions struct {
 	ScriptOptions                []string `json:"scriptOptions,omitempty"`
 	BuildDescriptorExcludeList   []string `json:"buildDescriptorExcludeList,omitempty"`
 	BuildDescriptorList          []string `json:"buildDescriptorList,omitempty"`
+	AffectedPackagesOnly         bool     `json:"affectedPackagesOnly,omitempty"`
+	AffectedPackagesBaseRef      string   `json:"affectedPackagesBaseRef,omitempty"`
+	ScriptParallelism            int      `json:"scriptParallelism,omitempty"`
 	CreateBOM                    bool     `json:"createBOM,omitempty"`
+	CreateProvenance             bool     `json:"createProvenance,omitempty"`
 	Publish                      bool     `json:"publish,omitempty"`
 	PublishTag                   string   `json:"publishTag,omitempty"`
 	RepositoryURL                string   `json:"repositoryUrl,omitempty"`
@@ -38,9 +42,16 @@ type npmExecuteScriptsOptions struct {
 	RepositoryUsername           string   `json:"repositoryUsername,omitempty"`
 	BuildSettingsInfo            string   `json:"buildSettingsInfo,omitempty"`
 	PackBeforePublish            bool     `json:"packBeforePublish,omitempty"`
+	PublishProvenance            bool     `json:"publishProvenance,omitempty"`
+	VerifyPublishIntegrity       bool     `json:"verifyPublishIntegrity,omitempty"`
+	SkipPublishedVersions        bool     `json:"skipPublishedVersions,omitempty"`
 	Production                   bool     `json:"production,omitempty"`
 	CreateBuildArtifactsMetadata bool     `json:"createBuildArtifactsMetadata,omitempty"`
 	PnpmVersion                  string   `json:"pnpmVersion,omitempty"`
+	BunVersion                   string   `json:"bunVersion,omitempty"`
+	DependencyCacheLocation      string   `json:"dependencyCacheLocation,omitempty"`
+	DependencyCacheCredentials   string   `json:"dependencyCacheCredentials,omitempty"`
+	DependencyCacheSave          bool     `json:"dependencyCacheSave,omitempty"`
 }
 
 type npmExecuteScriptsCommonPipelineEnvironment struct {
@@ -110,7 +121,7 @@ func (p *npmExecuteScriptsReports) persist(stepConfig npmExecuteScriptsOptions,
 	}
 }
 
-// NpmExecuteScriptsCommand Handles JavaScript dependency installation via npm, yarn or pnpm and basic npm commands.
+// NpmExecuteScriptsCommand Handles JavaScript dependency installation via npm, yarn, pnpm or Bun and basic npm commands.
 func NpmExecuteScriptsCommand() *cobra.Command {
 	const STEP_NAME = "npmExecuteScripts"
 
@@ -125,17 +136,22 @@ func NpmExecuteScriptsCommand() *cobra.Command {
 
 	var createNpmExecuteScriptsCmd = &cobra.Command{
 		Use:   STEP_NAME,
-		Short: "Handles JavaScript dependency installation via npm, yarn or pnpm and basic npm commands.",
+		Short: "Handles JavaScript dependency installation via npm, yarn, pnpm or Bun and basic npm commands.",
 		Long: `### Lock file detection:
 
   - If ` + "`" + `package-lock.json` + "`" + ` is found → runs ` + "`" + `npm ci` + "`" + `
-  - If ` + "`" + `yarn.lock.json` + "`" + ` is found → runs ` + "`" + `yarn install --frozen-lockfile` + "`" + `
+  - If ` + "`" + `yarn.lock` + "`" + ` is found → runs ` + "`" + `yarn install --frozen-lockfile` + "`" + `
+  - If ` + "`" + `yarn.lock` + "`" + ` is found together with a ` + "`" + `.yarnrc.yml` + "`" + ` file or the lock file format of Yarn 2 or later (Yarn Berry) → runs ` + "`" + `yarn install --immutable` + "`" + `
   - If ` + "`" + `pnpm-lock.yaml` + "`" + ` is found → runs ` + "`" + `pnpm install --frozen-lockfile` + "`" + `
+  - If ` + "`" + `bun.lock` + "`" + ` or ` + "`" + `bun.lockb` + "`" + ` is found → runs ` + "`" + `bun install --frozen-lockfile` + "`" + `
   - If no lock file is found → defaults to ` + "`" + `npm install` + "`" + ` and continues execution
 
-Only the install command uses the detected package manager (npm, yarn, or pnpm). All other commands (e.g., ` + "`" + `run` + "`" + `, ` + "`" + `pack` + "`" + `, ` + "`" + `publish` + "`" + `) are executed via the ` + "`" + `npm` + "`" + ` CLI, regardless of which lock file is detected.<br/>
+For npm, yarn classic and pnpm only the install command uses the detected package manager. All other commands (e.g., ` + "`" + `run` + "`" + `, ` + "`" + `pack` + "`" + `, ` + "`" + `publish` + "`" + `) are executed via the ` + "`" + `npm` + "`" + ` CLI, regardless of which lock file is detected.<br/>
+Yarn Berry and Bun projects additionally run their scripts with ` + "`" + `yarn run` + "`" + ` and ` + "`" + `bun run` + "`" + `, since with Plug'n'Play the dependencies are not installed in ` + "`" + `node_modules` + "`" + `. Their packages are always packed with ` + "`" + `yarn pack` + "`" + ` and ` + "`" + `bun pm pack` + "`" + ` before publishing, which replaces ` + "`" + `workspace:` + "`" + ` dependency ranges. Packages of Yarn Berry and Bun workspaces without own lock file use the lock file of the workspace root. For Yarn Berry the ` + "`" + `defaultNpmRegistry` + "`" + ` is configured as ` + "`" + `npmRegistryServer` + "`" + ` in the ` + "`" + `.yarnrc.yml` + "`" + ` file of the user's home directory, the SBOM is created with the [CycloneDX yarn plugin](https://github.com/CycloneDX/cyclonedx-node-yarn).<br/>
 Rationale: In the Piper environment, using the npm CLI for non-install commands provides sufficient functionality without requiring additional CLI dependencies. Supporting yarn or pnpm for these commands was deemed unnecessary due to lack of added benefit.<br/>
 If your project contains multiple package.json files (i.e., multi module projects), install command will be run in every directory where the package.json file is found. One can use ` + "`" + `buildDescriptorList` + "`" + ` or ` + "`" + `buildDescriptorExcludeList` + "`" + ` (more details below) to override the default behaviour.<br/>
+### Workspaces and monorepos:
+The scripts are run in the order of the dependencies between the packages, i.e. a package is run after the packages of the project it depends on. Packages not depending on each other can be run in parallel with ` + "`" + `scriptParallelism` + "`" + `. With ` + "`" + `affectedPackagesOnly` + "`" + ` the scripts are only run in the packages affected by the changes, which are the packages containing changed files and the packages depending on them. Changes of lock files or registry configuration in the root directory affect all packages. Determining the changed files requires the git history of the base reference, e.g. a checkout with ` + "`" + `fetch-depth: 0` + "`" + `.
 ### pnpm multi-module support:
 pnpm multi-module projects are supported when each package has its own ` + "`" + `pnpm-lock.yaml` + "`" + ` file. Workspace-based pnpm projects are not yet supported.
 ### pnpm and running tests.
@@ -176,6 +192,7 @@ If your build has scoped/unscoped dependencies from a private repository you can
 			log.SetStepErrors(stepErrors)
 			log.RegisterSecret(stepConfig.RepositoryPassword)
 			log.RegisterSecret(stepConfig.RepositoryUsername)
+			log.RegisterSecret(stepConfig.DependencyCacheCredentials)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -274,7 +291,11 @@ func addNpmExecuteScriptsFlags(cmd *cobra.Command, stepConfig *npmExecuteScripts
 	cmd.Flags().StringSliceVar(&stepConfig.ScriptOptions, "scriptOptions", []string{}, "Options are passed to all runScripts calls separated by a '--'. './piper npmExecuteScripts --runScripts ci-e2e --scriptOptions '--tag1' will correspond to 'npm run ci-e2e -- --tag1'")
 	cmd.Flags().StringSliceVar(&stepConfig.BuildDescriptorExcludeList, "buildDescriptorExcludeList", []string{`deployment/**`}, "List of build descriptors and therefore modules to exclude from execution of the npm scripts. The elements can either be a path to the build descriptor or a pattern.")
 	cmd.Flags().StringSliceVar(&stepConfig.BuildDescriptorList, "buildDescriptorList", []string{}, "List of build descriptors and therefore modules for execution of the npm scripts. The elements have to be paths to the build descriptors. **If set, buildDescriptorExcludeList will be ignored.**")
+	cmd.Flags().BoolVar(&stepConfig.AffectedPackagesOnly, "affectedPackagesOnly", false, "Runs the `runScripts` only in the packages containing files changed by the pull request or by the commits of the build and in the packages depending on them within the workspace. If the changed files cannot be determined, the scripts are run in all packages.")
+	cmd.Flags().StringVar(&stepConfig.AffectedPackagesBaseRef, "affectedPackagesBaseRef", os.Getenv("PIPER_affectedPackagesBaseRef"), "Git reference the changed files are determined against with `affectedPackagesOnly`, e.g. `origin/main`. Defaults to the target branch of the pull request, outside of pull requests the commits of the build are used.")
+	cmd.Flags().IntVar(&stepConfig.ScriptParallelism, "scriptParallelism", 1, "Maximum number of packages running a script in parallel. Packages are always run after the workspace packages they depend on.")
 	cmd.Flags().BoolVar(&stepConfig.CreateBOM, "createBOM", false, "Create a BOM xml using CycloneDX.")
+	cmd.Flags().BoolVar(&stepConfig.CreateProvenance, "createProvenance", false, "Creates an [in-toto](https://github.com/in-toto/attestation) statement with the [SLSA provenance](https://slsa.dev/spec/v1.0/provenance) of the build artifacts in `provenance/npmExecuteScripts.intoto.json`. It contains the digests of the artifacts, the invoking configuration, the source, the build run of the orchestrator and the dependencies listed in the BOM (see `createBOM`). The packages packed with `npm pack`, e.g. when publishing with `packBeforePublish`, are the build artifacts.")
 	cmd.Flags().BoolVar(&stepConfig.Publish, "publish", false, "Configures npm to publish the artifact to a repository.")
 	cmd.Flags().StringVar(&stepConfig.PublishTag, "publishTag", os.Getenv("PIPER_publishTag"), "Value of --tag flag that is passed to `npm publish` command. If not specified, it will be determined automatically depending on semver2 version (e.g., 1.0.0-202601012233 will be 'prerelease', 1.0.0 will be 'latest').")
 	cmd.Flags().StringVar(&stepConfig.RepositoryURL, "repositoryUrl", os.Getenv("PIPER_repositoryUrl"), "Url to the repository to which the project artifacts should be published.")
@@ -282,9 +303,16 @@ func addNpmExecuteScriptsFlags(cmd *cobra.Command, stepConfig *npmExecuteScripts
 	cmd.Flags().StringVar(&stepConfig.RepositoryUsername, "repositoryUsername", os.Getenv("PIPER_repositoryUsername"), "Username for the repository to which the project artifacts should be published.")
 	cmd.Flags().StringVar(&stepConfig.BuildSettingsInfo, "buildSettingsInfo", os.Getenv("PIPER_buildSettingsInfo"), "build settings info is typically filled by the step automatically to create information about the build settings that were used during the npm build . This information is typically used for compliance related processes.")
 	cmd.Flags().BoolVar(&stepConfig.PackBeforePublish, "packBeforePublish", false, "used for executing npm pack first, followed by npm publish. This two step maybe required in two cases. case 1) When building multiple npm packages (multiple package.json) please keep this parameter true and also see `buildDescriptorList` or  `buildDescriptorExcludeList` to choose which package(s) to publish. case 2)when you are building a single npm (single `package.json` in your repo) / multiple npm (multiple package.json) scoped package(s) and have npm dependencies from the same scope.")
+	cmd.Flags().BoolVar(&stepConfig.PublishProvenance, "publishProvenance", false, "Publishes the packages with a provenance statement signed by npm using `npm publish --provenance`. This requires a registry supporting provenance like the public npm registry and is only available on GitHub Actions, with the `id-token: write` permission, and on GitLab CI, with an `SIGSTORE_ID_TOKEN` ID token. The step fails on other orchestrators, use `createProvenance` there to create an [in-toto](https://github.com/in-toto/attestation) statement with the [SLSA provenance](https://slsa.dev/spec/v1.0/provenance) of the packed packages instead.")
+	cmd.Flags().BoolVar(&stepConfig.VerifyPublishIntegrity, "verifyPublishIntegrity", false, "Verifies after publishing that the integrity of the package in the registry matches the SHA-512 digest of the packed tarball. The registry is queried repeatedly with increasing delays until the published version is available. The packages are packed before publishing if `packBeforePublish` is not active.")
+	cmd.Flags().BoolVar(&stepConfig.SkipPublishedVersions, "skipPublishedVersions", false, "Skips publishing packages whose version is already available in the registry instead of failing, e.g. when re-running a pipeline.")
 	cmd.Flags().BoolVar(&stepConfig.Production, "production", false, "used for omitting installation of dev. dependencies if true")
 	cmd.Flags().BoolVar(&stepConfig.CreateBuildArtifactsMetadata, "createBuildArtifactsMetadata", false, "metadata about the artifacts that are build and published , this metadata is generally used by steps downstream in the pipeline")
 	cmd.Flags().StringVar(&stepConfig.PnpmVersion, "pnpmVersion", os.Getenv("PIPER_pnpmVersion"), "Version of pnpm to use for installation. If not specified, will use globally installed pnpm or install latest locally. Only used when pnpm-lock.yaml is detected.")
+	cmd.Flags().StringVar(&stepConfig.BunVersion, "bunVersion", os.Getenv("PIPER_bunVersion"), "Version of Bun to use for installation and running scripts. If not specified, will use globally installed bun or install latest locally. Only used when bun.lock or bun.lockb is detected.")
+	cmd.Flags().StringVar(&stepConfig.DependencyCacheLocation, "dependencyCacheLocation", os.Getenv("PIPER_dependencyCacheLocation"), "Location of the dependency cache, either a local directory (e.g. a volume shared between builds) or a bucket URL with an optional prefix: `s3://<bucket>/<prefix>`, `gs://<bucket>/<prefix>` or `azblob://<container>/<prefix>`. The cache is keyed by a hash of the `package-lock.json`, `npm-shrinkwrap.json`, `pnpm-lock.yaml`, `yarn.lock`, `bun.lock` and `bun.lockb` files and restored before the build. If empty, no dependency cache is used.")
+	cmd.Flags().StringVar(&stepConfig.DependencyCacheCredentials, "dependencyCacheCredentials", os.Getenv("PIPER_dependencyCacheCredentials"), "JSON credentials of the bucket used as dependency cache. For S3 `{\"access_key_id\": \"\", \"secret_access_key\": \"\", \"region\": \"\"}` with an optional `endpoint` for S3 compatible storages, the default AWS credential chain is used if empty. For Google Cloud Storage the JSON key of a service account. For Azure `{\"account_name\": \"\", \"sas_token\": \"\"}`.")
+	cmd.Flags().BoolVar(&stepConfig.DependencyCacheSave, "dependencyCacheSave", true, "Saves the dependency cache after a successful build if no cache was restored for the current key. Set to false for read-only access, e.g. in pull request builds.")
 
 }
 
@@ -294,7 +322,7 @@ func npmExecuteScriptsMetadata() config.StepData {
 		Metadata: config.StepMetadata{
 			Name:        "npmExecuteScripts",
 			Aliases:     []config.Alias{{Name: "executeNpm", Deprecated: false}},
-			Description: "Handles JavaScript dependency installation via npm, yarn or pnpm and basic npm commands.",
+			Description: "Handles JavaScript dependency installation via npm, yarn, pnpm or Bun and basic npm commands.",
 			Errors: []config.StepError{
 				{
 					Pattern:  "npm error code E401",
@@ -345,6 +373,9 @@ func npmExecuteScriptsMetadata() config.StepData {
 		},
 		Spec: config.StepSpec{
 			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "dependencyCacheCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the JSON credentials of the bucket used as dependency cache.", Type: "jenkins"},
+				},
 				Resources: []config.StepResources{
 					{Name: "source", Type: "stash"},
 				},
@@ -412,6 +443,33 @@ func npmExecuteScriptsMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     []string{},
 					},
+					{
+						Name:        "affectedPackagesOnly",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "affectedPackagesBaseRef",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_affectedPackagesBaseRef"),
+					},
+					{
+						Name:        "scriptParallelism",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     1,
+					},
 					{
 						Name:        "createBOM",
 						ResourceRef: []config.ResourceReference{},
@@ -421,6 +479,15 @@ func npmExecuteScriptsMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "createProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 					{
 						Name:        "publish",
 						ResourceRef: []config.ResourceReference{},
@@ -519,6 +586,33 @@ func npmExecuteScriptsMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "publishProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "verifyPublishIntegrity",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "skipPublishedVersions",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 					{
 						Name:        "production",
 						ResourceRef: []config.ResourceReference{},
@@ -546,6 +640,53 @@ func npmExecuteScriptsMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_pnpmVersion"),
 					},
+					{
+						Name:        "bunVersion",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_bunVersion"),
+					},
+					{
+						Name:        "dependencyCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_dependencyCacheLocation"),
+					},
+					{
+						Name: "dependencyCacheCredentials",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "dependencyCacheCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "dependencyCacheVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "dependency-cache",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_dependencyCacheCredentials"),
+					},
+					{
+						Name:        "dependencyCacheSave",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
 				},
 			},
 			Containers: []config.Container{
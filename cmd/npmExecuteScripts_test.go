@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 	"testing"
 
@@ -11,6 +12,7 @@ import (
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/npm"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/stretchr/testify/assert"
 )
@@ -55,7 +57,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		})
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: cfg.Install, RunScripts: cfg.RunScripts, PackagesList: cfg.BuildDescriptorList}}
-		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe, utils.FilesMock)
 
 		assert.NoError(t, err)
 	})
@@ -71,7 +73,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		})
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: cfg.Install, RunScripts: cfg.RunScripts, ExcludeList: cfg.BuildDescriptorExcludeList}}
-		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe, utils.FilesMock)
 
 		assert.NoError(t, err)
 	})
@@ -87,7 +89,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		})
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: cfg.Install, RunScripts: cfg.RunScripts, ScriptOptions: cfg.ScriptOptions}}
-		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe, utils.FilesMock)
 
 		assert.NoError(t, err)
 	})
@@ -103,7 +105,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		})
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: cfg.Install, RunScripts: cfg.RunScripts}}
-		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe, utils.FilesMock)
 
 		assert.NoError(t, err)
 	})
@@ -119,7 +121,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		})
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: cfg.Install, RunScripts: cfg.RunScripts}}
-		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe, utils.FilesMock)
 
 		assert.NoError(t, err)
 	})
@@ -135,7 +137,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		})
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: cfg.Install, RunScripts: cfg.RunScripts, VirtualFrameBuffer: cfg.VirtualFrameBuffer}}
-		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe, utils.FilesMock)
 
 		assert.NoError(t, err)
 	})
@@ -155,7 +157,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 			OpenFile: config.OpenPiperFile,
 		})
 
-		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe, utils.FilesMock)
 
 		if assert.NoError(t, err) {
 			if assert.Equal(t, 4, len(utils.execRunner.Calls)) {
@@ -178,7 +180,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		})
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: cfg.Install, RunScripts: cfg.RunScripts}}
-		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe, utils.FilesMock)
 
 		assert.NoError(t, err)
 	})
@@ -197,7 +199,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 
 		npmExecutor := npm.Execute{Utils: &utils, Options: options}
 		wantError := "could not find any package.json file with script : ci-build "
-		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe, utils.FilesMock)
 		assert.EqualErrorf(t, err, wantError, "expected to exit with error")
 	})
 
@@ -215,7 +217,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: cfg.Install, RunScripts: cfg.RunScripts}}
 
-		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &cfg, &cpe, utils.FilesMock)
 		assert.NoError(t, err)
 
 		v := os.Getenv("NODE_ENV")
@@ -223,3 +225,98 @@ func TestNpmExecuteScripts(t *testing.T) {
 	})
 
 }
+
+type npmChangedFilesConfigProvider struct {
+	orchestrator.UnknownOrchestratorConfigProvider
+	pullRequestBase string
+	changeSets      []orchestrator.ChangeSet
+}
+
+func (p *npmChangedFilesConfigProvider) IsPullRequest() bool {
+	return p.pullRequestBase != ""
+}
+
+func (p *npmChangedFilesConfigProvider) PullRequestConfig() orchestrator.PullRequestConfig {
+	return orchestrator.PullRequestConfig{Base: p.pullRequestBase}
+}
+
+func (p *npmChangedFilesConfigProvider) ChangeSets() []orchestrator.ChangeSet {
+	return p.changeSets
+}
+
+func TestNpmChangedFiles(t *testing.T) {
+	t.Run("base reference", func(t *testing.T) {
+		runner := &mock.ExecMockRunner{StdoutReturn: map[string]string{"git diff --name-only origin/main...HEAD": "packages/core/index.js\npackage.json\n"}}
+
+		changedFiles, err := npmChangedFiles("origin/main", &npmChangedFilesConfigProvider{pullRequestBase: "develop"}, runner)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"packages/core/index.js", "package.json"}, changedFiles)
+	})
+
+	t.Run("pull request base", func(t *testing.T) {
+		runner := &mock.ExecMockRunner{StdoutReturn: map[string]string{"git diff --name-only origin/develop...HEAD": "README.md\n"}}
+
+		changedFiles, err := npmChangedFiles("", &npmChangedFilesConfigProvider{pullRequestBase: "develop"}, runner)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"README.md"}, changedFiles)
+	})
+
+	t.Run("commits of the build", func(t *testing.T) {
+		runner := &mock.ExecMockRunner{StdoutReturn: map[string]string{
+			"git diff-tree --no-commit-id --name-only -r abc": "packages/web/app.js\npackages/core/index.js",
+			"git diff-tree --no-commit-id --name-only -r def": "packages/core/index.js",
+		}}
+
+		changedFiles, err := npmChangedFiles("", &npmChangedFilesConfigProvider{changeSets: []orchestrator.ChangeSet{{CommitId: "abc"}, {CommitId: "def"}}}, runner)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"packages/web/app.js", "packages/core/index.js"}, changedFiles)
+	})
+
+	t.Run("changes unknown", func(t *testing.T) {
+		changedFiles, err := npmChangedFiles("", &npmChangedFilesConfigProvider{}, &mock.ExecMockRunner{})
+
+		assert.EqualError(t, err, "neither a base reference nor the commits of the build are known")
+		assert.Nil(t, changedFiles)
+	})
+
+	t.Run("git fails", func(t *testing.T) {
+		runner := &mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{"git diff --name-only main...HEAD": errors.New("unknown revision")}}
+
+		_, err := npmChangedFiles("main", &npmChangedFilesConfigProvider{}, runner)
+
+		assert.EqualError(t, err, "failed to determine changed files with git diff --name-only main...HEAD: unknown revision")
+	})
+}
+
+func TestNpmProvenanceSupported(t *testing.T) {
+	t.Setenv("GITLAB_CI", "true")
+
+	assert.True(t, npmProvenanceSupported())
+}
+
+func TestNpmPackedTarballPatterns(t *testing.T) {
+	t.Run("packages list", func(t *testing.T) {
+		utils := npm.NewNpmMockUtilsBundle()
+		npmExecutor := npm.NpmExecutorMock{Utils: utils}
+
+		patterns, err := npmPackedTarballPatterns(&npmExecutor, &npmExecuteScriptsOptions{BuildDescriptorList: []string{"package.json", "packages/core/package.json"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"*.tgz", "packages/core/*.tgz"}, patterns)
+	})
+
+	t.Run("discovered packages", func(t *testing.T) {
+		utils := npm.NewNpmMockUtilsBundle()
+		utils.AddFile("package.json", []byte("{\"name\": \"Test\" }"))
+		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
+		npmExecutor := npm.NpmExecutorMock{Utils: utils}
+
+		patterns, err := npmPackedTarballPatterns(&npmExecutor, &npmExecuteScriptsOptions{})
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"*.tgz", "src/*.tgz"}, patterns)
+	})
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+)
+
+type ociArtifactExecuteUtils interface {
+	piperutils.FileUtils
+}
+
+type ociArtifactExecuteUtilsBundle struct {
+	*piperutils.Files
+}
+
+func newOciArtifactExecuteUtils() ociArtifactExecuteUtils {
+	return &ociArtifactExecuteUtilsBundle{Files: &piperutils.Files{}}
+}
+
+func ociArtifactExecute(config ociArtifactExecuteOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *ociArtifactExecuteCommonPipelineEnvironment) {
+	utils := newOciArtifactExecuteUtils()
+
+	if err := runOciArtifactExecute(&config, utils, commonPipelineEnvironment); err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runOciArtifactExecute(config *ociArtifactExecuteOptions, utils ociArtifactExecuteUtils, commonPipelineEnvironment *ociArtifactExecuteCommonPipelineEnvironment) error {
+	if len(config.ArtifactReference) == 0 && len(config.Subject) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.New("please configure artifactReference or subject")
+	}
+	opts, err := docker.RegistryOptions(context.Background(), config.DockerConfigJSON, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	if config.Action == "pull" {
+		return pullArtifacts(config, utils, opts...)
+	}
+
+	files, err := artifactFiles(config, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	reference, err := docker.PushArtifact(config.ArtifactReference, docker.Artifact{
+		ArtifactType: config.ArtifactType,
+		Files:        files,
+		Annotations:  config.Annotations,
+		Subject:      config.Subject,
+	}, utils, opts...)
+	if err != nil {
+		return err
+	}
+	commonPipelineEnvironment.custom.ociArtifactReference = reference
+	if _, digest, found := strings.Cut(reference, "@"); found {
+		commonPipelineEnvironment.custom.ociArtifactDigest = digest
+	}
+	return nil
+}
+
+// pullArtifacts writes the files of the artifact reference into the target directory.
+// The referrers of the subject are written to one subdirectory per artifact, since their files may have the same paths.
+func pullArtifacts(config *ociArtifactExecuteOptions, utils ociArtifactExecuteUtils, opts ...remote.Option) error {
+	if len(config.ArtifactReference) > 0 {
+		_, err := docker.PullArtifact(config.ArtifactReference, config.TargetDirectory, utils, opts...)
+		return err
+	}
+	referrers, err := docker.FindReferrers(config.Subject, config.ArtifactType, opts...)
+	if err != nil {
+		return err
+	}
+	if len(referrers) == 0 {
+		return fmt.Errorf("no artifacts of type '%v' found for '%v'", config.ArtifactType, config.Subject)
+	}
+	for _, referrer := range referrers {
+		_, digest, _ := strings.Cut(referrer, "@")
+		targetDir := filepath.Join(config.TargetDirectory, strings.ReplaceAll(digest, ":", "-"))
+		if _, err := docker.PullArtifact(referrer, targetDir, utils, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// artifactFiles resolves the file patterns and assigns the media type of the longest matching pattern
+func artifactFiles(config *ociArtifactExecuteOptions, utils ociArtifactExecuteUtils) ([]docker.ArtifactFile, error) {
+	patterns := make([]string, 0, len(config.MediaTypes))
+	for pattern := range config.MediaTypes {
+		patterns = append(patterns, pattern)
+	}
+	sort.SliceStable(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	paths := []string{}
+	for _, pattern := range config.Files {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve file pattern '%v': %w", pattern, err)
+		}
+		for _, match := range matches {
+			if !slices.Contains(paths, match) {
+				paths = append(paths, match)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files found for the patterns %v", config.Files)
+	}
+
+	files := []docker.ArtifactFile{}
+	for _, path := range paths {
+		file := docker.ArtifactFile{Path: path}
+		for _, pattern := range patterns {
+			if matched, _ := doublestar.Match(pattern, path); matched {
+				file.MediaType = config.MediaTypes[pattern]
+				break
+			}
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
@@ -0,0 +1,363 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/eventing"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/spf13/cobra"
+)
+
+type ociArtifactExecuteOptions struct {
+	Action            string            `json:"action,omitempty" validate:"possible-values=push pull"`
+	ArtifactReference string            `json:"artifactReference,omitempty"`
+	Subject           string            `json:"subject,omitempty"`
+	Files             []string          `json:"files,omitempty"`
+	ArtifactType      string            `json:"artifactType,omitempty"`
+	MediaTypes        map[string]string `json:"mediaTypes,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	TargetDirectory   string            `json:"targetDirectory,omitempty"`
+	DockerConfigJSON  string            `json:"dockerConfigJSON,omitempty"`
+}
+
+type ociArtifactExecuteCommonPipelineEnvironment struct {
+	custom struct {
+		ociArtifactReference string
+		ociArtifactDigest    string
+	}
+}
+
+func (p *ociArtifactExecuteCommonPipelineEnvironment) persist(path, resourceName string) {
+	content := []struct {
+		category string
+		name     string
+		value    interface{}
+	}{
+		{category: "custom", name: "ociArtifactReference", value: p.custom.ociArtifactReference},
+		{category: "custom", name: "ociArtifactDigest", value: p.custom.ociArtifactDigest},
+	}
+
+	errCount := 0
+	for _, param := range content {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(param.category, param.name), param.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting piper environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Error("failed to persist Piper environment")
+	}
+}
+
+// OciArtifactExecuteCommand Pushes build results as OCI artifacts to a container registry and pulls them back
+func OciArtifactExecuteCommand() *cobra.Command {
+	const STEP_NAME = "ociArtifactExecute"
+
+	metadata := ociArtifactExecuteMetadata()
+	var stepConfig ociArtifactExecuteOptions
+	var startTime time.Time
+	var commonPipelineEnvironment ociArtifactExecuteCommonPipelineEnvironment
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createOciArtifactExecuteCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Pushes build results as OCI artifacts to a container registry and pulls them back",
+		Long: `This step stores arbitrary build results like MTA archives, test reports or SBOMs as [OCI artifacts](https://github.com/opencontainers/image-spec/blob/main/manifest.md#guidelines-for-artifact-usage) in a container registry.
+Each file is stored as a layer of the artifact, the file path relative to the working directory is kept in the annotation ` + "`" + `org.opencontainers.image.title` + "`" + ` (for files outside of the working directory only the file name), hence the artifacts are compatible with [oras](https://oras.land).
+
+With ` + "`" + `subject` + "`" + ` the artifact is attached to an image as referrer, e.g. to store the SBOM or the test reports next to the image they belong to.
+Registries not supporting the referrers API are handled via the referrers tag schema.
+
+The digest reference of the pushed artifact is exported to the commonPipelineEnvironment as ` + "`" + `custom/ociArtifactReference` + "`" + ` and ` + "`" + `custom/ociArtifactDigest` + "`" + `.
+
+In a later stage the files can be pulled back with ` + "`" + `action: pull` + "`" + `, either from the ` + "`" + `artifactReference` + "`" + ` or from all artifacts of the ` + "`" + `artifactType` + "`" + ` referring to the ` + "`" + `subject` + "`" + `.
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+steps:
+  ociArtifactExecute:
+    action: push
+    files:
+      - '**/bom-*.xml'
+    artifactType: application/vnd.cyclonedx+xml
+    subject: my.registry.com/my-app:1.0.0
+` + "`" + `` + "`" + `` + "`" + ``,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err = PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			// Set step error patterns for improved error detection
+			stepErrors := make([]log.StepError, len(metadata.Metadata.Errors))
+			for i, err := range metadata.Metadata.Errors {
+				stepErrors[i] = log.StepError{
+					Pattern:  err.Pattern,
+					Message:  err.Message,
+					Category: err.Category,
+				}
+			}
+			log.SetStepErrors(stepErrors)
+			log.RegisterSecret(stepConfig.DockerConfigJSON)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			vaultClient := config.GlobalVaultClient()
+			var oidcTokenProvider func(string) (string, error)
+			if vaultClient != nil {
+				defer vaultClient.MustRevokeToken()
+				oidcTokenProvider = vaultClient.GetOIDCTokenByValidation
+			}
+
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.LogStepTelemetryData()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.GCPPubSubConfig.ProjectNumber) > 0 {
+					if err := eventing.PublishTaskRunFinishedEvent(
+						oidcTokenProvider,
+						&GeneralConfig,
+						eventing.EventContext{
+							StepName:   STEP_NAME,
+							StageName:  telemetryClient.GetData().StageName,
+							ErrorCode:  stepTelemetryData.ErrorCode,
+							PipelineID: telemetryClient.GetBuildURL(),
+						},
+					); err != nil {
+						log.Entry().WithError(err).Warn("failed to publish GCP Pub/Sub event")
+					}
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(STEP_NAME)
+			ociArtifactExecute(stepConfig, &stepTelemetryData, &commonPipelineEnvironment)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addOciArtifactExecuteFlags(createOciArtifactExecuteCmd, &stepConfig)
+	return createOciArtifactExecuteCmd
+}
+
+func addOciArtifactExecuteFlags(cmd *cobra.Command, stepConfig *ociArtifactExecuteOptions) {
+	cmd.Flags().StringVar(&stepConfig.Action, "action", `push`, "Whether the files are pushed as artifact or pulled from an artifact.")
+	cmd.Flags().StringVar(&stepConfig.ArtifactReference, "artifactReference", os.Getenv("PIPER_artifactReference"), "Reference of the artifact, e.g. `my.registry.com/my-app-artifacts:1.0.0`. Optional for artifacts with a `subject`, they are then pushed by digest into the repository of the subject.")
+	cmd.Flags().StringVar(&stepConfig.Subject, "subject", os.Getenv("PIPER_subject"), "Reference of the image the artifact refers to, e.g. `my.registry.com/my-app:1.0.0` or `my.registry.com/my-app@sha256:...`.")
+	cmd.Flags().StringSliceVar(&stepConfig.Files, "files", []string{}, "Glob patterns of the files which are pushed as artifact.")
+	cmd.Flags().StringVar(&stepConfig.ArtifactType, "artifactType", `application/vnd.unknown.artifact.v1`, "Type of the artifact, stored as media type of the artifact configuration. When pulling referrers, only artifacts of this type are pulled.")
+	cmd.Flags().StringToStringVar(&stepConfig.MediaTypes, "mediaTypes", map[string]string{}, "Media types of the files by glob pattern. If several patterns match a file, the longest pattern is used. Files without matching pattern are stored as `application/octet-stream`.\n\n```yaml\nmediaTypes:\n  '**/*.mtar': application/vnd.sap.mta+zip\n  '**/bom-*.xml': application/vnd.cyclonedx+xml\n```\n")
+	cmd.Flags().StringToStringVar(&stepConfig.Annotations, "annotations", map[string]string{}, "Annotations of the artifact, e.g. `org.opencontainers.image.version`.")
+	cmd.Flags().StringVar(&stepConfig.TargetDirectory, "targetDirectory", `.`, "Directory the pulled files are written to.\nArtifacts found via `subject` are written to a subdirectory per artifact named after its digest, e.g. `sha256-0123abcd...`, so that files with the same path in different artifacts do not overwrite each other.\n")
+	cmd.Flags().StringVar(&stepConfig.DockerConfigJSON, "dockerConfigJSON", os.Getenv("PIPER_dockerConfigJSON"), "Path to the file `.docker/config.json` - this is typically provided by your CI/CD system. You can find more details about the Docker credentials in the [Docker documentation](https://docs.docker.com/engine/reference/commandline/login/).")
+
+	cmd.MarkFlagRequired("action")
+}
+
+// retrieve step metadata
+func ociArtifactExecuteMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "ociArtifactExecute",
+			Aliases:     []config.Alias{},
+			Description: "Pushes build results as OCI artifacts to a container registry and pulls them back",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "dockerConfigJsonCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing Docker config.json (with registry credential(s)).", Type: "jenkins"},
+				},
+				Resources: []config.StepResources{
+					{Name: "buildDescriptor", Type: "stash"},
+					{Name: "buildResult", Type: "stash"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:        "action",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     `push`,
+					},
+					{
+						Name:        "artifactReference",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_artifactReference"),
+					},
+					{
+						Name:        "subject",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_subject"),
+					},
+					{
+						Name:        "files",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "artifactType",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `application/vnd.unknown.artifact.v1`,
+					},
+					{
+						Name:        "mediaTypes",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "map[string]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     map[string]string{},
+					},
+					{
+						Name:        "annotations",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "map[string]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     map[string]string{},
+					},
+					{
+						Name:        "targetDirectory",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.`,
+					},
+					{
+						Name: "dockerConfigJSON",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/dockerConfigJSON",
+							},
+
+							{
+								Name: "dockerConfigJsonCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "dockerConfigFileVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "docker-config",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_dockerConfigJSON"),
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "commonPipelineEnvironment",
+						Type: "piperEnvironment",
+						Parameters: []map[string]interface{}{
+							{"name": "custom/ociArtifactReference"},
+							{"name": "custom/ociArtifactDigest"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
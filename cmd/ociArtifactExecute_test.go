@@ -0,0 +1,115 @@
+//go:build unit
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+type ociArtifactExecuteMockUtils struct {
+	*mock.FilesMock
+}
+
+func TestRunOciArtifactExecute(t *testing.T) {
+	t.Parallel()
+	host := newSigningTestRegistry(t)
+	pushSigningTestImage(t, host+"/app:1.0.0")
+	files := &mock.FilesMock{}
+	files.AddFile("mta_archives/app.mtar", []byte("mtar"))
+	files.AddFile("target/bom-maven.xml", []byte("<bom/>"))
+	files.AddFile("bom-npm.xml", []byte("<bom/>"))
+
+	t.Run("push and pull tagged artifact", func(t *testing.T) {
+		t.Parallel()
+		config := ociArtifactExecuteOptions{
+			Action:            "push",
+			ArtifactReference: host + "/app-artifacts:1.0.0",
+			Files:             []string{"mta_archives/*.mtar"},
+			ArtifactType:      "application/vnd.sap.mta.v1",
+		}
+		cpe := ociArtifactExecuteCommonPipelineEnvironment{}
+		require.NoError(t, runOciArtifactExecute(&config, ociArtifactExecuteMockUtils{FilesMock: files}, &cpe))
+		assert.Regexp(t, "^sha256:[0-9a-f]{64}$", cpe.custom.ociArtifactDigest)
+		assert.Equal(t, host+"/app-artifacts@"+cpe.custom.ociArtifactDigest, cpe.custom.ociArtifactReference)
+
+		target := &mock.FilesMock{}
+		config = ociArtifactExecuteOptions{Action: "pull", ArtifactReference: cpe.custom.ociArtifactReference, TargetDirectory: "download"}
+		err := runOciArtifactExecute(&config, ociArtifactExecuteMockUtils{FilesMock: target}, &ociArtifactExecuteCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.True(t, target.HasFile("download/mta_archives/app.mtar"))
+	})
+
+	t.Run("push and pull referrers", func(t *testing.T) {
+		t.Parallel()
+		config := ociArtifactExecuteOptions{
+			Action:       "push",
+			Subject:      host + "/app:1.0.0",
+			Files:        []string{"**/bom-*.xml"},
+			ArtifactType: "application/vnd.cyclonedx+xml",
+		}
+		sbomCPE := ociArtifactExecuteCommonPipelineEnvironment{}
+		require.NoError(t, runOciArtifactExecute(&config, ociArtifactExecuteMockUtils{FilesMock: files}, &sbomCPE))
+		config.Files = []string{"target/bom-maven.xml"}
+		reportCPE := ociArtifactExecuteCommonPipelineEnvironment{}
+		require.NoError(t, runOciArtifactExecute(&config, ociArtifactExecuteMockUtils{FilesMock: files}, &reportCPE))
+
+		target := &mock.FilesMock{}
+		config = ociArtifactExecuteOptions{Action: "pull", Subject: host + "/app:1.0.0", ArtifactType: "application/vnd.cyclonedx+xml", TargetDirectory: "download"}
+		err := runOciArtifactExecute(&config, ociArtifactExecuteMockUtils{FilesMock: target}, &ociArtifactExecuteCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		sbomDir := "download/" + strings.ReplaceAll(sbomCPE.custom.ociArtifactDigest, ":", "-")
+		assert.True(t, target.HasFile(sbomDir+"/target/bom-maven.xml"))
+		assert.True(t, target.HasFile(sbomDir+"/bom-npm.xml"))
+		reportDir := "download/" + strings.ReplaceAll(reportCPE.custom.ociArtifactDigest, ":", "-")
+		assert.True(t, target.HasFile(reportDir+"/target/bom-maven.xml"))
+		assert.False(t, target.HasFile(reportDir+"/bom-npm.xml"))
+
+		config.ArtifactType = "application/spdx+json"
+		err = runOciArtifactExecute(&config, ociArtifactExecuteMockUtils{FilesMock: target}, &ociArtifactExecuteCommonPipelineEnvironment{})
+		assert.EqualError(t, err, "no artifacts of type 'application/spdx+json' found for '"+host+"/app:1.0.0'")
+	})
+
+	t.Run("configuration errors", func(t *testing.T) {
+		t.Parallel()
+		err := runOciArtifactExecute(&ociArtifactExecuteOptions{Action: "push"}, ociArtifactExecuteMockUtils{FilesMock: files}, &ociArtifactExecuteCommonPipelineEnvironment{})
+		assert.EqualError(t, err, "please configure artifactReference or subject")
+
+		config := ociArtifactExecuteOptions{Action: "push", ArtifactReference: host + "/app-artifacts:1.0.0", Files: []string{"*.zip"}}
+		err = runOciArtifactExecute(&config, ociArtifactExecuteMockUtils{FilesMock: files}, &ociArtifactExecuteCommonPipelineEnvironment{})
+		assert.EqualError(t, err, "no files found for the patterns [*.zip]")
+	})
+}
+
+func TestArtifactFiles(t *testing.T) {
+	t.Parallel()
+	files := &mock.FilesMock{}
+	files.AddFile("mta_archives/app.mtar", []byte("mtar"))
+	files.AddFile("target/bom-maven.xml", []byte("<bom/>"))
+	files.AddFile("target/report.xml", []byte("<report/>"))
+	config := ociArtifactExecuteOptions{
+		Files: []string{"mta_archives/*.mtar", "target/*.xml", "**/bom-*.xml"},
+		MediaTypes: map[string]string{
+			"**/*.xml":     "application/xml",
+			"**/bom-*.xml": "application/vnd.cyclonedx+xml",
+			"**/*.mtar":    "application/vnd.sap.mta+zip",
+		},
+	}
+
+	artifactFiles, err := artifactFiles(&config, ociArtifactExecuteMockUtils{FilesMock: files})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []docker.ArtifactFile{
+		{Path: "mta_archives/app.mtar", MediaType: "application/vnd.sap.mta+zip"},
+		{Path: "target/bom-maven.xml", MediaType: "application/vnd.cyclonedx+xml"},
+		{Path: "target/report.xml", MediaType: "application/xml"},
+	}, artifactFiles)
+}
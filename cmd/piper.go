@@ -158,6 +158,11 @@ func Execute() {
 	rootCmd.AddCommand(BtpCreateServiceBindingCommand())
 	rootCmd.AddCommand(BtpDeleteServiceInstanceCommand())
 	rootCmd.AddCommand(BtpDeleteServiceBindingCommand())
+	rootCmd.AddCommand(SecretScanCommand())
+	rootCmd.AddCommand(LicenseComplianceCheckCommand())
+	rootCmd.AddCommand(ImageVerifySignatureCommand())
+	rootCmd.AddCommand(ImageLintCommand())
+	rootCmd.AddCommand(OciArtifactExecuteCommand())
 
 	addRootFlags(rootCmd)
 
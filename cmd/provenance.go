@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/build"
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// writeProvenance creates the SLSA provenance of the build artifacts of a step and writes it into the provenance directory.
+// The resolved dependencies are read from the SBOMs matching the patterns. Without build artifacts no provenance is created.
+func writeProvenance(metadata config.StepData, options any, subjects []build.ResourceDescriptor, sbomPatterns []string, startedOn time.Time, utils piperutils.FileUtils) (*build.Statement, error) {
+	if len(subjects) == 0 {
+		log.Entry().Warnf("No build artifacts found, provenance of step %v is not created", metadata.Metadata.Name)
+		return nil, nil
+	}
+	sbomFiles, err := globFiles(sbomPatterns, utils)
+	if err != nil {
+		return nil, err
+	}
+	if len(sbomFiles) == 0 {
+		log.Entry().Warn("No SBOM found, the provenance will not contain the resolved dependencies. Please consider activating createBOM.")
+	}
+
+	statement, err := build.NewProvenance(build.ProvenanceOptions{
+		StepName:    metadata.Metadata.Name,
+		StepVersion: GitCommit,
+		Parameters:  provenanceParameters(metadata, options),
+		Subjects:    subjects,
+		SBOMFiles:   sbomFiles,
+		StartedOn:   startedOn,
+	}, orchestrator.GetOrchestratorConfigProvider(nil), utils)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := build.WriteProvenance(statement, metadata.Metadata.Name, utils); err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// writeImageProvenance creates the provenance of the images pushed by a step, the images are referenced by digest
+func writeImageProvenance(metadata config.StepData, options any, images []string, startedOn time.Time, utils piperutils.FileUtils) (*build.Statement, error) {
+	if len(images) == 0 {
+		log.Entry().Warnf("No pushed images found, provenance of step %v is not created", metadata.Metadata.Name)
+		return nil, nil
+	}
+	subjects, err := build.ImageSubjects(images)
+	if err != nil {
+		return nil, err
+	}
+	return writeProvenance(metadata, options, subjects, []string{"bom-docker-*.xml"}, startedOn, utils)
+}
+
+// provenanceFileSubjects returns the subjects of the build artifacts matching the patterns
+func provenanceFileSubjects(patterns []string, utils piperutils.FileUtils) ([]build.ResourceDescriptor, error) {
+	files, err := globFiles(patterns, utils)
+	if err != nil {
+		return nil, err
+	}
+	return build.FileSubjects(files, utils)
+}
+
+// globFiles returns the files matching the patterns, files in node_modules are excluded
+func globFiles(patterns []string, utils piperutils.FileUtils) ([]string, error) {
+	files := []string{}
+	for _, pattern := range patterns {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for files with pattern '%v': %w", pattern, err)
+		}
+		matches, err = piperutils.ExcludeFiles(matches, []string{"**/node_modules/**"})
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if !slices.Contains(files, match) {
+				files = append(files, match)
+			}
+		}
+	}
+	return files, nil
+}
+
+// provenanceParameters returns the invoking configuration of a step without secrets, empty values are omitted already when marshalling the options
+func provenanceParameters(metadata config.StepData, options any) map[string]any {
+	content, err := json.Marshal(options)
+	if err != nil {
+		log.Entry().Warnf("failed to read step configuration for provenance: %v", err)
+		return nil
+	}
+	values := map[string]any{}
+	if err := json.Unmarshal(content, &values); err != nil {
+		log.Entry().Warnf("failed to read step configuration for provenance: %v", err)
+		return nil
+	}
+
+	parameters := map[string]any{}
+	for _, param := range metadata.Spec.Inputs.Parameters {
+		value, ok := values[param.Name]
+		if !ok || secretParameter(param) {
+			continue
+		}
+		parameters[param.Name] = value
+	}
+	return parameters
+}
+
+// secretParameter identifies parameters which contain credentials, either declared as secret, resolved from credentials
+// or with a name indicating credentials
+func secretParameter(param config.StepParameters) bool {
+	if param.Secret {
+		return true
+	}
+	for _, ref := range param.ResourceRef {
+		if ref.Type == "secret" || ref.Type == "vaultSecret" || ref.Type == "vaultSecretFile" {
+			return true
+		}
+	}
+	name := strings.ToLower(param.Name)
+	for _, sensitive := range []string{"password", "token", "secret", "credential"} {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
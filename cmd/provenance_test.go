@@ -0,0 +1,118 @@
+//go:build unit
+
+package cmd
+
+import (
+	"crypto"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/build"
+	"github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func TestProvenanceParameters(t *testing.T) {
+	t.Parallel()
+	config := kanikoExecuteOptions{
+		ContainerImageName:        "app",
+		ContainerImageTag:         "1.0.0",
+		ContainerRegistryPassword: "registryPassword",
+		DockerConfigJSON:          "config.json",
+		SigningKeyPassword:        "keyPassword",
+		CreateProvenance:          true,
+	}
+
+	parameters := provenanceParameters(kanikoExecuteMetadata(), config)
+
+	assert.Equal(t, "app", parameters["containerImageName"])
+	assert.Equal(t, "1.0.0", parameters["containerImageTag"])
+	assert.Equal(t, true, parameters["createProvenance"])
+	assert.NotContains(t, parameters, "containerRegistryPassword")
+	assert.NotContains(t, parameters, "dockerConfigJSON")
+	assert.NotContains(t, parameters, "signingKeyPassword")
+	assert.NotContains(t, parameters, "signImages")
+}
+
+func TestWriteProvenance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("file artifacts", func(t *testing.T) {
+		t.Parallel()
+		files := &mock.FilesMock{}
+		files.AddFile("dist/app-1.0.0.tar.gz", []byte("sdist"))
+		files.AddFile("dist/app-1.0.0-py3-none-any.whl", []byte("wheel"))
+		files.AddFile("bom-pip.xml", []byte(`<bom xmlns="http://cyclonedx.org/schema/bom/1.4"><components><component type="library"><name>requests</name><purl>pkg:pypi/requests@2.31.0</purl></component></components></bom>`))
+
+		subjects, err := provenanceFileSubjects([]string{"dist/*.whl", "dist/*.tar.gz"}, files)
+		require.NoError(t, err)
+		statement, err := writeProvenance(pythonBuildMetadata(), pythonBuildOptions{CreateProvenance: true}, subjects, []string{"bom-pip.xml"}, time.Now(), files)
+
+		require.NoError(t, err)
+		assert.Len(t, statement.Subject, 2)
+		assert.Contains(t, statement.Predicate.BuildDefinition.ResolvedDependencies, build.ResourceDescriptor{Name: "requests", URI: "pkg:pypi/requests@2.31.0"})
+		assert.Equal(t, "pythonBuild", statement.Predicate.BuildDefinition.ExternalParameters["step"])
+		assert.True(t, files.HasFile("provenance/pythonBuild.intoto.json"))
+	})
+
+	t.Run("no artifacts", func(t *testing.T) {
+		t.Parallel()
+		files := &mock.FilesMock{}
+
+		statement, err := writeProvenance(npmExecuteScriptsMetadata(), npmExecuteScriptsOptions{}, nil, []string{"**/bom-npm.xml"}, time.Now(), files)
+
+		assert.NoError(t, err)
+		assert.Nil(t, statement)
+		assert.False(t, files.HasFile("provenance/npmExecuteScripts.intoto.json"))
+	})
+}
+
+func TestImageProvenance(t *testing.T) {
+	t.Parallel()
+	host := newSigningTestRegistry(t)
+	files := &mock.FilesMock{}
+	addSigningTestKeys(t, files, "cosign.key", "cosign.pub")
+	digest := pushSigningTestImage(t, host+"/app:1.0.0")
+
+	t.Run("attached to images", func(t *testing.T) {
+		t.Parallel()
+		config := cnbBuildOptions{SignImages: true, SigningKey: "cosign.key", CreateProvenance: true}
+
+		images := []string{host + "/app@" + digest}
+		statement, err := writeImageProvenance(cnbBuildMetadata(), config, images, time.Now(), files)
+		require.NoError(t, err)
+		assert.Equal(t, []build.ResourceDescriptor{{Name: host + "/app", Digest: map[string]string{"sha256": digest[len("sha256:"):]}}}, statement.Subject)
+
+		err = attachProvenance(images, statement, config.SigningKey, "", "", files)
+		require.NoError(t, err)
+
+		publicKey, _ := files.FileRead("cosign.pub")
+		key, err := docker.LoadPublicKey(publicKey)
+		require.NoError(t, err)
+		attested, err := docker.VerifyAttestation(images[0], build.ProvenancePredicateType, []crypto.PublicKey{key})
+		require.NoError(t, err)
+		attestedStatement := build.Statement{}
+		require.NoError(t, json.Unmarshal(attested, &attestedStatement))
+		assert.Equal(t, statement.Subject, attestedStatement.Subject)
+	})
+
+	t.Run("no pushed images", func(t *testing.T) {
+		t.Parallel()
+		files := &mock.FilesMock{}
+		statement, err := writeImageProvenance(kanikoExecuteMetadata(), kanikoExecuteOptions{}, nil, time.Now(), files)
+
+		assert.NoError(t, err)
+		assert.Nil(t, statement)
+		assert.False(t, files.HasFile("provenance/kanikoExecute.intoto.json"))
+	})
+
+	t.Run("missing signing key", func(t *testing.T) {
+		t.Parallel()
+		err := attachProvenance([]string{host + "/app@" + digest}, &build.Statement{}, "", "", "", files)
+		assert.EqualError(t, err, "signingKey is required for attaching the provenance to images")
+	})
+}
@@ -1,17 +1,21 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/buildcache"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/python"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -24,6 +28,7 @@ type pythonBuildUtils interface {
 	command.ExecRunner
 	FileExists(filename string) (bool, error)
 	piperutils.FileUtils
+	NewExecRunner() command.ExecRunner
 }
 
 type pythonBuildUtilsBundle struct {
@@ -44,6 +49,16 @@ func newPythonBuildUtils() pythonBuildUtils {
 	return &utils
 }
 
+// NewExecRunner returns a new execRunner for running tests concurrently
+func (u *pythonBuildUtilsBundle) NewExecRunner() command.ExecRunner {
+	execRunner := &command.Command{
+		StepName: stepName,
+	}
+	execRunner.Stdout(log.Writer())
+	execRunner.Stderr(log.Writer())
+	return execRunner
+}
+
 func pythonBuild(config pythonBuildOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *pythonBuildCommonPipelineEnvironment) {
 	utils := newPythonBuildUtils()
 
@@ -54,6 +69,7 @@ func pythonBuild(config pythonBuildOptions, telemetryData *telemetry.CustomData,
 }
 
 func runPythonBuild(config *pythonBuildOptions, telemetryData *telemetry.CustomData, utils pythonBuildUtils, commonPipelineEnvironment *pythonBuildCommonPipelineEnvironment) error {
+	startedOn := time.Now()
 	if exitHandler, err := python.CreateVirtualEnvironment(utils.RunExecutable, utils.RemoveAll, config.VirtualEnvironmentName); err != nil {
 		return err
 	} else {
@@ -61,13 +77,41 @@ func runPythonBuild(config *pythonBuildOptions, telemetryData *telemetry.CustomD
 		defer exitHandler()
 	}
 
+	dependencyCache := restoreDependencyCache(config.DependencyCacheLocation, config.DependencyCacheCredentials, config.DependencyCacheSave,
+		func() (string, error) { return buildcache.FilesKey("pip", buildcache.PipLockFiles, utils) }, buildcache.PipDirs())
+
 	// check project descriptor
 	buildDescriptorFilePath, err := searchDescriptor([]string{"pyproject.toml", "setup.py"}, utils.FileExists)
 	if err != nil {
 		return fmt.Errorf("failed to determine build descriptor file: %w", err)
 	}
 
-	if strings.HasSuffix(buildDescriptorFilePath, "pyproject.toml") {
+	buildTool := config.BuildTool
+	switch buildTool {
+	case "":
+		buildTool = python.BuildToolPip
+	case "auto":
+		buildTool = python.DetectBuildTool(utils.FileExists, utils.ReadFile)
+	}
+
+	if buildTool != python.BuildToolPip {
+		// handle projects managed by poetry, pdm, hatch or uv
+		if !strings.HasSuffix(buildDescriptorFilePath, "pyproject.toml") {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("build tool %s requires a pyproject.toml file", buildTool)
+		}
+		workDir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		// the build tools install the dependencies into the active virtual environment
+		utils.AppendEnv([]string{
+			fmt.Sprintf("VIRTUAL_ENV=%s", filepath.Join(workDir, config.VirtualEnvironmentName)),
+		})
+		if err := python.BuildWithTool(utils.RunExecutable, config.VirtualEnvironmentName, buildTool, config.SetupFlags); err != nil {
+			return fmt.Errorf("failed to build python project: %w", err)
+		}
+	} else if strings.HasSuffix(buildDescriptorFilePath, "pyproject.toml") {
 		// handle pyproject.toml file
 		workDir, err := os.Getwd()
 		if err != nil {
@@ -87,18 +131,18 @@ func runPythonBuild(config *pythonBuildOptions, telemetryData *telemetry.CustomD
 	}
 
 	if config.RunTests {
-		if err := python.InstallTestDependencies(utils.RunExecutable, config.VirtualEnvironmentName); err != nil {
-			log.SetErrorCategory(log.ErrorBuild)
-			return fmt.Errorf("failed to install test dependencies: %w", err)
-		}
-		if err := python.RunTests(utils.RunExecutable, config.VirtualEnvironmentName, config.TestOptions); err != nil {
-			log.SetErrorCategory(log.ErrorTest)
-			return fmt.Errorf("failed to run python tests: %w", err)
+		if err := runPythonTests(config, buildTool, utils); err != nil {
+			return err
 		}
 	}
+	dependencyCache.Save(context.Background())
 
 	if config.CreateBOM {
-		if err := python.CreateBOM(utils.RunExecutable, utils.FileExists, utils.ReadFile, config.VirtualEnvironmentName, config.RequirementsFilePath, cycloneDxVersion, CycloneDxSchemaVersion); err != nil {
+		if _, hasLockFile := python.LockFiles[buildTool]; hasLockFile {
+			if err := python.CreateBOMFromLockFile(utils.RunExecutable, utils.ReadFile, config.VirtualEnvironmentName, buildTool, cycloneDxVersion, CycloneDxSchemaVersion); err != nil {
+				return fmt.Errorf("failed to create BOM: %w", err)
+			}
+		} else if err := python.CreateBOM(utils.RunExecutable, utils.FileExists, utils.ReadFile, config.VirtualEnvironmentName, config.RequirementsFilePath, cycloneDxVersion, CycloneDxSchemaVersion); err != nil {
 			return fmt.Errorf("failed to create BOM: %w", err)
 		}
 	}
@@ -109,10 +153,22 @@ func runPythonBuild(config *pythonBuildOptions, telemetryData *telemetry.CustomD
 		commonPipelineEnvironment.custom.buildSettingsInfo = info
 	}
 
+	if config.CreateProvenance {
+		subjects, err := provenanceFileSubjects([]string{"dist/*.whl", "dist/*.tar.gz"}, utils)
+		if err != nil {
+			return fmt.Errorf("failed to create provenance: %w", err)
+		}
+		if _, err := writeProvenance(pythonBuildMetadata(), config, subjects, []string{python.BOMFilename}, startedOn, utils); err != nil {
+			return fmt.Errorf("failed to create provenance: %w", err)
+		}
+	}
+
 	if config.Publish {
-		if err := python.PublishPackage(
+		if err := python.PublishPackageWithTool(
 			utils.RunExecutable,
+			utils.AppendEnv,
 			config.VirtualEnvironmentName,
+			buildTool,
 			config.TargetRepositoryURL,
 			config.TargetRepositoryUser,
 			config.TargetRepositoryPassword,
@@ -123,6 +179,129 @@ func runPythonBuild(config *pythonBuildOptions, telemetryData *telemetry.CustomD
 	return nil
 }
 
+// runPythonTests runs the tests with the python of the build or with each of the configured python versions.
+// The test reports are published and the coverage is checked against the threshold.
+func runPythonTests(config *pythonBuildOptions, buildTool string, utils pythonBuildUtils) error {
+	testRunner := config.TestRunner
+	if len(testRunner) == 0 {
+		testRunner = python.TestRunnerPytest
+	}
+	if testRunner == python.TestRunnerPytest {
+		if err := python.InstallTestDependencies(utils.RunExecutable, config.VirtualEnvironmentName); err != nil {
+			log.SetErrorCategory(log.ErrorBuild)
+			return fmt.Errorf("failed to install test dependencies: %w", err)
+		}
+	} else if err := python.InstallTestRunner(utils.RunExecutable, config.VirtualEnvironmentName, testRunner); err != nil {
+		log.SetErrorCategory(log.ErrorBuild)
+		return fmt.Errorf("failed to install test dependencies: %w", err)
+	}
+
+	var testReports []python.TestReports
+	var testErr error
+	if len(config.PythonVersions) == 0 {
+		reports := python.ReportFiles("")
+		if testRunner == python.TestRunnerPytest {
+			testErr = python.RunTests(utils.RunExecutable, config.VirtualEnvironmentName, config.TestOptions)
+		} else {
+			reports, testErr = python.RunTestsWithRunner(utils.RunExecutable, config.VirtualEnvironmentName, testRunner, "", config.TestOptions)
+		}
+		testReports = []python.TestReports{reports}
+	} else if testRunner == python.TestRunnerPytest {
+		testReports, testErr = runPythonTestsInParallel(config, buildTool, utils)
+	} else {
+		// tox and nox build and install the project themselves in shared directories, hence the versions are tested one after another
+		for _, pythonVersion := range config.PythonVersions {
+			reports, err := python.RunTestsWithRunner(utils.RunExecutable, config.VirtualEnvironmentName, testRunner, pythonVersion, config.TestOptions)
+			testReports = append(testReports, reports)
+			if err != nil {
+				testErr = fmt.Errorf("python %s: %w", pythonVersion, err)
+				break
+			}
+		}
+	}
+
+	// the reports of failed tests are published as well
+	persistPythonTestReports(testReports, utils)
+	if testErr != nil {
+		log.SetErrorCategory(log.ErrorTest)
+		return fmt.Errorf("failed to run python tests: %w", testErr)
+	}
+
+	if config.CoverageThreshold > 0 {
+		for _, reports := range testReports {
+			coverage, err := python.LineCoverage(utils.FileRead, reports.Coverage)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorTest)
+				return fmt.Errorf("failed to check coverage threshold: %w", err)
+			}
+			log.Entry().Infof("Line coverage of %s is %.2f%%", reports.Coverage, coverage)
+			if coverage < float64(config.CoverageThreshold) {
+				log.SetErrorCategory(log.ErrorTest)
+				return fmt.Errorf("line coverage of %.2f%% in %s is below the threshold of %d%%", coverage, reports.Coverage, config.CoverageThreshold)
+			}
+		}
+	}
+	return nil
+}
+
+// runPythonTestsInParallel runs pytest in parallel with each of the configured python versions.
+// The test environments are created one after another as pip builds the project in the shared build directory,
+// the tests write their coverage data to a file of their own.
+func runPythonTestsInParallel(config *pythonBuildOptions, buildTool string, utils pythonBuildUtils) ([]python.TestReports, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	execRunners := make([]command.ExecRunner, len(config.PythonVersions))
+	for i, pythonVersion := range config.PythonVersions {
+		execRunners[i] = utils.NewExecRunner()
+		execRunners[i].AppendEnv([]string{
+			fmt.Sprintf("VIRTUAL_ENV=%s", filepath.Join(workDir, python.TestEnvironment(config.VirtualEnvironmentName, pythonVersion))),
+			fmt.Sprintf("COVERAGE_FILE=%s", python.CoverageDataFile(pythonVersion)),
+		})
+		if err := python.CreateTestEnvironment(execRunners[i].RunExecutable, utils.FileExists, config.VirtualEnvironmentName, pythonVersion, buildTool, config.RequirementsFilePath); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Entry().Infof("Running python tests with python %v in parallel", strings.Join(config.PythonVersions, ", "))
+	testReports := make([]python.TestReports, len(config.PythonVersions))
+	g := errgroup.Group{}
+	for i, pythonVersion := range config.PythonVersions {
+		g.Go(func() error {
+			var err error
+			testReports[i], err = python.RunTestsWithPythonVersion(execRunners[i].RunExecutable, config.VirtualEnvironmentName, pythonVersion, config.TestOptions)
+			if err != nil {
+				log.Entry().WithError(err).Errorf("python tests with python %s failed", pythonVersion)
+				return fmt.Errorf("python %s: %w", pythonVersion, err)
+			}
+			return nil
+		})
+	}
+	return testReports, g.Wait()
+}
+
+func persistPythonTestReports(testReports []python.TestReports, utils pythonBuildUtils) {
+	reports := []piperutils.Path{}
+	for _, testReport := range testReports {
+		suffix := ""
+		if len(testReport.PythonVersion) > 0 {
+			suffix = fmt.Sprintf(" (python %s)", testReport.PythonVersion)
+		}
+		if exists, _ := utils.FileExists(testReport.JUnit); exists {
+			reports = append(reports, piperutils.Path{Name: "python test results" + suffix, Target: testReport.JUnit})
+		} else {
+			log.Entry().Warnf("test results %s not found", testReport.JUnit)
+		}
+		if exists, _ := utils.FileExists(testReport.Coverage); exists {
+			reports = append(reports, piperutils.Path{Name: "python coverage report" + suffix, Target: testReport.Coverage})
+		}
+	}
+	if err := piperutils.PersistReportsAndLinks(stepName, "", utils, reports, nil); err != nil {
+		log.Entry().Warnf("failed to persist reports: %v", err)
+	}
+}
+
 // TODO: extract to common place
 func createBuildSettingsInfo(config *pythonBuildOptions) (string, error) {
 	log.Entry().Debugf("creating build settings information...")
@@ -23,18 +23,26 @@ import (
 )
 
 type pythonBuildOptions struct {
-	BuildFlags               []string `json:"buildFlags,omitempty"`
-	SetupFlags               []string `json:"setupFlags,omitempty"`
-	CreateBOM                bool     `json:"createBOM,omitempty"`
-	Publish                  bool     `json:"publish,omitempty"`
-	TargetRepositoryPassword string   `json:"targetRepositoryPassword,omitempty"`
-	TargetRepositoryUser     string   `json:"targetRepositoryUser,omitempty"`
-	TargetRepositoryURL      string   `json:"targetRepositoryURL,omitempty"`
-	BuildSettingsInfo        string   `json:"buildSettingsInfo,omitempty"`
-	VirtualEnvironmentName   string   `json:"virtualEnvironmentName,omitempty"`
-	RequirementsFilePath     string   `json:"requirementsFilePath,omitempty"`
-	RunTests                 bool     `json:"runTests,omitempty"`
-	TestOptions              []string `json:"testOptions,omitempty"`
+	BuildFlags                 []string `json:"buildFlags,omitempty"`
+	SetupFlags                 []string `json:"setupFlags,omitempty"`
+	BuildTool                  string   `json:"buildTool,omitempty" validate:"possible-values=auto pip poetry pdm hatch uv"`
+	CreateBOM                  bool     `json:"createBOM,omitempty"`
+	CreateProvenance           bool     `json:"createProvenance,omitempty"`
+	Publish                    bool     `json:"publish,omitempty"`
+	TargetRepositoryPassword   string   `json:"targetRepositoryPassword,omitempty"`
+	TargetRepositoryUser       string   `json:"targetRepositoryUser,omitempty"`
+	TargetRepositoryURL        string   `json:"targetRepositoryURL,omitempty"`
+	BuildSettingsInfo          string   `json:"buildSettingsInfo,omitempty"`
+	VirtualEnvironmentName     string   `json:"virtualEnvironmentName,omitempty"`
+	RequirementsFilePath       string   `json:"requirementsFilePath,omitempty"`
+	RunTests                   bool     `json:"runTests,omitempty"`
+	TestRunner                 string   `json:"testRunner,omitempty" validate:"possible-values=pytest tox nox"`
+	PythonVersions             []string `json:"pythonVersions,omitempty"`
+	TestOptions                []string `json:"testOptions,omitempty"`
+	CoverageThreshold          int      `json:"coverageThreshold,omitempty"`
+	DependencyCacheLocation    string   `json:"dependencyCacheLocation,omitempty"`
+	DependencyCacheCredentials string   `json:"dependencyCacheCredentials,omitempty"`
+	DependencyCacheSave        bool     `json:"dependencyCacheSave,omitempty"`
 }
 
 type pythonBuildCommonPipelineEnvironment struct {
@@ -75,8 +83,8 @@ func (p *pythonBuildReports) persist(stepConfig pythonBuildOptions, gcpJsonKeyFi
 	}
 	log.Entry().Info("Uploading reports to Google Cloud Storage...")
 	content := []gcs.ReportOutputParam{
-		{FilePattern: "**/TEST-python.xml", ParamRef: "", StepResultType: "junit"},
-		{FilePattern: "**/cobertura-coverage.xml", ParamRef: "", StepResultType: "cobertura-coverage"},
+		{FilePattern: "**/TEST-python*.xml", ParamRef: "", StepResultType: "junit"},
+		{FilePattern: "**/cobertura-coverage*.xml", ParamRef: "", StepResultType: "cobertura-coverage"},
 	}
 
 	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
@@ -119,6 +127,12 @@ func PythonBuildCommand() *cobra.Command {
 		Long: `This step will build a python project.
 It will prioritize ` + "`" + `pyproject.toml` + "`" + ` file but can also be used with a ` + "`" + `setup.py` + "`" + ` manifest and builds a wheel and tarball artifact.
 
+### Build with Poetry, PDM, Hatch or uv
+
+Projects managed with [Poetry](https://python-poetry.org), [PDM](https://pdm-project.org), [Hatch](https://hatch.pypa.io) or [uv](https://docs.astral.sh/uv/) are built with the respective tool if it is set with ` + "`" + `buildTool` + "`" + `. With ` + "`" + `buildTool: auto` + "`" + ` the tool is detected from the lock file (` + "`" + `poetry.lock` + "`" + `, ` + "`" + `pdm.lock` + "`" + `, ` + "`" + `uv.lock` + "`" + `), a ` + "`" + `hatch.toml` + "`" + ` file or the ` + "`" + `[tool.poetry]` + "`" + ` and ` + "`" + `[tool.hatch.envs]` + "`" + ` sections of the ` + "`" + `pyproject.toml` + "`" + `.
+The tool is installed into the virtual environment, the dependencies are installed from the lock file, which has to be up to date, and the wheel and tarball are built into the ` + "`" + `dist` + "`" + ` folder. The ` + "`" + `setupFlags` + "`" + ` are passed to the build command of the tool.
+The packages are published and the BOM is created from the lock file with the tool as well.
+
 ### Build with depedencies from a private repository
 
 If your build has dependencies from a private repository you can include the standard ` + "`" + `requirements.txt` + "`" + ` into the source code with ` + "`" + `--extra-index-url` + "`" + ` as the first line
@@ -160,6 +174,7 @@ The variables ` + "`" + `PIPER_VAULTCREDENTIAL_USERNAME` + "`" + ` and ` + "`" +
 			log.SetStepErrors(stepErrors)
 			log.RegisterSecret(stepConfig.TargetRepositoryPassword)
 			log.RegisterSecret(stepConfig.TargetRepositoryUser)
+			log.RegisterSecret(stepConfig.DependencyCacheCredentials)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -253,16 +268,24 @@ The variables ` + "`" + `PIPER_VAULTCREDENTIAL_USERNAME` + "`" + ` and ` + "`" +
 func addPythonBuildFlags(cmd *cobra.Command, stepConfig *pythonBuildOptions) {
 	cmd.Flags().StringSliceVar(&stepConfig.BuildFlags, "buildFlags", []string{}, "Defines list of build flags passed to python binary.")
 	cmd.Flags().StringSliceVar(&stepConfig.SetupFlags, "setupFlags", []string{}, "Defines list of flags passed to setup.py / build module.")
+	cmd.Flags().StringVar(&stepConfig.BuildTool, "buildTool", `pip`, "Defines the tool building the project, by default the project is built with pip.\nWith `auto` the tool is detected from the lock file and the configuration of the project, projects without them are built with pip.\n")
 	cmd.Flags().BoolVar(&stepConfig.CreateBOM, "createBOM", false, "Creates the bill of materials (BOM) using CycloneDX plugin.")
+	cmd.Flags().BoolVar(&stepConfig.CreateProvenance, "createProvenance", false, "Creates an [in-toto](https://github.com/in-toto/attestation) statement with the [SLSA provenance](https://slsa.dev/spec/v1.0/provenance) of the build artifacts in `provenance/pythonBuild.intoto.json`. It contains the digests of the artifacts, the invoking configuration, the source, the build run of the orchestrator and the dependencies listed in the BOM (see `createBOM`). The wheels and source distributions in the `dist` folder are the build artifacts.")
 	cmd.Flags().BoolVar(&stepConfig.Publish, "publish", false, "Configures the build to publish artifacts to a repository.")
 	cmd.Flags().StringVar(&stepConfig.TargetRepositoryPassword, "targetRepositoryPassword", os.Getenv("PIPER_targetRepositoryPassword"), "Password for the target repository where the compiled binaries shall be uploaded - typically provided by the CI/CD environment.")
 	cmd.Flags().StringVar(&stepConfig.TargetRepositoryUser, "targetRepositoryUser", os.Getenv("PIPER_targetRepositoryUser"), "Username for the target repository where the compiled binaries shall be uploaded - typically provided by the CI/CD environment.")
 	cmd.Flags().StringVar(&stepConfig.TargetRepositoryURL, "targetRepositoryURL", os.Getenv("PIPER_targetRepositoryURL"), "URL of the target repository where the compiled binaries shall be uploaded - typically provided by the CI/CD environment.")
 	cmd.Flags().StringVar(&stepConfig.BuildSettingsInfo, "buildSettingsInfo", os.Getenv("PIPER_buildSettingsInfo"), "build settings info is typically filled by the step automatically to create information about the build settings that were used during the build. This information is typically used for compliance related processes.")
 	cmd.Flags().StringVar(&stepConfig.VirtualEnvironmentName, "virtualEnvironmentName", `piperBuild-env`, "name of the virtual environment that will be used for the build")
-	cmd.Flags().StringVar(&stepConfig.RequirementsFilePath, "requirementsFilePath", `requirements.txt`, "file path to the requirements.txt file needed for the sbom cycloneDx file creation.")
-	cmd.Flags().BoolVar(&stepConfig.RunTests, "runTests", false, "When set to true, pytest and pytest-cov are installed into the virtual environment via pip and the test suite\nis executed. JUnit and Cobertura XML reports are written to TEST-python.xml and cobertura-coverage.xml\nrespectively. Any non-zero pytest exit code — including exit code 5 (no tests collected) — causes the step\nto fail. Poetry-managed projects should omit this flag and use a preBuild extension to run tests instead,\nas pytest is installed directly with pip outside of poetry's dependency management.\n")
+	cmd.Flags().StringVar(&stepConfig.RequirementsFilePath, "requirementsFilePath", `requirements.txt`, "file path to the requirements.txt file needed for the sbom cycloneDx file creation. It is also installed into the test environments of the `pythonVersions` if it exists.")
+	cmd.Flags().BoolVar(&stepConfig.RunTests, "runTests", false, "When set to true, the tests are executed with the `testRunner`. For pytest, pytest and pytest-cov are installed\ninto the virtual environment via pip. JUnit and Cobertura XML reports are written to TEST-python.xml and\ncobertura-coverage.xml respectively, with the python version as suffix if `pythonVersions` are configured.\nAny non-zero exit code — including pytest exit code 5 (no tests collected) — causes the step to fail.\n")
+	cmd.Flags().StringVar(&stepConfig.TestRunner, "testRunner", `pytest`, "Defines the tool running the tests. tox and nox are installed into the virtual environment and the report\noptions are passed to them as positional arguments, which the tox environments and nox sessions have to forward\nto pytest, e.g. `commands = pytest {posargs}` or `session.run(\"pytest\", *session.posargs)`.\n")
+	cmd.Flags().StringSliceVar(&stepConfig.PythonVersions, "pythonVersions", []string{}, "Python versions the tests are run with, e.g. `3.11` and `3.12`. The versions have to be installed on the agent.\nWith pytest the versions are tested in parallel, each in a virtual environment of its own created with `python<version>`,\ninto which the project is installed like into the virtual environment of the build, including the `requirementsFilePath`\nor the locked dependencies of the `buildTool`. Each version writes its coverage data to a `.coverage.py<version>` file.\nWith tox the environment `py<version>` and with nox the sessions of the version are run one after another.\nIf empty, the tests are run with the python of the build.\n")
 	cmd.Flags().StringSliceVar(&stepConfig.TestOptions, "testOptions", []string{}, "List of additional options passed verbatim to pytest after the injected report flags (--junitxml, --cov, --cov-report).")
+	cmd.Flags().IntVar(&stepConfig.CoverageThreshold, "coverageThreshold", 0, "Minimum line coverage in percent of the tests, the step fails if the coverage of any test run is lower. If 0, the coverage is not checked.")
+	cmd.Flags().StringVar(&stepConfig.DependencyCacheLocation, "dependencyCacheLocation", os.Getenv("PIPER_dependencyCacheLocation"), "Location of the dependency cache, either a local directory (e.g. a volume shared between builds) or a bucket URL with an optional prefix: `s3://<bucket>/<prefix>`, `gs://<bucket>/<prefix>` or `azblob://<container>/<prefix>`. The cache is keyed by a hash of the `requirements*.txt` files and the lock files of Poetry, PDM and uv and restored before the build. If empty, no dependency cache is used.")
+	cmd.Flags().StringVar(&stepConfig.DependencyCacheCredentials, "dependencyCacheCredentials", os.Getenv("PIPER_dependencyCacheCredentials"), "JSON credentials of the bucket used as dependency cache. For S3 `{\"access_key_id\": \"\", \"secret_access_key\": \"\", \"region\": \"\"}` with an optional `endpoint` for S3 compatible storages, the default AWS credential chain is used if empty. For Google Cloud Storage the JSON key of a service account. For Azure `{\"account_name\": \"\", \"sas_token\": \"\"}`.")
+	cmd.Flags().BoolVar(&stepConfig.DependencyCacheSave, "dependencyCacheSave", true, "Saves the dependency cache after a successful build if no cache was restored for the current key. Set to false for read-only access, e.g. in pull request builds.")
 
 }
 
@@ -276,6 +299,9 @@ func pythonBuildMetadata() config.StepData {
 		},
 		Spec: config.StepSpec{
 			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "dependencyCacheCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the JSON credentials of the bucket used as dependency cache.", Type: "jenkins"},
+				},
 				Parameters: []config.StepParameters{
 					{
 						Name:        "buildFlags",
@@ -295,6 +321,15 @@ func pythonBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     []string{},
 					},
+					{
+						Name:        "buildTool",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `pip`,
+					},
 					{
 						Name:        "createBOM",
 						ResourceRef: []config.ResourceReference{},
@@ -304,6 +339,15 @@ func pythonBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "createProvenance",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 					{
 						Name:        "publish",
 						ResourceRef: []config.ResourceReference{},
@@ -396,6 +440,24 @@ func pythonBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "testRunner",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `pytest`,
+					},
+					{
+						Name:        "pythonVersions",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
 					{
 						Name:        "testOptions",
 						ResourceRef: []config.ResourceReference{},
@@ -405,6 +467,53 @@ func pythonBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     []string{},
 					},
+					{
+						Name:        "coverageThreshold",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     0,
+					},
+					{
+						Name:        "dependencyCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_dependencyCacheLocation"),
+					},
+					{
+						Name: "dependencyCacheCredentials",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "dependencyCacheCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "dependencyCacheVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "dependency-cache",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_dependencyCacheCredentials"),
+					},
+					{
+						Name:        "dependencyCacheSave",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -423,8 +532,8 @@ func pythonBuildMetadata() config.StepData {
 						Name: "reports",
 						Type: "reports",
 						Parameters: []map[string]interface{}{
-							{"filePattern": "**/TEST-python.xml", "type": "junit"},
-							{"filePattern": "**/cobertura-coverage.xml", "type": "cobertura-coverage"},
+							{"filePattern": "**/TEST-python*.xml", "type": "junit"},
+							{"filePattern": "**/cobertura-coverage*.xml", "type": "cobertura-coverage"},
 						},
 					},
 				},
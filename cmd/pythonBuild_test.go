@@ -5,11 +5,14 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/mock"
@@ -17,20 +20,37 @@ import (
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type pythonBuildMockUtils struct {
 	config *pythonBuildOptions
 	*mock.ExecMockRunner
 	*mock.FilesMock
+	concurrentRunners *pythonConcurrentRunners
+}
+
+// pythonConcurrentRunners records the exec runners of the tests running concurrently
+type pythonConcurrentRunners struct {
+	mutex   sync.Mutex
+	runners []*mock.ExecMockRunner
+}
+
+func (f pythonBuildMockUtils) NewExecRunner() command.ExecRunner {
+	runner := &mock.ExecMockRunner{ShouldFailOnCommand: f.ExecMockRunner.ShouldFailOnCommand}
+	f.concurrentRunners.mutex.Lock()
+	defer f.concurrentRunners.mutex.Unlock()
+	f.concurrentRunners.runners = append(f.concurrentRunners.runners, runner)
+	return runner
 }
 
 const minimalSetupPyFileContent = "from setuptools import setup\n\nsetup(name='MyPackageName',version='1.0.0')"
 
 func newPythonBuildTestsUtils() pythonBuildMockUtils {
 	utils := pythonBuildMockUtils{
-		ExecMockRunner: &mock.ExecMockRunner{},
-		FilesMock:      &mock.FilesMock{},
+		ExecMockRunner:    &mock.ExecMockRunner{},
+		FilesMock:         &mock.FilesMock{},
+		concurrentRunners: &pythonConcurrentRunners{},
 	}
 	return utils
 }
@@ -138,6 +158,47 @@ func TestRunPythonBuild(t *testing.T) {
 		assert.Equal(t, filepath.Join("dummy", "bin", "cyclonedx-py"), utils.ExecMockRunner.Calls[7].Exec)
 		assert.Equal(t, []string{"env", "--output-file", "bom-pip.xml", "--output-format", "XML", "--spec-version", "1.4"}, utils.ExecMockRunner.Calls[7].Params)
 	})
+
+	t.Run("success - build, BOM and publish with detected uv", func(t *testing.T) {
+		config := pythonBuildOptions{
+			BuildTool:                "auto",
+			CreateBOM:                true,
+			Publish:                  true,
+			TargetRepositoryURL:      "https://my.target.repository.local",
+			TargetRepositoryUser:     "user",
+			TargetRepositoryPassword: "password",
+			VirtualEnvironmentName:   "dummy",
+		}
+		utils := newPythonBuildTestsUtils()
+		utils.AddFile("pyproject.toml", []byte("[project]\nname = \"example\"\nversion = \"1.0.0\"\n"))
+		utils.AddFile("uv.lock", []byte("version = 1\n"))
+		utils.AddDir("dummy")
+		telemetryData := telemetry.CustomData{}
+
+		err := runPythonBuild(&config, &telemetryData, utils, &cpe)
+		assert.NoError(t, err)
+		uv := filepath.Join("dummy", "bin", "uv")
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: filepath.Join("dummy", "bin", "pip"), Params: []string{"install", "--upgrade", "--root-user-action=ignore", "uv"}},
+			{Exec: uv, Params: []string{"sync", "--locked", "--active", "--no-install-project"}},
+			{Exec: uv, Params: []string{"build"}},
+			{Exec: uv, Params: []string{"export", "--format", "requirements-txt", "--no-hashes", "--no-dev", "--no-emit-project", "--output-file", "requirements-lock.txt"}},
+			{Exec: filepath.Join("dummy", "bin", "pip"), Params: []string{"install", "--upgrade", "--root-user-action=ignore", "cyclonedx-bom==7.3.0"}},
+			{Exec: filepath.Join("dummy", "bin", "cyclonedx-py"), Params: []string{"requirements", "--output-file", "bom-pip.xml", "--output-format", "XML", "--spec-version", "1.4", "--pyproject", "pyproject.toml", "requirements-lock.txt"}},
+			{Exec: uv, Params: []string{"publish", "--publish-url", config.TargetRepositoryURL, "--username", config.TargetRepositoryUser, "--password", config.TargetRepositoryPassword, "dist/*"}},
+		}, utils.ExecMockRunner.Calls[3:])
+		assert.True(t, slices.ContainsFunc(utils.ExecMockRunner.Env, func(env string) bool { return strings.HasPrefix(env, "VIRTUAL_ENV=") }))
+	})
+
+	t.Run("failure - build tool without pyproject.toml", func(t *testing.T) {
+		config := pythonBuildOptions{BuildTool: "poetry"}
+		utils := newPythonBuildTestsUtils()
+		utils.AddFile("setup.py", []byte(minimalSetupPyFileContent))
+		telemetryData := telemetry.CustomData{}
+
+		err := runPythonBuild(&config, &telemetryData, utils, &cpe)
+		assert.EqualError(t, err, "build tool poetry requires a pyproject.toml file")
+	})
 }
 
 func TestRunPythonBuildWithToml(t *testing.T) {
@@ -491,3 +552,125 @@ func TestRunPythonBuildWithTests(t *testing.T) {
 		}
 	})
 }
+
+func TestRunPythonTests(t *testing.T) {
+	coverageReport := func(lineRate string) []byte {
+		return []byte(`<?xml version="1.0" ?><coverage line-rate="` + lineRate + `" branch-rate="0"><packages/></coverage>`)
+	}
+
+	t.Run("success - python versions in parallel", func(t *testing.T) {
+		cfg := pythonBuildOptions{
+			VirtualEnvironmentName: "dummy",
+			PythonVersions:         []string{"3.11", "3.12"},
+			CoverageThreshold:      80,
+		}
+		utils := newPythonBuildTestsUtils()
+		utils.AddFile("TEST-python-3.11.xml", []byte("<testsuites/>"))
+		utils.AddFile("TEST-python-3.12.xml", []byte("<testsuites/>"))
+		utils.AddFile("cobertura-coverage-3.11.xml", coverageReport("0.9"))
+		utils.AddFile("cobertura-coverage-3.12.xml", coverageReport("0.85"))
+
+		err := runPythonTests(&cfg, python.BuildToolPip, utils)
+
+		assert.NoError(t, err)
+		require.Len(t, utils.concurrentRunners.runners, 2)
+		pytestCalls := []mock.ExecCall{}
+		for _, runner := range utils.concurrentRunners.runners {
+			pytestCalls = append(pytestCalls, runner.Calls[len(runner.Calls)-1])
+		}
+		slices.SortFunc(pytestCalls, func(a, b mock.ExecCall) int { return strings.Compare(a.Exec, b.Exec) })
+		cwd, _ := os.Getwd()
+		assert.Equal(t, []string{"VIRTUAL_ENV=" + filepath.Join(cwd, "dummy-py3.11"), "COVERAGE_FILE=.coverage.py3.11"}, utils.concurrentRunners.runners[0].Env)
+		assert.Equal(t, []string{"VIRTUAL_ENV=" + filepath.Join(cwd, "dummy-py3.12"), "COVERAGE_FILE=.coverage.py3.12"}, utils.concurrentRunners.runners[1].Env)
+		assert.Equal(t, mock.ExecCall{Exec: "python3.11", Params: []string{"-m", "venv", "dummy-py3.11"}}, utils.concurrentRunners.runners[0].Calls[0])
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: filepath.Join("dummy-py3.11", "bin", "pytest"), Params: []string{"--junitxml=TEST-python-3.11.xml", "--cov", "--cov-report=xml:cobertura-coverage-3.11.xml"}},
+			{Exec: filepath.Join("dummy-py3.12", "bin", "pytest"), Params: []string{"--junitxml=TEST-python-3.12.xml", "--cov", "--cov-report=xml:cobertura-coverage-3.12.xml"}},
+		}, pytestCalls)
+		reports, err := utils.FileRead("pythonBuild_reports.json")
+		require.NoError(t, err)
+		for _, report := range []string{"TEST-python-3.11.xml", "TEST-python-3.12.xml", "cobertura-coverage-3.11.xml", "cobertura-coverage-3.12.xml"} {
+			assert.Contains(t, string(reports), report)
+		}
+	})
+
+	t.Run("success - tox", func(t *testing.T) {
+		cfg := pythonBuildOptions{
+			VirtualEnvironmentName: "dummy",
+			TestRunner:             "tox",
+		}
+		utils := newPythonBuildTestsUtils()
+
+		err := runPythonTests(&cfg, python.BuildToolPip, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: filepath.Join("dummy", "bin", "pip"), Params: []string{"install", "--upgrade", "--root-user-action=ignore", "tox"}},
+			{Exec: filepath.Join("dummy", "bin", "tox"), Params: []string{"run", "--", "--junitxml=TEST-python.xml", "--cov", "--cov-report=xml:cobertura-coverage.xml"}},
+		}, utils.ExecMockRunner.Calls)
+	})
+
+	t.Run("success - tox with python versions", func(t *testing.T) {
+		cfg := pythonBuildOptions{
+			VirtualEnvironmentName: "dummy",
+			TestRunner:             "tox",
+			PythonVersions:         []string{"3.11", "3.12"},
+		}
+		utils := newPythonBuildTestsUtils()
+
+		err := runPythonTests(&cfg, python.BuildToolPip, utils)
+
+		assert.NoError(t, err)
+		assert.Empty(t, utils.concurrentRunners.runners)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: filepath.Join("dummy", "bin", "pip"), Params: []string{"install", "--upgrade", "--root-user-action=ignore", "tox"}},
+			{Exec: filepath.Join("dummy", "bin", "tox"), Params: []string{"run", "-e", "py3.11", "--", "--junitxml=TEST-python-3.11.xml", "--cov", "--cov-report=xml:cobertura-coverage-3.11.xml"}},
+			{Exec: filepath.Join("dummy", "bin", "tox"), Params: []string{"run", "-e", "py3.12", "--", "--junitxml=TEST-python-3.12.xml", "--cov", "--cov-report=xml:cobertura-coverage-3.12.xml"}},
+		}, utils.ExecMockRunner.Calls)
+	})
+
+	t.Run("failure - coverage below threshold", func(t *testing.T) {
+		log.SetErrorCategory(log.ErrorUndefined)
+		defer log.SetErrorCategory(log.ErrorUndefined)
+		cfg := pythonBuildOptions{
+			VirtualEnvironmentName: "dummy",
+			CoverageThreshold:      80,
+		}
+		utils := newPythonBuildTestsUtils()
+		utils.AddFile("cobertura-coverage.xml", coverageReport("0.755"))
+
+		err := runPythonTests(&cfg, python.BuildToolPip, utils)
+
+		assert.EqualError(t, err, "line coverage of 75.50% in cobertura-coverage.xml is below the threshold of 80%")
+		assert.Equal(t, log.ErrorTest, log.GetErrorCategory())
+	})
+
+	t.Run("failure - coverage report missing", func(t *testing.T) {
+		cfg := pythonBuildOptions{
+			VirtualEnvironmentName: "dummy",
+			CoverageThreshold:      80,
+		}
+		utils := newPythonBuildTestsUtils()
+
+		err := runPythonTests(&cfg, python.BuildToolPip, utils)
+
+		assert.ErrorContains(t, err, "failed to check coverage threshold: failed to read coverage report cobertura-coverage.xml")
+	})
+
+	t.Run("failure - tests of a python version fail", func(t *testing.T) {
+		cfg := pythonBuildOptions{
+			VirtualEnvironmentName: "dummy",
+			PythonVersions:         []string{"3.11", "3.12"},
+		}
+		utils := newPythonBuildTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{filepath.Join("dummy-py3.12", "bin", "pytest"): fmt.Errorf("exit status 1")}
+		utils.AddFile("TEST-python-3.12.xml", []byte("<testsuites/>"))
+
+		err := runPythonTests(&cfg, python.BuildToolPip, utils)
+
+		assert.EqualError(t, err, "failed to run python tests: python 3.12: pytest execution failed: exit status 1")
+		reports, err := utils.FileRead("pythonBuild_reports.json")
+		require.NoError(t, err)
+		assert.Contains(t, string(reports), "TEST-python-3.12.xml")
+	})
+}
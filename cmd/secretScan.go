@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	pipergit "github.com/SAP/jenkins-library/pkg/git"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/secretscan"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+type secretScanUtils interface {
+	piperutils.FileUtils
+
+	PlainOpen(path string) (*git.Repository, error)
+}
+
+type secretScanUtilsBundle struct {
+	*piperutils.Files
+}
+
+func (s *secretScanUtilsBundle) PlainOpen(path string) (*git.Repository, error) {
+	return pipergit.PlainOpen(path)
+}
+
+func newSecretScanUtils() secretScanUtils {
+	return &secretScanUtilsBundle{Files: &piperutils.Files{}}
+}
+
+// pullRequestProvider provides the pull request information of the orchestrator
+type pullRequestProvider interface {
+	IsPullRequest() bool
+	PullRequestConfig() orchestrator.PullRequestConfig
+}
+
+func secretScan(config secretScanOptions, telemetryData *telemetry.CustomData) {
+	utils := newSecretScanUtils()
+	provider := orchestrator.GetOrchestratorConfigProvider(nil)
+
+	if err := runSecretScan(&config, utils, provider); err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runSecretScan(config *secretScanOptions, utils secretScanUtils, provider pullRequestProvider) error {
+	ruleSet, err := loadSecretScanRules(config, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	options := secretscan.Options{ExcludePaths: config.ExcludePaths, MaxFileSize: int64(config.MaxFileSize)}
+	if !config.UpdateBaseline {
+		if options.Baseline, err = loadSecretScanBaseline(config, utils); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+	}
+	scanner, err := secretscan.NewScanner(ruleSet, options)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("invalid rules: %w", err)
+	}
+
+	findings, err := executeSecretScan(config, scanner, utils, provider)
+	if err != nil {
+		return err
+	}
+	findings = secretscan.Deduplicate(findings)
+
+	reports, err := secretscan.WriteReports(findings, scanner.Rules(), utils)
+	if err != nil {
+		return err
+	}
+	piperutils.PersistReportsAndLinks("secretScan", "", utils, reports, nil)
+
+	if config.UpdateBaseline {
+		content, err := secretscan.NewBaseline(findings).ToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to create baseline: %w", err)
+		}
+		if err := utils.FileWrite(config.BaselineFile, content, 0666); err != nil {
+			return fmt.Errorf("failed to write baseline file '%v': %w", config.BaselineFile, err)
+		}
+		log.Entry().Infof("Baseline with %v finding(s) written to '%v'", len(findings), config.BaselineFile)
+		return nil
+	}
+
+	if len(findings) == 0 {
+		log.Entry().Info("No secrets detected")
+		return nil
+	}
+	for _, finding := range findings {
+		location := fmt.Sprintf("%v:%v", finding.File, finding.Line)
+		if len(finding.Commit) > 0 {
+			location = fmt.Sprintf("%v (commit %v)", location, finding.Commit)
+		}
+		log.Entry().Warnf("%v detected in %v: %v", finding.Description, location, finding.Redacted)
+	}
+	if config.FailOnFindings {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v secret(s) detected", len(findings))
+	}
+	return nil
+}
+
+func executeSecretScan(config *secretScanOptions, scanner *secretscan.Scanner, utils secretScanUtils, provider pullRequestProvider) ([]secretscan.Finding, error) {
+	if config.PullRequestDiffOnly && provider.IsPullRequest() {
+		base := provider.PullRequestConfig().Base
+		log.Entry().Infof("Scanning lines added in pull request compared to '%v'", base)
+		repository, err := utils.PlainOpen(config.ScanPath)
+		if err != nil {
+			return nil, err
+		}
+		commits, err := pullRequestCommits(repository, base)
+		if err != nil {
+			return nil, err
+		}
+		return scanner.ScanCommits(commits)
+	}
+
+	log.Entry().Infof("Scanning files in '%v'", config.ScanPath)
+	findings, err := scanner.ScanDirectory(config.ScanPath, utils)
+	if err != nil {
+		return nil, err
+	}
+	if config.ScanHistory {
+		log.Entry().Info("Scanning git history")
+		repository, err := utils.PlainOpen(config.ScanPath)
+		if err != nil {
+			return nil, err
+		}
+		commits, err := repository.Log(&git.LogOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read git history: %w", err)
+		}
+		historyFindings, err := scanner.ScanCommits(commits)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, historyFindings...)
+	}
+	return findings, nil
+}
+
+// pullRequestCommits returns the commits of the current branch which are not part of the base branch.
+// The base branch is looked up locally and as remote branch since pipelines usually only check out the pull request.
+func pullRequestCommits(repository *git.Repository, base string) (object.CommitIter, error) {
+	if len(base) == 0 {
+		return nil, errors.New("base branch of pull request not available")
+	}
+	var lastErr error
+	for _, ref := range []string{base, "origin/" + base, "refs/remotes/origin/" + base} {
+		commits, err := pipergit.LogRange(repository, ref, "HEAD")
+		if err == nil {
+			return commits, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("failed to determine commits of pull request: %w", lastErr)
+}
+
+func loadSecretScanRules(config *secretScanOptions, utils secretScanUtils) (secretscan.RuleSet, error) {
+	ruleSet := secretscan.RuleSet{}
+	if config.UseDefaultRules {
+		ruleSet = secretscan.DefaultRules()
+	}
+	if len(config.RulesFile) > 0 {
+		content, err := utils.FileRead(config.RulesFile)
+		if err != nil {
+			return ruleSet, fmt.Errorf("failed to read rules file '%v': %w", config.RulesFile, err)
+		}
+		customRules, err := secretscan.ParseRules(content)
+		if err != nil {
+			return ruleSet, err
+		}
+		ruleSet = ruleSet.Merge(customRules)
+	}
+	if len(ruleSet.Rules) == 0 {
+		return ruleSet, errors.New("no rules configured, provide a rulesFile or activate useDefaultRules")
+	}
+	return ruleSet, nil
+}
+
+func loadSecretScanBaseline(config *secretScanOptions, utils secretScanUtils) (*secretscan.Baseline, error) {
+	if len(config.BaselineFile) == 0 {
+		return nil, nil
+	}
+	exists, err := utils.FileExists(config.BaselineFile)
+	if err != nil || !exists {
+		return nil, nil
+	}
+	content, err := utils.FileRead(config.BaselineFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file '%v': %w", config.BaselineFile, err)
+	}
+	return secretscan.ParseBaseline(content)
+}
@@ -0,0 +1,363 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/eventing"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/spf13/cobra"
+)
+
+type secretScanOptions struct {
+	ScanPath            string   `json:"scanPath,omitempty"`
+	RulesFile           string   `json:"rulesFile,omitempty"`
+	UseDefaultRules     bool     `json:"useDefaultRules,omitempty"`
+	ExcludePaths        []string `json:"excludePaths,omitempty"`
+	MaxFileSize         int      `json:"maxFileSize,omitempty"`
+	ScanHistory         bool     `json:"scanHistory,omitempty"`
+	PullRequestDiffOnly bool     `json:"pullRequestDiffOnly,omitempty"`
+	BaselineFile        string   `json:"baselineFile,omitempty"`
+	UpdateBaseline      bool     `json:"updateBaseline,omitempty"`
+	FailOnFindings      bool     `json:"failOnFindings,omitempty"`
+}
+
+type secretScanReports struct {
+}
+
+func (p *secretScanReports) persist(stepConfig secretScanOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/piper_secretscan_report.json", ParamRef: "", StepResultType: "secretscan"},
+		{FilePattern: "**/piper_secretscan.sarif", ParamRef: "", StepResultType: "secretscan"},
+	}
+
+	gcsClient, err := gcs.NewClient(gcpJsonKeyFilePath, "")
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, piperutils.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// SecretScanCommand Scan the source code and its git history for hard-coded secrets
+func SecretScanCommand() *cobra.Command {
+	const STEP_NAME = "secretScan"
+
+	metadata := secretScanMetadata()
+	var stepConfig secretScanOptions
+	var startTime time.Time
+	var reports secretScanReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createSecretScanCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Scan the source code and its git history for hard-coded secrets",
+		Long: `This step detects hard-coded secrets like access keys, tokens, passwords or private keys in the source code.
+In contrast to ` + "`" + `credentialdiggerScan` + "`" + ` the scan is executed natively without the need for an external service.
+
+Secrets are detected based on a rule set combining regular expressions, keywords and the Shannon entropy of the detected value.
+The built-in rules can be extended or replaced with the ` + "`" + `rulesFile` + "`" + `, which has the following format:
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+rules:
+  - id: internal-token
+    description: Internal API token
+    regex: 'itk-([0-9a-f]{32})'
+    secretGroup: 1
+    keywords: [itk-]
+    entropy: 3
+    severity: high
+allowlist:
+  paths: ['**/testdata/**']
+  regexes: ['^dummy-.*$']
+` + "`" + `` + "`" + `` + "`" + `
+
+Findings can be suppressed by adding the comment ` + "`" + `piper:allow-secret` + "`" + ` to the affected line or by adding them to a baseline file.
+The baseline file can be created via ` + "`" + `updateBaseline` + "`" + ` and committed to the repository after the findings have been reviewed.
+
+In pull request builds only the lines added by the commits of the pull request are scanned.
+
+The findings are reported as JSON and SARIF file.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err = PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			// Set step error patterns for improved error detection
+			stepErrors := make([]log.StepError, len(metadata.Metadata.Errors))
+			for i, err := range metadata.Metadata.Errors {
+				stepErrors[i] = log.StepError{
+					Pattern:  err.Pattern,
+					Message:  err.Message,
+					Category: err.Category,
+				}
+			}
+			log.SetStepErrors(stepErrors)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 || len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			vaultClient := config.GlobalVaultClient()
+			var oidcTokenProvider func(string) (string, error)
+			if vaultClient != nil {
+				defer vaultClient.MustRevokeToken()
+				oidcTokenProvider = vaultClient.GetOIDCTokenByValidation
+			}
+
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.LogStepTelemetryData()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.Dsn,
+						GeneralConfig.HookConfig.SplunkConfig.Token,
+						GeneralConfig.HookConfig.SplunkConfig.Index,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint) > 0 {
+					splunkClient.Initialize(GeneralConfig.CorrelationID,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblEndpoint,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblToken,
+						GeneralConfig.HookConfig.SplunkConfig.ProdCriblIndex,
+						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+				if len(GeneralConfig.HookConfig.GCPPubSubConfig.ProjectNumber) > 0 {
+					if err := eventing.PublishTaskRunFinishedEvent(
+						oidcTokenProvider,
+						&GeneralConfig,
+						eventing.EventContext{
+							StepName:   STEP_NAME,
+							StageName:  telemetryClient.GetData().StageName,
+							ErrorCode:  stepTelemetryData.ErrorCode,
+							PipelineID: telemetryClient.GetBuildURL(),
+						},
+					); err != nil {
+						log.Entry().WithError(err).Warn("failed to publish GCP Pub/Sub event")
+					}
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(STEP_NAME)
+			secretScan(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addSecretScanFlags(createSecretScanCmd, &stepConfig)
+	return createSecretScanCmd
+}
+
+func addSecretScanFlags(cmd *cobra.Command, stepConfig *secretScanOptions) {
+	cmd.Flags().StringVar(&stepConfig.ScanPath, "scanPath", `.`, "Directory which is scanned.")
+	cmd.Flags().StringVar(&stepConfig.RulesFile, "rulesFile", os.Getenv("PIPER_rulesFile"), "Path to a YAML file containing additional rules and allowlist entries. Rules with the id of a built-in rule replace it.")
+	cmd.Flags().BoolVar(&stepConfig.UseDefaultRules, "useDefaultRules", true, "Whether the built-in rules are used in addition to the rules of the `rulesFile`.")
+	cmd.Flags().StringSliceVar(&stepConfig.ExcludePaths, "excludePaths", []string{`.git/**`, `**/node_modules/**`}, "Glob patterns of files which are not scanned, relative to the `scanPath`.")
+	cmd.Flags().IntVar(&stepConfig.MaxFileSize, "maxFileSize", 1048576, "Maximal size of a file in bytes to be scanned, larger files are skipped.")
+	cmd.Flags().BoolVar(&stepConfig.ScanHistory, "scanHistory", false, "Whether the lines added by all commits of the git history are scanned in addition to the current content.")
+	cmd.Flags().BoolVar(&stepConfig.PullRequestDiffOnly, "pullRequestDiffOnly", true, "In pull request builds, only scan the lines added by the commits of the pull request.")
+	cmd.Flags().StringVar(&stepConfig.BaselineFile, "baselineFile", `.secretscan-baseline.json`, "Path to the baseline file containing known findings which are not reported. A missing file is ignored.")
+	cmd.Flags().BoolVar(&stepConfig.UpdateBaseline, "updateBaseline", false, "Write all current findings into the `baselineFile` instead of failing, e.g. when introducing the scan to an existing repository.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnFindings, "failOnFindings", true, "Whether the step fails in case secrets have been detected.")
+
+}
+
+// retrieve step metadata
+func secretScanMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "secretScan",
+			Aliases:     []config.Alias{},
+			Description: "Scan the source code and its git history for hard-coded secrets",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "scanPath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.`,
+					},
+					{
+						Name:        "rulesFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_rulesFile"),
+					},
+					{
+						Name:        "useDefaultRules",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "excludePaths",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`.git/**`, `**/node_modules/**`},
+					},
+					{
+						Name:        "maxFileSize",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     1048576,
+					},
+					{
+						Name:        "scanHistory",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "pullRequestDiffOnly",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "baselineFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.secretscan-baseline.json`,
+					},
+					{
+						Name:        "updateBaseline",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "failOnFindings",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/piper_secretscan_report.json", "type": "secretscan"},
+							{"filePattern": "**/piper_secretscan.sarif", "type": "secretscan"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,177 @@
+//go:build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/secretscan"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// test secret is assembled to avoid false positives of other scanners
+var secretScanTestKey = "AKIA" + "Q3EGRTWZ5MKVBN2X"
+
+type secretScanMockUtils struct {
+	*mock.FilesMock
+	repository *git.Repository
+}
+
+func (s *secretScanMockUtils) PlainOpen(path string) (*git.Repository, error) {
+	return s.repository, nil
+}
+
+func newSecretScanTestsUtils() *secretScanMockUtils {
+	return &secretScanMockUtils{FilesMock: &mock.FilesMock{}}
+}
+
+type pullRequestProviderMock struct {
+	base string
+}
+
+func (p *pullRequestProviderMock) IsPullRequest() bool {
+	return len(p.base) > 0
+}
+
+func (p *pullRequestProviderMock) PullRequestConfig() orchestrator.PullRequestConfig {
+	return orchestrator.PullRequestConfig{Base: p.base}
+}
+
+func defaultSecretScanOptions() secretScanOptions {
+	return secretScanOptions{
+		ScanPath:            ".",
+		UseDefaultRules:     true,
+		ExcludePaths:        []string{".git/**"},
+		PullRequestDiffOnly: true,
+		BaselineFile:        ".secretscan-baseline.json",
+		FailOnFindings:      true,
+	}
+}
+
+func TestRunSecretScan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no findings", func(t *testing.T) {
+		t.Parallel()
+		config := defaultSecretScanOptions()
+		utils := newSecretScanTestsUtils()
+		utils.AddFile("src/main.go", []byte("package main"))
+
+		err := runSecretScan(&config, utils, &pullRequestProviderMock{})
+
+		assert.NoError(t, err)
+		exists, _ := utils.FileExists("secretscan/piper_secretscan.sarif")
+		assert.True(t, exists)
+	})
+
+	t.Run("findings fail the step", func(t *testing.T) {
+		t.Parallel()
+		config := defaultSecretScanOptions()
+		utils := newSecretScanTestsUtils()
+		utils.AddFile("src/config.properties", []byte("aws.key="+secretScanTestKey))
+		utils.AddFile("src/other.properties", []byte("aws.key="+secretScanTestKey+" # "+secretscan.AllowComment))
+
+		err := runSecretScan(&config, utils, &pullRequestProviderMock{})
+
+		assert.EqualError(t, err, "1 secret(s) detected")
+		content, _ := utils.FileRead("secretscan/piper_secretscan_report.json")
+		var findings []secretscan.Finding
+		assert.NoError(t, json.Unmarshal(content, &findings))
+		if assert.Len(t, findings, 1) {
+			assert.Equal(t, "src/config.properties", findings[0].File)
+		}
+	})
+
+	t.Run("findings without failing", func(t *testing.T) {
+		t.Parallel()
+		config := defaultSecretScanOptions()
+		config.FailOnFindings = false
+		utils := newSecretScanTestsUtils()
+		utils.AddFile("src/config.properties", []byte("aws.key="+secretScanTestKey))
+
+		assert.NoError(t, runSecretScan(&config, utils, &pullRequestProviderMock{}))
+	})
+
+	t.Run("update and apply baseline", func(t *testing.T) {
+		t.Parallel()
+		config := defaultSecretScanOptions()
+		config.UpdateBaseline = true
+		utils := newSecretScanTestsUtils()
+		utils.AddFile("src/config.properties", []byte("aws.key="+secretScanTestKey))
+
+		assert.NoError(t, runSecretScan(&config, utils, &pullRequestProviderMock{}))
+		exists, _ := utils.FileExists(".secretscan-baseline.json")
+		assert.True(t, exists)
+
+		config.UpdateBaseline = false
+		assert.NoError(t, runSecretScan(&config, utils, &pullRequestProviderMock{}))
+	})
+
+	t.Run("custom rules only", func(t *testing.T) {
+		t.Parallel()
+		config := defaultSecretScanOptions()
+		config.UseDefaultRules = false
+		config.RulesFile = "rules.yaml"
+		utils := newSecretScanTestsUtils()
+		utils.AddFile("rules.yaml", []byte("rules:\n  - id: internal\n    regex: 'itk-[0-9]{4}'\n"))
+		utils.AddFile("src/config.properties", []byte("aws.key="+secretScanTestKey+"\ntoken=itk-1234"))
+
+		err := runSecretScan(&config, utils, &pullRequestProviderMock{})
+
+		assert.EqualError(t, err, "1 secret(s) detected")
+	})
+
+	t.Run("no rules", func(t *testing.T) {
+		t.Parallel()
+		config := defaultSecretScanOptions()
+		config.UseDefaultRules = false
+		utils := newSecretScanTestsUtils()
+
+		err := runSecretScan(&config, utils, &pullRequestProviderMock{})
+
+		assert.EqualError(t, err, "no rules configured, provide a rulesFile or activate useDefaultRules")
+	})
+
+	t.Run("pull request diff", func(t *testing.T) {
+		t.Parallel()
+		repository, err := git.Init(memory.NewStorage(), memfs.New())
+		assert.NoError(t, err)
+		worktree, _ := repository.Worktree()
+		commit := func(path, content string) plumbing.Hash {
+			file, _ := worktree.Filesystem.Create(path)
+			file.Write([]byte(content))
+			file.Close()
+			worktree.Add(path)
+			hash, err := worktree.Commit("update", &git.CommitOptions{Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}})
+			assert.NoError(t, err)
+			return hash
+		}
+		base := commit("old.properties", "aws.key="+secretScanTestKey)
+		assert.NoError(t, repository.Storer.SetReference(plumbing.NewHashReference("refs/remotes/origin/main", base)))
+		commit("new.properties", "token=\"ghp_"+"k2Jf9xQ7LmT4bV8nR1sY6wZ3cH5dG0aE9uPq\"")
+
+		config := defaultSecretScanOptions()
+		utils := newSecretScanTestsUtils()
+		utils.repository = repository
+
+		err = runSecretScan(&config, utils, &pullRequestProviderMock{base: "main"})
+
+		assert.EqualError(t, err, "1 secret(s) detected")
+		content, _ := utils.FileRead("secretscan/piper_secretscan_report.json")
+		var findings []secretscan.Finding
+		assert.NoError(t, json.Unmarshal(content, &findings))
+		if assert.Len(t, findings, 1) {
+			assert.Equal(t, "new.properties", findings[0].File)
+			assert.Equal(t, "github-token", findings[0].RuleID)
+		}
+	})
+}
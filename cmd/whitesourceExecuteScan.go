@@ -28,6 +28,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
 	"github.com/SAP/jenkins-library/pkg/versioning"
+	"github.com/package-url/packageurl-go"
 	"github.com/xuri/excelize/v2"
 
 	"github.com/google/go-github/v68/github"
@@ -780,6 +781,39 @@ func collectVulnsAndLibsForProject(
 	return vulCount, alerts, assessedAlerts, libraries, errorsOccurred
 }
 
+func issueTrackerOptions(config *ScanOptions, utils whitesourceUtils) *reporting.IssueTrackerOptions {
+	return &reporting.IssueTrackerOptions{
+		IssueTracker:            config.IssueTracker,
+		GithubToken:             config.GithubToken,
+		GithubAPIURL:            config.GithubAPIURL,
+		Owner:                   config.Owner,
+		Repository:              config.Repository,
+		Assignees:               config.Assignees,
+		IssueService:            utils.GetIssueService(),
+		SearchService:           utils.GetSearchService(),
+		JiraURL:                 config.JiraURL,
+		JiraProjectKey:          config.JiraProjectKey,
+		JiraIssueType:           config.JiraIssueType,
+		JiraAPIVersion:          config.JiraAPIVersion,
+		JiraUsername:            config.JiraUsername,
+		JiraToken:               config.JiraToken,
+		AzureBoardsOrganization: config.AzureBoardsOrganization,
+		AzureBoardsProject:      config.AzureBoardsProject,
+		AzureBoardsToken:        config.AzureBoardsToken,
+		AzureBoardsWorkItemType: config.AzureBoardsWorkItemType,
+		AzureBoardsOpenState:    config.AzureBoardsOpenState,
+		AzureBoardsClosedState:  config.AzureBoardsClosedState,
+	}
+}
+
+// issueScope separates the issues of several products and projects reporting into the same issue tracker
+func issueScope(config *ScanOptions) string {
+	if len(config.ProjectName) > 0 {
+		return config.ProductName + "/" + config.ProjectName
+	}
+	return config.ProductName
+}
+
 func reportGitHubIssuesAndCreateReports(
 	ctx context.Context,
 	config *ScanOptions,
@@ -794,7 +828,19 @@ func reportGitHubIssuesAndCreateReports(
 	errorsOccured := make([]string, 0)
 	reportPaths := make([]piperutils.Path, 0)
 
-	if config.CreateResultIssue && vulnerabilitiesCount > 0 && len(config.GithubToken) > 0 && len(config.GithubAPIURL) > 0 && len(config.Owner) > 0 && len(config.Repository) > 0 {
+	trackerOptions := issueTrackerOptions(config, utils)
+	if config.CreateResultIssue && !config.SyncResultIssues && config.IssueTracker != reporting.IssueTrackerGitHub && len(config.IssueTracker) > 0 {
+		log.Entry().Warnf("Issue tracker %v is only supported in combination with syncResultIssues, result issues are created in GitHub", config.IssueTracker)
+	}
+	if config.CreateResultIssue && config.SyncResultIssues && trackerOptions.Configured() {
+		log.Entry().Debugf("Synchronizing result issues in %v for %v alert(s) and %v assessed alert(s)", config.IssueTracker, len(allAlerts), len(allAssessedAlerts))
+		tracker, err := reporting.NewIssueTracker(trackerOptions)
+		if err != nil {
+			errorsOccured = append(errorsOccured, fmt.Sprint(err))
+		} else if err := reporting.SyncFindings(ctx, tracker, "Mend", issueScope(config), ws.ToTrackedFindings(allAlerts, allAssessedAlerts)); err != nil {
+			errorsOccured = append(errorsOccured, fmt.Sprint(err))
+		}
+	} else if config.CreateResultIssue && vulnerabilitiesCount > 0 && len(config.GithubToken) > 0 && len(config.GithubAPIURL) > 0 && len(config.Owner) > 0 && len(config.Repository) > 0 {
 		log.Entry().Debugf("Creating result issues for %v alert(s)", vulnerabilitiesCount)
 		issueDetails := make([]reporting.IssueDetail, len(allAlerts))
 		piperutils.CopyAtoB(allAlerts, issueDetails)
@@ -811,6 +857,12 @@ func reportGitHubIssuesAndCreateReports(
 		}
 	}
 
+	if config.CreateFixPullRequest && vulnerabilitiesCount > 0 && len(config.GithubToken) > 0 && len(config.GithubAPIURL) > 0 && len(config.Owner) > 0 && len(config.Repository) > 0 {
+		if err := createFixPullRequest(config, utils, allAlerts); err != nil {
+			errorsOccured = append(errorsOccured, fmt.Sprint(err))
+		}
+	}
+
 	scanReport := ws.CreateCustomVulnerabilityReport(config.ProductName, scan, &allAlerts, cvssSeverityLimit)
 	paths, err := ws.WriteCustomVulnerabilityReports(config.ProductName, scan, scanReport, utils)
 	if err != nil {
@@ -1232,3 +1284,192 @@ func logConfigInVerboseModeForWhitesource(config ScanOptions) {
 	debugLog, _ := json.Marshal(config)
 	log.Entry().Debugf("Whitesource configuration: %v", string(debugLog))
 }
+
+var fixDescriptorPatterns = map[string]string{
+	packageurl.TypeMaven: "**/pom.xml",
+	packageurl.TypeNPM:   "**/package.json",
+	packageurl.TypePyPi:  "**/requirements*.txt",
+}
+
+// createFixPullRequest opens one pull request upgrading all vulnerable direct dependencies for which a fix is known
+func createFixPullRequest(config *ScanOptions, utils whitesourceUtils, alerts []ws.Alert) error {
+	upgrades := ws.MinimalUpgrades(alerts)
+	if len(upgrades) == 0 {
+		log.Entry().Info("No dependency upgrades available which fix the reported vulnerabilities")
+		return nil
+	}
+	if len(config.FixPullRequestBaseBranch) == 0 {
+		return errors.New("no base branch configured for the pull request with dependency upgrades (fixPullRequestBaseBranch)")
+	}
+
+	patches, err := applyDependencyUpgrades(upgrades, utils)
+	if err != nil {
+		return err
+	}
+	if len(patches) == 0 {
+		log.Entry().Info("None of the vulnerable dependencies is declared with an explicit version in a supported build descriptor")
+		return nil
+	}
+
+	pullRequest, err := piperGithub.CreatePullRequest(&piperGithub.CreatePullRequestOptions{
+		APIURL:        config.GithubAPIURL,
+		Owner:         config.Owner,
+		Repository:    config.Repository,
+		Token:         config.GithubToken,
+		TrustedCerts:  config.CustomTLSCertificateLinks,
+		Base:          config.FixPullRequestBaseBranch,
+		Head:          config.FixPullRequestBranch,
+		Title:         fmt.Sprintf("Upgrade vulnerable dependencies of %v", config.ProductName),
+		Body:          ws.UpgradesToMarkdown(upgrades),
+		CommitMessage: fmt.Sprintf("Upgrade %v vulnerable dependencies", len(upgrades)),
+		Labels:        config.FixPullRequestLabels,
+		Patches:       patches,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create pull request with dependency upgrades: %w", err)
+	}
+	log.Entry().Infof("Pull request with dependency upgrades available at %v", pullRequest.GetHTMLURL())
+	return nil
+}
+
+// applyDependencyUpgrades determines the build descriptors of the project declaring the vulnerable dependencies
+// and returns the patches upgrading them on the base branch of the pull request. The workspace is left unchanged.
+func applyDependencyUpgrades(upgrades []ws.DependencyUpgrade, utils whitesourceUtils) (map[string]piperGithub.FilePatch, error) {
+	descriptorUpdates := map[string][]versioning.DependencyUpdate{}
+	changedDescriptors := []string{}
+	for _, upgrade := range upgrades {
+		pattern, ok := fixDescriptorPatterns[upgrade.PackageType()]
+		if !ok {
+			log.Entry().Infof("Upgrading %v dependencies is not supported, skipping %v", upgrade.PackageType(), upgrade.PackageName())
+			continue
+		}
+		descriptors, err := utils.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for build descriptors '%v': %w", pattern, err)
+		}
+		for _, descriptor := range descriptors {
+			if isGeneratedDescriptor(descriptor) {
+				continue
+			}
+			content, err := utils.FileRead(descriptor)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read build descriptor '%v': %w", descriptor, err)
+			}
+			update := versioning.DependencyUpdate{
+				GroupID:    upgrade.Library.GroupID,
+				ArtifactID: upgrade.PackageName(),
+				Version:    upgrade.TargetVersion,
+			}
+			_, changed, err := versioning.UpdateDependencyVersion(descriptor, content, update)
+			if err != nil {
+				return nil, fmt.Errorf("failed to upgrade %v in '%v': %w", upgrade.PackageName(), descriptor, err)
+			}
+			if changed {
+				log.Entry().Infof("Upgrading %v from %v to %v in '%v'", upgrade.PackageName(), upgrade.Library.Version, upgrade.TargetVersion, descriptor)
+				if _, ok := descriptorUpdates[descriptor]; !ok {
+					changedDescriptors = append(changedDescriptors, descriptor)
+				}
+				descriptorUpdates[descriptor] = append(descriptorUpdates[descriptor], update)
+			}
+		}
+	}
+
+	patches := map[string]piperGithub.FilePatch{}
+	for _, descriptor := range changedDescriptors {
+		descriptorPath := filepath.ToSlash(descriptor)
+		updates := descriptorUpdates[descriptor]
+		patches[descriptorPath] = func(readBaseFile func(path string) ([]byte, error)) ([]byte, error) {
+			content, err := readBaseFile(descriptorPath)
+			if err != nil {
+				return nil, err
+			}
+			return upgradeDescriptor(descriptorPath, content, updates)
+		}
+		if filepath.Base(descriptor) != "package.json" {
+			continue
+		}
+		lockFile := filepath.Join(filepath.Dir(descriptor), "package-lock.json")
+		if exists, _ := utils.FileExists(lockFile); !exists {
+			continue
+		}
+		lockFilePath := filepath.ToSlash(lockFile)
+		patches[lockFilePath] = func(readBaseFile func(path string) ([]byte, error)) ([]byte, error) {
+			return updateNpmLockFile(descriptorPath, lockFilePath, updates, readBaseFile, utils)
+		}
+	}
+	return patches, nil
+}
+
+func upgradeDescriptor(descriptor string, content []byte, updates []versioning.DependencyUpdate) ([]byte, error) {
+	for _, update := range updates {
+		var err error
+		if content, _, err = versioning.UpdateDependencyVersion(descriptor, content, update); err != nil {
+			return nil, fmt.Errorf("failed to upgrade %v in '%v': %w", update.ArtifactID, descriptor, err)
+		}
+	}
+	return content, nil
+}
+
+func isGeneratedDescriptor(descriptor string) bool {
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(descriptor)), "/") {
+		if dir == "node_modules" || dir == "target" {
+			return true
+		}
+	}
+	return false
+}
+
+// updateNpmLockFile regenerates the lock file of the upgraded package.json of the base branch in a temporary directory
+func updateNpmLockFile(descriptor, lockFile string, updates []versioning.DependencyUpdate, readBaseFile func(path string) ([]byte, error), utils whitesourceUtils) ([]byte, error) {
+	packageJSON, err := readBaseFile(descriptor)
+	if err != nil {
+		return nil, err
+	}
+	if packageJSON, err = upgradeDescriptor(descriptor, packageJSON, updates); err != nil {
+		return nil, err
+	}
+	lockContent, err := readBaseFile(lockFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := utils.TempDir("", "npm-lock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() {
+		_ = utils.RemoveAll(tmpDir)
+	}()
+	if err := utils.FileWrite(filepath.Join(tmpDir, "package.json"), packageJSON, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write '%v': %w", descriptor, err)
+	}
+	if err := utils.FileWrite(filepath.Join(tmpDir, "package-lock.json"), lockContent, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write '%v': %w", lockFile, err)
+	}
+	// the registry configuration of the project is needed to resolve the upgraded dependencies
+	npmrc := filepath.Join(filepath.Dir(filepath.FromSlash(descriptor)), ".npmrc")
+	if exists, _ := utils.FileExists(npmrc); exists {
+		if _, err := utils.Copy(npmrc, filepath.Join(tmpDir, ".npmrc")); err != nil {
+			return nil, fmt.Errorf("failed to copy '%v': %w", npmrc, err)
+		}
+	}
+
+	oldWorkingDirectory, err := utils.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	if err := utils.Chdir(tmpDir); err != nil {
+		return nil, fmt.Errorf("failed to change into directory '%v': %w", tmpDir, err)
+	}
+	defer func() {
+		_ = utils.Chdir(oldWorkingDirectory)
+	}()
+	if err := utils.RunExecutable("npm", "install", "--package-lock-only", "--ignore-scripts"); err != nil {
+		return nil, fmt.Errorf("failed to update '%v': %w", lockFile, err)
+	}
+	content, err := utils.FileRead("package-lock.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%v': %w", lockFile, err)
+	}
+	return content, nil
+}
@@ -76,10 +76,28 @@ type whitesourceExecuteScanOptions struct {
 	DisableNpmSubmodulesAggregation      bool     `json:"disableNpmSubmodulesAggregation,omitempty"`
 	GithubToken                          string   `json:"githubToken,omitempty"`
 	CreateResultIssue                    bool     `json:"createResultIssue,omitempty"`
+	SyncResultIssues                     bool     `json:"syncResultIssues,omitempty"`
+	IssueTracker                         string   `json:"issueTracker,omitempty" validate:"possible-values=github jira azureBoards"`
+	JiraURL                              string   `json:"jiraUrl,omitempty"`
+	JiraProjectKey                       string   `json:"jiraProjectKey,omitempty"`
+	JiraIssueType                        string   `json:"jiraIssueType,omitempty"`
+	JiraAPIVersion                       string   `json:"jiraApiVersion,omitempty" validate:"possible-values=2 3"`
+	JiraUsername                         string   `json:"jiraUsername,omitempty"`
+	JiraToken                            string   `json:"jiraToken,omitempty"`
+	AzureBoardsOrganization              string   `json:"azureBoardsOrganization,omitempty"`
+	AzureBoardsProject                   string   `json:"azureBoardsProject,omitempty"`
+	AzureBoardsWorkItemType              string   `json:"azureBoardsWorkItemType,omitempty"`
+	AzureBoardsOpenState                 string   `json:"azureBoardsOpenState,omitempty"`
+	AzureBoardsClosedState               string   `json:"azureBoardsClosedState,omitempty"`
+	AzureBoardsToken                     string   `json:"azureBoardsToken,omitempty"`
 	GithubAPIURL                         string   `json:"githubApiUrl,omitempty"`
 	Owner                                string   `json:"owner,omitempty"`
 	Repository                           string   `json:"repository,omitempty"`
 	Assignees                            []string `json:"assignees,omitempty"`
+	CreateFixPullRequest                 bool     `json:"createFixPullRequest,omitempty"`
+	FixPullRequestBaseBranch             string   `json:"fixPullRequestBaseBranch,omitempty"`
+	FixPullRequestBranch                 string   `json:"fixPullRequestBranch,omitempty"`
+	FixPullRequestLabels                 []string `json:"fixPullRequestLabels,omitempty"`
 	CustomTLSCertificateLinks            []string `json:"customTlsCertificateLinks,omitempty"`
 	PrivateModules                       string   `json:"privateModules,omitempty"`
 	PrivateModulesGitToken               string   `json:"privateModulesGitToken,omitempty"`
@@ -265,6 +283,9 @@ The step uses the so-called Mend Unified Agent. For details please refer to the
 			log.RegisterSecret(stepConfig.OrgToken)
 			log.RegisterSecret(stepConfig.UserToken)
 			log.RegisterSecret(stepConfig.GithubToken)
+			log.RegisterSecret(stepConfig.JiraUsername)
+			log.RegisterSecret(stepConfig.JiraToken)
+			log.RegisterSecret(stepConfig.AzureBoardsToken)
 			log.RegisterSecret(stepConfig.PrivateModulesGitToken)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
@@ -411,10 +432,28 @@ func addWhitesourceExecuteScanFlags(cmd *cobra.Command, stepConfig *whitesourceE
 	cmd.Flags().BoolVar(&stepConfig.DisableNpmSubmodulesAggregation, "disableNpmSubmodulesAggregation", false, "The default Mend behavior is to aggregate all submodules of NPM project into one project in Mend. This parameter disables this behavior, thus for each submodule a separate project is created.")
 	cmd.Flags().StringVar(&stepConfig.GithubToken, "githubToken", os.Getenv("PIPER_githubToken"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line")
 	cmd.Flags().BoolVar(&stepConfig.CreateResultIssue, "createResultIssue", false, "Activate creation of a result issue in GitHub.")
+	cmd.Flags().BoolVar(&stepConfig.SyncResultIssues, "syncResultIssues", false, "Track each finding in a dedicated issue and close issues of resolved findings.")
+	cmd.Flags().StringVar(&stepConfig.IssueTracker, "issueTracker", `github`, "Issue tracker the findings are synchronized with when using `syncResultIssues`.")
+	cmd.Flags().StringVar(&stepConfig.JiraURL, "jiraUrl", os.Getenv("PIPER_jiraUrl"), "URL of the Jira server, e.g. `https://jira.example.com`.")
+	cmd.Flags().StringVar(&stepConfig.JiraProjectKey, "jiraProjectKey", os.Getenv("PIPER_jiraProjectKey"), "Key of the Jira project issues are created in.")
+	cmd.Flags().StringVar(&stepConfig.JiraIssueType, "jiraIssueType", `Bug`, "Type of the Jira issues created for findings.")
+	cmd.Flags().StringVar(&stepConfig.JiraAPIVersion, "jiraApiVersion", `2`, "Version of the Jira REST API, `2` for Jira Server and Data Center and `3` for Jira Cloud.")
+	cmd.Flags().StringVar(&stepConfig.JiraUsername, "jiraUsername", os.Getenv("PIPER_jiraUsername"), "User to authenticate to Jira. Without a user the token is sent as bearer token.")
+	cmd.Flags().StringVar(&stepConfig.JiraToken, "jiraToken", os.Getenv("PIPER_jiraToken"), "API token or personal access token to authenticate to Jira.")
+	cmd.Flags().StringVar(&stepConfig.AzureBoardsOrganization, "azureBoardsOrganization", os.Getenv("PIPER_azureBoardsOrganization"), "Azure DevOps organization work items are created in.")
+	cmd.Flags().StringVar(&stepConfig.AzureBoardsProject, "azureBoardsProject", os.Getenv("PIPER_azureBoardsProject"), "Azure DevOps project work items are created in.")
+	cmd.Flags().StringVar(&stepConfig.AzureBoardsWorkItemType, "azureBoardsWorkItemType", `Bug`, "Type of the work items created for findings.")
+	cmd.Flags().StringVar(&stepConfig.AzureBoardsOpenState, "azureBoardsOpenState", `New`, "State work items are set to when a finding is reported again.")
+	cmd.Flags().StringVar(&stepConfig.AzureBoardsClosedState, "azureBoardsClosedState", `Closed`, "State work items are set to when a finding is no longer reported.")
+	cmd.Flags().StringVar(&stepConfig.AzureBoardsToken, "azureBoardsToken", os.Getenv("PIPER_azureBoardsToken"), "Personal access token with scope `Work Items (Read & write)` to authenticate to Azure Boards.")
 	cmd.Flags().StringVar(&stepConfig.GithubAPIURL, "githubApiUrl", `https://api.github.com`, "Set the GitHub API URL.")
 	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Set the GitHub organization.")
 	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Set the GitHub repository.")
 	cmd.Flags().StringSliceVar(&stepConfig.Assignees, "assignees", []string{``}, "Defines the assignees for the Github Issue created/updated with the results of the scan as a list of login names.")
+	cmd.Flags().BoolVar(&stepConfig.CreateFixPullRequest, "createFixPullRequest", false, "Activate creation of a pull request in GitHub which upgrades vulnerable direct dependencies.")
+	cmd.Flags().StringVar(&stepConfig.FixPullRequestBaseBranch, "fixPullRequestBaseBranch", os.Getenv("PIPER_fixPullRequestBaseBranch"), "Branch the pull request with dependency upgrades is based on.")
+	cmd.Flags().StringVar(&stepConfig.FixPullRequestBranch, "fixPullRequestBranch", `piper/whitesource-fixes`, "Branch which receives the dependency upgrades. An existing branch is reset on each run.")
+	cmd.Flags().StringSliceVar(&stepConfig.FixPullRequestLabels, "fixPullRequestLabels", []string{`security`}, "Labels added to the pull request with dependency upgrades.")
 	cmd.Flags().StringSliceVar(&stepConfig.CustomTLSCertificateLinks, "customTlsCertificateLinks", []string{}, "List of download links to custom TLS certificates. This is required to ensure trusted connections to instances with repositories (like nexus) when publish flag is set to true.")
 	cmd.Flags().StringVar(&stepConfig.PrivateModules, "privateModules", os.Getenv("PIPER_privateModules"), "Tells go which modules shall be considered to be private (by setting [GOPRIVATE](https://pkg.go.dev/cmd/go#hdr-Configuration_for_downloading_non_public_code)).")
 	cmd.Flags().StringVar(&stepConfig.PrivateModulesGitToken, "privateModulesGitToken", os.Getenv("PIPER_privateModulesGitToken"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line.")
@@ -458,6 +497,8 @@ func whitesourceExecuteScanMetadata() config.StepData {
 					{Name: "dockerConfigJsonCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing Docker config.json (with registry credential(s)). You can find more details about the Docker credentials in the [Docker documentation](https://docs.docker.com/engine/reference/commandline/login/).", Type: "jenkins", Aliases: []config.Alias{{Name: "dockerCredentialsId", Deprecated: true}}},
 					{Name: "githubTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing token to authenticate to GitHub.", Type: "jenkins"},
 					{Name: "golangPrivateModulesGitTokenCredentialsId", Description: "Jenkins 'Username with password' credentials ID containing username/password for http access to your git repos where your go private modules are stored.", Type: "jenkins"},
+					{Name: "jiraCredentialsId", Description: "Jenkins 'Username with password' credentials ID containing username and API token to authenticate to Jira.", Type: "jenkins"},
+					{Name: "azureBoardsTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the personal access token to authenticate to Azure Boards.", Type: "jenkins"},
 				},
 				Resources: []config.StepResources{
 					{Name: "buildDescriptor", Type: "stash"},
@@ -1042,6 +1083,167 @@ func whitesourceExecuteScanMetadata() config.StepData {
 						Aliases:   []config.Alias{},
 						Default:   false,
 					},
+					{
+						Name:        "syncResultIssues",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "issueTracker",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `github`,
+					},
+					{
+						Name:        "jiraUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_jiraUrl"),
+					},
+					{
+						Name:        "jiraProjectKey",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_jiraProjectKey"),
+					},
+					{
+						Name:        "jiraIssueType",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `Bug`,
+					},
+					{
+						Name:        "jiraApiVersion",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `2`,
+					},
+					{
+						Name: "jiraUsername",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "jiraCredentialsId",
+								Param: "username",
+								Type:  "secret",
+							},
+
+							{
+								Name:    "jiraVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "jira",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_jiraUsername"),
+					},
+					{
+						Name: "jiraToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "jiraCredentialsId",
+								Param: "password",
+								Type:  "secret",
+							},
+
+							{
+								Name:    "jiraVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "jira",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_jiraToken"),
+					},
+					{
+						Name:        "azureBoardsOrganization",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_azureBoardsOrganization"),
+					},
+					{
+						Name:        "azureBoardsProject",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_azureBoardsProject"),
+					},
+					{
+						Name:        "azureBoardsWorkItemType",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `Bug`,
+					},
+					{
+						Name:        "azureBoardsOpenState",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `New`,
+					},
+					{
+						Name:        "azureBoardsClosedState",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `Closed`,
+					},
+					{
+						Name: "azureBoardsToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "azureBoardsTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "azureBoardsVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "azure-boards",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_azureBoardsToken"),
+					},
 					{
 						Name:        "githubApiUrl",
 						ResourceRef: []config.ResourceReference{},
@@ -1088,6 +1290,47 @@ func whitesourceExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     []string{``},
 					},
+					{
+						Name:        "createFixPullRequest",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "fixPullRequestBaseBranch",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "git/branch",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_fixPullRequestBaseBranch"),
+					},
+					{
+						Name:        "fixPullRequestBranch",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `piper/whitesource-fixes`,
+					},
+					{
+						Name:        "fixPullRequestLabels",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`security`},
+					},
 					{
 						Name:        "customTlsCertificateLinks",
 						ResourceRef: []config.ResourceReference{},
@@ -949,3 +949,79 @@ func TestBuildToolFiles(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestApplyDependencyUpgrades(t *testing.T) {
+	t.Parallel()
+	upgrades := []ws.DependencyUpgrade{
+		{
+			Library:       ws.Library{GroupID: "org.apache.commons", ArtifactID: "commons-text", Version: "1.9", LibType: "MAVEN_ARTIFACT"},
+			TargetVersion: "1.10.0",
+		},
+		{
+			Library:       ws.Library{GroupID: "lodash", ArtifactID: "lodash-4.17.15.tgz", Version: "4.17.15", LibType: "javascript/Node.js"},
+			TargetVersion: "4.17.21",
+		},
+	}
+
+	t.Run("patches descriptors and npm lock file of base branch", func(t *testing.T) {
+		utilsMock := newWhitesourceUtilsMock()
+		utilsMock.AddFile("pom.xml", []byte("<project><dependencies><dependency><groupId>org.apache.commons</groupId><artifactId>commons-text</artifactId><version>1.9</version></dependency></dependencies></project>"))
+		utilsMock.AddFile("app/package.json", []byte(`{"dependencies": {"lodash": "^4.17.15"}}`))
+		utilsMock.AddFile("app/package-lock.json", []byte(`{}`))
+		utilsMock.AddFile("app/.npmrc", []byte("registry=https://npm.example.com"))
+		utilsMock.AddFile("app/node_modules/x/package.json", []byte(`{"dependencies": {"lodash": "^4.17.15"}}`))
+		baseFiles := map[string]string{
+			"pom.xml":               "<project><version>2.0</version><dependencies><dependency><groupId>org.apache.commons</groupId><artifactId>commons-text</artifactId><version>1.9</version></dependency></dependencies></project>",
+			"app/package.json":      `{"dependencies": {"lodash": "^4.17.15", "express": "^4.0.0"}}`,
+			"app/package-lock.json": `{"lockfileVersion": 3}`,
+		}
+		readBaseFile := func(path string) ([]byte, error) {
+			return []byte(baseFiles[path]), nil
+		}
+
+		patches, err := applyDependencyUpgrades(upgrades, utilsMock)
+
+		assert.NoError(t, err)
+		assert.Len(t, patches, 3)
+		pom, err := patches["pom.xml"](readBaseFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(pom), "<version>2.0</version>")
+		assert.Contains(t, string(pom), "<version>1.10.0</version>")
+		packageJSON, err := patches["app/package.json"](readBaseFile)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"dependencies": {"lodash": "^4.17.21", "express": "^4.0.0"}}`, string(packageJSON))
+		lock, err := patches["app/package-lock.json"](readBaseFile)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"lockfileVersion": 3}`, string(lock))
+		if assert.Len(t, utilsMock.Calls, 1) {
+			assert.Equal(t, "npm", utilsMock.Calls[0].Exec)
+			assert.Equal(t, []string{"install", "--package-lock-only", "--ignore-scripts"}, utilsMock.Calls[0].Params)
+		}
+
+		content, _ := utilsMock.FileRead("app/package.json")
+		assert.Equal(t, `{"dependencies": {"lodash": "^4.17.15"}}`, string(content), "workspace must not be changed")
+		content, _ = utilsMock.FileRead("pom.xml")
+		assert.Contains(t, string(content), "<version>1.9</version>")
+	})
+
+	t.Run("no declared dependencies", func(t *testing.T) {
+		utilsMock := newWhitesourceUtilsMock()
+		utilsMock.AddFile("requirements.txt", []byte("flask==2.0.0\n"))
+
+		files, err := applyDependencyUpgrades(upgrades, utilsMock)
+
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+	})
+}
+
+func TestIssueTrackerOptions(t *testing.T) {
+	t.Parallel()
+	config := &ScanOptions{IssueTracker: "jira", JiraURL: "https://jira.example.com", JiraProjectKey: "SEC", JiraToken: "token", Owner: "owner"}
+
+	options := issueTrackerOptions(config, newWhitesourceUtilsMock())
+
+	assert.True(t, options.Configured())
+	assert.Equal(t, "SEC", options.JiraProjectKey)
+	assert.Equal(t, "owner", options.Owner)
+}
@@ -0,0 +1,93 @@
+//go:build integration
+// +build integration
+
+// can be executed with
+// go test -v -tags integration -run TestBuildKitIntegration ./integration/...
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/SAP/jenkins-library/pkg/buildkit"
+)
+
+func TestBuildKitIntegrationBuild(t *testing.T) {
+	ctx := context.Background()
+
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "moby/buildkit:v0.32.2",
+			Privileged:   true,
+			Cmd:          []string{"--addr", "tcp://0.0.0.0:1234"},
+			ExposedPorts: []string{"1234/tcp"},
+			WaitingFor:   wait.ForListeningPort("1234/tcp").WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	}
+	buildkitContainer, err := testcontainers.GenericContainer(ctx, req)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := buildkitContainer.Terminate(ctx); err != nil {
+			t.Logf("Failed to terminate container: %v", err)
+		}
+	})
+
+	host, err := buildkitContainer.Host(ctx)
+	require.NoError(t, err)
+	port, err := buildkitContainer.MappedPort(ctx, "1234")
+	require.NoError(t, err)
+
+	contextDir := t.TempDir()
+	dockerfile := `FROM busybox:1.36 AS build
+ARG VERSION
+RUN --mount=type=secret,id=token test "$(cat /run/secrets/token)" = "secret" && echo "$VERSION" > /version
+
+FROM scratch AS runtime
+COPY --from=build /version /version
+`
+	require.NoError(t, os.WriteFile(filepath.Join(contextDir, "Dockerfile"), []byte(dockerfile), 0o644))
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret"), 0o600))
+
+	t.Run("build image without push", func(t *testing.T) {
+		progress := &bytes.Buffer{}
+
+		digest, err := buildkit.Build(ctx, &buildkit.BuildOptions{
+			Address:      fmt.Sprintf("tcp://%s:%s", host, port.Port()),
+			ContextDir:   contextDir,
+			Dockerfile:   filepath.Join(contextDir, "Dockerfile"),
+			Target:       "runtime",
+			BuildArgs:    []string{"VERSION=1.0.0"},
+			Secrets:      []string{"id=token,src=" + tokenFile},
+			Destinations: []string{"localhost/buildkit-integration:latest"},
+		}, progress)
+
+		require.NoError(t, err, progress.String())
+		assert.Regexp(t, "^sha256:[a-f0-9]{64}$", digest)
+		assert.Contains(t, progress.String(), "RUN --mount=type=secret,id=token")
+	})
+
+	t.Run("build fails", func(t *testing.T) {
+		_, err := buildkit.Build(ctx, &buildkit.BuildOptions{
+			Address:    fmt.Sprintf("tcp://%s:%s", host, port.Port()),
+			ContextDir: contextDir,
+			Dockerfile: filepath.Join(contextDir, "Dockerfile"),
+			Target:     "runtime",
+			BuildArgs:  []string{"VERSION=1.0.0"},
+		}, &bytes.Buffer{})
+
+		assert.ErrorContains(t, err, "BuildKit build failed")
+	})
+}
@@ -0,0 +1,181 @@
+package ado
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+)
+
+// Work item fields used for tracking
+const (
+	FieldTitle       = "System.Title"
+	FieldDescription = "System.Description"
+	FieldState       = "System.State"
+	FieldTags        = "System.Tags"
+	FieldHistory     = "System.History"
+)
+
+// maximum number of work items which can be retrieved with one request
+const workItemsBatchSize = 200
+
+type WorkItemClient interface {
+	QueryWorkItemsByTag(tag string) ([]WorkItem, error)
+	CreateWorkItem(workItemType string, fields map[string]interface{}) (WorkItem, error)
+	UpdateWorkItem(id int, fields map[string]interface{}) error
+}
+
+type WorkItemClientImpl struct {
+	ctx            context.Context
+	workItemClient workitemtracking.Client
+	project        string
+}
+
+// WorkItem contains the fields of an Azure Boards work item relevant for tracking
+type WorkItem struct {
+	ID          int
+	Title       string
+	Description string
+	State       string
+	Tags        []string
+}
+
+// QueryWorkItemsByTag returns all work items of the project carrying the given tag
+func (wc *WorkItemClientImpl) QueryWorkItemsByTag(tag string) ([]WorkItem, error) {
+	query := fmt.Sprintf("SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project AND [System.Tags] CONTAINS '%v'", strings.ReplaceAll(tag, "'", "''"))
+	result, err := wc.workItemClient.QueryByWiql(wc.ctx, workitemtracking.QueryByWiqlArgs{
+		Wiql:    &workitemtracking.Wiql{Query: &query},
+		Project: &wc.project,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error: query work items failed: %w", err)
+	}
+	if result.WorkItems == nil {
+		return []WorkItem{}, nil
+	}
+
+	ids := []int{}
+	for _, reference := range *result.WorkItems {
+		if reference.Id != nil {
+			ids = append(ids, *reference.Id)
+		}
+	}
+
+	fields := []string{FieldTitle, FieldDescription, FieldState, FieldTags}
+	workItems := []WorkItem{}
+	for start := 0; start < len(ids); start += workItemsBatchSize {
+		end := min(start+workItemsBatchSize, len(ids))
+		batch := ids[start:end]
+		items, err := wc.workItemClient.GetWorkItems(wc.ctx, workitemtracking.GetWorkItemsArgs{
+			Ids:     &batch,
+			Project: &wc.project,
+			Fields:  &fields,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error: get work items failed: %w", err)
+		}
+		for _, item := range *items {
+			workItems = append(workItems, toWorkItem(item))
+		}
+	}
+	return workItems, nil
+}
+
+// CreateWorkItem creates a work item of the given type with the given field values
+func (wc *WorkItemClientImpl) CreateWorkItem(workItemType string, fields map[string]interface{}) (WorkItem, error) {
+	item, err := wc.workItemClient.CreateWorkItem(wc.ctx, workitemtracking.CreateWorkItemArgs{
+		Document: toPatchDocument(fields),
+		Project:  &wc.project,
+		Type:     &workItemType,
+	})
+	if err != nil {
+		return WorkItem{}, fmt.Errorf("error: create work item failed: %w", err)
+	}
+	return toWorkItem(*item), nil
+}
+
+// UpdateWorkItem updates the given field values of a work item.
+// Comments can be added by providing them via the field System.History.
+func (wc *WorkItemClientImpl) UpdateWorkItem(id int, fields map[string]interface{}) error {
+	_, err := wc.workItemClient.UpdateWorkItem(wc.ctx, workitemtracking.UpdateWorkItemArgs{
+		Document: toPatchDocument(fields),
+		Id:       &id,
+		Project:  &wc.project,
+	})
+	if err != nil {
+		return fmt.Errorf("error: update work item %v failed: %w", id, err)
+	}
+	return nil
+}
+
+func toPatchDocument(fields map[string]interface{}) *[]webapi.JsonPatchOperation {
+	document := []webapi.JsonPatchOperation{}
+	// ensure stable order of operations
+	for _, field := range []string{FieldTitle, FieldDescription, FieldState, FieldTags, FieldHistory} {
+		if value, ok := fields[field]; ok {
+			path := "/fields/" + field
+			document = append(document, webapi.JsonPatchOperation{Op: &webapi.OperationValues.Add, Path: &path, Value: value})
+		}
+	}
+	return &document
+}
+
+func toWorkItem(item workitemtracking.WorkItem) WorkItem {
+	workItem := WorkItem{Tags: []string{}}
+	if item.Id != nil {
+		workItem.ID = *item.Id
+	}
+	if item.Fields == nil {
+		return workItem
+	}
+	fields := *item.Fields
+	workItem.Title = fmt.Sprint(valueOrEmpty(fields[FieldTitle]))
+	workItem.Description = fmt.Sprint(valueOrEmpty(fields[FieldDescription]))
+	workItem.State = fmt.Sprint(valueOrEmpty(fields[FieldState]))
+	for _, tag := range strings.Split(fmt.Sprint(valueOrEmpty(fields[FieldTags])), ";") {
+		if tag = strings.TrimSpace(tag); len(tag) > 0 {
+			workItem.Tags = append(workItem.Tags, tag)
+		}
+	}
+	return workItem
+}
+
+func valueOrEmpty(value interface{}) interface{} {
+	if value == nil {
+		return ""
+	}
+	return value
+}
+
+// NewWorkItemClient Create a client to interact with the Work Item Tracking area
+func NewWorkItemClient(organization string, personalAccessToken string, project string) (WorkItemClient, error) {
+	if organization == "" {
+		return nil, errors.New("error: organization must not be empty")
+	}
+	if personalAccessToken == "" {
+		return nil, errors.New("error: personal access token must not be empty")
+	}
+	if project == "" {
+		return nil, errors.New("error: project must not be empty")
+	}
+
+	organizationUrl := fmt.Sprintf("%s/%s", azureUrl, organization)
+	connection := azuredevops.NewPatConnection(organizationUrl, personalAccessToken)
+
+	ctx := context.Background()
+
+	workItemClient, err := workitemtracking.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WorkItemClientImpl{
+		ctx:            ctx,
+		workItemClient: workItemClient,
+		project:        project,
+	}, nil
+}
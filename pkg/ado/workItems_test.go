@@ -0,0 +1,113 @@
+//go:build unit
+// +build unit
+
+package ado
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/workitemtracking"
+	"github.com/stretchr/testify/assert"
+)
+
+type workItemTrackingMock struct {
+	workitemtracking.Client
+	query        string
+	requestedIDs [][]int
+	documents    map[int][]string
+	queryErr     error
+	workItems    []workitemtracking.WorkItemReference
+}
+
+func (m *workItemTrackingMock) QueryByWiql(ctx context.Context, args workitemtracking.QueryByWiqlArgs) (*workitemtracking.WorkItemQueryResult, error) {
+	m.query = *args.Wiql.Query
+	if m.queryErr != nil {
+		return nil, m.queryErr
+	}
+	return &workitemtracking.WorkItemQueryResult{WorkItems: &m.workItems}, nil
+}
+
+func (m *workItemTrackingMock) GetWorkItems(ctx context.Context, args workitemtracking.GetWorkItemsArgs) (*[]workitemtracking.WorkItem, error) {
+	m.requestedIDs = append(m.requestedIDs, *args.Ids)
+	items := []workitemtracking.WorkItem{}
+	for _, id := range *args.Ids {
+		id := id
+		fields := map[string]interface{}{
+			FieldTitle: "title",
+			FieldState: "New",
+			FieldTags:  "tool:mend; severity:high",
+		}
+		items = append(items, workitemtracking.WorkItem{Id: &id, Fields: &fields})
+	}
+	return &items, nil
+}
+
+func (m *workItemTrackingMock) CreateWorkItem(ctx context.Context, args workitemtracking.CreateWorkItemArgs) (*workitemtracking.WorkItem, error) {
+	id := 42
+	m.recordDocument(id, args.Document)
+	return &workitemtracking.WorkItem{Id: &id}, nil
+}
+
+func (m *workItemTrackingMock) UpdateWorkItem(ctx context.Context, args workitemtracking.UpdateWorkItemArgs) (*workitemtracking.WorkItem, error) {
+	m.recordDocument(*args.Id, args.Document)
+	return &workitemtracking.WorkItem{Id: args.Id}, nil
+}
+
+func (m *workItemTrackingMock) recordDocument(id int, document *[]webapi.JsonPatchOperation) {
+	if m.documents == nil {
+		m.documents = map[int][]string{}
+	}
+	for _, operation := range *document {
+		m.documents[id] = append(m.documents[id], *operation.Path)
+	}
+}
+
+func TestQueryWorkItemsByTag(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		references := []workitemtracking.WorkItemReference{}
+		for i := 1; i <= 250; i++ {
+			id := i
+			references = append(references, workitemtracking.WorkItemReference{Id: &id})
+		}
+		clientMock := &workItemTrackingMock{workItems: references}
+		client := WorkItemClientImpl{ctx: context.Background(), workItemClient: clientMock, project: "project"}
+
+		workItems, err := client.QueryWorkItemsByTag("tool:it's")
+
+		assert.NoError(t, err)
+		assert.Contains(t, clientMock.query, "[System.Tags] CONTAINS 'tool:it''s'")
+		assert.Len(t, clientMock.requestedIDs, 2)
+		assert.Len(t, clientMock.requestedIDs[0], 200)
+		assert.Len(t, workItems, 250)
+		assert.Equal(t, WorkItem{ID: 1, Title: "title", State: "New", Tags: []string{"tool:mend", "severity:high"}}, workItems[0])
+	})
+
+	t.Run("error", func(t *testing.T) {
+		clientMock := &workItemTrackingMock{queryErr: errors.New("query error")}
+		client := WorkItemClientImpl{ctx: context.Background(), workItemClient: clientMock, project: "project"}
+
+		_, err := client.QueryWorkItemsByTag("tool:mend")
+
+		assert.EqualError(t, err, "error: query work items failed: query error")
+	})
+}
+
+func TestCreateAndUpdateWorkItem(t *testing.T) {
+	t.Parallel()
+	clientMock := &workItemTrackingMock{}
+	client := WorkItemClientImpl{ctx: context.Background(), workItemClient: clientMock, project: "project"}
+
+	workItem, err := client.CreateWorkItem("Bug", map[string]interface{}{FieldTags: "tool:mend", FieldTitle: "title"})
+	assert.NoError(t, err)
+	assert.Equal(t, 42, workItem.ID)
+	assert.Equal(t, []string{"/fields/System.Title", "/fields/System.Tags"}, clientMock.documents[42])
+
+	err = client.UpdateWorkItem(7, map[string]interface{}{FieldHistory: "comment", FieldState: "Closed"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/fields/System.State", "/fields/System.History"}, clientMock.documents[7])
+}
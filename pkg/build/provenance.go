@@ -0,0 +1,318 @@
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// Provenance statements follow the in-toto attestation framework (https://github.com/in-toto/attestation)
+// with a predicate of SLSA provenance v1 (https://slsa.dev/spec/v1.0/provenance)
+const (
+	// StatementType is the type of in-toto statements v1
+	StatementType = "https://in-toto.io/Statement/v1"
+	// ProvenancePredicateType is the predicate type of SLSA provenance v1
+	ProvenancePredicateType = "https://slsa.dev/provenance/v1"
+	// ProvenanceBuildType describes how the external parameters of a piper build step have to be interpreted
+	ProvenanceBuildType = "https://github.com/SAP/jenkins-library/provenance/buildtypes/step/v1"
+	// ProvenanceDirectory is the directory the provenance statements are written to
+	ProvenanceDirectory = "provenance"
+
+	builderID = "https://github.com/SAP/jenkins-library"
+)
+
+// Statement is an in-toto statement with a SLSA provenance predicate
+type Statement struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     Provenance           `json:"predicate"`
+}
+
+// ResourceDescriptor describes a subject or a dependency of a build
+type ResourceDescriptor struct {
+	Name        string            `json:"name,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest,omitempty"`
+	Annotations map[string]any    `json:"annotations,omitempty"`
+}
+
+// Provenance is the SLSA provenance v1 predicate
+type Provenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the inputs of a build
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   map[string]any       `json:"externalParameters"`
+	InternalParameters   map[string]any       `json:"internalParameters,omitempty"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+// RunDetails describes the build platform and the invocation of a build
+type RunDetails struct {
+	Builder  Builder       `json:"builder"`
+	Metadata BuildMetadata `json:"metadata"`
+}
+
+// Builder identifies the build platform
+type Builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+// BuildMetadata contains the identification and timing of a build run
+type BuildMetadata struct {
+	InvocationID string     `json:"invocationId,omitempty"`
+	StartedOn    *time.Time `json:"startedOn,omitempty"`
+	FinishedOn   *time.Time `json:"finishedOn,omitempty"`
+}
+
+// ProvenanceOptions contains the information about a build which is recorded in the provenance
+type ProvenanceOptions struct {
+	StepName string
+	// StepVersion is the version of piper executing the step
+	StepVersion string
+	// Parameters is the invoking configuration of the step, secrets must not be contained
+	Parameters map[string]any
+	Subjects   []ResourceDescriptor
+	// SBOMFiles are CycloneDX SBOMs in XML or JSON format listing the resolved dependencies
+	SBOMFiles []string
+	StartedOn time.Time
+}
+
+// NewProvenance creates a provenance statement for the build artifacts.
+// Dependencies are taken from the SBOMs, the source and the run details from the orchestrator.
+func NewProvenance(options ProvenanceOptions, provider orchestrator.ConfigProvider, utils piperutils.FileUtils) (*Statement, error) {
+	if len(options.Subjects) == 0 {
+		return nil, fmt.Errorf("no build artifacts found for the provenance of step %v", options.StepName)
+	}
+
+	source, hasSource := sourceDependency(provider)
+	dependencies := []ResourceDescriptor{}
+	if hasSource {
+		dependencies = append(dependencies, source)
+	}
+	seen := map[string]bool{}
+	for _, sbomFile := range options.SBOMFiles {
+		content, err := utils.FileRead(sbomFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SBOM '%v': %w", sbomFile, err)
+		}
+		sbomDependencies, err := DependenciesFromSBOM(content)
+		if err != nil {
+			return nil, fmt.Errorf("SBOM '%v': %w", sbomFile, err)
+		}
+		for _, dependency := range sbomDependencies {
+			key := dependency.URI + "|" + dependency.Name
+			if !seen[key] {
+				seen[key] = true
+				dependencies = append(dependencies, dependency)
+			}
+		}
+	}
+
+	externalParameters := map[string]any{"step": options.StepName}
+	if len(options.Parameters) > 0 {
+		externalParameters["config"] = options.Parameters
+	}
+	if hasSource {
+		externalParameters["source"] = source
+	}
+
+	internalParameters := map[string]any{}
+	addKnown(internalParameters, "orchestrator", provider.OrchestratorType())
+	addKnown(internalParameters, "stageName", provider.StageName())
+	addKnown(internalParameters, "jobName", provider.JobName())
+	addKnown(internalParameters, "jobUrl", provider.JobURL())
+	addKnown(internalParameters, "buildId", provider.BuildID())
+	addKnown(internalParameters, "buildUrl", provider.BuildURL())
+	addKnown(internalParameters, "buildReason", provider.BuildReason())
+
+	version := map[string]string{}
+	if len(options.StepVersion) > 0 {
+		version["piper"] = options.StepVersion
+	}
+	if orchestratorVersion := provider.OrchestratorVersion(); known(orchestratorVersion) {
+		version[strings.ToLower(provider.OrchestratorType())] = orchestratorVersion
+	}
+
+	finishedOn := time.Now().UTC()
+	metadata := BuildMetadata{FinishedOn: &finishedOn}
+	if !options.StartedOn.IsZero() {
+		startedOn := options.StartedOn.UTC()
+		metadata.StartedOn = &startedOn
+	}
+	if buildURL := provider.BuildURL(); known(buildURL) {
+		metadata.InvocationID = buildURL
+	} else if buildID := provider.BuildID(); known(buildID) {
+		metadata.InvocationID = buildID
+	}
+
+	return &Statement{
+		Type:          StatementType,
+		Subject:       options.Subjects,
+		PredicateType: ProvenancePredicateType,
+		Predicate: Provenance{
+			BuildDefinition: BuildDefinition{
+				BuildType:            ProvenanceBuildType,
+				ExternalParameters:   externalParameters,
+				InternalParameters:   internalParameters,
+				ResolvedDependencies: dependencies,
+			},
+			RunDetails: RunDetails{
+				Builder:  Builder{ID: builderID, Version: version},
+				Metadata: metadata,
+			},
+		},
+	}, nil
+}
+
+// FileSubjects returns the subjects of build artifacts available as files
+func FileSubjects(files []string, utils piperutils.FileUtils) ([]ResourceDescriptor, error) {
+	subjects := []ResourceDescriptor{}
+	for _, file := range files {
+		digest, err := utils.SHA256(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate digest of '%v': %w", file, err)
+		}
+		subjects = append(subjects, ResourceDescriptor{Name: path.Base(strings.ReplaceAll(file, "\\", "/")), Digest: map[string]string{"sha256": digest}})
+	}
+	return subjects, nil
+}
+
+// ImageSubjects returns the subjects of container images referenced by digest, e.g. `my.registry.com/my-image@sha256:...`
+func ImageSubjects(images []string) ([]ResourceDescriptor, error) {
+	subjects := []ResourceDescriptor{}
+	for _, image := range images {
+		name, digest, found := strings.Cut(image, "@")
+		algorithm, value, valid := strings.Cut(digest, ":")
+		if !found || !valid || len(value) == 0 {
+			return nil, fmt.Errorf("image '%v' is not referenced by digest", image)
+		}
+		subjects = append(subjects, ResourceDescriptor{Name: name, Digest: map[string]string{algorithm: value}})
+	}
+	return subjects, nil
+}
+
+// DependenciesFromSBOM returns the components of a CycloneDX SBOM in XML or JSON format as resolved dependencies.
+// Components are identified by their package URL, hashes declared in the SBOM are used as digests.
+func DependenciesFromSBOM(content []byte) ([]ResourceDescriptor, error) {
+	bomFormat := cdx.BOMFileFormatXML
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '{' {
+		bomFormat = cdx.BOMFileFormatJSON
+	}
+	var bom cdx.BOM
+	if err := cdx.NewBOMDecoder(bytes.NewReader(content), bomFormat).Decode(&bom); err != nil {
+		return nil, fmt.Errorf("failed to decode SBOM: %w", err)
+	}
+	dependencies := []ResourceDescriptor{}
+	if bom.Components != nil {
+		collectDependencies(*bom.Components, &dependencies)
+	}
+	return dependencies, nil
+}
+
+func collectDependencies(components []cdx.Component, dependencies *[]ResourceDescriptor) {
+	for _, component := range components {
+		name := component.Name
+		if len(component.Group) > 0 {
+			name = component.Group + "/" + component.Name
+		}
+		dependency := ResourceDescriptor{Name: name, URI: component.PackageURL}
+		if len(component.PackageURL) == 0 && len(component.Version) > 0 {
+			dependency.Name = name + "@" + component.Version
+		}
+		if component.Hashes != nil {
+			for _, hash := range *component.Hashes {
+				if algorithm := digestAlgorithm(hash.Algorithm); len(algorithm) > 0 && len(hash.Value) > 0 {
+					if dependency.Digest == nil {
+						dependency.Digest = map[string]string{}
+					}
+					dependency.Digest[algorithm] = strings.ToLower(hash.Value)
+				}
+			}
+		}
+		if len(dependency.Name) > 0 || len(dependency.URI) > 0 {
+			*dependencies = append(*dependencies, dependency)
+		}
+		if component.Components != nil {
+			collectDependencies(*component.Components, dependencies)
+		}
+	}
+}
+
+// digestAlgorithm maps CycloneDX hash algorithms to the digest names of in-toto
+func digestAlgorithm(algorithm cdx.HashAlgorithm) string {
+	switch algorithm {
+	case cdx.HashAlgoSHA1:
+		return "sha1"
+	case cdx.HashAlgoSHA256:
+		return "sha256"
+	case cdx.HashAlgoSHA384:
+		return "sha384"
+	case cdx.HashAlgoSHA512:
+		return "sha512"
+	case cdx.HashAlgoSHA3_256:
+		return "sha3-256"
+	case cdx.HashAlgoSHA3_512:
+		return "sha3-512"
+	case cdx.HashAlgoMD5:
+		return "md5"
+	}
+	return ""
+}
+
+// WriteProvenance writes the statement to the provenance directory and returns the path of the file
+func WriteProvenance(statement *Statement, stepName string, utils piperutils.FileUtils) (string, error) {
+	content, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal provenance: %w", err)
+	}
+	if err := utils.MkdirAll(ProvenanceDirectory, 0o777); err != nil {
+		return "", fmt.Errorf("failed to create directory '%v': %w", ProvenanceDirectory, err)
+	}
+	provenanceFile := path.Join(ProvenanceDirectory, stepName+".intoto.json")
+	if err := utils.FileWrite(provenanceFile, content, 0o666); err != nil {
+		return "", fmt.Errorf("failed to write provenance '%v': %w", provenanceFile, err)
+	}
+	log.Entry().Infof("Provenance of %v artifact(s) written to '%v'", len(statement.Subject), provenanceFile)
+	return provenanceFile, nil
+}
+
+func sourceDependency(provider orchestrator.ConfigProvider) (ResourceDescriptor, bool) {
+	repoURL := provider.RepoURL()
+	if !known(repoURL) {
+		return ResourceDescriptor{}, false
+	}
+	source := ResourceDescriptor{URI: "git+" + strings.TrimPrefix(repoURL, "git+")}
+	if commit := provider.CommitSHA(); known(commit) {
+		source.Digest = map[string]string{"gitCommit": commit}
+	}
+	if ref := provider.GitReference(); known(ref) {
+		source.Annotations = map[string]any{"ref": ref}
+	}
+	return source, true
+}
+
+// known filters values which are not provided by the orchestrator
+func known(value string) bool {
+	return len(value) > 0 && value != "n/a"
+}
+
+func addKnown(parameters map[string]any, key, value string) {
+	if known(value) {
+		parameters[key] = value
+	}
+}
@@ -0,0 +1,176 @@
+//go:build unit
+
+package build
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+)
+
+type provenanceTestProvider struct {
+	orchestrator.UnknownOrchestratorConfigProvider
+}
+
+func (p *provenanceTestProvider) OrchestratorType() string    { return "GitHubActions" }
+func (p *provenanceTestProvider) OrchestratorVersion() string { return "n/a" }
+func (p *provenanceTestProvider) StageName() string           { return "Build" }
+func (p *provenanceTestProvider) JobName() string             { return "n/a" }
+func (p *provenanceTestProvider) JobURL() string              { return "https://github.com/SAP/app/actions/runs/42" }
+func (p *provenanceTestProvider) BuildID() string             { return "42" }
+func (p *provenanceTestProvider) BuildURL() string {
+	return "https://github.com/SAP/app/actions/runs/42/attempts/1"
+}
+func (p *provenanceTestProvider) BuildReason() string  { return "Push" }
+func (p *provenanceTestProvider) RepoURL() string      { return "https://github.com/SAP/app" }
+func (p *provenanceTestProvider) CommitSHA() string    { return "0123456789abcdef" }
+func (p *provenanceTestProvider) GitReference() string { return "refs/heads/main" }
+
+const provenanceTestSBOM = `<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.4" version="1">
+  <metadata>
+    <component type="application"><name>app</name><version>1.0.0</version></component>
+  </metadata>
+  <components>
+    <component type="library">
+      <group>org.example</group>
+      <name>lib</name>
+      <version>2.0.0</version>
+      <hashes>
+        <hash alg="SHA-256">ABCDEF</hash>
+        <hash alg="BLAKE3">ignored</hash>
+      </hashes>
+      <purl>pkg:maven/org.example/lib@2.0.0</purl>
+      <components>
+        <component type="library"><name>nested</name><version>0.1.0</version></component>
+      </components>
+    </component>
+  </components>
+</bom>`
+
+func TestNewProvenance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		files := &mock.FilesMock{}
+		files.AddFile("bom-maven.xml", []byte(provenanceTestSBOM))
+		files.AddFile("bom-other.json", []byte(`{"bomFormat": "CycloneDX", "specVersion": "1.4", "components": [{"type": "library", "name": "lib", "group": "org.example", "version": "2.0.0", "purl": "pkg:maven/org.example/lib@2.0.0"}]}`))
+		startedOn := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		statement, err := NewProvenance(ProvenanceOptions{
+			StepName:    "mavenBuild",
+			StepVersion: "abc",
+			Parameters:  map[string]any{"publish": true},
+			Subjects:    []ResourceDescriptor{{Name: "app.jar", Digest: map[string]string{"sha256": "1234"}}},
+			SBOMFiles:   []string{"bom-maven.xml", "bom-other.json"},
+			StartedOn:   startedOn,
+		}, &provenanceTestProvider{}, files)
+
+		require.NoError(t, err)
+		assert.Equal(t, StatementType, statement.Type)
+		assert.Equal(t, ProvenancePredicateType, statement.PredicateType)
+		assert.Equal(t, []ResourceDescriptor{{Name: "app.jar", Digest: map[string]string{"sha256": "1234"}}}, statement.Subject)
+
+		definition := statement.Predicate.BuildDefinition
+		assert.Equal(t, ProvenanceBuildType, definition.BuildType)
+		assert.Equal(t, "mavenBuild", definition.ExternalParameters["step"])
+		assert.Equal(t, map[string]any{"publish": true}, definition.ExternalParameters["config"])
+		assert.Equal(t, map[string]any{
+			"orchestrator": "GitHubActions",
+			"stageName":    "Build",
+			"jobUrl":       "https://github.com/SAP/app/actions/runs/42",
+			"buildId":      "42",
+			"buildUrl":     "https://github.com/SAP/app/actions/runs/42/attempts/1",
+			"buildReason":  "Push",
+		}, definition.InternalParameters)
+		assert.Equal(t, []ResourceDescriptor{
+			{URI: "git+https://github.com/SAP/app", Digest: map[string]string{"gitCommit": "0123456789abcdef"}, Annotations: map[string]any{"ref": "refs/heads/main"}},
+			{Name: "org.example/lib", URI: "pkg:maven/org.example/lib@2.0.0", Digest: map[string]string{"sha256": "abcdef"}},
+			{Name: "nested@0.1.0"},
+		}, definition.ResolvedDependencies)
+
+		details := statement.Predicate.RunDetails
+		assert.Equal(t, Builder{ID: "https://github.com/SAP/jenkins-library", Version: map[string]string{"piper": "abc"}}, details.Builder)
+		assert.Equal(t, "https://github.com/SAP/app/actions/runs/42/attempts/1", details.Metadata.InvocationID)
+		assert.Equal(t, startedOn, *details.Metadata.StartedOn)
+		assert.NotNil(t, details.Metadata.FinishedOn)
+	})
+
+	t.Run("unknown orchestrator", func(t *testing.T) {
+		t.Parallel()
+		statement, err := NewProvenance(ProvenanceOptions{
+			StepName: "golangBuild",
+			Subjects: []ResourceDescriptor{{Name: "app", Digest: map[string]string{"sha256": "1234"}}},
+		}, &orchestrator.UnknownOrchestratorConfigProvider{}, &mock.FilesMock{})
+
+		require.NoError(t, err)
+		assert.Empty(t, statement.Predicate.BuildDefinition.ResolvedDependencies)
+		assert.Equal(t, map[string]any{"orchestrator": "Unknown"}, statement.Predicate.BuildDefinition.InternalParameters)
+		assert.NotContains(t, statement.Predicate.BuildDefinition.ExternalParameters, "source")
+		assert.Empty(t, statement.Predicate.RunDetails.Metadata.InvocationID)
+		assert.Nil(t, statement.Predicate.RunDetails.Metadata.StartedOn)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewProvenance(ProvenanceOptions{StepName: "golangBuild"}, &provenanceTestProvider{}, &mock.FilesMock{})
+		assert.EqualError(t, err, "no build artifacts found for the provenance of step golangBuild")
+
+		files := &mock.FilesMock{}
+		files.AddFile("bom.xml", []byte("no sbom"))
+		options := ProvenanceOptions{Subjects: []ResourceDescriptor{{Name: "app"}}, SBOMFiles: []string{"missing.xml"}}
+		_, err = NewProvenance(options, &provenanceTestProvider{}, files)
+		assert.ErrorContains(t, err, "failed to read SBOM 'missing.xml'")
+
+		options.SBOMFiles = []string{"bom.xml"}
+		_, err = NewProvenance(options, &provenanceTestProvider{}, files)
+		assert.ErrorContains(t, err, "SBOM 'bom.xml': failed to decode SBOM")
+	})
+}
+
+func TestImageSubjects(t *testing.T) {
+	t.Parallel()
+	subjects, err := ImageSubjects([]string{"my.registry.com/app@sha256:1234"})
+	assert.NoError(t, err)
+	assert.Equal(t, []ResourceDescriptor{{Name: "my.registry.com/app", Digest: map[string]string{"sha256": "1234"}}}, subjects)
+
+	_, err = ImageSubjects([]string{"my.registry.com/app:1.0.0"})
+	assert.EqualError(t, err, "image 'my.registry.com/app:1.0.0' is not referenced by digest")
+}
+
+func TestFileSubjects(t *testing.T) {
+	t.Parallel()
+	files := &mock.FilesMock{}
+	files.AddFile("target/app.jar", []byte("content"))
+
+	subjects, err := FileSubjects([]string{"target/app.jar"}, files)
+
+	assert.NoError(t, err)
+	require.Len(t, subjects, 1)
+	assert.Equal(t, "app.jar", subjects[0].Name)
+	assert.Len(t, subjects[0].Digest["sha256"], 64)
+}
+
+func TestWriteProvenance(t *testing.T) {
+	t.Parallel()
+	files := &mock.FilesMock{}
+	statement := &Statement{Type: StatementType, Subject: []ResourceDescriptor{{Name: "app"}}, PredicateType: ProvenancePredicateType}
+
+	provenanceFile, err := WriteProvenance(statement, "golangBuild", files)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "provenance/golangBuild.intoto.json", provenanceFile)
+	content, err := files.FileRead(provenanceFile)
+	require.NoError(t, err)
+	written := map[string]any{}
+	require.NoError(t, json.Unmarshal(content, &written))
+	assert.Equal(t, StatementType, written["_type"])
+	assert.Equal(t, ProvenancePredicateType, written["predicateType"])
+}
@@ -0,0 +1,162 @@
+package buildcache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// createArchive writes the content of the directories into a gzipped tar archive.
+// The entries are prefixed with the index of their directory, so that they can be restored into other locations.
+func createArchive(archivePath string, dirs []string) (err error) {
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive '%v': %w", archivePath, err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for index, dir := range dirs {
+		if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		err := filepath.WalkDir(dir, func(current string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// only directories and regular files are cached, links and sockets in cache directories are not worth restoring
+			if !entry.IsDir() && !entry.Type().IsRegular() {
+				return nil
+			}
+			relativePath, err := filepath.Rel(dir, current)
+			if err != nil {
+				return err
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = path.Join(strconv.Itoa(index), filepath.ToSlash(relativePath))
+			if entry.IsDir() {
+				header.Name += "/"
+			}
+			if err := tarWriter.WriteHeader(header); err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			return copyFile(tarWriter, current)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to archive '%v': %w", dir, err)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write archive '%v': %w", archivePath, err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write archive '%v': %w", archivePath, err)
+	}
+	return nil
+}
+
+func copyFile(target io.Writer, source string) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(target, file)
+	return err
+}
+
+// extractArchive restores the content of the archive into the directories.
+// Directories are created writable, so that tools can add to the restored caches.
+// Existing files are kept.
+func extractArchive(archivePath string, dirs []string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive '%v': %w", archivePath, err)
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read archive '%v': %w", archivePath, err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive '%v': %w", archivePath, err)
+		}
+		index, relativePath, found := strings.Cut(strings.TrimSuffix(header.Name, "/"), "/")
+		dirIndex, indexErr := strconv.Atoi(index)
+		if indexErr != nil || dirIndex < 0 || dirIndex >= len(dirs) {
+			continue
+		}
+		target := dirs[dirIndex]
+		if found {
+			target = filepath.Join(dirs[dirIndex], filepath.FromSlash(relativePath))
+			if !strings.HasPrefix(target, filepath.Clean(dirs[dirIndex])+string(os.PathSeparator)) {
+				return fmt.Errorf("invalid entry '%v' in archive '%v'", header.Name, archivePath)
+			}
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return fmt.Errorf("failed to create directory '%v': %w", target, err)
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tarReader, header.FileInfo().Mode().Perm()); err != nil {
+				return fmt.Errorf("failed to restore file '%v': %w", target, err)
+			}
+		}
+	}
+}
+
+// writeFile creates the file unless it exists already, since files in dependency caches do not change once downloaded
+// and files of a Go module cache are read-only.
+func writeFile(target string, content io.Reader, mode fs.FileMode) error {
+	if _, err := os.Lstat(target); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_EXCL, mode|0200)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Chmod(target, mode)
+}
@@ -0,0 +1,70 @@
+//go:build unit
+// +build unit
+
+package buildcache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	t.Parallel()
+	source := t.TempDir()
+	modCache := filepath.Join(source, "mod")
+	require.NoError(t, os.MkdirAll(filepath.Join(modCache, "github.com", "pkg", "errors@v0.9.1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(modCache, "github.com", "pkg", "errors@v0.9.1", "errors.go"), []byte("package errors"), 0444))
+	require.NoError(t, os.MkdirAll(filepath.Join(modCache, "cache", "empty"), 0755))
+	archive := filepath.Join(t.TempDir(), "cache.tar.gz")
+
+	require.NoError(t, createArchive(archive, []string{filepath.Join(source, "missing"), modCache}))
+
+	target := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(target, "existing"), []byte("kept"), 0644))
+	dirs := []string{filepath.Join(target, "other"), filepath.Join(target, "restored")}
+	require.NoError(t, extractArchive(archive, dirs))
+
+	restoredFile := filepath.Join(target, "restored", "github.com", "pkg", "errors@v0.9.1", "errors.go")
+	content, err := os.ReadFile(restoredFile)
+	require.NoError(t, err)
+	assert.Equal(t, "package errors", string(content))
+	info, err := os.Stat(restoredFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0444), info.Mode().Perm())
+	assert.DirExists(t, filepath.Join(target, "restored", "cache", "empty"))
+	assert.NoDirExists(t, filepath.Join(target, "other"))
+
+	t.Run("keeps existing files", func(t *testing.T) {
+		require.NoError(t, extractArchive(archive, dirs))
+		content, err := os.ReadFile(restoredFile)
+		require.NoError(t, err)
+		assert.Equal(t, "package errors", string(content))
+	})
+}
+
+func TestExtractArchiveRejectsTraversal(t *testing.T) {
+	t.Parallel()
+	archive := filepath.Join(t.TempDir(), "cache.tar.gz")
+	file, err := os.Create(archive)
+	require.NoError(t, err)
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "0/../../evil", Typeflag: tar.TypeReg, Mode: 0644, Size: 4}))
+	_, err = tarWriter.Write([]byte("evil"))
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, file.Close())
+
+	target := filepath.Join(t.TempDir(), "cache")
+	err = extractArchive(archive, []string{target})
+
+	assert.ErrorContains(t, err, "invalid entry '0/../../evil'")
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(target), "..", "evil"))
+}
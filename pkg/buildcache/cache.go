@@ -0,0 +1,110 @@
+package buildcache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// Config configures the dependency cache of a build step
+type Config struct {
+	// Location is the directory or bucket URL the cache archives are stored in, the cache is disabled if it is empty
+	Location    string
+	Credentials string
+	// Save uploads the cache after a successful build if the key was not restored
+	Save bool
+}
+
+// Cache is a restored dependency cache which can be saved after the build
+type Cache struct {
+	storage  Storage
+	key      string
+	dirs     []string
+	save     bool
+	restored bool
+}
+
+// Restore downloads the archive stored under the key and extracts it into the cache directories.
+// A cache must never fail a build, therefore all failures are only logged and a nil Cache is returned
+// if the cache is disabled, the key is empty or the storage is not available.
+func Restore(ctx context.Context, config Config, key string, dirs []string) *Cache {
+	if config.Location == "" {
+		return nil
+	}
+	if key == "" {
+		log.Entry().Info("No lock files found, dependency cache is not used")
+		return nil
+	}
+	storage, err := NewStorage(config.Location, config.Credentials)
+	if err != nil {
+		log.Entry().WithError(err).Warn("Dependency cache is not available")
+		return nil
+	}
+	cache := &Cache{storage: storage, key: key, dirs: dirs, save: config.Save}
+
+	tempDir, err := os.MkdirTemp("", "buildcache")
+	if err != nil {
+		log.Entry().WithError(err).Warn("Failed to restore dependency cache")
+		return cache
+	}
+	defer os.RemoveAll(tempDir)
+	archive := filepath.Join(tempDir, "cache.tar.gz")
+
+	found, err := storage.Download(ctx, archiveName(key), archive)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("Failed to download dependency cache '%v'", key)
+		return cache
+	}
+	if !found {
+		log.Entry().Infof("Dependency cache '%v' not found", key)
+		return cache
+	}
+	if err := extractArchive(archive, dirs); err != nil {
+		log.Entry().WithError(err).Warnf("Failed to restore dependency cache '%v'", key)
+		return cache
+	}
+	log.Entry().Infof("Restored dependency cache '%v' into %v", key, dirs)
+	cache.restored = true
+	return cache
+}
+
+// Save uploads the cache directories under the key unless the cache was restored from this key before.
+// Failures are only logged.
+func (c *Cache) Save(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	defer func() {
+		if err := c.storage.Close(); err != nil {
+			log.Entry().WithError(err).Debug("Failed to close dependency cache storage")
+		}
+	}()
+	if !c.save || c.restored {
+		log.Entry().Debugf("Dependency cache '%v' is not saved", c.key)
+		return
+	}
+
+	tempDir, err := os.MkdirTemp("", "buildcache")
+	if err != nil {
+		log.Entry().WithError(err).Warn("Failed to save dependency cache")
+		return
+	}
+	defer os.RemoveAll(tempDir)
+	archive := filepath.Join(tempDir, "cache.tar.gz")
+
+	if err := createArchive(archive, c.dirs); err != nil {
+		log.Entry().WithError(err).Warnf("Failed to save dependency cache '%v'", c.key)
+		return
+	}
+	if err := c.storage.Upload(ctx, archive, archiveName(c.key)); err != nil {
+		log.Entry().WithError(err).Warnf("Failed to upload dependency cache '%v'", c.key)
+		return
+	}
+	log.Entry().Infof("Saved dependency cache '%v'", c.key)
+}
+
+func archiveName(key string) string {
+	return key + ".tar.gz"
+}
@@ -0,0 +1,60 @@
+//go:build unit
+// +build unit
+
+package buildcache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	location := t.TempDir()
+	config := Config{Location: location, Save: true}
+	repository := filepath.Join(t.TempDir(), "repository")
+	require.NoError(t, os.MkdirAll(filepath.Join(repository, "org", "example"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repository, "org", "example", "lib.jar"), []byte("jar"), 0644))
+
+	t.Run("miss and save", func(t *testing.T) {
+		cache := Restore(ctx, config, "maven-0123456789abcdef", []string{repository})
+		require.NotNil(t, cache)
+		assert.False(t, cache.restored)
+
+		cache.Save(ctx)
+		assert.FileExists(t, filepath.Join(location, "maven-0123456789abcdef.tar.gz"))
+	})
+
+	t.Run("hit", func(t *testing.T) {
+		restoredRepository := filepath.Join(t.TempDir(), "repository")
+		cache := Restore(ctx, config, "maven-0123456789abcdef", []string{restoredRepository})
+		require.NotNil(t, cache)
+		assert.True(t, cache.restored)
+		assert.FileExists(t, filepath.Join(restoredRepository, "org", "example", "lib.jar"))
+
+		require.NoError(t, os.Remove(filepath.Join(location, "maven-0123456789abcdef.tar.gz")))
+		cache.Save(ctx)
+		assert.NoFileExists(t, filepath.Join(location, "maven-0123456789abcdef.tar.gz"), "a restored cache is not saved again")
+	})
+
+	t.Run("read only", func(t *testing.T) {
+		cache := Restore(ctx, Config{Location: location}, "maven-fedcba9876543210", []string{repository})
+		require.NotNil(t, cache)
+		cache.Save(ctx)
+		assert.NoFileExists(t, filepath.Join(location, "maven-fedcba9876543210.tar.gz"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Nil(t, Restore(ctx, Config{}, "maven-0123456789abcdef", []string{repository}))
+		assert.Nil(t, Restore(ctx, config, "", []string{repository}))
+		assert.Nil(t, Restore(ctx, Config{Location: "ftp://cache/maven"}, "maven-0123456789abcdef", []string{repository}))
+		var cache *Cache
+		cache.Save(ctx)
+	})
+}
@@ -0,0 +1,68 @@
+package buildcache
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// MavenDirs returns the local maven repository, which is the M2Path of the step if configured
+func MavenDirs(m2Path string) []string {
+	if m2Path != "" {
+		if absolutePath, err := filepath.Abs(m2Path); err == nil {
+			return []string{absolutePath}
+		}
+		return []string{m2Path}
+	}
+	return []string{filepath.Join(homeDir(), ".m2", "repository")}
+}
+
+// NpmDirs returns the cache of npm, the store of pnpm and the global caches of Yarn Berry and Bun
+func NpmDirs() []string {
+	npmCache := os.Getenv("npm_config_cache")
+	if npmCache == "" {
+		npmCache = filepath.Join(homeDir(), ".npm")
+	}
+	return []string{
+		npmCache,
+		filepath.Join(homeDir(), ".local", "share", "pnpm", "store"),
+		filepath.Join(homeDir(), ".yarn", "berry", "cache"),
+		filepath.Join(homeDir(), ".bun", "install", "cache"),
+	}
+}
+
+// GoDirs returns the Go module cache
+func GoDirs() []string {
+	if modCache := os.Getenv("GOMODCACHE"); modCache != "" {
+		return []string{modCache}
+	}
+	if goPath := filepath.SplitList(os.Getenv("GOPATH")); len(goPath) > 0 && goPath[0] != "" {
+		return []string{filepath.Join(goPath[0], "pkg", "mod")}
+	}
+	return []string{filepath.Join(homeDir(), "go", "pkg", "mod")}
+}
+
+// PipDirs returns the caches of pip, Poetry, PDM and uv
+func PipDirs() []string {
+	dirs := []string{}
+	for _, cache := range []struct{ env, dir string }{
+		{"PIP_CACHE_DIR", "pip"},
+		{"POETRY_CACHE_DIR", "pypoetry"},
+		{"PDM_CACHE_DIR", "pdm"},
+		{"UV_CACHE_DIR", "uv"},
+	} {
+		if cacheDir := os.Getenv(cache.env); cacheDir != "" {
+			dirs = append(dirs, cacheDir)
+		} else {
+			dirs = append(dirs, filepath.Join(homeDir(), ".cache", cache.dir))
+		}
+	}
+	return dirs
+}
+
+func homeDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	return home
+}
@@ -0,0 +1,93 @@
+package buildcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/maven"
+)
+
+// lock files identifying the dependencies of a project per tool
+var (
+	NpmLockFiles = []string{"**/package-lock.json", "**/npm-shrinkwrap.json", "**/pnpm-lock.yaml", "**/yarn.lock", "**/bun.lock", "**/bun.lockb"}
+	GoLockFiles  = []string{"**/go.sum"}
+	PipLockFiles = []string{"**/requirements*.txt", "**/poetry.lock", "**/pdm.lock", "**/uv.lock"}
+)
+
+// KeyUtils provides the file access needed to calculate cache keys
+type KeyUtils interface {
+	FileExists(path string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	Glob(pattern string) (matches []string, err error)
+}
+
+// MavenKey calculates the cache key from the pom.xml files of all modules of the maven project.
+// An empty key is returned if there is no pom.xml.
+func MavenKey(pomPath string, utils KeyUtils) (string, error) {
+	if pomPath == "" {
+		pomPath = "pom.xml"
+	}
+	hasher := sha256.New()
+	found := false
+	err := maven.VisitAllMavenModules(filepath.Dir(pomPath), utils, nil, func(info maven.ModuleInfo) error {
+		found = true
+		return addFile(hasher, info.PomXMLPath, utils)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate cache key from maven modules: %w", err)
+	}
+	if !found {
+		return "", nil
+	}
+	return formatKey("maven", hasher), nil
+}
+
+// FilesKey calculates the cache key of the tool from the files matching the patterns, ignoring files in node_modules.
+// An empty key is returned if no file matches.
+func FilesKey(tool string, patterns []string, utils KeyUtils) (string, error) {
+	files := []string{}
+	for _, pattern := range patterns {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			return "", fmt.Errorf("failed to search for files matching '%v': %w", pattern, err)
+		}
+		for _, match := range matches {
+			if slices.Contains(strings.Split(filepath.ToSlash(match), "/"), "node_modules") || slices.Contains(files, match) {
+				continue
+			}
+			files = append(files, match)
+		}
+	}
+	if len(files) == 0 {
+		return "", nil
+	}
+	slices.Sort(files)
+
+	hasher := sha256.New()
+	for _, file := range files {
+		if err := addFile(hasher, file, utils); err != nil {
+			return "", fmt.Errorf("failed to calculate cache key: %w", err)
+		}
+	}
+	return formatKey(tool, hasher), nil
+}
+
+// addFile adds path and content of the file to the hash, so that moving a lock file changes the key as well
+func addFile(hasher hash.Hash, file string, utils KeyUtils) error {
+	content, err := utils.FileRead(file)
+	if err != nil {
+		return fmt.Errorf("failed to read '%v': %w", file, err)
+	}
+	fileHash := sha256.Sum256(content)
+	fmt.Fprintf(hasher, "%v %x\n", filepath.ToSlash(file), fileHash)
+	return nil
+}
+
+func formatKey(tool string, hasher hash.Hash) string {
+	return tool + "-" + hex.EncodeToString(hasher.Sum(nil))[:16]
+}
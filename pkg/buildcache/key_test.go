@@ -0,0 +1,72 @@
+//go:build unit
+// +build unit
+
+package buildcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func TestMavenKey(t *testing.T) {
+	t.Parallel()
+	newFiles := func(moduleVersion string) *mock.FilesMock {
+		files := &mock.FilesMock{}
+		files.AddFile("pom.xml", []byte("<project><artifactId>parent</artifactId><modules><module>app</module></modules></project>"))
+		files.AddFile("app/pom.xml", []byte("<project><artifactId>app</artifactId><version>"+moduleVersion+"</version></project>"))
+		return files
+	}
+
+	key, err := MavenKey("", newFiles("1.0.0"))
+	require.NoError(t, err)
+	assert.Regexp(t, "^maven-[0-9a-f]{16}$", key)
+
+	sameKey, err := MavenKey("pom.xml", newFiles("1.0.0"))
+	require.NoError(t, err)
+	assert.Equal(t, key, sameKey)
+
+	otherKey, err := MavenKey("pom.xml", newFiles("1.0.1"))
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherKey, "changes of modules must change the key")
+
+	emptyKey, err := MavenKey("pom.xml", &mock.FilesMock{})
+	require.NoError(t, err)
+	assert.Empty(t, emptyKey)
+}
+
+func TestFilesKey(t *testing.T) {
+	t.Parallel()
+	files := &mock.FilesMock{}
+	files.AddFile("package-lock.json", []byte(`{"lockfileVersion": 3}`))
+	files.AddFile("ui/pnpm-lock.yaml", []byte("lockfileVersion: '9.0'"))
+
+	key, err := FilesKey("npm", NpmLockFiles, files)
+	require.NoError(t, err)
+	assert.Regexp(t, "^npm-[0-9a-f]{16}$", key)
+
+	t.Run("ignores node_modules", func(t *testing.T) {
+		files.AddFile("node_modules/dependency/package-lock.json", []byte(`{}`))
+		sameKey, err := FilesKey("npm", NpmLockFiles, files)
+		require.NoError(t, err)
+		assert.Equal(t, key, sameKey)
+	})
+
+	t.Run("changes with the content", func(t *testing.T) {
+		changed := &mock.FilesMock{}
+		changed.AddFile("package-lock.json", []byte(`{"lockfileVersion": 3}`))
+		changed.AddFile("ui/pnpm-lock.yaml", []byte("lockfileVersion: '6.0'"))
+		otherKey, err := FilesKey("npm", NpmLockFiles, changed)
+		require.NoError(t, err)
+		assert.NotEqual(t, key, otherKey)
+	})
+
+	t.Run("without lock files", func(t *testing.T) {
+		emptyKey, err := FilesKey("go", GoLockFiles, files)
+		require.NoError(t, err)
+		assert.Empty(t, emptyKey)
+	})
+}
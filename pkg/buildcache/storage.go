@@ -0,0 +1,292 @@
+package buildcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/SAP/jenkins-library/pkg/gcs"
+)
+
+// Storage stores cache archives under their key
+type Storage interface {
+	// Download writes the archive stored under the key into the target file and returns false if there is no such archive
+	Download(ctx context.Context, key, targetFile string) (bool, error)
+	Upload(ctx context.Context, sourceFile, key string) error
+	Close() error
+}
+
+// NewStorage creates the storage for the location, which is either an URL of a bucket with an optional prefix
+// (s3://bucket/prefix, gs://bucket/prefix, azblob://container/prefix) or a local directory.
+// The credentials are the JSON credentials of the respective cloud provider.
+func NewStorage(location, credentials string) (Storage, error) {
+	scheme, _, found := strings.Cut(location, "://")
+	if !found {
+		return &directoryStorage{dir: location}, nil
+	}
+	parsed, err := url.Parse(location)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid cache location '%v'", location)
+	}
+	prefix := strings.Trim(parsed.Path, "/")
+	switch scheme {
+	case "s3":
+		return newS3Storage(parsed.Host, prefix, credentials)
+	case "gs":
+		return newGCSStorage(parsed.Host, prefix, credentials)
+	case "azblob":
+		return newAzureStorage(parsed.Host, prefix, credentials)
+	default:
+		return nil, fmt.Errorf("unsupported cache location '%v', supported are s3://, gs://, azblob:// and local directories", location)
+	}
+}
+
+func objectName(prefix, key string) string {
+	return path.Join(prefix, key)
+}
+
+// directoryStorage stores the archives in a local directory, e.g. a volume shared between builds
+type directoryStorage struct {
+	dir string
+}
+
+func (s *directoryStorage) Download(_ context.Context, key, targetFile string) (bool, error) {
+	source, err := os.Open(filepath.Join(s.dir, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer source.Close()
+	return true, writeTo(targetFile, source)
+}
+
+// Upload copies the archive into a temporary file first, so that concurrent builds never read a partial archive
+func (s *directoryStorage) Upload(_ context.Context, sourceFile, key string) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	target := filepath.Join(s.dir, key)
+	source, err := os.Open(sourceFile)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	temp, err := os.CreateTemp(s.dir, "."+key+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := io.Copy(temp, source); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), target)
+}
+
+func (s *directoryStorage) Close() error {
+	return nil
+}
+
+func writeTo(targetFile string, content io.Reader) error {
+	target, err := os.Create(targetFile)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(target, content); err != nil {
+		target.Close()
+		return err
+	}
+	return target.Close()
+}
+
+// s3API contains the functions of the S3 client used by the storage, so that it can be mocked
+type s3API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// s3Credentials uses the format of the credentials of awsS3Upload, an optional endpoint allows S3 compatible storages
+type s3Credentials struct {
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	Region          string `json:"region"`
+	Endpoint        string `json:"endpoint"`
+}
+
+type s3Storage struct {
+	client s3API
+	bucket string
+	prefix string
+}
+
+// newS3Storage uses the default credential chain of the AWS SDK, e.g. an instance role, unless credentials are provided
+func newS3Storage(bucket, prefix, jsonCredentials string) (Storage, error) {
+	var creds s3Credentials
+	if jsonCredentials != "" {
+		if err := json.Unmarshal([]byte(jsonCredentials), &creds); err != nil {
+			return nil, fmt.Errorf("failed to read AWS credentials: %w", err)
+		}
+	}
+	options := []func(*config.LoadOptions) error{}
+	if creds.Region != "" {
+		options = append(options, config.WithRegion(creds.Region))
+	}
+	if creds.AccessKeyID != "" {
+		options = append(options, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(creds.AccessKeyID, creds.SecretAccessKey, "")))
+	}
+	cfg, err := config.LoadDefaultConfig(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure AWS client: %w", err)
+	}
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if creds.Endpoint != "" {
+			o.BaseEndpoint = aws.String(creds.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	return &s3Storage{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (s *s3Storage) Download(ctx context.Context, key, targetFile string) (bool, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(objectName(s.prefix, key)),
+	})
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer output.Body.Close()
+	return true, writeTo(targetFile, output.Body)
+}
+
+func (s *s3Storage) Upload(ctx context.Context, sourceFile, key string) error {
+	source, err := os.Open(sourceFile)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(objectName(s.prefix, key)),
+		Body:   source,
+	})
+	return err
+}
+
+func (s *s3Storage) Close() error {
+	return nil
+}
+
+type gcsStorage struct {
+	client gcs.Client
+	bucket string
+	prefix string
+}
+
+// newGCSStorage expects the JSON key of a service account as credentials
+func newGCSStorage(bucket, prefix, jsonKey string) (Storage, error) {
+	if jsonKey == "" {
+		return nil, errors.New("the cache location on Google Cloud Storage requires the JSON key of a service account as credentials")
+	}
+	client, err := gcs.NewClientFromJSONKey([]byte(jsonKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Google Cloud Storage client: %w", err)
+	}
+	return &gcsStorage{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (s *gcsStorage) Download(ctx context.Context, key, targetFile string) (bool, error) {
+	err := s.client.DownloadFile(ctx, s.bucket, objectName(s.prefix, key), targetFile)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *gcsStorage) Upload(ctx context.Context, sourceFile, key string) error {
+	return s.client.UploadFile(ctx, s.bucket, sourceFile, objectName(s.prefix, key))
+}
+
+func (s *gcsStorage) Close() error {
+	return s.client.Close()
+}
+
+// azureCredentials uses the format of the credentials of azureBlobUpload, the container is part of the location
+type azureCredentials struct {
+	SASToken    string `json:"sas_token"`
+	AccountName string `json:"account_name"`
+}
+
+type azureStorage struct {
+	client *container.Client
+	prefix string
+}
+
+func newAzureStorage(containerName, prefix, jsonCredentials string) (Storage, error) {
+	var creds azureCredentials
+	if err := json.Unmarshal([]byte(jsonCredentials), &creds); err != nil {
+		return nil, fmt.Errorf("failed to read Azure credentials: %w", err)
+	}
+	if creds.AccountName == "" || creds.SASToken == "" {
+		return nil, errors.New("the Azure credentials require account_name and sas_token")
+	}
+	serviceClient, err := service.NewClientWithNoCredential(fmt.Sprintf("https://%s.blob.core.windows.net/?%s", creds.AccountName, creds.SASToken), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure service client: %w", err)
+	}
+	return &azureStorage{client: serviceClient.NewContainerClient(containerName), prefix: prefix}, nil
+}
+
+func (s *azureStorage) Download(ctx context.Context, key, targetFile string) (bool, error) {
+	target, err := os.Create(targetFile)
+	if err != nil {
+		return false, err
+	}
+	defer target.Close()
+	_, err = s.client.NewBlockBlobClient(objectName(s.prefix, key)).DownloadFile(ctx, target, nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *azureStorage) Upload(ctx context.Context, sourceFile, key string) error {
+	source, err := os.Open(sourceFile)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	_, err = s.client.NewBlockBlobClient(objectName(s.prefix, key)).UploadFile(ctx, source, nil)
+	return err
+}
+
+func (s *azureStorage) Close() error {
+	return nil
+}
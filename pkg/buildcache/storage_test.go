@@ -0,0 +1,140 @@
+//go:build unit
+// +build unit
+
+package buildcache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStorage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("directory", func(t *testing.T) {
+		storage, err := NewStorage("/mnt/cache", "")
+		require.NoError(t, err)
+		assert.Equal(t, &directoryStorage{dir: "/mnt/cache"}, storage)
+	})
+
+	t.Run("S3", func(t *testing.T) {
+		storage, err := NewStorage("s3://build-cache/piper/", `{"access_key_id": "id", "secret_access_key": "secret", "region": "eu-central-1"}`)
+		require.NoError(t, err)
+		s3Storage, ok := storage.(*s3Storage)
+		require.True(t, ok)
+		assert.Equal(t, "build-cache", s3Storage.bucket)
+		assert.Equal(t, "piper", s3Storage.prefix)
+	})
+
+	t.Run("GCS", func(t *testing.T) {
+		storage, err := NewStorage("gs://build-cache/piper", `{"type": "service_account", "project_id": "project", "client_email": "cache@project.iam.gserviceaccount.com", "private_key": "key", "token_uri": "https://oauth2.googleapis.com/token"}`)
+		require.NoError(t, err)
+		gcsStorage, ok := storage.(*gcsStorage)
+		require.True(t, ok)
+		assert.Equal(t, "build-cache", gcsStorage.bucket)
+		assert.Equal(t, "piper", gcsStorage.prefix)
+		assert.NoError(t, storage.Close())
+	})
+
+	t.Run("Azure", func(t *testing.T) {
+		storage, err := NewStorage("azblob://build-cache", `{"account_name": "account", "sas_token": "sv=2024"}`)
+		require.NoError(t, err)
+		azureStorage, ok := storage.(*azureStorage)
+		require.True(t, ok)
+		assert.Empty(t, azureStorage.prefix)
+	})
+
+	t.Run("error cases", func(t *testing.T) {
+		_, err := NewStorage("ftp://build-cache", "")
+		assert.ErrorContains(t, err, "unsupported cache location 'ftp://build-cache'")
+
+		_, err = NewStorage("s3://", "")
+		assert.EqualError(t, err, "invalid cache location 's3://'")
+
+		_, err = NewStorage("gs://build-cache", "")
+		assert.ErrorContains(t, err, "requires the JSON key of a service account")
+
+		_, err = NewStorage("gs://build-cache", "no JSON key")
+		assert.ErrorContains(t, err, "failed to create Google Cloud Storage client")
+
+		_, err = NewStorage("azblob://build-cache", `{"account_name": "account"}`)
+		assert.EqualError(t, err, "the Azure credentials require account_name and sas_token")
+	})
+}
+
+func TestDirectoryStorage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	storage := &directoryStorage{dir: filepath.Join(t.TempDir(), "cache")}
+	source := filepath.Join(t.TempDir(), "archive")
+	require.NoError(t, os.WriteFile(source, []byte("archive"), 0644))
+	target := filepath.Join(t.TempDir(), "downloaded")
+
+	found, err := storage.Download(ctx, "npm-0123456789abcdef.tar.gz", target)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, storage.Upload(ctx, source, "npm-0123456789abcdef.tar.gz"))
+	found, err = storage.Download(ctx, "npm-0123456789abcdef.tar.gz", target)
+	require.NoError(t, err)
+	assert.True(t, found)
+	content, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "archive", string(content))
+	entries, err := os.ReadDir(storage.dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files must be left")
+}
+
+type s3Mock struct {
+	objects map[string][]byte
+}
+
+func (m *s3Mock) GetObject(_ context.Context, params *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	content, ok := m.objects[*params.Bucket+"/"+*params.Key]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(content))}, nil
+}
+
+func (m *s3Mock) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	content, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	m.objects[*params.Bucket+"/"+*params.Key] = content
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestS3Storage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := &s3Mock{objects: map[string][]byte{}}
+	storage := &s3Storage{client: client, bucket: "build-cache", prefix: "piper"}
+	source := filepath.Join(t.TempDir(), "archive")
+	require.NoError(t, os.WriteFile(source, []byte("archive"), 0644))
+	target := filepath.Join(t.TempDir(), "downloaded")
+
+	found, err := storage.Download(ctx, "go-0123456789abcdef.tar.gz", target)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, storage.Upload(ctx, source, "go-0123456789abcdef.tar.gz"))
+	assert.Contains(t, client.objects, "build-cache/piper/go-0123456789abcdef.tar.gz")
+	found, err = storage.Download(ctx, "go-0123456789abcdef.tar.gz", target)
+	require.NoError(t, err)
+	assert.True(t, found)
+	content, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "archive", string(content))
+}
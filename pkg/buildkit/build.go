@@ -0,0 +1,202 @@
+package buildkit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/auth/authprovider"
+	"github.com/moby/buildkit/session/secrets"
+	"github.com/moby/buildkit/session/secrets/secretsprovider"
+	"github.com/moby/buildkit/util/progress/progressui"
+	"github.com/tonistiigi/fsutil"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// BuildOptions configure the build of a Dockerfile by a BuildKit daemon
+type BuildOptions struct {
+	// Address of the BuildKit daemon, e.g. tcp://buildkitd:1234 or unix:///run/buildkit/buildkitd.sock
+	Address    string
+	ContextDir string
+	// Dockerfile is the absolute path of the Dockerfile
+	Dockerfile string
+	// Target is the stage of a multi-stage Dockerfile which is built
+	Target string
+	// BuildArgs in the form KEY=VALUE
+	BuildArgs []string
+	Platforms []string
+	// Secrets in the format of buildctl, e.g. id=npmrc,src=/home/user/.npmrc or id=token,env=TOKEN
+	Secrets []string
+	// CacheImports and CacheExports in the format of buildctl, e.g. type=registry,ref=registry.example.com/app:cache
+	CacheImports []string
+	CacheExports []string
+	// Destinations are the image names the result is exported to, the image is only built if empty
+	Destinations []string
+	Push         bool
+	// DockerConfig is the content of the Docker config.json used to authenticate to registries
+	DockerConfig []byte
+}
+
+// Build builds the Dockerfile with the Dockerfile frontend of the BuildKit daemon, the progress is written in plain text.
+// The digest of the image is returned if the image has been exported.
+func Build(ctx context.Context, options *BuildOptions, progress io.Writer) (string, error) {
+	solveOpt, err := SolveOpt(options)
+	if err != nil {
+		return "", err
+	}
+
+	c, err := client.New(ctx, options.Address)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to BuildKit daemon at '%v': %w", options.Address, err)
+	}
+	defer c.Close()
+
+	display, err := progressui.NewDisplay(progress, progressui.PlainMode)
+	if err != nil {
+		return "", fmt.Errorf("failed to create progress display: %w", err)
+	}
+
+	var response *client.SolveResponse
+	ch := make(chan *client.SolveStatus)
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		var err error
+		response, err = c.Solve(egCtx, nil, *solveOpt, ch)
+		return err
+	})
+	eg.Go(func() error {
+		_, err := display.UpdateFrom(context.Background(), ch)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return "", fmt.Errorf("BuildKit build failed: %w", err)
+	}
+
+	digest := response.ExporterResponse["containerimage.digest"]
+	log.Entry().Debugf("image digest: %s", digest)
+	return digest, nil
+}
+
+// SolveOpt translates the build options into the solve request of the Dockerfile frontend
+func SolveOpt(options *BuildOptions) (*client.SolveOpt, error) {
+	contextFS, err := fsutil.NewFS(options.ContextDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid build context '%v': %w", options.ContextDir, err)
+	}
+	dockerfileFS, err := fsutil.NewFS(filepath.Dir(options.Dockerfile))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Dockerfile directory '%v': %w", filepath.Dir(options.Dockerfile), err)
+	}
+
+	frontendAttrs := map[string]string{"filename": filepath.Base(options.Dockerfile)}
+	if len(options.Target) > 0 {
+		frontendAttrs["target"] = options.Target
+	}
+	for _, buildArg := range options.BuildArgs {
+		key, value, ok := strings.Cut(buildArg, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid build argument '%v', please use the format KEY=VALUE", buildArg)
+		}
+		frontendAttrs["build-arg:"+key] = value
+	}
+	if len(options.Platforms) > 0 {
+		frontendAttrs["platform"] = strings.Join(options.Platforms, ",")
+	}
+
+	dockerConfig := options.DockerConfig
+	if len(dockerConfig) == 0 {
+		dockerConfig = []byte(`{"auths":{}}`)
+	}
+	configFile, err := config.LoadFromReader(bytes.NewReader(dockerConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Docker config: %w", err)
+	}
+	secretStore, err := secretSources(options.Secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	solveOpt := &client.SolveOpt{
+		Frontend:      "dockerfile.v0",
+		FrontendAttrs: frontendAttrs,
+		LocalMounts:   map[string]fsutil.FS{"context": contextFS, "dockerfile": dockerfileFS},
+		Session: []session.Attachable{
+			authprovider.NewDockerAuthProvider(authprovider.DockerAuthProviderConfig{AuthConfigProvider: authprovider.LoadAuthConfig(configFile)}),
+			secretsprovider.NewSecretProvider(secretStore),
+		},
+	}
+	if solveOpt.CacheImports, err = cacheEntries(options.CacheImports); err != nil {
+		return nil, err
+	}
+	if solveOpt.CacheExports, err = cacheEntries(options.CacheExports); err != nil {
+		return nil, err
+	}
+	if len(options.Destinations) > 0 {
+		solveOpt.Exports = []client.ExportEntry{{
+			Type: client.ExporterImage,
+			Attrs: map[string]string{
+				"name": strings.Join(options.Destinations, ","),
+				"push": strconv.FormatBool(options.Push),
+			},
+		}}
+	}
+	return solveOpt, nil
+}
+
+func secretSources(buildSecrets []string) (secrets.SecretStore, error) {
+	sources := []secretsprovider.Source{}
+	for _, secret := range buildSecrets {
+		attrs, err := parseAttributes(secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid build secret '%v': %w", secret, err)
+		}
+		source := secretsprovider.Source{ID: attrs["id"], FilePath: attrs["src"], Env: attrs["env"]}
+		if len(source.FilePath) == 0 {
+			source.FilePath = attrs["source"]
+		}
+		if len(source.ID) == 0 {
+			return nil, fmt.Errorf("invalid build secret '%v': missing id", secret)
+		}
+		sources = append(sources, source)
+	}
+	return secretsprovider.NewStore(sources)
+}
+
+func cacheEntries(caches []string) ([]client.CacheOptionsEntry, error) {
+	entries := []client.CacheOptionsEntry{}
+	for _, cache := range caches {
+		attrs, err := parseAttributes(cache)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache '%v': %w", cache, err)
+		}
+		cacheType := attrs["type"]
+		if len(cacheType) == 0 {
+			return nil, fmt.Errorf("invalid cache '%v': missing type", cache)
+		}
+		delete(attrs, "type")
+		entries = append(entries, client.CacheOptionsEntry{Type: cacheType, Attrs: attrs})
+	}
+	return entries, nil
+}
+
+// parseAttributes parses comma separated attributes in the form key=value
+func parseAttributes(value string) (map[string]string, error) {
+	attrs := map[string]string{}
+	for _, field := range strings.Split(value, ",") {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("attribute '%v' is not in the form key=value", field)
+		}
+		attrs[strings.TrimSpace(key)] = value
+	}
+	return attrs, nil
+}
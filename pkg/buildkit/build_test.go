@@ -0,0 +1,82 @@
+//go:build unit
+// +build unit
+
+package buildkit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moby/buildkit/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolveOpt(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "npmrc"), []byte("registry=https://npm.example.com"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "docker"), 0o755))
+
+	t.Run("success", func(t *testing.T) {
+		solveOpt, err := SolveOpt(&BuildOptions{
+			ContextDir:   dir,
+			Dockerfile:   filepath.Join(dir, "docker", "Dockerfile.app"),
+			Target:       "runtime",
+			BuildArgs:    []string{"VERSION=1.2.3", "EMPTY="},
+			Platforms:    []string{"linux/amd64", "linux/arm64"},
+			Secrets:      []string{"id=npmrc,src=" + filepath.Join(dir, "npmrc"), "id=token,env=TOKEN"},
+			CacheImports: []string{"type=registry,ref=registry.example.com/app:cache"},
+			CacheExports: []string{"type=registry,ref=registry.example.com/app:cache,mode=max"},
+			Destinations: []string{"registry.example.com/app:1.2.3", "registry.example.com/app:latest"},
+			Push:         true,
+			DockerConfig: []byte(`{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}`),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "dockerfile.v0", solveOpt.Frontend)
+		assert.Equal(t, map[string]string{
+			"filename":          "Dockerfile.app",
+			"target":            "runtime",
+			"build-arg:VERSION": "1.2.3",
+			"build-arg:EMPTY":   "",
+			"platform":          "linux/amd64,linux/arm64",
+		}, solveOpt.FrontendAttrs)
+		assert.Contains(t, solveOpt.LocalMounts, "context")
+		assert.Contains(t, solveOpt.LocalMounts, "dockerfile")
+		assert.Len(t, solveOpt.Session, 2)
+		assert.Equal(t, []client.CacheOptionsEntry{{Type: "registry", Attrs: map[string]string{"ref": "registry.example.com/app:cache"}}}, solveOpt.CacheImports)
+		assert.Equal(t, []client.CacheOptionsEntry{{Type: "registry", Attrs: map[string]string{"ref": "registry.example.com/app:cache", "mode": "max"}}}, solveOpt.CacheExports)
+		assert.Equal(t, []client.ExportEntry{{Type: client.ExporterImage, Attrs: map[string]string{
+			"name": "registry.example.com/app:1.2.3,registry.example.com/app:latest",
+			"push": "true",
+		}}}, solveOpt.Exports)
+	})
+
+	t.Run("build without export", func(t *testing.T) {
+		solveOpt, err := SolveOpt(&BuildOptions{ContextDir: dir, Dockerfile: filepath.Join(dir, "Dockerfile")})
+
+		require.NoError(t, err)
+		assert.Empty(t, solveOpt.Exports)
+		assert.Equal(t, map[string]string{"filename": "Dockerfile"}, solveOpt.FrontendAttrs)
+	})
+
+	t.Run("invalid build argument", func(t *testing.T) {
+		_, err := SolveOpt(&BuildOptions{ContextDir: dir, Dockerfile: filepath.Join(dir, "Dockerfile"), BuildArgs: []string{"VERSION"}})
+
+		assert.EqualError(t, err, "invalid build argument 'VERSION', please use the format KEY=VALUE")
+	})
+
+	t.Run("invalid secret", func(t *testing.T) {
+		_, err := SolveOpt(&BuildOptions{ContextDir: dir, Dockerfile: filepath.Join(dir, "Dockerfile"), Secrets: []string{"src=/tmp/npmrc"}})
+
+		assert.EqualError(t, err, "invalid build secret 'src=/tmp/npmrc': missing id")
+	})
+
+	t.Run("invalid cache", func(t *testing.T) {
+		_, err := SolveOpt(&BuildOptions{ContextDir: dir, Dockerfile: filepath.Join(dir, "Dockerfile"), CacheImports: []string{"registry.example.com/app:cache"}})
+
+		assert.EqualError(t, err, "invalid cache 'registry.example.com/app:cache': attribute 'registry.example.com/app:cache' is not in the form key=value")
+	})
+}
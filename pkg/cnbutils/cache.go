@@ -0,0 +1,123 @@
+package cnbutils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+const (
+	cacheKeyHashLength   = 16
+	cacheKeyPrefixLength = 100
+)
+
+var invalidCacheKeyChars = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// CacheKey derives the key of the build cache of an image from the image name, the project descriptor and the buildpack order.
+// Hence a cache is not reused once the buildpacks or the build configuration of the image change.
+// The key is a valid image tag and directory name of the form <image name>-<hash>.
+func CacheKey(imageName string, projectDescriptor []byte, order Order) (string, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(order); err != nil {
+		return "", fmt.Errorf("failed to encode buildpack order: %w", err)
+	}
+	hash := sha256.New()
+	hash.Write(buf.Bytes())
+	hash.Write(projectDescriptor)
+
+	prefix := strings.Trim(invalidCacheKeyChars.ReplaceAllString(strings.ToLower(imageName), "-"), "-.")
+	if len(prefix) > cacheKeyPrefixLength {
+		prefix = prefix[:cacheKeyPrefixLength]
+	}
+	if len(prefix) == 0 {
+		prefix = "cache"
+	}
+	return fmt.Sprintf("%s-%s", prefix, hex.EncodeToString(hash.Sum(nil))[:cacheKeyHashLength]), nil
+}
+
+// isStaleCacheKey checks whether the candidate is a cache key of the same image as the key, but with another hash
+func isStaleCacheKey(candidate, key string) bool {
+	prefix := key[:strings.LastIndex(key, "-")+1]
+	hash, found := strings.CutPrefix(candidate, prefix)
+	if !found || candidate == key || len(hash) != cacheKeyHashLength {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
+
+// CacheImageReference returns the reference of the cache image tagged with the cache key
+func CacheImageReference(cacheImage, key string) (string, error) {
+	repository, err := name.NewRepository(cacheImage)
+	if err != nil {
+		return "", fmt.Errorf("invalid cache image '%v', the cache image must not contain a tag or digest: %w", cacheImage, err)
+	}
+	return repository.Tag(key).String(), nil
+}
+
+// PruneCacheImages deletes the cache images of the same image with another cache key from the repository of the cache image
+func PruneCacheImages(cacheImage, key string, opts ...remote.Option) ([]string, error) {
+	repository, err := name.NewRepository(cacheImage)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cache image '%v': %w", cacheImage, err)
+	}
+	tags, err := remote.List(repository, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of cache image '%v': %w", cacheImage, err)
+	}
+	current, err := remote.Head(repository.Tag(key), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache image '%v': %w", repository.Tag(key), err)
+	}
+
+	pruned := []string{}
+	for _, tag := range tags {
+		if !isStaleCacheKey(tag, key) {
+			continue
+		}
+		descriptor, err := remote.Head(repository.Tag(tag), opts...)
+		if err != nil {
+			return pruned, fmt.Errorf("failed to read cache image '%v': %w", repository.Tag(tag), err)
+		}
+		if descriptor.Digest == current.Digest {
+			continue
+		}
+		if err := remote.Delete(repository.Digest(descriptor.Digest.String()), opts...); err != nil {
+			return pruned, fmt.Errorf("failed to delete cache image '%v': %w", repository.Tag(tag), err)
+		}
+		log.Entry().Infof("Stale cache image '%v' deleted", repository.Tag(tag))
+		pruned = append(pruned, repository.Tag(tag).String())
+	}
+	return pruned, nil
+}
+
+// PruneCacheDirectories deletes the cache directories of the same image with another cache key from the cache directory
+func PruneCacheDirectories(cacheDir, key string, utils piperutils.FileUtils) ([]string, error) {
+	candidates, err := utils.Glob(filepath.Join(cacheDir, "*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache directories in '%v': %w", cacheDir, err)
+	}
+	pruned := []string{}
+	for _, candidate := range candidates {
+		if !isStaleCacheKey(filepath.Base(candidate), key) {
+			continue
+		}
+		if err := utils.RemoveAll(candidate); err != nil {
+			return pruned, fmt.Errorf("failed to delete cache directory '%v': %w", candidate, err)
+		}
+		log.Entry().Infof("Stale cache directory '%v' deleted", candidate)
+		pruned = append(pruned, candidate)
+	}
+	return pruned, nil
+}
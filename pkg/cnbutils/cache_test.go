@@ -0,0 +1,109 @@
+//go:build unit
+// +build unit
+
+package cnbutils_test
+
+import (
+	"io"
+	stdlog "log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/cnbutils"
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func TestCacheKey(t *testing.T) {
+	t.Parallel()
+	order := cnbutils.Order{Order: []cnbutils.OrderEntry{{Group: []cnbutils.BuildPackMetadata{{ID: "paketo-buildpacks/java", Version: "1.0.0"}}}}}
+	otherOrder := cnbutils.Order{Order: []cnbutils.OrderEntry{{Group: []cnbutils.BuildPackMetadata{{ID: "paketo-buildpacks/java", Version: "2.0.0"}}}}}
+
+	key, err := cnbutils.CacheKey("my-app", []byte("[project]"), order)
+	require.NoError(t, err)
+	assert.Regexp(t, "^my-app-[0-9a-f]{16}$", key)
+
+	sameKey, _ := cnbutils.CacheKey("my-app", []byte("[project]"), order)
+	assert.Equal(t, key, sameKey)
+	otherOrderKey, _ := cnbutils.CacheKey("my-app", []byte("[project]"), otherOrder)
+	assert.NotEqual(t, key, otherOrderKey)
+	otherDescriptorKey, _ := cnbutils.CacheKey("my-app", []byte("[project]\nid = 'x'"), order)
+	assert.NotEqual(t, key, otherDescriptorKey)
+
+	key, _ = cnbutils.CacheKey("Team/My_App", nil, order)
+	assert.Regexp(t, "^team-my_app-[0-9a-f]{16}$", key)
+	key, _ = cnbutils.CacheKey(strings.Repeat("a", 200), nil, order)
+	assert.Len(t, key, 117)
+}
+
+func TestCacheImageReference(t *testing.T) {
+	t.Parallel()
+	ref, err := cnbutils.CacheImageReference("my.registry.com/my-app-cache", "my-app-0123456789abcdef")
+	assert.NoError(t, err)
+	assert.Equal(t, "my.registry.com/my-app-cache:my-app-0123456789abcdef", ref)
+
+	_, err = cnbutils.CacheImageReference("my.registry.com/my-app-cache:latest", "my-app-0123456789abcdef")
+	assert.ErrorContains(t, err, "invalid cache image 'my.registry.com/my-app-cache:latest', the cache image must not contain a tag or digest")
+}
+
+func TestPruneCacheImages(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(registry.New(registry.Logger(stdlog.New(io.Discard, "", 0))))
+	t.Cleanup(server.Close)
+	cacheImage := strings.TrimPrefix(server.URL, "http://") + "/cache"
+
+	push := func(tag string) string {
+		img, err := random.Image(128, 1)
+		require.NoError(t, err)
+		ref, _ := name.ParseReference(cacheImage + ":" + tag)
+		require.NoError(t, remote.Write(ref, img))
+		digest, _ := img.Digest()
+		return cacheImage + "@" + digest.String()
+	}
+	push("my-app-0123456789abcdef")
+	stale := push("my-app-fedcba9876543210")
+	otherImage := push("my-app-backend-fedcba9876543210")
+	noKey := push("latest")
+
+	pruned, err := cnbutils.PruneCacheImages(cacheImage, "my-app-0123456789abcdef")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{cacheImage + ":my-app-fedcba9876543210"}, pruned)
+	for image, exists := range map[string]bool{stale: false, otherImage: true, noKey: true} {
+		ref, _ := name.ParseReference(image)
+		_, err := remote.Head(ref)
+		assert.Equal(t, exists, err == nil, image)
+	}
+
+	_, err = cnbutils.PruneCacheImages(cacheImage, "my-app-aaaaaaaaaaaaaaaa")
+	assert.ErrorContains(t, err, "failed to read cache image")
+}
+
+func TestPruneCacheDirectories(t *testing.T) {
+	t.Parallel()
+	files := &mock.FilesMock{}
+	for _, dir := range []string{"my-app-0123456789abcdef", "my-app-fedcba9876543210", "my-app-backend-fedcba9876543210", "my-app-other"} {
+		files.AddDir("cache/" + dir)
+	}
+
+	pruned, err := cnbutils.PruneCacheDirectories("cache", "my-app-0123456789abcdef", files)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cache/my-app-fedcba9876543210"}, pruned)
+	assert.True(t, dirExists(files, "cache/my-app-0123456789abcdef"))
+	assert.False(t, dirExists(files, "cache/my-app-fedcba9876543210"))
+	assert.True(t, dirExists(files, "cache/my-app-backend-fedcba9876543210"))
+	assert.True(t, dirExists(files, "cache/my-app-other"))
+}
+
+func dirExists(files *mock.FilesMock, dir string) bool {
+	exists, _ := files.DirExists(dir)
+	return exists
+}
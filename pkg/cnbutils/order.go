@@ -35,12 +35,13 @@ func (o Order) Save(path string) error {
 	return nil
 }
 
-func loadExistingOrder(utils BuildUtils) (Order, error) {
+// LoadOrder reads the buildpack order from the order.toml file at the path
+func LoadOrder(path string, utils BuildUtils) (Order, error) {
 	order := Order{
 		Utils: utils,
 	}
 
-	orderReader, err := utils.Open(DefaultOrderPath)
+	orderReader, err := utils.Open(path)
 	if err != nil {
 		return Order{}, err
 	}
@@ -76,7 +77,7 @@ func CreateOrder(bpacks, preBpacks, postBpacks []string, dockerCreds string, uti
 	var order Order
 	var err error
 	if len(bpacks) == 0 {
-		order, err = loadExistingOrder(utils)
+		order, err = LoadOrder(DefaultOrderPath, utils)
 		if err != nil {
 			return Order{}, err
 		}
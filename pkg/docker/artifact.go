@@ -0,0 +1,211 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+const (
+	// DefaultArtifactType is the artifact type of artifacts without a specific type, as used by oras
+	DefaultArtifactType = "application/vnd.unknown.artifact.v1"
+	// DefaultArtifactFileMediaType is the media type of files without a specific media type
+	DefaultArtifactFileMediaType = "application/octet-stream"
+	// TitleAnnotation contains the file name of a layer of an artifact
+	TitleAnnotation = "org.opencontainers.image.title"
+)
+
+// ArtifactFile is a file which is stored as layer of an OCI artifact
+type ArtifactFile struct {
+	Path      string
+	MediaType string
+}
+
+// Artifact describes the content of an OCI artifact
+type Artifact struct {
+	ArtifactType string
+	Files        []ArtifactFile
+	Annotations  map[string]string
+	// Subject is the image the artifact refers to, e.g. an SBOM referring to the image it describes
+	Subject string
+}
+
+// PushArtifact pushes the files as OCI artifact to the target reference. Without target, the artifact is pushed
+// by digest into the repository of the subject. The digest reference of the pushed artifact is returned.
+func PushArtifact(target string, artifact Artifact, utils piperutils.FileUtils, opts ...remote.Option) (string, error) {
+	if len(artifact.Files) == 0 {
+		return "", errors.New("no files provided for the artifact")
+	}
+	artifactType := artifact.ArtifactType
+	if len(artifactType) == 0 {
+		artifactType = DefaultArtifactType
+	}
+	img := mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), types.MediaType(artifactType))
+
+	titles := map[string]bool{}
+	for _, file := range artifact.Files {
+		title := artifactFileTitle(file.Path)
+		if titles[title] {
+			return "", fmt.Errorf("artifact contains the file '%v' more than once", title)
+		}
+		titles[title] = true
+		content, err := utils.FileRead(file.Path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read file '%v': %w", file.Path, err)
+		}
+		mediaType := file.MediaType
+		if len(mediaType) == 0 {
+			mediaType = DefaultArtifactFileMediaType
+		}
+		img, err = mutate.Append(img, mutate.Addendum{
+			Layer:       static.NewLayer(content, types.MediaType(mediaType)),
+			Annotations: map[string]string{TitleAnnotation: title},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to add file '%v' to artifact: %w", file.Path, err)
+		}
+	}
+	if len(artifact.Annotations) > 0 {
+		img = mutate.Annotations(img, artifact.Annotations).(v1.Image)
+	}
+
+	var repository name.Repository
+	if len(artifact.Subject) > 0 {
+		subject, err := resolveDigest(artifact.Subject, opts...)
+		if err != nil {
+			return "", err
+		}
+		descriptor, err := remote.Head(subject, opts...)
+		if err != nil {
+			return "", fmt.Errorf("failed to read subject '%v': %w", subject, err)
+		}
+		img = mutate.Subject(img, *descriptor).(v1.Image)
+		repository = subject.Context()
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate digest of artifact: %w", err)
+	}
+	var ref name.Reference
+	if len(target) > 0 {
+		if ref, err = name.ParseReference(target); err != nil {
+			return "", fmt.Errorf("invalid artifact reference '%v': %w", target, err)
+		}
+		repository = ref.Context()
+	} else if len(artifact.Subject) > 0 {
+		ref = repository.Digest(digest.String())
+	} else {
+		return "", errors.New("either a target reference or a subject is required for pushing an artifact")
+	}
+
+	if err := remote.Write(ref, img, opts...); err != nil {
+		return "", fmt.Errorf("failed to push artifact '%v': %w", ref, err)
+	}
+	log.Entry().Infof("Artifact with %v file(s) pushed to '%v'", len(artifact.Files), ref)
+	return repository.Digest(digest.String()).String(), nil
+}
+
+// PullArtifact writes the files of the artifact into the target directory and returns their paths
+func PullArtifact(source, targetDir string, utils piperutils.FileUtils, opts ...remote.Option) ([]string, error) {
+	ref, err := name.ParseReference(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact reference '%v': %w", source, err)
+	}
+	img, err := remote.Image(ref, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact '%v': %w", source, err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest of artifact '%v': %w", source, err)
+	}
+
+	files := []string{}
+	for _, descriptor := range manifest.Layers {
+		title := descriptor.Annotations[TitleAnnotation]
+		if len(title) == 0 {
+			log.Entry().Debugf("Skipping layer %v of artifact '%v' without title", descriptor.Digest, source)
+			continue
+		}
+		cleaned := path.Clean(title)
+		if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return nil, fmt.Errorf("artifact '%v' contains the file '%v' outside of the target directory", source, title)
+		}
+		layer, err := img.LayerByDigest(descriptor.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file '%v' of artifact '%v': %w", title, source, err)
+		}
+		content, err := readLayer(layer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file '%v' of artifact '%v': %w", title, source, err)
+		}
+		file := filepath.Join(targetDir, filepath.FromSlash(cleaned))
+		if err := utils.MkdirAll(filepath.Dir(file), 0777); err != nil {
+			return nil, fmt.Errorf("failed to create directory for '%v': %w", file, err)
+		}
+		if err := utils.FileWrite(file, content, 0666); err != nil {
+			return nil, fmt.Errorf("failed to write file '%v': %w", file, err)
+		}
+		files = append(files, file)
+	}
+	log.Entry().Infof("%v file(s) of artifact '%v' written to '%v'", len(files), source, targetDir)
+	return files, nil
+}
+
+// FindReferrers returns the digest references of the artifacts of the artifact type which refer to the subject.
+// Registries without support for the referrers API are handled via the referrers tag schema.
+func FindReferrers(subject, artifactType string, opts ...remote.Option) ([]string, error) {
+	digest, err := resolveDigest(subject, opts...)
+	if err != nil {
+		return nil, err
+	}
+	index, err := remote.Referrers(digest, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read referrers of '%v': %w", digest, err)
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read referrers of '%v': %w", digest, err)
+	}
+	referrers := []string{}
+	for _, descriptor := range manifest.Manifests {
+		if len(artifactType) == 0 || descriptor.ArtifactType == artifactType {
+			referrers = append(referrers, digest.Context().Digest(descriptor.Digest.String()).String())
+		}
+	}
+	return referrers, nil
+}
+
+// artifactFileTitle returns the name of a file within an artifact. Relative paths are kept, while absolute paths
+// and paths outside of the working directory are reduced to the file name, since they cannot be pulled again.
+func artifactFileTitle(file string) string {
+	if filepath.IsLocal(file) {
+		return filepath.ToSlash(filepath.Clean(file))
+	}
+	return filepath.Base(file)
+}
+
+func readLayer(layer v1.Layer) ([]byte, error) {
+	// artifact layers are not compressed, hence the compressed content is the file
+	reader, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
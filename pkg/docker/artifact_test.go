@@ -0,0 +1,126 @@
+//go:build unit
+// +build unit
+
+package docker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func TestPushAndPullArtifact(t *testing.T) {
+	t.Parallel()
+	host := newTestRegistry(t)
+	files := &mock.FilesMock{}
+	files.AddFile("mta_archives/app.mtar", []byte("mtar"))
+	files.AddFile("reports/junit.xml", []byte("<testsuites/>"))
+
+	t.Run("tagged artifact", func(t *testing.T) {
+		t.Parallel()
+		pushed, err := PushArtifact(host+"/app-artifacts:1.0.0", Artifact{
+			ArtifactType: "application/vnd.sap.mta.v1",
+			Files:        []ArtifactFile{{Path: "mta_archives/app.mtar", MediaType: "application/zip"}, {Path: "reports/junit.xml"}},
+			Annotations:  map[string]string{"org.opencontainers.image.version": "1.0.0"},
+		}, files)
+		require.NoError(t, err)
+
+		ref, _ := name.ParseReference(host + "/app-artifacts:1.0.0")
+		img, err := remote.Image(ref)
+		require.NoError(t, err)
+		digest, _ := img.Digest()
+		assert.Equal(t, host+"/app-artifacts@"+digest.String(), pushed)
+		manifest, _ := img.Manifest()
+		assert.Equal(t, types.MediaType("application/vnd.sap.mta.v1"), manifest.Config.MediaType)
+		assert.Equal(t, "1.0.0", manifest.Annotations["org.opencontainers.image.version"])
+		require.Len(t, manifest.Layers, 2)
+		assert.Equal(t, types.MediaType("application/zip"), manifest.Layers[0].MediaType)
+		assert.Equal(t, "mta_archives/app.mtar", manifest.Layers[0].Annotations[TitleAnnotation])
+		assert.Equal(t, types.MediaType(DefaultArtifactFileMediaType), manifest.Layers[1].MediaType)
+
+		target := &mock.FilesMock{}
+		pulled, err := PullArtifact(pushed, "download", target)
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join("download", "mta_archives", "app.mtar"), filepath.Join("download", "reports", "junit.xml")}, pulled)
+		content, _ := target.FileRead(filepath.Join("download", "mta_archives", "app.mtar"))
+		assert.Equal(t, []byte("mtar"), content)
+	})
+
+	t.Run("files outside of the working directory", func(t *testing.T) {
+		t.Parallel()
+		outside := &mock.FilesMock{}
+		outside.AddFile("/workspace/target/app.jar", []byte("jar"))
+		outside.AddFile("../sbom.json", []byte("{}"))
+
+		pushed, err := PushArtifact(host+"/outside:1.0.0", Artifact{
+			Files: []ArtifactFile{{Path: "/workspace/target/app.jar"}, {Path: "../sbom.json"}},
+		}, outside)
+		require.NoError(t, err)
+
+		target := &mock.FilesMock{}
+		pulled, err := PullArtifact(pushed, "download", target)
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join("download", "app.jar"), filepath.Join("download", "sbom.json")}, pulled)
+		content, _ := target.FileRead(filepath.Join("download", "app.jar"))
+		assert.Equal(t, []byte("jar"), content)
+	})
+
+	t.Run("referrer of image", func(t *testing.T) {
+		t.Parallel()
+		imageDigest := pushRandomImage(t, host+"/app:1.0.0")
+
+		sbom, err := PushArtifact("", Artifact{
+			ArtifactType: "application/vnd.cyclonedx+xml",
+			Files:        []ArtifactFile{{Path: "reports/junit.xml"}},
+			Subject:      host + "/app:1.0.0",
+		}, files)
+		require.NoError(t, err)
+		_, err = PushArtifact("", Artifact{Files: []ArtifactFile{{Path: "mta_archives/app.mtar"}}, Subject: imageDigest}, files)
+		require.NoError(t, err)
+
+		referrers, err := FindReferrers(host+"/app:1.0.0", "application/vnd.cyclonedx+xml")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{sbom}, referrers)
+		referrers, err = FindReferrers(imageDigest, "")
+		assert.NoError(t, err)
+		assert.Len(t, referrers, 2)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := PushArtifact(host+"/app-artifacts:1.0.0", Artifact{}, files)
+		assert.EqualError(t, err, "no files provided for the artifact")
+
+		_, err = PushArtifact("", Artifact{Files: []ArtifactFile{{Path: "reports/junit.xml"}}}, files)
+		assert.EqualError(t, err, "either a target reference or a subject is required for pushing an artifact")
+
+		_, err = PushArtifact(host+"/app-artifacts:1.0.0", Artifact{Files: []ArtifactFile{{Path: "missing.txt"}}}, files)
+		assert.ErrorContains(t, err, "failed to read file 'missing.txt'")
+
+		duplicates := &mock.FilesMock{}
+		duplicates.AddFile("/build/a/app.jar", []byte("a"))
+		duplicates.AddFile("/build/b/app.jar", []byte("b"))
+		_, err = PushArtifact(host+"/app-artifacts:1.0.0", Artifact{Files: []ArtifactFile{{Path: "/build/a/app.jar"}, {Path: "/build/b/app.jar"}}}, duplicates)
+		assert.EqualError(t, err, "artifact contains the file 'app.jar' more than once")
+
+		ref, _ := name.ParseReference(host + "/unsafe:1.0.0")
+		unsafe, err := mutate.Append(empty.Image, mutate.Addendum{
+			Layer:       static.NewLayer([]byte("content"), DefaultArtifactFileMediaType),
+			Annotations: map[string]string{TitleAnnotation: "../escape.txt"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref, unsafe))
+		_, err = PullArtifact(ref.String(), "download", &mock.FilesMock{})
+		assert.EqualError(t, err, "artifact '"+ref.String()+"' contains the file '../escape.txt' outside of the target directory")
+	})
+}
@@ -0,0 +1,195 @@
+package docker
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// Attestations are stored in the format of cosign: an image tagged with the digest of the attested image
+// which contains one layer per attestation. Each layer is a DSSE envelope (https://github.com/secure-systems-lab/dsse)
+// of an in-toto statement.
+const (
+	// DSSEMediaType is the media type of the attestation layers
+	DSSEMediaType = "application/vnd.dsse.envelope.v1+json"
+	// InTotoPayloadType is the payload type of DSSE envelopes containing in-toto statements
+	InTotoPayloadType = "application/vnd.in-toto+json"
+	// PredicateTypeAnnotation is the layer annotation containing the predicate type of the attestation
+	PredicateTypeAnnotation = "predicateType"
+)
+
+// Envelope is a DSSE envelope
+type Envelope struct {
+	PayloadType string              `json:"payloadType"`
+	Payload     string              `json:"payload"`
+	Signatures  []EnvelopeSignature `json:"signatures"`
+}
+
+// EnvelopeSignature is a signature of a DSSE envelope
+type EnvelopeSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// AttestationTag returns the tag of the image containing the attestations of the image with the given digest
+func AttestationTag(digest name.Digest) name.Tag {
+	return digest.Context().Tag(strings.ReplaceAll(digest.DigestStr(), ":", "-") + ".att")
+}
+
+// AttachAttestation signs the in-toto statement and pushes it as attestation next to the image, existing attestations are kept.
+// Tags are resolved to the digest of the image, the digest reference of the attested image is returned.
+func AttachAttestation(image string, statement []byte, predicateType string, key crypto.Signer, opts ...remote.Option) (string, error) {
+	digest, err := resolveDigest(image, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := sign(key, preAuthEncoding(InTotoPayloadType, statement))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign attestation of image '%v': %w", digest, err)
+	}
+	envelope, err := json.Marshal(Envelope{
+		PayloadType: InTotoPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(statement),
+		Signatures:  []EnvelopeSignature{{Sig: base64.StdEncoding.EncodeToString(signature)}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create attestation envelope: %w", err)
+	}
+
+	attestationTag := AttestationTag(digest)
+	attestations, err := readSignatures(attestationTag, opts...)
+	if err != nil {
+		return "", err
+	}
+	if attestations == nil {
+		attestations = mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), types.OCIConfigJSON)
+	} else if alreadyAttested(attestations, statement) {
+		log.Entry().Infof("Image '%v' already contains the attestation", digest)
+		return digest.String(), nil
+	}
+
+	attestations, err = mutate.Append(attestations, mutate.Addendum{
+		Layer:       static.NewLayer(envelope, DSSEMediaType),
+		Annotations: map[string]string{PredicateTypeAnnotation: predicateType},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to add attestation: %w", err)
+	}
+	if err := remote.Write(attestationTag, attestations, opts...); err != nil {
+		return "", fmt.Errorf("failed to push attestation '%v': %w", attestationTag, err)
+	}
+	log.Entry().Infof("Attestation of image '%v' pushed to '%v'", digest, attestationTag)
+	return digest.String(), nil
+}
+
+// VerifyAttestation returns the statement of the first attestation of the predicate type which can be verified with one of the public keys
+func VerifyAttestation(image, predicateType string, publicKeys []crypto.PublicKey, opts ...remote.Option) ([]byte, error) {
+	if len(publicKeys) == 0 {
+		return nil, errors.New("no public keys provided")
+	}
+	digest, err := resolveDigest(image, opts...)
+	if err != nil {
+		return nil, err
+	}
+	attestations, err := readSignatures(AttestationTag(digest), opts...)
+	if err != nil {
+		return nil, err
+	}
+	if attestations == nil {
+		return nil, fmt.Errorf("no attestations found for image '%v'", digest)
+	}
+
+	envelopes, err := attestationEnvelopes(attestations, predicateType)
+	if err != nil {
+		return nil, err
+	}
+	for _, envelope := range envelopes {
+		statement, err := base64.StdEncoding.DecodeString(envelope.Payload)
+		if err != nil {
+			log.Entry().Debugf("skipping attestation with invalid payload: %v", err)
+			continue
+		}
+		encoded := preAuthEncoding(envelope.PayloadType, statement)
+		for _, envelopeSignature := range envelope.Signatures {
+			signature, err := base64.StdEncoding.DecodeString(envelopeSignature.Sig)
+			if err != nil {
+				continue
+			}
+			for _, publicKey := range publicKeys {
+				if verify(publicKey, encoded, signature) == nil {
+					return statement, nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("no valid attestation of type '%v' found for image '%v'", predicateType, digest)
+}
+
+// attestationEnvelopes returns the envelopes of the attestations with the given predicate type, all envelopes if no type is given
+func attestationEnvelopes(attestations v1.Image, predicateType string) ([]Envelope, error) {
+	manifest, err := attestations.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation manifest: %w", err)
+	}
+	envelopes := []Envelope{}
+	for _, descriptor := range manifest.Layers {
+		if descriptor.MediaType != DSSEMediaType || (len(predicateType) > 0 && descriptor.Annotations[PredicateTypeAnnotation] != predicateType) {
+			continue
+		}
+		layer, err := attestations.LayerByDigest(descriptor.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attestation layer '%v': %w", descriptor.Digest, err)
+		}
+		reader, err := layer.Compressed()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attestation layer '%v': %w", descriptor.Digest, err)
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attestation layer '%v': %w", descriptor.Digest, err)
+		}
+		envelope := Envelope{}
+		if err := json.Unmarshal(content, &envelope); err != nil || envelope.PayloadType != InTotoPayloadType {
+			log.Entry().Debugf("skipping invalid attestation layer '%v'", descriptor.Digest)
+			continue
+		}
+		envelopes = append(envelopes, envelope)
+	}
+	return envelopes, nil
+}
+
+// preAuthEncoding returns the content which is signed for a DSSE envelope
+func preAuthEncoding(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// alreadyAttested checks whether the attestations contain an envelope of the statement
+func alreadyAttested(attestations v1.Image, statement []byte) bool {
+	envelopes, err := attestationEnvelopes(attestations, "")
+	if err != nil {
+		return false
+	}
+	encoded := base64.StdEncoding.EncodeToString(statement)
+	for _, envelope := range envelopes {
+		if envelope.Payload == encoded {
+			return true
+		}
+	}
+	return false
+}
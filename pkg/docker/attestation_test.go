@@ -0,0 +1,90 @@
+//go:build unit
+// +build unit
+
+package docker
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachAndVerifyAttestation(t *testing.T) {
+	t.Parallel()
+	host := newTestRegistry(t)
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	statement := []byte(`{"_type":"https://in-toto.io/Statement/v1","predicateType":"https://slsa.dev/provenance/v1"}`)
+
+	t.Run("attach to tag and verify digest", func(t *testing.T) {
+		t.Parallel()
+		digest := pushRandomImage(t, host+"/app:1.0.0")
+
+		attested, err := AttachAttestation(host+"/app:1.0.0", statement, "https://slsa.dev/provenance/v1", key)
+		assert.NoError(t, err)
+		assert.Equal(t, digest, attested)
+
+		verified, err := VerifyAttestation(digest, "https://slsa.dev/provenance/v1", []crypto.PublicKey{otherKey.Public(), key.Public()})
+		assert.NoError(t, err)
+		assert.Equal(t, statement, verified)
+
+		_, err = VerifyAttestation(digest, "https://slsa.dev/provenance/v1", []crypto.PublicKey{otherKey.Public()})
+		assert.EqualError(t, err, fmt.Sprintf("no valid attestation of type 'https://slsa.dev/provenance/v1' found for image '%v'", digest))
+		_, err = VerifyAttestation(digest, "https://spdx.dev/Document", []crypto.PublicKey{key.Public()})
+		assert.EqualError(t, err, fmt.Sprintf("no valid attestation of type 'https://spdx.dev/Document' found for image '%v'", digest))
+	})
+
+	t.Run("attestation format", func(t *testing.T) {
+		t.Parallel()
+		digest := pushRandomImage(t, host+"/format:1.0.0")
+		for i := 0; i < 2; i++ {
+			_, err := AttachAttestation(digest, statement, "https://slsa.dev/provenance/v1", key)
+			require.NoError(t, err)
+		}
+		_, err := AttachAttestation(digest, []byte(`{"other":"statement"}`), "https://spdx.dev/Document", key)
+		require.NoError(t, err)
+
+		digestRef, _ := name.NewDigest(digest)
+		assert.Equal(t, host+"/format:sha256-"+strings.TrimPrefix(digestRef.DigestStr(), "sha256:")+".att", AttestationTag(digestRef).String())
+		attestations, err := remote.Image(AttestationTag(digestRef))
+		require.NoError(t, err)
+		manifest, _ := attestations.Manifest()
+		require.Len(t, manifest.Layers, 2)
+		assert.Equal(t, DSSEMediaType, string(manifest.Layers[0].MediaType))
+		assert.Equal(t, "https://slsa.dev/provenance/v1", manifest.Layers[0].Annotations[PredicateTypeAnnotation])
+
+		envelopes, err := attestationEnvelopes(attestations, "https://slsa.dev/provenance/v1")
+		require.NoError(t, err)
+		require.Len(t, envelopes, 1)
+		assert.Equal(t, InTotoPayloadType, envelopes[0].PayloadType)
+		assert.Equal(t, base64.StdEncoding.EncodeToString(statement), envelopes[0].Payload)
+		require.Len(t, envelopes[0].Signatures, 1)
+		signature, _ := base64.StdEncoding.DecodeString(envelopes[0].Signatures[0].Sig)
+		assert.NoError(t, verify(key.Public(), []byte("DSSEv1 28 application/vnd.in-toto+json "+fmt.Sprint(len(statement))+" "+string(statement)), signature))
+	})
+
+	t.Run("image without attestations", func(t *testing.T) {
+		t.Parallel()
+		digest := pushRandomImage(t, host+"/plain:1.0.0")
+
+		_, err := VerifyAttestation(digest, "https://slsa.dev/provenance/v1", []crypto.PublicKey{key.Public()})
+
+		assert.EqualError(t, err, fmt.Sprintf("no attestations found for image '%v'", digest))
+	})
+
+	t.Run("unknown image", func(t *testing.T) {
+		t.Parallel()
+		_, err := AttachAttestation(host+"/unknown:1.0.0", statement, "https://slsa.dev/provenance/v1", key)
+		assert.ErrorContains(t, err, "failed to resolve digest of image")
+	})
+}
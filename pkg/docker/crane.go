@@ -4,13 +4,16 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -183,6 +186,79 @@ func (c *craneUtilsBundle) LoadImage(ctx context.Context, src string) (v1.Image,
 	return img, err
 }
 
+// PullImage reads the image from the registry, for a multi-platform image the image of the platform is returned
+func (c *craneUtilsBundle) PullImage(ctx context.Context, src, platform string) (v1.Image, error) {
+	p, err := parsePlatform(platform)
+	if err != nil {
+		return nil, err
+	}
+	var img v1.Image
+	err = c.retryOperation(ctx, "PullImage", func() error {
+		var pullErr error
+		img, pullErr = crane.Pull(src, c.getCraneOptions(ctx, p)...)
+		return pullErr
+	})
+	return img, err
+}
+
+// PushImageIndex pushes the index including all images it references. The images are pushed before the index,
+// thus the tag is only updated once all platforms are available.
+func (c *craneUtilsBundle) PushImageIndex(ctx context.Context, index v1.ImageIndex, dest string) error {
+	o := crane.GetOptions(c.getCraneOptions(ctx, nil)...)
+	ref, err := name.ParseReference(dest, o.Name...)
+	if err != nil {
+		return fmt.Errorf("invalid image reference %q: %w", dest, err)
+	}
+	return c.retryOperation(ctx, "PushImageIndex", func() error {
+		return remote.WriteIndex(ref, index, o.Remote...)
+	})
+}
+
+// GetImageIndex reads the index of a multi-platform image from the registry
+func (c *craneUtilsBundle) GetImageIndex(ctx context.Context, src string) (v1.ImageIndex, error) {
+	o := crane.GetOptions(c.getCraneOptions(ctx, nil)...)
+	ref, err := name.ParseReference(src, o.Name...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image reference %q: %w", src, err)
+	}
+	var index v1.ImageIndex
+	err = c.retryOperation(ctx, "GetImageIndex", func() error {
+		var getErr error
+		index, getErr = remote.Index(ref, o.Remote...)
+		return getErr
+	})
+	return index, err
+}
+
+// GetImageDigest resolves the digest of the image or image index the reference points to
+func (c *craneUtilsBundle) GetImageDigest(ctx context.Context, src string) (string, error) {
+	var digest string
+	err := c.retryOperation(ctx, "GetImageDigest", func() error {
+		var digestErr error
+		digest, digestErr = crane.Digest(src, c.getCraneOptions(ctx, nil)...)
+		return digestErr
+	})
+	return digest, err
+}
+
+// ListTags lists the tags of the repository
+func (c *craneUtilsBundle) ListTags(ctx context.Context, repository string) ([]string, error) {
+	var tags []string
+	err := c.retryOperation(ctx, "ListTags", func() error {
+		var listErr error
+		tags, listErr = crane.ListTags(repository, c.getCraneOptions(ctx, nil)...)
+		return listErr
+	})
+	return tags, err
+}
+
+// DeleteImage deletes the manifest the reference points to. Registries remove all tags of the manifest.
+func (c *craneUtilsBundle) DeleteImage(ctx context.Context, src string) error {
+	return c.retryOperation(ctx, "DeleteImage", func() error {
+		return crane.Delete(src, c.getCraneOptions(ctx, nil)...)
+	})
+}
+
 // parsePlatform is a wrapper for v1.ParsePlatform. It is necessary because
 // v1.ParsePlatform returns an empty struct when the platform is equal to an empty string,
 // whereas we expect 'nil'
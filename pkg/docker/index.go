@@ -0,0 +1,94 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// PlatformImage is the image of one platform of a multi-platform image
+type PlatformImage struct {
+	Platform v1.Platform
+	Image    v1.Image
+}
+
+// NewImageIndex assembles a multi-platform image from the images of the individual platforms.
+// An OCI image index is created, a Docker manifest list if any of the images is a Docker image.
+func NewImageIndex(images []PlatformImage) (v1.ImageIndex, error) {
+	if len(images) == 0 {
+		return nil, errors.New("no images provided for the image index")
+	}
+	mediaType := types.OCIImageIndex
+	addenda := []mutate.IndexAddendum{}
+	platforms := map[string]bool{}
+	for _, platformImage := range images {
+		platform := platformImage.Platform
+		if platforms[platform.String()] {
+			return nil, fmt.Errorf("platform %v is provided more than once", platform.String())
+		}
+		platforms[platform.String()] = true
+
+		config, err := platformImage.Image.ConfigFile()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read configuration of image for platform %v: %w", platform.String(), err)
+		}
+		imagePlatform := v1.Platform{OS: config.OS, Architecture: config.Architecture, Variant: config.Variant}
+		// images frequently do not declare a variant, hence only a declared variant has to match
+		required := v1.Platform{OS: platform.OS, Architecture: platform.Architecture}
+		if len(config.Variant) > 0 {
+			required.Variant = platform.Variant
+		}
+		if len(config.OS) > 0 && len(config.Architecture) > 0 && !imagePlatform.Satisfies(required) {
+			return nil, fmt.Errorf("image for platform %v has been built for platform %v", platform.String(), imagePlatform.String())
+		}
+		if len(platform.OSVersion) == 0 {
+			platform.OSVersion = config.OSVersion
+		}
+
+		imageMediaType, err := platformImage.Image.MediaType()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read media type of image for platform %v: %w", platform.String(), err)
+		}
+		if imageMediaType == types.DockerManifestSchema2 {
+			mediaType = types.DockerManifestList
+		}
+		addenda = append(addenda, mutate.IndexAddendum{
+			Add:        platformImage.Image,
+			Descriptor: v1.Descriptor{MediaType: imageMediaType, Platform: &platform},
+		})
+	}
+	return mutate.AppendManifests(mutate.IndexMediaType(empty.Index, mediaType), addenda...), nil
+}
+
+// VerifyImageIndex checks that the pushed index references the images of all platforms of the expected index
+func VerifyImageIndex(expected, pushed v1.ImageIndex) error {
+	expectedManifest, err := expected.IndexManifest()
+	if err != nil {
+		return fmt.Errorf("failed to read image index: %w", err)
+	}
+	pushedManifest, err := pushed.IndexManifest()
+	if err != nil {
+		return fmt.Errorf("failed to read pushed image index: %w", err)
+	}
+	missing := []string{}
+	for _, expectedDescriptor := range expectedManifest.Manifests {
+		found := false
+		for _, pushedDescriptor := range pushedManifest.Manifests {
+			if pushedDescriptor.Digest == expectedDescriptor.Digest && pushedDescriptor.Platform != nil && pushedDescriptor.Platform.Equals(*expectedDescriptor.Platform) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, expectedDescriptor.Platform.String())
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("platforms %v are missing in the pushed image index", missing)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+//go:build unit
+// +build unit
+
+package docker
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPlatformImage(t *testing.T, os, arch string, mediaType types.MediaType) v1.Image {
+	img, err := random.Image(256, 1)
+	require.NoError(t, err)
+	configFile, err := img.ConfigFile()
+	require.NoError(t, err)
+	configFile.OS, configFile.Architecture = os, arch
+	img, err = mutate.ConfigFile(img, configFile)
+	require.NoError(t, err)
+	return mutate.MediaType(img, mediaType)
+}
+
+func TestNewImageIndex(t *testing.T) {
+	t.Parallel()
+	amd64 := v1.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}
+
+	t.Run("OCI image index", func(t *testing.T) {
+		t.Parallel()
+		amd64Image := newPlatformImage(t, "linux", "amd64", types.OCIManifestSchema1)
+		index, err := NewImageIndex([]PlatformImage{
+			{Platform: amd64, Image: amd64Image},
+			{Platform: arm64, Image: newPlatformImage(t, "linux", "arm64", types.OCIManifestSchema1)},
+		})
+		require.NoError(t, err)
+
+		mediaType, _ := index.MediaType()
+		assert.Equal(t, types.OCIImageIndex, mediaType)
+		manifest, err := index.IndexManifest()
+		require.NoError(t, err)
+		require.Len(t, manifest.Manifests, 2)
+		digest, _ := amd64Image.Digest()
+		assert.Equal(t, digest, manifest.Manifests[0].Digest)
+		assert.Equal(t, types.OCIManifestSchema1, manifest.Manifests[0].MediaType)
+		assert.Equal(t, amd64, *manifest.Manifests[0].Platform)
+		assert.Equal(t, arm64, *manifest.Manifests[1].Platform)
+	})
+
+	t.Run("Docker manifest list", func(t *testing.T) {
+		t.Parallel()
+		index, err := NewImageIndex([]PlatformImage{
+			{Platform: amd64, Image: newPlatformImage(t, "linux", "amd64", types.DockerManifestSchema2)},
+			{Platform: arm64, Image: newPlatformImage(t, "linux", "arm64", types.OCIManifestSchema1)},
+		})
+		require.NoError(t, err)
+
+		mediaType, _ := index.MediaType()
+		assert.Equal(t, types.DockerManifestList, mediaType)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		amd64Image := newPlatformImage(t, "linux", "amd64", types.OCIManifestSchema1)
+
+		_, err := NewImageIndex(nil)
+		assert.EqualError(t, err, "no images provided for the image index")
+
+		_, err = NewImageIndex([]PlatformImage{{Platform: amd64, Image: amd64Image}, {Platform: amd64, Image: amd64Image}})
+		assert.EqualError(t, err, "platform linux/amd64 is provided more than once")
+
+		_, err = NewImageIndex([]PlatformImage{{Platform: arm64, Image: amd64Image}})
+		assert.EqualError(t, err, "image for platform linux/arm64/v8 has been built for platform linux/amd64")
+	})
+}
+
+func TestPushImageIndex(t *testing.T) {
+	t.Parallel()
+	host := newTestRegistry(t)
+	amd64 := v1.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := v1.Platform{OS: "linux", Architecture: "arm64"}
+	index, err := NewImageIndex([]PlatformImage{
+		{Platform: amd64, Image: newPlatformImage(t, "linux", "amd64", types.OCIManifestSchema1)},
+		{Platform: arm64, Image: newPlatformImage(t, "linux", "arm64", types.OCIManifestSchema1)},
+	})
+	require.NoError(t, err)
+	craneUtils := NewCraneUtilsBundle()
+
+	require.NoError(t, craneUtils.PushImageIndex(context.Background(), index, host+"/app:1.0.0"))
+	pushed, err := craneUtils.GetImageIndex(context.Background(), host+"/app:1.0.0")
+	require.NoError(t, err)
+
+	assert.NoError(t, VerifyImageIndex(index, pushed))
+	partial, err := NewImageIndex([]PlatformImage{{Platform: amd64, Image: newPlatformImage(t, "linux", "amd64", types.OCIManifestSchema1)}})
+	require.NoError(t, err)
+	assert.EqualError(t, VerifyImageIndex(index, partial), "platforms [linux/amd64 linux/arm64] are missing in the pushed image index")
+}
@@ -3,6 +3,9 @@ package mock
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 )
@@ -15,10 +18,45 @@ var (
 
 type CraneMockUtils struct {
 	ErrCopyImage, ErrPushImage, ErrLoadImage error
+	ErrPullImage, ErrPushImageIndex          error
+	ErrDeleteImage                           error
+	// Images are returned by LoadImage and PullImage by source
+	Images map[string]v1.Image
+	// PushedIndexes contains the indexes pushed by destination
+	PushedIndexes map[string]v1.ImageIndex
+	// Digests are returned by GetImageDigest by image reference, copied images inherit the digest of the source
+	Digests map[string]string
+	// Tags are returned by ListTags by repository
+	Tags map[string][]string
+	// CopiedImages contains the sources of the copied images by destination
+	CopiedImages map[string]string
+	// DeletedImages contains the references passed to DeleteImage
+	DeletedImages []string
+
+	mutex sync.Mutex
 }
 
 func (c *CraneMockUtils) CopyImage(_ context.Context, src, dest, platform string) error {
-	return c.ErrCopyImage
+	if c.ErrCopyImage != nil {
+		return c.ErrCopyImage
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.CopiedImages == nil {
+		c.CopiedImages = map[string]string{}
+	}
+	c.CopiedImages[dest] = src
+	digest, ok := c.Digests[src]
+	if i := strings.Index(src, "@"); i >= 0 {
+		digest, ok = src[i+1:], true
+	}
+	if ok {
+		if c.Digests == nil {
+			c.Digests = map[string]string{}
+		}
+		c.Digests[dest] = digest
+	}
+	return nil
 }
 
 func (c *CraneMockUtils) PushImage(_ context.Context, im v1.Image, dest, platform string) error {
@@ -26,5 +64,70 @@ func (c *CraneMockUtils) PushImage(_ context.Context, im v1.Image, dest, platfor
 }
 
 func (c *CraneMockUtils) LoadImage(_ context.Context, src string) (v1.Image, error) {
-	return nil, c.ErrLoadImage
+	if c.ErrLoadImage != nil {
+		return nil, c.ErrLoadImage
+	}
+	return c.Images[src], nil
+}
+
+func (c *CraneMockUtils) PullImage(_ context.Context, src, platform string) (v1.Image, error) {
+	if c.ErrPullImage != nil {
+		return nil, c.ErrPullImage
+	}
+	image, ok := c.Images[src]
+	if !ok {
+		return nil, fmt.Errorf("image %v not found", src)
+	}
+	return image, nil
+}
+
+func (c *CraneMockUtils) PushImageIndex(_ context.Context, index v1.ImageIndex, dest string) error {
+	if c.ErrPushImageIndex != nil {
+		return c.ErrPushImageIndex
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.PushedIndexes == nil {
+		c.PushedIndexes = map[string]v1.ImageIndex{}
+	}
+	c.PushedIndexes[dest] = index
+	return nil
+}
+
+func (c *CraneMockUtils) GetImageIndex(_ context.Context, src string) (v1.ImageIndex, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	index, ok := c.PushedIndexes[src]
+	if !ok {
+		return nil, fmt.Errorf("image index %v not found", src)
+	}
+	return index, nil
+}
+
+func (c *CraneMockUtils) GetImageDigest(_ context.Context, src string) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	digest, ok := c.Digests[src]
+	if !ok {
+		return "", fmt.Errorf("image %v not found", src)
+	}
+	return digest, nil
+}
+
+func (c *CraneMockUtils) ListTags(_ context.Context, repository string) ([]string, error) {
+	tags, ok := c.Tags[repository]
+	if !ok {
+		return nil, fmt.Errorf("repository %v not found", repository)
+	}
+	return tags, nil
+}
+
+func (c *CraneMockUtils) DeleteImage(_ context.Context, src string) error {
+	if c.ErrDeleteImage != nil {
+		return c.ErrDeleteImage
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.DeletedImages = append(c.DeletedImages, src)
+	return nil
 }
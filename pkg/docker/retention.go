@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// VersionTagsToPrune returns the version tags of a repository which exceed the number of versions to keep, oldest first.
+// Tags which are no versions (e.g. latest or environment tags) and protected tags are never pruned.
+func VersionTagsToPrune(tags []string, keep int, protected []string) []string {
+	isProtected := map[string]bool{}
+	for _, tag := range protected {
+		isProtected[tag] = true
+	}
+	type versionTag struct {
+		tag     string
+		version *semver.Version
+	}
+	versionTags := []versionTag{}
+	for _, tag := range tags {
+		version, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		versionTags = append(versionTags, versionTag{tag: tag, version: version})
+	}
+	sort.SliceStable(versionTags, func(i, j int) bool {
+		return versionTags[i].version.GreaterThan(versionTags[j].version)
+	})
+
+	prune := []string{}
+	for i := len(versionTags) - 1; i >= keep && i >= 0; i-- {
+		if !isProtected[versionTags[i].tag] {
+			prune = append(prune, versionTags[i].tag)
+		}
+	}
+	return prune
+}
+
+// SignatureTags returns the tags cosign stores the signatures, attestations and SBOMs of the image with the digest at.
+func SignatureTags(digest string) []string {
+	prefix := strings.Replace(digest, ":", "-", 1)
+	return []string{prefix + ".sig", prefix + ".att", prefix + ".sbom"}
+}
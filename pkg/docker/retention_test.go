@@ -0,0 +1,35 @@
+//go:build unit
+// +build unit
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionTagsToPrune(t *testing.T) {
+	t.Parallel()
+	tags := []string{"latest", "1.10.0", "1.2.0", "prod", "1.9.0-20260101120000-abc", "1.9.0-20260102120000-def", "v0.9.0", "sha256-1234.sig"}
+
+	t.Run("keep newest versions", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"v0.9.0", "1.2.0", "1.9.0-20260101120000-abc"}, VersionTagsToPrune(tags, 2, nil))
+	})
+
+	t.Run("protected tags", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"v0.9.0", "1.9.0-20260101120000-abc"}, VersionTagsToPrune(tags, 2, []string{"1.2.0"}))
+	})
+
+	t.Run("fewer versions than kept", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, VersionTagsToPrune(tags, 5, nil))
+		assert.Empty(t, VersionTagsToPrune([]string{"latest"}, 0, nil))
+	})
+}
+
+func TestSignatureTags(t *testing.T) {
+	assert.Equal(t, []string{"sha256-abc.sig", "sha256-abc.att", "sha256-abc.sbom"}, SignatureTags("sha256:abc"))
+}
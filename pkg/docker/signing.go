@@ -0,0 +1,409 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// Signatures are stored in the format of cosign (https://github.com/sigstore/cosign/blob/main/specs/SIGNATURE_SPEC.md):
+// an image tagged with the digest of the signed image which contains one layer per signature
+const (
+	// SimpleSigningMediaType is the media type of the signature layers
+	SimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// SignatureAnnotation is the layer annotation containing the base64 encoded signature of the layer content
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	signaturePayloadType = "cosign container image signature"
+)
+
+// SignaturePayload is the signed content in the simple signing format
+type SignaturePayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]any `json:"optional"`
+}
+
+// encryptedKey is the JSON content of a private key encrypted by cosign
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// LoadSigningKey reads a PEM encoded private key. Keys encrypted by cosign (`cosign generate-key-pair`) are decrypted with the password,
+// unencrypted keys in PKCS#8, PKCS#1 or SEC 1 format are supported as well.
+func LoadSigningKey(content, password []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("signing key is not PEM encoded")
+	}
+	der := block.Bytes
+	switch block.Type {
+	case "ENCRYPTED SIGSTORE PRIVATE KEY", "ENCRYPTED COSIGN PRIVATE KEY":
+		var err error
+		if der, err = decryptKey(block.Bytes, password); err != nil {
+			return nil, err
+		}
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(der)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(der)
+	case "PRIVATE KEY":
+	default:
+		return nil, fmt.Errorf("unsupported signing key type '%v'", block.Type)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key of type %T", key)
+	}
+	return signer, nil
+}
+
+func decryptKey(content, password []byte) ([]byte, error) {
+	key := encryptedKey{}
+	if err := json.Unmarshal(content, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse encrypted signing key: %w", err)
+	}
+	if key.KDF.Name != "scrypt" || key.Cipher.Name != "nacl/secretbox" {
+		return nil, fmt.Errorf("unsupported encryption of signing key: %v/%v", key.KDF.Name, key.Cipher.Name)
+	}
+	if len(key.Cipher.Nonce) != 24 {
+		return nil, errors.New("invalid nonce of encrypted signing key")
+	}
+	derived, err := scrypt.Key(password, key.KDF.Salt, key.KDF.Params.N, key.KDF.Params.R, key.KDF.Params.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	var nonce [24]byte
+	var secretKey [32]byte
+	copy(nonce[:], key.Cipher.Nonce)
+	copy(secretKey[:], derived)
+	decrypted, ok := secretbox.Open(nil, key.Ciphertext, &nonce, &secretKey)
+	if !ok {
+		return nil, errors.New("failed to decrypt signing key, please check the password")
+	}
+	return decrypted, nil
+}
+
+// LoadPublicKey reads a PEM encoded public key as created by `cosign generate-key-pair`
+func LoadPublicKey(content []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("unsupported public key type '%v'", block.Type)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	return key, nil
+}
+
+// RegistryOptions returns the options to access a registry using the credentials of the given docker config.json.
+// Without a docker config the default keychain is used.
+func RegistryOptions(ctx context.Context, dockerConfigJSON string, utils piperutils.FileUtils) ([]remote.Option, error) {
+	opts := []remote.Option{remote.WithContext(ctx), remote.WithTransport(newHTTPTransport(false))}
+	if len(dockerConfigJSON) == 0 {
+		return append(opts, remote.WithAuthFromKeychain(authn.DefaultKeychain)), nil
+	}
+	content, err := utils.FileRead(dockerConfigJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docker config '%v': %w", dockerConfigJSON, err)
+	}
+	keychain, err := newConfigFileKeychain(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse docker config '%v': %w", dockerConfigJSON, err)
+	}
+	return append(opts, remote.WithAuthFromKeychain(keychain)), nil
+}
+
+// configFileKeychain resolves credentials from a docker config.json which is not located in the default location
+type configFileKeychain struct {
+	configFile *configfile.ConfigFile
+}
+
+func newConfigFileKeychain(content []byte) (*configFileKeychain, error) {
+	configFile, err := config.LoadFromReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	return &configFileKeychain{configFile: configFile}, nil
+}
+
+func (k *configFileKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	registry := target.RegistryStr()
+	if registry == name.DefaultRegistry {
+		registry = authn.DefaultAuthKey
+	}
+	auth, err := k.configFile.GetAuthConfig(registry)
+	if err != nil {
+		return nil, err
+	}
+	if len(auth.Username) == 0 && len(auth.Password) == 0 && len(auth.Auth) == 0 && len(auth.IdentityToken) == 0 && len(auth.RegistryToken) == 0 {
+		return authn.Anonymous, nil
+	}
+	return authn.FromConfig(authn.AuthConfig{
+		Username:      auth.Username,
+		Password:      auth.Password,
+		Auth:          auth.Auth,
+		IdentityToken: auth.IdentityToken,
+		RegistryToken: auth.RegistryToken,
+	}), nil
+}
+
+// SignatureTag returns the tag of the image containing the signatures of the image with the given digest
+func SignatureTag(digest name.Digest) name.Tag {
+	return digest.Context().Tag(strings.ReplaceAll(digest.DigestStr(), ":", "-") + ".sig")
+}
+
+// SignImage signs the image and pushes the signature next to it, existing signatures are kept.
+// Tags are resolved to the digest of the image, the digest reference of the signed image is returned.
+func SignImage(image string, key crypto.Signer, opts ...remote.Option) (string, error) {
+	digest, err := resolveDigest(image, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	payload := SignaturePayload{}
+	payload.Critical.Identity.DockerReference = digest.Context().Name()
+	payload.Critical.Image.DockerManifestDigest = digest.DigestStr()
+	payload.Critical.Type = signaturePayloadType
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to create signature payload: %w", err)
+	}
+	signature, err := sign(key, content)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign image '%v': %w", digest, err)
+	}
+
+	signatureTag := SignatureTag(digest)
+	signatures, err := readSignatures(signatureTag, opts...)
+	if err != nil {
+		return "", err
+	}
+	if signatures == nil {
+		signatures = mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), types.OCIConfigJSON)
+	} else if alreadySigned(signatures, content, key.Public()) {
+		log.Entry().Infof("Image '%v' is already signed with the given key", digest)
+		return digest.String(), nil
+	}
+
+	signatures, err = mutate.Append(signatures, mutate.Addendum{
+		Layer:       static.NewLayer(content, SimpleSigningMediaType),
+		Annotations: map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(signature)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to add signature: %w", err)
+	}
+	if err := remote.Write(signatureTag, signatures, opts...); err != nil {
+		return "", fmt.Errorf("failed to push signature '%v': %w", signatureTag, err)
+	}
+	log.Entry().Infof("Signature of image '%v' pushed to '%v'", digest, signatureTag)
+	return digest.String(), nil
+}
+
+// VerifyImageSignature checks that the image has at least one signature which can be verified with one of the public keys.
+// The digest reference of the verified image is returned.
+func VerifyImageSignature(image string, publicKeys []crypto.PublicKey, opts ...remote.Option) (string, error) {
+	if len(publicKeys) == 0 {
+		return "", errors.New("no public keys provided")
+	}
+	digest, err := resolveDigest(image, opts...)
+	if err != nil {
+		return "", err
+	}
+	signatures, err := readSignatures(SignatureTag(digest), opts...)
+	if err != nil {
+		return "", err
+	}
+	if signatures == nil {
+		return "", fmt.Errorf("no signatures found for image '%v'", digest)
+	}
+
+	layers, err := signatureLayers(signatures)
+	if err != nil {
+		return "", err
+	}
+	for _, layer := range layers {
+		payload := SignaturePayload{}
+		if err := json.Unmarshal(layer.content, &payload); err != nil {
+			log.Entry().Debugf("skipping invalid signature payload: %v", err)
+			continue
+		}
+		if payload.Critical.Type != signaturePayloadType || payload.Critical.Image.DockerManifestDigest != digest.DigestStr() {
+			log.Entry().Debugf("skipping signature of '%v'", payload.Critical.Image.DockerManifestDigest)
+			continue
+		}
+		for _, publicKey := range publicKeys {
+			if verify(publicKey, layer.content, layer.signature) == nil {
+				return digest.String(), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no valid signature found for image '%v'", digest)
+}
+
+func resolveDigest(image string, opts ...remote.Option) (name.Digest, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("invalid image reference '%v': %w", image, err)
+	}
+	if digest, ok := ref.(name.Digest); ok {
+		return digest, nil
+	}
+	descriptor, err := remote.Head(ref, opts...)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("failed to resolve digest of image '%v': %w", image, err)
+	}
+	return ref.Context().Digest(descriptor.Digest.String()), nil
+}
+
+// readSignatures returns the image containing the signatures or nil if the image is not signed yet
+func readSignatures(signatureTag name.Tag, opts ...remote.Option) (v1.Image, error) {
+	signatures, err := remote.Image(signatureTag, opts...)
+	if err != nil {
+		var transportErr *transport.Error
+		if errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read signatures '%v': %w", signatureTag, err)
+	}
+	return signatures, nil
+}
+
+type signatureLayer struct {
+	content   []byte
+	signature []byte
+}
+
+func signatureLayers(signatures v1.Image) ([]signatureLayer, error) {
+	manifest, err := signatures.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature manifest: %w", err)
+	}
+	layers := []signatureLayer{}
+	for _, descriptor := range manifest.Layers {
+		if descriptor.MediaType != SimpleSigningMediaType {
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(descriptor.Annotations[SignatureAnnotation])
+		if err != nil || len(signature) == 0 {
+			log.Entry().Debugf("skipping signature layer '%v' without valid signature annotation", descriptor.Digest)
+			continue
+		}
+		layer, err := signatures.LayerByDigest(descriptor.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signature layer '%v': %w", descriptor.Digest, err)
+		}
+		reader, err := layer.Compressed()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signature layer '%v': %w", descriptor.Digest, err)
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signature layer '%v': %w", descriptor.Digest, err)
+		}
+		layers = append(layers, signatureLayer{content: content, signature: signature})
+	}
+	return layers, nil
+}
+
+// alreadySigned checks whether the signatures contain a valid signature of the payload for the public key
+func alreadySigned(signatures v1.Image, payload []byte, publicKey crypto.PublicKey) bool {
+	layers, err := signatureLayers(signatures)
+	if err != nil {
+		return false
+	}
+	for _, layer := range layers {
+		if bytes.Equal(layer.content, payload) && verify(publicKey, layer.content, layer.signature) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func sign(key crypto.Signer, payload []byte) ([]byte, error) {
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		return key.Sign(rand.Reader, payload, crypto.Hash(0))
+	}
+	hash := sha256.Sum256(payload)
+	return key.Sign(rand.Reader, hash[:], crypto.SHA256)
+}
+
+func verify(publicKey crypto.PublicKey, payload, signature []byte) error {
+	hash := sha256.Sum256(payload)
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, hash[:], signature) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, payload, signature) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key of type %T", publicKey)
+	}
+}
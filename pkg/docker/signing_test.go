@@ -0,0 +1,229 @@
+//go:build unit
+// +build unit
+
+package docker
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	stdlog "log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func newTestRegistry(t *testing.T) string {
+	server := httptest.NewServer(registry.New(registry.Logger(stdlog.New(io.Discard, "", 0))))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func pushRandomImage(t *testing.T, image string) string {
+	img, err := random.Image(512, 1)
+	require.NoError(t, err)
+	ref, err := name.ParseReference(image)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+	digest, err := img.Digest()
+	require.NoError(t, err)
+	return ref.Context().Digest(digest.String()).String()
+}
+
+// encryptTestKey encrypts a private key the same way as `cosign generate-key-pair`, with reduced scrypt cost
+func encryptTestKey(t *testing.T, key crypto.PrivateKey, password string) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	encrypted := encryptedKey{}
+	encrypted.KDF.Name = "scrypt"
+	encrypted.KDF.Params.N, encrypted.KDF.Params.R, encrypted.KDF.Params.P = 1024, 8, 1
+	encrypted.KDF.Salt = make([]byte, 32)
+	encrypted.Cipher.Name = "nacl/secretbox"
+	encrypted.Cipher.Nonce = make([]byte, 24)
+	_, _ = rand.Read(encrypted.KDF.Salt)
+	_, _ = rand.Read(encrypted.Cipher.Nonce)
+	derived, err := scrypt.Key([]byte(password), encrypted.KDF.Salt, 1024, 8, 1, 32)
+	require.NoError(t, err)
+	var nonce [24]byte
+	var secretKey [32]byte
+	copy(nonce[:], encrypted.Cipher.Nonce)
+	copy(secretKey[:], derived)
+	encrypted.Ciphertext = secretbox.Seal(nil, der, &nonce, &secretKey)
+	content, err := json.Marshal(encrypted)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: content})
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	t.Parallel()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("encrypted cosign key", func(t *testing.T) {
+		t.Parallel()
+		signer, err := LoadSigningKey(encryptTestKey(t, key, "secret"), []byte("secret"))
+		assert.NoError(t, err)
+		assert.True(t, key.Equal(signer))
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		t.Parallel()
+		_, err := LoadSigningKey(encryptTestKey(t, key, "secret"), []byte("wrong"))
+		assert.EqualError(t, err, "failed to decrypt signing key, please check the password")
+	})
+
+	t.Run("unencrypted keys", func(t *testing.T) {
+		t.Parallel()
+		der, _ := x509.MarshalECPrivateKey(key)
+		signer, err := LoadSigningKey(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil)
+		assert.NoError(t, err)
+		assert.True(t, key.Equal(signer))
+
+		_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+		der, _ = x509.MarshalPKCS8PrivateKey(edKey)
+		signer, err = LoadSigningKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil)
+		assert.NoError(t, err)
+		assert.True(t, edKey.Equal(signer))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := LoadSigningKey([]byte("no key"), nil)
+		assert.EqualError(t, err, "signing key is not PEM encoded")
+		_, err = LoadSigningKey(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{}}), nil)
+		assert.EqualError(t, err, "unsupported signing key type 'CERTIFICATE'")
+	})
+}
+
+func TestLoadPublicKey(t *testing.T) {
+	t.Parallel()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(key.Public())
+
+	publicKey, err := LoadPublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(publicKey))
+	_, err = LoadPublicKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: der}))
+	assert.EqualError(t, err, "unsupported public key type 'RSA PUBLIC KEY'")
+}
+
+func TestSignAndVerifyImage(t *testing.T) {
+	t.Parallel()
+	host := newTestRegistry(t)
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	t.Run("sign tag and verify digest", func(t *testing.T) {
+		t.Parallel()
+		digest := pushRandomImage(t, host+"/app:1.0.0")
+
+		signed, err := SignImage(host+"/app:1.0.0", key)
+		assert.NoError(t, err)
+		assert.Equal(t, digest, signed)
+
+		verified, err := VerifyImageSignature(digest, []crypto.PublicKey{otherKey.Public(), key.Public()})
+		assert.NoError(t, err)
+		assert.Equal(t, digest, verified)
+
+		_, err = VerifyImageSignature(digest, []crypto.PublicKey{otherKey.Public()})
+		assert.EqualError(t, err, fmt.Sprintf("no valid signature found for image '%v'", digest))
+	})
+
+	t.Run("signature format", func(t *testing.T) {
+		t.Parallel()
+		digest := pushRandomImage(t, host+"/format:1.0.0")
+		_, err := SignImage(digest, key)
+		require.NoError(t, err)
+
+		digestRef, _ := name.NewDigest(digest)
+		assert.Equal(t, host+"/format:sha256-"+strings.TrimPrefix(digestRef.DigestStr(), "sha256:")+".sig", SignatureTag(digestRef).String())
+		signatures, err := remote.Image(SignatureTag(digestRef))
+		require.NoError(t, err)
+		manifest, _ := signatures.Manifest()
+		require.Len(t, manifest.Layers, 1)
+		assert.Equal(t, SimpleSigningMediaType, string(manifest.Layers[0].MediaType))
+		assert.NotEmpty(t, manifest.Layers[0].Annotations[SignatureAnnotation])
+		layers, _ := signatureLayers(signatures)
+		assert.JSONEq(t, fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%v/format"},"image":{"docker-manifest-digest":"%v"},"type":"cosign container image signature"},"optional":null}`, host, digestRef.DigestStr()), string(layers[0].content))
+	})
+
+	t.Run("multiple keys and repeated signing", func(t *testing.T) {
+		t.Parallel()
+		digest := pushRandomImage(t, host+"/multi:1.0.0")
+		for _, signer := range []crypto.Signer{key, key, edKey} {
+			_, err := SignImage(digest, signer)
+			require.NoError(t, err)
+		}
+
+		digestRef, _ := name.NewDigest(digest)
+		signatures, _ := remote.Image(SignatureTag(digestRef))
+		manifest, _ := signatures.Manifest()
+		assert.Len(t, manifest.Layers, 2)
+		_, err := VerifyImageSignature(digest, []crypto.PublicKey{edKey.Public()})
+		assert.NoError(t, err)
+	})
+
+	t.Run("unsigned image", func(t *testing.T) {
+		t.Parallel()
+		digest := pushRandomImage(t, host+"/unsigned:1.0.0")
+
+		_, err := VerifyImageSignature(host+"/unsigned:1.0.0", []crypto.PublicKey{key.Public()})
+
+		assert.EqualError(t, err, fmt.Sprintf("no signatures found for image '%v'", digest))
+	})
+
+	t.Run("unknown image", func(t *testing.T) {
+		t.Parallel()
+		_, err := SignImage(host+"/unknown:1.0.0", key)
+		assert.ErrorContains(t, err, "failed to resolve digest of image")
+	})
+}
+
+func TestRegistryOptions(t *testing.T) {
+	t.Parallel()
+	utils := &mock.FilesMock{}
+	utils.AddFile("config.json", []byte(`{"auths":{"my.registry":{"auth":"dXNlcjpwYXNz"}}}`))
+
+	opts, err := RegistryOptions(context.Background(), "config.json", utils)
+	assert.NoError(t, err)
+	assert.Len(t, opts, 3)
+
+	keychain, err := newConfigFileKeychain([]byte(`{"auths":{"my.registry":{"auth":"dXNlcjpwYXNz"}}}`))
+	require.NoError(t, err)
+	registry, _ := name.NewRegistry("my.registry")
+	auth, err := keychain.Resolve(registry)
+	assert.NoError(t, err)
+	authConfig, _ := auth.Authorization()
+	assert.Equal(t, "user", authConfig.Username)
+	assert.Equal(t, "pass", authConfig.Password)
+
+	other, _ := name.NewRegistry("other.registry")
+	auth, err = keychain.Resolve(other)
+	assert.NoError(t, err)
+	assert.Equal(t, authn.Anonymous, auth)
+
+	_, err = RegistryOptions(context.Background(), "missing.json", utils)
+	assert.ErrorContains(t, err, "failed to read docker config 'missing.json'")
+}
@@ -54,6 +54,28 @@ func NewClient(keyFile, token string, opts ...clientOptions) (Client, error) {
 	return client, nil
 }
 
+// NewClientFromJSONKey initializes the Google Cloud Storage client with the JSON key of a service account, the key is kept in memory only
+func NewClientFromJSONKey(jsonKey []byte, opts ...clientOptions) (Client, error) {
+	client := &gcsClient{
+		openFile:   openFileFromFS,
+		createFile: createFileOnFS,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	o := append([]option.ClientOption{option.WithAuthCredentialsJSON(option.ServiceAccount, jsonKey)}, client.gcsOptions...)
+	gcs, err := storage.NewClient(context.Background(), o...)
+	if err != nil {
+		return nil, fmt.Errorf("JSON key auth failed: %w", err)
+	}
+
+	client.gcs = *gcs
+	return client, nil
+}
+
 func (cl *gcsClient) UploadFile(ctx context.Context, bucketID string, sourcePath string, targetPath string) error {
 	sourcePath = filepath.Clean(sourcePath)
 	log.Entry().Debugf("Uploading %v to %v\n", sourcePath, targetPath)
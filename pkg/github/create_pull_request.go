@@ -0,0 +1,242 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/google/go-github/v68/github"
+)
+
+type githubGitService interface {
+	GetRef(ctx context.Context, owner string, repo string, ref string) (*github.Reference, *github.Response, error)
+	CreateRef(ctx context.Context, owner string, repo string, ref *github.Reference) (*github.Reference, *github.Response, error)
+	UpdateRef(ctx context.Context, owner string, repo string, ref *github.Reference, force bool) (*github.Reference, *github.Response, error)
+	GetCommit(ctx context.Context, owner string, repo string, sha string) (*github.Commit, *github.Response, error)
+	CreateTree(ctx context.Context, owner string, repo string, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error)
+	CreateCommit(ctx context.Context, owner string, repo string, commit *github.Commit, opts *github.CreateCommitOptions) (*github.Commit, *github.Response, error)
+	GetTree(ctx context.Context, owner string, repo string, sha string, recursive bool) (*github.Tree, *github.Response, error)
+	GetBlobRaw(ctx context.Context, owner string, repo string, sha string) ([]byte, *github.Response, error)
+}
+
+type githubPullRequestService interface {
+	List(ctx context.Context, owner string, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	Create(ctx context.Context, owner string, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
+	Edit(ctx context.Context, owner string, repo string, number int, pull *github.PullRequest) (*github.PullRequest, *github.Response, error)
+}
+
+type githubLabelService interface {
+	AddLabelsToIssue(ctx context.Context, owner string, repo string, number int, labels []string) ([]*github.Label, *github.Response, error)
+}
+
+// FilePatch computes the new content of a file in the pull request.
+// readBaseFile returns the content of any file as it is on the base branch.
+type FilePatch func(readBaseFile func(path string) ([]byte, error)) ([]byte, error)
+
+// CreatePullRequestOptions to configure the creation of a pull request containing file changes
+type CreatePullRequestOptions struct {
+	APIURL        string            `json:"apiUrl,omitempty"`
+	Owner         string            `json:"owner,omitempty"`
+	Repository    string            `json:"repository,omitempty"`
+	Token         string            `json:"token,omitempty"`
+	TrustedCerts  []string          `json:"trustedCerts,omitempty"`
+	Base          string            `json:"base,omitempty"`
+	Head          string            `json:"head,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	Body          []byte            `json:"body,omitempty"`
+	CommitMessage string            `json:"commitMessage,omitempty"`
+	Labels        []string          `json:"labels,omitempty"`
+	Files         map[string][]byte `json:"files,omitempty"`
+	// Patches are applied to the content of the files on the base branch, so that changes on the base branch since the checkout are kept
+	Patches map[string]FilePatch `json:"-"`
+}
+
+// CreatePullRequest commits the given files and patched files on top of the base branch into the head branch and opens a pull request for it.
+// An existing head branch is reset and an already open pull request for it is updated.
+func CreatePullRequest(options *CreatePullRequestOptions) (*github.PullRequest, error) {
+	ctx, client, err := NewClientBuilder(options.Token, options.APIURL).WithTrustedCerts(options.TrustedCerts).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+	}
+	return createPullRequestLocal(ctx, options, client.Git, client.PullRequests, client.Issues)
+}
+
+func createPullRequestLocal(
+	ctx context.Context,
+	options *CreatePullRequestOptions,
+	gitService githubGitService,
+	prService githubPullRequestService,
+	labelService githubLabelService,
+) (*github.PullRequest, error) {
+	if len(options.Files) == 0 && len(options.Patches) == 0 {
+		return nil, fmt.Errorf("no files provided for pull request '%v'", options.Title)
+	}
+
+	baseRef, resp, err := gitService.GetRef(ctx, options.Owner, options.Repository, "heads/"+options.Base)
+	if err != nil {
+		logResponseStatus("get base branch", resp)
+		return nil, fmt.Errorf("failed to get base branch '%v': %w", options.Base, err)
+	}
+	baseCommit, resp, err := gitService.GetCommit(ctx, options.Owner, options.Repository, baseRef.GetObject().GetSHA())
+	if err != nil {
+		logResponseStatus("get base commit", resp)
+		return nil, fmt.Errorf("failed to get commit of base branch '%v': %w", options.Base, err)
+	}
+
+	files := map[string][]byte{}
+	for path, content := range options.Files {
+		files[path] = content
+	}
+	readBaseFile := baseFileReader(ctx, options, gitService, baseCommit.GetTree().GetSHA())
+	for path, patch := range options.Patches {
+		content, err := patch(readBaseFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to patch '%v': %w", path, err)
+		}
+		files[path] = content
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	entries := make([]*github.TreeEntry, 0, len(paths))
+	for _, path := range paths {
+		entries = append(entries, &github.TreeEntry{
+			Path:    github.Ptr(path),
+			Mode:    github.Ptr("100644"),
+			Type:    github.Ptr("blob"),
+			Content: github.Ptr(string(files[path])),
+		})
+	}
+	tree, resp, err := gitService.CreateTree(ctx, options.Owner, options.Repository, baseCommit.GetTree().GetSHA(), entries)
+	if err != nil {
+		logResponseStatus("create tree", resp)
+		return nil, fmt.Errorf("failed to create tree: %w", err)
+	}
+
+	commit, resp, err := gitService.CreateCommit(ctx, options.Owner, options.Repository, &github.Commit{
+		Message: github.Ptr(options.CommitMessage),
+		Tree:    tree,
+		Parents: []*github.Commit{{SHA: baseCommit.SHA}},
+	}, nil)
+	if err != nil {
+		logResponseStatus("create commit", resp)
+		return nil, fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	headRef := &github.Reference{
+		Ref:    github.Ptr("refs/heads/" + options.Head),
+		Object: &github.GitObject{SHA: commit.SHA},
+	}
+	_, resp, err = gitService.GetRef(ctx, options.Owner, options.Repository, "heads/"+options.Head)
+	if err != nil {
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			logResponseStatus("get head branch", resp)
+			return nil, fmt.Errorf("failed to get head branch '%v': %w", options.Head, err)
+		}
+		if _, resp, err = gitService.CreateRef(ctx, options.Owner, options.Repository, headRef); err != nil {
+			logResponseStatus("create head branch", resp)
+			return nil, fmt.Errorf("failed to create head branch '%v': %w", options.Head, err)
+		}
+	} else if _, resp, err = gitService.UpdateRef(ctx, options.Owner, options.Repository, headRef, true); err != nil {
+		logResponseStatus("update head branch", resp)
+		return nil, fmt.Errorf("failed to update head branch '%v': %w", options.Head, err)
+	}
+
+	pullRequest, err := createOrUpdatePullRequest(ctx, options, prService)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(options.Labels) > 0 {
+		if _, resp, err := labelService.AddLabelsToIssue(ctx, options.Owner, options.Repository, pullRequest.GetNumber(), options.Labels); err != nil {
+			logResponseStatus("add labels", resp)
+			return nil, fmt.Errorf("failed to add labels to pull request #%v: %w", pullRequest.GetNumber(), err)
+		}
+	}
+	return pullRequest, nil
+}
+
+// baseFileReader returns a function reading files from the given tree of the base branch.
+// The tree is only fetched on the first read.
+func baseFileReader(ctx context.Context, options *CreatePullRequestOptions, gitService githubGitService, treeSHA string) func(path string) ([]byte, error) {
+	var blobs map[string]string
+	return func(path string) ([]byte, error) {
+		if blobs == nil {
+			tree, resp, err := gitService.GetTree(ctx, options.Owner, options.Repository, treeSHA, true)
+			if err != nil {
+				logResponseStatus("get base tree", resp)
+				return nil, fmt.Errorf("failed to get tree of base branch '%v': %w", options.Base, err)
+			}
+			if tree.GetTruncated() {
+				return nil, fmt.Errorf("tree of base branch '%v' is too large to be read", options.Base)
+			}
+			blobs = map[string]string{}
+			for _, entry := range tree.Entries {
+				if entry.GetType() == "blob" {
+					blobs[entry.GetPath()] = entry.GetSHA()
+				}
+			}
+		}
+		sha, ok := blobs[path]
+		if !ok {
+			return nil, fmt.Errorf("file '%v' does not exist on base branch '%v': %w", path, options.Base, os.ErrNotExist)
+		}
+		content, resp, err := gitService.GetBlobRaw(ctx, options.Owner, options.Repository, sha)
+		if err != nil {
+			logResponseStatus("get blob", resp)
+			return nil, fmt.Errorf("failed to read '%v' from base branch '%v': %w", path, options.Base, err)
+		}
+		return content, nil
+	}
+}
+
+func createOrUpdatePullRequest(ctx context.Context, options *CreatePullRequestOptions, prService githubPullRequestService) (*github.PullRequest, error) {
+	existing, resp, err := prService.List(ctx, options.Owner, options.Repository, &github.PullRequestListOptions{
+		State: "open",
+		Head:  options.Owner + ":" + options.Head,
+		Base:  options.Base,
+	})
+	if err != nil {
+		logResponseStatus("list pull requests", resp)
+		return nil, fmt.Errorf("failed to look up existing pull requests: %w", err)
+	}
+
+	body := string(options.Body)
+	if len(existing) > 0 {
+		pullRequest, resp, err := prService.Edit(ctx, options.Owner, options.Repository, existing[0].GetNumber(), &github.PullRequest{
+			Title: &options.Title,
+			Body:  &body,
+		})
+		if err != nil {
+			logResponseStatus("edit pull request", resp)
+			return nil, fmt.Errorf("failed to update pull request #%v: %w", existing[0].GetNumber(), err)
+		}
+		log.Entry().Debugf("Updated pull request: %v", pullRequest)
+		return pullRequest, nil
+	}
+
+	pullRequest, resp, err := prService.Create(ctx, options.Owner, options.Repository, &github.NewPullRequest{
+		Title: &options.Title,
+		Head:  &options.Head,
+		Base:  &options.Base,
+		Body:  &body,
+	})
+	if err != nil {
+		logResponseStatus("create pull request", resp)
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	log.Entry().Debugf("New pull request created: %v", pullRequest)
+	return pullRequest, nil
+}
+
+func logResponseStatus(action string, resp *github.Response) {
+	if resp != nil && resp.Response != nil {
+		log.Entry().Errorf("GitHub %v returned response code %v", action, resp.Status)
+	}
+}
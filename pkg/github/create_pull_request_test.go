@@ -0,0 +1,219 @@
+//go:build unit
+
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type ghGitServiceMock struct {
+	existingRefs map[string]string
+	createdRef   *github.Reference
+	updatedRef   *github.Reference
+	treeEntries  []*github.TreeEntry
+	baseTree     string
+	commit       *github.Commit
+	baseFiles    map[string]string
+}
+
+func (g *ghGitServiceMock) GetRef(ctx context.Context, owner string, repo string, ref string) (*github.Reference, *github.Response, error) {
+	sha, ok := g.existingRefs[ref]
+	if !ok {
+		return nil, &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound, Status: "404"}}, errors.New("not found")
+	}
+	return &github.Reference{Ref: &ref, Object: &github.GitObject{SHA: &sha}}, &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
+}
+
+func (g *ghGitServiceMock) CreateRef(ctx context.Context, owner string, repo string, ref *github.Reference) (*github.Reference, *github.Response, error) {
+	g.createdRef = ref
+	return ref, nil, nil
+}
+
+func (g *ghGitServiceMock) UpdateRef(ctx context.Context, owner string, repo string, ref *github.Reference, force bool) (*github.Reference, *github.Response, error) {
+	g.updatedRef = ref
+	return ref, nil, nil
+}
+
+func (g *ghGitServiceMock) GetCommit(ctx context.Context, owner string, repo string, sha string) (*github.Commit, *github.Response, error) {
+	return &github.Commit{SHA: &sha, Tree: &github.Tree{SHA: github.Ptr("tree-" + sha)}}, nil, nil
+}
+
+func (g *ghGitServiceMock) CreateTree(ctx context.Context, owner string, repo string, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error) {
+	g.baseTree = baseTree
+	g.treeEntries = entries
+	return &github.Tree{SHA: github.Ptr("new-tree")}, nil, nil
+}
+
+func (g *ghGitServiceMock) CreateCommit(ctx context.Context, owner string, repo string, commit *github.Commit, opts *github.CreateCommitOptions) (*github.Commit, *github.Response, error) {
+	g.commit = commit
+	return &github.Commit{SHA: github.Ptr("new-commit")}, nil, nil
+}
+
+func (g *ghGitServiceMock) GetTree(ctx context.Context, owner string, repo string, sha string, recursive bool) (*github.Tree, *github.Response, error) {
+	entries := []*github.TreeEntry{}
+	for path := range g.baseFiles {
+		entries = append(entries, &github.TreeEntry{Path: github.Ptr(path), Type: github.Ptr("blob"), SHA: github.Ptr("blob-" + path)})
+	}
+	return &github.Tree{SHA: &sha, Entries: entries}, nil, nil
+}
+
+func (g *ghGitServiceMock) GetBlobRaw(ctx context.Context, owner string, repo string, sha string) ([]byte, *github.Response, error) {
+	return []byte(g.baseFiles[strings.TrimPrefix(sha, "blob-")]), nil, nil
+}
+
+type ghPullRequestServiceMock struct {
+	existing []*github.PullRequest
+	created  *github.NewPullRequest
+	edited   *github.PullRequest
+}
+
+func (g *ghPullRequestServiceMock) List(ctx context.Context, owner string, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+	return g.existing, nil, nil
+}
+
+func (g *ghPullRequestServiceMock) Create(ctx context.Context, owner string, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error) {
+	g.created = pull
+	return &github.PullRequest{Number: github.Ptr(7), Title: pull.Title}, nil, nil
+}
+
+func (g *ghPullRequestServiceMock) Edit(ctx context.Context, owner string, repo string, number int, pull *github.PullRequest) (*github.PullRequest, *github.Response, error) {
+	g.edited = pull
+	pull.Number = &number
+	return pull, nil, nil
+}
+
+type ghLabelServiceMock struct {
+	number int
+	labels []string
+}
+
+func (g *ghLabelServiceMock) AddLabelsToIssue(ctx context.Context, owner string, repo string, number int, labels []string) ([]*github.Label, *github.Response, error) {
+	g.number = number
+	g.labels = labels
+	return nil, nil, nil
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("creates branch and pull request", func(t *testing.T) {
+		gitService := &ghGitServiceMock{existingRefs: map[string]string{"heads/main": "base-commit"}}
+		prService := &ghPullRequestServiceMock{}
+		labelService := &ghLabelServiceMock{}
+		options := CreatePullRequestOptions{
+			Owner:         "owner",
+			Repository:    "repo",
+			Base:          "main",
+			Head:          "fixes",
+			Title:         "Upgrade dependencies",
+			Body:          []byte("body"),
+			CommitMessage: "Upgrade",
+			Labels:        []string{"security"},
+			Files:         map[string][]byte{"pom.xml": []byte("<project/>"), "app/package.json": []byte("{}")},
+		}
+
+		pullRequest, err := createPullRequestLocal(ctx, &options, gitService, prService, labelService)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, pullRequest.GetNumber())
+		assert.Equal(t, "tree-base-commit", gitService.baseTree)
+		if assert.Len(t, gitService.treeEntries, 2) {
+			assert.Equal(t, "app/package.json", gitService.treeEntries[0].GetPath())
+			assert.Equal(t, "<project/>", gitService.treeEntries[1].GetContent())
+		}
+		assert.Equal(t, "base-commit", gitService.commit.Parents[0].GetSHA())
+		assert.Equal(t, "refs/heads/fixes", gitService.createdRef.GetRef())
+		assert.Equal(t, "new-commit", gitService.createdRef.GetObject().GetSHA())
+		assert.Nil(t, gitService.updatedRef)
+		assert.Equal(t, "fixes", prService.created.GetHead())
+		assert.Equal(t, "main", prService.created.GetBase())
+		assert.Equal(t, "body", prService.created.GetBody())
+		assert.Equal(t, 7, labelService.number)
+		assert.Equal(t, []string{"security"}, labelService.labels)
+	})
+
+	t.Run("updates existing branch and pull request", func(t *testing.T) {
+		gitService := &ghGitServiceMock{existingRefs: map[string]string{"heads/main": "base-commit", "heads/fixes": "old-commit"}}
+		prService := &ghPullRequestServiceMock{existing: []*github.PullRequest{{Number: github.Ptr(3)}}}
+		options := CreatePullRequestOptions{
+			Base:  "main",
+			Head:  "fixes",
+			Title: "Upgrade dependencies",
+			Files: map[string][]byte{"pom.xml": []byte("<project/>")},
+		}
+
+		pullRequest, err := createPullRequestLocal(ctx, &options, gitService, prService, &ghLabelServiceMock{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, pullRequest.GetNumber())
+		assert.Nil(t, gitService.createdRef)
+		assert.Equal(t, "new-commit", gitService.updatedRef.GetObject().GetSHA())
+		assert.Nil(t, prService.created)
+		assert.Equal(t, "Upgrade dependencies", prService.edited.GetTitle())
+	})
+
+	t.Run("patches files of base branch", func(t *testing.T) {
+		gitService := &ghGitServiceMock{
+			existingRefs: map[string]string{"heads/main": "base-commit"},
+			baseFiles:    map[string]string{"pom.xml": "<project>base</project>"},
+		}
+		options := CreatePullRequestOptions{
+			Base:  "main",
+			Head:  "fixes",
+			Title: "Upgrade dependencies",
+			Patches: map[string]FilePatch{"pom.xml": func(readBaseFile func(path string) ([]byte, error)) ([]byte, error) {
+				content, err := readBaseFile("pom.xml")
+				return append(content, []byte(" patched")...), err
+			}},
+		}
+
+		_, err := createPullRequestLocal(ctx, &options, gitService, &ghPullRequestServiceMock{}, &ghLabelServiceMock{})
+
+		assert.NoError(t, err)
+		if assert.Len(t, gitService.treeEntries, 1) {
+			assert.Equal(t, "<project>base</project> patched", gitService.treeEntries[0].GetContent())
+		}
+	})
+
+	t.Run("fails for file missing on base branch", func(t *testing.T) {
+		gitService := &ghGitServiceMock{existingRefs: map[string]string{"heads/main": "base-commit"}}
+		options := CreatePullRequestOptions{
+			Base: "main",
+			Head: "fixes",
+			Patches: map[string]FilePatch{"pom.xml": func(readBaseFile func(path string) ([]byte, error)) ([]byte, error) {
+				return readBaseFile("pom.xml")
+			}},
+		}
+
+		_, err := createPullRequestLocal(ctx, &options, gitService, &ghPullRequestServiceMock{}, &ghLabelServiceMock{})
+
+		assert.EqualError(t, err, "failed to patch 'pom.xml': file 'pom.xml' does not exist on base branch 'main': file does not exist")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		assert.Nil(t, gitService.commit)
+	})
+
+	t.Run("fails for missing base branch", func(t *testing.T) {
+		options := CreatePullRequestOptions{Base: "main", Head: "fixes", Files: map[string][]byte{"pom.xml": {}}}
+
+		_, err := createPullRequestLocal(ctx, &options, &ghGitServiceMock{}, &ghPullRequestServiceMock{}, &ghLabelServiceMock{})
+
+		assert.EqualError(t, err, "failed to get base branch 'main': not found")
+	})
+
+	t.Run("fails without files", func(t *testing.T) {
+		options := CreatePullRequestOptions{Title: "Upgrade dependencies"}
+
+		_, err := createPullRequestLocal(ctx, &options, &ghGitServiceMock{}, &ghPullRequestServiceMock{}, &ghLabelServiceMock{})
+
+		assert.EqualError(t, err, "no files provided for pull request 'Upgrade dependencies'")
+	})
+}
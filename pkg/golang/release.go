@@ -0,0 +1,178 @@
+package golang
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/multiarch"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// DefaultReleaseArchiveNameTemplate names the release archives like `mytool_1.2.3_linux_amd64`
+const DefaultReleaseArchiveNameTemplate = "{{.Name}}_{{.Version}}_{{.OS}}_{{.Arch}}"
+
+// ChecksumsFile is the name of the file listing the SHA-256 digests of the release archives
+const ChecksumsFile = "checksums.txt"
+
+// ReleaseArchiveOptions configure the release archives containing the binaries built for a platform
+type ReleaseArchiveOptions struct {
+	// NameTemplate is a golang template for the archive name without extension.
+	// It can use the fields Name, Version, OS, Arch and the commonPipelineEnvironment in the form `.CPE["<paramName>"]`.
+	NameTemplate string
+	Name         string
+	Version      string
+	CPE          map[string]any
+	// Files are added to the archives of all platforms, e.g. LICENSE or README.md
+	Files []string
+	// Directory is the directory the archives are written to
+	Directory string
+	// ModTime is used for all archive entries, together with fixed owners and permissions it makes the archives reproducible
+	ModTime time.Time
+}
+
+type releaseArchiveNameValues struct {
+	Name    string
+	Version string
+	OS      string
+	Arch    string
+	CPE     map[string]any
+}
+
+// CreateReleaseArchive packages the binaries built for the platform and the additional files into a zip archive for windows
+// and a gzipped tar archive for other platforms. The path of the archive is returned.
+func CreateReleaseArchive(platform multiarch.Platform, binaries []string, options ReleaseArchiveOptions, utils piperutils.FileUtils) (string, error) {
+	nameTemplate := options.NameTemplate
+	if len(nameTemplate) == 0 {
+		nameTemplate = DefaultReleaseArchiveNameTemplate
+	}
+	tmpl, err := template.New("releaseArchiveName").Option("missingkey=zero").Parse(nameTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse release archive name template '%v': %w", nameTemplate, err)
+	}
+	var name bytes.Buffer
+	if err := tmpl.Execute(&name, releaseArchiveNameValues{Name: options.Name, Version: options.Version, OS: platform.OS, Arch: platform.Arch, CPE: options.CPE}); err != nil {
+		return "", fmt.Errorf("failed to execute release archive name template '%v': %w", nameTemplate, err)
+	}
+
+	entries := []releaseArchiveEntry{}
+	for _, binary := range binaries {
+		entries = append(entries, releaseArchiveEntry{source: binary, name: binaryEntryName(binary, platform), mode: 0o755})
+	}
+	for _, file := range options.Files {
+		entries = append(entries, releaseArchiveEntry{source: file, name: path.Base(filepath.ToSlash(file)), mode: 0o644})
+	}
+	slices.SortFunc(entries, func(a, b releaseArchiveEntry) int { return strings.Compare(a.name, b.name) })
+	for i := 1; i < len(entries); i++ {
+		if entries[i].name == entries[i-1].name {
+			return "", fmt.Errorf("release archive for %v/%v contains '%v' more than once", platform.OS, platform.Arch, entries[i].name)
+		}
+	}
+
+	var content bytes.Buffer
+	archive := filepath.Join(options.Directory, name.String())
+	if platform.OS == "windows" {
+		archive += ".zip"
+		err = writeZip(&content, entries, options.ModTime, utils)
+	} else {
+		archive += ".tar.gz"
+		err = writeTarGz(&content, entries, options.ModTime, utils)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create release archive '%v': %w", archive, err)
+	}
+
+	if err := utils.MkdirAll(filepath.Dir(archive), 0o777); err != nil {
+		return "", fmt.Errorf("failed to create directory for release archive '%v': %w", archive, err)
+	}
+	if err := utils.FileWrite(archive, content.Bytes(), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write release archive '%v': %w", archive, err)
+	}
+	log.Entry().Infof("Release archive '%v' created with %v file(s)", archive, len(entries))
+	return archive, nil
+}
+
+// WriteChecksums writes the SHA-256 digests of the files in the format of sha256sum into the checksums file
+func WriteChecksums(files []string, checksumsFile string, utils piperutils.FileUtils) error {
+	var checksums strings.Builder
+	for _, file := range files {
+		digest, err := utils.SHA256(file)
+		if err != nil {
+			return fmt.Errorf("failed to calculate digest of '%v': %w", file, err)
+		}
+		fmt.Fprintf(&checksums, "%s  %s\n", digest, filepath.Base(file))
+	}
+	if err := utils.FileWrite(checksumsFile, []byte(checksums.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write checksums file '%v': %w", checksumsFile, err)
+	}
+	return nil
+}
+
+type releaseArchiveEntry struct {
+	source string
+	name   string
+	mode   fs.FileMode
+}
+
+// binaryEntryName removes the platform suffix of binaries built for multiple architectures, e.g. mytool-linux.amd64 becomes mytool
+func binaryEntryName(binary string, platform multiarch.Platform) string {
+	name := path.Base(filepath.ToSlash(binary))
+	extension := ""
+	if platform.OS == "windows" {
+		extension = ".exe"
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(name, extension), fmt.Sprintf("-%s.%s", platform.OS, platform.Arch)) + extension
+}
+
+func writeTarGz(writer io.Writer, entries []releaseArchiveEntry, modTime time.Time, utils piperutils.FileUtils) error {
+	gzipWriter := gzip.NewWriter(writer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		content, err := utils.FileRead(entry.source)
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{Typeflag: tar.TypeReg, Name: entry.name, Size: int64(len(content)), Mode: int64(entry.mode), ModTime: modTime.UTC(), Format: tar.FormatPAX}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tarWriter.Write(content); err != nil {
+			return err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+func writeZip(writer io.Writer, entries []releaseArchiveEntry, modTime time.Time, utils piperutils.FileUtils) error {
+	zipWriter := zip.NewWriter(writer)
+	for _, entry := range entries {
+		content, err := utils.FileRead(entry.source)
+		if err != nil {
+			return err
+		}
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate, Modified: modTime.UTC()}
+		header.SetMode(entry.mode)
+		entryWriter, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if _, err := entryWriter.Write(content); err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
@@ -0,0 +1,139 @@
+//go:build unit
+// +build unit
+
+package golang
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/multiarch"
+)
+
+func TestCreateReleaseArchive(t *testing.T) {
+	t.Parallel()
+	modTime := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	options := ReleaseArchiveOptions{Name: "mytool", Version: "1.2.3", Files: []string{"LICENSE"}, Directory: "dist", ModTime: modTime}
+	newFiles := func() *mock.FilesMock {
+		files := &mock.FilesMock{}
+		files.AddFile("bin/mytool-linux.amd64", []byte("linux binary"))
+		files.AddFile("bin/mytool-windows.amd64.exe", []byte("windows binary"))
+		files.AddFile("LICENSE", []byte("Apache-2.0"))
+		return files
+	}
+
+	t.Run("tar.gz", func(t *testing.T) {
+		files := newFiles()
+
+		archive, err := CreateReleaseArchive(multiarch.Platform{OS: "linux", Arch: "amd64"}, []string{"bin/mytool-linux.amd64"}, options, files)
+
+		require.NoError(t, err)
+		assert.Equal(t, "dist/mytool_1.2.3_linux_amd64.tar.gz", archive)
+		content, err := files.FileRead(archive)
+		require.NoError(t, err)
+		gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+		require.NoError(t, err)
+		tarReader := tar.NewReader(gzipReader)
+		entries := []string{}
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			entryContent, err := io.ReadAll(tarReader)
+			require.NoError(t, err)
+			assert.Equal(t, modTime, header.ModTime.UTC())
+			entries = append(entries, fmt.Sprintf("%s %o %s", header.Name, header.Mode, entryContent))
+		}
+		assert.Equal(t, []string{"LICENSE 644 Apache-2.0", "mytool 755 linux binary"}, entries)
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		files := newFiles()
+		options := options
+		options.NameTemplate = `{{.Name}}-{{index .CPE "git/headCommitId"}}-{{.OS}}`
+		options.CPE = map[string]any{"git/headCommitId": "a1b2c3"}
+
+		archive, err := CreateReleaseArchive(multiarch.Platform{OS: "windows", Arch: "amd64"}, []string{"bin/mytool-windows.amd64.exe"}, options, files)
+
+		require.NoError(t, err)
+		assert.Equal(t, "dist/mytool-a1b2c3-windows.zip", archive)
+		content, err := files.FileRead(archive)
+		require.NoError(t, err)
+		zipReader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+		entries := []string{}
+		for _, file := range zipReader.File {
+			assert.Equal(t, modTime, file.Modified.UTC())
+			entries = append(entries, file.Name)
+		}
+		assert.Equal(t, []string{"LICENSE", "mytool.exe"}, entries)
+	})
+
+	t.Run("reproducible", func(t *testing.T) {
+		files := newFiles()
+		first, err := CreateReleaseArchive(multiarch.Platform{OS: "linux", Arch: "amd64"}, []string{"bin/mytool-linux.amd64"}, options, files)
+		require.NoError(t, err)
+		firstContent, _ := files.FileRead(first)
+
+		other := newFiles()
+		second, err := CreateReleaseArchive(multiarch.Platform{OS: "linux", Arch: "amd64"}, []string{"bin/mytool-linux.amd64"}, options, other)
+		require.NoError(t, err)
+		secondContent, _ := other.FileRead(second)
+
+		assert.Equal(t, firstContent, secondContent)
+	})
+
+	t.Run("duplicate entry", func(t *testing.T) {
+		files := newFiles()
+		files.AddFile("docs/LICENSE", []byte("MIT"))
+		options := options
+		options.Files = []string{"LICENSE", "docs/LICENSE"}
+
+		_, err := CreateReleaseArchive(multiarch.Platform{OS: "linux", Arch: "amd64"}, []string{"bin/mytool-linux.amd64"}, options, files)
+
+		assert.EqualError(t, err, "release archive for linux/amd64 contains 'LICENSE' more than once")
+	})
+
+	t.Run("missing binary", func(t *testing.T) {
+		_, err := CreateReleaseArchive(multiarch.Platform{OS: "linux", Arch: "arm64"}, []string{"bin/mytool-linux.arm64"}, options, newFiles())
+
+		assert.ErrorContains(t, err, "failed to create release archive 'dist/mytool_1.2.3_linux_arm64.tar.gz'")
+	})
+
+	t.Run("invalid name template", func(t *testing.T) {
+		options := options
+		options.NameTemplate = "{{.Name"
+
+		_, err := CreateReleaseArchive(multiarch.Platform{OS: "linux", Arch: "amd64"}, []string{"bin/mytool-linux.amd64"}, options, newFiles())
+
+		assert.ErrorContains(t, err, "failed to parse release archive name template '{{.Name'")
+	})
+}
+
+func TestWriteChecksums(t *testing.T) {
+	t.Parallel()
+	files := &mock.FilesMock{}
+	files.AddFile("dist/mytool_1.2.3_linux_amd64.tar.gz", []byte("linux"))
+	files.AddFile("dist/mytool_1.2.3_windows_amd64.zip", []byte("windows"))
+
+	err := WriteChecksums([]string{"dist/mytool_1.2.3_linux_amd64.tar.gz", "dist/mytool_1.2.3_windows_amd64.zip"}, "dist/checksums.txt", files)
+
+	require.NoError(t, err)
+	content, err := files.FileRead("dist/checksums.txt")
+	require.NoError(t, err)
+	linuxDigest, _ := files.SHA256("dist/mytool_1.2.3_linux_amd64.tar.gz")
+	windowsDigest, _ := files.SHA256("dist/mytool_1.2.3_windows_amd64.zip")
+	assert.Equal(t, linuxDigest+"  mytool_1.2.3_linux_amd64.tar.gz\n"+windowsDigest+"  mytool_1.2.3_windows_amd64.zip\n", string(content))
+}
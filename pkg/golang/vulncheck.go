@@ -0,0 +1,379 @@
+package golang
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// VulncheckReportsDirectory defines the subfolder for the vulnerability reports which are generated
+const VulncheckReportsDirectory = "govulncheck"
+
+// Reachability of a vulnerability, from the most to the least relevant
+const (
+	// ReachabilityCalled means the code calls a vulnerable function
+	ReachabilityCalled = "called"
+	// ReachabilityImported means the code imports a vulnerable package without calling a vulnerable function
+	ReachabilityImported = "imported"
+	// ReachabilityRequired means the code requires a vulnerable module without importing a vulnerable package
+	ReachabilityRequired = "required"
+)
+
+var reachabilityOrder = []string{ReachabilityCalled, ReachabilityImported, ReachabilityRequired}
+
+// VulnerabilityFinding is a vulnerability of the Go vulnerability database affecting a module the code depends on
+type VulnerabilityFinding struct {
+	ID           string   `json:"id"`
+	Aliases      []string `json:"aliases,omitempty"`
+	Summary      string   `json:"summary,omitempty"`
+	Details      string   `json:"details,omitempty"`
+	URL          string   `json:"url,omitempty"`
+	Module       string   `json:"module"`
+	Version      string   `json:"version,omitempty"`
+	FixedVersion string   `json:"fixedVersion,omitempty"`
+	Reachability string   `json:"reachability"`
+	// Packages are the vulnerable packages imported by the code
+	Packages []string `json:"packages,omitempty"`
+	// Symbols are the vulnerable functions called by the code
+	Symbols []string `json:"symbols,omitempty"`
+	// Calls are the locations in the code calling a vulnerable function
+	Calls      []VulnerableCall   `json:"calls,omitempty"`
+	Assessment *format.Assessment `json:"assessment,omitempty"`
+}
+
+// VulnerableCall is the location in the code from which a vulnerable function is called
+type VulnerableCall struct {
+	Function string `json:"function"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// govulncheck -json writes a stream of messages, only the vulnerabilities and the findings are of interest
+type vulncheckMessage struct {
+	OSV     *vulncheckOSV     `json:"osv"`
+	Finding *vulncheckFinding `json:"finding"`
+}
+
+type vulncheckOSV struct {
+	ID               string   `json:"id"`
+	Aliases          []string `json:"aliases"`
+	Summary          string   `json:"summary"`
+	Details          string   `json:"details"`
+	DatabaseSpecific struct {
+		URL string `json:"url"`
+	} `json:"database_specific"`
+}
+
+type vulncheckFinding struct {
+	OSV          string           `json:"osv"`
+	FixedVersion string           `json:"fixed_version"`
+	Trace        []vulncheckFrame `json:"trace"`
+}
+
+type vulncheckFrame struct {
+	Module   string `json:"module"`
+	Version  string `json:"version"`
+	Package  string `json:"package"`
+	Function string `json:"function"`
+	Receiver string `json:"receiver"`
+	Position *struct {
+		Filename string `json:"filename"`
+		Line     int    `json:"line"`
+	} `json:"position"`
+}
+
+// ParseVulncheckOutput reads the findings from the JSON output of govulncheck.
+// The findings of a vulnerability in a module are merged, the reachability is the most relevant one of them.
+func ParseVulncheckOutput(output []byte) ([]VulnerabilityFinding, error) {
+	osvs := map[string]vulncheckOSV{}
+	findings := []VulnerabilityFinding{}
+	decoder := json.NewDecoder(bufio.NewReader(bytes.NewReader(output)))
+	for decoder.More() {
+		var message vulncheckMessage
+		if err := decoder.Decode(&message); err != nil {
+			return nil, fmt.Errorf("failed to parse govulncheck output: %w", err)
+		}
+		if message.OSV != nil {
+			osvs[message.OSV.ID] = *message.OSV
+		}
+		if message.Finding == nil || len(message.Finding.Trace) == 0 {
+			continue
+		}
+
+		vulnerable := message.Finding.Trace[0]
+		index := slices.IndexFunc(findings, func(f VulnerabilityFinding) bool { return f.ID == message.Finding.OSV && f.Module == vulnerable.Module })
+		if index < 0 {
+			findings = append(findings, VulnerabilityFinding{ID: message.Finding.OSV, Module: vulnerable.Module, Version: vulnerable.Version, FixedVersion: message.Finding.FixedVersion, Reachability: ReachabilityRequired})
+			index = len(findings) - 1
+		}
+		finding := &findings[index]
+		if len(vulnerable.Package) > 0 && !slices.Contains(finding.Packages, vulnerable.Package) {
+			finding.Packages = append(finding.Packages, vulnerable.Package)
+			finding.Reachability = moreRelevant(finding.Reachability, ReachabilityImported)
+		}
+		if len(vulnerable.Function) > 0 {
+			symbol := symbolName(vulnerable)
+			if !slices.Contains(finding.Symbols, symbol) {
+				finding.Symbols = append(finding.Symbols, symbol)
+			}
+			finding.Reachability = ReachabilityCalled
+			finding.Calls = append(finding.Calls, entryCall(message.Finding.Trace))
+		}
+	}
+
+	for i := range findings {
+		osv := osvs[findings[i].ID]
+		findings[i].Aliases = osv.Aliases
+		findings[i].Summary = osv.Summary
+		findings[i].Details = osv.Details
+		findings[i].URL = osv.DatabaseSpecific.URL
+		if len(findings[i].URL) == 0 {
+			findings[i].URL = "https://pkg.go.dev/vuln/" + findings[i].ID
+		}
+	}
+	return findings, nil
+}
+
+func moreRelevant(a, b string) string {
+	if slices.Index(reachabilityOrder, b) < slices.Index(reachabilityOrder, a) {
+		return b
+	}
+	return a
+}
+
+func symbolName(frame vulncheckFrame) string {
+	if len(frame.Receiver) > 0 {
+		return fmt.Sprintf("%s.%s.%s", frame.Package, strings.TrimPrefix(frame.Receiver, "*"), frame.Function)
+	}
+	return fmt.Sprintf("%s.%s", frame.Package, frame.Function)
+}
+
+// entryCall returns the location of the outermost frame of the call stack, which is the code of the project
+func entryCall(trace []vulncheckFrame) VulnerableCall {
+	entry := trace[len(trace)-1]
+	call := VulnerableCall{Function: symbolName(entry)}
+	if entry.Position != nil {
+		call.File = entry.Position.Filename
+		call.Line = entry.Position.Line
+	}
+	return call
+}
+
+// PackageURL returns the package URL of the vulnerable module
+func (f VulnerabilityFinding) PackageURL() string {
+	purl := "pkg:golang/" + f.Module
+	if len(f.Version) > 0 {
+		purl += "@" + f.Version
+	}
+	return purl
+}
+
+// ApplyAssessments attaches the assessments to the findings they match. An assessment matches if it refers to the
+// vulnerability or one of its aliases and to the package URL of the module, with or without version.
+func ApplyAssessments(findings []VulnerabilityFinding, assessments []format.Assessment) {
+	for i := range findings {
+		identifiers := append([]string{findings[i].ID}, findings[i].Aliases...)
+		for _, assessment := range assessments {
+			if !slices.Contains(identifiers, assessment.Vulnerability) {
+				continue
+			}
+			for _, purl := range assessment.Purls {
+				if purl.Purl == findings[i].PackageURL() || purl.Purl == "pkg:golang/"+findings[i].Module {
+					log.Entry().Debugf("matching assessment %v on package %v detected for vulnerability %v", assessment.Vulnerability, purl.Purl, findings[i].ID)
+					findings[i].Assessment = &assessment
+				}
+			}
+		}
+	}
+}
+
+// CountVulnerabilities returns the number of findings without assessment which are at least as relevant as the reachability
+func CountVulnerabilities(findings []VulnerabilityFinding, reachability string) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.Assessment == nil && moreRelevant(finding.Reachability, reachability) == finding.Reachability {
+			count++
+		}
+	}
+	return count
+}
+
+// CreateVulncheckSarif creates a SARIF result from the findings
+func CreateVulncheckSarif(findings []VulnerabilityFinding) *format.SARIF {
+	sarif := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+	}
+	run := format.Runs{
+		Results: []format.Results{},
+		Tool: format.Tool{Driver: format.Driver{
+			Name:           "govulncheck",
+			InformationUri: "https://go.dev/security/vuln/",
+			Rules:          []format.SarifRule{},
+		}},
+		ThreadFlowLocations: []format.Locations{},
+	}
+
+	ruleIndices := map[string]int{}
+	for _, finding := range findings {
+		if _, ok := ruleIndices[finding.ID]; !ok {
+			ruleIndices[finding.ID] = len(run.Tool.Driver.Rules)
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, format.SarifRule{
+				ID:               finding.ID,
+				Name:             finding.ID,
+				ShortDescription: &format.Message{Text: finding.Summary},
+				FullDescription:  &format.Message{Text: finding.Details},
+				HelpURI:          finding.URL,
+				Help:             &format.Help{Text: fixText(finding)},
+				Properties:       &format.SarifRuleProperties{Tags: append([]string{"security", "vulnerability"}, finding.Aliases...)},
+			})
+		}
+
+		message := fmt.Sprintf("%v %v@%v is %v: %v", finding.ID, finding.Module, finding.Version, finding.Reachability, finding.Summary)
+		result := format.Results{
+			RuleID:              finding.ID,
+			RuleIndex:           ruleIndices[finding.ID],
+			Level:               vulncheckLevel(finding.Reachability),
+			Message:             &format.Message{Text: message},
+			Locations:           []format.Location{},
+			PartialFingerprints: format.PartialFingerprints{PackageURLPlusCVEHash: finding.PackageURL() + "+" + finding.ID},
+			Properties: &format.SarifProperties{
+				ToolSeverity:      vulnerabilitySeverity(finding.Reachability),
+				InstanceSeverity:  vulnerabilitySeverity(finding.Reachability),
+				ToolState:         "Unreviewed",
+				UnifiedAuditState: "new",
+			},
+		}
+		if finding.Assessment != nil {
+			result.Properties.Audited = true
+			result.Properties.ToolState = string(finding.Assessment.Status)
+			result.Properties.ToolAuditMessage = string(finding.Assessment.Analysis)
+			result.Properties.UnifiedAuditState = string(finding.Assessment.Status)
+		}
+		for _, call := range finding.Calls {
+			if len(call.File) == 0 {
+				continue
+			}
+			result.Locations = append(result.Locations, format.Location{
+				PhysicalLocation: format.PhysicalLocation{
+					ArtifactLocation: format.ArtifactLocation{URI: filepath.ToSlash(call.File)},
+					Region:           format.Region{StartLine: call.Line},
+				},
+				Message: &format.Message{Text: fmt.Sprintf("%v calls vulnerable code", call.Function)},
+			})
+		}
+		run.Results = append(run.Results, result)
+	}
+	sarif.Runs = append(sarif.Runs, run)
+	return &sarif
+}
+
+func fixText(finding VulnerabilityFinding) string {
+	if len(finding.FixedVersion) == 0 {
+		return fmt.Sprintf("No fixed version of %v is available.", finding.Module)
+	}
+	return fmt.Sprintf("Upgrade %v to %v or later.", finding.Module, finding.FixedVersion)
+}
+
+// vulnerabilitySeverity derives the severity from the reachability since the Go vulnerability database has no scores
+func vulnerabilitySeverity(reachability string) string {
+	switch reachability {
+	case ReachabilityCalled:
+		return "high"
+	case ReachabilityImported:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+func vulncheckLevel(reachability string) string {
+	switch reachability {
+	case ReachabilityCalled:
+		return "error"
+	case ReachabilityImported:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// ToVulnerabilityReport returns the finding as report, e.g. for creating an issue
+func (f VulnerabilityFinding) ToVulnerabilityReport(projectName, projectVersion string) reporting.VulnerabilityReport {
+	description := f.Details
+	if len(f.Symbols) > 0 {
+		description += fmt.Sprintf("\n\nVulnerable functions called: %v", strings.Join(f.Symbols, ", "))
+	}
+	resolution := ""
+	if len(f.FixedVersion) > 0 {
+		resolution = fixText(f)
+	}
+	return reporting.VulnerabilityReport{
+		ProjectName:       projectName,
+		ProjectVersion:    projectVersion,
+		ArtifactID:        f.Module,
+		Version:           f.Version,
+		PackageURL:        f.PackageURL(),
+		Description:       strings.TrimSpace(description),
+		DependencyType:    f.Reachability,
+		Resolution:        resolution,
+		Severity:          vulnerabilitySeverity(f.Reachability),
+		VulnerabilityLink: f.URL,
+		VulnerabilityName: f.ID,
+		Origin:            "govulncheck",
+	}
+}
+
+// WriteVulncheckReports writes the findings as JSON and SARIF report and a markdown report for each finding without assessment
+func WriteVulncheckReports(findings []VulnerabilityFinding, projectName, projectVersion string, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+	if err := utils.MkdirAll(VulncheckReportsDirectory, 0777); err != nil {
+		return reportPaths, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	jsonReport, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to marshal findings: %w", err)
+	}
+	jsonReportPath := filepath.Join(VulncheckReportsDirectory, "piper_govulncheck_report.json")
+	if err := utils.FileWrite(jsonReportPath, jsonReport, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write JSON report: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "govulncheck JSON report", Target: jsonReportPath})
+
+	sarifReport, err := json.Marshal(CreateVulncheckSarif(findings))
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to marshal SARIF report: %w", err)
+	}
+	sarifReportPath := filepath.Join(VulncheckReportsDirectory, "piper_govulncheck.sarif")
+	if err := utils.FileWrite(sarifReportPath, sarifReport, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write SARIF report: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "govulncheck SARIF report", Target: sarifReportPath})
+
+	for _, finding := range findings {
+		if finding.Assessment != nil {
+			continue
+		}
+		report := finding.ToVulnerabilityReport(projectName, projectVersion)
+		markdown, err := report.ToMarkdown()
+		if err != nil {
+			return reportPaths, fmt.Errorf("failed to create markdown report of %v: %w", finding.ID, err)
+		}
+		markdownPath := filepath.Join(VulncheckReportsDirectory, fmt.Sprintf("%v-%v.md", finding.ID, strings.ReplaceAll(finding.Module, "/", "_")))
+		if err := utils.FileWrite(markdownPath, markdown, 0666); err != nil {
+			return reportPaths, fmt.Errorf("failed to write markdown report: %w", err)
+		}
+		reportPaths = append(reportPaths, piperutils.Path{Name: fmt.Sprintf("govulncheck %v report", finding.ID), Target: markdownPath})
+	}
+	return reportPaths, nil
+}
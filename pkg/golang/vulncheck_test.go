@@ -0,0 +1,169 @@
+//go:build unit
+// +build unit
+
+package golang
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+const vulncheckOutput = `{"config":{"protocol_version":"v1.0.0","scanner_name":"govulncheck"}}
+{"osv":{"id":"GO-2024-2687","aliases":["CVE-2023-45288"],"summary":"HTTP/2 CONTINUATION flood in net/http","details":"An attacker may cause an HTTP/2 endpoint to read arbitrary amounts of header data.","database_specific":{"url":"https://pkg.go.dev/vuln/GO-2024-2687"}}}
+{"osv":{"id":"GO-2023-1988","aliases":["CVE-2023-3978"],"summary":"Improper rendering of text nodes in golang.org/x/net/html"}}
+{"osv":{"id":"GO-2022-0969","summary":"Denial of service in net/http"}}
+{"finding":{"osv":"GO-2024-2687","fixed_version":"v0.23.0","trace":[{"module":"golang.org/x/net","version":"v0.10.0"}]}}
+{"finding":{"osv":"GO-2024-2687","fixed_version":"v0.23.0","trace":[{"module":"golang.org/x/net","version":"v0.10.0","package":"golang.org/x/net/http2"}]}}
+{"finding":{"osv":"GO-2024-2687","fixed_version":"v0.23.0","trace":[{"module":"golang.org/x/net","version":"v0.10.0","package":"golang.org/x/net/http2","function":"ReadFrame","receiver":"*Framer"},{"module":"example.com/app","package":"example.com/app/server","function":"serve","position":{"filename":"server/server.go","line":42}}]}}
+{"finding":{"osv":"GO-2023-1988","fixed_version":"v0.13.0","trace":[{"module":"golang.org/x/net","version":"v0.10.0","package":"golang.org/x/net/html"}]}}
+{"finding":{"osv":"GO-2022-0969","trace":[{"module":"golang.org/x/text","version":"v0.3.0"}]}}
+`
+
+func TestParseVulncheckOutput(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		findings, err := ParseVulncheckOutput([]byte(vulncheckOutput))
+
+		require.NoError(t, err)
+		require.Len(t, findings, 3)
+		assert.Equal(t, VulnerabilityFinding{
+			ID:           "GO-2024-2687",
+			Aliases:      []string{"CVE-2023-45288"},
+			Summary:      "HTTP/2 CONTINUATION flood in net/http",
+			Details:      "An attacker may cause an HTTP/2 endpoint to read arbitrary amounts of header data.",
+			URL:          "https://pkg.go.dev/vuln/GO-2024-2687",
+			Module:       "golang.org/x/net",
+			Version:      "v0.10.0",
+			FixedVersion: "v0.23.0",
+			Reachability: ReachabilityCalled,
+			Packages:     []string{"golang.org/x/net/http2"},
+			Symbols:      []string{"golang.org/x/net/http2.Framer.ReadFrame"},
+			Calls:        []VulnerableCall{{Function: "example.com/app/server.serve", File: "server/server.go", Line: 42}},
+		}, findings[0])
+		assert.Equal(t, ReachabilityImported, findings[1].Reachability)
+		assert.Equal(t, []string{"golang.org/x/net/html"}, findings[1].Packages)
+		assert.Empty(t, findings[1].Symbols)
+		assert.Equal(t, ReachabilityRequired, findings[2].Reachability)
+		assert.Equal(t, "https://pkg.go.dev/vuln/GO-2022-0969", findings[2].URL)
+	})
+
+	t.Run("no findings", func(t *testing.T) {
+		findings, err := ParseVulncheckOutput([]byte(`{"config":{"scanner_name":"govulncheck"}}`))
+
+		require.NoError(t, err)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		_, err := ParseVulncheckOutput([]byte(`{"finding":`))
+
+		assert.ErrorContains(t, err, "failed to parse govulncheck output")
+	})
+}
+
+func TestApplyAssessments(t *testing.T) {
+	t.Parallel()
+	findings, err := ParseVulncheckOutput([]byte(vulncheckOutput))
+	require.NoError(t, err)
+
+	ApplyAssessments(findings, []format.Assessment{
+		{Vulnerability: "CVE-2023-45288", Status: format.NotRelevant, Analysis: format.Mitigated, Purls: []format.Purl{{Purl: "pkg:golang/golang.org/x/net"}}},
+		{Vulnerability: "GO-2023-1988", Status: format.NotRelevant, Analysis: format.NotPresent, Purls: []format.Purl{{Purl: "pkg:golang/golang.org/x/net@v0.9.0"}}},
+		{Vulnerability: "GO-2022-0969", Status: format.NotRelevant, Analysis: format.NotPresent, Purls: []format.Purl{{Purl: "pkg:golang/golang.org/x/text@v0.3.0"}}},
+	})
+
+	require.NotNil(t, findings[0].Assessment)
+	assert.Equal(t, format.Mitigated, findings[0].Assessment.Analysis)
+	assert.Nil(t, findings[1].Assessment, "assessment of another version must not match")
+	require.NotNil(t, findings[2].Assessment)
+	assert.Equal(t, "GO-2022-0969", findings[2].Assessment.Vulnerability)
+
+	assert.Equal(t, 0, CountVulnerabilities(findings, ReachabilityCalled))
+	assert.Equal(t, 1, CountVulnerabilities(findings, ReachabilityImported))
+	assert.Equal(t, 1, CountVulnerabilities(findings, ReachabilityRequired))
+}
+
+func TestCountVulnerabilities(t *testing.T) {
+	t.Parallel()
+	findings, err := ParseVulncheckOutput([]byte(vulncheckOutput))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, CountVulnerabilities(findings, ReachabilityCalled))
+	assert.Equal(t, 2, CountVulnerabilities(findings, ReachabilityImported))
+	assert.Equal(t, 3, CountVulnerabilities(findings, ReachabilityRequired))
+}
+
+func TestCreateVulncheckSarif(t *testing.T) {
+	t.Parallel()
+	findings, err := ParseVulncheckOutput([]byte(vulncheckOutput))
+	require.NoError(t, err)
+	findings[1].Assessment = &format.Assessment{Vulnerability: "GO-2023-1988", Status: format.NotRelevant, Analysis: format.NotPresent}
+
+	sarif := CreateVulncheckSarif(findings)
+
+	require.Len(t, sarif.Runs, 1)
+	run := sarif.Runs[0]
+	assert.Equal(t, "govulncheck", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 3)
+	assert.Equal(t, "Upgrade golang.org/x/net to v0.23.0 or later.", run.Tool.Driver.Rules[0].Help.Text)
+	assert.Equal(t, "No fixed version of golang.org/x/text is available.", run.Tool.Driver.Rules[2].Help.Text)
+	require.Len(t, run.Results, 3)
+
+	called := run.Results[0]
+	assert.Equal(t, "error", called.Level)
+	assert.Equal(t, "high", called.Properties.ToolSeverity)
+	assert.False(t, called.Properties.Audited)
+	require.Len(t, called.Locations, 1)
+	assert.Equal(t, "server/server.go", called.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 42, called.Locations[0].PhysicalLocation.Region.StartLine)
+
+	imported := run.Results[1]
+	assert.Equal(t, "warning", imported.Level)
+	assert.True(t, imported.Properties.Audited)
+	assert.Equal(t, string(format.NotRelevant), imported.Properties.ToolState)
+	assert.Empty(t, imported.Locations)
+
+	assert.Equal(t, "note", run.Results[2].Level)
+	assert.Equal(t, "low", run.Results[2].Properties.ToolSeverity)
+}
+
+func TestWriteVulncheckReports(t *testing.T) {
+	t.Parallel()
+	findings, err := ParseVulncheckOutput([]byte(vulncheckOutput))
+	require.NoError(t, err)
+	findings[2].Assessment = &format.Assessment{Vulnerability: "GO-2022-0969", Status: format.NotRelevant, Analysis: format.NotPresent}
+	files := &mock.FilesMock{}
+
+	reports, err := WriteVulncheckReports(findings, "example.com/app", "1.2.3", files)
+
+	require.NoError(t, err)
+	targets := []string{}
+	for _, report := range reports {
+		targets = append(targets, report.Target)
+	}
+	assert.Equal(t, []string{
+		filepath.Join("govulncheck", "piper_govulncheck_report.json"),
+		filepath.Join("govulncheck", "piper_govulncheck.sarif"),
+		filepath.Join("govulncheck", "GO-2024-2687-golang.org_x_net.md"),
+		filepath.Join("govulncheck", "GO-2023-1988-golang.org_x_net.md"),
+	}, targets)
+
+	content, err := files.FileRead(filepath.Join("govulncheck", "piper_govulncheck_report.json"))
+	require.NoError(t, err)
+	var written []VulnerabilityFinding
+	require.NoError(t, json.Unmarshal(content, &written))
+	assert.Equal(t, findings, written)
+
+	markdown, err := files.FileRead(filepath.Join("govulncheck", "GO-2024-2687-golang.org_x_net.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(markdown), "GO-2024-2687")
+	assert.Contains(t, string(markdown), "golang.org/x/net/http2.Framer.ReadFrame")
+}
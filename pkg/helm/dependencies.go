@@ -0,0 +1,224 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/provenance"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// MirrorDependencies rewrites the repository URLs of the chart dependencies in Chart.yaml and Chart.lock to the mirrors.
+// The mirrors map repository URL prefixes to the URL prefixes of the mirror, the longest matching prefix is used.
+// Since helm refuses a Chart.lock which does not match the dependencies in Chart.yaml, the digest of the Chart.lock is updated as well.
+// The returned function restores the original files once the dependencies are resolved, calling it more than once has no effect.
+func MirrorDependencies(chartPath string, mirrors map[string]string, utils piperutils.FileUtils) ([]string, func() error, error) {
+	originals := map[string][]byte{}
+	written := []string{}
+	restore := func() error {
+		for len(written) > 0 {
+			file := written[0]
+			if err := utils.FileWrite(file, originals[file], 0666); err != nil {
+				return fmt.Errorf("failed to restore %v: %w", file, err)
+			}
+			written = written[1:]
+		}
+		return nil
+	}
+	write := func(file string, original, content []byte) error {
+		originals[file] = original
+		written = append(written, file)
+		if err := utils.FileWrite(file, content, 0666); err != nil {
+			return fmt.Errorf("failed to write %v: %w", file, errors.Join(err, restore()))
+		}
+		return nil
+	}
+
+	rewritten := []string{}
+	chartFile := filepath.Join(chartPath, "Chart.yaml")
+	chartOriginal, err := utils.FileRead(chartFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %v: %w", chartFile, err)
+	}
+	chartContent, chartRewrites, err := rewriteRepositories(chartOriginal, mirrors)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to rewrite repositories in %v: %w", chartFile, err)
+	}
+	rewritten = append(rewritten, chartRewrites...)
+
+	lockFile := filepath.Join(chartPath, "Chart.lock")
+	lockExists, err := utils.FileExists(lockFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check for %v: %w", lockFile, err)
+	}
+	var lockOriginal []byte
+	if lockExists {
+		if lockOriginal, err = utils.FileRead(lockFile); err != nil {
+			return nil, nil, fmt.Errorf("failed to read %v: %w", lockFile, err)
+		}
+	}
+	if len(rewritten) > 0 {
+		if err := write(chartFile, chartOriginal, chartContent); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !lockExists {
+		return rewritten, restore, nil
+	}
+
+	lockContent, lockRewrites, err := rewriteRepositories(lockOriginal, mirrors)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to rewrite repositories in %v: %w", lockFile, errors.Join(err, restore()))
+	}
+	if len(chartRewrites) == 0 && len(lockRewrites) == 0 {
+		return rewritten, restore, nil
+	}
+	if lockContent, err = updateLockDigest(chartContent, lockContent); err != nil {
+		return nil, nil, fmt.Errorf("failed to update digest of %v: %w", lockFile, errors.Join(err, restore()))
+	}
+	if err := write(lockFile, lockOriginal, lockContent); err != nil {
+		return nil, nil, err
+	}
+	for _, repository := range lockRewrites {
+		if !slices.Contains(rewritten, repository) {
+			rewritten = append(rewritten, repository)
+		}
+	}
+	return rewritten, restore, nil
+}
+
+// mirrorRepository returns the repository URL with the longest matching prefix replaced by its mirror
+func mirrorRepository(repository string, mirrors map[string]string) (string, bool) {
+	prefixes := make([]string, 0, len(mirrors))
+	for prefix := range mirrors {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+	for _, prefix := range prefixes {
+		trimmed := strings.TrimSuffix(prefix, "/")
+		if repository == trimmed || strings.HasPrefix(repository, trimmed+"/") {
+			return strings.TrimSuffix(mirrors[prefix], "/") + strings.TrimPrefix(repository, trimmed), true
+		}
+	}
+	return repository, false
+}
+
+// rewriteRepositories replaces the repositories of the dependencies in the document while keeping its formatting and comments
+func rewriteRepositories(content []byte, mirrors map[string]string) ([]byte, []string, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, nil, err
+	}
+	if len(document.Content) == 0 {
+		return content, nil, nil
+	}
+
+	rewritten := []string{}
+	dependencies := mappingValue(document.Content[0], "dependencies")
+	if dependencies == nil || dependencies.Kind != yaml.SequenceNode {
+		return content, rewritten, nil
+	}
+	for _, dependency := range dependencies.Content {
+		repository := mappingValue(dependency, "repository")
+		if repository == nil || repository.Kind != yaml.ScalarNode {
+			continue
+		}
+		if mirror, ok := mirrorRepository(repository.Value, mirrors); ok {
+			log.Entry().Infof("Mirroring dependency repository '%v' to '%v'", repository.Value, mirror)
+			if !slices.Contains(rewritten, repository.Value) {
+				rewritten = append(rewritten, repository.Value)
+			}
+			repository.Value = mirror
+		}
+	}
+	if len(rewritten) == 0 {
+		return content, rewritten, nil
+	}
+
+	rewrittenContent, err := encodeYAML(&document)
+	return rewrittenContent, rewritten, err
+}
+
+// updateLockDigest sets the digest of the lock file the same way as helm, see HashReq in helm.sh/helm/v3/internal/resolver
+func updateLockDigest(chartContent, lockContent []byte) ([]byte, error) {
+	requirements, err := parseDependencies(chartContent)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := parseDependencies(lockContent)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal([2][]*chart.Dependency{requirements, locked})
+	if err != nil {
+		return nil, err
+	}
+	digest, err := provenance.Digest(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(lockContent, &document); err != nil {
+		return nil, err
+	}
+	node := mappingValue(document.Content[0], "digest")
+	if node == nil {
+		return nil, fmt.Errorf("no digest found")
+	}
+	node.Value = "sha256:" + digest
+
+	return encodeYAML(&document)
+}
+
+// parseDependencies reads the dependencies like helm via their JSON representation, so that the digest matches the one calculated by helm
+func parseDependencies(content []byte) ([]*chart.Dependency, error) {
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(map[string]interface{}{"dependencies": document["dependencies"]})
+	if err != nil {
+		return nil, err
+	}
+	var parsed struct {
+		Dependencies []*chart.Dependency `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+	return parsed.Dependencies, nil
+}
+
+func encodeYAML(document *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+//go:build unit
+// +build unit
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+// taken from the test data of helm, pkg/chartutil/testdata/import-values-from-enabled-subchart/parent-chart
+const helmTestChart = `apiVersion: v2
+name: parent-chart
+version: v0.1.0
+appVersion: v0.1.0
+dependencies:
+  - name: dev
+    repository: "file://envs/dev"
+    version: ">= 0.0.1"
+    condition: dev.enabled,global.dev.enabled
+    tags:
+      - dev
+    import-values:
+      - data
+
+  - name: prod
+    repository: "file://envs/prod"
+    version: ">= 0.0.1"
+    condition: prod.enabled,global.prod.enabled
+    tags:
+      - prod
+    import-values:
+      - data
+`
+
+const helmTestLock = `dependencies:
+- name: dev
+  repository: file://envs/dev
+  version: v0.1.0
+- name: prod
+  repository: file://envs/prod
+  version: v0.1.0
+digest: sha256:9403fc24f6cf9d6055820126cf7633b4bd1fed3c77e4880c674059f536346182
+generated: "2020-02-03T10:38:51.180474+01:00"
+`
+
+func TestUpdateLockDigest(t *testing.T) {
+	t.Parallel()
+	content, err := updateLockDigest([]byte(helmTestChart), []byte(helmTestLock))
+
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "digest: sha256:9403fc24f6cf9d6055820126cf7633b4bd1fed3c77e4880c674059f536346182\n", "the digest must match the one calculated by helm")
+}
+
+func TestMirrorDependencies(t *testing.T) {
+	t.Parallel()
+	mirrors := map[string]string{
+		"https://charts.bitnami.com":          "https://nexus.example.com/repository/charts",
+		"https://charts.bitnami.com/bitnami/": "https://nexus.example.com/repository/bitnami/",
+		"oci://registry-1.docker.io":          "oci://mirror.example.com",
+	}
+
+	t.Run("Chart.yaml and Chart.lock", func(t *testing.T) {
+		t.Parallel()
+		files := &mock.FilesMock{}
+		files.AddFile("chart/Chart.yaml", []byte(`apiVersion: v2
+name: app
+version: 1.0.0
+dependencies:
+  # the database
+  - name: postgresql
+    version: 16.x.x
+    repository: https://charts.bitnami.com/bitnami
+  - name: redis
+    version: 21.x.x
+    repository: oci://registry-1.docker.io/bitnamicharts
+  - name: common
+    version: 1.0.0
+    repository: file://../common
+`))
+		files.AddFile("chart/Chart.lock", []byte(`dependencies:
+- name: postgresql
+  repository: https://charts.bitnami.com/bitnami
+  version: 16.7.4
+- name: redis
+  repository: oci://registry-1.docker.io/bitnamicharts
+  version: 21.1.3
+- name: common
+  repository: file://../common
+  version: 1.0.0
+digest: sha256:0000000000000000000000000000000000000000000000000000000000000000
+generated: "2025-05-13T10:00:00.000000+02:00"
+`))
+
+		originalChart, _ := files.FileRead("chart/Chart.yaml")
+		originalLock, _ := files.FileRead("chart/Chart.lock")
+
+		rewritten, restore, err := MirrorDependencies("chart", mirrors, files)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://charts.bitnami.com/bitnami", "oci://registry-1.docker.io/bitnamicharts"}, rewritten)
+		chart, _ := files.FileRead("chart/Chart.yaml")
+		assert.Equal(t, `apiVersion: v2
+name: app
+version: 1.0.0
+dependencies:
+  # the database
+  - name: postgresql
+    version: 16.x.x
+    repository: https://nexus.example.com/repository/bitnami
+  - name: redis
+    version: 21.x.x
+    repository: oci://mirror.example.com/bitnamicharts
+  - name: common
+    version: 1.0.0
+    repository: file://../common
+`, string(chart))
+		lock, _ := files.FileRead("chart/Chart.lock")
+		assert.Contains(t, string(lock), "repository: https://nexus.example.com/repository/bitnami\n")
+		assert.Contains(t, string(lock), "repository: oci://mirror.example.com/bitnamicharts\n")
+		assert.NotContains(t, string(lock), "sha256:0000000000000000000000000000000000000000000000000000000000000000")
+		expected, err := updateLockDigest(chart, lock)
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(lock))
+
+		// helm dependency update rewrites the lock file
+		files.AddFile("chart/Chart.lock", []byte("digest: sha256:1111111111111111111111111111111111111111111111111111111111111111\n"))
+		require.NoError(t, restore())
+		require.NoError(t, restore())
+		chart, _ = files.FileRead("chart/Chart.yaml")
+		assert.Equal(t, string(originalChart), string(chart))
+		lock, _ = files.FileRead("chart/Chart.lock")
+		assert.Equal(t, string(originalLock), string(lock))
+	})
+
+	t.Run("without Chart.lock", func(t *testing.T) {
+		t.Parallel()
+		files := &mock.FilesMock{}
+		files.AddFile("chart/Chart.yaml", []byte("apiVersion: v2\nname: app\nversion: 1.0.0\ndependencies:\n  - name: common\n    repository: https://charts.bitnami.com/common\n"))
+
+		rewritten, restore, err := MirrorDependencies("chart", mirrors, files)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://charts.bitnami.com/common"}, rewritten)
+		chart, _ := files.FileRead("chart/Chart.yaml")
+		assert.Contains(t, string(chart), "repository: https://nexus.example.com/repository/charts/common\n")
+		require.NoError(t, restore())
+		chart, _ = files.FileRead("chart/Chart.yaml")
+		assert.Contains(t, string(chart), "repository: https://charts.bitnami.com/common\n")
+		assert.False(t, files.HasFile("chart/Chart.lock"))
+	})
+
+	t.Run("without matching repositories", func(t *testing.T) {
+		t.Parallel()
+		files := &mock.FilesMock{}
+		files.AddFile("chart/Chart.yaml", []byte(helmTestChart))
+		files.AddFile("chart/Chart.lock", []byte(helmTestLock))
+
+		rewritten, restore, err := MirrorDependencies("chart", mirrors, files)
+
+		require.NoError(t, err)
+		assert.Empty(t, rewritten)
+		assert.NoError(t, restore())
+		lock, _ := files.FileRead("chart/Chart.lock")
+		assert.Equal(t, helmTestLock, string(lock))
+	})
+
+	t.Run("error case: missing Chart.yaml", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := MirrorDependencies("chart", mirrors, &mock.FilesMock{})
+		assert.ErrorContains(t, err, "failed to read chart/Chart.yaml")
+	})
+}
@@ -0,0 +1,200 @@
+package helm
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// VerificationReportsDirectory defines the subfolder for the chart verification reports
+const VerificationReportsDirectory = "helm-verification"
+
+// UnitTestReportFile is the JUnit report written by the chart unit tests
+const UnitTestReportFile = "TEST-helm-unittest.xml"
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// CreateJUnitReport creates a JUnit report with a test suite per scenario and a test case per check.
+// Checks with errors fail, warnings are part of the output of the test case.
+func CreateJUnitReport(chartName string, results []CheckResult, reportTime time.Time) ([]byte, error) {
+	report := junitTestSuites{}
+	suites := map[string]int{}
+	for _, result := range results {
+		index, ok := suites[result.Scenario]
+		if !ok {
+			index = len(report.TestSuites)
+			suites[result.Scenario] = index
+			report.TestSuites = append(report.TestSuites, junitTestSuite{Name: fmt.Sprintf("%v: %v", chartName, result.Scenario), Timestamp: reportTime.Format(time.RFC3339)})
+		}
+		suite := &report.TestSuites[index]
+
+		testCase := junitTestCase{Name: result.Check, ClassName: suite.Name}
+		failures, warnings := []string{}, []string{}
+		for _, finding := range result.Findings {
+			if finding.Severity == SeverityError {
+				failures = append(failures, finding.String())
+			} else {
+				warnings = append(warnings, finding.String())
+			}
+		}
+		if len(failures) > 0 {
+			testCase.Failure = &junitFailure{Message: fmt.Sprintf("%v issue(s) found", len(failures)), Text: strings.Join(failures, "\n")}
+			suite.Failures++
+		}
+		testCase.SystemOut = strings.Join(warnings, "\n")
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	content, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+// CreateScanReport creates a report of the chart verification for the pipeline summary
+func CreateScanReport(chartName string, results []CheckResult, reportTime time.Time) reporting.ScanReport {
+	errorCount := CountFindings(results, SeverityError)
+	warningCount := CountFindings(results, SeverityWarning)
+	failedChecks := 0
+	scenarios := map[string]bool{}
+	for _, result := range results {
+		scenarios[result.Scenario] = true
+		if result.Failed() {
+			failedChecks++
+		}
+	}
+
+	scanReport := reporting.ScanReport{
+		ReportTitle: "Helm Chart Verification Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Chart", Details: chartName},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Rendered scenarios", Details: fmt.Sprint(len(scenarios))},
+			{Description: "Executed checks", Details: fmt.Sprint(len(results))},
+			{Description: "Failed checks", Details: fmt.Sprint(failedChecks), Style: styleIfNotZero(failedChecks, reporting.Red)},
+			{Description: "Errors", Details: fmt.Sprint(errorCount), Style: styleIfNotZero(errorCount, reporting.Red)},
+			{Description: "Warnings", Details: fmt.Sprint(warningCount), Style: styleIfNotZero(warningCount, reporting.Yellow)},
+		},
+		ReportTime:     reportTime,
+		SuccessfulScan: errorCount == 0,
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No issues found",
+		Headers:       []string{"Scenario", "Check", "Resource", "Issue", "Severity"},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			row := reporting.ScanRow{}
+			row.AddColumn(result.Scenario, 0)
+			row.AddColumn(result.Check, 0)
+			row.AddColumn(finding.Resource, 0)
+			row.AddColumn(finding.Message, 0)
+			row.AddColumn(finding.Severity, severityStyle(finding.Severity))
+			detailTable.Rows = append(detailTable.Rows, row)
+		}
+	}
+	scanReport.DetailTable = detailTable
+	return scanReport
+}
+
+func severityStyle(severity string) reporting.ColumnStyle {
+	if severity == SeverityError {
+		return reporting.Red
+	}
+	return reporting.Yellow
+}
+
+func styleIfNotZero(count int, style reporting.ColumnStyle) reporting.ColumnStyle {
+	if count > 0 {
+		return style
+	}
+	return 0
+}
+
+// WriteReports writes the verification results as JSON, HTML and JUnit report as well as the JSON report used by the pipeline summary
+func WriteReports(chartName string, results []CheckResult, reportTime time.Time, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+	if err := utils.MkdirAll(VerificationReportsDirectory, 0777); err != nil {
+		return reportPaths, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	jsonReport, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to marshal results: %w", err)
+	}
+	jsonReportPath := filepath.Join(VerificationReportsDirectory, "piper_helm_verification_report.json")
+	if err := utils.FileWrite(jsonReportPath, jsonReport, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write JSON report: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Helm chart verification JSON report", Target: jsonReportPath})
+
+	scanReport := CreateScanReport(chartName, results, reportTime)
+	htmlReport, err := scanReport.ToHTML()
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to create HTML report: %w", err)
+	}
+	htmlReportPath := filepath.Join(VerificationReportsDirectory, "piper_helm_verification_report.html")
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Helm chart verification report", Target: htmlReportPath})
+
+	junitReport, err := CreateJUnitReport(chartName, results, reportTime)
+	if err != nil {
+		return reportPaths, err
+	}
+	junitReportPath := filepath.Join(VerificationReportsDirectory, "TEST-helm-verification.xml")
+	if err := utils.FileWrite(junitReportPath, junitReport, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Helm chart verification JUnit report", Target: junitReportPath})
+
+	// JSON reports are used by step pipelineCreateScanSummary
+	// ignore JSON errors since structure is in our hands
+	stepReport, _ := scanReport.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return reportPaths, fmt.Errorf("failed to create step reporting directory: %w", err)
+		}
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "helmBuild.json"), stepReport, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write step report: %w", err)
+	}
+
+	return reportPaths, nil
+}
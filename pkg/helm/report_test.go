@@ -0,0 +1,85 @@
+//go:build unit
+// +build unit
+
+package helm
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+var reportTestResults = []CheckResult{
+	{Scenario: "default values", Check: CheckDeprecatedAPIs, Findings: []Finding{{Resource: "HorizontalPodAutoscaler/app", Message: "deprecated", Severity: SeverityWarning}}},
+	{Scenario: "default values", Check: CheckPrivileged, Findings: []Finding{}},
+	{Scenario: "values-prod.yaml", Check: CheckResourceLimits, Findings: []Finding{{Resource: "Deployment/app", Message: "container 'app' has no cpu limit", Severity: SeverityError}}},
+}
+
+func TestCreateJUnitReport(t *testing.T) {
+	t.Parallel()
+	report, err := CreateJUnitReport("app", reportTestResults, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="app: default values" tests="2" failures="0" timestamp="2026-10-19T08:00:00Z">
+    <testcase name="deprecated-apis" classname="app: default values">
+      <system-out>HorizontalPodAutoscaler/app: deprecated</system-out>
+    </testcase>
+    <testcase name="no-privileged-containers" classname="app: default values"></testcase>
+  </testsuite>
+  <testsuite name="app: values-prod.yaml" tests="1" failures="1" timestamp="2026-10-19T08:00:00Z">
+    <testcase name="resource-limits" classname="app: values-prod.yaml">
+      <failure message="1 issue(s) found">Deployment/app: container &#39;app&#39; has no cpu limit</failure>
+    </testcase>
+  </testsuite>
+</testsuites>`, string(report))
+}
+
+func TestCreateScanReport(t *testing.T) {
+	t.Parallel()
+	scanReport := CreateScanReport("app", reportTestResults, time.Now())
+
+	assert.Equal(t, "Helm Chart Verification Report", scanReport.ReportTitle)
+	assert.False(t, scanReport.SuccessfulScan)
+	assert.Equal(t, []reporting.OverviewRow{
+		{Description: "Rendered scenarios", Details: "2"},
+		{Description: "Executed checks", Details: "3"},
+		{Description: "Failed checks", Details: "1", Style: reporting.Red},
+		{Description: "Errors", Details: "1", Style: reporting.Red},
+		{Description: "Warnings", Details: "1", Style: reporting.Yellow},
+	}, scanReport.Overview)
+	require.Len(t, scanReport.DetailTable.Rows, 2)
+	assert.Equal(t, "values-prod.yaml", scanReport.DetailTable.Rows[1].Columns[0].Content)
+
+	assert.True(t, CreateScanReport("app", reportTestResults[:2], time.Now()).SuccessfulScan)
+}
+
+func TestWriteReports(t *testing.T) {
+	t.Parallel()
+	files := &mock.FilesMock{}
+
+	reports, err := WriteReports("app", reportTestResults, time.Now(), files)
+
+	require.NoError(t, err)
+	assert.Len(t, reports, 3)
+	for _, file := range []string{
+		filepath.Join(VerificationReportsDirectory, "piper_helm_verification_report.json"),
+		filepath.Join(VerificationReportsDirectory, "piper_helm_verification_report.html"),
+		filepath.Join(VerificationReportsDirectory, "TEST-helm-verification.xml"),
+		filepath.Join(reporting.StepReportDirectory, "helmBuild.json"),
+	} {
+		assert.True(t, files.HasFile(file), file)
+	}
+	content, _ := files.FileRead(filepath.Join(VerificationReportsDirectory, "piper_helm_verification_report.json"))
+	var results []CheckResult
+	require.NoError(t, json.Unmarshal(content, &results))
+	assert.Equal(t, reportTestResults, results)
+}
@@ -0,0 +1,294 @@
+package helm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"go.yaml.in/yaml/v3"
+)
+
+// Names of the chart checks
+const (
+	CheckRender         = "render"
+	CheckValuesSchema   = "values-schema"
+	CheckDeprecatedAPIs = "deprecated-apis"
+	CheckPrivileged     = "no-privileged-containers"
+	CheckResourceLimits = "resource-limits"
+	CheckUnitTests      = "unit-tests"
+)
+
+// Severities of the findings, only errors fail the verification
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// schemaViolationMessage is part of the helm error output in case the values do not match the values.schema.json of the chart
+const schemaViolationMessage = "values don't meet the specifications of the schema"
+
+// Finding is an issue detected by a chart check
+type Finding struct {
+	Resource string `json:"resource,omitempty"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+func (f Finding) String() string {
+	if len(f.Resource) == 0 {
+		return f.Message
+	}
+	return fmt.Sprintf("%v: %v", f.Resource, f.Message)
+}
+
+// CheckResult contains the findings of a chart check executed for a scenario, i.e. a combination of values file and Kubernetes version
+type CheckResult struct {
+	Scenario string    `json:"scenario"`
+	Check    string    `json:"check"`
+	Findings []Finding `json:"findings"`
+}
+
+// Failed checks whether the check has findings with severity error
+func (c CheckResult) Failed() bool {
+	for _, finding := range c.Findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// CountFindings counts the findings of the given severity
+func CountFindings(results []CheckResult, severity string) int {
+	count := 0
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			if finding.Severity == severity {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// ScenarioName names the combination of values file and Kubernetes version a chart is rendered for
+func ScenarioName(valuesFile, kubeVersion string) string {
+	if len(valuesFile) == 0 {
+		valuesFile = "default values"
+	}
+	if len(kubeVersion) == 0 {
+		return valuesFile
+	}
+	return fmt.Sprintf("%v (Kubernetes %v)", valuesFile, kubeVersion)
+}
+
+// RenderFailure turns the error of rendering the chart into the result of the failed check.
+// Helm validates the values against the values.schema.json while rendering, hence schema violations are reported by the values-schema check.
+func RenderFailure(scenario string, err error) CheckResult {
+	check := CheckRender
+	if strings.Contains(err.Error(), schemaViolationMessage) {
+		check = CheckValuesSchema
+	}
+	return CheckResult{Scenario: scenario, Check: check, Findings: []Finding{{Message: err.Error(), Severity: SeverityError}}}
+}
+
+type manifest struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   map[string]interface{} `yaml:"metadata"`
+	Spec       map[string]interface{} `yaml:"spec"`
+}
+
+func (m manifest) name() string {
+	name, _ := m.Metadata["name"].(string)
+	return fmt.Sprintf("%v/%v", m.Kind, name)
+}
+
+// CheckManifests runs the deprecated API check and the given policy checks against the rendered manifests of a scenario.
+// Without Kubernetes version deprecated APIs are only reported as warnings.
+func CheckManifests(scenario string, manifests []byte, kubeVersion string, policies []string) ([]CheckResult, error) {
+	parsed, err := parseManifests(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []CheckResult{}
+	deprecated, err := checkDeprecatedAPIs(parsed, kubeVersion)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, CheckResult{Scenario: scenario, Check: CheckDeprecatedAPIs, Findings: deprecated})
+
+	for _, policy := range policies {
+		var findings []Finding
+		switch policy {
+		case CheckPrivileged:
+			findings = checkContainers(parsed, privilegedContainer)
+		case CheckResourceLimits:
+			findings = checkContainers(parsed, missingResourceLimits)
+		default:
+			return nil, fmt.Errorf("unknown policy check '%v'", policy)
+		}
+		results = append(results, CheckResult{Scenario: scenario, Check: policy, Findings: findings})
+	}
+	return results, nil
+}
+
+func parseManifests(manifests []byte) ([]manifest, error) {
+	parsed := []manifest{}
+	decoder := yaml.NewDecoder(bytes.NewReader(manifests))
+	for {
+		var m manifest
+		if err := decoder.Decode(&m); err != nil {
+			if errors.Is(err, io.EOF) {
+				return parsed, nil
+			}
+			return nil, fmt.Errorf("failed to parse rendered manifests: %w", err)
+		}
+		if len(m.Kind) > 0 {
+			parsed = append(parsed, m)
+		}
+	}
+}
+
+type deprecatedAPI struct {
+	apiVersion  string
+	kinds       []string
+	deprecated  string
+	removed     string
+	replacement string
+}
+
+// deprecatedAPIs lists the API versions removed from Kubernetes, see https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var deprecatedAPIs = []deprecatedAPI{
+	{apiVersion: "extensions/v1beta1", kinds: []string{"Deployment", "DaemonSet", "ReplicaSet"}, deprecated: "1.8", removed: "1.16", replacement: "apps/v1"},
+	{apiVersion: "extensions/v1beta1", kinds: []string{"NetworkPolicy"}, deprecated: "1.9", removed: "1.16", replacement: "networking.k8s.io/v1"},
+	{apiVersion: "extensions/v1beta1", kinds: []string{"PodSecurityPolicy"}, deprecated: "1.10", removed: "1.16", replacement: "policy/v1beta1"},
+	{apiVersion: "apps/v1beta1", kinds: []string{"Deployment", "StatefulSet", "ReplicaSet"}, deprecated: "1.9", removed: "1.16", replacement: "apps/v1"},
+	{apiVersion: "apps/v1beta2", kinds: []string{"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet"}, deprecated: "1.9", removed: "1.16", replacement: "apps/v1"},
+	{apiVersion: "extensions/v1beta1", kinds: []string{"Ingress"}, deprecated: "1.14", removed: "1.22", replacement: "networking.k8s.io/v1"},
+	{apiVersion: "networking.k8s.io/v1beta1", kinds: []string{"Ingress", "IngressClass"}, deprecated: "1.19", removed: "1.22", replacement: "networking.k8s.io/v1"},
+	{apiVersion: "rbac.authorization.k8s.io/v1beta1", kinds: []string{"Role", "ClusterRole", "RoleBinding", "ClusterRoleBinding"}, deprecated: "1.17", removed: "1.22", replacement: "rbac.authorization.k8s.io/v1"},
+	{apiVersion: "apiextensions.k8s.io/v1beta1", kinds: []string{"CustomResourceDefinition"}, deprecated: "1.16", removed: "1.22", replacement: "apiextensions.k8s.io/v1"},
+	{apiVersion: "admissionregistration.k8s.io/v1beta1", kinds: []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}, deprecated: "1.16", removed: "1.22", replacement: "admissionregistration.k8s.io/v1"},
+	{apiVersion: "scheduling.k8s.io/v1beta1", kinds: []string{"PriorityClass"}, deprecated: "1.14", removed: "1.22", replacement: "scheduling.k8s.io/v1"},
+	{apiVersion: "storage.k8s.io/v1beta1", kinds: []string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"}, deprecated: "1.19", removed: "1.22", replacement: "storage.k8s.io/v1"},
+	{apiVersion: "certificates.k8s.io/v1beta1", kinds: []string{"CertificateSigningRequest"}, deprecated: "1.19", removed: "1.22", replacement: "certificates.k8s.io/v1"},
+	{apiVersion: "coordination.k8s.io/v1beta1", kinds: []string{"Lease"}, deprecated: "1.19", removed: "1.22", replacement: "coordination.k8s.io/v1"},
+	{apiVersion: "apiregistration.k8s.io/v1beta1", kinds: []string{"APIService"}, deprecated: "1.19", removed: "1.22", replacement: "apiregistration.k8s.io/v1"},
+	{apiVersion: "authentication.k8s.io/v1beta1", kinds: []string{"TokenReview"}, deprecated: "1.19", removed: "1.22", replacement: "authentication.k8s.io/v1"},
+	{apiVersion: "authorization.k8s.io/v1beta1", kinds: []string{"SubjectAccessReview", "LocalSubjectAccessReview", "SelfSubjectAccessReview"}, deprecated: "1.19", removed: "1.22", replacement: "authorization.k8s.io/v1"},
+	{apiVersion: "policy/v1beta1", kinds: []string{"PodDisruptionBudget"}, deprecated: "1.21", removed: "1.25", replacement: "policy/v1"},
+	{apiVersion: "policy/v1beta1", kinds: []string{"PodSecurityPolicy"}, deprecated: "1.21", removed: "1.25", replacement: "Pod Security Admission"},
+	{apiVersion: "batch/v1beta1", kinds: []string{"CronJob"}, deprecated: "1.21", removed: "1.25", replacement: "batch/v1"},
+	{apiVersion: "discovery.k8s.io/v1beta1", kinds: []string{"EndpointSlice"}, deprecated: "1.21", removed: "1.25", replacement: "discovery.k8s.io/v1"},
+	{apiVersion: "events.k8s.io/v1beta1", kinds: []string{"Event"}, deprecated: "1.22", removed: "1.25", replacement: "events.k8s.io/v1"},
+	{apiVersion: "node.k8s.io/v1beta1", kinds: []string{"RuntimeClass"}, deprecated: "1.22", removed: "1.25", replacement: "node.k8s.io/v1"},
+	{apiVersion: "autoscaling/v2beta1", kinds: []string{"HorizontalPodAutoscaler"}, deprecated: "1.22", removed: "1.25", replacement: "autoscaling/v2"},
+	{apiVersion: "autoscaling/v2beta2", kinds: []string{"HorizontalPodAutoscaler"}, deprecated: "1.23", removed: "1.26", replacement: "autoscaling/v2"},
+	{apiVersion: "flowcontrol.apiserver.k8s.io/v1beta1", kinds: []string{"FlowSchema", "PriorityLevelConfiguration"}, deprecated: "1.23", removed: "1.26", replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{apiVersion: "storage.k8s.io/v1beta1", kinds: []string{"CSIStorageCapacity"}, deprecated: "1.24", removed: "1.27", replacement: "storage.k8s.io/v1"},
+	{apiVersion: "flowcontrol.apiserver.k8s.io/v1beta2", kinds: []string{"FlowSchema", "PriorityLevelConfiguration"}, deprecated: "1.26", removed: "1.29", replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{apiVersion: "flowcontrol.apiserver.k8s.io/v1beta3", kinds: []string{"FlowSchema", "PriorityLevelConfiguration"}, deprecated: "1.29", removed: "1.32", replacement: "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+func checkDeprecatedAPIs(manifests []manifest, kubeVersion string) ([]Finding, error) {
+	var version *semver.Version
+	if len(kubeVersion) > 0 {
+		v, err := semver.NewVersion(kubeVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Kubernetes version '%v': %w", kubeVersion, err)
+		}
+		version = v
+	}
+
+	findings := []Finding{}
+	for _, m := range manifests {
+		for _, api := range deprecatedAPIs {
+			if api.apiVersion != m.APIVersion || !slices.Contains(api.kinds, m.Kind) {
+				continue
+			}
+			finding := Finding{Resource: m.name(), Severity: SeverityWarning}
+			switch {
+			case version != nil && !version.LessThan(semver.MustParse(api.removed)):
+				finding.Severity = SeverityError
+				finding.Message = fmt.Sprintf("%v %v was removed in Kubernetes %v, use %v instead", m.APIVersion, m.Kind, api.removed, api.replacement)
+			case version != nil && version.LessThan(semver.MustParse(api.deprecated)):
+				continue
+			default:
+				finding.Message = fmt.Sprintf("%v %v is deprecated since Kubernetes %v and removed in %v, use %v instead", m.APIVersion, m.Kind, api.deprecated, api.removed, api.replacement)
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+type containerCheck func(container map[string]interface{}) []string
+
+func privilegedContainer(container map[string]interface{}) []string {
+	securityContext, _ := container["securityContext"].(map[string]interface{})
+	if privileged, _ := securityContext["privileged"].(bool); privileged {
+		return []string{"runs privileged"}
+	}
+	return nil
+}
+
+func missingResourceLimits(container map[string]interface{}) []string {
+	resources, _ := container["resources"].(map[string]interface{})
+	limits, _ := resources["limits"].(map[string]interface{})
+	issues := []string{}
+	for _, resource := range []string{"cpu", "memory"} {
+		if _, ok := limits[resource]; !ok {
+			issues = append(issues, fmt.Sprintf("has no %v limit", resource))
+		}
+	}
+	return issues
+}
+
+// checkContainers runs the check against all containers and init containers of the workloads
+func checkContainers(manifests []manifest, check containerCheck) []Finding {
+	findings := []Finding{}
+	for _, m := range manifests {
+		podSpec := podSpecOf(m)
+		if podSpec == nil {
+			continue
+		}
+		for _, field := range []string{"initContainers", "containers"} {
+			containers, _ := podSpec[field].([]interface{})
+			for _, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _ := container["name"].(string)
+				for _, issue := range check(container) {
+					findings = append(findings, Finding{Resource: m.name(), Message: fmt.Sprintf("container '%v' %v", name, issue), Severity: SeverityError})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func podSpecOf(m manifest) map[string]interface{} {
+	path := []string{}
+	switch m.Kind {
+	case "Pod":
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		path = []string{"template", "spec"}
+	case "CronJob":
+		path = []string{"jobTemplate", "spec", "template", "spec"}
+	default:
+		return nil
+	}
+	spec := m.Spec
+	for _, key := range path {
+		spec, _ = spec[key].(map[string]interface{})
+	}
+	return spec
+}
@@ -0,0 +1,171 @@
+//go:build unit
+// +build unit
+
+package helm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const verifyTestManifests = `---
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: app
+---
+apiVersion: autoscaling/v2beta2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init
+          securityContext:
+            privileged: true
+          resources:
+            limits:
+              cpu: 100m
+              memory: 64Mi
+      containers:
+        - name: app
+          resources:
+            limits:
+              memory: 128Mi
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cleanup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: cleanup
+              resources:
+                limits:
+                  cpu: 100m
+                  memory: 64Mi
+`
+
+func TestCheckManifests(t *testing.T) {
+	t.Parallel()
+	policies := []string{CheckPrivileged, CheckResourceLimits}
+
+	t.Run("with Kubernetes version", func(t *testing.T) {
+		t.Parallel()
+		results, err := CheckManifests("values.yaml (Kubernetes 1.25.0)", []byte(verifyTestManifests), "1.25.0", policies)
+
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, CheckDeprecatedAPIs, results[0].Check)
+		assert.Equal(t, []Finding{
+			{Resource: "PodDisruptionBudget/app", Message: "policy/v1beta1 PodDisruptionBudget was removed in Kubernetes 1.25, use policy/v1 instead", Severity: SeverityError},
+			{Resource: "HorizontalPodAutoscaler/app", Message: "autoscaling/v2beta2 HorizontalPodAutoscaler is deprecated since Kubernetes 1.23 and removed in 1.26, use autoscaling/v2 instead", Severity: SeverityWarning},
+		}, results[0].Findings)
+		assert.Equal(t, CheckPrivileged, results[1].Check)
+		assert.Equal(t, []Finding{{Resource: "Deployment/app", Message: "container 'init' runs privileged", Severity: SeverityError}}, results[1].Findings)
+		assert.Equal(t, CheckResourceLimits, results[2].Check)
+		assert.Equal(t, []Finding{{Resource: "Deployment/app", Message: "container 'app' has no cpu limit", Severity: SeverityError}}, results[2].Findings)
+		for _, result := range results {
+			assert.Equal(t, "values.yaml (Kubernetes 1.25.0)", result.Scenario)
+		}
+	})
+
+	t.Run("deprecations not yet relevant for older Kubernetes version", func(t *testing.T) {
+		t.Parallel()
+		results, err := CheckManifests("default values", []byte(verifyTestManifests), "v1.20.4", nil)
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Empty(t, results[0].Findings)
+		assert.False(t, results[0].Failed())
+	})
+
+	t.Run("without Kubernetes version", func(t *testing.T) {
+		t.Parallel()
+		results, err := CheckManifests("default values", []byte(verifyTestManifests), "", nil)
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Len(t, results[0].Findings, 2)
+		assert.Equal(t, 0, CountFindings(results, SeverityError))
+		assert.Equal(t, 2, CountFindings(results, SeverityWarning))
+	})
+
+	t.Run("APIs removed with Kubernetes 1.22", func(t *testing.T) {
+		t.Parallel()
+		manifests := `---
+apiVersion: certificates.k8s.io/v1beta1
+kind: CertificateSigningRequest
+metadata:
+  name: app
+---
+apiVersion: coordination.k8s.io/v1beta1
+kind: Lease
+metadata:
+  name: app
+---
+apiVersion: apiregistration.k8s.io/v1beta1
+kind: APIService
+metadata:
+  name: v1beta1.metrics.k8s.io
+---
+apiVersion: extensions/v1beta1
+kind: NetworkPolicy
+metadata:
+  name: app
+`
+		results, err := CheckManifests("default values", []byte(manifests), "1.22.0", nil)
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, []Finding{
+			{Resource: "CertificateSigningRequest/app", Message: "certificates.k8s.io/v1beta1 CertificateSigningRequest was removed in Kubernetes 1.22, use certificates.k8s.io/v1 instead", Severity: SeverityError},
+			{Resource: "Lease/app", Message: "coordination.k8s.io/v1beta1 Lease was removed in Kubernetes 1.22, use coordination.k8s.io/v1 instead", Severity: SeverityError},
+			{Resource: "APIService/v1beta1.metrics.k8s.io", Message: "apiregistration.k8s.io/v1beta1 APIService was removed in Kubernetes 1.22, use apiregistration.k8s.io/v1 instead", Severity: SeverityError},
+			{Resource: "NetworkPolicy/app", Message: "extensions/v1beta1 NetworkPolicy was removed in Kubernetes 1.16, use networking.k8s.io/v1 instead", Severity: SeverityError},
+		}, results[0].Findings)
+	})
+
+	t.Run("error cases", func(t *testing.T) {
+		t.Parallel()
+		_, err := CheckManifests("default values", []byte(verifyTestManifests), "latest", nil)
+		assert.ErrorContains(t, err, "invalid Kubernetes version 'latest'")
+
+		_, err = CheckManifests("default values", []byte(verifyTestManifests), "", []string{"no-host-network"})
+		assert.EqualError(t, err, "unknown policy check 'no-host-network'")
+
+		_, err = CheckManifests("default values", []byte("kind: [Deployment"), "", nil)
+		assert.ErrorContains(t, err, "failed to parse rendered manifests")
+	})
+}
+
+func TestRenderFailure(t *testing.T) {
+	t.Parallel()
+	result := RenderFailure("default values", errors.New("failed to execute helm template: exit status 1: Error: values don't meet the specifications of the schema(s) in the following chart(s):\napp:\n- replicas: Invalid type. Expected: integer, given: string"))
+	assert.Equal(t, CheckValuesSchema, result.Check)
+	assert.True(t, result.Failed())
+
+	result = RenderFailure("default values", errors.New("failed to execute helm template: exit status 1: Error: parse error"))
+	assert.Equal(t, CheckRender, result.Check)
+}
+
+func TestScenarioName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "default values", ScenarioName("", ""))
+	assert.Equal(t, "values-prod.yaml", ScenarioName("values-prod.yaml", ""))
+	assert.Equal(t, "default values (Kubernetes 1.33.0)", ScenarioName("", "1.33.0"))
+}
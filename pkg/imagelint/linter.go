@@ -0,0 +1,291 @@
+package imagelint
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/SAP/jenkins-library/pkg/secretscan"
+)
+
+// IDs of the rules the image is checked against
+const (
+	RuleRootUser            = "root-user"
+	RuleMissingHealthcheck  = "missing-healthcheck"
+	RuleSecretInEnv         = "secret-in-env"
+	RuleSecretInLabel       = "secret-in-label"
+	RuleSecretInFile        = "secret-in-file"
+	RuleLargeLayer          = "large-layer"
+	RuleDisallowedBaseImage = "disallowed-base-image"
+	RuleUnknownBaseImage    = "unknown-base-image"
+)
+
+// BaseImageAnnotation is the OCI annotation, or label, naming the base image of an image
+const BaseImageAnnotation = "org.opencontainers.image.base.name"
+
+// Rule describes a check of the linter
+type Rule struct {
+	ID          string
+	Description string
+	Severity    string
+	Help        string
+}
+
+// Rules returns all rules of the linter
+func Rules() []Rule {
+	return []Rule{
+		{ID: RuleRootUser, Description: "Image runs as root user", Severity: "medium", Help: "Add a USER instruction with a non-root user to the Dockerfile."},
+		{ID: RuleMissingHealthcheck, Description: "Image has no health check", Severity: "low", Help: "Add a HEALTHCHECK instruction to the Dockerfile, unless the health is checked by the orchestrator."},
+		{ID: RuleSecretInEnv, Description: "Secret in environment variable", Severity: "high", Help: "Remove the secret from the image and provide it at runtime, e.g. via a secret store."},
+		{ID: RuleSecretInLabel, Description: "Secret in label", Severity: "high", Help: "Remove the secret from the labels of the image."},
+		{ID: RuleSecretInFile, Description: "Secret in image file", Severity: "high", Help: "Remove the file from the image, e.g. via .dockerignore or a multi-stage build, and rotate the secret. Deleting it in a later layer does not remove it from the image."},
+		{ID: RuleLargeLayer, Description: "Large image layer", Severity: "low", Help: "Reduce the size of the layer, e.g. by cleaning up package manager caches in the same instruction or using a multi-stage build."},
+		{ID: RuleDisallowedBaseImage, Description: "Disallowed base image", Severity: "high", Help: "Build the image on top of an approved base image."},
+		{ID: RuleUnknownBaseImage, Description: "Unknown base image", Severity: "medium", Help: "Set the annotation or label " + BaseImageAnnotation + " when building the image, e.g. with docker buildx build or via LABEL in the Dockerfile."},
+	}
+}
+
+// Finding is a violation of a rule detected in an image
+type Finding struct {
+	RuleID   string `json:"ruleId"`
+	Severity string `json:"severity"`
+	Image    string `json:"image"`
+	Message  string `json:"message"`
+	// Location within the image, e.g. the configuration entry or the layer and file
+	Location    string `json:"location"`
+	File        string `json:"file,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Options to configure the linter
+type Options struct {
+	// MaxLayerSize is the maximal compressed size of a layer in bytes, 0 means no limit
+	MaxLayerSize int64
+	// DisallowedBaseImages are glob patterns of base images which must not be used, e.g. "docker.io/library/centos:*"
+	DisallowedBaseImages []string
+	// ExcludeRules are the IDs of rules which are not checked
+	ExcludeRules []string
+	// ScanFiles enables the secret detection in the files of the layers
+	ScanFiles bool
+	// MaxFileSize is the maximal size of a file in bytes to be scanned for secrets, 0 means no limit
+	MaxFileSize int64
+}
+
+// Linter checks the configuration and layers of images without running them
+type Linter struct {
+	options Options
+	scanner *secretscan.Scanner
+}
+
+var secretNamePattern = regexp.MustCompile(`(?i)(password|passwd|pwd|secret|token|api_?key|access_?key|private_?key|credentials?)$`)
+
+// files which contain credentials by definition
+var sensitiveFiles = []string{"**/id_rsa", "**/id_dsa", "**/id_ecdsa", "**/id_ed25519", "**/.git-credentials", "**/.netrc", "**/.pgpass", "**/.aws/credentials"}
+
+// NewLinter creates a linter, secrets are detected with the default rules of the secret scan
+func NewLinter(options Options) (*Linter, error) {
+	scanner, err := secretscan.NewScanner(secretscan.DefaultRules(), secretscan.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return &Linter{options: options, scanner: scanner}, nil
+}
+
+// Lint checks the image against all rules which are not excluded
+func (l *Linter) Lint(imageName string, image v1.Image) ([]Finding, error) {
+	config, err := image.ConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration of image '%v': %w", imageName, err)
+	}
+	manifest, err := image.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest of image '%v': %w", imageName, err)
+	}
+
+	findings := []Finding{}
+	add := func(finding Finding) {
+		if slices.Contains(l.options.ExcludeRules, finding.RuleID) {
+			return
+		}
+		finding.Image = imageName
+		finding.Severity = severity(finding.RuleID)
+		hash := sha256.Sum256([]byte(finding.RuleID + ":" + finding.Location + ":" + finding.Message))
+		finding.Fingerprint = hex.EncodeToString(hash[:16])
+		findings = append(findings, finding)
+	}
+
+	l.lintConfig(config, manifest, add)
+	if err := l.lintLayers(image, config, add); err != nil {
+		return nil, fmt.Errorf("failed to inspect layers of image '%v': %w", imageName, err)
+	}
+	return findings, nil
+}
+
+func (l *Linter) lintConfig(config *v1.ConfigFile, manifest *v1.Manifest, add func(Finding)) {
+	user := strings.SplitN(config.Config.User, ":", 2)[0]
+	if user == "" || user == "root" || user == "0" {
+		add(Finding{RuleID: RuleRootUser, Location: "config/User", Message: "The image runs as root user since no other user is configured"})
+	}
+
+	healthcheck := config.Config.Healthcheck
+	if healthcheck == nil || len(healthcheck.Test) == 0 || healthcheck.Test[0] == "NONE" {
+		add(Finding{RuleID: RuleMissingHealthcheck, Location: "config/Healthcheck", Message: "The image does not define a health check"})
+	}
+
+	for _, env := range config.Config.Env {
+		key, value, _ := strings.Cut(env, "=")
+		if l.isSecret(key, value) {
+			add(Finding{RuleID: RuleSecretInEnv, Location: "config/Env/" + key, Message: fmt.Sprintf("Environment variable %v contains a secret", key)})
+		}
+	}
+	labels := slices.Sorted(maps.Keys(config.Config.Labels))
+	for _, key := range labels {
+		if l.isSecret(key, config.Config.Labels[key]) {
+			add(Finding{RuleID: RuleSecretInLabel, Location: "config/Labels/" + key, Message: fmt.Sprintf("Label %v contains a secret", key)})
+		}
+	}
+
+	baseImage := manifest.Annotations[BaseImageAnnotation]
+	if len(baseImage) == 0 {
+		baseImage = config.Config.Labels[BaseImageAnnotation]
+	}
+	if len(l.options.DisallowedBaseImages) == 0 {
+		return
+	}
+	if len(baseImage) == 0 {
+		// without the base image the image cannot be proven to be built on an allowed one
+		add(Finding{RuleID: RuleUnknownBaseImage, Location: "annotations/" + BaseImageAnnotation, Message: fmt.Sprintf("The base image cannot be checked since the image carries neither the annotation nor the label %v", BaseImageAnnotation)})
+		return
+	}
+	for _, pattern := range l.options.DisallowedBaseImages {
+		if matched, _ := path.Match(pattern, baseImage); matched || pattern == baseImage {
+			add(Finding{RuleID: RuleDisallowedBaseImage, Location: "annotations/" + BaseImageAnnotation, Message: fmt.Sprintf("The base image %v matches the disallowed pattern %v", baseImage, pattern)})
+			break
+		}
+	}
+}
+
+// isSecret detects secrets by the name of the variable or the value itself, references to secrets like file paths are ignored
+func (l *Linter) isSecret(name, value string) bool {
+	if len(value) == 0 || strings.HasPrefix(value, "$") || strings.HasPrefix(value, "/") {
+		return false
+	}
+	if secretNamePattern.MatchString(strings.ReplaceAll(name, ".", "_")) {
+		return true
+	}
+	return len(l.scanner.ScanLine(name, 1, value)) > 0
+}
+
+func (l *Linter) lintLayers(image v1.Image, config *v1.ConfigFile, add func(Finding)) error {
+	layers, err := image.Layers()
+	if err != nil {
+		return err
+	}
+	createdBy := layerHistory(config)
+	for i, layer := range layers {
+		location := fmt.Sprintf("layer %v", i+1)
+		if l.options.MaxLayerSize > 0 && !slices.Contains(l.options.ExcludeRules, RuleLargeLayer) {
+			size, err := layer.Size()
+			if err != nil {
+				return fmt.Errorf("failed to read size of %v: %w", location, err)
+			}
+			if size > l.options.MaxLayerSize {
+				message := fmt.Sprintf("The compressed size of %v is %v bytes which exceeds the maximum of %v bytes", location, size, l.options.MaxLayerSize)
+				if i < len(createdBy) && len(createdBy[i]) > 0 {
+					message = fmt.Sprintf("%v, created by: %v", message, createdBy[i])
+				}
+				add(Finding{RuleID: RuleLargeLayer, Location: location, Message: message})
+			}
+		}
+		if l.options.ScanFiles && !slices.Contains(l.options.ExcludeRules, RuleSecretInFile) {
+			if err := l.scanLayerFiles(layer, location, add); err != nil {
+				return fmt.Errorf("failed to read files of %v: %w", location, err)
+			}
+		}
+	}
+	return nil
+}
+
+// layerHistory returns the instructions which created the layers, history entries without a layer are skipped
+func layerHistory(config *v1.ConfigFile) []string {
+	createdBy := []string{}
+	for _, history := range config.History {
+		if !history.EmptyLayer {
+			createdBy = append(createdBy, history.CreatedBy)
+		}
+	}
+	return createdBy
+}
+
+func (l *Linter) scanLayerFiles(layer v1.Layer, location string, add func(Finding)) error {
+	content, err := layer.Uncompressed()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	reader := tar.NewReader(content)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg || strings.HasPrefix(path.Base(header.Name), ".wh.") {
+			continue
+		}
+		file := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		if isSensitiveFile(file) && header.Size > 0 {
+			add(Finding{RuleID: RuleSecretInFile, Location: fmt.Sprintf("%v: %v", location, file), File: file, Message: fmt.Sprintf("The file %v contains credentials", file)})
+			continue
+		}
+		if l.options.MaxFileSize > 0 && header.Size > l.options.MaxFileSize {
+			continue
+		}
+		secrets, err := l.scanner.ScanReader(file, reader)
+		if err != nil {
+			return err
+		}
+		for _, secret := range secrets {
+			add(Finding{
+				RuleID:   RuleSecretInFile,
+				Location: fmt.Sprintf("%v: %v:%v", location, file, secret.Line),
+				File:     file,
+				Line:     secret.Line,
+				Message:  fmt.Sprintf("%v detected in %v: %v", secret.Description, file, secret.Redacted),
+			})
+		}
+	}
+}
+
+func isSensitiveFile(file string) bool {
+	for _, pattern := range sensitiveFiles {
+		if matched, _ := doublestar.Match(pattern, file); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func severity(ruleID string) string {
+	for _, rule := range Rules() {
+		if rule.ID == ruleID {
+			return rule.Severity
+		}
+	}
+	return "medium"
+}
@@ -0,0 +1,139 @@
+package imagelint
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// ReportsDirectory defines the subfolder for the image lint reports which are generated
+const ReportsDirectory = "imagelint"
+
+// CreateSarif creates a SARIF result from the findings. Findings in the image configuration are located in the Dockerfile.
+func CreateSarif(findings []Finding, dockerfile string) *format.SARIF {
+	sarif := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+	}
+	run := format.Runs{
+		Results: []format.Results{},
+		Tool: format.Tool{Driver: format.Driver{
+			Name:           "Piper image lint",
+			InformationUri: "https://www.project-piper.io/steps/imageLint/",
+			Rules:          []format.SarifRule{},
+		}},
+		ThreadFlowLocations: []format.Locations{},
+	}
+
+	ruleIndices := map[string]int{}
+	for _, rule := range Rules() {
+		ruleIndices[rule.ID] = len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, format.SarifRule{
+			ID:                   rule.ID,
+			Name:                 rule.ID,
+			ShortDescription:     &format.Message{Text: rule.Description},
+			FullDescription:      &format.Message{Text: rule.Description},
+			DefaultConfiguration: &format.DefaultConfiguration{Level: sarifLevel(rule.Severity)},
+			Help:                 &format.Help{Text: rule.Help},
+			Properties:           &format.SarifRuleProperties{Tags: []string{"container", "image"}, SecuritySeverity: securitySeverity(rule.Severity)},
+		})
+	}
+
+	for _, finding := range findings {
+		uri := finding.File
+		if len(uri) == 0 {
+			uri = dockerfile
+		}
+		run.Results = append(run.Results, format.Results{
+			RuleID:    finding.RuleID,
+			RuleIndex: ruleIndices[finding.RuleID],
+			Level:     sarifLevel(finding.Severity),
+			Message:   &format.Message{Text: fmt.Sprintf("%v (image %v)", finding.Message, finding.Image)},
+			Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{
+				ArtifactLocation: format.ArtifactLocation{URI: uri},
+				Region:           format.Region{StartLine: max(finding.Line, 1)},
+				LogicalLocations: []format.LogicalLocation{{FullyQualifiedName: finding.Image + " " + finding.Location}},
+			}}},
+			PartialFingerprints: format.PartialFingerprints{PrimaryLocationLineHash: finding.Fingerprint},
+			Properties: &format.SarifProperties{
+				ToolSeverity:      finding.Severity,
+				InstanceSeverity:  finding.Severity,
+				ToolState:         "Unreviewed",
+				UnifiedAuditState: "new",
+			},
+		})
+	}
+	sarif.Runs = append(sarif.Runs, run)
+	return &sarif
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case "high":
+		return "error"
+	case "low":
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+func securitySeverity(severity string) string {
+	switch severity {
+	case "high":
+		return "8.0"
+	case "low":
+		return "2.0"
+	default:
+		return "5.0"
+	}
+}
+
+// SeverityRank orders the severities, higher values are more severe. Unknown severities are ranked lowest.
+func SeverityRank(severity string) int {
+	switch severity {
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// WriteReports writes the findings as JSON and SARIF report
+func WriteReports(findings []Finding, dockerfile string, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+	if err := utils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return reportPaths, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	jsonReport, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to marshal findings: %w", err)
+	}
+	jsonReportPath := filepath.Join(ReportsDirectory, "piper_imagelint_report.json")
+	if err := utils.FileWrite(jsonReportPath, jsonReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, fmt.Errorf("failed to write JSON report: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Image lint JSON report", Target: jsonReportPath})
+
+	sarifReport, err := json.Marshal(CreateSarif(findings, dockerfile))
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to marshal SARIF report: %w", err)
+	}
+	sarifReportPath := filepath.Join(ReportsDirectory, "piper_imagelint.sarif")
+	if err := utils.FileWrite(sarifReportPath, sarifReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, fmt.Errorf("failed to write SARIF file: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Image lint SARIF file", Target: sarifReportPath})
+	return reportPaths, nil
+}
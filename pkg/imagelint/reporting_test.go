@@ -0,0 +1,56 @@
+//go:build unit
+
+package imagelint
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSarif(t *testing.T) {
+	t.Parallel()
+	findings := []Finding{
+		{RuleID: RuleRootUser, Severity: "medium", Image: "app:1.0.0", Message: "root", Location: "config/User", Fingerprint: "fp1"},
+		{RuleID: RuleSecretInFile, Severity: "high", Image: "app:1.0.0", Message: "secret", Location: "layer 1: app/.env:3", File: "app/.env", Line: 3, Fingerprint: "fp2"},
+	}
+
+	sarif := CreateSarif(findings, "docker/Dockerfile")
+
+	require.Len(t, sarif.Runs, 1)
+	assert.Len(t, sarif.Runs[0].Tool.Driver.Rules, len(Rules()))
+	require.Len(t, sarif.Runs[0].Results, 2)
+	config := sarif.Runs[0].Results[0]
+	assert.Equal(t, "warning", config.Level)
+	assert.Equal(t, "root (image app:1.0.0)", config.Message.Text)
+	assert.Equal(t, "docker/Dockerfile", config.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 1, config.Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, "app:1.0.0 config/User", config.Locations[0].PhysicalLocation.LogicalLocations[0].FullyQualifiedName)
+	file := sarif.Runs[0].Results[1]
+	assert.Equal(t, "error", file.Level)
+	assert.Equal(t, 4, file.RuleIndex)
+	assert.Equal(t, "app/.env", file.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 3, file.Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, "fp2", file.PartialFingerprints.PrimaryLocationLineHash)
+}
+
+func TestWriteReports(t *testing.T) {
+	t.Parallel()
+	files := &mock.FilesMock{}
+	findings := []Finding{{RuleID: RuleRootUser, Severity: "medium", Image: "app:1.0.0", Location: "config/User"}}
+
+	reports, err := WriteReports(findings, "Dockerfile", files)
+
+	assert.NoError(t, err)
+	assert.Len(t, reports, 2)
+	content, err := files.FileRead("imagelint/piper_imagelint.sarif")
+	require.NoError(t, err)
+	sarif := format.SARIF{}
+	require.NoError(t, json.Unmarshal(content, &sarif))
+	assert.Equal(t, RuleRootUser, sarif.Runs[0].Results[0].RuleID)
+	assert.True(t, files.HasFile("imagelint/piper_imagelint_report.json"))
+}
@@ -24,6 +24,8 @@ type HelmExecutor interface {
 	RunHelmPublish() (string, error)
 	RunHelmDependency() error
 	RunHelmTemplate() ([]byte, error)
+	RunHelmTemplateFor(valuesFiles []string, kubeVersion string) ([]byte, error)
+	RunHelmUnitTest(junitFile string) error
 }
 
 // HelmExecute struct
@@ -66,6 +68,12 @@ type HelmExecuteOptions struct {
 	HelmCommand               string   `json:"helmCommand,omitempty"`
 	CustomTLSCertificateLinks []string `json:"customTlsCertificateLinks,omitempty"`
 	RenderSubchartNotes       bool     `json:"renderSubchartNotes,omitempty"`
+	SignChart                 bool     `json:"signChart,omitempty"`
+	SigningKey                string   `json:"signingKey,omitempty"`
+	SigningKeyring            string   `json:"signingKeyring,omitempty"`
+	SigningPassphrase         string   `json:"signingPassphrase,omitempty"`
+	VerifyDependencies        bool     `json:"verifyDependencies,omitempty"`
+	DependencyKeyring         string   `json:"dependencyKeyring,omitempty"`
 }
 
 // NewHelmExecutor creates HelmExecute instance
@@ -341,6 +349,14 @@ func (h *HelmExecute) runHelmPackage() error {
 	if len(h.config.AppVersion) > 0 {
 		helmParams = append(helmParams, "--app-version", h.config.AppVersion)
 	}
+	if h.config.SignChart {
+		signingParams, cleanup, err := h.signingParams()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		helmParams = append(helmParams, signingParams...)
+	}
 	if h.verbose {
 		helmParams = append(helmParams, "--debug")
 	}
@@ -352,6 +368,39 @@ func (h *HelmExecute) runHelmPackage() error {
 	return nil
 }
 
+// signingParams returns the parameters to create a provenance file (.prov) signed with the PGP key while packaging.
+// Since helm reads the passphrase only from a terminal or a file, it is passed in a temporary file which is removed by the returned cleanup function.
+func (h *HelmExecute) signingParams() ([]string, func(), error) {
+	if len(h.config.SigningKey) == 0 || len(h.config.SigningKeyring) == 0 {
+		return nil, nil, fmt.Errorf("signing the chart requires signingKey and signingKeyring")
+	}
+	params := []string{"--sign", "--key", h.config.SigningKey, "--keyring", h.config.SigningKeyring}
+	if len(h.config.SigningPassphrase) == 0 {
+		return params, func() {}, nil
+	}
+
+	tmpDir, err := h.utils.TempDir("", "helm-signing")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	passphraseFile := filepath.Join(tmpDir, "passphrase")
+	cleanup := func() {
+		if exists, _ := h.utils.FileExists(passphraseFile); exists {
+			if err := h.utils.FileRemove(passphraseFile); err != nil {
+				log.Entry().Warnf("failed to remove passphrase file %v: %v", passphraseFile, err)
+			}
+		}
+		if err := h.utils.RemoveAll(tmpDir); err != nil {
+			log.Entry().Warnf("failed to remove temporary directory %v: %v", tmpDir, err)
+		}
+	}
+	if err := h.utils.FileWrite(passphraseFile, []byte(h.config.SigningPassphrase), 0600); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to write passphrase file: %w", err)
+	}
+	return append(params, "--passphrase-file", passphraseFile), cleanup, nil
+}
+
 // RunHelmTemplate renders the chart locally (`helm template`) and returns the
 // rendered multi-document manifest YAML captured from stdout. Used for SBOM
 // image discovery. Stdout is temporarily redirected to a buffer for the
@@ -365,30 +414,89 @@ func (h *HelmExecute) RunHelmTemplate() ([]byte, error) {
 		return nil, fmt.Errorf("failed to execute deployments: %v", err)
 	}
 
+	helmParams := h.templateParams(h.config.HelmValues, "")
+
+	var buf bytes.Buffer
+	h.utils.Stdout(&buf)
+	defer h.utils.Stdout(h.stdout)
+
+	if err := h.utils.RunExecutable("helm", helmParams...); err != nil {
+		return nil, fmt.Errorf("failed to execute helm template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// RunHelmTemplateFor renders the chart locally like RunHelmTemplate, with the
+// given values files applied on top of the configured helm values and the
+// capabilities of the given Kubernetes version (`--kube-version`). Helm
+// validates the values against the values.schema.json of the chart while
+// rendering, the error output of helm is therefore part of the returned error.
+func (h *HelmExecute) RunHelmTemplateFor(valuesFiles []string, kubeVersion string) ([]byte, error) {
+	if len(h.config.ChartPath) == 0 {
+		return nil, fmt.Errorf("there is no ChartPath value. The chartPath value is mandatory")
+	}
+
+	helmParams := h.templateParams(append(append([]string{}, h.config.HelmValues...), valuesFiles...), kubeVersion)
+
+	var buf, errBuf bytes.Buffer
+	h.utils.Stdout(&buf)
+	h.utils.Stderr(io.MultiWriter(&errBuf, h.stdout))
+	defer h.utils.Stdout(h.stdout)
+	defer h.utils.Stderr(h.stdout)
+
+	if err := h.utils.RunExecutable("helm", helmParams...); err != nil {
+		if details := strings.TrimSpace(errBuf.String()); len(details) > 0 {
+			return nil, fmt.Errorf("failed to execute helm template: %w: %v", err, details)
+		}
+		return nil, fmt.Errorf("failed to execute helm template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (h *HelmExecute) templateParams(valuesFiles []string, kubeVersion string) []string {
 	helmParams := []string{
 		"template",
 		h.config.DeploymentName,
 		h.config.ChartPath,
 	}
-	for _, value := range h.config.HelmValues {
+	for _, value := range valuesFiles {
 		helmParams = append(helmParams, "--values", value)
 	}
 	if len(h.config.Namespace) > 0 {
 		helmParams = append(helmParams, "--namespace", h.config.Namespace)
 	}
+	if len(kubeVersion) > 0 {
+		helmParams = append(helmParams, "--kube-version", kubeVersion)
+	}
 	if h.verbose {
 		helmParams = append(helmParams, "--debug")
 	}
+	return helmParams
+}
 
-	var buf bytes.Buffer
-	h.utils.Stdout(&buf)
-	defer h.utils.Stdout(h.stdout)
+// RunHelmUnitTest runs the unit tests of the chart with the helm-unittest plugin
+// and writes the test results as JUnit report
+func (h *HelmExecute) RunHelmUnitTest(junitFile string) error {
+	if len(h.config.ChartPath) == 0 {
+		return fmt.Errorf("there is no ChartPath value. The chartPath value is mandatory")
+	}
 
+	helmParams := []string{
+		"unittest",
+		"--output-type", "JUnit",
+		"--output-file", junitFile,
+		h.config.ChartPath,
+	}
+
+	h.utils.Stdout(h.stdout)
+	log.Entry().Debugf("Helm parameters: %v", helmParams)
 	if err := h.utils.RunExecutable("helm", helmParams...); err != nil {
-		return nil, fmt.Errorf("failed to execute helm template: %w", err)
+		return fmt.Errorf("failed to execute helm unittest: %w", err)
 	}
 
-	return buf.Bytes(), nil
+	return nil
 }
 
 // RunHelmTest is used to run tests for a release
@@ -437,6 +545,13 @@ func (h *HelmExecute) RunHelmDependency() error {
 
 	helmParams = append(helmParams, h.config.Dependency, h.config.ChartPath)
 
+	if h.config.VerifyDependencies && h.config.Dependency != "list" {
+		helmParams = append(helmParams, "--verify")
+		if len(h.config.DependencyKeyring) > 0 {
+			helmParams = append(helmParams, "--keyring", h.config.DependencyKeyring)
+		}
+	}
+
 	if len(h.config.AdditionalParameters) > 0 {
 		helmParams = append(helmParams, h.config.AdditionalParameters...)
 	}
@@ -476,6 +591,12 @@ func (h *HelmExecute) RunHelmPublish() (string, error) {
 		return "", fmt.Errorf("there's no target repository for helm chart publishing configured")
 	}
 
+	binary := fmt.Sprintf("%s-%s.tgz", h.config.DeploymentName, h.config.PublishVersion)
+
+	if strings.HasPrefix(h.config.TargetRepositoryURL, "oci://") {
+		return h.runHelmPush(binary)
+	}
+
 	repoClientOptions := piperhttp.ClientOptions{
 		Username:     h.config.TargetRepositoryUser,
 		Password:     h.config.TargetRepositoryPassword,
@@ -484,8 +605,6 @@ func (h *HelmExecute) RunHelmPublish() (string, error) {
 
 	h.utils.SetOptions(repoClientOptions)
 
-	binary := fmt.Sprintf("%s-%s.tgz", h.config.DeploymentName, h.config.PublishVersion)
-
 	separator := "/"
 
 	if strings.HasSuffix(h.config.TargetRepositoryURL, "/") {
@@ -505,9 +624,51 @@ func (h *HelmExecute) RunHelmPublish() (string, error) {
 		return "", fmt.Errorf("couldn't upload artifact, received status code %d", response.StatusCode)
 	}
 
+	if h.config.SignChart {
+		provenanceFile := binary + ".prov"
+		log.Entry().Infof("publishing provenance file: %s.prov", targetURL)
+		response, err := h.utils.UploadRequest(http.MethodPut, targetURL+".prov", provenanceFile, "", nil, nil, "binary")
+		if err != nil {
+			return "", fmt.Errorf("couldn't upload provenance file: %w", err)
+		}
+		if !(response.StatusCode == 200 || response.StatusCode == 201) {
+			return "", fmt.Errorf("couldn't upload provenance file, received status code %d", response.StatusCode)
+		}
+	}
+
 	return targetURL, nil
 }
 
+// runHelmPush pushes the packaged chart to an OCI registry, helm pushes the provenance file of a signed chart together with the chart.
+// The reference of the pushed chart is returned.
+func (h *HelmExecute) runHelmPush(binary string) (string, error) {
+	repository := strings.TrimSuffix(h.config.TargetRepositoryURL, "/")
+	registryParams := []string{}
+	if len(h.config.DockerConfigJSON) > 0 {
+		registryParams = append(registryParams, "--registry-config", h.config.DockerConfigJSON)
+	}
+
+	if len(h.config.TargetRepositoryUser) > 0 {
+		host, _, _ := strings.Cut(strings.TrimPrefix(repository, "oci://"), "/")
+		loginParams := append([]string{"registry", "login", host, "--username", h.config.TargetRepositoryUser, "--password", h.config.TargetRepositoryPassword}, registryParams...)
+		if err := h.runHelmCommand(loginParams); err != nil {
+			return "", fmt.Errorf("couldn't log in to registry '%v': %w", host, err)
+		}
+	}
+
+	helmParams := append([]string{"push", binary, repository}, registryParams...)
+	if h.verbose {
+		helmParams = append(helmParams, "--debug")
+	}
+	log.Entry().Infof("publishing artifact: %s", repository)
+	if err := h.runHelmCommand(helmParams); err != nil {
+		return "", fmt.Errorf("couldn't push chart: %w", err)
+	}
+
+	// OCI tags must not contain '+', helm replaces it with '_'
+	return fmt.Sprintf("%s/%s:%s", repository, h.config.DeploymentName, strings.ReplaceAll(h.config.PublishVersion, "+", "_")), nil
+}
+
 func (h *HelmExecute) runHelmCommand(helmParams []string) error {
 
 	h.utils.Stdout(h.stdout)
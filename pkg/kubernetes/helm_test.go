@@ -452,6 +452,18 @@ func TestRunHelmPackage(t *testing.T) {
 				{Exec: "helm", Params: []string{"package", ".", "--version", "1.2.3", "--dependency-update", "--app-version", "9.8.7"}},
 			},
 		},
+		{
+			config: HelmExecuteOptions{
+				ChartPath:      ".",
+				DeploymentName: "testPackage",
+				SignChart:      true,
+				SigningKey:     "helm@example.com",
+				SigningKeyring: "secring.gpg",
+			},
+			expectedExecCalls: []mock.ExecCall{
+				{Exec: "helm", Params: []string{"package", ".", "--sign", "--key", "helm@example.com", "--keyring", "secring.gpg"}},
+			},
+		},
 	}
 
 	for i, testCase := range testTable {
@@ -472,6 +484,36 @@ func TestRunHelmPackage(t *testing.T) {
 	}
 }
 
+func TestRunHelmPackageSigning(t *testing.T) {
+	t.Run("with passphrase", func(t *testing.T) {
+		files := &mock.FilesMock{}
+		utils := helmMockUtilsBundle{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: files}
+		helmExecute := HelmExecute{
+			utils:  utils,
+			config: HelmExecuteOptions{ChartPath: ".", SignChart: true, SigningKey: "helm@example.com", SigningKeyring: "secring.gpg", SigningPassphrase: "secret"},
+			stdout: log.Writer(),
+		}
+
+		err := helmExecute.runHelmPackage()
+
+		require.NoError(t, err)
+		require.Len(t, utils.Calls, 1)
+		assert.Equal(t, []string{"package", ".", "--sign", "--key", "helm@example.com", "--keyring", "secring.gpg", "--passphrase-file", "/tmp/helm-signingtest/passphrase"}, utils.Calls[0].Params)
+		assert.False(t, files.HasFile("/tmp/helm-signingtest/passphrase"), "the passphrase file must be removed")
+		assert.True(t, files.HasRemovedFile("/tmp/helm-signingtest/passphrase"))
+	})
+
+	t.Run("error case: missing signing key", func(t *testing.T) {
+		utils := helmMockUtilsBundle{ExecMockRunner: &mock.ExecMockRunner{}}
+		helmExecute := HelmExecute{utils: utils, config: HelmExecuteOptions{ChartPath: ".", SignChart: true, SigningKeyring: "secring.gpg"}, stdout: log.Writer()}
+
+		err := helmExecute.runHelmPackage()
+
+		assert.EqualError(t, err, "signing the chart requires signingKey and signingKeyring")
+		assert.Empty(t, utils.Calls)
+	})
+}
+
 func TestRunHelmTemplate(t *testing.T) {
 	renderedManifests := "apiVersion: apps/v1\nkind: Deployment\nspec:\n  template:\n    spec:\n      containers:\n        - image: registry.example.com/app:1.2.3\n"
 
@@ -580,6 +622,83 @@ func TestRunHelmTemplate(t *testing.T) {
 	})
 }
 
+func TestRunHelmTemplateFor(t *testing.T) {
+	renderedManifests := "apiVersion: apps/v1\nkind: Deployment\n"
+
+	t.Run("values files and Kubernetes version", func(t *testing.T) {
+		utils := helmMockUtilsBundle{
+			ExecMockRunner: &mock.ExecMockRunner{
+				StdoutReturn: map[string]string{"helm template.*": renderedManifests},
+			},
+		}
+		helmExecute := HelmExecute{
+			utils:  utils,
+			config: HelmExecuteOptions{ChartPath: "charts/app", DeploymentName: "testChart", HelmValues: []string{"v.yaml"}, Namespace: "ns"},
+			stdout: log.Writer(),
+		}
+
+		out, err := helmExecute.RunHelmTemplateFor([]string{"values-prod.yaml"}, "1.33.0")
+
+		require.NoError(t, err)
+		require.Len(t, utils.Calls, 1)
+		assert.Equal(t, []string{"template", "testChart", "charts/app", "--values", "v.yaml", "--values", "values-prod.yaml", "--namespace", "ns", "--kube-version", "1.33.0"}, utils.Calls[0].Params)
+		assert.Equal(t, renderedManifests, string(out))
+		assert.Equal(t, []string{"v.yaml"}, helmExecute.config.HelmValues, "the configured values must not be modified")
+		assert.Equal(t, log.Writer(), utils.GetStdout())
+	})
+
+	t.Run("without values files and Kubernetes version", func(t *testing.T) {
+		utils := helmMockUtilsBundle{ExecMockRunner: &mock.ExecMockRunner{}}
+		helmExecute := HelmExecute{utils: utils, config: HelmExecuteOptions{ChartPath: ".", DeploymentName: "testChart"}, stdout: log.Writer()}
+
+		_, err := helmExecute.RunHelmTemplateFor(nil, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"template", "testChart", "."}, utils.Calls[0].Params)
+	})
+
+	t.Run("error cases", func(t *testing.T) {
+		utils := helmMockUtilsBundle{
+			ExecMockRunner: &mock.ExecMockRunner{
+				ShouldFailOnCommand: map[string]error{"helm template.*": fmt.Errorf("exit status 1")},
+			},
+		}
+		helmExecute := HelmExecute{utils: utils, config: HelmExecuteOptions{ChartPath: ".", DeploymentName: "testChart"}, stdout: log.Writer()}
+
+		_, err := helmExecute.RunHelmTemplateFor(nil, "1.33.0")
+		assert.EqualError(t, err, "failed to execute helm template: exit status 1")
+
+		helmExecute.config.ChartPath = ""
+		_, err = helmExecute.RunHelmTemplateFor(nil, "1.33.0")
+		assert.ErrorContains(t, err, "chartPath value is mandatory")
+	})
+}
+
+func TestRunHelmUnitTest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		utils := helmMockUtilsBundle{ExecMockRunner: &mock.ExecMockRunner{}}
+		helmExecute := HelmExecute{utils: utils, config: HelmExecuteOptions{ChartPath: "charts/app"}, stdout: log.Writer()}
+
+		err := helmExecute.RunHelmUnitTest("reports/TEST-helm-unittest.xml")
+
+		require.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "helm", Params: []string{"unittest", "--output-type", "JUnit", "--output-file", "reports/TEST-helm-unittest.xml", "charts/app"}}}, utils.Calls)
+	})
+
+	t.Run("failing tests", func(t *testing.T) {
+		utils := helmMockUtilsBundle{
+			ExecMockRunner: &mock.ExecMockRunner{
+				ShouldFailOnCommand: map[string]error{"helm unittest.*": fmt.Errorf("exit status 1")},
+			},
+		}
+		helmExecute := HelmExecute{utils: utils, config: HelmExecuteOptions{ChartPath: "charts/app"}, stdout: log.Writer()}
+
+		err := helmExecute.RunHelmUnitTest("TEST-helm-unittest.xml")
+
+		assert.EqualError(t, err, "failed to execute helm unittest: exit status 1")
+	})
+}
+
 func TestRunHelmTest(t *testing.T) {
 	testTable := []struct {
 		config            HelmExecuteOptions
@@ -676,6 +795,29 @@ func TestRunHelmDependency(t *testing.T) {
 				{Exec: "helm", Params: []string{"dependency", "update", "."}},
 			},
 		},
+		{
+			config: HelmExecuteOptions{
+				ChartPath:          ".",
+				Dependency:         "build",
+				VerifyDependencies: true,
+				DependencyKeyring:  "pubring.gpg",
+			},
+			expectedError: nil,
+			expectedExecCalls: []mock.ExecCall{
+				{Exec: "helm", Params: []string{"dependency", "build", ".", "--verify", "--keyring", "pubring.gpg"}},
+			},
+		},
+		{
+			config: HelmExecuteOptions{
+				ChartPath:          ".",
+				Dependency:         "list",
+				VerifyDependencies: true,
+			},
+			expectedError: nil,
+			expectedExecCalls: []mock.ExecCall{
+				{Exec: "helm", Params: []string{"dependency", "list", "."}},
+			},
+		},
 	}
 
 	for i, testCase := range testTable {
@@ -732,6 +874,69 @@ func TestRunHelmPublish(t *testing.T) {
 			assert.Equal(t, "https://my.target.repository.local/test_helm_chart-1.2.3.tgz", utils.FileUploads["test_helm_chart-1.2.3.tgz"])
 		}
 	})
+
+	t.Run("success with provenance file", func(t *testing.T) {
+		utils := helmMockUtilsBundle{
+			ExecMockRunner: &mock.ExecMockRunner{},
+			HttpClientMock: &mock.HttpClientMock{
+				FileUploads: map[string]string{},
+			},
+		}
+		utils.ReturnFileUploadStatus = 201
+		helmExecute := HelmExecute{
+			utils: utils,
+			config: HelmExecuteOptions{
+				TargetRepositoryURL: "https://my.target.repository.local",
+				PublishVersion:      "1.2.3",
+				DeploymentName:      "test_helm_chart",
+				ChartPath:           ".",
+				SignChart:           true,
+				SigningKey:          "helm@example.com",
+				SigningKeyring:      "secring.gpg",
+			},
+			stdout: log.Writer(),
+		}
+
+		targetURL, err := helmExecute.RunHelmPublish()
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://my.target.repository.local/test_helm_chart-1.2.3.tgz", targetURL)
+		assert.Equal(t, map[string]string{
+			"test_helm_chart-1.2.3.tgz":      "https://my.target.repository.local/test_helm_chart-1.2.3.tgz",
+			"test_helm_chart-1.2.3.tgz.prov": "https://my.target.repository.local/test_helm_chart-1.2.3.tgz.prov",
+		}, utils.FileUploads)
+	})
+
+	t.Run("success with OCI registry", func(t *testing.T) {
+		utils := helmMockUtilsBundle{
+			ExecMockRunner: &mock.ExecMockRunner{},
+			HttpClientMock: &mock.HttpClientMock{
+				FileUploads: map[string]string{},
+			},
+		}
+		helmExecute := HelmExecute{
+			utils: utils,
+			config: HelmExecuteOptions{
+				TargetRepositoryURL:      "oci://my.registry.com/charts/",
+				TargetRepositoryUser:     "testUser",
+				TargetRepositoryPassword: "testPWD",
+				DockerConfigJSON:         ".pipeline/docker/config.json",
+				PublishVersion:           "1.2.3+build.1",
+				DeploymentName:           "test_helm_chart",
+				ChartPath:                ".",
+			},
+			stdout: log.Writer(),
+		}
+
+		targetURL, err := helmExecute.RunHelmPublish()
+
+		require.NoError(t, err)
+		assert.Equal(t, "oci://my.registry.com/charts/test_helm_chart:1.2.3_build.1", targetURL)
+		assert.Empty(t, utils.FileUploads)
+		require.Len(t, utils.Calls, 3)
+		assert.Equal(t, mock.ExecCall{Exec: "helm", Params: []string{"registry", "login", "my.registry.com", "--username", "testUser", "--password", "testPWD", "--registry-config", ".pipeline/docker/config.json"}}, utils.Calls[1])
+		assert.Equal(t, mock.ExecCall{Exec: "helm", Params: []string{"push", "test_helm_chart-1.2.3+build.1.tgz", "oci://my.registry.com/charts", "--registry-config", ".pipeline/docker/config.json"}}, utils.Calls[2])
+	})
 }
 
 func TestRunHelmCommand(t *testing.T) {
@@ -207,6 +207,65 @@ func (_c *HelmExecutor_RunHelmPublish_Call) RunAndReturn(run func() (string, err
 	return _c
 }
 
+// RunHelmTemplateFor provides a mock function with given fields: valuesFiles, kubeVersion
+func (_m *HelmExecutor) RunHelmTemplateFor(valuesFiles []string, kubeVersion string) ([]byte, error) {
+	ret := _m.Called(valuesFiles, kubeVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunHelmTemplateFor")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string, string) ([]byte, error)); ok {
+		return rf(valuesFiles, kubeVersion)
+	}
+	if rf, ok := ret.Get(0).(func([]string, string) []byte); ok {
+		r0 = rf(valuesFiles, kubeVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string, string) error); ok {
+		r1 = rf(valuesFiles, kubeVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HelmExecutor_RunHelmTemplateFor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunHelmTemplateFor'
+type HelmExecutor_RunHelmTemplateFor_Call struct {
+	*mock.Call
+}
+
+// RunHelmTemplateFor is a helper method to define mock.On call
+//   - valuesFiles []string
+//   - kubeVersion string
+func (_e *HelmExecutor_Expecter) RunHelmTemplateFor(valuesFiles interface{}, kubeVersion interface{}) *HelmExecutor_RunHelmTemplateFor_Call {
+	return &HelmExecutor_RunHelmTemplateFor_Call{Call: _e.mock.On("RunHelmTemplateFor", valuesFiles, kubeVersion)}
+}
+
+func (_c *HelmExecutor_RunHelmTemplateFor_Call) Run(run func(valuesFiles []string, kubeVersion string)) *HelmExecutor_RunHelmTemplateFor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *HelmExecutor_RunHelmTemplateFor_Call) Return(_a0 []byte, _a1 error) *HelmExecutor_RunHelmTemplateFor_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *HelmExecutor_RunHelmTemplateFor_Call) RunAndReturn(run func([]string, string) ([]byte, error)) *HelmExecutor_RunHelmTemplateFor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RunHelmTest provides a mock function with given fields:
 func (_m *HelmExecutor) RunHelmTest() error {
 	ret := _m.Called()
@@ -297,6 +356,52 @@ func (_c *HelmExecutor_RunHelmUninstall_Call) RunAndReturn(run func() error) *He
 	return _c
 }
 
+// RunHelmUnitTest provides a mock function with given fields: junitFile
+func (_m *HelmExecutor) RunHelmUnitTest(junitFile string) error {
+	ret := _m.Called(junitFile)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunHelmUnitTest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(junitFile)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HelmExecutor_RunHelmUnitTest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunHelmUnitTest'
+type HelmExecutor_RunHelmUnitTest_Call struct {
+	*mock.Call
+}
+
+// RunHelmUnitTest is a helper method to define mock.On call
+//   - junitFile string
+func (_e *HelmExecutor_Expecter) RunHelmUnitTest(junitFile interface{}) *HelmExecutor_RunHelmUnitTest_Call {
+	return &HelmExecutor_RunHelmUnitTest_Call{Call: _e.mock.On("RunHelmUnitTest", junitFile)}
+}
+
+func (_c *HelmExecutor_RunHelmUnitTest_Call) Run(run func(junitFile string)) *HelmExecutor_RunHelmUnitTest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *HelmExecutor_RunHelmUnitTest_Call) Return(_a0 error) *HelmExecutor_RunHelmUnitTest_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *HelmExecutor_RunHelmUnitTest_Call) RunAndReturn(run func(string) error) *HelmExecutor_RunHelmUnitTest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RunHelmUpgrade provides a mock function with given fields:
 func (_m *HelmExecutor) RunHelmUpgrade() error {
 	ret := _m.Called()
@@ -0,0 +1,137 @@
+package licensing
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+)
+
+// shippedComponents returns the components which are part of the delivery, i.e. not excluded, sorted by name
+func shippedComponents(components []Component) []Component {
+	shipped := []Component{}
+	for _, component := range components {
+		if component.Scope != string(cdx.ScopeExcluded) {
+			shipped = append(shipped, component)
+		}
+	}
+	sort.SliceStable(shipped, func(i, j int) bool {
+		if shipped[i].DisplayName() != shipped[j].DisplayName() {
+			return shipped[i].DisplayName() < shipped[j].DisplayName()
+		}
+		return shipped[i].Version < shipped[j].Version
+	})
+	return shipped
+}
+
+type licenseText struct {
+	ID   string
+	Text string
+	URL  string
+}
+
+// licenseTexts collects the texts of all licenses used by the components, licenses without
+// embedded text reference the SPDX license list
+func licenseTexts(components []Component) []licenseText {
+	texts := map[string]licenseText{}
+	for _, component := range components {
+		for _, license := range component.Expression.Licenses() {
+			id, _, _ := strings.Cut(license, " WITH ")
+			if id == NoAssertion {
+				continue
+			}
+			text := texts[id]
+			text.ID = id
+			if len(text.Text) == 0 {
+				text.Text = component.LicenseTexts[license]
+			}
+			if !strings.HasPrefix(id, "LicenseRef-") {
+				text.URL = fmt.Sprintf("https://spdx.org/licenses/%v.html", id)
+			}
+			texts[id] = text
+		}
+	}
+	result := []licenseText{}
+	for _, text := range texts {
+		result = append(result, text)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// Notice renders a plain text NOTICE file listing all shipped third-party components with their licenses
+func Notice(productName string, components []Component) []byte {
+	shipped := shippedComponents(components)
+	var notice strings.Builder
+	fmt.Fprintf(&notice, "Third-party notices for %v\n\n", productName)
+	fmt.Fprintf(&notice, "%v includes the following third-party components:\n", productName)
+	for _, component := range shipped {
+		fmt.Fprintf(&notice, "\n%v %v\n", component.DisplayName(), component.Version)
+		fmt.Fprintf(&notice, "  License: %v\n", component.Expression)
+		if len(component.Copyright) > 0 {
+			fmt.Fprintf(&notice, "  Copyright: %v\n", component.Copyright)
+		}
+	}
+	texts := licenseTexts(shipped)
+	if len(texts) > 0 {
+		notice.WriteString("\n\nLicenses\n========\n")
+		for _, text := range texts {
+			fmt.Fprintf(&notice, "\n%v\n%v\n", text.ID, strings.Repeat("-", len(text.ID)))
+			switch {
+			case len(text.Text) > 0:
+				notice.WriteString(strings.TrimSpace(text.Text) + "\n")
+			case len(text.URL) > 0:
+				fmt.Fprintf(&notice, "See %v\n", text.URL)
+			}
+		}
+	}
+	return []byte(notice.String())
+}
+
+const attributionTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Third-party notices for {{.ProductName}}</title>
+<style>
+body { font-family: Arial, Verdana, Helvetica, sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; }
+pre { white-space: pre-wrap; background: #f5f5f5; padding: 1em; }
+</style>
+</head>
+<body>
+<h1>Third-party notices for {{.ProductName}}</h1>
+<p>{{.ProductName}} includes the following third-party components.</p>
+<table>
+<tr><th>Component</th><th>Version</th><th>License</th><th>Copyright</th></tr>
+{{range .Components}}<tr><td>{{.DisplayName}}</td><td>{{.Version}}</td><td>{{.Expression.String}}</td><td>{{.Copyright}}</td></tr>
+{{end}}</table>
+{{if .Licenses}}<h2>Licenses</h2>
+{{range .Licenses}}<h3 id="{{.ID}}">{{.ID}}</h3>
+{{if .Text}}<pre>{{.Text}}</pre>{{else if .URL}}<p><a href="{{.URL}}">{{.URL}}</a></p>{{end}}
+{{end}}{{end}}</body>
+</html>
+`
+
+// Attribution renders an HTML attribution document listing all shipped third-party components with their licenses
+func Attribution(productName string, components []Component) ([]byte, error) {
+	shipped := shippedComponents(components)
+	tmpl, err := template.New("attribution").Parse(attributionTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse attribution template: %w", err)
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, struct {
+		ProductName string
+		Components  []Component
+		Licenses    []licenseText
+	}{productName, shipped, licenseTexts(shipped)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render attribution: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,89 @@
+//go:build unit
+
+package licensing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+func testComponents(t *testing.T) []Component {
+	expression := func(value string) *Expression {
+		parsed, err := ParseExpression(value)
+		assert.NoError(t, err)
+		return parsed
+	}
+	return []Component{
+		{Name: "zeta", Version: "2.0", Scope: "required", Expression: expression("MIT"), Copyright: "Copyright (c) Zeta <authors>"},
+		{Name: "alpha", Group: "org", Version: "1.0", Scope: "required", Expression: expression("Apache-2.0 OR LicenseRef-Custom"), LicenseTexts: map[string]string{"LicenseRef-Custom": "Custom license text"}},
+		{Name: "tool", Version: "0.1", Scope: "excluded", Expression: expression("GPL-3.0-only")},
+	}
+}
+
+func TestNotice(t *testing.T) {
+	t.Parallel()
+
+	notice := string(Notice("my-app", testComponents(t)))
+
+	assert.Contains(t, notice, "my-app includes the following third-party components:\n\norg/alpha 1.0\n  License: Apache-2.0 OR LicenseRef-Custom\n\nzeta 2.0\n  License: MIT\n  Copyright: Copyright (c) Zeta <authors>\n")
+	assert.Contains(t, notice, "\nApache-2.0\n----------\nSee https://spdx.org/licenses/Apache-2.0.html\n")
+	assert.Contains(t, notice, "\nLicenseRef-Custom\n-----------------\nCustom license text\n")
+	assert.NotContains(t, notice, "tool")
+	assert.NotContains(t, notice, "GPL")
+}
+
+func TestAttribution(t *testing.T) {
+	t.Parallel()
+
+	attribution, err := Attribution("my-app", testComponents(t))
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(attribution), "<tr><td>zeta</td><td>2.0</td><td>MIT</td><td>Copyright (c) Zeta &lt;authors&gt;</td></tr>")
+	assert.Contains(t, string(attribution), `<a href="https://spdx.org/licenses/MIT.html">`)
+	assert.Contains(t, string(attribution), "<pre>Custom license text</pre>")
+	assert.NotContains(t, string(attribution), "GPL")
+}
+
+func TestCreateScanReport(t *testing.T) {
+	t.Parallel()
+	policy := &Policy{Allow: []string{"MIT"}, Deny: []string{"GPL-*"}}
+	results := policy.EvaluateComponents(testComponents(t))
+
+	report := CreateScanReport("my-app", results, time.Now())
+
+	assert.False(t, report.SuccessfulScan)
+	assert.Equal(t, "1", report.Overview[1].Details)
+	assert.Equal(t, reporting.ColumnStyle(reporting.Red), report.Overview[1].Style)
+	if assert.Len(t, report.DetailTable.Rows, 3) {
+		assert.Equal(t, "tool", report.DetailTable.Rows[0].Columns[0].Content)
+		assert.Equal(t, "deny", report.DetailTable.Rows[0].Columns[4].Content)
+		assert.Equal(t, reporting.ColumnStyle(reporting.Red), report.DetailTable.Rows[0].Columns[4].Style)
+	}
+}
+
+func TestWriteReports(t *testing.T) {
+	t.Parallel()
+	utils := &mock.FilesMock{}
+	policy := &Policy{Default: Allow}
+	results := policy.EvaluateComponents(testComponents(t))
+
+	reports, err := WriteReports("my-app", results, time.Now(), utils)
+
+	assert.NoError(t, err)
+	assert.Len(t, reports, 4)
+	for _, file := range []string{
+		"license-compliance/piper_license_compliance_report.json",
+		"license-compliance/piper_license_compliance_report.html",
+		"license-compliance/NOTICE.txt",
+		"license-compliance/attribution.html",
+		reporting.StepReportDirectory + "/licenseComplianceCheck.json",
+	} {
+		assert.True(t, utils.HasWrittenFile(file), file)
+	}
+	content, _ := utils.FileRead("license-compliance/piper_license_compliance_report.json")
+	assert.Contains(t, string(content), `"license": "Apache-2.0 OR LicenseRef-Custom"`)
+}
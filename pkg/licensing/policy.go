@@ -0,0 +1,174 @@
+package licensing
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Decision is the result of evaluating a license against a policy
+type Decision string
+
+// Possible decisions, ordered from least to most restrictive
+const (
+	Allow  Decision = "allow"
+	Review Decision = "review"
+	Deny   Decision = "deny"
+)
+
+func (d Decision) rank() int {
+	switch d {
+	case Allow:
+		return 0
+	case Deny:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Policy defines which licenses are allowed, need a review or are denied.
+// Entries are SPDX license identifiers and may contain wildcards, e.g. "GPL-*".
+// Exact entries take precedence over entries with wildcards.
+type Policy struct {
+	Allow  []string `yaml:"allow,omitempty"`
+	Review []string `yaml:"review,omitempty"`
+	Deny   []string `yaml:"deny,omitempty"`
+	// Default is the decision for licenses not listed, "review" if not set
+	Default Decision `yaml:"default,omitempty"`
+	// Scopes contains policies for dependency scopes (required, optional, excluded) which take precedence over the general policy
+	Scopes map[string]*Policy `yaml:"scopes,omitempty"`
+}
+
+// ParsePolicy parses a policy file in YAML format
+func ParsePolicy(content []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := yaml.Unmarshal(content, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse license policy: %w", err)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (p *Policy) validate() error {
+	switch p.Default {
+	case "", Allow, Review, Deny:
+	default:
+		return fmt.Errorf("invalid default decision '%v', use one of allow, review, deny", p.Default)
+	}
+	for scope, scopePolicy := range p.Scopes {
+		if scopePolicy == nil {
+			continue
+		}
+		if err := scopePolicy.validate(); err != nil {
+			return fmt.Errorf("scope %v: %w", scope, err)
+		}
+	}
+	return nil
+}
+
+// Decide returns the decision for a single license of a dependency with the given scope
+func (p *Policy) Decide(license, scope string) Decision {
+	candidates := []string{license}
+	if base, _, found := strings.Cut(license, " WITH "); found {
+		candidates = append(candidates, base)
+	}
+	scopePolicy := p.Scopes[scope]
+	// any rule of the scope, including wildcards, takes precedence over the general rules
+	for _, policy := range []*Policy{scopePolicy, p} {
+		if policy == nil {
+			continue
+		}
+		for _, candidate := range candidates {
+			for _, wildcards := range []bool{false, true} {
+				if decision, ok := policy.match(candidate, wildcards); ok {
+					return decision
+				}
+			}
+		}
+	}
+	if scopePolicy != nil && len(scopePolicy.Default) > 0 {
+		return scopePolicy.Default
+	}
+	if len(p.Default) > 0 {
+		return p.Default
+	}
+	return Review
+}
+
+func (p *Policy) match(license string, wildcards bool) (Decision, bool) {
+	for _, entry := range []struct {
+		decision Decision
+		patterns []string
+	}{{Deny, p.Deny}, {Review, p.Review}, {Allow, p.Allow}} {
+		for _, pattern := range entry.patterns {
+			isWildcard := strings.ContainsAny(pattern, "*?")
+			if isWildcard != wildcards {
+				continue
+			}
+			if wildcards {
+				if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(license)); matched {
+					return entry.decision, true
+				}
+			} else if strings.EqualFold(pattern, license) {
+				return entry.decision, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Evaluate evaluates a license expression: for OR the least restrictive, for AND the most restrictive decision applies
+func (p *Policy) Evaluate(expression *Expression, scope string) Decision {
+	if expression == nil {
+		return p.Decide(NoAssertion, scope)
+	}
+	if len(expression.Operator) == 0 {
+		return p.Decide(expression.License, scope)
+	}
+	var result Decision
+	for i, operand := range expression.Operands {
+		decision := p.Evaluate(operand, scope)
+		if i == 0 ||
+			(expression.Operator == "OR" && decision.rank() < result.rank()) ||
+			(expression.Operator == "AND" && decision.rank() > result.rank()) {
+			result = decision
+		}
+	}
+	return result
+}
+
+// Result is the evaluation result of a component
+type Result struct {
+	Component Component
+	Decision  Decision
+}
+
+// EvaluateComponents evaluates all components against the policy, the results are sorted by decision and name
+func (p *Policy) EvaluateComponents(components []Component) []Result {
+	results := []Result{}
+	for _, component := range components {
+		results = append(results, Result{Component: component, Decision: p.Evaluate(component.Expression, component.Scope)})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Decision.rank() != results[j].Decision.rank() {
+			return results[i].Decision.rank() > results[j].Decision.rank()
+		}
+		return results[i].Component.DisplayName() < results[j].Component.DisplayName()
+	})
+	return results
+}
+
+// CountDecisions counts the results per decision
+func CountDecisions(results []Result) map[Decision]int {
+	counts := map[Decision]int{Allow: 0, Review: 0, Deny: 0}
+	for _, result := range results {
+		counts[result.Decision]++
+	}
+	return counts
+}
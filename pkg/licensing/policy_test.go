@@ -0,0 +1,86 @@
+//go:build unit
+
+package licensing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPolicy = `
+allow: [MIT, Apache-2.0, "BSD-*", GPL-2.0-only WITH Classpath-exception-2.0]
+review: [LGPL-*, NOASSERTION, MPL-2.0]
+deny: ["GPL-*", "AGPL-*"]
+default: review
+scopes:
+  optional:
+    allow: ["GPL-*", "MPL-*"]
+  excluded:
+    default: allow
+`
+
+func TestPolicyDecide(t *testing.T) {
+	t.Parallel()
+	policy, err := ParsePolicy([]byte(testPolicy))
+	assert.NoError(t, err)
+
+	assert.Equal(t, Allow, policy.Decide("MIT", "required"))
+	assert.Equal(t, Allow, policy.Decide("bsd-3-clause", "required"))
+	assert.Equal(t, Deny, policy.Decide("GPL-3.0-only", "required"))
+	// exact entries take precedence over wildcards
+	assert.Equal(t, Allow, policy.Decide("GPL-2.0-only WITH Classpath-exception-2.0", "required"))
+	assert.Equal(t, Deny, policy.Decide("GPL-3.0-only WITH GCC-exception-3.1", "required"))
+	assert.Equal(t, Review, policy.Decide("LGPL-2.1-only", "required"))
+	assert.Equal(t, Review, policy.Decide("EPL-2.0", "required"))
+	// scope specific policies
+	assert.Equal(t, Allow, policy.Decide("GPL-3.0-only", "optional"))
+	assert.Equal(t, Deny, policy.Decide("AGPL-3.0-only", "optional"))
+	assert.Equal(t, Allow, policy.Decide("EPL-2.0", "excluded"))
+	assert.Equal(t, Review, policy.Decide("MPL-2.0", "required"))
+	// wildcards of a scope take precedence over exact entries of the general policy
+	assert.Equal(t, Allow, policy.Decide("MPL-2.0", "optional"))
+	assert.Equal(t, Deny, policy.Decide("GPL-3.0-only", "excluded"))
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	t.Parallel()
+	policy, _ := ParsePolicy([]byte(testPolicy))
+	evaluate := func(expression string) Decision {
+		parsed, err := ParseExpression(expression)
+		assert.NoError(t, err)
+		return policy.Evaluate(parsed, "required")
+	}
+
+	assert.Equal(t, Allow, evaluate("GPL-3.0-only OR MIT"))
+	assert.Equal(t, Deny, evaluate("GPL-3.0-only AND MIT"))
+	assert.Equal(t, Review, evaluate("(GPL-3.0-only OR LGPL-3.0-only) AND MIT"))
+	assert.Equal(t, Review, evaluate(""))
+}
+
+func TestEvaluateComponents(t *testing.T) {
+	t.Parallel()
+	policy, _ := ParsePolicy([]byte(testPolicy))
+	mit, _ := ParseExpression("MIT")
+	gpl, _ := ParseExpression("GPL-3.0-only")
+	components := []Component{
+		{Name: "b", Scope: "required", Expression: mit},
+		{Name: "a", Scope: "required", Expression: mit},
+		{Name: "c", Scope: "required", Expression: gpl},
+		{Name: "d", Scope: "optional", Expression: gpl},
+	}
+
+	results := policy.EvaluateComponents(components)
+
+	assert.Equal(t, []string{"c", "a", "b", "d"}, []string{results[0].Component.Name, results[1].Component.Name, results[2].Component.Name, results[3].Component.Name})
+	assert.Equal(t, map[Decision]int{Allow: 3, Review: 0, Deny: 1}, CountDecisions(results))
+}
+
+func TestParsePolicyInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := ParsePolicy([]byte("default: maybe"))
+	assert.EqualError(t, err, "invalid default decision 'maybe', use one of allow, review, deny")
+
+	_, err = ParsePolicy([]byte("scopes:\n  optional:\n    default: never"))
+	assert.EqualError(t, err, "scope optional: invalid default decision 'never', use one of allow, review, deny")
+}
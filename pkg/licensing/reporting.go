@@ -0,0 +1,153 @@
+package licensing
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// ReportsDirectory defines the subfolder for the license compliance reports and notice files which are generated
+const ReportsDirectory = "license-compliance"
+
+type resultEntry struct {
+	Name     string   `json:"name"`
+	Group    string   `json:"group,omitempty"`
+	Version  string   `json:"version,omitempty"`
+	PURL     string   `json:"purl,omitempty"`
+	Scope    string   `json:"scope"`
+	License  string   `json:"license"`
+	Decision Decision `json:"decision"`
+}
+
+// CreateScanReport creates a report of the license evaluation for the pipeline summary
+func CreateScanReport(productName string, results []Result, reportTime time.Time) reporting.ScanReport {
+	counts := CountDecisions(results)
+	scanReport := reporting.ScanReport{
+		ReportTitle: "License Compliance Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Product name", Details: productName},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Total number of components", Details: fmt.Sprint(len(results))},
+			{Description: "Components with denied licenses", Details: fmt.Sprint(counts[Deny]), Style: styleIfNotZero(counts[Deny], reporting.Red)},
+			{Description: "Components with licenses to be reviewed", Details: fmt.Sprint(counts[Review]), Style: styleIfNotZero(counts[Review], reporting.Yellow)},
+			{Description: "Components with allowed licenses", Details: fmt.Sprint(counts[Allow])},
+		},
+		ReportTime:     reportTime,
+		SuccessfulScan: counts[Deny] == 0,
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No components found",
+		Headers:       []string{"Component", "Version", "Scope", "License", "Decision"},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, result := range results {
+		row := reporting.ScanRow{}
+		row.AddColumn(result.Component.DisplayName(), 0)
+		row.AddColumn(result.Component.Version, 0)
+		row.AddColumn(result.Component.Scope, 0)
+		row.AddColumn(result.Component.Expression, 0)
+		row.AddColumn(result.Decision, decisionStyle(result.Decision))
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+	return scanReport
+}
+
+func decisionStyle(decision Decision) reporting.ColumnStyle {
+	switch decision {
+	case Allow:
+		return reporting.Green
+	case Deny:
+		return reporting.Red
+	default:
+		return reporting.Yellow
+	}
+}
+
+func styleIfNotZero(count int, style reporting.ColumnStyle) reporting.ColumnStyle {
+	if count > 0 {
+		return style
+	}
+	return 0
+}
+
+// WriteReports writes the evaluation results as JSON and HTML report, the third-party notice files and
+// the JSON report used by the pipeline summary
+func WriteReports(productName string, results []Result, reportTime time.Time, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+	if err := utils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return reportPaths, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	entries := []resultEntry{}
+	components := []Component{}
+	for _, result := range results {
+		component := result.Component
+		components = append(components, component)
+		entries = append(entries, resultEntry{
+			Name:     component.Name,
+			Group:    component.Group,
+			Version:  component.Version,
+			PURL:     component.PURL,
+			Scope:    component.Scope,
+			License:  component.Expression.String(),
+			Decision: result.Decision,
+		})
+	}
+	jsonReport, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to marshal results: %w", err)
+	}
+	jsonReportPath := filepath.Join(ReportsDirectory, "piper_license_compliance_report.json")
+	if err := utils.FileWrite(jsonReportPath, jsonReport, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write JSON report: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "License compliance JSON report", Target: jsonReportPath})
+
+	scanReport := CreateScanReport(productName, results, reportTime)
+	htmlReport, err := scanReport.ToHTML()
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to create HTML report: %w", err)
+	}
+	htmlReportPath := filepath.Join(ReportsDirectory, "piper_license_compliance_report.html")
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "License compliance report", Target: htmlReportPath})
+
+	noticePath := filepath.Join(ReportsDirectory, "NOTICE.txt")
+	if err := utils.FileWrite(noticePath, Notice(productName, components), 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write notice file: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Third-party notice", Target: noticePath})
+
+	attribution, err := Attribution(productName, components)
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to create attribution: %w", err)
+	}
+	attributionPath := filepath.Join(ReportsDirectory, "attribution.html")
+	if err := utils.FileWrite(attributionPath, attribution, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write attribution file: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Third-party attribution", Target: attributionPath})
+
+	// JSON reports are used by step pipelineCreateScanSummary
+	// ignore JSON errors since structure is in our hands
+	stepReport, _ := scanReport.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return reportPaths, fmt.Errorf("failed to create step reporting directory: %w", err)
+		}
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "licenseComplianceCheck.json"), stepReport, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write step report: %w", err)
+	}
+	return reportPaths, nil
+}
@@ -0,0 +1,146 @@
+package licensing
+
+import (
+	"bytes"
+	"fmt"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// Component is a dependency with its license information as declared in an SBOM
+type Component struct {
+	Name      string
+	Group     string
+	Version   string
+	PURL      string
+	Scope     string
+	Copyright string
+	// Expression is the normalized license expression of all declared licenses
+	Expression *Expression
+	// LicenseTexts contains license texts embedded into the SBOM by license identifier
+	LicenseTexts map[string]string
+}
+
+// DisplayName returns the name of the component including its group
+func (c Component) DisplayName() string {
+	if len(c.Group) > 0 {
+		return c.Group + "/" + c.Name
+	}
+	return c.Name
+}
+
+// key identifies a component across SBOMs
+func (c Component) key() string {
+	if len(c.PURL) > 0 {
+		return c.PURL
+	}
+	return c.DisplayName() + "@" + c.Version
+}
+
+// ReadComponents reads the components of a CycloneDX SBOM in XML or JSON format. The component described by
+// the SBOM itself is not contained, nested components are.
+func ReadComponents(content []byte) ([]Component, error) {
+	bomFormat := cdx.BOMFileFormatXML
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '{' {
+		bomFormat = cdx.BOMFileFormatJSON
+	}
+	var bom cdx.BOM
+	if err := cdx.NewBOMDecoder(bytes.NewReader(content), bomFormat).Decode(&bom); err != nil {
+		return nil, fmt.Errorf("failed to decode SBOM: %w", err)
+	}
+	components := []Component{}
+	if bom.Components != nil {
+		collectComponents(*bom.Components, &components)
+	}
+	return components, nil
+}
+
+func collectComponents(cdxComponents []cdx.Component, components *[]Component) {
+	for _, cdxComponent := range cdxComponents {
+		scope := string(cdxComponent.Scope)
+		if len(scope) == 0 {
+			scope = string(cdx.ScopeRequired)
+		}
+		component := Component{
+			Name:         cdxComponent.Name,
+			Group:        cdxComponent.Group,
+			Version:      cdxComponent.Version,
+			PURL:         cdxComponent.PackageURL,
+			Scope:        scope,
+			Copyright:    cdxComponent.Copyright,
+			LicenseTexts: map[string]string{},
+		}
+		expressions := []*Expression{}
+		if cdxComponent.Licenses != nil {
+			for _, choice := range *cdxComponent.Licenses {
+				if expression := licenseChoiceExpression(choice, component); expression != nil {
+					expressions = append(expressions, expression)
+					if choice.License != nil && choice.License.Text != nil && len(choice.License.Text.Content) > 0 && choice.License.Text.Encoding != "base64" {
+						component.LicenseTexts[expression.License] = choice.License.Text.Content
+					}
+				}
+			}
+		}
+		component.Expression = CombineExpressions(expressions)
+		*components = append(*components, component)
+		if cdxComponent.Components != nil {
+			collectComponents(*cdxComponent.Components, components)
+		}
+	}
+}
+
+func licenseChoiceExpression(choice cdx.LicenseChoice, component Component) *Expression {
+	if len(choice.Expression) > 0 {
+		expression, err := ParseExpression(choice.Expression)
+		if err != nil {
+			log.Entry().Warnf("Component %v: %v", component.DisplayName(), err)
+			return &Expression{License: NormalizeLicense(choice.Expression)}
+		}
+		return expression
+	}
+	if choice.License == nil {
+		return nil
+	}
+	switch {
+	case len(choice.License.ID) > 0:
+		return &Expression{License: NormalizeLicense(choice.License.ID)}
+	case len(choice.License.Name) > 0:
+		return &Expression{License: NormalizeLicense(choice.License.Name)}
+	case len(choice.License.URL) > 0:
+		return &Expression{License: NormalizeLicense(choice.License.URL)}
+	}
+	return nil
+}
+
+// MergeComponents combines the components of several SBOMs, components contained multiple times are only kept once.
+// The strictest scope is kept, i.e. a component which is required in one SBOM is considered required.
+func MergeComponents(componentLists ...[]Component) []Component {
+	merged := []Component{}
+	indices := map[string]int{}
+	for _, components := range componentLists {
+		for _, component := range components {
+			index, known := indices[component.key()]
+			if !known {
+				indices[component.key()] = len(merged)
+				merged = append(merged, component)
+				continue
+			}
+			if scopeRank(component.Scope) < scopeRank(merged[index].Scope) {
+				merged[index].Scope = component.Scope
+			}
+		}
+	}
+	return merged
+}
+
+func scopeRank(scope string) int {
+	switch scope {
+	case string(cdx.ScopeRequired):
+		return 0
+	case string(cdx.ScopeOptional):
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,97 @@
+//go:build unit
+
+package licensing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testBomXML = `<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.4" version="1">
+  <metadata>
+    <component type="application"><name>my-app</name><version>1.0.0</version></component>
+  </metadata>
+  <components>
+    <component type="library">
+      <group>org.apache.commons</group>
+      <name>commons-text</name>
+      <version>1.10.0</version>
+      <scope>required</scope>
+      <licenses><license><name>Apache License, Version 2.0</name></license></licenses>
+      <purl>pkg:maven/org.apache.commons/commons-text@1.10.0</purl>
+    </component>
+    <component type="library">
+      <group>junit</group>
+      <name>junit</name>
+      <version>4.13.2</version>
+      <scope>optional</scope>
+      <licenses><license><id>EPL-1.0</id><text>Eclipse Public License text</text></license></licenses>
+      <purl>pkg:maven/junit/junit@4.13.2</purl>
+      <components>
+        <component type="library"><name>hamcrest</name><version>1.3</version><licenses><expression>BSD-3-Clause OR MIT</expression></licenses></component>
+      </components>
+    </component>
+    <component type="library">
+      <name>unknown</name>
+      <version>0.1</version>
+    </component>
+  </components>
+</bom>`
+
+const testBomJSON = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "components": [
+    {"type": "library", "name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21", "licenses": [{"license": {"id": "MIT"}}]},
+    {"type": "library", "group": "junit", "name": "junit", "version": "4.13.2", "scope": "required", "purl": "pkg:maven/junit/junit@4.13.2", "licenses": [{"license": {"id": "EPL-1.0"}}]}
+  ]
+}`
+
+func TestReadComponents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("xml", func(t *testing.T) {
+		components, err := ReadComponents([]byte(testBomXML))
+
+		assert.NoError(t, err)
+		if assert.Len(t, components, 4) {
+			assert.Equal(t, "org.apache.commons/commons-text", components[0].DisplayName())
+			assert.Equal(t, "Apache-2.0", components[0].Expression.String())
+			assert.Equal(t, "required", components[0].Scope)
+			assert.Equal(t, "optional", components[1].Scope)
+			assert.Equal(t, map[string]string{"EPL-1.0": "Eclipse Public License text"}, components[1].LicenseTexts)
+			assert.Equal(t, "hamcrest", components[2].Name)
+			assert.Equal(t, "BSD-3-Clause OR MIT", components[2].Expression.String())
+			assert.Equal(t, NoAssertion, components[3].Expression.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		components, err := ReadComponents([]byte(testBomJSON))
+
+		assert.NoError(t, err)
+		if assert.Len(t, components, 2) {
+			assert.Equal(t, "MIT", components[0].Expression.String())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ReadComponents([]byte("<bom"))
+		assert.ErrorContains(t, err, "failed to decode SBOM")
+	})
+}
+
+func TestMergeComponents(t *testing.T) {
+	t.Parallel()
+	xmlComponents, _ := ReadComponents([]byte(testBomXML))
+	jsonComponents, _ := ReadComponents([]byte(testBomJSON))
+
+	merged := MergeComponents(xmlComponents, jsonComponents)
+
+	assert.Len(t, merged, 5)
+	// junit is a required dependency according to the second SBOM
+	assert.Equal(t, "junit", merged[1].Name)
+	assert.Equal(t, "required", merged[1].Scope)
+}
@@ -0,0 +1,279 @@
+package licensing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NoAssertion is used for components without (recognizable) license information
+const NoAssertion = "NOASSERTION"
+
+var (
+	spdxIDPattern      = regexp.MustCompile(`^(?:LicenseRef-)?[A-Za-z0-9][A-Za-z0-9.+-]*$`)
+	licenseRefReplacer = regexp.MustCompile(`[^A-Za-z0-9.]+`)
+)
+
+// licenseAliases maps common license names and URLs, as found in package descriptors, to SPDX identifiers or LicenseRefs.
+// Keys are lower case with whitespace, commas and the term "the" removed.
+var licenseAliases = map[string]string{
+	"apache2":                              "Apache-2.0",
+	"apache20":                             "Apache-2.0",
+	"apache-2":                             "Apache-2.0",
+	"apache-2.0":                           "Apache-2.0",
+	"apache2.0":                            "Apache-2.0",
+	"apachelicense2.0":                     "Apache-2.0",
+	"apachelicenseversion2.0":              "Apache-2.0",
+	"apachesoftwarelicenseversion2.0":      "Apache-2.0",
+	"asl2.0":                               "Apache-2.0",
+	"apache.org/licenses/license-2.0":      "Apache-2.0",
+	"mit":                                  "MIT",
+	"mitlicense":                           "MIT",
+	"opensource.org/licenses/mit":          "MIT",
+	"bsd3":                                 "BSD-3-Clause",
+	"bsd-3":                                "BSD-3-Clause",
+	"bsd3-clause":                          "BSD-3-Clause",
+	"bsd-3-clause":                         "BSD-3-Clause",
+	"newbsdlicense":                        "BSD-3-Clause",
+	"bsdlicense3":                          "BSD-3-Clause",
+	"opensource.org/licenses/bsd-3-clause": "BSD-3-Clause",
+	"bsd2":                                 "BSD-2-Clause",
+	"bsd-2-clause":                         "BSD-2-Clause",
+	"simplifiedbsdlicense":                 "BSD-2-Clause",
+	"isc":                                  "ISC",
+	"isclicense":                           "ISC",
+	"mpl2.0":                               "MPL-2.0",
+	"mozillapubliclicense2.0":              "MPL-2.0",
+	"mozillapubliclicenseversion2.0":       "MPL-2.0",
+	"epl1.0":                               "EPL-1.0",
+	"eclipsepubliclicense1.0":              "EPL-1.0",
+	"eclipsepubliclicense-v1.0":            "EPL-1.0",
+	"epl2.0":                               "EPL-2.0",
+	"eclipsepubliclicense2.0":              "EPL-2.0",
+	"eclipsepubliclicense-v2.0":            "EPL-2.0",
+	"edl1.0":                               "BSD-3-Clause",
+	"eclipsedistributionlicense-v1.0":      "BSD-3-Clause",
+	"cddl1.0":                              "CDDL-1.0",
+	"cddl1.1":                              "CDDL-1.1",
+	"gpl2":                                 "GPL-2.0-only",
+	"gplv2":                                "GPL-2.0-only",
+	"gpl-2.0":                              "GPL-2.0-only",
+	"gnugeneralpubliclicensev2.0":          "GPL-2.0-only",
+	"gnugeneralpubliclicenseversion2":      "GPL-2.0-only",
+	"gpl3":                                 "GPL-3.0-only",
+	"gplv3":                                "GPL-3.0-only",
+	"gpl-3.0":                              "GPL-3.0-only",
+	"gnugeneralpubliclicensev3.0":          "GPL-3.0-only",
+	"gnugeneralpubliclicenseversion3":      "GPL-3.0-only",
+	"lgpl2.1":                              "LGPL-2.1-only",
+	"lgplv2.1":                             "LGPL-2.1-only",
+	"lgpl-2.1":                             "LGPL-2.1-only",
+	"gnulessergeneralpubliclicensev2.1":    "LGPL-2.1-only",
+	"lgpl3":                                "LGPL-3.0-only",
+	"lgplv3":                               "LGPL-3.0-only",
+	"lgpl-3.0":                             "LGPL-3.0-only",
+	"gnulessergeneralpubliclicensev3.0":    "LGPL-3.0-only",
+	"agpl3":                                "AGPL-3.0-only",
+	"agplv3":                               "AGPL-3.0-only",
+	"agpl-3.0":                             "AGPL-3.0-only",
+	"gnuafferogeneralpubliclicensev3.0":    "AGPL-3.0-only",
+	"unlicense":                            "Unlicense",
+	"theunlicense":                         "Unlicense",
+	"cc01.0":                               "CC0-1.0",
+	"0bsd":                                 "0BSD",
+	"zlib":                                 "Zlib",
+	"python-2.0":                           "Python-2.0",
+	"psf":                                  "PSF-2.0",
+	// names not identifying a single license, e.g. the clause count of BSD, are kept as LicenseRef instead of guessing
+	"bsd":          "LicenseRef-BSD",
+	"bsdlicense":   "LicenseRef-BSD",
+	"publicdomain": "LicenseRef-Public-Domain",
+}
+
+// NormalizeLicense maps a license id, name or URL to an SPDX license identifier.
+// Unknown licenses are returned as LicenseRef, empty values as NOASSERTION.
+func NormalizeLicense(license string) string {
+	license = strings.TrimSpace(license)
+	if len(license) == 0 {
+		return NoAssertion
+	}
+	if id, ok := licenseAliases[aliasKey(license)]; ok {
+		return id
+	}
+	if spdxIDPattern.MatchString(license) {
+		return license
+	}
+	return "LicenseRef-" + strings.Trim(licenseRefReplacer.ReplaceAllString(license, "-"), "-")
+}
+
+func aliasKey(license string) string {
+	key := strings.ToLower(license)
+	for _, prefix := range []string{"https://", "http://", "www."} {
+		key = strings.TrimPrefix(key, prefix)
+	}
+	key = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(key, "/"), ".txt"), ".html")
+	key = strings.TrimPrefix(key, "the ")
+	return strings.NewReplacer(" ", "", ",", "", "(", "", ")", "", "\"", "").Replace(key)
+}
+
+// Expression is a parsed SPDX license expression
+type Expression struct {
+	// Operator is AND or OR for compound expressions, empty for a single license
+	Operator string
+	Operands []*Expression
+	// License is the license identifier of a simple expression, including a potential exception, e.g. "GPL-2.0-only WITH Classpath-exception-2.0"
+	License string
+}
+
+// String renders the expression in SPDX syntax
+func (e *Expression) String() string {
+	if e == nil {
+		return NoAssertion
+	}
+	if len(e.Operator) == 0 {
+		return e.License
+	}
+	parts := []string{}
+	for _, operand := range e.Operands {
+		part := operand.String()
+		if len(operand.Operator) > 0 && operand.Operator != e.Operator {
+			part = "(" + part + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " "+e.Operator+" ")
+}
+
+// Licenses returns all license identifiers contained in the expression
+func (e *Expression) Licenses() []string {
+	if e == nil {
+		return []string{NoAssertion}
+	}
+	if len(e.Operator) == 0 {
+		return []string{e.License}
+	}
+	licenses := []string{}
+	for _, operand := range e.Operands {
+		licenses = append(licenses, operand.Licenses()...)
+	}
+	return licenses
+}
+
+// ParseExpression parses an SPDX license expression, license identifiers are normalized.
+// Operators are case-insensitive, AND binds stronger than OR.
+func ParseExpression(expression string) (*Expression, error) {
+	tokens := tokenize(expression)
+	if len(tokens) == 0 {
+		return &Expression{License: NoAssertion}, nil
+	}
+	parser := &expressionParser{tokens: tokens}
+	parsed, err := parser.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression '%v': %w", expression, err)
+	}
+	if parser.position < len(tokens) {
+		return nil, fmt.Errorf("invalid license expression '%v': unexpected '%v'", expression, tokens[parser.position])
+	}
+	return parsed, nil
+}
+
+// CombineExpressions joins several license expressions with AND, since all declared licenses need to be respected
+func CombineExpressions(expressions []*Expression) *Expression {
+	switch len(expressions) {
+	case 0:
+		return &Expression{License: NoAssertion}
+	case 1:
+		return expressions[0]
+	}
+	combined := &Expression{Operator: "AND"}
+	known := map[string]bool{}
+	for _, expression := range expressions {
+		if known[expression.String()] {
+			continue
+		}
+		known[expression.String()] = true
+		combined.Operands = append(combined.Operands, expression)
+	}
+	if len(combined.Operands) == 1 {
+		return combined.Operands[0]
+	}
+	return combined
+}
+
+func tokenize(expression string) []string {
+	expression = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)
+	return strings.Fields(expression)
+}
+
+type expressionParser struct {
+	tokens   []string
+	position int
+}
+
+func (p *expressionParser) peek() string {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+	return ""
+}
+
+func (p *expressionParser) parseOr() (*Expression, error) {
+	return p.parseBinary("OR", p.parseAnd)
+}
+
+func (p *expressionParser) parseAnd() (*Expression, error) {
+	return p.parseBinary("AND", p.parsePrimary)
+}
+
+func (p *expressionParser) parseBinary(operator string, parseOperand func() (*Expression, error)) (*Expression, error) {
+	first, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*Expression{first}
+	for strings.EqualFold(p.peek(), operator) {
+		p.position++
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return &Expression{Operator: operator, Operands: operands}, nil
+}
+
+func (p *expressionParser) parsePrimary() (*Expression, error) {
+	token := p.peek()
+	switch {
+	case len(token) == 0:
+		return nil, fmt.Errorf("unexpected end")
+	case token == "(":
+		p.position++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		p.position++
+		return inner, nil
+	case token == ")" || strings.EqualFold(token, "AND") || strings.EqualFold(token, "OR") || strings.EqualFold(token, "WITH"):
+		return nil, fmt.Errorf("unexpected '%v'", token)
+	}
+	p.position++
+	license := NormalizeLicense(token)
+	if strings.EqualFold(p.peek(), "WITH") {
+		p.position++
+		exception := p.peek()
+		if len(exception) == 0 || exception == "(" || exception == ")" {
+			return nil, fmt.Errorf("missing license exception")
+		}
+		p.position++
+		license = license + " WITH " + exception
+	}
+	return &Expression{License: license}, nil
+}
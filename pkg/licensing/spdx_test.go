@@ -0,0 +1,64 @@
+//go:build unit
+
+package licensing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLicense(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"":            NoAssertion,
+		"MIT":         "MIT",
+		"MIT License": "MIT",
+		"The Apache Software License, Version 2.0":        "Apache-2.0",
+		"Apache License, Version 2.0":                     "Apache-2.0",
+		"https://www.apache.org/licenses/LICENSE-2.0.txt": "Apache-2.0",
+		"http://opensource.org/licenses/MIT":              "MIT",
+		"GPLv2":                                           "GPL-2.0-only",
+		"Eclipse Public License - v 2.0":                  "EPL-2.0",
+		"Artistic-2.0":                                    "Artistic-2.0",
+		"Some Proprietary License":                        "LicenseRef-Some-Proprietary-License",
+		"BSD":                                             "LicenseRef-BSD",
+		"BSD License":                                     "LicenseRef-BSD",
+		"BSD-3":                                           "BSD-3-Clause",
+		"Public Domain":                                   "LicenseRef-Public-Domain",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, NormalizeLicense(input), input)
+	}
+}
+
+func TestParseExpression(t *testing.T) {
+	t.Parallel()
+
+	t.Run("precedence and normalization", func(t *testing.T) {
+		expression, err := ParseExpression("mit OR Apache-2.0 and BSD-3")
+		assert.NoError(t, err)
+		assert.Equal(t, "MIT OR (Apache-2.0 AND BSD-3-Clause)", expression.String())
+		assert.Equal(t, []string{"MIT", "Apache-2.0", "BSD-3-Clause"}, expression.Licenses())
+	})
+
+	t.Run("parentheses and exceptions", func(t *testing.T) {
+		expression, err := ParseExpression("(GPL-2.0-only WITH Classpath-exception-2.0 OR MIT) AND ISC")
+		assert.NoError(t, err)
+		assert.Equal(t, "(GPL-2.0-only WITH Classpath-exception-2.0 OR MIT) AND ISC", expression.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, invalid := range []string{"MIT OR", "(MIT", "MIT)", "AND MIT", "GPL-2.0-only WITH"} {
+			_, err := ParseExpression(invalid)
+			assert.Error(t, err, invalid)
+		}
+	})
+
+	t.Run("combine", func(t *testing.T) {
+		mit, _ := ParseExpression("MIT")
+		either, _ := ParseExpression("Apache-2.0 OR MIT")
+		assert.Equal(t, "MIT AND (Apache-2.0 OR MIT)", CombineExpressions([]*Expression{mit, either, mit}).String())
+		assert.Equal(t, NoAssertion, CombineExpressions(nil).String())
+	})
+}
@@ -0,0 +1,154 @@
+package maven
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	packageurl "github.com/package-url/packageurl-go"
+)
+
+// Artifact identifies a resolved maven artifact.
+type Artifact struct {
+	GroupID    string `json:"groupId"`
+	ArtifactID string `json:"artifactId"`
+	Version    string `json:"version"`
+	Type       string `json:"type,omitempty"`
+	Classifier string `json:"classifier,omitempty"`
+	Scope      string `json:"scope,omitempty"`
+}
+
+// Key returns groupId:artifactId of the artifact.
+func (a Artifact) Key() string {
+	return a.GroupID + ":" + a.ArtifactID
+}
+
+// String returns the coordinates of the artifact.
+func (a Artifact) String() string {
+	if a.Classifier != "" {
+		return a.Key() + ":" + a.Version + ":" + a.Classifier
+	}
+	return a.Key() + ":" + a.Version
+}
+
+// ResolvedDependency is a dependency of a module with the path it is introduced by.
+type ResolvedDependency struct {
+	Artifact
+	// Via contains the dependencies introducing the dependency, starting with the direct dependency of the module
+	Via []string `json:"via,omitempty"`
+}
+
+// ModuleDependencies are the resolved dependencies of a module.
+type ModuleDependencies struct {
+	Module string
+	// Version is the version of the module as resolved by maven, e.g. with CI friendly versions replaced
+	Version      string
+	Dependencies []ResolvedDependency
+	// Conflicts contains the dependencies omitted by the version mediation of maven in favour of another version,
+	// they are only known from a verbose dependency tree
+	Conflicts []ResolvedDependency
+}
+
+// DependencyTreeNode is a node of the dependency tree written by the tree goal of the maven-dependency-plugin with outputType json.
+type DependencyTreeNode struct {
+	GroupID    string               `json:"groupId"`
+	ArtifactID string               `json:"artifactId"`
+	Version    string               `json:"version"`
+	Type       string               `json:"type"`
+	Scope      string               `json:"scope"`
+	Classifier string               `json:"classifier"`
+	Children   []DependencyTreeNode `json:"children"`
+}
+
+// ParseDependencyTree reads the dependencies of a module from the JSON output of the dependency tree.
+// A verbose tree also contains the dependencies omitted for a conflict or as duplicates, they are mediated like maven does:
+// the nearest dependency wins and of dependencies with the same depth the first declared one.
+func ParseDependencyTree(content []byte) (ModuleDependencies, error) {
+	var root DependencyTreeNode
+	if err := json.Unmarshal(content, &root); err != nil {
+		return ModuleDependencies{}, fmt.Errorf("failed to parse dependency tree: %w", err)
+	}
+	dependencies := []ResolvedDependency{}
+	collectTreeDependencies(root.Children, []string{}, &dependencies)
+	module := ModuleDependencies{Module: root.GroupID + ":" + root.ArtifactID, Version: root.Version}
+	module.Dependencies, module.Conflicts = mediateVersions(dependencies)
+	return module, nil
+}
+
+// mediateVersions selects one version per dependency, the dependencies are expected in the order of a depth-first traversal of the tree
+func mediateVersions(dependencies []ResolvedDependency) ([]ResolvedDependency, []ResolvedDependency) {
+	mediationKey := func(dependency ResolvedDependency) string {
+		return dependency.Key() + ":" + dependency.Type + ":" + dependency.Classifier
+	}
+	winners := map[string]int{}
+	for i, dependency := range dependencies {
+		key := mediationKey(dependency)
+		if winner, ok := winners[key]; !ok || len(dependency.Via) < len(dependencies[winner].Via) {
+			winners[key] = i
+		}
+	}
+
+	resolved := []ResolvedDependency{}
+	var conflicts []ResolvedDependency
+	for i, dependency := range dependencies {
+		winner := dependencies[winners[mediationKey(dependency)]]
+		switch {
+		case winners[mediationKey(dependency)] == i:
+			resolved = append(resolved, dependency)
+		case dependency.Version != winner.Version:
+			conflicts = append(conflicts, dependency)
+		}
+	}
+	return resolved, conflicts
+}
+
+func collectTreeDependencies(nodes []DependencyTreeNode, via []string, dependencies *[]ResolvedDependency) {
+	for _, node := range nodes {
+		artifact := Artifact{GroupID: node.GroupID, ArtifactID: node.ArtifactID, Version: node.Version, Type: node.Type, Classifier: node.Classifier, Scope: node.Scope}
+		*dependencies = append(*dependencies, ResolvedDependency{Artifact: artifact, Via: via})
+		collectTreeDependencies(node.Children, append(append([]string{}, via...), artifact.String()), dependencies)
+	}
+}
+
+// ParseBOMDependencies reads the maven components of a CycloneDX SBOM in XML or JSON format as dependencies of the component described by the SBOM.
+// Since the SBOM does not contain the dependency paths, the dependencies are considered direct dependencies.
+func ParseBOMDependencies(content []byte) (ModuleDependencies, error) {
+	bomFormat := cdx.BOMFileFormatXML
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '{' {
+		bomFormat = cdx.BOMFileFormatJSON
+	}
+	var bom cdx.BOM
+	if err := cdx.NewBOMDecoder(bytes.NewReader(content), bomFormat).Decode(&bom); err != nil {
+		return ModuleDependencies{}, fmt.Errorf("failed to decode SBOM: %w", err)
+	}
+	module := ModuleDependencies{Dependencies: []ResolvedDependency{}}
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
+		module.Module = bom.Metadata.Component.Group + ":" + bom.Metadata.Component.Name
+		module.Version = bom.Metadata.Component.Version
+	}
+	if bom.Components != nil {
+		collectBOMDependencies(*bom.Components, &module.Dependencies)
+	}
+	return module, nil
+}
+
+func collectBOMDependencies(components []cdx.Component, dependencies *[]ResolvedDependency) {
+	for _, component := range components {
+		artifact := Artifact{GroupID: component.Group, ArtifactID: component.Name, Version: component.Version, Type: "jar"}
+		if purl, err := packageurl.FromString(component.PackageURL); err == nil {
+			if purl.Type != packageurl.TypeMaven {
+				continue
+			}
+			qualifiers := purl.Qualifiers.Map()
+			artifact.Classifier = qualifiers["classifier"]
+			if qualifiers["type"] != "" {
+				artifact.Type = qualifiers["type"]
+			}
+		}
+		*dependencies = append(*dependencies, ResolvedDependency{Artifact: artifact})
+		if component.Components != nil {
+			collectBOMDependencies(*component.Components, dependencies)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+//go:build unit
+// +build unit
+
+package maven
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDependencyTree(t *testing.T) {
+	t.Parallel()
+	t.Run("success case", func(t *testing.T) {
+		module, err := ParseDependencyTree([]byte(`{
+  "groupId": "com.example", "artifactId": "app", "version": "1.0.0", "type": "jar", "scope": "", "classifier": "",
+  "children": [
+    {
+      "groupId": "org.springframework", "artifactId": "spring-web", "version": "6.1.0", "type": "jar", "scope": "compile", "classifier": "",
+      "children": [
+        {"groupId": "org.springframework", "artifactId": "spring-core", "version": "6.1.0", "type": "jar", "scope": "compile", "classifier": ""}
+      ]
+    },
+    {"groupId": "org.junit.jupiter", "artifactId": "junit-jupiter", "version": "5.10.0", "type": "jar", "scope": "test", "classifier": "tests"}
+  ]
+}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, ModuleDependencies{Module: "com.example:app", Version: "1.0.0", Dependencies: []ResolvedDependency{
+			{Artifact: Artifact{GroupID: "org.springframework", ArtifactID: "spring-web", Version: "6.1.0", Type: "jar", Scope: "compile"}, Via: []string{}},
+			{Artifact: Artifact{GroupID: "org.springframework", ArtifactID: "spring-core", Version: "6.1.0", Type: "jar", Scope: "compile"}, Via: []string{"org.springframework:spring-web:6.1.0"}},
+			{Artifact: Artifact{GroupID: "org.junit.jupiter", ArtifactID: "junit-jupiter", Version: "5.10.0", Type: "jar", Classifier: "tests", Scope: "test"}, Via: []string{}},
+		}}, module)
+		assert.Equal(t, "org.junit.jupiter:junit-jupiter:5.10.0:tests", module.Dependencies[2].String())
+	})
+
+	t.Run("verbose tree", func(t *testing.T) {
+		module, err := ParseDependencyTree([]byte(`{
+  "groupId": "com.example", "artifactId": "app", "version": "1.0.0", "type": "jar",
+  "children": [
+    {
+      "groupId": "org.apache.httpcomponents", "artifactId": "httpclient", "version": "4.5.14", "type": "jar", "scope": "compile",
+      "children": [
+        {"groupId": "commons-codec", "artifactId": "commons-codec", "version": "1.11", "type": "jar", "scope": "compile"},
+        {"groupId": "org.slf4j", "artifactId": "slf4j-api", "version": "2.0.9", "type": "jar", "scope": "compile"}
+      ]
+    },
+    {"groupId": "commons-codec", "artifactId": "commons-codec", "version": "1.16.0", "type": "jar", "scope": "compile"},
+    {
+      "groupId": "com.acme", "artifactId": "lib", "version": "1.0.0", "type": "jar", "scope": "compile",
+      "children": [
+        {"groupId": "org.slf4j", "artifactId": "slf4j-api", "version": "2.0.9", "type": "jar", "scope": "compile"}
+      ]
+    }
+  ]
+}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, []ResolvedDependency{
+			{Artifact: Artifact{GroupID: "org.apache.httpcomponents", ArtifactID: "httpclient", Version: "4.5.14", Type: "jar", Scope: "compile"}, Via: []string{}},
+			{Artifact: Artifact{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "2.0.9", Type: "jar", Scope: "compile"}, Via: []string{"org.apache.httpcomponents:httpclient:4.5.14"}},
+			{Artifact: Artifact{GroupID: "commons-codec", ArtifactID: "commons-codec", Version: "1.16.0", Type: "jar", Scope: "compile"}, Via: []string{}},
+			{Artifact: Artifact{GroupID: "com.acme", ArtifactID: "lib", Version: "1.0.0", Type: "jar", Scope: "compile"}, Via: []string{}},
+		}, module.Dependencies)
+		assert.Equal(t, []ResolvedDependency{
+			{Artifact: Artifact{GroupID: "commons-codec", ArtifactID: "commons-codec", Version: "1.11", Type: "jar", Scope: "compile"}, Via: []string{"org.apache.httpcomponents:httpclient:4.5.14"}},
+		}, module.Conflicts)
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		_, err := ParseDependencyTree([]byte("com.example:app:jar:1.0.0"))
+		assert.ErrorContains(t, err, "failed to parse dependency tree")
+	})
+}
+
+func TestParseBOMDependencies(t *testing.T) {
+	t.Parallel()
+	t.Run("XML", func(t *testing.T) {
+		module, err := ParseBOMDependencies([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.4" version="1">
+  <metadata>
+    <component type="library">
+      <group>com.example</group>
+      <name>app</name>
+      <version>1.0.0</version>
+    </component>
+  </metadata>
+  <components>
+    <component type="library">
+      <group>org.springframework</group>
+      <name>spring-core</name>
+      <version>6.1.0</version>
+      <purl>pkg:maven/org.springframework/spring-core@6.1.0?type=jar</purl>
+    </component>
+    <component type="library">
+      <group>io.netty</group>
+      <name>netty-transport-native-epoll</name>
+      <version>4.1.100.Final</version>
+      <purl>pkg:maven/io.netty/netty-transport-native-epoll@4.1.100.Final?classifier=linux-x86_64&amp;type=jar</purl>
+    </component>
+    <component type="library">
+      <name>left-pad</name>
+      <version>1.3.0</version>
+      <purl>pkg:npm/left-pad@1.3.0</purl>
+    </component>
+  </components>
+</bom>`))
+
+		require.NoError(t, err)
+		assert.Equal(t, ModuleDependencies{Module: "com.example:app", Version: "1.0.0", Dependencies: []ResolvedDependency{
+			{Artifact: Artifact{GroupID: "org.springframework", ArtifactID: "spring-core", Version: "6.1.0", Type: "jar"}},
+			{Artifact: Artifact{GroupID: "io.netty", ArtifactID: "netty-transport-native-epoll", Version: "4.1.100.Final", Type: "jar", Classifier: "linux-x86_64"}},
+		}}, module)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		module, err := ParseBOMDependencies([]byte(`{"bomFormat": "CycloneDX", "specVersion": "1.4", "version": 1,
+  "components": [{"type": "library", "group": "org.slf4j", "name": "slf4j-api", "version": "2.0.9", "purl": "pkg:maven/org.slf4j/slf4j-api@2.0.9?type=jar"}]}`))
+
+		require.NoError(t, err)
+		assert.Empty(t, module.Module)
+		assert.Equal(t, []ResolvedDependency{{Artifact: Artifact{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "2.0.9", Type: "jar"}}}, module.Dependencies)
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		_, err := ParseBOMDependencies([]byte("<bom"))
+		assert.ErrorContains(t, err, "failed to decode SBOM")
+	})
+}
@@ -0,0 +1,260 @@
+package maven
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// checks of the dependency analysis
+const (
+	CheckVersionConflicts   = "versionConflicts"
+	CheckSnapshots          = "snapshots"
+	CheckBannedDependencies = "bannedDependencies"
+	CheckDuplicateClasses   = "duplicateClasses"
+)
+
+// severities of dependency issues, issues of checks the build fails on are errors
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// DependencyIssue is a violation of the dependency policy.
+type DependencyIssue struct {
+	Check      string `json:"check"`
+	Module     string `json:"module,omitempty"`
+	Dependency string `json:"dependency"`
+	Message    string `json:"message"`
+	Severity   string `json:"severity"`
+}
+
+// DependencyAnalysisOptions configure the dependency analysis.
+type DependencyAnalysisOptions struct {
+	Checks []string
+	// FailOn contains the checks whose issues are errors
+	FailOn []string
+	// BannedDependencies contains patterns groupId[:artifactId[:version]], each part may contain wildcards
+	BannedDependencies []string
+	// ReleaseBuild enables the check for SNAPSHOT dependencies
+	ReleaseBuild bool
+	// ProjectModules contains groupId:artifactId of the modules of the project, which are not analyzed as dependencies
+	ProjectModules []string
+	// LocalRepository is the local maven repository containing the jars of the dependencies
+	LocalRepository string
+}
+
+// AnalyzeDependencies checks the resolved dependencies of the modules for version conflicts within and across modules, SNAPSHOT dependencies in release builds,
+// banned dependencies and classes contained in more than one jar of a module.
+func AnalyzeDependencies(modules []ModuleDependencies, options DependencyAnalysisOptions, utils fileReader) ([]DependencyIssue, error) {
+	for _, pattern := range options.BannedDependencies {
+		if _, err := path.Match(pattern, ""); err != nil || strings.Count(pattern, ":") > 2 {
+			return nil, fmt.Errorf("invalid banned dependency '%v', expected groupId[:artifactId[:version]]", pattern)
+		}
+	}
+
+	issues := []DependencyIssue{}
+	if slices.Contains(options.Checks, CheckVersionConflicts) {
+		issues = append(issues, versionConflicts(modules, options)...)
+	}
+	for _, module := range modules {
+		for _, dependency := range module.Dependencies {
+			if slices.Contains(options.ProjectModules, dependency.Key()) {
+				continue
+			}
+			if slices.Contains(options.Checks, CheckSnapshots) && options.ReleaseBuild && strings.HasSuffix(dependency.Version, "-SNAPSHOT") {
+				issues = append(issues, DependencyIssue{Check: CheckSnapshots, Module: module.Module, Dependency: dependency.String(), Message: "SNAPSHOT dependency in a release build" + viaText(dependency)})
+			}
+			if slices.Contains(options.Checks, CheckBannedDependencies) {
+				if pattern, banned := bannedBy(dependency.Artifact, options.BannedDependencies); banned {
+					issues = append(issues, DependencyIssue{Check: CheckBannedDependencies, Module: module.Module, Dependency: dependency.String(), Message: fmt.Sprintf("banned by '%v'%v", pattern, viaText(dependency))})
+				}
+			}
+		}
+	}
+	if slices.Contains(options.Checks, CheckDuplicateClasses) {
+		duplicates, err := duplicateClasses(modules, options, utils)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, duplicates...)
+	}
+
+	for i := range issues {
+		issues[i].Severity = SeverityWarning
+		if slices.Contains(options.FailOn, issues[i].Check) {
+			issues[i].Severity = SeverityError
+		}
+	}
+	return issues, nil
+}
+
+// CountIssues returns the number of issues of the severity.
+func CountIssues(issues []DependencyIssue, severity string) int {
+	count := 0
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+func viaText(dependency ResolvedDependency) string {
+	if len(dependency.Via) == 0 {
+		return ""
+	}
+	return " via " + strings.Join(dependency.Via, " -> ")
+}
+
+// versionConflicts finds dependencies required in different versions within a module and dependencies resolved in different versions by the modules of the project
+func versionConflicts(modules []ModuleDependencies, options DependencyAnalysisOptions) []DependencyIssue {
+	issues := []DependencyIssue{}
+	for _, module := range modules {
+		for _, conflict := range module.Conflicts {
+			if slices.Contains(options.ProjectModules, conflict.Key()) {
+				continue
+			}
+			index := slices.IndexFunc(module.Dependencies, func(dependency ResolvedDependency) bool {
+				return dependency.Key() == conflict.Key() && dependency.Type == conflict.Type && dependency.Classifier == conflict.Classifier
+			})
+			if index < 0 {
+				continue
+			}
+			issues = append(issues, DependencyIssue{Check: CheckVersionConflicts, Module: module.Module, Dependency: conflict.String(),
+				Message: fmt.Sprintf("omitted for conflict with %v%v", module.Dependencies[index].Version, viaText(conflict))})
+		}
+	}
+
+	versions := map[string]map[string][]string{}
+	for _, module := range modules {
+		for _, dependency := range module.Dependencies {
+			if slices.Contains(options.ProjectModules, dependency.Key()) {
+				continue
+			}
+			if versions[dependency.Key()] == nil {
+				versions[dependency.Key()] = map[string][]string{}
+			}
+			if !slices.Contains(versions[dependency.Key()][dependency.Version], module.Module) {
+				versions[dependency.Key()][dependency.Version] = append(versions[dependency.Key()][dependency.Version], module.Module)
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(versions) {
+		if len(versions[key]) < 2 {
+			continue
+		}
+		usages := []string{}
+		for _, version := range sortedKeys(versions[key]) {
+			usages = append(usages, fmt.Sprintf("%v (%v)", version, strings.Join(versions[key][version], ", ")))
+		}
+		issues = append(issues, DependencyIssue{Check: CheckVersionConflicts, Dependency: key, Message: "resolved in different versions: " + strings.Join(usages, ", ")})
+	}
+	return issues
+}
+
+func bannedBy(artifact Artifact, patterns []string) (string, bool) {
+	parts := []string{artifact.GroupID, artifact.ArtifactID, artifact.Version}
+	for _, pattern := range patterns {
+		matched := true
+		for i, patternPart := range strings.Split(pattern, ":") {
+			if ok, _ := path.Match(patternPart, parts[i]); !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// duplicateClasses finds classes contained in more than one jar on the classpath of a module
+func duplicateClasses(modules []ModuleDependencies, options DependencyAnalysisOptions, utils fileReader) ([]DependencyIssue, error) {
+	classesByJar := map[string][]string{}
+	issues := []DependencyIssue{}
+	for _, module := range modules {
+		jarsByClass := map[string][]string{}
+		for _, dependency := range module.Dependencies {
+			if dependency.Scope == "test" || (dependency.Type != "" && dependency.Type != "jar") || slices.Contains(options.ProjectModules, dependency.Key()) {
+				continue
+			}
+			classes, ok := classesByJar[dependency.String()]
+			if !ok {
+				var err error
+				if classes, err = jarClasses(localRepositoryJar(options.LocalRepository, dependency.Artifact), utils); err != nil {
+					return nil, fmt.Errorf("failed to read classes of %v: %w", dependency.String(), err)
+				}
+				classesByJar[dependency.String()] = classes
+			}
+			for _, class := range classes {
+				if !slices.Contains(jarsByClass[class], dependency.String()) {
+					jarsByClass[class] = append(jarsByClass[class], dependency.String())
+				}
+			}
+		}
+
+		// classes are reported per combination of jars containing them
+		duplicatesByJars := map[string][]string{}
+		for class, jars := range jarsByClass {
+			if len(jars) > 1 {
+				slices.Sort(jars)
+				duplicatesByJars[strings.Join(jars, ", ")] = append(duplicatesByJars[strings.Join(jars, ", ")], class)
+			}
+		}
+		for _, jars := range sortedKeys(duplicatesByJars) {
+			classes := duplicatesByJars[jars]
+			slices.Sort(classes)
+			issues = append(issues, DependencyIssue{Check: CheckDuplicateClasses, Module: module.Module, Dependency: jars, Message: fmt.Sprintf("%v class(es) contained in more than one jar, e.g. %v", len(classes), classes[0])})
+		}
+	}
+	return issues, nil
+}
+
+func localRepositoryJar(localRepository string, artifact Artifact) string {
+	name := artifact.ArtifactID + "-" + artifact.Version
+	if artifact.Classifier != "" {
+		name += "-" + artifact.Classifier
+	}
+	return filepath.Join(localRepository, filepath.FromSlash(strings.ReplaceAll(artifact.GroupID, ".", "/")), artifact.ArtifactID, artifact.Version, name+".jar")
+}
+
+// jarClasses returns the class names in the jar, jars missing in the local repository are skipped
+func jarClasses(jar string, utils fileReader) ([]string, error) {
+	content, err := utils.FileRead(jar)
+	if err != nil {
+		log.Entry().Debugf("Skipping '%v' in duplicate class check: %v", jar, err)
+		return []string{}, nil
+	}
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+	classes := []string{}
+	for _, file := range reader.File {
+		// module descriptors and classes for specific Java versions of multi-release jars are no duplicates
+		if !strings.HasSuffix(file.Name, ".class") || path.Base(file.Name) == "module-info.class" || strings.HasPrefix(file.Name, "META-INF/") {
+			continue
+		}
+		classes = append(classes, strings.ReplaceAll(strings.TrimSuffix(file.Name, ".class"), "/", "."))
+	}
+	return classes, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,126 @@
+//go:build unit
+// +build unit
+
+package maven
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func dependency(coordinates string, via ...string) ResolvedDependency {
+	parts := strings.Split(coordinates, ":")
+	return ResolvedDependency{Artifact: Artifact{GroupID: parts[0], ArtifactID: parts[1], Version: parts[2], Type: "jar", Scope: "compile"}, Via: via}
+}
+
+func TestAnalyzeDependencies(t *testing.T) {
+	t.Parallel()
+	modules := []ModuleDependencies{
+		{Module: "com.example:app", Dependencies: []ResolvedDependency{
+			dependency("com.example:core:1.0.0-SNAPSHOT"),
+			dependency("org.slf4j:slf4j-api:2.0.9"),
+			dependency("commons-logging:commons-logging:1.2", "org.apache.httpcomponents:httpclient:4.5.14"),
+		}},
+		{Module: "com.example:core", Dependencies: []ResolvedDependency{
+			dependency("org.slf4j:slf4j-api:1.7.36"),
+			dependency("com.acme:snapshot-lib:0.1-SNAPSHOT"),
+		}},
+	}
+
+	t.Run("all checks", func(t *testing.T) {
+		issues, err := AnalyzeDependencies(modules, DependencyAnalysisOptions{
+			Checks:             []string{CheckVersionConflicts, CheckSnapshots, CheckBannedDependencies},
+			FailOn:             []string{CheckBannedDependencies},
+			BannedDependencies: []string{"commons-logging", "org.slf4j:*:1.*"},
+			ReleaseBuild:       true,
+			ProjectModules:     []string{"com.example:app", "com.example:core"},
+		}, &mock.FilesMock{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []DependencyIssue{
+			{Check: CheckVersionConflicts, Dependency: "org.slf4j:slf4j-api", Message: "resolved in different versions: 1.7.36 (com.example:core), 2.0.9 (com.example:app)", Severity: SeverityWarning},
+			{Check: CheckBannedDependencies, Module: "com.example:app", Dependency: "commons-logging:commons-logging:1.2", Message: "banned by 'commons-logging' via org.apache.httpcomponents:httpclient:4.5.14", Severity: SeverityError},
+			{Check: CheckBannedDependencies, Module: "com.example:core", Dependency: "org.slf4j:slf4j-api:1.7.36", Message: "banned by 'org.slf4j:*:1.*'", Severity: SeverityError},
+			{Check: CheckSnapshots, Module: "com.example:core", Dependency: "com.acme:snapshot-lib:0.1-SNAPSHOT", Message: "SNAPSHOT dependency in a release build", Severity: SeverityWarning},
+		}, issues)
+		assert.Equal(t, 2, CountIssues(issues, SeverityError))
+		assert.Equal(t, 2, CountIssues(issues, SeverityWarning))
+	})
+
+	t.Run("snapshots allowed in snapshot builds", func(t *testing.T) {
+		issues, err := AnalyzeDependencies(modules, DependencyAnalysisOptions{Checks: []string{CheckSnapshots}}, &mock.FilesMock{})
+
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+	})
+
+	t.Run("version conflicts within a module", func(t *testing.T) {
+		converging := []ModuleDependencies{{
+			Module: "com.example:app",
+			Dependencies: []ResolvedDependency{
+				dependency("org.apache.httpcomponents:httpclient:4.5.14"),
+				dependency("commons-codec:commons-codec:1.16.0"),
+			},
+			Conflicts: []ResolvedDependency{dependency("commons-codec:commons-codec:1.11", "org.apache.httpcomponents:httpclient:4.5.14")},
+		}}
+
+		issues, err := AnalyzeDependencies(converging, DependencyAnalysisOptions{Checks: []string{CheckVersionConflicts}, FailOn: []string{CheckVersionConflicts}}, &mock.FilesMock{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []DependencyIssue{
+			{Check: CheckVersionConflicts, Module: "com.example:app", Dependency: "commons-codec:commons-codec:1.11", Message: "omitted for conflict with 1.16.0 via org.apache.httpcomponents:httpclient:4.5.14", Severity: SeverityError},
+		}, issues)
+	})
+
+	t.Run("duplicate classes", func(t *testing.T) {
+		localRepository := filepath.Join("home", ".m2", "repository")
+		files := &mock.FilesMock{}
+		modified := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+		files.AddFile(filepath.Join(localRepository, "javax", "servlet", "servlet-api", "2.5", "servlet-api-2.5.jar"), createZip(t, modified,
+			zipEntry{"META-INF/MANIFEST.MF", "Manifest-Version: 1.0\n"},
+			zipEntry{"javax/servlet/Servlet.class", "servlet"},
+			zipEntry{"javax/servlet/Filter.class", "filter"},
+		))
+		files.AddFile(filepath.Join(localRepository, "jakarta", "servlet", "jakarta.servlet-api", "4.0.4", "jakarta.servlet-api-4.0.4.jar"), createZip(t, modified,
+			zipEntry{"META-INF/MANIFEST.MF", "Manifest-Version: 1.0\n"},
+			zipEntry{"module-info.class", "module"},
+			zipEntry{"javax/servlet/Servlet.class", "servlet"},
+			zipEntry{"javax/servlet/Filter.class", "filter"},
+		))
+		files.AddFile(filepath.Join(localRepository, "com", "example", "test-utils", "1.0", "test-utils-1.0.jar"), createZip(t, modified,
+			zipEntry{"javax/servlet/Servlet.class", "servlet"},
+		))
+		testDependency := dependency("com.example:test-utils:1.0")
+		testDependency.Scope = "test"
+		modules := []ModuleDependencies{{Module: "com.example:web", Dependencies: []ResolvedDependency{
+			dependency("javax.servlet:servlet-api:2.5"),
+			dependency("jakarta.servlet:jakarta.servlet-api:4.0.4"),
+			dependency("org.missing:missing:1.0"),
+			testDependency,
+		}}}
+
+		issues, err := AnalyzeDependencies(modules, DependencyAnalysisOptions{Checks: []string{CheckDuplicateClasses}, LocalRepository: localRepository}, files)
+
+		require.NoError(t, err)
+		assert.Equal(t, []DependencyIssue{{
+			Check:      CheckDuplicateClasses,
+			Module:     "com.example:web",
+			Dependency: "jakarta.servlet:jakarta.servlet-api:4.0.4, javax.servlet:servlet-api:2.5",
+			Message:    "2 class(es) contained in more than one jar, e.g. javax.servlet.Filter",
+			Severity:   SeverityWarning,
+		}}, issues)
+	})
+
+	t.Run("invalid banned dependency", func(t *testing.T) {
+		_, err := AnalyzeDependencies(modules, DependencyAnalysisOptions{Checks: []string{CheckBannedDependencies}, BannedDependencies: []string{"org.slf4j:slf4j-api:1.7.36:jar"}}, &mock.FilesMock{})
+
+		assert.EqualError(t, err, "invalid banned dependency 'org.slf4j:slf4j-api:1.7.36:jar', expected groupId[:artifactId[:version]]")
+	})
+}
@@ -0,0 +1,120 @@
+package maven
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// DependencyAnalysisDirectory defines the subfolder for the dependency analysis reports
+const DependencyAnalysisDirectory = "dependency-analysis"
+
+// CreateDependencyScanReport creates a report of the dependency analysis for the pipeline summary
+func CreateDependencyScanReport(projectName string, modules []ModuleDependencies, checks []string, issues []DependencyIssue, reportTime time.Time) reporting.ScanReport {
+	errorCount := CountIssues(issues, SeverityError)
+	warningCount := CountIssues(issues, SeverityWarning)
+	dependencies := map[string]bool{}
+	for _, module := range modules {
+		for _, dependency := range module.Dependencies {
+			dependencies[dependency.String()] = true
+		}
+	}
+
+	scanReport := reporting.ScanReport{
+		ReportTitle: "Maven Dependency Analysis Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Project", Details: projectName},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Analyzed modules", Details: fmt.Sprint(len(modules))},
+			{Description: "Resolved dependencies", Details: fmt.Sprint(len(dependencies))},
+			{Description: "Executed checks", Details: fmt.Sprint(len(checks))},
+			{Description: "Errors", Details: fmt.Sprint(errorCount), Style: styleIfNotZero(errorCount, reporting.Red)},
+			{Description: "Warnings", Details: fmt.Sprint(warningCount), Style: styleIfNotZero(warningCount, reporting.Yellow)},
+		},
+		ReportTime:     reportTime,
+		SuccessfulScan: errorCount == 0,
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No issues found",
+		Headers:       []string{"Check", "Module", "Dependency", "Issue", "Severity"},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, issue := range issues {
+		row := reporting.ScanRow{}
+		row.AddColumn(issue.Check, 0)
+		row.AddColumn(issue.Module, 0)
+		row.AddColumn(issue.Dependency, 0)
+		row.AddColumn(issue.Message, 0)
+		row.AddColumn(issue.Severity, severityStyle(issue.Severity))
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+	return scanReport
+}
+
+func severityStyle(severity string) reporting.ColumnStyle {
+	if severity == SeverityError {
+		return reporting.Red
+	}
+	return reporting.Yellow
+}
+
+func styleIfNotZero(count int, style reporting.ColumnStyle) reporting.ColumnStyle {
+	if count > 0 {
+		return style
+	}
+	return 0
+}
+
+type reportUtils interface {
+	MkdirAll(path string, perm os.FileMode) error
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+// WriteDependencyAnalysisReports writes the issues as JSON and HTML report as well as the JSON report used by the pipeline summary
+func WriteDependencyAnalysisReports(scanReport reporting.ScanReport, issues []DependencyIssue, stepName string, utils reportUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+	if err := utils.MkdirAll(DependencyAnalysisDirectory, 0777); err != nil {
+		return reportPaths, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	jsonReport, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to marshal issues: %w", err)
+	}
+	jsonReportPath := filepath.Join(DependencyAnalysisDirectory, "piper_maven_dependency_analysis_report.json")
+	if err := utils.FileWrite(jsonReportPath, jsonReport, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write JSON report: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Maven dependency analysis JSON report", Target: jsonReportPath})
+
+	htmlReport, err := scanReport.ToHTML()
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to create HTML report: %w", err)
+	}
+	htmlReportPath := filepath.Join(DependencyAnalysisDirectory, "piper_maven_dependency_analysis_report.html")
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Maven dependency analysis report", Target: htmlReportPath})
+
+	// JSON reports are used by step pipelineCreateScanSummary
+	// ignore JSON errors since structure is in our hands
+	stepReport, _ := scanReport.ToJSON()
+	if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+		return reportPaths, fmt.Errorf("failed to create step reporting directory: %w", err)
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, stepName+".json"), stepReport, 0666); err != nil {
+		return reportPaths, fmt.Errorf("failed to write step report: %w", err)
+	}
+
+	return reportPaths, nil
+}
@@ -0,0 +1,51 @@
+//go:build unit
+// +build unit
+
+package maven
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+func TestDependencyAnalysisReports(t *testing.T) {
+	t.Parallel()
+	modules := []ModuleDependencies{
+		{Module: "com.example:app", Dependencies: []ResolvedDependency{dependency("org.slf4j:slf4j-api:2.0.9"), dependency("commons-logging:commons-logging:1.2")}},
+		{Module: "com.example:core", Dependencies: []ResolvedDependency{dependency("org.slf4j:slf4j-api:2.0.9")}},
+	}
+	issues := []DependencyIssue{
+		{Check: CheckBannedDependencies, Module: "com.example:app", Dependency: "commons-logging:commons-logging:1.2", Message: "banned by 'commons-logging'", Severity: SeverityError},
+	}
+
+	scanReport := CreateDependencyScanReport("com.example:parent", modules, []string{CheckBannedDependencies, CheckSnapshots}, issues, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC))
+
+	assert.False(t, scanReport.SuccessfulScan)
+	assert.Equal(t, []reporting.OverviewRow{
+		{Description: "Analyzed modules", Details: "2"},
+		{Description: "Resolved dependencies", Details: "2"},
+		{Description: "Executed checks", Details: "2"},
+		{Description: "Errors", Details: "1", Style: reporting.Red},
+		{Description: "Warnings", Details: "0"},
+	}, scanReport.Overview)
+	require.Len(t, scanReport.DetailTable.Rows, 1)
+	assert.Equal(t, "banned by 'commons-logging'", scanReport.DetailTable.Rows[0].Columns[3].Content)
+
+	files := &mock.FilesMock{}
+	reports, err := WriteDependencyAnalysisReports(scanReport, issues, "mavenBuild", files)
+
+	require.NoError(t, err)
+	assert.Len(t, reports, 2)
+	content, err := files.FileRead(filepath.Join(DependencyAnalysisDirectory, "piper_maven_dependency_analysis_report.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"dependency": "commons-logging:commons-logging:1.2"`)
+	assert.True(t, files.HasFile(filepath.Join(DependencyAnalysisDirectory, "piper_maven_dependency_analysis_report.html")))
+	assert.True(t, files.HasFile(filepath.Join(reporting.StepReportDirectory, "mavenBuild.json")))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"slices"
+	"strings"
 )
 
 // Project describes the Maven object model.
@@ -18,6 +19,28 @@ type Project struct {
 	Name         string       `xml:"name"`
 	Dependencies []Dependency `xml:"dependencies>dependency"`
 	Modules      []string     `xml:"modules>module"`
+	Properties   Properties   `xml:"properties"`
+}
+
+// Properties contains the properties defined in the POM.
+type Properties map[string]string
+
+// UnmarshalXML reads the properties, which are elements with arbitrary names.
+func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var properties struct {
+		Entries []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	}
+	if err := d.DecodeElement(&properties, &start); err != nil {
+		return err
+	}
+	*p = Properties{}
+	for _, entry := range properties.Entries {
+		(*p)[entry.XMLName.Local] = strings.TrimSpace(entry.Value)
+	}
+	return nil
 }
 
 // Parent describes the coordinates a module's parent POM.
@@ -0,0 +1,229 @@
+package maven
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+)
+
+// OutputTimestampProperty is the property which makes the maven archivers create reproducible archives.
+const OutputTimestampProperty = "project.build.outputTimestamp"
+
+// manifestAttributesIgnored are manifest attributes depending on the build environment instead of the sources
+var manifestAttributesIgnored = []string{"Build-Jdk", "Build-Jdk-Spec", "Built-By", "Build-Time", "Build-Timestamp", "Bnd-LastModified", "Created-By"}
+
+// ReproducibilityReport describes the result of verifying a maven build is reproducible.
+type ReproducibilityReport struct {
+	ModulesWithoutOutputTimestamp []string             `json:"modulesWithoutOutputTimestamp"`
+	Artifacts                     []ArtifactComparison `json:"artifacts"`
+}
+
+// ArtifactComparison describes the differences of a built artifact to its reference.
+type ArtifactComparison struct {
+	Artifact    string              `json:"artifact"`
+	Reference   string              `json:"reference"`
+	Differences []ArchiveDifference `json:"differences"`
+}
+
+// ArchiveDifference describes an entry which differs between two archives.
+// Entries of nested archives are separated by '!/' from the entry of the nested archive.
+type ArchiveDifference struct {
+	Entry  string `json:"entry"`
+	Reason string `json:"reason"`
+}
+
+// Reproducible returns true if all modules define the output timestamp and no artifact differs.
+func (r *ReproducibilityReport) Reproducible() bool {
+	return len(r.ModulesWithoutOutputTimestamp) == 0 && len(r.DifferingArtifacts()) == 0
+}
+
+// DifferingArtifacts returns the comparisons of the artifacts which differ from their reference.
+func (r *ReproducibilityReport) DifferingArtifacts() []ArtifactComparison {
+	differing := []ArtifactComparison{}
+	for _, artifact := range r.Artifacts {
+		if len(artifact.Differences) > 0 {
+			differing = append(differing, artifact)
+		}
+	}
+	return differing
+}
+
+// ModulesWithoutOutputTimestamp returns the pom.xml files of all modules which neither define the property
+// project.build.outputTimestamp nor inherit it from a parent within the project.
+// Parents outside of the project, e.g. a company wide parent POM, are not considered.
+func ModulesWithoutOutputTimestamp(path string, utils visitUtils) ([]string, error) {
+	modules := []ModuleInfo{}
+	err := VisitAllMavenModules(path, utils, nil, func(info ModuleInfo) error {
+		modules = append(modules, info)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	projects := map[string]*Project{}
+	for _, module := range modules {
+		projects[projectKey(module.Project.GroupID, module.Project.Parent.GroupID, module.Project.ArtifactID)] = module.Project
+	}
+	missing := []string{}
+	for _, module := range modules {
+		if !definesOutputTimestamp(module.Project, projects, 0) {
+			missing = append(missing, module.PomXMLPath)
+		}
+	}
+	return missing, nil
+}
+
+func projectKey(groupID, parentGroupID, artifactID string) string {
+	if groupID == "" {
+		groupID = parentGroupID
+	}
+	return groupID + ":" + artifactID
+}
+
+func definesOutputTimestamp(project *Project, projects map[string]*Project, depth int) bool {
+	if _, ok := project.Properties[OutputTimestampProperty]; ok {
+		return true
+	}
+	parent, ok := projects[projectKey(project.Parent.GroupID, "", project.Parent.ArtifactID)]
+	// the depth prevents endless recursion for projects inheriting from themselves
+	if !ok || depth > len(projects) {
+		return false
+	}
+	return definesOutputTimestamp(parent, projects, depth+1)
+}
+
+type fileReader interface {
+	FileRead(path string) ([]byte, error)
+}
+
+// CompareArchives compares the entries of two jar or war files and returns the entries which differ.
+// Entry timestamps and other archive metadata, manifest attributes describing the build environment and comments of pom.properties are ignored.
+// Nested archives, e.g. libraries of a war, are compared entry by entry with the same rules.
+func CompareArchives(reference, actual string, utils fileReader) ([]ArchiveDifference, error) {
+	referenceContent, err := utils.FileRead(reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%v': %w", reference, err)
+	}
+	actualContent, err := utils.FileRead(actual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%v': %w", actual, err)
+	}
+	differences, err := compareZip(referenceContent, actualContent, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare '%v' with '%v': %w", actual, reference, err)
+	}
+	return differences, nil
+}
+
+func compareZip(referenceContent, actualContent []byte, prefix string) ([]ArchiveDifference, error) {
+	referenceEntries, err := readZip(referenceContent)
+	if err != nil {
+		return nil, err
+	}
+	actualEntries, err := readZip(actualContent)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for name := range referenceEntries {
+		names = append(names, name)
+	}
+	for name := range actualEntries {
+		if _, ok := referenceEntries[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	differences := []ArchiveDifference{}
+	for _, name := range names {
+		referenceEntry, inReference := referenceEntries[name]
+		actualEntry, inActual := actualEntries[name]
+		switch {
+		case !inReference:
+			differences = append(differences, ArchiveDifference{Entry: prefix + name, Reason: "not contained in the reference"})
+		case !inActual:
+			differences = append(differences, ArchiveDifference{Entry: prefix + name, Reason: "missing compared to the reference"})
+		case bytes.Equal(normalizeEntry(name, referenceEntry), normalizeEntry(name, actualEntry)):
+			continue
+		case isArchive(name):
+			// nested archives are compared like the archive itself, differences in their metadata only, e.g. in the timestamps of their entries, are ignored
+			nested, err := compareZip(referenceEntry, actualEntry, prefix+name+"!/")
+			if err != nil {
+				differences = append(differences, ArchiveDifference{Entry: prefix + name, Reason: "content differs"})
+				continue
+			}
+			differences = append(differences, nested...)
+		default:
+			differences = append(differences, ArchiveDifference{Entry: prefix + name, Reason: "content differs"})
+		}
+	}
+	return differences, nil
+}
+
+func readZip(content []byte) (map[string][]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+	entries := map[string][]byte{}
+	for _, file := range reader.File {
+		entryReader, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open entry '%v': %w", file.Name, err)
+		}
+		entry, err := io.ReadAll(entryReader)
+		entryReader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read entry '%v': %w", file.Name, err)
+		}
+		entries[file.Name] = entry
+	}
+	return entries, nil
+}
+
+func isArchive(name string) bool {
+	return slices.Contains([]string{".jar", ".war", ".ear", ".zip"}, path.Ext(name))
+}
+
+// normalizeEntry removes the content of known nondeterministic metadata files which does not depend on the sources
+func normalizeEntry(name string, content []byte) []byte {
+	switch {
+	case name == "META-INF/MANIFEST.MF":
+		return filterLines(content, func(line string) bool {
+			attribute, _, _ := strings.Cut(line, ":")
+			return !slices.Contains(manifestAttributesIgnored, attribute)
+		})
+	case strings.HasPrefix(name, "META-INF/maven/") && strings.HasSuffix(name, "/pom.properties"):
+		return filterLines(content, func(line string) bool {
+			return !strings.HasPrefix(line, "#")
+		})
+	default:
+		return content
+	}
+}
+
+// filterLines keeps the lines accepted by the filter, continuation lines of the manifest share the decision of their attribute
+func filterLines(content []byte, keep func(line string) bool) []byte {
+	var filtered bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	kept := true
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if !strings.HasPrefix(line, " ") {
+			kept = keep(line)
+		}
+		if kept {
+			filtered.WriteString(line)
+			filtered.WriteString("\n")
+		}
+	}
+	return filtered.Bytes()
+}
@@ -0,0 +1,143 @@
+//go:build unit
+// +build unit
+
+package maven
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+type zipEntry struct {
+	name    string
+	content string
+}
+
+func createZip(t *testing.T, modified time.Time, entries ...zipEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		file, err := writer.CreateHeader(&zip.FileHeader{Name: entry.name, Method: zip.Deflate, Modified: modified})
+		require.NoError(t, err)
+		_, err = file.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestCompareArchives(t *testing.T) {
+	t.Parallel()
+	firstBuild := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	secondBuild := firstBuild.Add(time.Hour)
+	library := func(modified time.Time, content string) zipEntry {
+		return zipEntry{"WEB-INF/lib/lib.jar", string(createZip(t, modified, zipEntry{"lib/Lib.class", content}))}
+	}
+
+	files := &mock.FilesMock{}
+	files.AddFile("reference/app.war", createZip(t, firstBuild,
+		zipEntry{"META-INF/MANIFEST.MF", "Manifest-Version: 1.0\r\nCreated-By: Maven WAR Plugin 3.4.0\r\nBuild-Jdk-Spec: 17\r\nImplementation-Title: app\r\n"},
+		zipEntry{"META-INF/maven/com.example/app/pom.properties", "#Generated by Maven\n#Mon Oct 19 08:00:00 UTC 2026\nartifactId=app\n"},
+		zipEntry{"WEB-INF/classes/App.class", "app"},
+		zipEntry{"WEB-INF/classes/Removed.class", "removed"},
+		library(firstBuild, "lib"),
+	))
+	files.AddFile("target/app.war", createZip(t, secondBuild,
+		zipEntry{"META-INF/MANIFEST.MF", "Manifest-Version: 1.0\r\nCreated-By: Maven WAR Plugin 3.4.0\r\nBuild-Jdk-Spec: 21\r\nImplementation-Title: app\r\n"},
+		zipEntry{"META-INF/maven/com.example/app/pom.properties", "#Generated by Maven\n#Mon Oct 19 09:00:00 UTC 2026\nartifactId=app\n"},
+		zipEntry{"WEB-INF/classes/App.class", "app changed"},
+		zipEntry{"WEB-INF/classes/Added.class", "added"},
+		library(secondBuild, "lib changed"),
+	))
+
+	t.Run("differences", func(t *testing.T) {
+		differences, err := CompareArchives("reference/app.war", "target/app.war", files)
+
+		require.NoError(t, err)
+		assert.Equal(t, []ArchiveDifference{
+			{Entry: "WEB-INF/classes/Added.class", Reason: "not contained in the reference"},
+			{Entry: "WEB-INF/classes/App.class", Reason: "content differs"},
+			{Entry: "WEB-INF/classes/Removed.class", Reason: "missing compared to the reference"},
+			{Entry: "WEB-INF/lib/lib.jar!/lib/Lib.class", Reason: "content differs"},
+		}, differences)
+	})
+
+	t.Run("ignores timestamps and build environment", func(t *testing.T) {
+		files.AddFile("reference/lib.jar", createZip(t, firstBuild, zipEntry{"META-INF/MANIFEST.MF", "Manifest-Version: 1.0\nBuilt-By: jenkins\n"}, library(firstBuild, "lib")))
+		files.AddFile("target/lib.jar", createZip(t, secondBuild, zipEntry{"META-INF/MANIFEST.MF", "Manifest-Version: 1.0\nBuilt-By: runner\n"}, library(secondBuild, "lib")))
+
+		differences, err := CompareArchives("reference/lib.jar", "target/lib.jar", files)
+
+		require.NoError(t, err)
+		assert.Empty(t, differences)
+	})
+
+	t.Run("error cases", func(t *testing.T) {
+		_, err := CompareArchives("reference/missing.jar", "target/app.war", files)
+		assert.ErrorContains(t, err, "failed to read 'reference/missing.jar'")
+
+		files.AddFile("target/broken.jar", []byte("no zip"))
+		_, err = CompareArchives("reference/app.war", "target/broken.jar", files)
+		assert.ErrorContains(t, err, "failed to compare 'target/broken.jar' with 'reference/app.war'")
+	})
+}
+
+func TestModulesWithoutOutputTimestamp(t *testing.T) {
+	t.Parallel()
+	files := &mock.FilesMock{}
+	files.AddFile("pom.xml", []byte(`<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <packaging>pom</packaging>
+  <properties>
+    <project.build.outputTimestamp>2026-10-19T08:00:00Z</project.build.outputTimestamp>
+  </properties>
+  <modules>
+    <module>app</module>
+    <module>tools</module>
+  </modules>
+</project>`))
+	files.AddFile("app/pom.xml", []byte(`<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+  </parent>
+  <artifactId>app</artifactId>
+</project>`))
+	files.AddFile("tools/pom.xml", []byte(`<project>
+  <parent>
+    <groupId>org.springframework.boot</groupId>
+    <artifactId>spring-boot-starter-parent</artifactId>
+  </parent>
+  <groupId>com.example</groupId>
+  <artifactId>tools</artifactId>
+</project>`))
+
+	missing, err := ModulesWithoutOutputTimestamp(".", files)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tools/pom.xml"}, missing)
+}
+
+func TestReproducibilityReport(t *testing.T) {
+	t.Parallel()
+	report := ReproducibilityReport{Artifacts: []ArtifactComparison{
+		{Artifact: "target/app.jar"},
+		{Artifact: "target/app-sources.jar", Differences: []ArchiveDifference{{Entry: "App.java", Reason: "content differs"}}},
+	}}
+	assert.False(t, report.Reproducible())
+	assert.Len(t, report.DifferingArtifacts(), 1)
+
+	report.Artifacts = report.Artifacts[:1]
+	assert.True(t, report.Reproducible())
+	report.ModulesWithoutOutputTimestamp = []string{"pom.xml"}
+	assert.False(t, report.Reproducible())
+}
@@ -17,6 +17,7 @@ const (
 
 	// Package versions
 	cycloneDxNpmPackageVersion = "@cyclonedx/cyclonedx-npm@2.1.0"
+	cycloneDxYarnPluginVersion = "@cyclonedx/yarn-plugin-cyclonedx@1.0.0"
 	cdxgenPackageVersion       = "@cyclonedx/cdxgen@12.1.3"
 	cycloneDxCliVersion        = "v0.30.0"
 
@@ -35,27 +36,55 @@ var cycloneDxCliUrl = map[struct{ os, arch string }]string{
 }
 
 // CreateBOM generates a CycloneDX Bill of Materials (BOM) file for the given package.json files.
-// It supports both pnpm and other package managers (npm/yarn) with different BOM generation strategies.
+// It supports pnpm and Bun, Yarn Berry and other package managers (npm/yarn classic) with different BOM generation strategies.
 func (exec *Execute) CreateBOM(packageJSONFiles []string) error {
 	log.Entry().Debug("Detecting package manager...")
 	pm, err := exec.detectPackageManager()
 	if err != nil {
-		return fmt.Errorf("failed to detect package manager (looking for package.json, package-lock.json, pnpm-lock.yaml): %w", err)
+		return fmt.Errorf("failed to detect package manager (looking for package.json, package-lock.json, yarn.lock, pnpm-lock.yaml, bun.lock): %w", err)
 	}
 	if pm != nil {
 		log.Entry().Debugf("Detected package manager: %s", pm.Name)
 	}
 
-	if pm != nil && pm.Name == "pnpm" {
+	if pm != nil && (pm.Name == "pnpm" || pm.Name == bunName) {
 		return exec.createPnpmBOM(packageJSONFiles)
 	}
 
+	if pm != nil && pm.Name == yarnBerryName {
+		return exec.createYarnBerryBOM(packageJSONFiles)
+	}
+
 	return exec.createNpmBOM(packageJSONFiles)
 }
 
-// createPnpmBOM generates a BOM for pnpm projects using cdxgen and cyclonedx-cli
+// createYarnBerryBOM generates a BOM for Yarn Berry projects using the CycloneDX yarn plugin,
+// which in contrast to cyclonedx-npm does not require the dependencies to be installed in node_modules
+func (exec *Execute) createYarnBerryBOM(packageJSONFiles []string) error {
+	execRunner := exec.Utils.GetExecRunner()
+	oldWorkingDirectory, err := exec.Utils.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	for _, packageJSONFile := range packageJSONFiles {
+		if err := exec.Utils.Chdir(filepath.Dir(packageJSONFile)); err != nil {
+			return fmt.Errorf("failed to change into directory of %s: %w", packageJSONFile, err)
+		}
+		params := []string{"dlx", "-q", cycloneDxYarnPluginVersion, "--output-format", "XML", "--spec-version", CycloneDxSchemaVersion, "--production", "--output-file", npmBomFilename}
+		log.Entry().Debugf("Generating BOM for package %s with yarn params: %v", packageJSONFile, params)
+		if err := execRunner.RunExecutable("yarn", params...); err != nil {
+			return fmt.Errorf("failed to generate CycloneDX BOM for package %s using %s: %w", packageJSONFile, cycloneDxYarnPluginVersion, err)
+		}
+		if err := exec.Utils.Chdir(oldWorkingDirectory); err != nil {
+			return fmt.Errorf("failed to change back into original directory: %w", err)
+		}
+	}
+	return nil
+}
+
+// createPnpmBOM generates a BOM for pnpm and Bun projects using cdxgen and cyclonedx-cli
 func (exec *Execute) createPnpmBOM(packageJSONFiles []string) error {
-	log.Entry().Info("Starting BOM generation for pnpm project...")
+	log.Entry().Info("Starting BOM generation with cdxgen...")
 	log.Entry().Debug("Downloading CycloneDX CLI tool...")
 
 	cliPath, err := exec.downloadCycloneDxCli()
@@ -50,6 +50,27 @@ func TestBom(t *testing.T) {
 		}
 	})
 
+	t.Run("Create BOM with the CycloneDX yarn plugin for Yarn Berry project", func(t *testing.T) {
+		utils := newNpmMockUtilsBundle()
+		utils.AddFile("package.json", []byte("{}"))
+		utils.AddFile("yarn.lock", []byte("{}"))
+		utils.AddFile(".yarnrc.yml", []byte("nodeLinker: pnp\n"))
+		utils.AddFile(filepath.Join("src", "package.json"), []byte("{}"))
+
+		exec := &Execute{
+			Utils:     &utils,
+			pnpmSetup: pnpmSetupState{rootDir: "/"},
+		}
+		err := exec.CreateBOM([]string{"package.json", filepath.Join("src", "package.json")})
+
+		yarnParams := []string{"dlx", "-q", "@cyclonedx/yarn-plugin-cyclonedx@1.0.0", "--output-format", "XML", "--spec-version", CycloneDxSchemaVersion, "--production", "--output-file", "bom-npm.xml"}
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{{Exec: "yarn", Params: yarnParams}, {Exec: "yarn", Params: yarnParams}}, utils.execRunner.Calls)
+			cwd, _ := utils.Getwd()
+			assert.Equal(t, "/", cwd)
+		}
+	})
+
 	t.Run("Create BOM fails if cyclonedx-npm install fails", func(t *testing.T) {
 		utils := newNpmMockUtilsBundle()
 		utils.AddFile("package.json", []byte("{\"scripts\": { \"ci-lint\": \"exit 0\" } }"))
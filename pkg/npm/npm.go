@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/SAP/jenkins-library/pkg/command"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -22,6 +25,8 @@ type Execute struct {
 	Utils     Utils
 	Options   ExecutorOptions
 	pnpmSetup pnpmSetupState
+	// bunCommand is the cached bun executable, either the global or a locally installed one
+	bunCommand string
 }
 
 // pnpmSetupState holds the cached pnpm installation state
@@ -49,6 +54,9 @@ type ExecutorOptions struct {
 	DefaultNpmRegistry string
 	ExecRunner         ExecRunner
 	PnpmVersion        string
+	BunVersion         string
+	Workspace          WorkspaceOptions
+	Publish            PublishOptions
 }
 
 // NewExecutor instantiates Execute struct and sets executeOptions
@@ -106,6 +114,21 @@ func (u *utilsBundle) GetDownloadUtils() piperhttp.Downloader {
 	return u.downloadClient
 }
 
+// execRunnerFactory is implemented by utils creating independent exec runners, which allows running scripts concurrently
+type execRunnerFactory interface {
+	NewExecRunner() command.ExecRunner
+}
+
+// NewExecRunner returns a new execRunner for running a command concurrently to the execRunner of GetExecRunner
+func (u *utilsBundle) NewExecRunner() command.ExecRunner {
+	execRunner := &command.Command{
+		StepName: "npmExecuteScripts",
+	}
+	execRunner.Stdout(log.Writer())
+	execRunner.Stderr(log.Writer())
+	return execRunner
+}
+
 // GetExecRunner returns an execRunner if it's not yet initialized
 func (u *utilsBundle) GetExecRunner() ExecRunner {
 	if u.execRunner == nil {
@@ -145,6 +168,39 @@ func (exec *Execute) SetNpmRegistries() error {
 		}
 	}
 
+	// Bun reads the npm configuration, but Yarn Berry has its own configuration in .yarnrc.yml files
+	pm, _, err := exec.findWorkspacePackageManager()
+	if err != nil {
+		return err
+	}
+	if pm != nil && pm.Name == yarnBerryName {
+		return exec.setYarnBerryRegistry()
+	}
+	return nil
+}
+
+// setYarnBerryRegistry configures the default npm registry in the .yarnrc.yml file in the user's home directory
+func (exec *Execute) setYarnBerryRegistry() error {
+	execRunner := exec.Utils.GetExecRunner()
+	const yarnRegistry = "npmRegistryServer"
+
+	var buffer bytes.Buffer
+	execRunner.Stdout(&buffer)
+	err := execRunner.RunExecutable("yarn", "config", "get", yarnRegistry)
+	execRunner.Stdout(log.Writer())
+	if err != nil {
+		return err
+	}
+	preConfiguredRegistry := strings.TrimSpace(buffer.String())
+
+	if registryIsNonEmpty(preConfiguredRegistry) {
+		log.Entry().Info("Discovered pre-configured yarn registry " + yarnRegistry + " with value " + preConfiguredRegistry)
+	}
+
+	if exec.Options.DefaultNpmRegistry != "" && registryRequiresConfiguration(preConfiguredRegistry, "https://registry.yarnpkg.com") {
+		log.Entry().Info("yarn registry " + yarnRegistry + " was not configured, setting it to " + exec.Options.DefaultNpmRegistry)
+		return execRunner.RunExecutable("yarn", "config", "set", "--home", yarnRegistry, exec.Options.DefaultNpmRegistry)
+	}
 	return nil
 }
 
@@ -173,13 +229,20 @@ func (exec *Execute) RunScriptsInAllPackages(runScripts []string, runOptions []s
 
 	execRunner := exec.Utils.GetExecRunner()
 
+	var env []string
 	if virtualFrameBuffer {
 		cmd, err := execRunner.RunExecutableInBackground("Xvfb", "-ac", ":99", "-screen", "0", "1280x1024x16")
 		if err != nil {
 			return fmt.Errorf("failed to start virtual frame buffer%w", err)
 		}
 		defer cmd.Kill()
-		execRunner.SetEnv([]string{"DISPLAY=:99"})
+		env = []string{"DISPLAY=:99"}
+		execRunner.SetEnv(env)
+	}
+
+	packages, err := exec.readWorkspacePackages(packageJSONFiles)
+	if err != nil {
+		return err
 	}
 
 	for _, script := range runScripts {
@@ -193,9 +256,21 @@ func (exec *Execute) RunScriptsInAllPackages(runScripts []string, runOptions []s
 
 		}
 
-		for _, packageJSON := range packagesWithScript {
-			err = exec.executeScript(packageJSON, script, runOptions, scriptOptions)
-			if err != nil {
+		if exec.Options.Workspace.ChangedFiles != nil {
+			affected := affectedPackages(packages, exec.Options.Workspace.ChangedFiles)
+			packagesWithScript = slices.DeleteFunc(packagesWithScript, func(packageJSON string) bool {
+				return !slices.Contains(affected, packageJSON)
+			})
+			log.Entry().Infof("Running script %s in %v package(s) affected by %v changed file(s)", script, len(packagesWithScript), len(exec.Options.Workspace.ChangedFiles))
+		}
+
+		// packages are run after the workspace packages they depend on
+		levels, err := topologicalLevels(packages, packagesWithScript)
+		if err != nil {
+			return err
+		}
+		for _, level := range levels {
+			if err := exec.executeScripts(level, script, runOptions, scriptOptions, env); err != nil {
 				return err
 			}
 		}
@@ -203,40 +278,119 @@ func (exec *Execute) RunScriptsInAllPackages(runScripts []string, runOptions []s
 	return nil
 }
 
-func (exec *Execute) executeScript(packageJSON string, script string, runOptions []string, scriptOptions []string) error {
-	execRunner := exec.Utils.GetExecRunner()
-	oldWorkingDirectory, err := exec.Utils.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current working directory before executing npm scripts: %w", err)
+// executeScripts runs the script in packages not depending on each other, concurrently if configured
+func (exec *Execute) executeScripts(packageJSONFiles []string, script string, runOptions []string, scriptOptions []string, env []string) error {
+	factory, canRunConcurrently := exec.Utils.(execRunnerFactory)
+	if exec.Options.Workspace.Parallelism < 2 || len(packageJSONFiles) < 2 || !canRunConcurrently {
+		for _, packageJSON := range packageJSONFiles {
+			if err := exec.executeScript(packageJSON, script, runOptions, scriptOptions); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
-	dir := filepath.Dir(packageJSON)
-	err = exec.Utils.Chdir(dir)
-	if err != nil {
-		return fmt.Errorf("failed to change into directory for executing script: %w", err)
+	// registries and package managers are set up one after the other since they depend on the working directory
+	commands := make([]scriptCommand, len(packageJSONFiles))
+	for i, packageJSON := range packageJSONFiles {
+		command, err := exec.prepareScript(packageJSON, script, runOptions, scriptOptions)
+		if err != nil {
+			return err
+		}
+		commands[i] = command
 	}
 
-	// set in each directory to respect existing config in rc fileUtils
-	err = exec.SetNpmRegistries()
+	log.Entry().Infof("Running script %s in %v packages with up to %v packages in parallel", script, len(commands), exec.Options.Workspace.Parallelism)
+	g := errgroup.Group{}
+	g.SetLimit(exec.Options.Workspace.Parallelism)
+	for _, command := range commands {
+		g.Go(func() error {
+			execRunner := factory.NewExecRunner()
+			execRunner.SetDir(command.dir)
+			execRunner.SetEnv(env)
+			log.Entry().WithField("WorkingDirectory", command.dir).Info("run-script " + script)
+			if err := execRunner.RunExecutable(command.executable, command.params...); err != nil {
+				return fmt.Errorf("failed to run npm script %s in %s: %w", script, command.dir, err)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// scriptCommand is the command running a script in the directory of a package
+type scriptCommand struct {
+	dir        string
+	executable string
+	params     []string
+}
+
+func (exec *Execute) executeScript(packageJSON string, script string, runOptions []string, scriptOptions []string) error {
+	command, err := exec.prepareScript(packageJSON, script, runOptions, scriptOptions)
 	if err != nil {
 		return err
 	}
+	return exec.runInDirectory(command.dir, func() error {
+		log.Entry().WithField("WorkingDirectory", command.dir).Info("run-script " + script)
+		if err := exec.Utils.GetExecRunner().RunExecutable(command.executable, command.params...); err != nil {
+			return fmt.Errorf("failed to run npm script %s: %w", script, err)
+		}
+		return nil
+	})
+}
 
-	log.Entry().WithField("WorkingDirectory", dir).Info("run-script " + script)
+// prepareScript configures the registries in the directory of the package and determines the command running the script
+func (exec *Execute) prepareScript(packageJSON string, script string, runOptions []string, scriptOptions []string) (scriptCommand, error) {
+	command := scriptCommand{dir: filepath.Dir(packageJSON), executable: "npm"}
+	err := exec.runInDirectory(command.dir, func() error {
+		// set in each directory to respect existing config in rc fileUtils
+		if err := exec.SetNpmRegistries(); err != nil {
+			return err
+		}
+
+		// Yarn Berry and Bun run the scripts themselves since the dependencies may not be installed in node_modules
+		pm, err := exec.detectWorkspacePackageManager()
+		if err != nil {
+			return err
+		}
+		if pm != nil {
+			command.executable = pm.RunCommand
+		}
+		return nil
+	})
+	if err != nil {
+		return command, err
+	}
 
-	npmRunArgs := []string{"run", script}
+	command.params = []string{"run", script}
 	if len(runOptions) > 0 {
-		npmRunArgs = append(npmRunArgs, runOptions...)
+		command.params = append(command.params, runOptions...)
 	}
 
 	if len(scriptOptions) > 0 {
-		npmRunArgs = append(npmRunArgs, "--")
-		npmRunArgs = append(npmRunArgs, scriptOptions...)
+		// yarn and bun pass all arguments following the script name to the script
+		if command.executable == "npm" {
+			command.params = append(command.params, "--")
+		}
+		command.params = append(command.params, scriptOptions...)
+	}
+	return command, nil
+}
+
+// runInDirectory changes into the directory for running the function and back into the current directory afterwards
+func (exec *Execute) runInDirectory(dir string, run func() error) error {
+	oldWorkingDirectory, err := exec.Utils.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory before executing npm scripts: %w", err)
 	}
 
-	err = execRunner.RunExecutable("npm", npmRunArgs...)
+	err = exec.Utils.Chdir(dir)
 	if err != nil {
-		return fmt.Errorf("failed to run npm script %s: %w", script, err)
+		return fmt.Errorf("failed to change into directory for executing script: %w", err)
+	}
+
+	if err := run(); err != nil {
+		return err
 	}
 
 	err = exec.Utils.Chdir(oldWorkingDirectory)
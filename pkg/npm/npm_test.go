@@ -402,4 +402,42 @@ func TestNpm(t *testing.T) {
 		}
 	})
 
+	t.Run("Install deps and run scripts of a Yarn Berry workspace", func(t *testing.T) {
+		utils := newNpmMockUtilsBundle()
+		utils.AddFile("package.json", []byte("{\"workspaces\": [\"packages/*\"]}"))
+		utils.AddFile("yarn.lock", []byte("{}"))
+		utils.AddFile(".yarnrc.yml", []byte("nodeLinker: pnp\n"))
+		utils.AddFile(filepath.Join("packages", "app", "package.json"), []byte("{\"scripts\": { \"ci-build\": \"exit 0\" } }"))
+		utils.execRunner.StdoutReturn = map[string]string{
+			"npm config get registry -ws=false -iwr": "undefined",
+			"yarn config get npmRegistryServer":      "https://registry.yarnpkg.com\n",
+		}
+
+		exec := &Execute{
+			Utils:   &utils,
+			Options: ExecutorOptions{DefaultNpmRegistry: "https://example.org/npm"},
+			pnpmSetup: pnpmSetupState{
+				rootDir: "/", // Mock root directory
+			},
+		}
+		err := exec.install("package.json")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{
+				{Exec: "npm", Params: []string{"config", "get", "registry", "-ws=false", "-iwr"}},
+				{Exec: "npm", Params: []string{"config", "set", "registry", "https://example.org/npm", "-ws=false", "-iwr"}},
+				{Exec: "yarn", Params: []string{"config", "get", "npmRegistryServer"}},
+				{Exec: "yarn", Params: []string{"config", "set", "--home", "npmRegistryServer", "https://example.org/npm"}},
+				{Exec: "yarn", Params: []string{"install", "--immutable"}},
+			}, utils.execRunner.Calls)
+		}
+
+		utils.execRunner.Calls = nil
+		err = exec.executeScript(filepath.Join("packages", "app", "package.json"), "ci-build", []string{"--silent"}, []string{"--tag", "tag1"})
+
+		if assert.NoError(t, err) && assert.NotEmpty(t, utils.execRunner.Calls) {
+			assert.Equal(t, mock.ExecCall{Exec: "yarn", Params: []string{"run", "ci-build", "--silent", "--tag", "tag1"}}, utils.execRunner.Calls[len(utils.execRunner.Calls)-1])
+		}
+	})
+
 }
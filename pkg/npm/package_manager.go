@@ -1,6 +1,7 @@
 package npm
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,7 +9,13 @@ import (
 	"github.com/SAP/jenkins-library/pkg/log"
 )
 
-const pnpmPath = tmpInstallFolder + "/node_modules/.bin/pnpm"
+const (
+	pnpmPath = tmpInstallFolder + "/node_modules/.bin/pnpm"
+	bunPath  = tmpInstallFolder + "/node_modules/.bin/bun"
+
+	yarnBerryName = "yarn-berry"
+	bunName       = "bun"
+)
 
 // PackageManager represents a Node.js package manager configuration
 type PackageManager struct {
@@ -16,6 +23,22 @@ type PackageManager struct {
 	LockFile       string
 	InstallCommand string
 	InstallArgs    []string
+	// RunCommand runs the scripts of the packages, npm is used if empty
+	RunCommand string
+	// PackArgs are passed to the InstallCommand to pack a package for publishing, npm pack is used if empty
+	PackArgs []string
+}
+
+// yarnBerry is Yarn in version 2 or later which shares the lock file name with yarn classic.
+// Since dependencies may be installed with Plug'n'Play instead of node_modules, yarn runs the scripts itself
+// and packs the packages to replace workspace: dependency ranges.
+var yarnBerry = PackageManager{
+	Name:           yarnBerryName,
+	LockFile:       "yarn.lock",
+	InstallCommand: "yarn",
+	InstallArgs:    []string{"install", "--immutable"},
+	RunCommand:     "yarn",
+	PackArgs:       []string{"pack"},
 }
 
 // List of supported package managers with their configurations
@@ -38,6 +61,23 @@ var supportedPackageManagers = []PackageManager{
 		InstallCommand: pnpmPath,
 		InstallArgs:    []string{"install", "--frozen-lockfile"},
 	},
+	{
+		Name:           bunName,
+		LockFile:       "bun.lock",
+		InstallCommand: bunPath,
+		InstallArgs:    []string{"install", "--frozen-lockfile"},
+		RunCommand:     bunPath,
+		PackArgs:       []string{"pm", "pack"},
+	},
+	{
+		// binary lock file of Bun before version 1.2
+		Name:           bunName,
+		LockFile:       "bun.lockb",
+		InstallCommand: bunPath,
+		InstallArgs:    []string{"install", "--frozen-lockfile"},
+		RunCommand:     bunPath,
+		PackArgs:       []string{"pm", "pack"},
+	},
 }
 
 // InstallPnpm handles the special installation process for pnpm locally
@@ -71,17 +111,16 @@ func (exec *Execute) detectPackageManager() (*PackageManager, error) {
 			return nil, fmt.Errorf("failed to check for %s: %w", pm.LockFile, err)
 		}
 		if exists {
-			if pm.Name == "pnpm" {
-				if err := exec.setupPnpm(&pm); err != nil {
-					return nil, fmt.Errorf("failed to set up pnpm: %w", err)
-				}
-				// Use cached pnpm command
-				pm.InstallCommand = exec.pnpmSetup.command
-			}
-			return &pm, nil
+			return exec.setupPackageManager(pm, ".")
 		}
 	}
 
+	// packages of Yarn Berry and Bun workspaces are installed and run with the lock file of the workspace root
+	pm, err := exec.detectWorkspacePackageManager()
+	if err != nil || pm != nil {
+		return pm, err
+	}
+
 	// No lock file found - log warning and default to npm with regular install
 	log.Entry().Warn("No package lock file found. " +
 		"It is recommended to create a `package-lock.json` file by running `npm Install` locally." +
@@ -96,6 +135,106 @@ func (exec *Execute) detectPackageManager() (*PackageManager, error) {
 	}, nil
 }
 
+// detectWorkspacePackageManager returns Yarn Berry or Bun if the current directory or the root directory contains their lock file, nil otherwise
+func (exec *Execute) detectWorkspacePackageManager() (*PackageManager, error) {
+	pm, dir, err := exec.findWorkspacePackageManager()
+	if err != nil || pm == nil {
+		return nil, err
+	}
+	return exec.setupPackageManager(*pm, dir)
+}
+
+// findWorkspacePackageManager looks up the lock file of Yarn Berry or Bun in the current directory and the root directory without setting up the package manager
+func (exec *Execute) findWorkspacePackageManager() (*PackageManager, string, error) {
+	dirs := []string{"."}
+	if cwd, err := exec.Utils.Getwd(); err == nil && exec.pnpmSetup.rootDir != "" && filepath.Clean(cwd) != filepath.Clean(exec.pnpmSetup.rootDir) {
+		dirs = append(dirs, exec.pnpmSetup.rootDir)
+	}
+	for _, dir := range dirs {
+		for _, pm := range supportedPackageManagers {
+			if pm.Name != "yarn" && pm.Name != bunName {
+				continue
+			}
+			exists, err := exec.Utils.FileExists(filepath.Join(dir, pm.LockFile))
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to check for %s: %w", pm.LockFile, err)
+			}
+			if !exists {
+				continue
+			}
+			if pm.Name == "yarn" {
+				berry, err := exec.isYarnBerry(dir)
+				if err != nil || !berry {
+					// yarn classic workspaces keep using npm for packages without lock file
+					return nil, "", err
+				}
+				pm = yarnBerry
+			}
+			return &pm, dir, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// workspacePackageManagerOf returns Yarn Berry or Bun if they manage the package in the directory, nil otherwise
+func (exec *Execute) workspacePackageManagerOf(dir string) (*PackageManager, error) {
+	oldWorkingDirectory, err := exec.Utils.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	if err := exec.Utils.Chdir(dir); err != nil {
+		return nil, fmt.Errorf("failed to change into directory %s: %w", dir, err)
+	}
+	pm, err := exec.detectWorkspacePackageManager()
+	if chdirErr := exec.Utils.Chdir(oldWorkingDirectory); chdirErr != nil {
+		return nil, fmt.Errorf("failed to change back into original directory: %w", chdirErr)
+	}
+	return pm, err
+}
+
+// setupPackageManager distinguishes Yarn Berry from yarn classic and makes sure pnpm and Bun are available
+func (exec *Execute) setupPackageManager(pm PackageManager, dir string) (*PackageManager, error) {
+	switch pm.Name {
+	case "yarn":
+		berry, err := exec.isYarnBerry(dir)
+		if err != nil {
+			return nil, err
+		}
+		if berry {
+			pm = yarnBerry
+		}
+	case "pnpm":
+		if err := exec.setupPnpm(&pm); err != nil {
+			return nil, fmt.Errorf("failed to set up pnpm: %w", err)
+		}
+		// Use cached pnpm command
+		pm.InstallCommand = exec.pnpmSetup.command
+	case bunName:
+		if err := exec.setupBun(); err != nil {
+			return nil, fmt.Errorf("failed to set up bun: %w", err)
+		}
+		pm.InstallCommand = exec.bunCommand
+		pm.RunCommand = exec.bunCommand
+	}
+	return &pm, nil
+}
+
+// isYarnBerry checks for the configuration file of Yarn Berry or the metadata section of its lock file in the directory
+func (exec *Execute) isYarnBerry(dir string) (bool, error) {
+	exists, err := exec.Utils.FileExists(filepath.Join(dir, ".yarnrc.yml"))
+	if err != nil {
+		return false, fmt.Errorf("failed to check for .yarnrc.yml: %w", err)
+	}
+	if exists {
+		return true, nil
+	}
+	lockFile, err := exec.Utils.FileRead(filepath.Join(dir, "yarn.lock"))
+	if err != nil {
+		return false, fmt.Errorf("failed to read yarn.lock: %w", err)
+	}
+	return bytes.Contains(lockFile, []byte("\n__metadata:")), nil
+}
+
 // setupPnpm handles the pnpm installation and setup process
 func (exec *Execute) setupPnpm(pm *PackageManager) error {
 	// Check pnpm installation only once per Execute instance
@@ -144,3 +283,33 @@ func (exec *Execute) setupPnpm(pm *PackageManager) error {
 	}
 	return nil
 }
+
+// setupBun uses a globally installed bun or installs bun locally, the bun command is cached per Execute instance
+func (exec *Execute) setupBun() error {
+	if exec.bunCommand != "" {
+		return nil
+	}
+	execRunner := exec.Utils.GetExecRunner()
+	if exec.Options.BunVersion == "" {
+		if err := execRunner.RunExecutable("bun", "--version"); err == nil {
+			exec.bunCommand = "bun"
+			log.Entry().Info("Using globally installed bun")
+			return nil
+		}
+	}
+
+	prefixPath := filepath.Join(exec.pnpmSetup.rootDir, tmpInstallFolder)
+	localBun := filepath.Join(prefixPath, "node_modules", ".bin", "bun")
+	if exec.Options.BunVersion != "" || execRunner.RunExecutable(localBun, "--version") != nil {
+		bunPackage := "bun"
+		if exec.Options.BunVersion != "" {
+			bunPackage = fmt.Sprintf("bun@%s", exec.Options.BunVersion)
+		}
+		if err := execRunner.RunExecutable("npm", "install", bunPackage, "--prefix", prefixPath); err != nil {
+			return fmt.Errorf("failed to install bun locally: %w", err)
+		}
+	}
+	exec.bunCommand = localBun
+	log.Entry().Info("Using locally installed bun")
+	return nil
+}
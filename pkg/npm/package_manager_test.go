@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -306,3 +307,135 @@ func TestPackageManager(t *testing.T) {
 		}
 	})
 }
+
+func TestWorkspacePackageManagers(t *testing.T) {
+	t.Run("detect Yarn Berry and Bun", func(t *testing.T) {
+		tests := []struct {
+			name            string
+			files           map[string]string
+			expectedPM      string
+			expectedInstall []string
+			expectedRunCmd  string
+		}{
+			{
+				name:            "Yarn Berry with .yarnrc.yml",
+				files:           map[string]string{"yarn.lock": "{}", ".yarnrc.yml": "nodeLinker: pnp\n"},
+				expectedPM:      "yarn-berry",
+				expectedInstall: []string{"install", "--immutable"},
+				expectedRunCmd:  "yarn",
+			},
+			{
+				name:            "Yarn Berry lock file",
+				files:           map[string]string{"yarn.lock": "# This file is generated by running \"yarn install\" inside your project.\n\n__metadata:\n  version: 8\n"},
+				expectedPM:      "yarn-berry",
+				expectedInstall: []string{"install", "--immutable"},
+				expectedRunCmd:  "yarn",
+			},
+			{
+				name:            "yarn classic lock file",
+				files:           map[string]string{"yarn.lock": "# yarn lockfile v1\n"},
+				expectedPM:      "yarn",
+				expectedInstall: []string{"install", "--frozen-lockfile"},
+			},
+			{
+				name:            "Bun text lock file",
+				files:           map[string]string{"bun.lock": "{}"},
+				expectedPM:      "bun",
+				expectedInstall: []string{"install", "--frozen-lockfile"},
+				expectedRunCmd:  "bun",
+			},
+			{
+				name:            "Bun binary lock file",
+				files:           map[string]string{"bun.lockb": "binary"},
+				expectedPM:      "bun",
+				expectedInstall: []string{"install", "--frozen-lockfile"},
+				expectedRunCmd:  "bun",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				utils := newNpmMockUtilsBundle()
+				for file, content := range tt.files {
+					utils.AddFile(file, []byte(content))
+				}
+				exec := &Execute{Utils: &utils, pnpmSetup: pnpmSetupState{rootDir: "/"}}
+
+				pm, err := exec.detectPackageManager()
+
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.expectedPM, pm.Name)
+					assert.Equal(t, tt.expectedInstall, pm.InstallArgs)
+					assert.Equal(t, tt.expectedRunCmd, pm.RunCommand)
+				}
+			})
+		}
+	})
+
+	t.Run("workspace package uses lock file of the root", func(t *testing.T) {
+		utils := newNpmMockUtilsBundle()
+		utils.AddFile("/bun.lock", []byte("{}"))
+		utils.AddFile("/packages/app/package.json", []byte("{}"))
+		assert.NoError(t, utils.Chdir("/packages/app"))
+		exec := &Execute{Utils: &utils, pnpmSetup: pnpmSetupState{rootDir: "/"}}
+
+		pm, err := exec.detectPackageManager()
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, "bun", pm.Name)
+			assert.Equal(t, "bun", pm.InstallCommand)
+		}
+	})
+
+	t.Run("bun is installed locally", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			bunVersion    string
+			globalBun     bool
+			expectedCalls []mock.ExecCall
+		}{
+			{
+				name:      "no global bun",
+				globalBun: false,
+				expectedCalls: []mock.ExecCall{
+					{Exec: "bun", Params: []string{"--version"}},
+					{Exec: "/tmp/node_modules/.bin/bun", Params: []string{"--version"}},
+					{Exec: "npm", Params: []string{"install", "bun", "--prefix", "/tmp"}},
+				},
+			},
+			{
+				name:       "specific version",
+				bunVersion: "1.2.20",
+				globalBun:  true,
+				expectedCalls: []mock.ExecCall{
+					{Exec: "npm", Params: []string{"install", "bun@1.2.20", "--prefix", "/tmp"}},
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				utils := newNpmMockUtilsBundle()
+				utils.AddFile("bun.lock", []byte("{}"))
+				if !tt.globalBun {
+					utils.execRunner.ShouldFailOnCommand = map[string]error{
+						"bun --version":                        fmt.Errorf("command not found"),
+						"/tmp/node_modules/.bin/bun --version": fmt.Errorf("command not found"),
+					}
+				}
+				exec := &Execute{Utils: &utils, Options: ExecutorOptions{BunVersion: tt.bunVersion}, pnpmSetup: pnpmSetupState{rootDir: "/"}}
+
+				pm, err := exec.detectPackageManager()
+
+				if assert.NoError(t, err) {
+					assert.Equal(t, "/tmp/node_modules/.bin/bun", pm.InstallCommand)
+					assert.Equal(t, tt.expectedCalls, utils.execRunner.Calls)
+				}
+				// the bun setup is done only once
+				_, err = exec.detectPackageManager()
+				assert.NoError(t, err)
+				assert.Len(t, utils.execRunner.Calls, len(tt.expectedCalls))
+			})
+		}
+	})
+}
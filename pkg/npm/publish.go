@@ -1,17 +1,43 @@
 package npm
 
 import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/versioning"
 )
 
+// PublishOptions configure the verification of published packages
+type PublishOptions struct {
+	// Provenance publishes the packages with a provenance statement signed by npm, which requires a supported CI system
+	Provenance bool
+	// VerifyIntegrity compares the integrity of the published packages in the registry with the packed tarballs
+	VerifyIntegrity bool
+	// VerifyRetryInterval is the initial delay between the lookups of a published version which is not yet available in the registry.
+	// The delay doubles with each attempt and defaults to two seconds.
+	VerifyRetryInterval time.Duration
+	// SkipPublishedVersions skips packages whose version is already available in the registry instead of failing
+	SkipPublishedVersions bool
+}
+
+// verifyIntegrityAttempts is the number of lookups of a published version until it is considered missing in the registry
+const verifyIntegrityAttempts = 5
+
+// npmPublishedVersion is the registry metadata of a published package version
+type npmPublishedVersion struct {
+	Version   string `json:"version"`
+	Integrity string `json:"dist.integrity"`
+}
+
 type npmMinimalPackageDescriptor struct {
 	Name    string `json:"name"`
 	Version string `json:"version"`
@@ -56,10 +82,11 @@ func (exec *Execute) publish(packageJSON, registry, username, password, publishT
 
 	oldWorkingDirectory, err := exec.Utils.Getwd()
 
-	scope, err := exec.readPackageScope(packageJSON)
+	pd, err := exec.readPackage(packageJSON)
 	if err != nil {
 		return fmt.Errorf("error reading package scope from %s: %w", packageJSON, err)
 	}
+	scope := pd.Scope()
 
 	npmignore := NewNPMIgnore(filepath.Dir(packageJSON))
 	if exists, err := exec.Utils.FileExists(npmignore.filepath); exists {
@@ -137,19 +164,53 @@ func (exec *Execute) publish(packageJSON, registry, username, password, publishT
 		return fmt.Errorf("failed to read package version from %s: %w", packageJSON, err)
 	}
 
+	// the registry is queried with the configuration used for publishing
+	viewArgs := []string{}
+	if len(registry) > 0 {
+		viewArgs = append(viewArgs, "--userconfig", npmrc.filepath, "--registry", registry)
+	}
+	if exec.Options.Publish.SkipPublishedVersions {
+		published, err := exec.publishedVersion(pd.Name, version, viewArgs)
+		if err != nil {
+			log.Entry().Warnf("failed to check whether %s@%s is already published, publishing it: %v", pd.Name, version, err)
+		} else if published != nil {
+			log.Entry().Infof("%s@%s is already published, skipping %s", pd.Name, version, packageJSON)
+			return nil
+		}
+	}
+
 	tag := publishTag
 	if tag == "" && isPrerelease(version) {
 		tag = "prerelease"
 		log.Entry().Infof("No publish tag provided, using '%s' based on version %s", tag, version)
 	}
 
+	pm, err := exec.workspacePackageManagerOf(filepath.Dir(packageJSON))
+	if err != nil {
+		return err
+	}
+	if exec.Options.Publish.VerifyIntegrity && !packBeforePublish {
+		log.Entry().Infof("packing %s to verify the integrity of the published package", packageJSON)
+		packBeforePublish = true
+	}
+	// Yarn Berry and Bun replace workspace: dependency ranges only when packing the package
+	packCommand, packArgs := "npm", []string{"pack"}
+	if pm != nil {
+		if !packBeforePublish {
+			log.Entry().Infof("packing %s with %s before publishing", packageJSON, pm.Name)
+			packBeforePublish = true
+		}
+		packCommand, packArgs = pm.InstallCommand, pm.PackArgs
+	}
+
+	var tarballFilePath string
 	if packBeforePublish {
 		// change directory in package json file , since npm pack will run only for that packages
 		if err = exec.Utils.Chdir(filepath.Dir(packageJSON)); err != nil {
 			return fmt.Errorf("failed to change into directory for executing script: %w", err)
 		}
 
-		if err = execRunner.RunExecutable("npm", "pack"); err != nil {
+		if err = execRunner.RunExecutable(packCommand, packArgs...); err != nil {
 			return err
 		}
 
@@ -167,7 +228,7 @@ func (exec *Execute) publish(packageJSON, registry, username, password, publishT
 			return fmt.Errorf("found more tarballs than expected: %v", tarballs)
 		}
 
-		tarballFilePath, err := exec.Utils.Abs(tarballs[0])
+		tarballFilePath, err = exec.Utils.Abs(tarballs[0])
 		if err != nil {
 			return err
 		}
@@ -195,6 +256,9 @@ func (exec *Execute) publish(packageJSON, registry, username, password, publishT
 		if tag != "" {
 			publishArgs = append(publishArgs, "--tag", tag)
 		}
+		if exec.Options.Publish.Provenance {
+			publishArgs = append(publishArgs, "--provenance")
+		}
 
 		if err = execRunner.RunExecutable("npm", publishArgs...); err != nil {
 			return fmt.Errorf("failed publishing artifact: %w", err)
@@ -216,12 +280,21 @@ func (exec *Execute) publish(packageJSON, registry, username, password, publishT
 		if tag != "" {
 			publishArgs = append(publishArgs, "--tag", tag)
 		}
+		if exec.Options.Publish.Provenance {
+			publishArgs = append(publishArgs, "--provenance")
+		}
 
 		if err = execRunner.RunExecutable("npm", publishArgs...); err != nil {
 			return fmt.Errorf("failed publishing artifact: %w", err)
 		}
 	}
 
+	if exec.Options.Publish.VerifyIntegrity {
+		if err := exec.verifyPublishedIntegrity(pd.Name, version, tarballFilePath, viewArgs); err != nil {
+			return err
+		}
+	}
+
 	options := versioning.Options{}
 	var utils versioning.Utils
 
@@ -246,6 +319,64 @@ func (exec *Execute) publish(packageJSON, registry, username, password, publishT
 	return nil
 }
 
+// publishedVersion returns the registry metadata of the package version, nil is returned if the version is not published
+func (exec *Execute) publishedVersion(name, version string, viewArgs []string) (*npmPublishedVersion, error) {
+	execRunner := exec.Utils.GetExecRunner()
+
+	var buffer bytes.Buffer
+	execRunner.Stdout(&buffer)
+	err := execRunner.RunExecutable("npm", append([]string{"view", name + "@" + version, "version", "dist.integrity", "--json"}, viewArgs...)...)
+	execRunner.Stdout(log.Writer())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry metadata of %s@%s: %w", name, version, err)
+	}
+
+	// npm view has no output for versions which are not published
+	output := bytes.TrimSpace(buffer.Bytes())
+	if len(output) == 0 {
+		return nil, nil
+	}
+	var published npmPublishedVersion
+	if err := json.Unmarshal(output, &published); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal registry metadata of %s@%s: %w", name, version, err)
+	}
+	return &published, nil
+}
+
+// verifyPublishedIntegrity compares the integrity of the published package version with the SHA-512 digest of the packed tarball
+func (exec *Execute) verifyPublishedIntegrity(name, version, tarball string, viewArgs []string) error {
+	content, err := exec.Utils.FileRead(tarball)
+	if err != nil {
+		return fmt.Errorf("failed to read tarball %s: %w", tarball, err)
+	}
+	digest := sha512.Sum512(content)
+	integrity := "sha512-" + base64.StdEncoding.EncodeToString(digest[:])
+
+	interval := exec.Options.Publish.VerifyRetryInterval
+	if interval == 0 {
+		interval = 2 * time.Second
+	}
+	published, err := exec.publishedVersion(name, version, viewArgs)
+	// registries need some time until a published version is available
+	for attempt := 1; (err != nil || published == nil) && attempt < verifyIntegrityAttempts; attempt++ {
+		log.Entry().Debugf("published package %s@%s not yet available in the registry, retrying in %v", name, version, interval)
+		time.Sleep(interval)
+		interval *= 2
+		published, err = exec.publishedVersion(name, version, viewArgs)
+	}
+	if err != nil {
+		return err
+	}
+	if published == nil {
+		return fmt.Errorf("published package %s@%s not found in the registry", name, version)
+	}
+	if published.Integrity != integrity {
+		return fmt.Errorf("integrity %s of the published package %s@%s does not match the integrity %s of the packed tarball", published.Integrity, name, version, integrity)
+	}
+	log.Entry().Infof("verified integrity %s of the published package %s@%s", integrity, name, version)
+	return nil
+}
+
 func (exec *Execute) readPackage(packageJSON string) (*npmMinimalPackageDescriptor, error) {
 	b, err := exec.Utils.FileRead(packageJSON)
 	if err != nil {
@@ -260,14 +391,6 @@ func (exec *Execute) readPackage(packageJSON string) (*npmMinimalPackageDescript
 	return &pd, nil
 }
 
-func (exec *Execute) readPackageScope(packageJSON string) (string, error) {
-	pd, err := exec.readPackage(packageJSON)
-	if err != nil {
-		return "", err
-	}
-	return pd.Scope(), nil
-}
-
 // readPackageVersion reads the version from package.json
 func (exec *Execute) readPackageVersion(packageJSON string) (string, error) {
 	pd, err := exec.readPackage(packageJSON)
@@ -4,10 +4,15 @@
 package npm
 
 import (
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
 	"io"
 	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
@@ -15,6 +20,7 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/versioning"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type npmMockUtilsBundleRelativeGlob struct {
@@ -658,3 +664,151 @@ func TestIsPrerelease(t *testing.T) {
 		})
 	}
 }
+
+func TestNpmPublishWorkspacePackageManagers(t *testing.T) {
+	tests := []struct {
+		name        string
+		lockFiles   map[string]string
+		packCommand mock.ExecCall
+	}{
+		{
+			name:        "Yarn Berry",
+			lockFiles:   map[string]string{"yarn.lock": "{}", ".yarnrc.yml": "nodeLinker: pnp\n"},
+			packCommand: mock.ExecCall{Exec: "yarn", Params: []string{"pack"}},
+		},
+		{
+			name:        "Bun",
+			lockFiles:   map[string]string{"bun.lock": "{}"},
+			packCommand: mock.ExecCall{Exec: "bun", Params: []string{"pm", "pack"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			utils := newNpmMockUtilsBundleRelativeGlob()
+			utils.AddFile("package.json", []byte(`{"name": "piper-project", "version": "1.0.0", "dependencies": {"piper-lib": "workspace:^"}}`))
+			for path, content := range test.lockFiles {
+				utils.AddFile(path, []byte(content))
+			}
+			exec := &Execute{Utils: &utils}
+			propertiesLoadFile = utils.FileRead
+			propertiesWriteFile = utils.FileWrite
+			writeIgnoreFile = utils.FileWrite
+			utils.execRunner.Stub = func(call string, stdoutReturn map[string]string, shouldFailOnCommand map[string]error, stdout io.Writer) error {
+				utils.AddFile(filepath.Join(".", "package.tgz"), []byte("this is a tgz file"))
+				return nil
+			}
+
+			coordinates := []versioning.Coordinates{}
+			err := exec.PublishAllPackages([]string{"package.json"}, "https://my.private.npm.registry/", "ThisIsTheUser", "AndHereIsThePassword", "", false, &coordinates)
+
+			require.NoError(t, err)
+			assert.Contains(t, utils.execRunner.Calls, test.packCommand, "expected the package to be packed although packBeforePublish is false")
+			publishCmd := utils.execRunner.Calls[len(utils.execRunner.Calls)-1]
+			assert.Equal(t, "npm", publishCmd.Exec)
+			assert.Contains(t, publishCmd.Params, "--tarball")
+		})
+	}
+}
+
+func TestNpmPublishVerification(t *testing.T) {
+	const tarball = "this is a tgz file"
+	digest := sha512.Sum512([]byte(tarball))
+	integrity := "sha512-" + base64.StdEncoding.EncodeToString(digest[:])
+
+	tests := []struct {
+		name string
+		// registry metadata returned by npm view before and after publishing
+		metadata    []string
+		viewError   error
+		options     PublishOptions
+		publishArgs []string
+		skipped     bool
+		err         string
+	}{
+		{
+			name:     "version already published",
+			metadata: []string{`{"version": "1.0.0", "dist.integrity": "` + integrity + `"}`},
+			options:  PublishOptions{SkipPublishedVersions: true},
+			skipped:  true,
+		},
+		{
+			name:        "version not yet published",
+			metadata:    []string{""},
+			options:     PublishOptions{SkipPublishedVersions: true},
+			publishArgs: []string{"publish", "--userconfig", ".piperNpmrc", "--registry", "https://my.private.npm.registry/"},
+		},
+		{
+			name:        "registry metadata not available",
+			viewError:   errors.New("E404"),
+			options:     PublishOptions{SkipPublishedVersions: true},
+			publishArgs: []string{"publish", "--userconfig", ".piperNpmrc", "--registry", "https://my.private.npm.registry/"},
+		},
+		{
+			name:        "provenance and verified integrity",
+			metadata:    []string{`{"version": "1.0.0", "dist.integrity": "` + integrity + `"}`},
+			options:     PublishOptions{Provenance: true, VerifyIntegrity: true},
+			publishArgs: []string{"publish", "--tarball", "/package.tgz", "--userconfig", ".piperNpmrc", "--registry", "https://my.private.npm.registry/", "--provenance"},
+		},
+		{
+			name:     "integrity mismatch",
+			metadata: []string{`{"version": "1.0.0", "dist.integrity": "sha512-AAAA"}`},
+			options:  PublishOptions{VerifyIntegrity: true},
+			err:      "integrity sha512-AAAA of the published package piper-project@1.0.0 does not match the integrity " + integrity + " of the packed tarball",
+		},
+		{
+			name:        "published version available after retries",
+			metadata:    []string{"", "", `{"version": "1.0.0", "dist.integrity": "` + integrity + `"}`},
+			options:     PublishOptions{VerifyIntegrity: true, VerifyRetryInterval: time.Nanosecond},
+			publishArgs: []string{"publish", "--tarball", "/package.tgz", "--userconfig", ".piperNpmrc", "--registry", "https://my.private.npm.registry/"},
+		},
+		{
+			name:     "published version not found",
+			metadata: []string{"", "", "", "", ""},
+			options:  PublishOptions{VerifyIntegrity: true, VerifyRetryInterval: time.Nanosecond},
+			err:      "published package piper-project@1.0.0 not found in the registry",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			utils := newNpmMockUtilsBundleRelativeGlob()
+			utils.AddFile("package.json", []byte(`{"name": "piper-project", "version": "1.0.0"}`))
+			exec := &Execute{Utils: &utils, Options: ExecutorOptions{Publish: test.options}}
+			propertiesLoadFile = utils.FileRead
+			propertiesWriteFile = utils.FileWrite
+			writeIgnoreFile = utils.FileWrite
+			metadata := test.metadata
+			utils.execRunner.Stub = func(call string, stdoutReturn map[string]string, shouldFailOnCommand map[string]error, stdout io.Writer) error {
+				if strings.HasPrefix(call, "npm view piper-project@1.0.0 version dist.integrity --json --userconfig .piperNpmrc --registry https://my.private.npm.registry/") {
+					if test.viewError != nil {
+						return test.viewError
+					}
+					_, err := stdout.Write([]byte(metadata[0]))
+					metadata = metadata[1:]
+					return err
+				}
+				utils.AddFile(filepath.Join(".", "package.tgz"), []byte(tarball))
+				return nil
+			}
+
+			coordinates := []versioning.Coordinates{}
+			err := exec.PublishAllPackages([]string{"package.json"}, "https://my.private.npm.registry/", "ThisIsTheUser", "AndHereIsThePassword", "", false, &coordinates)
+
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			if test.skipped {
+				assert.Len(t, utils.execRunner.Calls, 1)
+				assert.Empty(t, coordinates)
+				return
+			}
+			publishCalls := slices.DeleteFunc(slices.Clone(utils.execRunner.Calls), func(call mock.ExecCall) bool { return call.Params[0] != "publish" })
+			if assert.Len(t, publishCalls, 1) {
+				assert.Equal(t, test.publishArgs, publishCalls[0].Params)
+			}
+		})
+	}
+}
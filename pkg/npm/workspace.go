@@ -0,0 +1,177 @@
+package npm
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// WorkspaceOptions configure how scripts are run in the packages of a workspace or monorepo
+type WorkspaceOptions struct {
+	// ChangedFiles restrict the script runs to the packages containing one of the files and the packages depending on them.
+	// Scripts are run in all packages if ChangedFiles is nil.
+	ChangedFiles []string
+	// Parallelism is the maximum number of packages running a script concurrently, packages are run one after the other if it is less than 2
+	Parallelism int
+}
+
+// workspaceGlobalFiles affect all packages of the workspace if they change in the root directory
+var workspaceGlobalFiles = []string{"package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml", "pnpm-workspace.yaml", "bun.lock", "bun.lockb", ".npmrc", ".yarnrc", ".yarnrc.yml"}
+
+// workspacePackage is a package of the workspace with the workspace packages it depends on
+type workspacePackage struct {
+	packageJSON  string
+	dir          string
+	name         string
+	dependencies []string
+}
+
+type workspacePackageDescriptor struct {
+	Name                 string            `json:"name"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// readWorkspacePackages reads the dependencies between the packages, dependencies on packages outside of the list are ignored
+func (exec *Execute) readWorkspacePackages(packageJSONFiles []string) ([]workspacePackage, error) {
+	descriptors := make([]workspacePackageDescriptor, len(packageJSONFiles))
+	names := map[string]bool{}
+	for i, packageJSON := range packageJSONFiles {
+		content, err := exec.Utils.FileRead(packageJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", packageJSON, err)
+		}
+		if err := json.Unmarshal(content, &descriptors[i]); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", packageJSON, err)
+		}
+		if descriptors[i].Name != "" {
+			names[descriptors[i].Name] = true
+		}
+	}
+
+	packages := make([]workspacePackage, len(packageJSONFiles))
+	for i, descriptor := range descriptors {
+		packages[i] = workspacePackage{packageJSON: packageJSONFiles[i], dir: filepath.ToSlash(filepath.Clean(filepath.Dir(packageJSONFiles[i]))), name: descriptor.Name}
+		for _, dependencies := range []map[string]string{descriptor.Dependencies, descriptor.DevDependencies, descriptor.PeerDependencies, descriptor.OptionalDependencies} {
+			for dependency := range dependencies {
+				if names[dependency] && dependency != descriptor.Name && !slices.Contains(packages[i].dependencies, dependency) {
+					packages[i].dependencies = append(packages[i].dependencies, dependency)
+				}
+			}
+		}
+		slices.Sort(packages[i].dependencies)
+	}
+	return packages, nil
+}
+
+// affectedPackages returns the package.json files of the packages containing a changed file and of all packages depending on them
+func affectedPackages(packages []workspacePackage, changedFiles []string) []string {
+	affected := map[string]bool{}
+	for _, file := range changedFiles {
+		file = path.Clean(filepath.ToSlash(file))
+		if path.Dir(file) == "." && slices.Contains(workspaceGlobalFiles, file) {
+			log.Entry().Infof("%s changed, all packages are affected", file)
+			return packageJSONFilesOf(packages)
+		}
+		// the package in the deepest directory contains the file
+		owner := -1
+		for i, pkg := range packages {
+			if (pkg.dir == "." || strings.HasPrefix(file, pkg.dir+"/")) && (owner < 0 || len(pkg.dir) > len(packages[owner].dir)) {
+				owner = i
+			}
+		}
+		if owner >= 0 {
+			affected[packages[owner].packageJSON] = true
+		}
+	}
+
+	// add the dependents until no further package is affected
+	for changed := true; changed; {
+		changed = false
+		for _, pkg := range packages {
+			if affected[pkg.packageJSON] {
+				continue
+			}
+			for _, dependency := range packages {
+				if affected[dependency.packageJSON] && dependency.name != "" && slices.Contains(pkg.dependencies, dependency.name) {
+					affected[pkg.packageJSON] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	result := []string{}
+	for _, pkg := range packages {
+		if affected[pkg.packageJSON] {
+			result = append(result, pkg.packageJSON)
+		}
+	}
+	return result
+}
+
+func packageJSONFilesOf(packages []workspacePackage) []string {
+	files := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		files = append(files, pkg.packageJSON)
+	}
+	return files
+}
+
+// topologicalLevels groups the selected package.json files by the depth of their dependencies within the workspace.
+// Packages of a level only depend on packages of previous levels, the order of the packages within a level is kept.
+func topologicalLevels(packages []workspacePackage, selected []string) ([][]string, error) {
+	byName := map[string]workspacePackage{}
+	for _, pkg := range packages {
+		if pkg.name != "" {
+			byName[pkg.name] = pkg
+		}
+	}
+
+	depths := map[string]int{}
+	var depth func(pkg workspacePackage, visiting []string) (int, error)
+	depth = func(pkg workspacePackage, visiting []string) (int, error) {
+		if d, ok := depths[pkg.packageJSON]; ok {
+			return d, nil
+		}
+		if slices.Contains(visiting, pkg.packageJSON) {
+			return 0, fmt.Errorf("cyclic dependency between the workspace packages %s", strings.Join(append(visiting, pkg.packageJSON), " -> "))
+		}
+		d := 0
+		for _, dependency := range pkg.dependencies {
+			dependencyDepth, err := depth(byName[dependency], append(visiting, pkg.packageJSON))
+			if err != nil {
+				return 0, err
+			}
+			d = max(d, dependencyDepth+1)
+		}
+		depths[pkg.packageJSON] = d
+		return d, nil
+	}
+
+	levels := [][]string{}
+	for _, pkg := range packages {
+		if !slices.Contains(selected, pkg.packageJSON) {
+			continue
+		}
+		d, err := depth(pkg, []string{})
+		if err != nil {
+			return nil, err
+		}
+		for len(levels) <= d {
+			levels = append(levels, []string{})
+		}
+		levels[d] = append(levels[d], pkg.packageJSON)
+	}
+
+	// levels without selected packages are dropped
+	return slices.DeleteFunc(levels, func(level []string) bool { return len(level) == 0 }), nil
+}
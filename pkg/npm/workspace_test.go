@@ -0,0 +1,204 @@
+//go:build unit
+// +build unit
+
+package npm
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type npmConcurrentMockUtilsBundle struct {
+	npmMockUtilsBundle
+	mutex   sync.Mutex
+	runners []*mock.ExecMockRunner
+}
+
+func (u *npmConcurrentMockUtilsBundle) NewExecRunner() command.ExecRunner {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	runner := &mock.ExecMockRunner{}
+	u.runners = append(u.runners, runner)
+	return runner
+}
+
+// scriptDirRecorder records the working directory of the script runs
+type scriptDirRecorder struct {
+	*mock.ExecMockRunner
+	files *mock.FilesMock
+	dirs  []string
+}
+
+func (r *scriptDirRecorder) RunExecutable(e string, p ...string) error {
+	if len(p) > 0 && p[0] == "run" {
+		r.dirs = append(r.dirs, r.files.CurrentDir)
+	}
+	return r.ExecMockRunner.RunExecutable(e, p...)
+}
+
+type npmScriptDirMockUtilsBundle struct {
+	npmMockUtilsBundle
+	recorder *scriptDirRecorder
+}
+
+func (u *npmScriptDirMockUtilsBundle) GetExecRunner() ExecRunner {
+	return u.recorder
+}
+
+func addWorkspace(t *testing.T, utils *npmMockUtilsBundle) {
+	t.Helper()
+	utils.AddFile("package.json", []byte(`{"name": "root", "private": true, "scripts": {"ci-build": "exit 0"}}`))
+	utils.AddFile(filepath.Join("packages", "utils", "package.json"), []byte(`{"name": "@example/utils", "scripts": {"ci-build": "exit 0"}, "dependencies": {"lodash": "^4.17.21"}}`))
+	utils.AddFile(filepath.Join("packages", "core", "package.json"), []byte(`{"name": "@example/core", "scripts": {"ci-build": "exit 0"}, "dependencies": {"@example/utils": "workspace:*"}}`))
+	utils.AddFile(filepath.Join("packages", "web", "package.json"), []byte(`{"name": "@example/web", "scripts": {"ci-build": "exit 0"}, "devDependencies": {"@example/core": "1.0.0"}}`))
+	utils.AddFile(filepath.Join("packages", "docs", "package.json"), []byte(`{"name": "@example/docs", "scripts": {"ci-build": "exit 0"}}`))
+}
+
+var workspacePackageJSONFiles = []string{
+	"package.json",
+	filepath.Join("packages", "web", "package.json"),
+	filepath.Join("packages", "core", "package.json"),
+	filepath.Join("packages", "utils", "package.json"),
+	filepath.Join("packages", "docs", "package.json"),
+}
+
+func TestReadWorkspacePackages(t *testing.T) {
+	utils := newNpmMockUtilsBundle()
+	addWorkspace(t, &utils)
+	exec := &Execute{Utils: &utils}
+
+	packages, err := exec.readWorkspacePackages(workspacePackageJSONFiles)
+
+	require.NoError(t, err)
+	assert.Equal(t, []workspacePackage{
+		{packageJSON: "package.json", dir: ".", name: "root"},
+		{packageJSON: workspacePackageJSONFiles[1], dir: "packages/web", name: "@example/web", dependencies: []string{"@example/core"}},
+		{packageJSON: workspacePackageJSONFiles[2], dir: "packages/core", name: "@example/core", dependencies: []string{"@example/utils"}},
+		{packageJSON: workspacePackageJSONFiles[3], dir: "packages/utils", name: "@example/utils"},
+		{packageJSON: workspacePackageJSONFiles[4], dir: "packages/docs", name: "@example/docs"},
+	}, packages)
+
+	t.Run("error case", func(t *testing.T) {
+		utils.AddFile("broken.json", []byte("{"))
+
+		_, err := exec.readWorkspacePackages([]string{"broken.json"})
+
+		assert.ErrorContains(t, err, "failed to unmarshal broken.json")
+	})
+}
+
+func TestAffectedPackages(t *testing.T) {
+	utils := newNpmMockUtilsBundle()
+	addWorkspace(t, &utils)
+	packages, err := (&Execute{Utils: &utils}).readWorkspacePackages(workspacePackageJSONFiles)
+	require.NoError(t, err)
+
+	t.Run("changed package and its dependents", func(t *testing.T) {
+		affected := affectedPackages(packages, []string{"packages/utils/src/index.js"})
+
+		assert.Equal(t, workspacePackageJSONFiles[1:4], affected)
+	})
+
+	t.Run("file owned by the root package", func(t *testing.T) {
+		affected := affectedPackages(packages, []string{"README.md", "packages/docs/README.md"})
+
+		assert.Equal(t, []string{"package.json", workspacePackageJSONFiles[4]}, affected)
+	})
+
+	t.Run("lock file in the root directory", func(t *testing.T) {
+		affected := affectedPackages(packages, []string{"pnpm-lock.yaml"})
+
+		assert.Equal(t, workspacePackageJSONFiles, affected)
+	})
+
+	t.Run("no changed files", func(t *testing.T) {
+		assert.Empty(t, affectedPackages(packages, []string{}))
+	})
+}
+
+func TestTopologicalLevels(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		utils := newNpmMockUtilsBundle()
+		addWorkspace(t, &utils)
+		packages, err := (&Execute{Utils: &utils}).readWorkspacePackages(workspacePackageJSONFiles)
+		require.NoError(t, err)
+
+		levels, err := topologicalLevels(packages, []string{workspacePackageJSONFiles[1], workspacePackageJSONFiles[3], workspacePackageJSONFiles[4]})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{workspacePackageJSONFiles[3], workspacePackageJSONFiles[4]},
+			{workspacePackageJSONFiles[1]},
+		}, levels)
+	})
+
+	t.Run("cyclic dependency", func(t *testing.T) {
+		packages := []workspacePackage{
+			{packageJSON: "a/package.json", name: "a", dependencies: []string{"b"}},
+			{packageJSON: "b/package.json", name: "b", dependencies: []string{"a"}},
+		}
+
+		_, err := topologicalLevels(packages, []string{"a/package.json", "b/package.json"})
+
+		assert.EqualError(t, err, "cyclic dependency between the workspace packages a/package.json -> b/package.json -> a/package.json")
+	})
+}
+
+func TestRunScriptsInWorkspace(t *testing.T) {
+	t.Run("topological order", func(t *testing.T) {
+		utils := &npmScriptDirMockUtilsBundle{npmMockUtilsBundle: newNpmMockUtilsBundle()}
+		utils.recorder = &scriptDirRecorder{ExecMockRunner: utils.execRunner, files: utils.FilesMock}
+		addWorkspace(t, &utils.npmMockUtilsBundle)
+		exec := &Execute{Utils: utils, pnpmSetup: pnpmSetupState{rootDir: "/"}}
+
+		err := exec.RunScriptsInAllPackages([]string{"ci-build"}, nil, nil, false, nil, workspacePackageJSONFiles)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"", filepath.Join("packages", "utils"), filepath.Join("packages", "docs"), filepath.Join("packages", "core"), filepath.Join("packages", "web")}, utils.recorder.dirs)
+	})
+
+	t.Run("affected packages only", func(t *testing.T) {
+		utils := newNpmMockUtilsBundle()
+		addWorkspace(t, &utils)
+		exec := &Execute{Utils: &utils, pnpmSetup: pnpmSetupState{rootDir: "/"}, Options: ExecutorOptions{Workspace: WorkspaceOptions{ChangedFiles: []string{"packages/core/index.js"}}}}
+
+		err := exec.RunScriptsInAllPackages([]string{"ci-build"}, nil, nil, false, nil, workspacePackageJSONFiles)
+
+		require.NoError(t, err)
+		runs := 0
+		for _, call := range utils.execRunner.Calls {
+			if call.Exec == "npm" && call.Params[0] == "run" {
+				runs++
+			}
+		}
+		assert.Equal(t, 2, runs)
+	})
+
+	t.Run("packages in parallel", func(t *testing.T) {
+		utils := &npmConcurrentMockUtilsBundle{npmMockUtilsBundle: newNpmMockUtilsBundle()}
+		addWorkspace(t, &utils.npmMockUtilsBundle)
+		exec := &Execute{Utils: utils, pnpmSetup: pnpmSetupState{rootDir: "/"}, Options: ExecutorOptions{Workspace: WorkspaceOptions{Parallelism: 4}}}
+
+		err := exec.RunScriptsInAllPackages([]string{"ci-build"}, nil, []string{"--verbose"}, false, nil, workspacePackageJSONFiles)
+
+		require.NoError(t, err)
+		// root and docs don't depend on other packages and run concurrently with utils
+		require.Len(t, utils.runners, 3)
+		dirs := []string{}
+		for _, runner := range utils.runners {
+			assert.Equal(t, []mock.ExecCall{{Exec: "npm", Params: []string{"run", "ci-build", "--", "--verbose"}}}, runner.Calls)
+			dirs = append(dirs, runner.Dir...)
+		}
+		sort.Strings(dirs)
+		assert.Equal(t, []string{".", filepath.Join("packages", "docs"), filepath.Join("packages", "utils")}, dirs)
+		// core and web are the only packages of their level and run one after the other
+		assert.Equal(t, mock.ExecCall{Exec: "npm", Params: []string{"run", "ci-build", "--", "--verbose"}}, utils.execRunner.Calls[len(utils.execRunner.Calls)-1])
+	})
+}
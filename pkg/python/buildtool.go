@@ -0,0 +1,197 @@
+package python
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// Build tools which manage python projects
+const (
+	BuildToolPip    = "pip"
+	BuildToolPoetry = "poetry"
+	BuildToolPDM    = "pdm"
+	BuildToolHatch  = "hatch"
+	BuildToolUv     = "uv"
+)
+
+// LockFiles maps the build tools to their lock file
+var LockFiles = map[string]string{
+	BuildToolPoetry: "poetry.lock",
+	BuildToolPDM:    "pdm.lock",
+	BuildToolUv:     "uv.lock",
+}
+
+const lockFileRequirements = "requirements-lock.txt"
+
+// DetectBuildTool determines the build tool of the project from its lock file or the tool configuration in the pyproject.toml.
+// Projects without lock file or tool configuration are built with pip.
+func DetectBuildTool(
+	existsFn func(path string) (bool, error),
+	readFileFn func(path string) ([]byte, error),
+) string {
+	for _, tool := range []string{BuildToolPoetry, BuildToolPDM, BuildToolUv} {
+		if exists, _ := existsFn(LockFiles[tool]); exists {
+			log.Entry().Infof("%s found, building with %s", LockFiles[tool], tool)
+			return tool
+		}
+	}
+	if exists, _ := existsFn("hatch.toml"); exists {
+		log.Entry().Info("hatch.toml found, building with hatch")
+		return BuildToolHatch
+	}
+	content, err := readFileFn("pyproject.toml")
+	if err != nil {
+		return BuildToolPip
+	}
+	// the metadata of Poetry 1 projects and the environments of Hatch are only understood by the tools themselves
+	if regexp.MustCompile(`(?m)^\s*\[tool\.poetry\]`).Match(content) {
+		log.Entry().Info("poetry configuration found in pyproject.toml, building with poetry")
+		return BuildToolPoetry
+	}
+	if regexp.MustCompile(`(?m)^\s*\[tool\.hatch\.envs`).Match(content) {
+		log.Entry().Info("hatch environments found in pyproject.toml, building with hatch")
+		return BuildToolHatch
+	}
+	return BuildToolPip
+}
+
+// InstallBuildTool installs the build tool into the virtual environment
+func InstallBuildTool(
+	executeFn func(executable string, params ...string) error,
+	virtualEnv string,
+	tool string,
+) error {
+	log.Entry().Debugf("installing %s", tool)
+	return install(executeFn, virtualEnv, tool, "", nil)
+}
+
+// InstallLockedDependencies installs the dependencies of the project exactly as listed in the lock file.
+// The installation fails if the lock file is outdated. Hatch has no lock file and manages the environments itself.
+func InstallLockedDependencies(
+	executeFn func(executable string, params ...string) error,
+	virtualEnv string,
+	tool string,
+) error {
+	log.Entry().Debug("installing locked project dependencies")
+	switch tool {
+	case BuildToolPoetry:
+		return executeFn(getBinary(virtualEnv, "poetry"), "install", "--no-interaction")
+	case BuildToolPDM:
+		return executeFn(getBinary(virtualEnv, "pdm"), "sync", "--no-self")
+	case BuildToolUv:
+		return executeFn(getBinary(virtualEnv, "uv"), "sync", "--locked", "--active", "--no-install-project")
+	case BuildToolHatch:
+		return nil
+	default:
+		return fmt.Errorf("build tool '%s' not supported", tool)
+	}
+}
+
+// BuildWithTool installs the build tool and the locked dependencies and builds the wheel and tarball with the build tool
+func BuildWithTool(
+	executeFn func(executable string, params ...string) error,
+	virtualEnv string,
+	tool string,
+	buildArgs []string,
+) error {
+	if err := InstallBuildTool(executeFn, virtualEnv, tool); err != nil {
+		return fmt.Errorf("failed to install %s: %w", tool, err)
+	}
+	if err := InstallLockedDependencies(executeFn, virtualEnv, tool); err != nil {
+		return fmt.Errorf("failed to install project dependencies: %w", err)
+	}
+
+	flags := []string{"build"}
+	flags = append(flags, buildArgs...)
+
+	log.Entry().Debugf("building project with %s", tool)
+	return executeFn(getBinary(virtualEnv, tool), flags...)
+}
+
+// PublishPackageWithTool uploads the wheel and tarball in the dist folder with the build tool.
+// Poetry reads the repository and its credentials from the environment, which is extended with appendEnvFn.
+func PublishPackageWithTool(
+	executeFn func(executable string, params ...string) error,
+	appendEnvFn func(env []string),
+	virtualEnv string,
+	tool string,
+	repository string,
+	username string,
+	password string,
+) error {
+	binary := getBinary(virtualEnv, tool)
+	switch tool {
+	case BuildToolPoetry:
+		// the repository is not written to the poetry.toml of the project
+		appendEnvFn([]string{
+			"POETRY_REPOSITORIES_PIPER_URL=" + repository,
+			"POETRY_HTTP_BASIC_PIPER_USERNAME=" + username,
+			"POETRY_HTTP_BASIC_PIPER_PASSWORD=" + password,
+		})
+		return executeFn(binary, "publish", "--no-interaction", "--repository", "piper")
+	case BuildToolPDM:
+		return executeFn(binary, "publish", "--no-build", "--repository", repository, "--username", username, "--password", password)
+	case BuildToolHatch:
+		return executeFn(binary, "publish", "--no-prompt", "--repo", repository, "--user", username, "--auth", password, "dist")
+	case BuildToolUv:
+		return executeFn(binary, "publish", "--publish-url", repository, "--username", username, "--password", password, "dist/*")
+	default:
+		return PublishPackage(executeFn, virtualEnv, repository, username, password)
+	}
+}
+
+// CreateBOMFromLockFile creates the BOM from the dependencies listed in the lock file of the build tool.
+// The lock files of PDM and uv are exported to a requirements file first.
+func CreateBOMFromLockFile(
+	executeFn func(executable string, params ...string) error,
+	readFileFn func(path string) ([]byte, error),
+	virtualEnv string,
+	tool string,
+	cycloneDxVersion string,
+	cycloneDxSchemaVersion string,
+) error {
+	var args []string
+	switch tool {
+	case BuildToolPoetry:
+		args = []string{"poetry"}
+	case BuildToolPDM:
+		if err := executeFn(getBinary(virtualEnv, "pdm"), "export", "--format", "requirements", "--without-hashes", "--prod", "--output", lockFileRequirements); err != nil {
+			return fmt.Errorf("failed to export %s: %w", LockFiles[tool], err)
+		}
+		args = []string{"requirements"}
+	case BuildToolUv:
+		if err := executeFn(getBinary(virtualEnv, "uv"), "export", "--format", "requirements-txt", "--no-hashes", "--no-dev", "--no-emit-project", "--output-file", lockFileRequirements); err != nil {
+			return fmt.Errorf("failed to export %s: %w", LockFiles[tool], err)
+		}
+		args = []string{"requirements"}
+	default:
+		return fmt.Errorf("build tool '%s' has no lock file", tool)
+	}
+
+	if err := InstallCycloneDX(executeFn, virtualEnv, cycloneDxVersion); err != nil {
+		return fmt.Errorf("failed to install cyclonedx module: %w", err)
+	}
+
+	log.Entry().Debugf("creating BOM from %s", LockFiles[tool])
+	args = append(args,
+		"--output-file", BOMFilename,
+		"--output-format", "XML",
+		"--spec-version", cycloneDxSchemaVersion,
+	)
+	if args[0] == "requirements" {
+		if hasMetadata := pyprojectHasMetadata(readFileFn, "pyproject.toml"); hasMetadata {
+			args = append(args, "--pyproject", "pyproject.toml")
+		}
+		args = append(args, lockFileRequirements)
+	}
+	if err := executeFn(getBinary(virtualEnv, "cyclonedx-py"), args...); err != nil {
+		return fmt.Errorf("failed to create BOM: %w", err)
+	}
+
+	if err := addPurlToRootComponent(BOMFilename); err != nil {
+		log.Entry().Warnf("failed to add purl to root component: %v", err)
+	}
+	return nil
+}
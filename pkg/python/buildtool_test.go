@@ -0,0 +1,182 @@
+//go:build unit
+// +build unit
+
+package python
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectBuildTool(t *testing.T) {
+	tt := []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{name: "poetry lock file", files: map[string]string{"pyproject.toml": "[project]\n", "poetry.lock": ""}, expected: BuildToolPoetry},
+		{name: "pdm lock file", files: map[string]string{"pyproject.toml": "[project]\n", "pdm.lock": ""}, expected: BuildToolPDM},
+		{name: "uv lock file", files: map[string]string{"pyproject.toml": "[project]\n", "uv.lock": ""}, expected: BuildToolUv},
+		{name: "hatch.toml", files: map[string]string{"pyproject.toml": "[project]\n", "hatch.toml": ""}, expected: BuildToolHatch},
+		{name: "poetry section", files: map[string]string{"pyproject.toml": "[tool.poetry]\nname = \"example\"\n"}, expected: BuildToolPoetry},
+		{name: "hatch environments", files: map[string]string{"pyproject.toml": "[project]\n\n[tool.hatch.envs.default]\n"}, expected: BuildToolHatch},
+		{name: "pyproject.toml without tool", files: map[string]string{"pyproject.toml": "[project]\n\n[tool.uv]\npackage = false\n"}, expected: BuildToolPip},
+		{name: "setup.py", files: map[string]string{"setup.py": ""}, expected: BuildToolPip},
+	}
+
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			mockFiles := mock.FilesMock{}
+			for name, content := range test.files {
+				mockFiles.AddFile(name, []byte(content))
+			}
+
+			assert.Equal(t, test.expected, DetectBuildTool(mockFiles.FileExists, mockFiles.ReadFile))
+		})
+	}
+}
+
+func TestBuildWithTool(t *testing.T) {
+	tt := []struct {
+		tool    string
+		install []string
+	}{
+		{tool: BuildToolPoetry, install: []string{"install", "--no-interaction"}},
+		{tool: BuildToolPDM, install: []string{"sync", "--no-self"}},
+		{tool: BuildToolUv, install: []string{"sync", "--locked", "--active", "--no-install-project"}},
+	}
+
+	for _, test := range tt {
+		t.Run(test.tool, func(t *testing.T) {
+			mockRunner := mock.ExecMockRunner{}
+
+			err := BuildWithTool(mockRunner.RunExecutable, ".venv", test.tool, []string{"--verbose"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, []mock.ExecCall{
+				{Exec: ".venv/bin/pip", Params: []string{"install", "--upgrade", "--root-user-action=ignore", test.tool}},
+				{Exec: ".venv/bin/" + test.tool, Params: test.install},
+				{Exec: ".venv/bin/" + test.tool, Params: []string{"build", "--verbose"}},
+			}, mockRunner.Calls)
+		})
+	}
+
+	t.Run("hatch", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{}
+
+		err := BuildWithTool(mockRunner.RunExecutable, ".venv", BuildToolHatch, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: ".venv/bin/pip", Params: []string{"install", "--upgrade", "--root-user-action=ignore", "hatch"}},
+			{Exec: ".venv/bin/hatch", Params: []string{"build"}},
+		}, mockRunner.Calls)
+	})
+
+	t.Run("outdated lock file", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{".venv/bin/uv sync --locked --active --no-install-project": fmt.Errorf("the lockfile needs to be updated")}}
+
+		err := BuildWithTool(mockRunner.RunExecutable, ".venv", BuildToolUv, nil)
+
+		assert.EqualError(t, err, "failed to install project dependencies: the lockfile needs to be updated")
+	})
+}
+
+func TestPublishPackageWithTool(t *testing.T) {
+	tt := []struct {
+		tool     string
+		expected []mock.ExecCall
+	}{
+		{tool: BuildToolPoetry, expected: []mock.ExecCall{
+			{Exec: ".venv/bin/poetry", Params: []string{"publish", "--no-interaction", "--repository", "piper"}},
+		}},
+		{tool: BuildToolPDM, expected: []mock.ExecCall{
+			{Exec: ".venv/bin/pdm", Params: []string{"publish", "--no-build", "--repository", "https://repo.example.com", "--username", "user", "--password", "secret"}},
+		}},
+		{tool: BuildToolHatch, expected: []mock.ExecCall{
+			{Exec: ".venv/bin/hatch", Params: []string{"publish", "--no-prompt", "--repo", "https://repo.example.com", "--user", "user", "--auth", "secret", "dist"}},
+		}},
+		{tool: BuildToolUv, expected: []mock.ExecCall{
+			{Exec: ".venv/bin/uv", Params: []string{"publish", "--publish-url", "https://repo.example.com", "--username", "user", "--password", "secret", "dist/*"}},
+		}},
+		{tool: BuildToolPip, expected: []mock.ExecCall{
+			{Exec: ".venv/bin/pip", Params: []string{"install", "--upgrade", "--root-user-action=ignore", "twine"}},
+			{Exec: ".venv/bin/twine", Params: []string{"upload", "--username", "user", "--password", "secret", "--repository-url", "https://repo.example.com", "--disable-progress-bar", "dist/*"}},
+		}},
+	}
+
+	for _, test := range tt {
+		t.Run(test.tool, func(t *testing.T) {
+			mockRunner := mock.ExecMockRunner{}
+
+			err := PublishPackageWithTool(mockRunner.RunExecutable, mockRunner.AppendEnv, ".venv", test.tool, "https://repo.example.com", "user", "secret")
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, mockRunner.Calls)
+			if test.tool == BuildToolPoetry {
+				assert.Equal(t, []string{
+					"POETRY_REPOSITORIES_PIPER_URL=https://repo.example.com",
+					"POETRY_HTTP_BASIC_PIPER_USERNAME=user",
+					"POETRY_HTTP_BASIC_PIPER_PASSWORD=secret",
+				}, mockRunner.Env)
+			} else {
+				assert.Empty(t, mockRunner.Env)
+			}
+		})
+	}
+}
+
+func TestCreateBOMFromLockFile(t *testing.T) {
+	installCycloneDX := mock.ExecCall{Exec: ".venv/bin/pip", Params: []string{"install", "--upgrade", "--root-user-action=ignore", "cyclonedx-bom==1.2.3"}}
+
+	t.Run("poetry", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{}
+		mockFiles := mock.FilesMock{}
+
+		err := CreateBOMFromLockFile(mockRunner.RunExecutable, mockFiles.ReadFile, ".venv", BuildToolPoetry, "1.2.3", "1.4")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			installCycloneDX,
+			{Exec: ".venv/bin/cyclonedx-py", Params: []string{"poetry", "--output-file", "bom-pip.xml", "--output-format", "XML", "--spec-version", "1.4"}},
+		}, mockRunner.Calls)
+	})
+
+	t.Run("uv", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{}
+		mockFiles := mock.FilesMock{}
+		mockFiles.AddFile("pyproject.toml", []byte("[project]\nname = \"example\"\nversion = \"1.0.0\"\n"))
+
+		err := CreateBOMFromLockFile(mockRunner.RunExecutable, mockFiles.ReadFile, ".venv", BuildToolUv, "1.2.3", "1.4")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: ".venv/bin/uv", Params: []string{"export", "--format", "requirements-txt", "--no-hashes", "--no-dev", "--no-emit-project", "--output-file", "requirements-lock.txt"}},
+			installCycloneDX,
+			{Exec: ".venv/bin/cyclonedx-py", Params: []string{"requirements", "--output-file", "bom-pip.xml", "--output-format", "XML", "--spec-version", "1.4", "--pyproject", "pyproject.toml", "requirements-lock.txt"}},
+		}, mockRunner.Calls)
+	})
+
+	t.Run("pdm", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{}
+		mockFiles := mock.FilesMock{}
+
+		err := CreateBOMFromLockFile(mockRunner.RunExecutable, mockFiles.ReadFile, ".venv", BuildToolPDM, "1.2.3", "1.4")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mock.ExecCall{Exec: ".venv/bin/pdm", Params: []string{"export", "--format", "requirements", "--without-hashes", "--prod", "--output", "requirements-lock.txt"}}, mockRunner.Calls[0])
+		assert.Equal(t, []string{"requirements", "--output-file", "bom-pip.xml", "--output-format", "XML", "--spec-version", "1.4", "requirements-lock.txt"}, mockRunner.Calls[2].Params)
+	})
+
+	t.Run("no lock file", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{}
+		mockFiles := mock.FilesMock{}
+
+		err := CreateBOMFromLockFile(mockRunner.RunExecutable, mockFiles.ReadFile, ".venv", BuildToolHatch, "1.2.3", "1.4")
+
+		assert.EqualError(t, err, "build tool 'hatch' has no lock file")
+	})
+}
@@ -1,6 +1,7 @@
 package python
 
 import (
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"os/exec"
@@ -14,6 +15,34 @@ const (
 	CoverageReportFile = "cobertura-coverage.xml"
 )
 
+// Test runners which execute the tests of python projects
+const (
+	TestRunnerPytest = "pytest"
+	TestRunnerTox    = "tox"
+	TestRunnerNox    = "nox"
+)
+
+// TestReports are the JUnit and Cobertura reports of a test run
+type TestReports struct {
+	// PythonVersion is empty for the tests run with the python of the build
+	PythonVersion string
+	JUnit         string
+	Coverage      string
+}
+
+// ReportFiles returns the report files of the test run with the python version.
+// The reports of the test run with the python of the build have no version suffix.
+func ReportFiles(pythonVersion string) TestReports {
+	if len(pythonVersion) == 0 {
+		return TestReports{JUnit: JUnitReportFile, Coverage: CoverageReportFile}
+	}
+	return TestReports{
+		PythonVersion: pythonVersion,
+		JUnit:         strings.TrimSuffix(JUnitReportFile, ".xml") + "-" + pythonVersion + ".xml",
+		Coverage:      strings.TrimSuffix(CoverageReportFile, ".xml") + "-" + pythonVersion + ".xml",
+	}
+}
+
 // RunTests runs pytest inside virtualEnv with the given extra testOptions.
 // executeFn must propagate *exec.ExitError transparently (via %w) so that the
 // exit-code-5 "no tests collected" branch can unwrap it with errors.As.
@@ -23,11 +52,167 @@ func RunTests(
 	testOptions []string,
 ) error {
 	log.Entry().Debug("running python tests")
-	// Reject testOptions that would silently relocate the report files managed
-	// by this step. The GCS upload globs in pythonBuild metadata are pinned to
-	// JUnitReportFile and CoverageReportFile; overriding them via testOptions
-	// causes reports to land at a different path while the upload glob matches
-	// nothing — producing a silent green build with no artifacts in GCS.
+	return runPytest(executeFn, getBinary(virtualEnv, "pytest"), ReportFiles(""), testOptions)
+}
+
+// TestEnvironment returns the virtual environment of the tests with the python version, which is located next to virtualEnv
+func TestEnvironment(virtualEnv, pythonVersion string) string {
+	return fmt.Sprintf("%s-py%s", virtualEnv, pythonVersion)
+}
+
+// CoverageDataFile returns the coverage data file of the tests with the python version.
+// It is set as COVERAGE_FILE, so that the tests running in parallel do not write to the same .coverage file.
+func CoverageDataFile(pythonVersion string) string {
+	return ".coverage.py" + pythonVersion
+}
+
+// CreateTestEnvironment creates the virtual environment of the tests with the python version and installs the project into
+// it like into the virtual environment of the build. The python version has to be installed on the agent as python<version>,
+// e.g. python3.12. Build tools other than pip install into the active virtual environment, executeFn has to set VIRTUAL_ENV
+// to the test environment.
+func CreateTestEnvironment(
+	executeFn func(executable string, params ...string) error,
+	existsFn func(path string) (bool, error),
+	virtualEnv string,
+	pythonVersion string,
+	tool string,
+	requirementsFile string,
+) error {
+	versionEnv := TestEnvironment(virtualEnv, pythonVersion)
+	log.Entry().Debugf("creating virtual environment for python %s", pythonVersion)
+	if err := executeFn("python"+pythonVersion, "-m", "venv", versionEnv); err != nil {
+		return fmt.Errorf("failed to create virtual environment for python %s: %w", pythonVersion, err)
+	}
+	if err := InstallProject(executeFn, existsFn, versionEnv, tool, requirementsFile); err != nil {
+		return fmt.Errorf("failed to install project for python %s: %w", pythonVersion, err)
+	}
+	if err := InstallTestDependencies(executeFn, versionEnv); err != nil {
+		return fmt.Errorf("failed to install test dependencies for python %s: %w", pythonVersion, err)
+	}
+	return nil
+}
+
+// InstallProject installs the project like the build does, with the build tool from its lock file
+// or with pip from the requirements file, if it exists, and the project descriptor.
+func InstallProject(
+	executeFn func(executable string, params ...string) error,
+	existsFn func(path string) (bool, error),
+	virtualEnv string,
+	tool string,
+	requirementsFile string,
+) error {
+	if len(tool) > 0 && tool != BuildToolPip {
+		if err := InstallBuildTool(executeFn, virtualEnv, tool); err != nil {
+			return fmt.Errorf("failed to install %s: %w", tool, err)
+		}
+		return InstallLockedDependencies(executeFn, virtualEnv, tool)
+	}
+	if len(requirementsFile) > 0 {
+		if exists, _ := existsFn(requirementsFile); exists {
+			if err := InstallRequirements(executeFn, virtualEnv, requirementsFile); err != nil {
+				return err
+			}
+		}
+	}
+	return InstallProjectDependencies(executeFn, virtualEnv)
+}
+
+// RunTestsWithPythonVersion runs pytest in the test environment of the python version created by CreateTestEnvironment
+func RunTestsWithPythonVersion(
+	executeFn func(executable string, params ...string) error,
+	virtualEnv string,
+	pythonVersion string,
+	testOptions []string,
+) (TestReports, error) {
+	reports := ReportFiles(pythonVersion)
+	log.Entry().Debugf("running python tests with python %s", pythonVersion)
+	return reports, runPytest(executeFn, getBinary(TestEnvironment(virtualEnv, pythonVersion), "pytest"), reports, testOptions)
+}
+
+// InstallTestRunner installs tox or nox into the virtual environment
+func InstallTestRunner(
+	executeFn func(executable string, params ...string) error,
+	virtualEnv string,
+	testRunner string,
+) error {
+	log.Entry().Debugf("installing %s", testRunner)
+	return install(executeFn, virtualEnv, testRunner, "", nil)
+}
+
+// RunTestsWithRunner runs the tests with tox or nox. The report options are passed as positional arguments, which the
+// tox environments and nox sessions have to forward to pytest, e.g. with `pytest {posargs}` or `session.run("pytest", *session.posargs)`.
+// With a python version only the tox environment or the nox sessions of the python version are run.
+func RunTestsWithRunner(
+	executeFn func(executable string, params ...string) error,
+	virtualEnv string,
+	testRunner string,
+	pythonVersion string,
+	testOptions []string,
+) (TestReports, error) {
+	reports := ReportFiles(pythonVersion)
+	if err := validateTestOptions(testOptions); err != nil {
+		return reports, err
+	}
+
+	var args []string
+	switch testRunner {
+	case TestRunnerTox:
+		args = []string{"run"}
+		if len(pythonVersion) > 0 {
+			args = append(args, "-e", "py"+pythonVersion)
+		}
+	case TestRunnerNox:
+		if len(pythonVersion) > 0 {
+			args = append(args, "--python", pythonVersion)
+		}
+	default:
+		return reports, fmt.Errorf("test runner '%s' not supported", testRunner)
+	}
+	args = append(args, "--")
+	args = append(args, reportArgs(reports)...)
+	args = append(args, testOptions...)
+
+	log.Entry().Debugf("running python tests with %s", testRunner)
+	if err := executeFn(getBinary(virtualEnv, testRunner), args...); err != nil {
+		return reports, fmt.Errorf("%s execution failed: %w", testRunner, err)
+	}
+	return reports, nil
+}
+
+func runPytest(
+	executeFn func(executable string, params ...string) error,
+	pytest string,
+	reports TestReports,
+	testOptions []string,
+) error {
+	if err := validateTestOptions(testOptions); err != nil {
+		return err
+	}
+	args := append(reportArgs(reports), testOptions...)
+	if err := executeFn(pytest, args...); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 5 {
+			return fmt.Errorf("pytest collected no tests — ensure your project has tests under a discoverable path (default: ./tests): %w", err)
+		}
+		return fmt.Errorf("pytest execution failed: %w", err)
+	}
+	return nil
+}
+
+func reportArgs(reports TestReports) []string {
+	return []string{
+		"--junitxml=" + reports.JUnit,
+		"--cov", // bare --cov covers all discovered code; pass --cov=<pkg> via testOptions to scope it
+		"--cov-report=xml:" + reports.Coverage,
+	}
+}
+
+// validateTestOptions rejects testOptions that would silently relocate the report files managed
+// by this step. The GCS upload globs in pythonBuild metadata are pinned to
+// JUnitReportFile and CoverageReportFile; overriding them via testOptions
+// causes reports to land at a different path while the upload glob matches
+// nothing — producing a silent green build with no artifacts in GCS.
+func validateTestOptions(testOptions []string) error {
 	for i, opt := range testOptions {
 		if strings.HasPrefix(opt, "--junitxml") || strings.HasPrefix(opt, "--junit-xml") {
 			return fmt.Errorf("testOptions must not override --junitxml/--junit-xml; the report path is managed by the step (got %q)", opt)
@@ -41,18 +226,23 @@ func RunTests(
 			return fmt.Errorf("testOptions must not override --cov-report xml; the report path is managed by the step (got %q %q)", opt, testOptions[i+1])
 		}
 	}
-	args := []string{
-		"--junitxml=" + JUnitReportFile,
-		"--cov", // bare --cov covers all discovered code; pass --cov=<pkg> via testOptions to scope it
-		"--cov-report=xml:" + CoverageReportFile,
+	return nil
+}
+
+// LineCoverage returns the line coverage in percent from the Cobertura report
+func LineCoverage(
+	readFileFn func(path string) ([]byte, error),
+	coverageReport string,
+) (float64, error) {
+	content, err := readFileFn(coverageReport)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read coverage report %s: %w", coverageReport, err)
 	}
-	args = append(args, testOptions...)
-	if err := executeFn(getBinary(virtualEnv, "pytest"), args...); err != nil {
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) && exitErr.ExitCode() == 5 {
-			return fmt.Errorf("pytest collected no tests — ensure your project has tests under a discoverable path (default: ./tests): %w", err)
-		}
-		return fmt.Errorf("pytest execution failed: %w", err)
+	var coverage struct {
+		LineRate float64 `xml:"line-rate,attr"`
 	}
-	return nil
+	if err := xml.Unmarshal(content, &coverage); err != nil {
+		return 0, fmt.Errorf("failed to parse coverage report %s: %w", coverageReport, err)
+	}
+	return coverage.LineRate * 100, nil
 }
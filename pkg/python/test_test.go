@@ -29,8 +29,9 @@ func exitError(t *testing.T, code int) error {
 // TestReportFileConstants pins JUnitReportFile and CoverageReportFile to their
 // expected string values. These constants are duplicated as glob patterns in
 // resources/metadata/pythonBuild.yaml under the `reports` output resource
-// ("**/TEST-python.xml" and "**/cobertura-coverage.xml"). If you change either
-// constant you MUST also update the metadata YAML and re-run `go generate`.
+// ("**/TEST-python*.xml" and "**/cobertura-coverage*.xml", which also match the
+// reports of the python versions). If you change either constant you MUST also
+// update the metadata YAML and re-run `go generate`.
 func TestReportFileConstants(t *testing.T) {
 	assert.Equal(t, "TEST-python.xml", JUnitReportFile,
 		"JUnitReportFile must match the glob in resources/metadata/pythonBuild.yaml reports output")
@@ -217,3 +218,160 @@ func TestRunTestsExitCode5(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "pytest collected no tests")
 }
+
+func TestReportFiles(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, TestReports{JUnit: "TEST-python.xml", Coverage: "cobertura-coverage.xml"}, ReportFiles(""))
+	assert.Equal(t, TestReports{PythonVersion: "3.12", JUnit: "TEST-python-3.12.xml", Coverage: "cobertura-coverage-3.12.xml"}, ReportFiles("3.12"))
+}
+
+func TestCreateTestEnvironment(t *testing.T) {
+	t.Parallel()
+	pip := filepath.Join(".venv-py3.12", "bin", "pip")
+	installTestDependencies := []mock.ExecCall{
+		{Exec: pip, Params: []string{"install", "--upgrade", "--root-user-action=ignore", "pytest"}},
+		{Exec: pip, Params: []string{"install", "--upgrade", "--root-user-action=ignore", "pytest-cov"}},
+	}
+
+	t.Run("pip with requirements file", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{}
+		mockFiles := mock.FilesMock{}
+		mockFiles.AddFile("requirements.txt", []byte("requests==2.32.3"))
+
+		err := CreateTestEnvironment(mockRunner.RunExecutable, mockFiles.FileExists, ".venv", "3.12", BuildToolPip, "requirements.txt")
+
+		assert.NoError(t, err)
+		assert.Equal(t, append([]mock.ExecCall{
+			{Exec: "python3.12", Params: []string{"-m", "venv", ".venv-py3.12"}},
+			{Exec: pip, Params: []string{"install", "--upgrade", "--root-user-action=ignore", "--requirement", "requirements.txt"}},
+			{Exec: pip, Params: []string{"install", "--upgrade", "--root-user-action=ignore", "."}},
+		}, installTestDependencies...), mockRunner.Calls)
+	})
+
+	t.Run("pip without requirements file", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{}
+
+		err := CreateTestEnvironment(mockRunner.RunExecutable, (&mock.FilesMock{}).FileExists, ".venv", "3.12", BuildToolPip, "requirements.txt")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mock.ExecCall{Exec: pip, Params: []string{"install", "--upgrade", "--root-user-action=ignore", "."}}, mockRunner.Calls[1])
+	})
+
+	t.Run("locked dependencies", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{}
+
+		err := CreateTestEnvironment(mockRunner.RunExecutable, (&mock.FilesMock{}).FileExists, ".venv", "3.12", BuildToolUv, "requirements.txt")
+
+		assert.NoError(t, err)
+		assert.Equal(t, append([]mock.ExecCall{
+			{Exec: "python3.12", Params: []string{"-m", "venv", ".venv-py3.12"}},
+			{Exec: pip, Params: []string{"install", "--upgrade", "--root-user-action=ignore", "uv"}},
+			{Exec: filepath.Join(".venv-py3.12", "bin", "uv"), Params: []string{"sync", "--locked", "--active", "--no-install-project"}},
+		}, installTestDependencies...), mockRunner.Calls)
+	})
+
+	t.Run("python version not installed", func(t *testing.T) {
+		mockRunner := mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{"python3.9": fmt.Errorf("executable file not found")}}
+
+		err := CreateTestEnvironment(mockRunner.RunExecutable, (&mock.FilesMock{}).FileExists, ".venv", "3.9", BuildToolPip, "")
+
+		assert.EqualError(t, err, "failed to create virtual environment for python 3.9: executable file not found")
+	})
+}
+
+func TestRunTestsWithPythonVersion(t *testing.T) {
+	t.Parallel()
+	mockRunner := mock.ExecMockRunner{}
+
+	reports, err := RunTestsWithPythonVersion(mockRunner.RunExecutable, ".venv", "3.12", []string{"-v"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ReportFiles("3.12"), reports)
+	assert.Equal(t, []mock.ExecCall{
+		{Exec: filepath.Join(".venv-py3.12", "bin", "pytest"), Params: []string{"--junitxml=TEST-python-3.12.xml", "--cov", "--cov-report=xml:cobertura-coverage-3.12.xml", "-v"}},
+	}, mockRunner.Calls)
+}
+
+func TestRunTestsWithRunner(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		testRunner    string
+		pythonVersion string
+		wantExec      string
+		wantParams    []string
+	}{
+		{
+			name:       "tox",
+			testRunner: TestRunnerTox,
+			wantExec:   filepath.Join(".venv", "bin", "tox"),
+			wantParams: []string{"run", "--", "--junitxml=TEST-python.xml", "--cov", "--cov-report=xml:cobertura-coverage.xml", "-x"},
+		},
+		{
+			name:          "tox with python version",
+			testRunner:    TestRunnerTox,
+			pythonVersion: "3.11",
+			wantExec:      filepath.Join(".venv", "bin", "tox"),
+			wantParams:    []string{"run", "-e", "py3.11", "--", "--junitxml=TEST-python-3.11.xml", "--cov", "--cov-report=xml:cobertura-coverage-3.11.xml", "-x"},
+		},
+		{
+			name:          "nox with python version",
+			testRunner:    TestRunnerNox,
+			pythonVersion: "3.12",
+			wantExec:      filepath.Join(".venv", "bin", "nox"),
+			wantParams:    []string{"--python", "3.12", "--", "--junitxml=TEST-python-3.12.xml", "--cov", "--cov-report=xml:cobertura-coverage-3.12.xml", "-x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mockRunner := mock.ExecMockRunner{}
+
+			reports, err := RunTestsWithRunner(mockRunner.RunExecutable, ".venv", tt.testRunner, tt.pythonVersion, []string{"-x"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, ReportFiles(tt.pythonVersion), reports)
+			assert.Equal(t, []mock.ExecCall{{Exec: tt.wantExec, Params: tt.wantParams}}, mockRunner.Calls)
+		})
+	}
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+		mockRunner := mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{filepath.Join(".venv", "bin", "nox"): fmt.Errorf("exit status 1")}}
+
+		_, err := RunTestsWithRunner(mockRunner.RunExecutable, ".venv", TestRunnerNox, "", nil)
+
+		assert.EqualError(t, err, "nox execution failed: exit status 1")
+	})
+
+	t.Run("conflicting test options", func(t *testing.T) {
+		t.Parallel()
+		mockRunner := mock.ExecMockRunner{}
+
+		_, err := RunTestsWithRunner(mockRunner.RunExecutable, ".venv", TestRunnerTox, "", []string{"--junitxml=other.xml"})
+
+		assert.ErrorContains(t, err, "--junitxml")
+		assert.Empty(t, mockRunner.Calls)
+	})
+}
+
+func TestLineCoverage(t *testing.T) {
+	t.Parallel()
+	mockFiles := mock.FilesMock{}
+	mockFiles.AddFile("cobertura-coverage.xml", []byte(`<?xml version="1.0" ?>
+<coverage version="7.6.1" timestamp="1760860800000" lines-valid="200" lines-covered="171" line-rate="0.855" branches-covered="0" branches-valid="0" branch-rate="0" complexity="0">
+	<packages/>
+</coverage>`))
+	mockFiles.AddFile("invalid.xml", []byte("<coverage"))
+
+	coverage, err := LineCoverage(mockFiles.FileRead, "cobertura-coverage.xml")
+	assert.NoError(t, err)
+	assert.InDelta(t, 85.5, coverage, 0.001)
+
+	_, err = LineCoverage(mockFiles.FileRead, "invalid.xml")
+	assert.ErrorContains(t, err, "failed to parse coverage report invalid.xml")
+
+	_, err = LineCoverage(mockFiles.FileRead, "missing.xml")
+	assert.ErrorContains(t, err, "failed to read coverage report missing.xml")
+}
@@ -0,0 +1,120 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/ado"
+)
+
+var workItemFingerprintPattern = regexp.MustCompile(`piper-finding: ([^\s"<\\]+)`)
+
+// AzureBoards tracks findings as work items in Azure Boards
+type AzureBoards struct {
+	Client       ado.WorkItemClient
+	WorkItemType string
+	// OpenState is the state re-opened work items are set to
+	OpenState string
+	// ClosedState is the state work items of resolved findings are set to
+	ClosedState string
+}
+
+// FindIssues returns all work items of a tool by the fingerprint contained in their description
+func (a *AzureBoards) FindIssues(ctx context.Context, toolLabel string) (map[string]*TrackedIssue, error) {
+	workItems, err := a.Client.QueryWorkItemsByTag(toolLabel)
+	if err != nil {
+		return nil, fmt.Errorf("error occurred when looking for existing work items: %w", err)
+	}
+	issues := map[string]*TrackedIssue{}
+	for _, workItem := range workItems {
+		match := workItemFingerprintPattern.FindStringSubmatch(workItem.Description)
+		if match == nil {
+			continue
+		}
+		closed := workItem.State == a.closedState()
+		// prefer open work items in case there are duplicates
+		if existing, ok := issues[match[1]]; ok && !existing.Closed {
+			continue
+		}
+		issues[match[1]] = &TrackedIssue{
+			ID:          strconv.Itoa(workItem.ID),
+			Fingerprint: match[1],
+			Title:       workItem.Title,
+			Body:        workItem.Description,
+			Labels:      workItem.Tags,
+			Closed:      closed,
+		}
+	}
+	return issues, nil
+}
+
+// IssueBody renders the text of the finding as HTML and adds the fingerprint
+func (a *AzureBoards) IssueBody(detail IssueDetail, fingerprint string) string {
+	return fmt.Sprintf("<pre>%v</pre><p>piper-finding: %v</p>", html.EscapeString(detail.ToTxt()), fingerprint)
+}
+
+// CreateIssue creates a work item for a finding
+func (a *AzureBoards) CreateIssue(ctx context.Context, issue *TrackedIssue) error {
+	workItemType := a.WorkItemType
+	if len(workItemType) == 0 {
+		workItemType = "Bug"
+	}
+	workItem, err := a.Client.CreateWorkItem(workItemType, map[string]interface{}{
+		ado.FieldTitle:       issue.Title,
+		ado.FieldDescription: issue.Body,
+		ado.FieldTags:        strings.Join(issue.Labels, "; "),
+	})
+	if err != nil {
+		return err
+	}
+	issue.ID = strconv.Itoa(workItem.ID)
+	return nil
+}
+
+// UpdateIssue updates description and tags of a work item
+func (a *AzureBoards) UpdateIssue(ctx context.Context, issue *TrackedIssue) error {
+	return a.updateWorkItem(issue, map[string]interface{}{
+		ado.FieldDescription: issue.Body,
+		ado.FieldTags:        strings.Join(issue.Labels, "; "),
+	})
+}
+
+// SetIssueState moves a work item into the closed or the open state
+func (a *AzureBoards) SetIssueState(ctx context.Context, issue *TrackedIssue, closed bool) error {
+	state := a.OpenState
+	if len(state) == 0 {
+		state = "New"
+	}
+	if closed {
+		state = a.closedState()
+	}
+	if err := a.updateWorkItem(issue, map[string]interface{}{ado.FieldState: state}); err != nil {
+		return err
+	}
+	issue.Closed = closed
+	return nil
+}
+
+// CommentIssue adds a comment to the discussion of a work item
+func (a *AzureBoards) CommentIssue(ctx context.Context, issue *TrackedIssue, comment string) error {
+	return a.updateWorkItem(issue, map[string]interface{}{ado.FieldHistory: html.EscapeString(comment)})
+}
+
+func (a *AzureBoards) updateWorkItem(issue *TrackedIssue, fields map[string]interface{}) error {
+	id, err := strconv.Atoi(issue.ID)
+	if err != nil {
+		return fmt.Errorf("invalid work item id '%v': %w", issue.ID, err)
+	}
+	return a.Client.UpdateWorkItem(id, fields)
+}
+
+func (a *AzureBoards) closedState() string {
+	if len(a.ClosedState) == 0 {
+		return "Closed"
+	}
+	return a.ClosedState
+}
@@ -0,0 +1,71 @@
+//go:build unit
+
+package reporting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/ado"
+	"github.com/stretchr/testify/assert"
+)
+
+type workItemClientMock struct {
+	queriedTag string
+	workItems  []ado.WorkItem
+	created    []map[string]interface{}
+	updated    map[int][]map[string]interface{}
+}
+
+func (w *workItemClientMock) QueryWorkItemsByTag(tag string) ([]ado.WorkItem, error) {
+	w.queriedTag = tag
+	return w.workItems, nil
+}
+
+func (w *workItemClientMock) CreateWorkItem(workItemType string, fields map[string]interface{}) (ado.WorkItem, error) {
+	w.created = append(w.created, fields)
+	return ado.WorkItem{ID: 100}, nil
+}
+
+func (w *workItemClientMock) UpdateWorkItem(id int, fields map[string]interface{}) error {
+	if w.updated == nil {
+		w.updated = map[int][]map[string]interface{}{}
+	}
+	w.updated[id] = append(w.updated[id], fields)
+	return nil
+}
+
+func TestAzureBoardsSyncFindings(t *testing.T) {
+	t.Parallel()
+	clientMock := &workItemClientMock{workItems: []ado.WorkItem{
+		{ID: 1, State: "Active", Tags: []string{"tool:mend", "severity:high"}, Description: "<pre>text</pre><p>piper-finding: CVE-1/pkg:npm/x@1</p>"},
+		{ID: 2, State: "Closed", Tags: []string{"tool:mend", "severity:low"}, Description: "<pre>old</pre><p>piper-finding: CVE-2/pkg:npm/x@1</p>"},
+		{ID: 3, State: "Active", Tags: []string{"tool:mend"}, Description: "<pre>resolved</pre><p>piper-finding: CVE-3/pkg:npm/x@1</p>"},
+		{ID: 4, State: "Active", Tags: []string{"tool:mend"}, Description: "no fingerprint"},
+	}}
+	boards := &AzureBoards{Client: clientMock, OpenState: "Active"}
+	findings := []TrackedFinding{
+		{Detail: &scanReportlMock{title: "One", text: "text"}, Fingerprint: "CVE-1/pkg:npm/x@1", Severity: "High"},
+		{Detail: &scanReportlMock{title: "Two", text: "<b>new</b>"}, Fingerprint: "CVE-2/pkg:npm/x@1", Severity: "Low"},
+		{Detail: &scanReportlMock{title: "Four", text: "four"}, Fingerprint: "CVE-4/pkg:npm/x@1"},
+	}
+
+	err := SyncFindings(context.Background(), boards, "Mend", "", findings)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "tool:mend", clientMock.queriedTag)
+	assert.NotContains(t, clientMock.updated, 1)
+	assert.Equal(t, []map[string]interface{}{
+		{ado.FieldDescription: "<pre>&lt;b&gt;new&lt;/b&gt;</pre><p>piper-finding: CVE-2/pkg:npm/x@1</p>", ado.FieldTags: "tool:mend; severity:low"},
+		{ado.FieldState: "Active"},
+		{ado.FieldHistory: "The finding is reported again, re-opening the issue."},
+	}, clientMock.updated[2])
+	assert.Equal(t, []map[string]interface{}{
+		{ado.FieldHistory: "The finding is no longer reported by Mend, closing the issue."},
+		{ado.FieldState: "Closed"},
+	}, clientMock.updated[3])
+	assert.NotContains(t, clientMock.updated, 4)
+	assert.Equal(t, []map[string]interface{}{
+		{ado.FieldTitle: "Four", ado.FieldDescription: "<pre>four</pre><p>piper-finding: CVE-4/pkg:npm/x@1</p>", ado.FieldTags: "tool:mend"},
+	}, clientMock.created)
+}
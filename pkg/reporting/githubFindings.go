@@ -0,0 +1,123 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/google/go-github/v68/github"
+)
+
+const fingerprintMarker = "<!-- piper-finding: %v -->"
+
+// maxSearchResults is the maximum number of results the GitHub search API returns for one query
+const maxSearchResults = 1000
+
+var fingerprintPattern = regexp.MustCompile(`<!-- piper-finding: (\S+) -->`)
+
+// SyncFindings synchronizes the findings of a tool within a scope with GitHub issues, one issue per finding.
+func (g *GitHub) SyncFindings(ctx context.Context, tool, scope string, findings []TrackedFinding) error {
+	return SyncFindings(ctx, g, tool, scope, findings)
+}
+
+// FindIssues returns all issues of a tool, open and closed ones, by the fingerprint contained in their body
+func (g *GitHub) FindIssues(ctx context.Context, toolLabel string) (map[string]*TrackedIssue, error) {
+	issues := map[string]*TrackedIssue{}
+	query := fmt.Sprintf("is:issue repo:%v/%v label:\"%v\"", *g.Owner, *g.Repository, toolLabel)
+	opts := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		searchResult, resp, err := g.SearchService.Issues(ctx, query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error occurred when looking for existing issues: %w", err)
+		}
+		// resolved findings could not be closed reliably on an incomplete result
+		if searchResult.GetTotal() > maxSearchResults {
+			return nil, fmt.Errorf("found %v issues with label '%v' which exceeds the %v results returned by the GitHub search", searchResult.GetTotal(), toolLabel, maxSearchResults)
+		}
+		if searchResult.GetIncompleteResults() {
+			return nil, fmt.Errorf("GitHub search for issues with label '%v' returned incomplete results", toolLabel)
+		}
+		for _, issue := range searchResult.Issues {
+			match := fingerprintPattern.FindStringSubmatch(issue.GetBody())
+			if match == nil {
+				continue
+			}
+			// prefer open issues in case there are duplicates
+			if existing, ok := issues[match[1]]; ok && !existing.Closed {
+				continue
+			}
+			labels := []string{}
+			for _, label := range issue.Labels {
+				labels = append(labels, label.GetName())
+			}
+			issues[match[1]] = &TrackedIssue{
+				ID:          strconv.Itoa(issue.GetNumber()),
+				Fingerprint: match[1],
+				Title:       issue.GetTitle(),
+				Body:        issue.GetBody(),
+				Labels:      labels,
+				Closed:      issue.GetState() == "closed",
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return issues, nil
+}
+
+// IssueBody renders the markdown of the finding and adds the fingerprint as hidden comment
+func (g *GitHub) IssueBody(detail IssueDetail, fingerprint string) string {
+	markdown, _ := detail.ToMarkdown()
+	return fmt.Sprintf("%v\n\n"+fingerprintMarker, string(markdown), fingerprint)
+}
+
+// CreateIssue creates a GitHub issue for a finding
+func (g *GitHub) CreateIssue(ctx context.Context, issue *TrackedIssue) error {
+	request := github.IssueRequest{Title: &issue.Title, Body: &issue.Body, Labels: &issue.Labels, Assignees: g.Assignees}
+	created, _, err := g.IssueService.Create(ctx, *g.Owner, *g.Repository, &request)
+	if err != nil {
+		return err
+	}
+	issue.ID = strconv.Itoa(created.GetNumber())
+	return nil
+}
+
+// UpdateIssue updates body and labels of a GitHub issue
+func (g *GitHub) UpdateIssue(ctx context.Context, issue *TrackedIssue) error {
+	number, err := strconv.Atoi(issue.ID)
+	if err != nil {
+		return fmt.Errorf("invalid issue number '%v': %w", issue.ID, err)
+	}
+	_, _, err = g.IssueService.Edit(ctx, *g.Owner, *g.Repository, number, &github.IssueRequest{Body: &issue.Body, Labels: &issue.Labels})
+	return err
+}
+
+// SetIssueState closes or re-opens a GitHub issue
+func (g *GitHub) SetIssueState(ctx context.Context, issue *TrackedIssue, closed bool) error {
+	number, err := strconv.Atoi(issue.ID)
+	if err != nil {
+		return fmt.Errorf("invalid issue number '%v': %w", issue.ID, err)
+	}
+	state := "open"
+	if closed {
+		state = "closed"
+	}
+	if _, _, err := g.IssueService.Edit(ctx, *g.Owner, *g.Repository, number, &github.IssueRequest{State: &state}); err != nil {
+		return err
+	}
+	issue.Closed = closed
+	return nil
+}
+
+// CommentIssue adds a comment to a GitHub issue
+func (g *GitHub) CommentIssue(ctx context.Context, issue *TrackedIssue, comment string) error {
+	number, err := strconv.Atoi(issue.ID)
+	if err != nil {
+		return fmt.Errorf("invalid issue number '%v': %w", issue.ID, err)
+	}
+	_, _, err = g.IssueService.CreateComment(ctx, *g.Owner, *g.Repository, number, &github.IssueComment{Body: &comment})
+	return err
+}
@@ -0,0 +1,146 @@
+//go:build unit
+
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type ghFindingServicesMock struct {
+	searchResult []*github.Issue
+	searchQuery  string
+	searchTotal  int
+	created      []*github.IssueRequest
+	edited       map[int]*github.IssueRequest
+	comments     map[int]string
+}
+
+func (g *ghFindingServicesMock) Create(ctx context.Context, owner string, repo string, issueRequest *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	g.created = append(g.created, issueRequest)
+	return &github.Issue{}, &github.Response{}, nil
+}
+
+func (g *ghFindingServicesMock) CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	if g.comments == nil {
+		g.comments = map[int]string{}
+	}
+	g.comments[number] = comment.GetBody()
+	return comment, &github.Response{}, nil
+}
+
+func (g *ghFindingServicesMock) Edit(ctx context.Context, owner string, repo string, number int, issueRequest *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	if g.edited == nil {
+		g.edited = map[int]*github.IssueRequest{}
+	}
+	g.edited[number] = issueRequest
+	return &github.Issue{}, &github.Response{}, nil
+}
+
+func (g *ghFindingServicesMock) Issues(ctx context.Context, query string, opts *github.SearchOptions) (*github.IssuesSearchResult, *github.Response, error) {
+	g.searchQuery = query
+	total := g.searchTotal
+	if total == 0 {
+		total = len(g.searchResult)
+	}
+	return &github.IssuesSearchResult{Total: &total, Issues: g.searchResult}, &github.Response{}, nil
+}
+
+func trackedIssue(number int, state, fingerprint string, labels ...string) *github.Issue {
+	body := fmt.Sprintf("# Title\n\n<!-- piper-finding: %v -->", fingerprint)
+	ghLabels := []*github.Label{}
+	for _, label := range labels {
+		ghLabels = append(ghLabels, &github.Label{Name: github.Ptr(label)})
+	}
+	return &github.Issue{Number: &number, State: &state, Body: &body, Labels: ghLabels}
+}
+
+func TestSyncFindings(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("creates, updates and closes issues", func(t *testing.T) {
+		ghMock := ghFindingServicesMock{searchResult: []*github.Issue{
+			trackedIssue(1, "open", "CVE-1/pkg:maven/a/b@1", "tool:mend", "tool:mend/product-a", "severity:high"),
+			trackedIssue(2, "open", "CVE-2/pkg:maven/a/b@1", "tool:mend", "tool:mend/product-a", "severity:low", "team-x"),
+			trackedIssue(3, "closed", "CVE-3/pkg:maven/a/b@1", "tool:mend", "tool:mend/product-a", "severity:high"),
+			trackedIssue(4, "open", "CVE-4/pkg:maven/a/b@1", "tool:mend", "tool:mend/product-a", "severity:high"),
+			trackedIssue(5, "closed", "CVE-5/pkg:maven/a/b@1", "tool:mend", "tool:mend/product-a", "severity:high"),
+		}}
+		gh := GitHub{Owner: &owner, Repository: &repository, IssueService: &ghMock, SearchService: &ghMock}
+		findings := []TrackedFinding{
+			{Detail: &scanReportlMock{title: "Title", markdown: []byte("# Title")}, Fingerprint: "CVE-1/pkg:maven/a/b@1", Severity: "HIGH"},
+			{Detail: &scanReportlMock{title: "Title", markdown: []byte("# Title")}, Fingerprint: "CVE-2/pkg:maven/a/b@1", Severity: "LOW", Assessment: &format.Assessment{Status: format.NotRelevant, Analysis: format.NotPresent}},
+			{Detail: &scanReportlMock{title: "Title", markdown: []byte("# Title")}, Fingerprint: "CVE-3/pkg:maven/a/b@1", Severity: "HIGH"},
+			{Detail: &scanReportlMock{title: "New", markdown: []byte("# New")}, Fingerprint: "CVE-6/pkg:maven/a/b@1", Severity: "CRITICAL"},
+		}
+
+		err := gh.SyncFindings(ctx, "Mend", "Product A", findings)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "is:issue repo:testOwner/testRepository label:\"tool:mend/product-a\"", ghMock.searchQuery)
+		// unchanged finding
+		assert.NotContains(t, ghMock.edited, 1)
+		// assessed finding gets labeled, manual labels are kept
+		if assert.Contains(t, ghMock.edited, 2) {
+			assert.Equal(t, []string{"tool:mend", "tool:mend/product-a", "severity:low", "assessment:notRelevant", "analysis:notPresent", "team-x"}, *ghMock.edited[2].Labels)
+			assert.Nil(t, ghMock.edited[2].State)
+		}
+		// finding reported again
+		if assert.Contains(t, ghMock.edited, 3) {
+			assert.Equal(t, "open", ghMock.edited[3].GetState())
+			assert.Contains(t, ghMock.comments[3], "re-opening")
+		}
+		// resolved finding
+		if assert.Contains(t, ghMock.edited, 4) {
+			assert.Equal(t, "closed", ghMock.edited[4].GetState())
+			assert.Equal(t, "The finding is no longer reported by Mend, closing the issue.", ghMock.comments[4])
+		}
+		assert.NotContains(t, ghMock.edited, 5)
+		// new finding
+		if assert.Len(t, ghMock.created, 1) {
+			assert.Equal(t, "New", ghMock.created[0].GetTitle())
+			assert.Equal(t, "# New\n\n<!-- piper-finding: CVE-6/pkg:maven/a/b@1 -->", ghMock.created[0].GetBody())
+			assert.Equal(t, []string{"tool:mend", "tool:mend/product-a", "severity:critical"}, *ghMock.created[0].Labels)
+		}
+	})
+
+	t.Run("creates one issue per fingerprint", func(t *testing.T) {
+		ghMock := ghFindingServicesMock{}
+		gh := GitHub{Owner: &owner, Repository: &repository, IssueService: &ghMock, SearchService: &ghMock}
+		finding := TrackedFinding{Detail: &scanReportlMock{title: "Title"}, Fingerprint: "CVE-1/pkg:npm/a@1", Ignored: true}
+
+		err := gh.SyncFindings(ctx, "mend", "", []TrackedFinding{finding, finding})
+
+		assert.NoError(t, err)
+		if assert.Len(t, ghMock.created, 1) {
+			assert.Equal(t, []string{"tool:mend", "assessment:ignored"}, *ghMock.created[0].Labels)
+		}
+	})
+
+	t.Run("fails when the search limit is exceeded", func(t *testing.T) {
+		ghMock := ghFindingServicesMock{searchResult: []*github.Issue{trackedIssue(1, "open", "CVE-1/pkg:maven/a/b@1", "tool:mend")}, searchTotal: 1001}
+		gh := GitHub{Owner: &owner, Repository: &repository, IssueService: &ghMock, SearchService: &ghMock}
+
+		err := gh.SyncFindings(ctx, "Mend", "Product A", nil)
+
+		assert.EqualError(t, err, "found 1001 issues with label 'tool:mend/product-a' which exceeds the 1000 results returned by the GitHub search")
+		assert.Empty(t, ghMock.edited)
+	})
+}
+
+func TestScopeLabel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "tool:mend/product-a/project", ScopeLabel("Mend", "Product  A/Project"))
+	long := ScopeLabel("Mend", "A very long product name which exceeds the label limit")
+	assert.Len(t, long, 50)
+	assert.True(t, strings.HasPrefix(long, "tool:mend/a-very-long-product-name"))
+	assert.NotEqual(t, long, ScopeLabel("Mend", "A very long product name which exceeds the label limit, too"))
+}
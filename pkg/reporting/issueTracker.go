@@ -0,0 +1,183 @@
+package reporting
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+const maxLabelLength = 50
+
+// TrackedFinding is a single scan finding which is tracked by a dedicated issue
+type TrackedFinding struct {
+	// Detail provides title and content of the issue
+	Detail IssueDetail
+	// Fingerprint identifies the finding across scans, e.g. CVE and affected component
+	Fingerprint string
+	Severity    string
+	// Assessment is set in case the finding has been assessed, e.g. via an assessment file
+	Assessment *format.Assessment
+	// Ignored marks findings which have been ignored within the tool itself
+	Ignored bool
+}
+
+// TrackedIssue is the representation of a finding within an issue tracker
+type TrackedIssue struct {
+	// ID is the tracker specific identifier, e.g. the issue number, key or work item id
+	ID          string
+	Fingerprint string
+	Title       string
+	// Body is the tracker specific content of the issue including the fingerprint
+	Body   string
+	Labels []string
+	Closed bool
+}
+
+// IssueTracker abstracts the systems findings can be tracked in, like GitHub issues, Jira or Azure Boards
+type IssueTracker interface {
+	// FindIssues returns all issues of a tool, open and closed ones, by the fingerprint of their finding
+	FindIssues(ctx context.Context, toolLabel string) (map[string]*TrackedIssue, error)
+	// IssueBody renders the content of an issue in the format of the tracker including the fingerprint
+	IssueBody(detail IssueDetail, fingerprint string) string
+	CreateIssue(ctx context.Context, issue *TrackedIssue) error
+	// UpdateIssue updates content and labels of an issue
+	UpdateIssue(ctx context.Context, issue *TrackedIssue) error
+	SetIssueState(ctx context.Context, issue *TrackedIssue, closed bool) error
+	CommentIssue(ctx context.Context, issue *TrackedIssue, comment string) error
+}
+
+// SyncFindings synchronizes the findings of a tool with an issue tracker, one issue per finding.
+// Issues are created for new findings, updated for known ones and closed with a comment for findings
+// which are no longer reported. Issues are labeled with the tool, the severity and a potential assessment.
+// The scope, e.g. the scanned product, separates the issues of several scans reporting into the same tracker:
+// only issues labeled with the scope are updated or closed.
+func SyncFindings(ctx context.Context, tracker IssueTracker, tool, scope string, findings []TrackedFinding) error {
+	toolLabels := []string{"tool:" + strings.ToLower(tool)}
+	if len(scope) > 0 {
+		toolLabels = append(toolLabels, ScopeLabel(tool, scope))
+	}
+	existingIssues, err := tracker.FindIssues(ctx, toolLabels[len(toolLabels)-1])
+	if err != nil {
+		return err
+	}
+
+	reported := map[string]bool{}
+	for _, finding := range findings {
+		if reported[finding.Fingerprint] {
+			continue
+		}
+		reported[finding.Fingerprint] = true
+		if err := syncFinding(ctx, tracker, toolLabels, finding, existingIssues[finding.Fingerprint]); err != nil {
+			return err
+		}
+	}
+
+	fingerprints := make([]string, 0, len(existingIssues))
+	for fingerprint := range existingIssues {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+	for _, fingerprint := range fingerprints {
+		issue := existingIssues[fingerprint]
+		if reported[fingerprint] || issue.Closed {
+			continue
+		}
+		log.Entry().Debugf("Closing issue %v for resolved finding %v", issue.ID, fingerprint)
+		if err := tracker.CommentIssue(ctx, issue, fmt.Sprintf("The finding is no longer reported by %v, closing the issue.", tool)); err != nil {
+			return fmt.Errorf("failed to comment on issue %v: %w", issue.ID, err)
+		}
+		if err := tracker.SetIssueState(ctx, issue, true); err != nil {
+			return fmt.Errorf("failed to close issue %v: %w", issue.ID, err)
+		}
+	}
+	return nil
+}
+
+// ScopeLabel returns the label of the findings of a tool within a scope, e.g. "tool:mend/my-product".
+// Labels are limited to 50 characters on GitHub, longer scopes are shortened and kept unique with a hash.
+func ScopeLabel(tool, scope string) string {
+	label := "tool:" + strings.ToLower(tool) + "/" + strings.Join(strings.Fields(strings.ToLower(scope)), "-")
+	if len(label) <= maxLabelLength {
+		return label
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(label)))[:8]
+	return label[:maxLabelLength-len(hash)-1] + "-" + hash
+}
+
+func syncFinding(ctx context.Context, tracker IssueTracker, toolLabels []string, finding TrackedFinding, existing *TrackedIssue) error {
+	issue := &TrackedIssue{
+		Fingerprint: finding.Fingerprint,
+		Title:       finding.Detail.Title(),
+		Body:        tracker.IssueBody(finding.Detail, finding.Fingerprint),
+		Labels:      findingLabels(toolLabels, finding),
+	}
+
+	if existing == nil {
+		log.Entry().Debugf("Creating issue for finding %v", finding.Fingerprint)
+		if err := tracker.CreateIssue(ctx, issue); err != nil {
+			return fmt.Errorf("failed to create issue for finding '%v': %w", finding.Fingerprint, err)
+		}
+		return nil
+	}
+
+	issue.ID = existing.ID
+	issue.Closed = existing.Closed
+	// keep labels which have been added manually
+	for _, label := range existing.Labels {
+		if !isFindingLabel(label) {
+			issue.Labels = append(issue.Labels, label)
+		}
+	}
+	if existing.Body != issue.Body || !sameLabels(existing.Labels, issue.Labels) {
+		if err := tracker.UpdateIssue(ctx, issue); err != nil {
+			return fmt.Errorf("failed to update issue %v for finding '%v': %w", issue.ID, finding.Fingerprint, err)
+		}
+	}
+	if existing.Closed {
+		if err := tracker.SetIssueState(ctx, issue, false); err != nil {
+			return fmt.Errorf("failed to re-open issue %v for finding '%v': %w", issue.ID, finding.Fingerprint, err)
+		}
+		if err := tracker.CommentIssue(ctx, issue, "The finding is reported again, re-opening the issue."); err != nil {
+			return fmt.Errorf("failed to comment on issue %v: %w", issue.ID, err)
+		}
+	}
+	return nil
+}
+
+func findingLabels(toolLabels []string, finding TrackedFinding) []string {
+	labels := slices.Clone(toolLabels)
+	if len(finding.Severity) > 0 {
+		labels = append(labels, "severity:"+strings.ToLower(finding.Severity))
+	}
+	if finding.Assessment != nil {
+		labels = append(labels, "assessment:"+string(finding.Assessment.Status))
+		if len(finding.Assessment.Analysis) > 0 {
+			labels = append(labels, "analysis:"+string(finding.Assessment.Analysis))
+		}
+	} else if finding.Ignored {
+		labels = append(labels, "assessment:ignored")
+	}
+	return labels
+}
+
+func isFindingLabel(label string) bool {
+	for _, prefix := range []string{"tool:", "severity:", "assessment:", "analysis:"} {
+		if strings.HasPrefix(label, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameLabels(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	sort.Strings(a)
+	sort.Strings(b)
+	return slices.Equal(a, b)
+}
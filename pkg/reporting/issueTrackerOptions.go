@@ -0,0 +1,100 @@
+package reporting
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/ado"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+)
+
+// Issue trackers findings can be synchronized with
+const (
+	IssueTrackerGitHub      = "github"
+	IssueTrackerJira        = "jira"
+	IssueTrackerAzureBoards = "azureBoards"
+)
+
+// IssueTrackerOptions are the settings of the issue tracker scan steps synchronize their findings with.
+// Steps map their parameters to the options and create the tracker via NewIssueTracker.
+// Currently only whitesourceExecuteScan synchronizes its findings (parameters `syncResultIssues` and `issueTracker`),
+// detectExecuteScan, checkmarxExecuteScan, checkmarxOneExecuteScan and fortifyExecuteScan create GitHub issues only.
+type IssueTrackerOptions struct {
+	// IssueTracker is one of github, jira or azureBoards, GitHub is used if empty
+	IssueTracker string
+
+	GithubToken   string
+	GithubAPIURL  string
+	Owner         string
+	Repository    string
+	Assignees     []string
+	IssueService  githubIssueService
+	SearchService githubSearchService
+
+	JiraURL        string
+	JiraProjectKey string
+	JiraIssueType  string
+	JiraAPIVersion string
+	// JiraUsername selects basic authentication with the token as password, otherwise the token is sent as bearer token
+	JiraUsername string
+	JiraToken    string
+
+	AzureBoardsOrganization string
+	AzureBoardsProject      string
+	AzureBoardsToken        string
+	AzureBoardsWorkItemType string
+	AzureBoardsOpenState    string
+	AzureBoardsClosedState  string
+}
+
+// Configured checks whether all settings required by the selected issue tracker are provided
+func (o *IssueTrackerOptions) Configured() bool {
+	switch o.IssueTracker {
+	case IssueTrackerJira:
+		return len(o.JiraURL) > 0 && len(o.JiraProjectKey) > 0 && len(o.JiraToken) > 0
+	case IssueTrackerAzureBoards:
+		return len(o.AzureBoardsOrganization) > 0 && len(o.AzureBoardsProject) > 0 && len(o.AzureBoardsToken) > 0
+	default:
+		return len(o.GithubToken) > 0 && len(o.GithubAPIURL) > 0 && len(o.Owner) > 0 && len(o.Repository) > 0
+	}
+}
+
+// NewIssueTracker creates the issue tracker selected in the options
+func NewIssueTracker(o *IssueTrackerOptions) (IssueTracker, error) {
+	switch o.IssueTracker {
+	case IssueTrackerJira:
+		client := &piperhttp.Client{}
+		if len(o.JiraUsername) > 0 {
+			client.SetOptions(piperhttp.ClientOptions{Username: o.JiraUsername, Password: o.JiraToken})
+		} else {
+			client.SetOptions(piperhttp.ClientOptions{Token: "Bearer " + o.JiraToken})
+		}
+		return &Jira{
+			ServerURL:  o.JiraURL,
+			ProjectKey: o.JiraProjectKey,
+			IssueType:  o.JiraIssueType,
+			APIVersion: o.JiraAPIVersion,
+			Client:     client,
+		}, nil
+	case IssueTrackerAzureBoards:
+		client, err := ado.NewWorkItemClient(o.AzureBoardsOrganization, o.AzureBoardsToken, o.AzureBoardsProject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Azure Boards client: %w", err)
+		}
+		return &AzureBoards{
+			Client:       client,
+			WorkItemType: o.AzureBoardsWorkItemType,
+			OpenState:    o.AzureBoardsOpenState,
+			ClosedState:  o.AzureBoardsClosedState,
+		}, nil
+	case IssueTrackerGitHub, "":
+		return &GitHub{
+			Owner:         &o.Owner,
+			Repository:    &o.Repository,
+			Assignees:     &o.Assignees,
+			IssueService:  o.IssueService,
+			SearchService: o.SearchService,
+		}, nil
+	default:
+		return nil, fmt.Errorf("issue tracker '%v' not supported", o.IssueTracker)
+	}
+}
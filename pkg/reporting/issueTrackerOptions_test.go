@@ -0,0 +1,47 @@
+//go:build unit
+
+package reporting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIssueTracker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("jira", func(t *testing.T) {
+		options := &IssueTrackerOptions{IssueTracker: IssueTrackerJira, JiraURL: "https://jira.example.com", JiraProjectKey: "SEC", JiraToken: "token", JiraAPIVersion: "3"}
+		assert.True(t, options.Configured())
+
+		tracker, err := NewIssueTracker(options)
+
+		assert.NoError(t, err)
+		if assert.IsType(t, &Jira{}, tracker) {
+			assert.Equal(t, "SEC", tracker.(*Jira).ProjectKey)
+			assert.Equal(t, "3", tracker.(*Jira).APIVersion)
+		}
+	})
+
+	t.Run("azure boards not configured", func(t *testing.T) {
+		options := &IssueTrackerOptions{IssueTracker: IssueTrackerAzureBoards, AzureBoardsOrganization: "org", AzureBoardsProject: "project", GithubToken: "token"}
+		assert.False(t, options.Configured())
+	})
+
+	t.Run("github", func(t *testing.T) {
+		options := &IssueTrackerOptions{IssueTracker: IssueTrackerGitHub, GithubToken: "token", GithubAPIURL: "https://api.github.com", Owner: "owner", Repository: "repo"}
+		assert.True(t, options.Configured())
+
+		tracker, err := NewIssueTracker(options)
+
+		assert.NoError(t, err)
+		assert.IsType(t, &GitHub{}, tracker)
+	})
+
+	t.Run("unsupported tracker", func(t *testing.T) {
+		_, err := NewIssueTracker(&IssueTrackerOptions{IssueTracker: "bugzilla"})
+
+		assert.EqualError(t, err, "issue tracker 'bugzilla' not supported")
+	})
+}
@@ -0,0 +1,305 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+)
+
+var textFingerprintPattern = regexp.MustCompile(`piper-finding: (\S+)`)
+
+const jiraSearchPageSize = 100
+
+// Jira tracks findings as Jira issues using the Jira REST API
+type Jira struct {
+	ServerURL  string
+	ProjectKey string
+	IssueType  string
+	// APIVersion is the version of the REST API, version 2 for Jira Server and Data Center and version 3 for Jira Cloud.
+	// Version 3 uses the Atlassian Document Format for rich text fields and the enhanced search.
+	APIVersion string
+	// Client needs to be configured with the credentials for the Jira server
+	Client piperhttp.Sender
+}
+
+type jiraIssue struct {
+	Key    string          `json:"key,omitempty"`
+	Fields jiraIssueFields `json:"fields"`
+}
+
+type jiraIssueFields struct {
+	Project     *jiraKey        `json:"project,omitempty"`
+	IssueType   *jiraName       `json:"issuetype,omitempty"`
+	Summary     string          `json:"summary,omitempty"`
+	Description json.RawMessage `json:"description,omitempty"`
+	Labels      []string        `json:"labels"`
+	Status      *jiraStatus     `json:"status,omitempty"`
+}
+
+type jiraKey struct {
+	Key string `json:"key"`
+}
+
+type jiraName struct {
+	Name string `json:"name"`
+}
+
+type jiraStatus struct {
+	StatusCategory jiraKey `json:"statusCategory"`
+}
+
+type jiraSearchResult struct {
+	// StartAt and Total are used by the search of Jira Server and Data Center
+	StartAt int `json:"startAt"`
+	Total   int `json:"total"`
+	// NextPageToken is used by the enhanced search of Jira Cloud and is empty on the last page
+	NextPageToken string      `json:"nextPageToken"`
+	Issues        []jiraIssue `json:"issues"`
+}
+
+type jiraTransitions struct {
+	Transitions []struct {
+		ID string `json:"id"`
+		To struct {
+			StatusCategory jiraKey `json:"statusCategory"`
+		} `json:"to"`
+	} `json:"transitions"`
+}
+
+// adfNode is a node of the Atlassian Document Format
+type adfNode struct {
+	Type    string    `json:"type"`
+	Version int       `json:"version,omitempty"`
+	Text    string    `json:"text,omitempty"`
+	Content []adfNode `json:"content,omitempty"`
+}
+
+// FindIssues returns all issues of a tool within the project by the fingerprint contained in their description
+func (j *Jira) FindIssues(ctx context.Context, toolLabel string) (map[string]*TrackedIssue, error) {
+	issues := map[string]*TrackedIssue{}
+	jql := fmt.Sprintf("project = \"%v\" AND labels = \"%v\" ORDER BY created ASC", j.ProjectKey, toolLabel)
+	startAt, nextPageToken := 0, ""
+	for {
+		params := url.Values{}
+		params.Add("jql", jql)
+		params.Add("fields", "summary,description,labels,status")
+		params.Add("maxResults", strconv.Itoa(jiraSearchPageSize))
+		// Jira Cloud only provides the enhanced search which pages with a token,
+		// Jira Server and Data Center only the search paging with an offset
+		endpoint := "/search"
+		if j.cloud() {
+			endpoint = "/search/jql"
+			if len(nextPageToken) > 0 {
+				params.Add("nextPageToken", nextPageToken)
+			}
+		} else {
+			params.Add("startAt", strconv.Itoa(startAt))
+		}
+
+		var result jiraSearchResult
+		if err := j.send(http.MethodGet, endpoint+"?"+params.Encode(), nil, &result); err != nil {
+			return nil, fmt.Errorf("error occurred when looking for existing issues: %w", err)
+		}
+		for _, issue := range result.Issues {
+			body := j.descriptionText(issue.Fields.Description)
+			match := textFingerprintPattern.FindStringSubmatch(body)
+			if match == nil {
+				continue
+			}
+			closed := issue.Fields.Status != nil && issue.Fields.Status.StatusCategory.Key == "done"
+			// prefer open issues in case there are duplicates
+			if existing, ok := issues[match[1]]; ok && !existing.Closed {
+				continue
+			}
+			labels := issue.Fields.Labels
+			if labels == nil {
+				labels = []string{}
+			}
+			issues[match[1]] = &TrackedIssue{
+				ID:          issue.Key,
+				Fingerprint: match[1],
+				Title:       issue.Fields.Summary,
+				Body:        body,
+				Labels:      labels,
+				Closed:      closed,
+			}
+		}
+		if j.cloud() {
+			nextPageToken = result.NextPageToken
+			if len(nextPageToken) == 0 {
+				break
+			}
+			continue
+		}
+		startAt += len(result.Issues)
+		if len(result.Issues) == 0 || startAt >= result.Total {
+			break
+		}
+	}
+	return issues, nil
+}
+
+// IssueBody renders the text of the finding and adds the fingerprint
+func (j *Jira) IssueBody(detail IssueDetail, fingerprint string) string {
+	return fmt.Sprintf("%v\n\npiper-finding: %v", strings.TrimSpace(detail.ToTxt()), fingerprint)
+}
+
+// CreateIssue creates a Jira issue for a finding
+func (j *Jira) CreateIssue(ctx context.Context, issue *TrackedIssue) error {
+	issueType := j.IssueType
+	if len(issueType) == 0 {
+		issueType = "Bug"
+	}
+	request := jiraIssue{Fields: jiraIssueFields{
+		Project:     &jiraKey{Key: j.ProjectKey},
+		IssueType:   &jiraName{Name: issueType},
+		Summary:     issue.Title,
+		Description: j.richText(issue.Body),
+		Labels:      issue.Labels,
+	}}
+	var created jiraIssue
+	if err := j.send(http.MethodPost, "/issue", request, &created); err != nil {
+		return err
+	}
+	issue.ID = created.Key
+	return nil
+}
+
+// UpdateIssue updates description and labels of a Jira issue
+func (j *Jira) UpdateIssue(ctx context.Context, issue *TrackedIssue) error {
+	request := jiraIssue{Fields: jiraIssueFields{
+		Description: j.richText(issue.Body),
+		Labels:      issue.Labels,
+	}}
+	return j.send(http.MethodPut, "/issue/"+url.PathEscape(issue.ID), request, nil)
+}
+
+// SetIssueState transitions a Jira issue into a status of category done or back into an open one
+func (j *Jira) SetIssueState(ctx context.Context, issue *TrackedIssue, closed bool) error {
+	var transitions jiraTransitions
+	if err := j.send(http.MethodGet, "/issue/"+url.PathEscape(issue.ID)+"/transitions", nil, &transitions); err != nil {
+		return err
+	}
+	// re-opening prefers a status of category "To Do" over one of category "In Progress"
+	targetCategories := []string{"new", "indeterminate"}
+	if closed {
+		targetCategories = []string{"done"}
+	}
+	for _, category := range targetCategories {
+		for _, transition := range transitions.Transitions {
+			if transition.To.StatusCategory.Key != category {
+				continue
+			}
+			request := map[string]interface{}{"transition": map[string]string{"id": transition.ID}}
+			if err := j.send(http.MethodPost, "/issue/"+url.PathEscape(issue.ID)+"/transitions", request, nil); err != nil {
+				return err
+			}
+			issue.Closed = closed
+			return nil
+		}
+	}
+	return fmt.Errorf("no transition available to change the status of issue %v", issue.ID)
+}
+
+// CommentIssue adds a comment to a Jira issue
+func (j *Jira) CommentIssue(ctx context.Context, issue *TrackedIssue, comment string) error {
+	request := map[string]json.RawMessage{"body": j.richText(comment)}
+	return j.send(http.MethodPost, "/issue/"+url.PathEscape(issue.ID)+"/comment", request, nil)
+}
+
+func (j *Jira) apiVersion() string {
+	if len(j.APIVersion) == 0 {
+		return "2"
+	}
+	return j.APIVersion
+}
+
+// cloud checks whether the API of Jira Cloud is used, Jira Server and Data Center only provide version 2
+func (j *Jira) cloud() bool {
+	return j.apiVersion() != "2"
+}
+
+// richText converts plain text into the format of rich text fields, one paragraph per line for the Atlassian Document Format
+func (j *Jira) richText(text string) json.RawMessage {
+	var value interface{} = text
+	if j.cloud() {
+		document := adfNode{Type: "doc", Version: 1, Content: []adfNode{}}
+		for _, line := range strings.Split(text, "\n") {
+			paragraph := adfNode{Type: "paragraph"}
+			if len(line) > 0 {
+				paragraph.Content = []adfNode{{Type: "text", Text: line}}
+			}
+			document.Content = append(document.Content, paragraph)
+		}
+		value = document
+	}
+	raw, _ := json.Marshal(value)
+	return raw
+}
+
+// descriptionText converts a rich text field back into plain text
+func (j *Jira) descriptionText(description json.RawMessage) string {
+	if len(description) == 0 || string(description) == "null" {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(description, &text); err == nil {
+		return text
+	}
+	var document adfNode
+	if err := json.Unmarshal(description, &document); err != nil {
+		return ""
+	}
+	lines := []string{}
+	for _, block := range document.Content {
+		lines = append(lines, adfText(block))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func adfText(node adfNode) string {
+	text := node.Text
+	for _, child := range node.Content {
+		text += adfText(child)
+	}
+	return text
+}
+
+func (j *Jira) send(method, endpoint string, request interface{}, response interface{}) error {
+	var body io.Reader
+	header := http.Header{"Accept": []string{"application/json"}}
+	if request != nil {
+		payload, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("failed to marshal Jira request: %w", err)
+		}
+		body = bytes.NewReader(payload)
+		header.Set("Content-Type", "application/json")
+	}
+	apiURL := fmt.Sprintf("%v/rest/api/%v%v", strings.TrimSuffix(j.ServerURL, "/"), j.apiVersion(), endpoint)
+	resp, err := j.Client.SendRequest(method, apiURL, body, header, nil)
+	if err != nil {
+		return fmt.Errorf("request to Jira API failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if response == nil {
+		return nil
+	}
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading Jira response failed: %w", err)
+	}
+	if err := json.Unmarshal(responseBody, response); err != nil {
+		return fmt.Errorf("failed to unmarshal Jira response: %w", err)
+	}
+	return nil
+}
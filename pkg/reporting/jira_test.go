@@ -0,0 +1,115 @@
+//go:build unit
+
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type jiraRequest struct {
+	method string
+	path   string
+	body   string
+}
+
+func newJiraServer(t *testing.T, searchResponse string, requests *[]jiraRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*requests = append(*requests, jiraRequest{method: r.Method, path: r.URL.Path, body: string(body)})
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/search":
+			assert.Equal(t, `project = "SEC" AND labels = "tool:mend" ORDER BY created ASC`, r.URL.Query().Get("jql"))
+			w.Write([]byte(searchResponse))
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/SEC-2/transitions":
+			w.Write([]byte(`{"transitions":[{"id":"11","to":{"statusCategory":{"key":"indeterminate"}}},{"id":"21","to":{"statusCategory":{"key":"done"}}}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"key":"SEC-3"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestJiraSyncFindings(t *testing.T) {
+	t.Parallel()
+	var requests []jiraRequest
+	searchResponse := `{"startAt":0,"total":2,"issues":[
+		{"key":"SEC-1","fields":{"summary":"Old","description":"text\n\npiper-finding: CVE-1/pkg:maven/a/b@1","labels":["tool:mend","severity:high"],"status":{"statusCategory":{"key":"new"}}}},
+		{"key":"SEC-2","fields":{"summary":"Resolved","description":"text\n\npiper-finding: CVE-2/pkg:maven/a/b@1","labels":["tool:mend"],"status":{"statusCategory":{"key":"indeterminate"}}}}
+	]}`
+	server := newJiraServer(t, searchResponse, &requests)
+	defer server.Close()
+
+	jira := &Jira{ServerURL: server.URL + "/", ProjectKey: "SEC", Client: &piperhttp.Client{}}
+	findings := []TrackedFinding{
+		{Detail: &scanReportlMock{title: "Old", text: "text\n"}, Fingerprint: "CVE-1/pkg:maven/a/b@1", Severity: "High"},
+		{Detail: &scanReportlMock{title: "New", text: "new text"}, Fingerprint: "CVE-3/pkg:maven/a/b@1", Severity: "Low"},
+	}
+
+	err := SyncFindings(context.Background(), jira, "Mend", "", findings)
+
+	assert.NoError(t, err)
+	// SEC-1 is up to date, SEC-2 gets commented and closed, a new issue is created
+	assert.Len(t, requests, 5)
+	assert.Equal(t, jiraRequest{method: http.MethodPost, path: "/rest/api/2/issue", body: `{"fields":{"project":{"key":"SEC"},"issuetype":{"name":"Bug"},"summary":"New","description":"new text\n\npiper-finding: CVE-3/pkg:maven/a/b@1","labels":["tool:mend","severity:low"]}}`}, requests[1])
+	assert.Equal(t, jiraRequest{method: http.MethodPost, path: "/rest/api/2/issue/SEC-2/comment", body: `{"body":"The finding is no longer reported by Mend, closing the issue."}`}, requests[2])
+	assert.Equal(t, http.MethodGet, requests[3].method)
+	assert.Equal(t, jiraRequest{method: http.MethodPost, path: "/rest/api/2/issue/SEC-2/transitions", body: `{"transition":{"id":"21"}}`}, requests[4])
+}
+
+func TestJiraFindIssuesCloud(t *testing.T) {
+	t.Parallel()
+	var pageTokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/3/search/jql", r.URL.Path)
+		assert.Empty(t, r.URL.Query().Get("startAt"))
+		pageTokens = append(pageTokens, r.URL.Query().Get("nextPageToken"))
+		if r.URL.Query().Get("nextPageToken") == "" {
+			w.Write([]byte(`{"nextPageToken":"page-2","issues":[{"key":"SEC-1","fields":{"summary":"One","description":{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"piper-finding: CVE-1/pkg:npm/a@1"}]}]},"labels":["tool:mend"]}}]}`))
+			return
+		}
+		w.Write([]byte(`{"isLast":true,"issues":[{"key":"SEC-2","fields":{"summary":"Two","description":{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"piper-finding: CVE-2/pkg:npm/a@1"}]}]},"labels":["tool:mend"],"status":{"statusCategory":{"key":"done"}}}}]}`))
+	}))
+	defer server.Close()
+	jira := &Jira{ServerURL: server.URL, ProjectKey: "SEC", APIVersion: "3", Client: &piperhttp.Client{}}
+
+	issues, err := jira.FindIssues(context.Background(), "tool:mend")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "page-2"}, pageTokens)
+	if assert.Len(t, issues, 2) {
+		assert.Equal(t, "SEC-1", issues["CVE-1/pkg:npm/a@1"].ID)
+		assert.True(t, issues["CVE-2/pkg:npm/a@1"].Closed)
+	}
+}
+
+func TestJiraRichText(t *testing.T) {
+	t.Parallel()
+
+	t.Run("API version 2", func(t *testing.T) {
+		jira := &Jira{}
+		assert.Equal(t, `"line 1\n\nline 2"`, string(jira.richText("line 1\n\nline 2")))
+		assert.Equal(t, "line 1\n\nline 2", jira.descriptionText(json.RawMessage(`"line 1\n\nline 2"`)))
+	})
+
+	t.Run("API version 3", func(t *testing.T) {
+		jira := &Jira{APIVersion: "3"}
+		document := jira.richText("line 1\n\nline 2")
+		assert.Equal(t, `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"line 1"}]},{"type":"paragraph"},{"type":"paragraph","content":[{"type":"text","text":"line 2"}]}]}`, string(document))
+		assert.Equal(t, "line 1\n\nline 2", jira.descriptionText(document))
+	})
+
+	t.Run("empty description", func(t *testing.T) {
+		jira := &Jira{APIVersion: "3"}
+		assert.Equal(t, "", jira.descriptionText(json.RawMessage(`null`)))
+	})
+}
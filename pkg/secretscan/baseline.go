@@ -0,0 +1,79 @@
+package secretscan
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Baseline contains known findings which have been reviewed and are not reported anymore
+type Baseline struct {
+	Findings []BaselineEntry `json:"findings"`
+
+	fingerprints map[string]bool
+}
+
+// BaselineEntry describes a known finding, only the fingerprint is relevant for matching
+type BaselineEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	RuleID      string `json:"ruleId,omitempty"`
+	File        string `json:"file,omitempty"`
+	Line        int    `json:"line,omitempty"`
+}
+
+// ParseBaseline parses the content of a baseline file
+func ParseBaseline(content []byte) (*Baseline, error) {
+	baseline := &Baseline{}
+	if err := json.Unmarshal(content, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline: %w", err)
+	}
+	return baseline, nil
+}
+
+// NewBaseline creates a baseline containing the given findings
+func NewBaseline(findings []Finding) *Baseline {
+	baseline := &Baseline{Findings: []BaselineEntry{}}
+	known := map[string]bool{}
+	for _, finding := range findings {
+		if known[finding.Fingerprint] {
+			continue
+		}
+		known[finding.Fingerprint] = true
+		baseline.Findings = append(baseline.Findings, BaselineEntry{
+			Fingerprint: finding.Fingerprint,
+			RuleID:      finding.RuleID,
+			File:        finding.File,
+			Line:        finding.Line,
+		})
+	}
+	sort.Slice(baseline.Findings, func(i, j int) bool {
+		a, b := baseline.Findings[i], baseline.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Fingerprint < b.Fingerprint
+	})
+	return baseline
+}
+
+// Contains checks whether a finding is part of the baseline
+func (b *Baseline) Contains(fingerprint string) bool {
+	if b == nil {
+		return false
+	}
+	if b.fingerprints == nil {
+		b.fingerprints = map[string]bool{}
+		for _, entry := range b.Findings {
+			b.fingerprints[entry.Fingerprint] = true
+		}
+	}
+	return b.fingerprints[fingerprint]
+}
+
+// ToJSON renders the baseline for storing it in the repository
+func (b *Baseline) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(b, "", "  ")
+}
@@ -0,0 +1,109 @@
+package secretscan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ScanCommits scans the lines added by each of the commits. Merge commits are skipped since their
+// changes are already covered by the merged commits.
+func (s *Scanner) ScanCommits(commits object.CommitIter) ([]Finding, error) {
+	findings := []Finding{}
+	scanned := 0
+	err := commits.ForEach(func(commit *object.Commit) error {
+		if commit.NumParents() > 1 {
+			return nil
+		}
+		commitFindings, err := s.scanCommit(commit)
+		if err != nil {
+			return err
+		}
+		scanned++
+		findings = append(findings, commitFindings...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Entry().Debugf("Scanned %v commit(s)", scanned)
+	return findings, nil
+}
+
+func (s *Scanner) scanCommit(commit *object.Commit) ([]Finding, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of commit %v: %w", commit.Hash, err)
+	}
+	var parentTree *object.Tree
+	if commit.NumParents() == 1 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent of commit %v: %w", commit.Hash, err)
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, fmt.Errorf("failed to get tree of commit %v: %w", parent.Hash, err)
+		}
+	}
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff commit %v: %w", commit.Hash, err)
+	}
+	patch, err := changes.Patch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create patch of commit %v: %w", commit.Hash, err)
+	}
+
+	findings := []Finding{}
+	for _, filePatch := range patch.FilePatches() {
+		_, to := filePatch.Files()
+		if filePatch.IsBinary() || to == nil || s.isExcluded(to.Path()) {
+			continue
+		}
+		for _, finding := range s.scanAddedLines(to.Path(), filePatch.Chunks()) {
+			finding.Commit = commit.Hash.String()
+			finding.Author = commit.Author.Email
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+// scanAddedLines scans the added lines of a file patch keeping track of their line number in the new file
+func (s *Scanner) scanAddedLines(path string, chunks []diff.Chunk) []Finding {
+	findings := []Finding{}
+	lineNumber := 1
+	for _, chunk := range chunks {
+		lines := strings.SplitAfter(chunk.Content(), "\n")
+		if len(lines[len(lines)-1]) == 0 {
+			lines = lines[:len(lines)-1]
+		}
+		switch chunk.Type() {
+		case diff.Equal:
+			lineNumber += len(lines)
+		case diff.Add:
+			for _, line := range lines {
+				findings = append(findings, s.ScanLine(path, lineNumber, strings.TrimSuffix(line, "\n"))...)
+				lineNumber++
+			}
+		}
+	}
+	return findings
+}
+
+// Deduplicate removes findings with the same fingerprint keeping the first occurrence
+func Deduplicate(findings []Finding) []Finding {
+	known := map[string]bool{}
+	unique := []Finding{}
+	for _, finding := range findings {
+		if known[finding.Fingerprint] {
+			continue
+		}
+		known[finding.Fingerprint] = true
+		unique = append(unique, finding)
+	}
+	return unique
+}
@@ -0,0 +1,65 @@
+//go:build unit
+
+package secretscan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func commitFile(t *testing.T, repo *git.Repository, path, content string) {
+	worktree, err := repo.Worktree()
+	assert.NoError(t, err)
+	file, err := worktree.Filesystem.Create(path)
+	assert.NoError(t, err)
+	_, err = file.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+	_, err = worktree.Add(path)
+	assert.NoError(t, err)
+	_, err = worktree.Commit("update "+path, &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	assert.NoError(t, err)
+}
+
+func TestScanCommits(t *testing.T) {
+	t.Parallel()
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	assert.NoError(t, err)
+
+	commitFile(t, repo, "config.properties", "aws.key="+testAWSKey+"\n")
+	// the secret is removed again but remains in the history
+	commitFile(t, repo, "config.properties", "aws.key=\n")
+	commitFile(t, repo, "ci.sh", "#!/bin/sh\necho start\n\nexport GH_TOKEN="+testGitHubToken+"\n")
+
+	scanner := newTestScanner(t, Options{})
+	commits, err := repo.Log(&git.LogOptions{})
+	assert.NoError(t, err)
+
+	findings, err := scanner.ScanCommits(commits)
+
+	assert.NoError(t, err)
+	if assert.Len(t, findings, 2) {
+		assert.Equal(t, "github-token", findings[0].RuleID)
+		assert.Equal(t, "ci.sh", findings[0].File)
+		assert.Equal(t, 4, findings[0].Line)
+		assert.Equal(t, "test@example.com", findings[0].Author)
+		assert.Len(t, findings[0].Commit, 40)
+		assert.Equal(t, "aws-access-key-id", findings[1].RuleID)
+		assert.Equal(t, "config.properties", findings[1].File)
+		assert.Equal(t, 1, findings[1].Line)
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	t.Parallel()
+	findings := Deduplicate([]Finding{{Fingerprint: "a", Line: 1}, {Fingerprint: "b"}, {Fingerprint: "a", Line: 2}})
+	assert.Equal(t, []Finding{{Fingerprint: "a", Line: 1}, {Fingerprint: "b"}}, findings)
+}
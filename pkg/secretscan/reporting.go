@@ -0,0 +1,126 @@
+package secretscan
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// ReportsDirectory defines the subfolder for the secret scan reports which are generated
+const ReportsDirectory = "secretscan"
+
+// CreateSarif creates a SARIF result from the findings
+func CreateSarif(findings []Finding, rules []Rule) *format.SARIF {
+	sarif := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+	}
+	run := format.Runs{
+		Results: []format.Results{},
+		Tool: format.Tool{Driver: format.Driver{
+			Name:           "Piper secret scan",
+			InformationUri: "https://www.project-piper.io/steps/secretScan/",
+			Rules:          []format.SarifRule{},
+		}},
+		ThreadFlowLocations: []format.Locations{},
+	}
+
+	ruleIndices := map[string]int{}
+	for _, rule := range rules {
+		ruleIndices[rule.ID] = len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, format.SarifRule{
+			ID:                   rule.ID,
+			Name:                 rule.ID,
+			ShortDescription:     &format.Message{Text: rule.Description},
+			FullDescription:      &format.Message{Text: rule.Description},
+			DefaultConfiguration: &format.DefaultConfiguration{Level: sarifLevel(rule.Severity)},
+			Help:                 &format.Help{Text: "Remove the secret from the source code, rotate it and provide it via a secret store instead."},
+			Properties:           &format.SarifRuleProperties{Tags: []string{"security", "secret"}, SecuritySeverity: securitySeverity(rule.Severity)},
+		})
+	}
+
+	for _, finding := range findings {
+		message := fmt.Sprintf("%v detected: %v", finding.Description, finding.Redacted)
+		if len(finding.Commit) > 0 {
+			message = fmt.Sprintf("%v (commit %v)", message, finding.Commit)
+		}
+		run.Results = append(run.Results, format.Results{
+			RuleID:    finding.RuleID,
+			RuleIndex: ruleIndices[finding.RuleID],
+			Level:     sarifLevel(finding.Severity),
+			Message:   &format.Message{Text: message},
+			Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{
+				ArtifactLocation: format.ArtifactLocation{URI: finding.File},
+				Region:           format.Region{StartLine: finding.Line, StartColumn: finding.Column},
+			}}},
+			PartialFingerprints: format.PartialFingerprints{PrimaryLocationLineHash: finding.Fingerprint},
+			Properties: &format.SarifProperties{
+				ToolSeverity:      finding.Severity,
+				InstanceSeverity:  finding.Severity,
+				ToolState:         "Unreviewed",
+				UnifiedAuditState: "new",
+			},
+		})
+	}
+	sarif.Runs = append(sarif.Runs, run)
+	return &sarif
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case "high", "critical":
+		return "error"
+	case "low", "info":
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+func securitySeverity(severity string) string {
+	switch severity {
+	case "critical":
+		return "9.5"
+	case "high":
+		return "8.0"
+	case "low", "info":
+		return "2.0"
+	default:
+		return "5.0"
+	}
+}
+
+// WriteReports writes the findings as JSON and SARIF report
+func WriteReports(findings []Finding, rules []Rule, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+	if err := utils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return reportPaths, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	jsonReport, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to marshal findings: %w", err)
+	}
+	jsonReportPath := filepath.Join(ReportsDirectory, "piper_secretscan_report.json")
+	if err := utils.FileWrite(jsonReportPath, jsonReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, fmt.Errorf("failed to write JSON report: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Secret scan JSON report", Target: jsonReportPath})
+
+	sarifReport, err := json.Marshal(CreateSarif(findings, rules))
+	if err != nil {
+		return reportPaths, fmt.Errorf("failed to marshal SARIF report: %w", err)
+	}
+	sarifReportPath := filepath.Join(ReportsDirectory, "piper_secretscan.sarif")
+	if err := utils.FileWrite(sarifReportPath, sarifReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, fmt.Errorf("failed to write SARIF file: %w", err)
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Secret scan SARIF file", Target: sarifReportPath})
+	return reportPaths, nil
+}
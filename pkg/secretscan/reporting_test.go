@@ -0,0 +1,71 @@
+//go:build unit
+
+package secretscan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSarif(t *testing.T) {
+	t.Parallel()
+	rules := []Rule{{ID: "one", Description: "First", Severity: "high"}, {ID: "two", Description: "Second", Severity: "low"}}
+	findings := []Finding{{RuleID: "two", Description: "Second", Severity: "low", File: "a/b.txt", Line: 3, Column: 5, Redacted: "abc********", Commit: "1234", Fingerprint: "fp"}}
+
+	sarif := CreateSarif(findings, rules)
+
+	assert.Equal(t, "2.1.0", sarif.Version)
+	if assert.Len(t, sarif.Runs, 1) {
+		run := sarif.Runs[0]
+		assert.Len(t, run.Tool.Driver.Rules, 2)
+		assert.Equal(t, "error", run.Tool.Driver.Rules[0].DefaultConfiguration.Level)
+		if assert.Len(t, run.Results, 1) {
+			assert.Equal(t, 1, run.Results[0].RuleIndex)
+			assert.Equal(t, "note", run.Results[0].Level)
+			assert.Equal(t, "Second detected: abc******** (commit 1234)", run.Results[0].Message.Text)
+			assert.Equal(t, format.Region{StartLine: 3, StartColumn: 5}, run.Results[0].Locations[0].PhysicalLocation.Region)
+			assert.Equal(t, "fp", run.Results[0].PartialFingerprints.PrimaryLocationLineHash)
+		}
+	}
+}
+
+func TestWriteReports(t *testing.T) {
+	t.Parallel()
+	utils := &mock.FilesMock{}
+	findings := []Finding{{RuleID: "one", File: "a.txt", Line: 1, Fingerprint: "fp"}}
+
+	paths, err := WriteReports(findings, []Rule{{ID: "one"}}, utils)
+
+	assert.NoError(t, err)
+	assert.Len(t, paths, 2)
+	content, err := utils.FileRead("secretscan/piper_secretscan_report.json")
+	assert.NoError(t, err)
+	var written []Finding
+	assert.NoError(t, json.Unmarshal(content, &written))
+	assert.Equal(t, findings, written)
+	exists, _ := utils.FileExists("secretscan/piper_secretscan.sarif")
+	assert.True(t, exists)
+}
+
+func TestBaseline(t *testing.T) {
+	t.Parallel()
+	baseline := NewBaseline([]Finding{
+		{Fingerprint: "b", File: "z.txt", Line: 1, RuleID: "r"},
+		{Fingerprint: "a", File: "a.txt", Line: 2, RuleID: "r"},
+		{Fingerprint: "b", File: "z.txt", Line: 1, RuleID: "r"},
+	})
+	content, err := baseline.ToJSON()
+	assert.NoError(t, err)
+
+	parsed, err := ParseBaseline(content)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BaselineEntry{{Fingerprint: "a", File: "a.txt", Line: 2, RuleID: "r"}, {Fingerprint: "b", File: "z.txt", Line: 1, RuleID: "r"}}, parsed.Findings)
+	assert.True(t, parsed.Contains("a"))
+	assert.False(t, parsed.Contains("c"))
+	assert.False(t, (*Baseline)(nil).Contains("a"))
+}
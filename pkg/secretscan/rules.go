@@ -0,0 +1,198 @@
+package secretscan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Rule describes how a specific kind of secret is detected
+type Rule struct {
+	ID          string `yaml:"id" json:"id"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Regex matches the secret, either as a whole or within the capture group SecretGroup
+	Regex       string `yaml:"regex" json:"regex"`
+	SecretGroup int    `yaml:"secretGroup,omitempty" json:"secretGroup,omitempty"`
+	// Keywords are used as a fast pre-filter, a line is only matched against the regex if it contains one of them
+	Keywords []string `yaml:"keywords,omitempty" json:"keywords,omitempty"`
+	// Entropy is the minimal Shannon entropy of the secret, used to skip placeholders and non-random values
+	Entropy  float64 `yaml:"entropy,omitempty" json:"entropy,omitempty"`
+	Severity string  `yaml:"severity,omitempty" json:"severity,omitempty"`
+
+	regex *regexp.Regexp
+}
+
+// Allowlist defines paths and secret values which are never reported
+type Allowlist struct {
+	// Paths are glob patterns of files which are not scanned, e.g. "**/testdata/**"
+	Paths []string `yaml:"paths,omitempty"`
+	// Regexes are matched against the detected secret, e.g. to ignore placeholders
+	Regexes []string `yaml:"regexes,omitempty"`
+
+	regexes []*regexp.Regexp
+}
+
+// RuleSet is the content of a rules file
+type RuleSet struct {
+	Rules     []Rule    `yaml:"rules"`
+	Allowlist Allowlist `yaml:"allowlist,omitempty"`
+}
+
+// DefaultRules returns the built-in rule set
+func DefaultRules() RuleSet {
+	return RuleSet{
+		Rules: []Rule{
+			{
+				ID:          "aws-access-key-id",
+				Description: "AWS access key ID",
+				Regex:       `\b((?:A3T[A-Z0-9]|AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{16})\b`,
+				SecretGroup: 1,
+				Keywords:    []string{"a3t", "akia", "asia", "abia", "acca"},
+				Entropy:     3,
+				Severity:    "high",
+			},
+			{
+				ID:          "aws-secret-access-key",
+				Description: "AWS secret access key",
+				Regex:       `(?i)aws.{0,20}?(?:secret|key).{0,20}?['"=:\s]\s*['"]?([A-Za-z0-9/+=]{40})\b`,
+				SecretGroup: 1,
+				Keywords:    []string{"aws"},
+				Entropy:     4,
+				Severity:    "high",
+			},
+			{
+				ID:          "github-token",
+				Description: "GitHub token",
+				Regex:       `\b((?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{82})\b`,
+				SecretGroup: 1,
+				Keywords:    []string{"ghp_", "gho_", "ghu_", "ghs_", "ghr_", "github_pat_"},
+				Severity:    "high",
+			},
+			{
+				ID:          "slack-token",
+				Description: "Slack token",
+				Regex:       `\b(xox[baprs]-[0-9A-Za-z-]{10,72})\b`,
+				SecretGroup: 1,
+				Keywords:    []string{"xoxb", "xoxa", "xoxp", "xoxr", "xoxs"},
+				Severity:    "high",
+			},
+			{
+				ID:          "google-api-key",
+				Description: "Google API key",
+				Regex:       `\b(AIza[0-9A-Za-z_-]{35})\b`,
+				SecretGroup: 1,
+				Keywords:    []string{"aiza"},
+				Severity:    "high",
+			},
+			{
+				ID:          "private-key",
+				Description: "Private key",
+				Regex:       `-----BEGIN ((?:RSA |DSA |EC |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?)-----`,
+				Keywords:    []string{"private key"},
+				Severity:    "high",
+			},
+			{
+				ID:          "jwt",
+				Description: "JSON Web Token",
+				Regex:       `\b(ey[A-Za-z0-9_-]{10,}\.ey[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})\b`,
+				SecretGroup: 1,
+				Keywords:    []string{"ey"},
+				Severity:    "medium",
+			},
+			{
+				ID:          "url-credentials",
+				Description: "Credentials in URL",
+				Regex:       `[a-zA-Z][a-zA-Z0-9+.-]*://[^\s:/@'"]+:([^\s:/@'"]{3,})@[^\s'"]+`,
+				SecretGroup: 1,
+				Keywords:    []string{"://"},
+				Entropy:     2,
+				Severity:    "medium",
+			},
+			{
+				ID:          "generic-secret",
+				Description: "Generic secret assignment",
+				Regex:       `(?i)(?:password|passwd|pwd|secret|token|api_?key|access_?key|client_?secret)[a-z0-9_.-]{0,20}\s*(?::=|=>|=|:)\s*['"]([^'"\s]{8,})['"]`,
+				SecretGroup: 1,
+				Keywords:    []string{"password", "passwd", "pwd", "secret", "token", "key"},
+				Entropy:     3.5,
+				Severity:    "medium",
+			},
+		},
+		Allowlist: Allowlist{
+			Regexes: []string{
+				`^\$\{[^}]*\}$`,
+				`^\{\{[^}]*\}\}$`,
+				`^<[^>]*>$`,
+				`(?i)^(?:x+|\*+|changeme|password|secret|example|dummy|placeholder|redacted)$`,
+			},
+		},
+	}
+}
+
+// ParseRules parses a rules file in YAML format
+func ParseRules(content []byte) (RuleSet, error) {
+	var ruleSet RuleSet
+	if err := yaml.Unmarshal(content, &ruleSet); err != nil {
+		return RuleSet{}, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	return ruleSet, nil
+}
+
+// Merge adds the rules and allowlist entries of another rule set, rules with the same id are replaced
+func (r RuleSet) Merge(other RuleSet) RuleSet {
+	merged := RuleSet{Allowlist: Allowlist{
+		Paths:   append(append([]string{}, r.Allowlist.Paths...), other.Allowlist.Paths...),
+		Regexes: append(append([]string{}, r.Allowlist.Regexes...), other.Allowlist.Regexes...),
+	}}
+	replaced := map[string]Rule{}
+	for _, rule := range other.Rules {
+		replaced[rule.ID] = rule
+	}
+	for _, rule := range r.Rules {
+		if replacement, ok := replaced[rule.ID]; ok {
+			rule = replacement
+			delete(replaced, rule.ID)
+		}
+		merged.Rules = append(merged.Rules, rule)
+	}
+	for _, rule := range other.Rules {
+		if _, ok := replaced[rule.ID]; ok {
+			merged.Rules = append(merged.Rules, rule)
+		}
+	}
+	return merged
+}
+
+func (r *RuleSet) compile() error {
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if len(rule.ID) == 0 {
+			return fmt.Errorf("rule %v has no id", i)
+		}
+		regex, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex of rule '%v': %w", rule.ID, err)
+		}
+		if rule.SecretGroup > regex.NumSubexp() {
+			return fmt.Errorf("secret group %v of rule '%v' does not exist", rule.SecretGroup, rule.ID)
+		}
+		rule.regex = regex
+		for j, keyword := range rule.Keywords {
+			rule.Keywords[j] = strings.ToLower(keyword)
+		}
+		if len(rule.Severity) == 0 {
+			rule.Severity = "medium"
+		}
+	}
+	r.Allowlist.regexes = []*regexp.Regexp{}
+	for _, pattern := range r.Allowlist.Regexes {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid allowlist regex '%v': %w", pattern, err)
+		}
+		r.Allowlist.regexes = append(r.Allowlist.regexes, regex)
+	}
+	return nil
+}
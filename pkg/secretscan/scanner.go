@@ -0,0 +1,276 @@
+package secretscan
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// AllowComment marks a line whose secrets are intentionally not reported
+const AllowComment = "piper:allow-secret"
+
+// number of bytes inspected to decide whether a file is binary
+const binaryDetectionSize = 8000
+
+// maximal length of a line scanned by ScanReader, the remainder of longer lines is skipped
+const maxLineLength = 1024 * 1024
+
+// Finding is a secret detected in a file or commit
+type Finding struct {
+	RuleID      string  `json:"ruleId"`
+	Description string  `json:"description,omitempty"`
+	Severity    string  `json:"severity"`
+	File        string  `json:"file"`
+	Line        int     `json:"line"`
+	Column      int     `json:"column"`
+	Commit      string  `json:"commit,omitempty"`
+	Author      string  `json:"author,omitempty"`
+	Redacted    string  `json:"secret"`
+	Entropy     float64 `json:"entropy"`
+	Fingerprint string  `json:"fingerprint"`
+}
+
+// Options to configure the scanner
+type Options struct {
+	// ExcludePaths are glob patterns of files which are not scanned, in addition to the allowlist of the rule set
+	ExcludePaths []string
+	// MaxFileSize is the maximal size of a file in bytes to be scanned, 0 means no limit
+	MaxFileSize int64
+	// Baseline contains fingerprints of known findings which are not reported
+	Baseline *Baseline
+}
+
+// Scanner detects secrets based on a rule set
+type Scanner struct {
+	ruleSet RuleSet
+	options Options
+}
+
+// NewScanner validates the rule set and creates a scanner
+func NewScanner(ruleSet RuleSet, options Options) (*Scanner, error) {
+	if err := ruleSet.compile(); err != nil {
+		return nil, err
+	}
+	return &Scanner{ruleSet: ruleSet, options: options}, nil
+}
+
+// Rules returns the rules of the scanner
+func (s *Scanner) Rules() []Rule {
+	return s.ruleSet.Rules
+}
+
+// ScanDirectory scans all files below the given directory
+func (s *Scanner) ScanDirectory(root string, utils piperutils.FileUtils) ([]Finding, error) {
+	matches, err := utils.Glob(filepath.Join(root, "**"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in '%v': %w", root, err)
+	}
+	sort.Strings(matches)
+	findings := []Finding{}
+	for _, match := range matches {
+		relativePath, err := filepath.Rel(root, match)
+		if err != nil {
+			relativePath = match
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		if s.isExcluded(relativePath) {
+			continue
+		}
+		info, err := utils.Stat(match)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if s.options.MaxFileSize > 0 && info.Size() > s.options.MaxFileSize {
+			log.Entry().Debugf("Skipping file '%v' exceeding the maximal size", relativePath)
+			continue
+		}
+		content, err := utils.FileRead(match)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file '%v': %w", match, err)
+		}
+		findings = append(findings, s.ScanContent(relativePath, content)...)
+	}
+	return findings, nil
+}
+
+// ScanContent scans the content of a single file, binary content is skipped
+func (s *Scanner) ScanContent(path string, content []byte) []Finding {
+	if isBinary(content) {
+		return []Finding{}
+	}
+	findings := []Finding{}
+	for i, line := range strings.Split(string(content), "\n") {
+		findings = append(findings, s.ScanLine(path, i+1, line)...)
+	}
+	return findings
+}
+
+// ScanReader scans the content of a file line by line without reading it into memory as a whole
+func (s *Scanner) ScanReader(path string, reader io.Reader) ([]Finding, error) {
+	buffered := bufio.NewReaderSize(reader, binaryDetectionSize)
+	head, err := buffered.Peek(binaryDetectionSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if isBinary(head) {
+		return []Finding{}, nil
+	}
+	findings := []Finding{}
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := readLine(buffered)
+		if errors.Is(err, io.EOF) {
+			return append(findings, s.ScanLine(path, lineNumber, line)...), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, s.ScanLine(path, lineNumber, line)...)
+	}
+}
+
+// readLine reads the next line without the line break, the part exceeding maxLineLength is skipped
+func readLine(reader *bufio.Reader) (string, error) {
+	line := []byte{}
+	for {
+		fragment, err := reader.ReadSlice('\n')
+		line = append(line, fragment[:min(len(fragment), maxLineLength-len(line))]...)
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		return strings.TrimSuffix(string(line), "\n"), err
+	}
+}
+
+// ScanLine scans a single line of a file. A secret matched by several rules is only reported
+// for the first one, therefore specific rules precede generic ones.
+func (s *Scanner) ScanLine(path string, lineNumber int, line string) []Finding {
+	findings := []Finding{}
+	if strings.Contains(line, AllowComment) {
+		return findings
+	}
+	matchedSpans := [][2]int{}
+	lowerLine := strings.ToLower(line)
+	for _, rule := range s.ruleSet.Rules {
+		if !containsKeyword(lowerLine, rule.Keywords) {
+			continue
+		}
+		for _, match := range rule.regex.FindAllStringSubmatchIndex(line, -1) {
+			start, end := match[2*rule.SecretGroup], match[2*rule.SecretGroup+1]
+			if start < 0 || overlaps(matchedSpans, start, end) {
+				continue
+			}
+			secret := line[start:end]
+			entropy := ShannonEntropy(secret)
+			if rule.Entropy > 0 && entropy < rule.Entropy {
+				continue
+			}
+			if s.isAllowedSecret(secret) {
+				continue
+			}
+			finding := Finding{
+				RuleID:      rule.ID,
+				Description: rule.Description,
+				Severity:    rule.Severity,
+				File:        path,
+				Line:        lineNumber,
+				Column:      start + 1,
+				Redacted:    redact(secret),
+				Entropy:     math.Round(entropy*100) / 100,
+				Fingerprint: fingerprint(rule.ID, path, secret),
+			}
+			matchedSpans = append(matchedSpans, [2]int{start, end})
+			if s.options.Baseline.Contains(finding.Fingerprint) {
+				log.Entry().Debugf("Skipping finding %v in '%v' contained in baseline", rule.ID, path)
+				continue
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func (s *Scanner) isExcluded(path string) bool {
+	for _, pattern := range append(append([]string{}, s.options.ExcludePaths...), s.ruleSet.Allowlist.Paths...) {
+		if matched, _ := doublestar.Match(pattern, path); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scanner) isAllowedSecret(secret string) bool {
+	for _, regex := range s.ruleSet.Allowlist.regexes {
+		if regex.MatchString(secret) {
+			return true
+		}
+	}
+	return false
+}
+
+func overlaps(spans [][2]int, start, end int) bool {
+	for _, span := range spans {
+		if start < span[1] && span[0] < end {
+			return true
+		}
+	}
+	return false
+}
+
+func containsKeyword(line string, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	for _, keyword := range keywords {
+		if strings.Contains(line, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShannonEntropy calculates the Shannon entropy of a string in bits per character
+func ShannonEntropy(value string) float64 {
+	if len(value) == 0 {
+		return 0
+	}
+	frequencies := map[rune]float64{}
+	length := 0.0
+	for _, char := range value {
+		frequencies[char]++
+		length++
+	}
+	entropy := 0.0
+	for _, count := range frequencies {
+		probability := count / length
+		entropy -= probability * math.Log2(probability)
+	}
+	return entropy
+}
+
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), binaryDetectionSize)], 0) >= 0
+}
+
+// redact keeps only a short prefix of the secret to allow identifying it
+func redact(secret string) string {
+	visible := min(4, len(secret)/4)
+	return secret[:visible] + strings.Repeat("*", 8)
+}
+
+// fingerprint identifies a finding independent of its position within the file
+func fingerprint(ruleID, path, secret string) string {
+	hash := sha256.Sum256([]byte(ruleID + ":" + path + ":" + secret))
+	return hex.EncodeToString(hash[:16])
+}
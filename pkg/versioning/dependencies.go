@@ -0,0 +1,190 @@
+package versioning
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// DependencyUpdate describes the version change of a dependency declared in a build descriptor
+type DependencyUpdate struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+}
+
+// UpdateDependencyVersion updates the declared version of a direct dependency in the content of a build descriptor.
+// Supported descriptors are pom.xml, package.json and pip requirements files.
+// The formatting of the descriptor is preserved and versions are never downgraded.
+// The returned flag indicates whether the content has been changed.
+func UpdateDependencyVersion(descriptor string, content []byte, update DependencyUpdate) ([]byte, bool, error) {
+	name := filepath.Base(descriptor)
+	switch {
+	case strings.HasSuffix(name, ".xml"):
+		return updateMavenDependency(content, update)
+	case name == "package.json":
+		return updateNpmDependency(content, update)
+	case strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt"):
+		return updatePipRequirement(content, update)
+	}
+	return content, false, fmt.Errorf("updating dependencies in '%v' is not supported", descriptor)
+}
+
+var (
+	mavenDependencyPattern = regexp.MustCompile(`(?s)<dependency>.*?</dependency>`)
+	mavenVersionPattern    = regexp.MustCompile(`(<version>\s*)([^<\s]+)(\s*</version>)`)
+	mavenPropertyPattern   = regexp.MustCompile(`^\$\{([^}]+)\}$`)
+	mavenExclusionsPattern = regexp.MustCompile(`(?s)<exclusions>.*?</exclusions>`)
+)
+
+func updateMavenDependency(content []byte, update DependencyUpdate) ([]byte, bool, error) {
+	changed := false
+	properties := []string{}
+	result := mavenDependencyPattern.ReplaceAllFunc(content, func(dependency []byte) []byte {
+		declaration := mavenExclusionsPattern.ReplaceAll(dependency, nil)
+		if !xmlElementEquals(declaration, "groupId", update.GroupID) || !xmlElementEquals(declaration, "artifactId", update.ArtifactID) {
+			return dependency
+		}
+		match := mavenVersionPattern.FindSubmatch(declaration)
+		if match == nil {
+			// version is managed elsewhere, e.g. in a parent pom
+			return dependency
+		}
+		if property := mavenPropertyPattern.FindSubmatch(match[2]); property != nil {
+			properties = append(properties, string(property[1]))
+			return dependency
+		}
+		if string(match[2]) == update.Version || isDowngrade(string(match[2]), update.Version) {
+			return dependency
+		}
+		changed = true
+		return mavenVersionPattern.ReplaceAll(dependency, []byte("${1}"+update.Version+"${3}"))
+	})
+
+	for _, property := range properties {
+		propertyPattern := regexp.MustCompile(`(<` + regexp.QuoteMeta(property) + `>\s*)([^<\s]+)(\s*</` + regexp.QuoteMeta(property) + `>)`)
+		match := propertyPattern.FindSubmatch(result)
+		if match == nil {
+			return content, false, fmt.Errorf("property '%v' defining the version of %v:%v not found", property, update.GroupID, update.ArtifactID)
+		}
+		if string(match[2]) != update.Version && !isDowngrade(string(match[2]), update.Version) {
+			result = propertyPattern.ReplaceAll(result, []byte("${1}"+update.Version+"${3}"))
+			changed = true
+		}
+	}
+	return result, changed, nil
+}
+
+func xmlElementEquals(content []byte, element, value string) bool {
+	pattern := regexp.MustCompile(`<` + element + `>\s*` + regexp.QuoteMeta(value) + `\s*</` + element + `>`)
+	return pattern.Match(content)
+}
+
+var npmDependencySections = []string{"dependencies", "devDependencies", "optionalDependencies", "peerDependencies"}
+
+func updateNpmDependency(content []byte, update DependencyUpdate) ([]byte, bool, error) {
+	sections, err := npmDependencySectionOffsets(content)
+	if err != nil {
+		return content, false, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+
+	// keep range operators like ^ or ~ in order to not change the update behavior of the project
+	pattern := regexp.MustCompile(`("` + regexp.QuoteMeta(update.ArtifactID) + `"\s*:\s*")([\^~>=]*)([^"]+)(")`)
+	changed := false
+	result := []byte{}
+	last := 0
+	// only the dependency sections are updated, the name may also appear e.g. in overrides or scripts
+	for _, section := range sections {
+		result = append(result, content[last:section[0]]...)
+		result = append(result, pattern.ReplaceAllFunc(content[section[0]:section[1]], func(declaration []byte) []byte {
+			match := pattern.FindSubmatch(declaration)
+			if string(match[3]) == update.Version || strings.ContainsAny(string(match[3]), ":/ ") {
+				// skip unchanged versions as well as urls, tags or complex ranges
+				return declaration
+			}
+			if isDowngrade(string(match[3]), update.Version) {
+				return declaration
+			}
+			changed = true
+			return []byte(string(match[1]) + string(match[2]) + update.Version + string(match[4]))
+		})...)
+		last = section[1]
+	}
+	result = append(result, content[last:]...)
+	return result, changed, nil
+}
+
+// npmDependencySectionOffsets returns the start and end offsets of the top-level dependency sections of a package.json
+func npmDependencySectionOffsets(content []byte) ([][2]int, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	if token, err := decoder.Token(); err != nil {
+		return nil, err
+	} else if token != json.Delim('{') {
+		return nil, fmt.Errorf("content is no JSON object")
+	}
+	sections := [][2]int{}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		start := decoder.InputOffset()
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		if name, ok := key.(string); ok && slices.Contains(npmDependencySections, name) {
+			sections = append(sections, [2]int{int(start), int(decoder.InputOffset())})
+		}
+	}
+	return sections, nil
+}
+
+// isDowngrade checks whether the target version is lower than the current one.
+// Versions which are no semantic versions are not compared.
+func isDowngrade(current, target string) bool {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return false
+	}
+	targetVersion, err := semver.NewVersion(target)
+	if err != nil {
+		return false
+	}
+	if targetVersion.LessThan(currentVersion) {
+		log.Entry().Warnf("Skipping downgrade from version %v to %v", current, target)
+		return true
+	}
+	return false
+}
+
+var pipRequirementPattern = regexp.MustCompile(`^(\s*)([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?(\s*)(===|==|>=|~=)(\s*)([^\s,;#]+)(.*)$`)
+
+func updatePipRequirement(content []byte, update DependencyUpdate) ([]byte, bool, error) {
+	lines := strings.Split(string(content), "\n")
+	changed := false
+	for i, line := range lines {
+		match := pipRequirementPattern.FindStringSubmatch(line)
+		if match == nil || normalizePipName(match[2]) != normalizePipName(update.ArtifactID) {
+			continue
+		}
+		if match[7] == update.Version || isDowngrade(match[7], update.Version) {
+			continue
+		}
+		lines[i] = match[1] + match[2] + match[3] + match[4] + match[5] + match[6] + update.Version + match[8]
+		changed = true
+	}
+	return []byte(strings.Join(lines, "\n")), changed, nil
+}
+
+// normalizePipName normalizes a python package name as defined in PEP 503
+func normalizePipName(name string) string {
+	return strings.ToLower(regexp.MustCompile(`[-_.]+`).ReplaceAllString(name, "-"))
+}
@@ -0,0 +1,162 @@
+//go:build unit
+
+package versioning
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateDependencyVersion(t *testing.T) {
+	t.Parallel()
+	t.Run("maven dependency with explicit version", func(t *testing.T) {
+		pom := `<project>
+  <dependencies>
+    <dependency>
+      <groupId>org.apache.commons</groupId>
+      <artifactId>commons-text</artifactId>
+      <version>1.9</version>
+      <exclusions>
+        <exclusion>
+          <groupId>org.example</groupId>
+          <artifactId>other</artifactId>
+        </exclusion>
+      </exclusions>
+    </dependency>
+    <dependency>
+      <groupId>org.example</groupId>
+      <artifactId>other</artifactId>
+      <version>1.9</version>
+    </dependency>
+  </dependencies>
+</project>`
+
+		result, changed, err := UpdateDependencyVersion("module/pom.xml", []byte(pom), DependencyUpdate{GroupID: "org.apache.commons", ArtifactID: "commons-text", Version: "1.10.0"})
+
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Contains(t, string(result), "<artifactId>commons-text</artifactId>\n      <version>1.10.0</version>")
+		assert.Contains(t, string(result), "<artifactId>other</artifactId>\n      <version>1.9</version>")
+	})
+
+	t.Run("maven dependency with version property", func(t *testing.T) {
+		pom := `<project>
+  <properties>
+    <commons.version>1.9</commons.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>org.apache.commons</groupId>
+      <artifactId>commons-text</artifactId>
+      <version>${commons.version}</version>
+    </dependency>
+  </dependencies>
+</project>`
+
+		result, changed, err := UpdateDependencyVersion("pom.xml", []byte(pom), DependencyUpdate{GroupID: "org.apache.commons", ArtifactID: "commons-text", Version: "1.10.0"})
+
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Contains(t, string(result), "<commons.version>1.10.0</commons.version>")
+		assert.Contains(t, string(result), "<version>${commons.version}</version>")
+	})
+
+	t.Run("maven dependency with managed version", func(t *testing.T) {
+		pom := `<project><dependencies><dependency><groupId>g</groupId><artifactId>a</artifactId></dependency></dependencies></project>`
+
+		result, changed, err := UpdateDependencyVersion("pom.xml", []byte(pom), DependencyUpdate{GroupID: "g", ArtifactID: "a", Version: "2.0.0"})
+
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, pom, string(result))
+	})
+
+	t.Run("npm dependency keeps range operator", func(t *testing.T) {
+		packageJSON := `{
+  "name": "app",
+  "dependencies": {
+    "lodash": "^4.17.15",
+    "express": "4.17.1"
+  },
+  "devDependencies": {
+    "mocha": "git+https://github.com/mochajs/mocha.git"
+  }
+}`
+
+		result, changed, err := UpdateDependencyVersion("package.json", []byte(packageJSON), DependencyUpdate{ArtifactID: "lodash", Version: "4.17.21"})
+
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Contains(t, string(result), `"lodash": "^4.17.21"`)
+		assert.Contains(t, string(result), `"express": "4.17.1"`)
+
+		_, changed, err = UpdateDependencyVersion("package.json", []byte(packageJSON), DependencyUpdate{ArtifactID: "mocha", Version: "10.0.0"})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+
+		_, changed, err = UpdateDependencyVersion("package.json", []byte(packageJSON), DependencyUpdate{ArtifactID: "app", Version: "10.0.0"})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("npm dependency outside of dependency sections", func(t *testing.T) {
+		packageJSON := `{
+  "name": "app",
+  "scripts": {
+    "lodash": "4.0.0"
+  },
+  "overrides": {
+    "lodash": "4.17.10"
+  },
+  "devDependencies": {
+    "lodash": "~4.17.15"
+  }
+}`
+
+		result, changed, err := UpdateDependencyVersion("package.json", []byte(packageJSON), DependencyUpdate{ArtifactID: "lodash", Version: "4.17.21"})
+
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, strings.Replace(packageJSON, `"lodash": "~4.17.15"`, `"lodash": "~4.17.21"`, 1), string(result))
+	})
+
+	t.Run("no downgrade", func(t *testing.T) {
+		pom := `<project><properties><commons.version>1.12.0</commons.version></properties><dependencies>
+<dependency><groupId>g</groupId><artifactId>a</artifactId><version>2.1.0</version></dependency>
+<dependency><groupId>org.apache.commons</groupId><artifactId>commons-text</artifactId><version>${commons.version}</version></dependency>
+</dependencies></project>`
+		result, changed, err := UpdateDependencyVersion("pom.xml", []byte(pom), DependencyUpdate{GroupID: "g", ArtifactID: "a", Version: "2.0.5"})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, pom, string(result))
+
+		result, changed, err = UpdateDependencyVersion("pom.xml", []byte(pom), DependencyUpdate{GroupID: "org.apache.commons", ArtifactID: "commons-text", Version: "1.10.0"})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, pom, string(result))
+
+		packageJSON := `{"dependencies": {"lodash": "^4.17.21"}}`
+		result, changed, err = UpdateDependencyVersion("package.json", []byte(packageJSON), DependencyUpdate{ArtifactID: "lodash", Version: "4.17.15"})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, packageJSON, string(result))
+	})
+
+	t.Run("pip requirement", func(t *testing.T) {
+		requirements := "requests==2.25.0 # http\nPyYAML>=5.3\nflask\n"
+
+		result, changed, err := UpdateDependencyVersion("requirements-dev.txt", []byte(requirements), DependencyUpdate{ArtifactID: "pyyaml", Version: "5.4"})
+
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "requests==2.25.0 # http\nPyYAML>=5.4\nflask\n", string(result))
+	})
+
+	t.Run("unsupported descriptor", func(t *testing.T) {
+		_, _, err := UpdateDependencyVersion("build.gradle", []byte{}, DependencyUpdate{ArtifactID: "a", Version: "1"})
+
+		assert.EqualError(t, err, "updating dependencies in 'build.gradle' is not supported")
+	})
+}
@@ -2,6 +2,8 @@ package versioning
 
 import (
 	"fmt"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -22,8 +24,29 @@ type tomlCoordinates struct {
 		Name    string `toml:"name"`
 		Version string `toml:"version"`
 	} `toml:"project"`
+	Tool struct {
+		// Poetry 1 keeps the metadata in its own section
+		Poetry struct {
+			Name    string `toml:"name"`
+			Version string `toml:"version"`
+		} `toml:"poetry"`
+		Hatch struct {
+			Version struct {
+				Path string `toml:"path"`
+			} `toml:"version"`
+		} `toml:"hatch"`
+		Pdm struct {
+			Version struct {
+				Source string `toml:"source"`
+				Path   string `toml:"path"`
+			} `toml:"version"`
+		} `toml:"pdm"`
+	} `toml:"tool"`
 }
 
+// versionAttributeRegex is used to match the version attribute of a python module providing the dynamic version of Hatch and PDM projects
+var versionAttributeRegex = regexp.MustCompile(`(?m)^__version__\s*=\s*['"](.*?)['"]`)
+
 func (p *Toml) init() error {
 	var coordinates tomlCoordinates
 
@@ -47,27 +70,70 @@ func (p *Toml) GetName() (string, error) {
 	if err := p.init(); err != nil {
 		return "", fmt.Errorf("failed to read file '%v': %w", p.Pip.path, err)
 	}
-	if len(p.coordinates.Project.Name) == 0 {
-		return "", fmt.Errorf("no name information found in file '%v'", p.Pip.path)
+	if len(p.coordinates.Project.Name) > 0 {
+		return p.coordinates.Project.Name, nil
+	}
+	if len(p.coordinates.Tool.Poetry.Name) > 0 {
+		return p.coordinates.Tool.Poetry.Name, nil
 	}
-	return p.coordinates.Project.Name, nil
+	return "", fmt.Errorf("no name information found in file '%v'", p.Pip.path)
 }
 
-// // GetVersion returns the current version from the build descriptor
+// GetVersion returns the current version from the build descriptor.
+// Besides the project metadata the version is read from the poetry section and from the module providing the dynamic version of Hatch and PDM projects.
 func (p *Toml) GetVersion() (string, error) {
 	if err := p.init(); err != nil {
 		return "", fmt.Errorf("failed to read file '%v': %w", p.Pip.path, err)
 	}
-	if len(p.coordinates.Project.Version) == 0 {
-		return "", fmt.Errorf("no version information found in file '%v'", p.Pip.path)
+	if len(p.coordinates.Project.Version) > 0 {
+		return p.coordinates.Project.Version, nil
+	}
+	if len(p.coordinates.Tool.Poetry.Version) > 0 {
+		return p.coordinates.Tool.Poetry.Version, nil
+	}
+	if versionFile := p.versionFile(); len(versionFile) > 0 {
+		content, err := p.Pip.readFile(versionFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read file '%v': %w", versionFile, err)
+		}
+		values := versionAttributeRegex.FindStringSubmatch(string(content))
+		if len(values) < 2 {
+			return "", fmt.Errorf("no version information found in file '%v'", versionFile)
+		}
+		return values[1], nil
 	}
-	return p.coordinates.Project.Version, nil
+	return "", fmt.Errorf("no version information found in file '%v'", p.Pip.path)
+}
+
+// versionFile returns the path of the module providing the dynamic version, relative to the working directory
+func (p *Toml) versionFile() string {
+	versionFile := p.coordinates.Tool.Hatch.Version.Path
+	if len(versionFile) == 0 && p.coordinates.Tool.Pdm.Version.Source == "file" {
+		versionFile = p.coordinates.Tool.Pdm.Version.Path
+	}
+	if len(versionFile) == 0 {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(p.Pip.path), versionFile)
 }
 
 // SetVersion updates the version in the build descriptor
 func (p *Toml) SetVersion(new string) error {
 	if current, err := p.GetVersion(); err != nil {
 		return err
+	} else if len(p.coordinates.Project.Version) == 0 && len(p.coordinates.Tool.Poetry.Version) == 0 {
+		versionFile := p.versionFile()
+		content, err := p.Pip.readFile(versionFile)
+		if err != nil {
+			return fmt.Errorf("failed to read file '%v': %w", versionFile, err)
+		}
+		updated := versionAttributeRegex.ReplaceAllStringFunc(string(content), func(attribute string) string {
+			return strings.Replace(attribute, current, new, 1)
+		})
+		if err := p.Pip.writeFile(versionFile, []byte(updated), 0600); err != nil {
+			return fmt.Errorf("failed to write file '%v': %w", versionFile, err)
+		}
+		return nil
 	} else {
 		// replace with single quotes
 		p.Pip.buildDescriptorContent = strings.ReplaceAll(
@@ -228,3 +228,83 @@ func TestTomlGetCoordinates(t *testing.T) {
 		assert.Equal(t, "", coordinates.Version)
 	})
 }
+
+func TestTomlBuildTools(t *testing.T) {
+	t.Parallel()
+	t.Run("success case - poetry section", func(t *testing.T) {
+		fileUtils := piperMock.FilesMock{}
+		fileUtils.AddFile(TomlBuildDescriptor, []byte(`[tool.poetry]
+name = "poetry-project"
+version = "1.0.0"
+
+[tool.poetry.dependencies]
+python = "^3.11"
+`))
+		toml := Toml{Pip: Pip{path: TomlBuildDescriptor, fileExists: fileUtils.FileExists, readFile: fileUtils.FileRead, writeFile: fileUtils.FileWrite}}
+
+		coordinates, err := toml.GetCoordinates()
+		assert.NoError(t, err)
+		assert.Equal(t, "poetry-project", coordinates.ArtifactID)
+		assert.Equal(t, "1.0.0", coordinates.Version)
+
+		assert.NoError(t, toml.SetVersion("1.1.0"))
+		content, _ := fileUtils.FileRead(TomlBuildDescriptor)
+		assert.Contains(t, string(content), `version = "1.1.0"`)
+		assert.Contains(t, string(content), `python = "^3.11"`)
+	})
+
+	t.Run("success case - hatch dynamic version", func(t *testing.T) {
+		fileUtils := piperMock.FilesMock{}
+		fileUtils.AddFile(TomlBuildDescriptor, []byte(`[project]
+name = "hatch-project"
+dynamic = ["version"]
+
+[tool.hatch.version]
+path = "src/hatch_project/__about__.py"
+`))
+		fileUtils.AddFile("src/hatch_project/__about__.py", []byte("# SPDX-License-Identifier: MIT\n__version__ = \"0.3.1\"\n"))
+		toml := Toml{Pip: Pip{path: TomlBuildDescriptor, fileExists: fileUtils.FileExists, readFile: fileUtils.FileRead, writeFile: fileUtils.FileWrite}}
+
+		coordinates, err := toml.GetCoordinates()
+		assert.NoError(t, err)
+		assert.Equal(t, "hatch-project", coordinates.ArtifactID)
+		assert.Equal(t, "0.3.1", coordinates.Version)
+
+		assert.NoError(t, toml.SetVersion("0.4.0"))
+		content, _ := fileUtils.FileRead("src/hatch_project/__about__.py")
+		assert.Equal(t, "# SPDX-License-Identifier: MIT\n__version__ = \"0.4.0\"\n", string(content))
+	})
+
+	t.Run("success case - pdm dynamic version", func(t *testing.T) {
+		fileUtils := piperMock.FilesMock{}
+		fileUtils.AddFile(TomlBuildDescriptor, []byte(`[project]
+name = "pdm-project"
+dynamic = ["version"]
+
+[tool.pdm.version]
+source = "file"
+path = "pdm_project/__init__.py"
+`))
+		fileUtils.AddFile("pdm_project/__init__.py", []byte("__version__ = '2.0.0'\n"))
+		toml := Toml{Pip: Pip{path: TomlBuildDescriptor, fileExists: fileUtils.FileExists, readFile: fileUtils.FileRead, writeFile: fileUtils.FileWrite}}
+
+		version, err := toml.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0", version)
+	})
+
+	t.Run("fail - version from source control", func(t *testing.T) {
+		fileUtils := piperMock.FilesMock{}
+		fileUtils.AddFile(TomlBuildDescriptor, []byte(`[project]
+name = "pdm-project"
+dynamic = ["version"]
+
+[tool.pdm.version]
+source = "scm"
+`))
+		toml := Toml{Pip: Pip{path: TomlBuildDescriptor, fileExists: fileUtils.FileExists, readFile: fileUtils.FileRead, writeFile: fileUtils.FileWrite}}
+
+		_, err := toml.GetVersion()
+		assert.ErrorContains(t, err, "no version information found in file 'pyproject.toml'")
+	})
+}
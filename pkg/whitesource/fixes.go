@@ -0,0 +1,218 @@
+package whitesource
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// DependencyUpgrade describes the minimal version upgrade of a direct dependency
+// which resolves all known security vulnerabilities reported for it.
+type DependencyUpgrade struct {
+	Library         Library
+	TargetVersion   string
+	Vulnerabilities []string
+}
+
+// PackageType returns the package URL type of the upgraded library, e.g. maven, npm or pypi.
+func (u DependencyUpgrade) PackageType() string {
+	return transformLibToPurlType(u.Library.LibType)
+}
+
+// PackageName returns the name under which the library is declared in a build descriptor.
+// For Maven this is the artifactId, the groupId is available via the library itself.
+func (u DependencyUpgrade) PackageName() string {
+	switch u.PackageType() {
+	case packageurl.TypeMaven:
+		return u.Library.ArtifactID
+	case packageurl.TypeNPM, packageurl.TypePyPi:
+		if len(u.Library.GroupID) > 0 {
+			return u.Library.GroupID
+		}
+		name := u.Library.ArtifactID
+		if len(name) == 0 {
+			name = u.Library.Name
+		}
+		for _, suffix := range []string{".tgz", ".tar.gz", ".whl", ".zip"} {
+			name = strings.TrimSuffix(name, suffix)
+		}
+		return strings.TrimSuffix(name, "-"+u.Library.Version)
+	}
+	return u.Library.ArtifactID
+}
+
+var fixVersionPattern = regexp.MustCompile(`(?i)upgrade to version\s+(.+)`)
+
+// MinimalUpgrades calculates for each vulnerable direct dependency the lowest version which fixes
+// all security vulnerabilities reported for it. Alerts without a usable fix resolution are ignored.
+func MinimalUpgrades(alerts []Alert) []DependencyUpgrade {
+	upgrades := map[string]*DependencyUpgrade{}
+	keys := []string{}
+
+	for _, alert := range alerts {
+		if alert.Type != "SECURITY_VULNERABILITY" || !alert.DirectDependency {
+			continue
+		}
+		if alert.Assessment != nil {
+			continue
+		}
+		target := fixVersion(alert.Library.Version, alert.Vulnerability)
+		if len(target) == 0 {
+			continue
+		}
+
+		key := fmt.Sprintf("%v:%v:%v", alert.Library.GroupID, alert.Library.ArtifactID, alert.Library.Version)
+		upgrade, ok := upgrades[key]
+		if !ok {
+			upgrade = &DependencyUpgrade{Library: alert.Library}
+			upgrades[key] = upgrade
+			keys = append(keys, key)
+		}
+		if len(upgrade.TargetVersion) == 0 || CompareVersions(target, upgrade.TargetVersion) > 0 {
+			upgrade.TargetVersion = target
+		}
+		if !slices.Contains(upgrade.Vulnerabilities, alert.Vulnerability.Name) {
+			upgrade.Vulnerabilities = append(upgrade.Vulnerabilities, alert.Vulnerability.Name)
+		}
+	}
+
+	sort.Strings(keys)
+	result := make([]DependencyUpgrade, 0, len(keys))
+	for _, key := range keys {
+		sort.Strings(upgrades[key].Vulnerabilities)
+		result = append(result, *upgrades[key])
+	}
+	return result
+}
+
+// fixVersion returns the lowest version mentioned in the fixes of a vulnerability which is higher
+// than the current version. Versions within the current major version are preferred.
+func fixVersion(current string, vul Vulnerability) string {
+	candidates := fixVersionCandidates(vul.TopFix.FixResolution)
+	for _, fix := range vul.AllFixes {
+		candidates = append(candidates, fixVersionCandidates(fix.FixResolution)...)
+	}
+	if len(candidates) == 0 {
+		candidates = fixVersionCandidates(vul.FixResolutionText)
+	}
+
+	var sameMajor, other string
+	for _, candidate := range candidates {
+		if CompareVersions(candidate, current) <= 0 {
+			continue
+		}
+		if majorVersion(candidate) == majorVersion(current) {
+			if len(sameMajor) == 0 || CompareVersions(candidate, sameMajor) < 0 {
+				sameMajor = candidate
+			}
+		} else if len(other) == 0 || CompareVersions(candidate, other) < 0 {
+			other = candidate
+		}
+	}
+	if len(sameMajor) > 0 {
+		return sameMajor
+	}
+	return other
+}
+
+// fixVersionCandidates extracts the versions from a fix resolution like
+// "Upgrade to version com.fasterxml.jackson.core:jackson-databind:2.12.7.1,2.13.4.1" or "Upgrade to version lodash - 4.17.21".
+func fixVersionCandidates(resolution string) []string {
+	match := fixVersionPattern.FindStringSubmatch(resolution)
+	if len(match) < 2 {
+		return nil
+	}
+	candidates := []string{}
+	for _, part := range strings.FieldsFunc(match[1], func(r rune) bool { return r == ',' || r == ';' || r == ' ' }) {
+		if idx := strings.LastIndex(part, ":"); idx >= 0 {
+			part = part[idx+1:]
+		}
+		part = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(part), "v"), ".")
+		if len(part) > 0 && part[0] >= '0' && part[0] <= '9' {
+			candidates = append(candidates, part)
+		}
+	}
+	return candidates
+}
+
+func majorVersion(version string) string {
+	return strings.SplitN(strings.TrimPrefix(version, "v"), ".", 2)[0]
+}
+
+// CompareVersions compares two dotted version strings numerically segment by segment.
+// A version carrying a qualifier (e.g. 1.0.0-beta) is considered lower than the plain version.
+// It returns -1 if a < b, 0 if a == b and 1 if a > b.
+func CompareVersions(a, b string) int {
+	aNumbers, aQualifier := splitVersion(a)
+	bNumbers, bQualifier := splitVersion(b)
+	for i := 0; i < len(aNumbers) || i < len(bNumbers); i++ {
+		var x, y int
+		if i < len(aNumbers) {
+			x = aNumbers[i]
+		}
+		if i < len(bNumbers) {
+			y = bNumbers[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case aQualifier == bQualifier:
+		return 0
+	case len(aQualifier) == 0:
+		return 1
+	case len(bQualifier) == 0:
+		return -1
+	case aQualifier < bQualifier:
+		return -1
+	}
+	return 1
+}
+
+func splitVersion(version string) ([]int, string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	numbers := []int{}
+	for len(version) > 0 {
+		end := strings.IndexFunc(version, func(r rune) bool { return r < '0' || r > '9' })
+		if end == 0 {
+			break
+		}
+		if end < 0 {
+			end = len(version)
+		}
+		n, _ := strconv.Atoi(version[:end])
+		numbers = append(numbers, n)
+		version = version[end:]
+		if !strings.HasPrefix(version, ".") {
+			break
+		}
+		version = version[1:]
+	}
+	return numbers, strings.TrimLeft(version, ".-_+")
+}
+
+// UpgradesToMarkdown renders the list of upgrades together with the vulnerabilities they fix,
+// e.g. to be used as the description of a pull request.
+func UpgradesToMarkdown(upgrades []DependencyUpgrade) []byte {
+	var sb strings.Builder
+	sb.WriteString("This pull request upgrades vulnerable direct dependencies to the lowest versions fixing the reported vulnerabilities.\n\n")
+	sb.WriteString("| Dependency | Current version | Target version | Fixed vulnerabilities |\n")
+	sb.WriteString("| --- | --- | --- | --- |\n")
+	for _, upgrade := range upgrades {
+		name := upgrade.PackageName()
+		if upgrade.PackageType() == packageurl.TypeMaven && len(upgrade.Library.GroupID) > 0 {
+			name = fmt.Sprintf("%v:%v", upgrade.Library.GroupID, name)
+		}
+		sb.WriteString(fmt.Sprintf("| %v | %v | %v | %v |\n", name, upgrade.Library.Version, upgrade.TargetVersion, strings.Join(upgrade.Vulnerabilities, ", ")))
+	}
+	return []byte(sb.String())
+}
@@ -0,0 +1,106 @@
+//go:build unit
+
+package whitesource
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinimalUpgrades(t *testing.T) {
+	t.Parallel()
+	t.Run("highest minimal fix of all vulnerabilities per library", func(t *testing.T) {
+		lib := Library{GroupID: "com.fasterxml.jackson.core", ArtifactID: "jackson-databind", Version: "2.12.1", LibType: "MAVEN_ARTIFACT"}
+		alerts := []Alert{
+			{
+				Type:             "SECURITY_VULNERABILITY",
+				DirectDependency: true,
+				Library:          lib,
+				Vulnerability: Vulnerability{
+					Name:   "CVE-2022-42003",
+					TopFix: Fix{FixResolution: "Upgrade to version com.fasterxml.jackson.core:jackson-databind:2.12.7.1,2.13.4.1"},
+				},
+			},
+			{
+				Type:             "SECURITY_VULNERABILITY",
+				DirectDependency: true,
+				Library:          lib,
+				Vulnerability: Vulnerability{
+					Name:     "CVE-2020-36518",
+					TopFix:   Fix{FixResolution: "Upgrade to version 2.12.6.1"},
+					AllFixes: []Fix{{FixResolution: "Upgrade to version 2.13.2.1"}},
+				},
+			},
+		}
+
+		upgrades := MinimalUpgrades(alerts)
+
+		if assert.Len(t, upgrades, 1) {
+			assert.Equal(t, "2.12.7.1", upgrades[0].TargetVersion)
+			assert.Equal(t, []string{"CVE-2020-36518", "CVE-2022-42003"}, upgrades[0].Vulnerabilities)
+			assert.Equal(t, "maven", upgrades[0].PackageType())
+			assert.Equal(t, "jackson-databind", upgrades[0].PackageName())
+		}
+	})
+
+	t.Run("ignores transitive, assessed and unfixable alerts", func(t *testing.T) {
+		lib := Library{GroupID: "lodash", ArtifactID: "lodash-4.17.15.tgz", Version: "4.17.15", LibType: "javascript/Node.js"}
+		alerts := []Alert{
+			{Type: "SECURITY_VULNERABILITY", Library: lib, Vulnerability: Vulnerability{Name: "CVE-1", TopFix: Fix{FixResolution: "Upgrade to version lodash - 4.17.21"}}},
+			{Type: "SECURITY_VULNERABILITY", DirectDependency: true, Library: lib, Assessment: &format.Assessment{}, Vulnerability: Vulnerability{Name: "CVE-2", TopFix: Fix{FixResolution: "Upgrade to version 4.17.21"}}},
+			{Type: "SECURITY_VULNERABILITY", DirectDependency: true, Library: lib, Vulnerability: Vulnerability{Name: "CVE-3", TopFix: Fix{FixResolution: "Replace or update the following files: lodash.js"}}},
+			{Type: "REJECTED_BY_POLICY_RESOURCE", DirectDependency: true, Library: lib},
+		}
+
+		assert.Empty(t, MinimalUpgrades(alerts))
+	})
+
+	t.Run("prefers fixes within the current major version", func(t *testing.T) {
+		lib := Library{GroupID: "lodash", ArtifactID: "lodash-4.17.15.tgz", Version: "4.17.15", LibType: "javascript/Node.js"}
+		alerts := []Alert{
+			{Type: "SECURITY_VULNERABILITY", DirectDependency: true, Library: lib, Vulnerability: Vulnerability{Name: "CVE-1", TopFix: Fix{FixResolution: "Upgrade to version lodash - 5.0.0,4.17.21,4.17.20"}}},
+		}
+
+		upgrades := MinimalUpgrades(alerts)
+
+		if assert.Len(t, upgrades, 1) {
+			assert.Equal(t, "4.17.20", upgrades[0].TargetVersion)
+			assert.Equal(t, "lodash", upgrades[0].PackageName())
+		}
+	})
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "1.2.3", b: "1.2.3", expected: 0},
+		{a: "1.2.10", b: "1.2.9", expected: 1},
+		{a: "2.12.7.1", b: "2.12.7", expected: 1},
+		{a: "v1.0", b: "1.0.0", expected: 0},
+		{a: "1.0.0-beta", b: "1.0.0", expected: -1},
+		{a: "1.0.0.RELEASE", b: "1.0.1", expected: -1},
+	}
+	for _, test := range tt {
+		assert.Equal(t, test.expected, CompareVersions(test.a, test.b), "%v <=> %v", test.a, test.b)
+	}
+}
+
+func TestUpgradesToMarkdown(t *testing.T) {
+	t.Parallel()
+	upgrades := []DependencyUpgrade{
+		{
+			Library:         Library{GroupID: "org.apache.commons", ArtifactID: "commons-text", Version: "1.9", LibType: "java"},
+			TargetVersion:   "1.10.0",
+			Vulnerabilities: []string{"CVE-2022-42889"},
+		},
+	}
+
+	markdown := string(UpgradesToMarkdown(upgrades))
+
+	assert.Contains(t, markdown, "| org.apache.commons:commons-text | 1.9 | 1.10.0 | CVE-2022-42889 |")
+}
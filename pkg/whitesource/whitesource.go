@@ -174,7 +174,7 @@ func (a Alert) ToMarkdown() ([]byte, error) {
 			PublishDate:       a.Vulnerability.PublishDate,
 			Resolution:        a.Vulnerability.TopFix.FixResolution,
 			Score:             score,
-			Severity:          consolidate(a.Vulnerability.Severity, a.Vulnerability.CVSS3Severity, a.Vulnerability.Score, a.Vulnerability.CVSS3Score),
+			Severity:          a.Severity(),
 			Version:           a.Library.Version,
 			PackageURL:        a.Library.ToPackageUrl().ToString(),
 			VulnerabilityLink: a.Vulnerability.URL,
@@ -204,6 +204,29 @@ func (a Alert) ToMarkdown() ([]byte, error) {
 	return []byte{}, nil
 }
 
+// Fingerprint returns a stable identifier of the alert consisting of vulnerability and affected library
+func (a Alert) Fingerprint() string {
+	return fmt.Sprintf("%v/%v", a.Vulnerability.Name, a.Library.ToPackageUrl().ToString())
+}
+
+// Severity returns the consolidated severity of the alert, e.g. HIGH or CRITICAL
+func (a Alert) Severity() string {
+	return consolidate(a.Vulnerability.Severity, a.Vulnerability.CVSS3Severity, a.Vulnerability.Score, a.Vulnerability.CVSS3Score)
+}
+
+// ToTrackedFindings converts alerts into findings which are tracked by dedicated GitHub issues.
+// Alerts without assessment contained in assessedAlerts are considered as ignored within WhiteSource.
+func ToTrackedFindings(alerts, assessedAlerts []Alert) []reporting.TrackedFinding {
+	findings := make([]reporting.TrackedFinding, 0, len(alerts)+len(assessedAlerts))
+	for _, alert := range alerts {
+		findings = append(findings, reporting.TrackedFinding{Detail: alert, Fingerprint: alert.Fingerprint(), Severity: alert.Severity(), Assessment: alert.Assessment})
+	}
+	for _, alert := range assessedAlerts {
+		findings = append(findings, reporting.TrackedFinding{Detail: alert, Fingerprint: alert.Fingerprint(), Severity: alert.Severity(), Assessment: alert.Assessment, Ignored: alert.Assessment == nil})
+	}
+	return findings
+}
+
 // ToTxt returns the textual representation of the contents
 func (a Alert) ToTxt() string {
 	score := consolidateScores(a.Vulnerability.Score, a.Vulnerability.CVSS3Score)
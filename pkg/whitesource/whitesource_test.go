@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/package-url/packageurl-go"
 	"github.com/stretchr/testify/assert"
@@ -531,3 +532,37 @@ func TestGetProjectAlertsByType(t *testing.T) {
 
 	})
 }
+
+func TestToTrackedFindings(t *testing.T) {
+	t.Parallel()
+	alerts := []Alert{
+		{
+			Library:       Library{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.1", LibType: "java"},
+			Vulnerability: Vulnerability{Name: "CVE-2021-44228", Severity: "high", CVSS3Severity: "high", CVSS3Score: 10},
+		},
+	}
+	assessedAlerts := []Alert{
+		{
+			Library:       Library{GroupID: "lodash", ArtifactID: "lodash", Version: "4.17.15", LibType: "node_packaged_module"},
+			Vulnerability: Vulnerability{Name: "CVE-2020-8203", Severity: "medium", Score: 5.8},
+		},
+		{
+			Assessment:    &format.Assessment{Status: format.NotRelevant},
+			Library:       Library{GroupID: "lodash", ArtifactID: "lodash", Version: "4.17.15", LibType: "node_packaged_module"},
+			Vulnerability: Vulnerability{Name: "CVE-2021-23337", Severity: "high", Score: 7.2},
+		},
+	}
+
+	findings := ToTrackedFindings(alerts, assessedAlerts)
+
+	if assert.Len(t, findings, 3) {
+		assert.Equal(t, "CVE-2021-44228/pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", findings[0].Fingerprint)
+		assert.Equal(t, "CRITICAL", findings[0].Severity)
+		assert.False(t, findings[0].Ignored)
+		assert.Equal(t, "CVE-2020-8203/pkg:npm/lodash/lodash@4.17.15", findings[1].Fingerprint)
+		assert.Equal(t, "MEDIUM", findings[1].Severity)
+		assert.True(t, findings[1].Ignored)
+		assert.False(t, findings[2].Ignored)
+		assert.Equal(t, format.NotRelevant, findings[2].Assessment.Status)
+	}
+}
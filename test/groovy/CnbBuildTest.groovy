@@ -68,9 +68,11 @@ public class CnbBuildTest extends BasePiperTest {
         assertThat(calledWithStepName, is('cnbBuild'))
         assertThat(calledWithMetadata, is('metadata/cnbBuild.yaml'))
 
-        assertThat(calledWithCredentials.size(), is(1))
+        assertThat(calledWithCredentials.size(), is(3))
         assertThat(calledWithCredentials[0].size(), is(3))
         assertThat(calledWithCredentials[0], allOf(hasEntry('type','file'), hasEntry('id','dockerConfigJsonCredentialsId'), hasEntry('env',['PIPER_dockerConfigJSON'])))
+        assertThat(calledWithCredentials[1], allOf(hasEntry('type','file'), hasEntry('id','signingKeyCredentialsId'), hasEntry('env',['PIPER_signingKey'])))
+        assertThat(calledWithCredentials[2], allOf(hasEntry('type','token'), hasEntry('id','signingKeyPasswordCredentialsId'), hasEntry('env',['PIPER_signingKeyPassword'])))
 
         assertTrue(calledWithFailOnError)
 
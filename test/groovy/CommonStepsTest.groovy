@@ -225,6 +225,11 @@ public class CommonStepsTest extends BasePiperTest{
         'apiProviderDownload', //implementing new golang pattern without fields
         'apiProxyUpload', //implementing new golang pattern without fields
         'gradleExecuteBuild', //implementing new golang pattern without fields
+        'secretScan', //implementing new golang pattern without fields
+        'imageVerifySignature', //implementing new golang pattern without fields
+        'imageLint', //implementing new golang pattern without fields
+        'ociArtifactExecute', //implementing new golang pattern without fields
+        'licenseComplianceCheck', //implementing new golang pattern without fields
         'shellExecute', //implementing new golang pattern without fields
         'apiKeyValueMapUpload', //implementing new golang pattern without fields
         'apiProviderUpload', //implementing new golang pattern without fields
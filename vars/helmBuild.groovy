@@ -9,6 +9,10 @@ void call(Map parameters = [:]) {
         [type: 'file', id: 'dockerConfigJsonCredentialsId', env: ['PIPER_dockerConfigJSON']],
         [type: 'usernamePassword', id: 'sourceRepositoryCredentialsId', env: ['PIPER_sourceRepositoryUser', 'PIPER_sourceRepositoryPassword']],
         [type: 'usernamePassword', id: 'targetRepositoryCredentialsId', env: ['PIPER_targetRepositoryUser', 'PIPER_targetRepositoryPassword']],
+        [type: 'file', id: 'signingKeyringCredentialsId', env: ['PIPER_signingKeyring']],
+        [type: 'token', id: 'signingPassphraseCredentialsId', env: ['PIPER_signingPassphrase']],
+        [type: 'file', id: 'ociSigningKeyCredentialsId', env: ['PIPER_ociSigningKey']],
+        [type: 'token', id: 'ociSigningKeyPasswordCredentialsId', env: ['PIPER_ociSigningKeyPassword']],
     ]
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
 }
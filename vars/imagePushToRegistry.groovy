@@ -4,6 +4,9 @@ import groovy.transform.Field
 @Field String METADATA_FILE = 'metadata/imagePushToRegistry.yaml'
 
 void call(Map parameters = [:]) {
-    List credentials = []
+    List credentials = [
+        [type: 'file', id: 'signingKeyCredentialsId', env: ['PIPER_signingKey']],
+        [type: 'token', id: 'signingKeyPasswordCredentialsId', env: ['PIPER_signingKeyPassword']]
+    ]
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
 }
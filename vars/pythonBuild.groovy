@@ -8,7 +8,10 @@ import static com.sap.piper.Prerequisites.checkScript
 @Field String STEP_NAME = getClass().getName()
 
 void call(Map parameters = [:]) {
-    List credentials = [[type: 'token', id: 'altDeploymentRepositoryPasswordId', env: ['PIPER_altDeploymentRepositoryPassword']]]
+    List credentials = [
+        [type: 'token', id: 'altDeploymentRepositoryPasswordId', env: ['PIPER_altDeploymentRepositoryPassword']],
+        [type: 'token', id: 'dependencyCacheCredentialsId', env: ['PIPER_dependencyCacheCredentials']]
+    ]
     final script = checkScript(this, parameters) ?: this
     parameters = DownloadCacheUtils.injectDownloadCacheInParameters(script, parameters, BuildTool.PIP)
 
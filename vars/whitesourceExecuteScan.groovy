@@ -18,7 +18,9 @@ void call(Map parameters = [:]) {
         [type: 'token', id: 'userTokenCredentialsId', env: ['PIPER_userToken']],
         [type: 'token', id: 'githubTokenCredentialsId', env: ['PIPER_githubToken']],
         [type: 'file', id: 'dockerConfigJsonCredentialsId', env: ['PIPER_dockerConfigJSON']],
-        [type: 'usernamePassword', id: 'golangPrivateModulesGitTokenCredentialsId', env: ['PIPER_privateModulesGitUsername', 'PIPER_privateModulesGitToken']]
+        [type: 'usernamePassword', id: 'golangPrivateModulesGitTokenCredentialsId', env: ['PIPER_privateModulesGitUsername', 'PIPER_privateModulesGitToken']],
+        [type: 'usernamePassword', id: 'jiraCredentialsId', env: ['PIPER_jiraUsername', 'PIPER_jiraToken']],
+        [type: 'token', id: 'azureBoardsTokenCredentialsId', env: ['PIPER_azureBoardsToken']]
     ]
     echo "WARNING: Step '${STEP_NAME}' is deprecated."
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)